@@ -4,17 +4,26 @@ import (
 	"context"
 	"core-service/internal/app"
 	authapi "core-service/internal/app/core-service/api/auth"
+	"core-service/internal/app/interceptors"
+	"core-service/internal/audit"
 	"core-service/internal/config"
 	"core-service/internal/db"
 	"core-service/internal/jwt"
 	"core-service/internal/logger"
+	"core-service/internal/notifier"
 	"core-service/internal/queue"
 	"core-service/internal/repository"
+	auditservice "core-service/internal/service/audit"
 	"core-service/internal/service/auth"
 	"core-service/internal/service/contract"
 	"core-service/internal/service/document"
+	"core-service/internal/service/erasure"
+	"core-service/internal/service/export"
+	"core-service/internal/service/membership"
 	"core-service/internal/service/note"
+	"core-service/internal/service/notification"
 	"core-service/internal/service/organization"
+	"core-service/internal/service/role"
 	"core-service/internal/service/storage"
 	"core-service/internal/service/template"
 	"core-service/internal/service/user"
@@ -26,6 +35,7 @@ import (
 	"os"
 	"os/signal"
 	"syscall"
+	"time"
 
 	"github.com/jackc/pgx/v5/pgxpool"
 )
@@ -79,6 +89,46 @@ func main() {
 	}
 	defer queueClient.Close()
 
+	// Create queue client for notification events (published by core-service, docs-processor and llm-service)
+	notificationQueue, err := queue.NewRabbitMQClient(cfg.GetRabbitMQURL(), cfg.GetNotificationsQueueName())
+	if err != nil {
+		logger.Fatalf(ctx, "Failed to create notifications queue client: %v", err)
+	}
+	defer notificationQueue.Close()
+
+	// Create queue client for audit events (published by core-service and llm-service)
+	auditQueue, err := queue.NewRabbitMQClient(cfg.GetRabbitMQURL(), cfg.GetAuditQueueName())
+	if err != nil {
+		logger.Fatalf(ctx, "Failed to create audit queue client: %v", err)
+	}
+	defer auditQueue.Close()
+
+	// Create queue clients for data erasure: requests to llm-service and step results from llm-service and docs-processor
+	erasureRequests, err := queue.NewRabbitMQClient(cfg.GetRabbitMQURL(), cfg.GetErasureRequestsQueueName())
+	if err != nil {
+		logger.Fatalf(ctx, "Failed to create erasure requests queue client: %v", err)
+	}
+	defer erasureRequests.Close()
+
+	erasureResults, err := queue.NewRabbitMQClient(cfg.GetRabbitMQURL(), cfg.GetErasureResultsQueueName())
+	if err != nil {
+		logger.Fatalf(ctx, "Failed to create erasure results queue client: %v", err)
+	}
+	defer erasureResults.Close()
+
+	// Create queue clients for organization exports: requests to llm-service and its exported files
+	exportRequests, err := queue.NewRabbitMQClient(cfg.GetRabbitMQURL(), cfg.GetExportRequestsQueueName())
+	if err != nil {
+		logger.Fatalf(ctx, "Failed to create export requests queue client: %v", err)
+	}
+	defer exportRequests.Close()
+
+	exportResults, err := queue.NewRabbitMQClient(cfg.GetRabbitMQURL(), cfg.GetExportResultsQueueName())
+	if err != nil {
+		logger.Fatalf(ctx, "Failed to create export results queue client: %v", err)
+	}
+	defer exportResults.Close()
+
 	// Initialize S3 client
 	s3Client, err := storagepkg.NewS3Client(
 		cfg.GetS3Endpoint(),
@@ -93,14 +143,27 @@ func main() {
 	}
 	logger.Info(ctx, "S3 client initialized")
 
+	// Audit events are attributed to the user of the current request
+	auditRecorder := audit.NewRecorder(auditQueue, "core-service", interceptors.UserIDFromContext)
+
 	// Initialize services
-	orgService := organization.New(repo)
-	userService := user.New(repo)
-	docService := document.New(repo, queueClient, "document_processing")
+	erasureService := erasure.New(repo, contextManager, s3Client, queueClient, erasureRequests, notificationQueue, auditRecorder, cfg)
+	exportService := export.New(repo, s3Client, exportRequests, notificationQueue, auditRecorder, cfg)
+	orgService := organization.New(repo, erasureService)
+	userService := user.New(repo, contextManager, notificationQueue, auditRecorder)
+	docService := document.New(repo, queueClient, "document_processing", auditRecorder)
 	noteService := note.New(repo)
-	templateService := template.New(repo, queueClient, contextManager)
-	contractService := contract.New(repo)
+	templateService := template.New(repo, queueClient, contextManager, auditRecorder)
+	contractService := contract.New(repo, auditRecorder)
 	storageService := storage.New(s3Client)
+	roleService := role.New(repo, auditRecorder)
+	auditService := auditservice.New(repo)
+	membershipService := membership.New(repo, cfg.GetAuthzMembershipCacheTTL())
+	notificationService := notification.New(
+		repo,
+		notifier.NewTelegramNotifier(cfg.GetNotificationsTelegramAPIURL(), cfg.GetNotificationsTelegramBotToken()),
+		cfg,
+	)
 
 	// Initialize JWT provider
 	jwtSecret := cfg.GetJWTSecret()
@@ -120,7 +183,7 @@ func main() {
 	)
 
 	// Initialize auth service
-	authService := auth.New(repo, jwtProvider, telegramValidator)
+	authService := auth.New(repo, jwtProvider, telegramValidator, cfg.GetAuthzServiceTokens())
 
 	// Wrap auth service with gRPC API handler
 	authAPIService := authapi.NewService(authService)
@@ -134,6 +197,13 @@ func main() {
 		TemplateService:     templateService,
 		ContractService:     contractService,
 		StorageService:      storageService,
+		NotificationService: notificationService,
+		RoleService:         roleService,
+		AuditService:        auditService,
+		ErasureService:      erasureService,
+		ExportService:       exportService,
+		Memberships:         membershipService,
+		Resources:           repo,
 	}, authAPIService,
 		app.WithHTTPPathPrefix(cfg.GetHTTPPathPrefix()),
 	)
@@ -151,6 +221,51 @@ func main() {
 		cancel()
 	}()
 
+	// Start notification consumer
+	if cfg.GetNotificationsEnabled() {
+		if cfg.GetNotificationsTelegramBotToken() == "" {
+			logger.Warn(ctx, "Notifications bot token not set, Telegram delivery will fail")
+		}
+		go func() {
+			err := notificationQueue.Consume(ctx, cfg.GetNotificationsConsumerTag(), cfg.GetNotificationsPrefetchCount(), notificationService.HandleMessage)
+			if err != nil {
+				logger.Errorf(ctx, "Notification consumer stopped: %v", err)
+			}
+		}()
+	}
+
+	// Start audit log consumer
+	if cfg.GetAuditEnabled() {
+		go func() {
+			err := auditQueue.Consume(ctx, cfg.GetAuditConsumerTag(), cfg.GetAuditPrefetchCount(), auditService.HandleMessage)
+			if err != nil {
+				logger.Errorf(ctx, "Audit consumer stopped: %v", err)
+			}
+		}()
+	}
+
+	// Start data erasure: purge organizations and accounts after the grace period and collect step results
+	if cfg.GetErasureEnabled() {
+		go func() {
+			err := erasureResults.Consume(ctx, cfg.GetErasureConsumerTag(), cfg.GetErasurePrefetchCount(), erasureService.HandleMessage)
+			if err != nil {
+				logger.Errorf(ctx, "Erasure results consumer stopped: %v", err)
+			}
+		}()
+		go runErasures(ctx, erasureService, cfg.GetErasureCheckInterval())
+	}
+
+	// Start organization exports: build archives from llm-service results, time out stalled exports and delete expired archives
+	if cfg.GetExportEnabled() {
+		go func() {
+			err := exportResults.Consume(ctx, cfg.GetExportConsumerTag(), cfg.GetExportPrefetchCount(), exportService.HandleMessage)
+			if err != nil {
+				logger.Errorf(ctx, "Export results consumer stopped: %v", err)
+			}
+		}()
+		go runExportMaintenance(ctx, exportService, cfg.GetExportCheckInterval())
+	}
+
 	// Run app
 	if err := application.Run(ctx); err != nil {
 		logger.Fatalf(ctx, "Application error: %v", err)
@@ -158,3 +273,37 @@ func main() {
 
 	logger.Info(ctx, "Core Service stopped")
 }
+
+// runErasures starts due erasures on every tick until the context is cancelled
+func runErasures(ctx context.Context, erasureService *erasure.Service, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := erasureService.RunDueErasures(ctx); err != nil {
+				logger.Errorf(ctx, "Failed to run due erasures: %v", err)
+			}
+		}
+	}
+}
+
+// runExportMaintenance fails stalled exports and deletes expired archives on every tick until the context is cancelled
+func runExportMaintenance(ctx context.Context, exportService *export.Service, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := exportService.RunMaintenance(ctx); err != nil {
+				logger.Errorf(ctx, "Failed to run export maintenance: %v", err)
+			}
+		}
+	}
+}
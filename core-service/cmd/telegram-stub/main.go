@@ -0,0 +1,22 @@
+package main
+
+import (
+	"flag"
+	"log"
+	"net/http"
+
+	"core-service/internal/notifier"
+)
+
+// telegram-stub serves a local Telegram Bot API stand-in for notification tests.
+// Point notifications.telegram.api_url at it and read sent messages from GET /messages.
+func main() {
+	var addr string
+	flag.StringVar(&addr, "addr", ":8081", "Address to listen on")
+	flag.Parse()
+
+	log.Printf("Telegram stub listening on %s", addr)
+	if err := http.ListenAndServe(addr, notifier.NewStubServer()); err != nil {
+		log.Fatalf("Telegram stub stopped: %v", err)
+	}
+}
@@ -0,0 +1,112 @@
+package app
+
+import (
+	"context"
+	"core-service/internal/app/interceptors"
+	"core-service/internal/domain"
+	pb "core-service/pkg/core"
+)
+
+// ResourceOwners loads resources addressed by id to find the organization they belong to
+type ResourceOwners interface {
+	GetDocument(ctx context.Context, id domain.ID) (domain.Document, error)
+	GetNote(ctx context.Context, id domain.ID) (domain.Note, error)
+	GetContract(ctx context.Context, id domain.ID) (domain.GeneratedContract, error)
+	GetInvitationByID(ctx context.Context, id domain.ID) (domain.Invitation, error)
+}
+
+// accessPolicy declares, for every protected RPC, which organization it touches and the permission required there.
+// Reading organization data only requires membership; changes require the matching permission,
+// which admins always have and employees get from the built-in employee role or their custom role.
+// Upload URLs only require membership because chat attachments use them too;
+// adding a file to the knowledge base is gated by RegisterDocument.
+// Contract templates are shared by all organizations and only require authentication.
+func accessPolicy(owners ResourceOwners) interceptors.AccessPolicy {
+	documentOrg := interceptors.ResourceOwner(func(ctx context.Context, id domain.ID) (domain.ID, error) {
+		doc, err := owners.GetDocument(ctx, id)
+		return doc.OrganizationID, err
+	})
+	noteOrg := interceptors.ResourceOwner(func(ctx context.Context, id domain.ID) (domain.ID, error) {
+		note, err := owners.GetNote(ctx, id)
+		return note.OrganizationID, err
+	})
+	contractOrg := interceptors.ResourceOwner(func(ctx context.Context, id domain.ID) (domain.ID, error) {
+		contract, err := owners.GetContract(ctx, id)
+		return contract.OrganizationID, err
+	})
+	invitationOrg := interceptors.ResourceOwner(func(ctx context.Context, id domain.ID) (domain.ID, error) {
+		invitation, err := owners.GetInvitationByID(ctx, id)
+		return invitation.OrganizationID, err
+	})
+	orgField := interceptors.OrganizationIDField
+
+	return interceptors.AccessPolicy{
+		pb.AuthService_RefreshToken_FullMethodName: interceptors.Authenticated(),
+
+		pb.OrganizationService_CreateOrganization_FullMethodName:           interceptors.Authenticated(),
+		pb.OrganizationService_ListMyOrganizations_FullMethodName:          interceptors.Authenticated(),
+		pb.OrganizationService_GetOrganizationProfileSchema_FullMethodName: interceptors.Authenticated(),
+		pb.OrganizationService_GetOrganization_FullMethodName:              interceptors.Member(interceptors.IDField),
+		pb.OrganizationService_UpdateOrganization_FullMethodName:           interceptors.Permitted(interceptors.IDField, domain.PermissionOrganizationManage),
+		pb.OrganizationService_DeleteOrganization_FullMethodName:           interceptors.Permitted(interceptors.IDField, domain.PermissionOrganizationManage),
+
+		pb.UserService_AcceptInvitation_FullMethodName:     interceptors.Authenticated(),
+		pb.UserService_GetUser_FullMethodName:              interceptors.Authenticated(),
+		pb.UserService_ListUsers_FullMethodName:            interceptors.Member(orgField),
+		pb.UserService_InviteUser_FullMethodName:           interceptors.Permitted(orgField, domain.PermissionUsersInvite),
+		pb.UserService_UpdateUserRole_FullMethodName:       interceptors.Permitted(orgField, domain.PermissionUsersManage),
+		pb.UserService_DeactivateUser_FullMethodName:       interceptors.Permitted(orgField, domain.PermissionUsersManage),
+		pb.UserService_LeaveOrganization_FullMethodName:    interceptors.Member(orgField),
+		pb.UserService_TransferOwnership_FullMethodName:    interceptors.Admin(orgField),
+		pb.UserService_ListInvitations_FullMethodName:      interceptors.Permitted(orgField, domain.PermissionUsersInvite),
+		pb.UserService_DeleteInvitation_FullMethodName:     interceptors.Permitted(invitationOrg, domain.PermissionUsersInvite),
+		pb.UserService_RevokeInvitation_FullMethodName:     interceptors.Permitted(invitationOrg, domain.PermissionUsersInvite),
+		pb.UserService_RegenerateInvitation_FullMethodName: interceptors.Permitted(invitationOrg, domain.PermissionUsersInvite),
+		pb.UserService_ListInvitationUses_FullMethodName:   interceptors.Permitted(invitationOrg, domain.PermissionUsersInvite),
+
+		pb.RoleService_ListPermissions_FullMethodName: interceptors.Authenticated(),
+		pb.RoleService_ListRoles_FullMethodName:       interceptors.Member(orgField),
+		pb.RoleService_CreateRole_FullMethodName:      interceptors.Permitted(orgField, domain.PermissionRolesManage),
+		pb.RoleService_UpdateRole_FullMethodName:      interceptors.Permitted(orgField, domain.PermissionRolesManage),
+		pb.RoleService_DeleteRole_FullMethodName:      interceptors.Permitted(orgField, domain.PermissionRolesManage),
+
+		pb.DocumentService_RegisterDocument_FullMethodName: interceptors.Permitted(orgField, domain.PermissionDocumentsWrite),
+		pb.DocumentService_ListDocuments_FullMethodName:    interceptors.Member(orgField),
+		pb.DocumentService_GetDocument_FullMethodName:      interceptors.Member(documentOrg),
+		pb.DocumentService_DeleteDocument_FullMethodName:   interceptors.Permitted(documentOrg, domain.PermissionDocumentsDelete),
+
+		pb.NoteService_CreateNote_FullMethodName: interceptors.Permitted(orgField, domain.PermissionNotesWrite),
+		pb.NoteService_ListNotes_FullMethodName:  interceptors.Member(orgField),
+		pb.NoteService_GetNote_FullMethodName:    interceptors.Member(noteOrg),
+		pb.NoteService_DeleteNote_FullMethodName: interceptors.Permitted(noteOrg, domain.PermissionNotesDelete),
+
+		pb.ContractTemplateService_CreateTemplate_FullMethodName: interceptors.Authenticated(),
+		pb.ContractTemplateService_ListTemplates_FullMethodName:  interceptors.Authenticated(),
+		pb.ContractTemplateService_UpdateTemplate_FullMethodName: interceptors.Authenticated(),
+		pb.ContractTemplateService_DeleteTemplate_FullMethodName: interceptors.Authenticated(),
+
+		pb.GeneratedContractService_ListContracts_FullMethodName:  interceptors.Permitted(orgField, domain.PermissionContractsRead),
+		pb.GeneratedContractService_GetContract_FullMethodName:    interceptors.Permitted(contractOrg, domain.PermissionContractsRead),
+		pb.GeneratedContractService_DeleteContract_FullMethodName: interceptors.Permitted(contractOrg, domain.PermissionContractsDelete),
+
+		pb.StorageService_GenerateUploadURL_FullMethodName:   interceptors.Member(orgField),
+		pb.StorageService_GenerateDownloadURL_FullMethodName: interceptors.Member(interceptors.S3KeyOwner),
+
+		pb.NotificationService_GetNotificationPreferences_FullMethodName:    interceptors.Authenticated(),
+		pb.NotificationService_UpdateNotificationPreferences_FullMethodName: interceptors.Authenticated(),
+
+		pb.AuditService_ListAuditEvents_FullMethodName:   interceptors.Permitted(orgField, domain.PermissionAuditView),
+		pb.AuditService_ListMyAuditEvents_FullMethodName: interceptors.Authenticated(),
+
+		pb.ErasureService_GetOrganizationDeletion_FullMethodName:    interceptors.Permitted(orgField, domain.PermissionOrganizationManage),
+		pb.ErasureService_CancelOrganizationDeletion_FullMethodName: interceptors.Permitted(orgField, domain.PermissionOrganizationManage),
+		pb.ErasureService_DeleteMyAccount_FullMethodName:            interceptors.Authenticated(),
+		pb.ErasureService_GetMyAccountDeletion_FullMethodName:       interceptors.Authenticated(),
+		pb.ErasureService_CancelMyAccountDeletion_FullMethodName:    interceptors.Authenticated(),
+		pb.ErasureService_ListMyDeletionReports_FullMethodName:      interceptors.Authenticated(),
+
+		pb.ExportService_ExportOrganization_FullMethodName:      interceptors.Permitted(orgField, domain.PermissionOrganizationManage),
+		pb.ExportService_GetOrganizationExport_FullMethodName:   interceptors.Permitted(orgField, domain.PermissionOrganizationManage),
+		pb.ExportService_ListOrganizationExports_FullMethodName: interceptors.Permitted(orgField, domain.PermissionOrganizationManage),
+	}
+}
@@ -0,0 +1,344 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"testing"
+
+	"core-service/internal/app/interceptors"
+	"core-service/internal/domain"
+	"core-service/internal/logger"
+	pb "core-service/pkg/core"
+
+	"google.golang.org/grpc"
+)
+
+func TestMain(m *testing.M) {
+	logger.Init()
+	os.Exit(m.Run())
+}
+
+type memberKey struct {
+	userID, organizationID domain.ID
+}
+
+// fakeMemberships - членства в организациях в памяти
+type fakeMemberships map[memberKey]*domain.OrganizationMember
+
+func (f fakeMemberships) add(userID, organizationID domain.ID, role domain.UserRole, status domain.UserStatus) {
+	f[memberKey{userID, organizationID}] = &domain.OrganizationMember{
+		ID:             domain.NewID(),
+		OrganizationID: organizationID,
+		UserID:         userID,
+		Role:           role,
+		Status:         status,
+	}
+}
+
+// addCustom добавляет активного сотрудника с собственной ролью
+func (f fakeMemberships) addCustom(userID, organizationID domain.ID, permissions ...domain.Permission) {
+	roleID := domain.NewID()
+	f[memberKey{userID, organizationID}] = &domain.OrganizationMember{
+		ID:                domain.NewID(),
+		OrganizationID:    organizationID,
+		UserID:            userID,
+		Role:              domain.UserRoleEmployee,
+		Status:            domain.UserStatusActive,
+		CustomRoleID:      &roleID,
+		CustomPermissions: permissions,
+	}
+}
+
+func (f fakeMemberships) GetOrganizationMember(_ context.Context, userID, organizationID domain.ID) (*domain.OrganizationMember, error) {
+	member, ok := f[memberKey{userID, organizationID}]
+	if !ok {
+		return nil, domain.ErrNotFound
+	}
+	return member, nil
+}
+
+// fakeOwners - ресурсы организаций в памяти
+type fakeOwners struct {
+	documents   map[domain.ID]domain.ID
+	notes       map[domain.ID]domain.ID
+	contracts   map[domain.ID]domain.ID
+	invitations map[domain.ID]domain.ID
+}
+
+func ownerOf(resources map[domain.ID]domain.ID, id domain.ID) (domain.ID, error) {
+	orgID, ok := resources[id]
+	if !ok {
+		return domain.ID{}, domain.ErrNotFound
+	}
+	return orgID, nil
+}
+
+func (f *fakeOwners) GetDocument(_ context.Context, id domain.ID) (domain.Document, error) {
+	orgID, err := ownerOf(f.documents, id)
+	return domain.Document{ID: id, OrganizationID: orgID}, err
+}
+
+func (f *fakeOwners) GetNote(_ context.Context, id domain.ID) (domain.Note, error) {
+	orgID, err := ownerOf(f.notes, id)
+	return domain.Note{ID: id, OrganizationID: orgID}, err
+}
+
+func (f *fakeOwners) GetContract(_ context.Context, id domain.ID) (domain.GeneratedContract, error) {
+	orgID, err := ownerOf(f.contracts, id)
+	return domain.GeneratedContract{ID: id, OrganizationID: orgID}, err
+}
+
+func (f *fakeOwners) GetInvitationByID(_ context.Context, id domain.ID) (domain.Invitation, error) {
+	orgID, err := ownerOf(f.invitations, id)
+	return domain.Invitation{ID: id, OrganizationID: orgID}, err
+}
+
+// tenantFixture - две организации: в A есть администратор, сотрудник, деактивированный администратор
+// и сотрудники с собственными ролями (бухгалтер и рекрутер), в B - только администратор;
+// outsider не состоит ни в одной
+type tenantFixture struct {
+	orgA, orgB                           domain.ID
+	adminA, employeeA, inactiveA, adminB domain.ID
+	outsider                             domain.ID
+	documentA, noteA, contractA, inviteA domain.ID
+	accountantA, recruiterA              domain.ID
+	authorizer                           *interceptors.Authorizer
+}
+
+func newTenantFixture() *tenantFixture {
+	f := &tenantFixture{
+		orgA: domain.NewID(), orgB: domain.NewID(),
+		adminA: domain.NewID(), employeeA: domain.NewID(), inactiveA: domain.NewID(), adminB: domain.NewID(),
+		outsider:    domain.NewID(),
+		accountantA: domain.NewID(), recruiterA: domain.NewID(),
+		documentA: domain.NewID(), noteA: domain.NewID(), contractA: domain.NewID(), inviteA: domain.NewID(),
+	}
+
+	members := fakeMemberships{}
+	members.add(f.adminA, f.orgA, domain.UserRoleAdmin, domain.UserStatusActive)
+	members.add(f.employeeA, f.orgA, domain.UserRoleEmployee, domain.UserStatusActive)
+	members.add(f.inactiveA, f.orgA, domain.UserRoleAdmin, domain.UserStatusInactive)
+	members.add(f.adminB, f.orgB, domain.UserRoleAdmin, domain.UserStatusActive)
+	members.addCustom(f.accountantA, f.orgA, domain.PermissionContractsRead)
+	members.addCustom(f.recruiterA, f.orgA, domain.PermissionUsersInvite, domain.PermissionDocumentsWrite)
+
+	owners := &fakeOwners{
+		documents:   map[domain.ID]domain.ID{f.documentA: f.orgA},
+		notes:       map[domain.ID]domain.ID{f.noteA: f.orgA},
+		contracts:   map[domain.ID]domain.ID{f.contractA: f.orgA},
+		invitations: map[domain.ID]domain.ID{f.inviteA: f.orgA},
+	}
+
+	f.authorizer = interceptors.NewAuthorizer(accessPolicy(owners), members, unprotectedMethods...)
+	return f
+}
+
+// call прогоняет запрос через unary-интерцептор от имени пользователя
+func (f *tenantFixture) call(userID domain.ID, method string, req any) error {
+	ctx := interceptors.WithUserID(context.Background(), userID)
+	handler := func(context.Context, any) (any, error) { return "ok", nil }
+	_, err := f.authorizer.UnaryInterceptor()(ctx, req, &grpc.UnaryServerInfo{FullMethod: method}, handler)
+	return err
+}
+
+// TestAccessPolicyCrossTenant проверяет матрицу доступа: участник организации A по роли,
+// деактивированный участник, администратор чужой организации и посторонний пользователь
+func TestAccessPolicyCrossTenant(t *testing.T) {
+	f := newTenantFixture()
+	orgA := f.orgA.String()
+
+	cases := []struct {
+		method    string
+		req       any
+		adminOnly bool
+	}{
+		{pb.OrganizationService_GetOrganization_FullMethodName, &pb.GetOrganizationRequest{Id: orgA}, false},
+		{pb.OrganizationService_UpdateOrganization_FullMethodName, &pb.UpdateOrganizationRequest{Id: orgA}, true},
+		{pb.OrganizationService_DeleteOrganization_FullMethodName, &pb.DeleteOrganizationRequest{Id: orgA}, true},
+		{pb.UserService_ListUsers_FullMethodName, &pb.ListUsersRequest{OrganizationId: orgA}, false},
+		{pb.UserService_InviteUser_FullMethodName, &pb.InviteUserRequest{OrganizationId: orgA}, true},
+		{pb.UserService_UpdateUserRole_FullMethodName, &pb.UpdateUserRoleRequest{OrganizationId: orgA, Id: f.employeeA.String()}, true},
+		{pb.UserService_DeactivateUser_FullMethodName, &pb.DeactivateUserRequest{OrganizationId: orgA, Id: f.employeeA.String()}, true},
+		{pb.UserService_LeaveOrganization_FullMethodName, &pb.LeaveOrganizationRequest{OrganizationId: orgA}, false},
+		{pb.UserService_TransferOwnership_FullMethodName, &pb.TransferOwnershipRequest{OrganizationId: orgA, NewOwnerId: f.employeeA.String()}, true},
+		{pb.UserService_ListInvitations_FullMethodName, &pb.ListInvitationsRequest{OrganizationId: orgA}, true},
+		{pb.UserService_DeleteInvitation_FullMethodName, &pb.DeleteInvitationRequest{Id: f.inviteA.String()}, true},
+		{pb.UserService_RevokeInvitation_FullMethodName, &pb.RevokeInvitationRequest{Id: f.inviteA.String()}, true},
+		{pb.UserService_RegenerateInvitation_FullMethodName, &pb.RegenerateInvitationRequest{Id: f.inviteA.String()}, true},
+		{pb.UserService_ListInvitationUses_FullMethodName, &pb.ListInvitationUsesRequest{Id: f.inviteA.String()}, true},
+		{pb.RoleService_ListRoles_FullMethodName, &pb.ListRolesRequest{OrganizationId: orgA}, false},
+		{pb.RoleService_CreateRole_FullMethodName, &pb.CreateRoleRequest{OrganizationId: orgA}, true},
+		{pb.RoleService_UpdateRole_FullMethodName, &pb.UpdateRoleRequest{OrganizationId: orgA, Id: domain.NewID().String()}, true},
+		{pb.RoleService_DeleteRole_FullMethodName, &pb.DeleteRoleRequest{OrganizationId: orgA, Id: domain.NewID().String()}, true},
+		{pb.DocumentService_RegisterDocument_FullMethodName, &pb.RegisterDocumentRequest{OrganizationId: orgA}, false},
+		{pb.DocumentService_ListDocuments_FullMethodName, &pb.ListDocumentsRequest{OrganizationId: orgA}, false},
+		{pb.DocumentService_GetDocument_FullMethodName, &pb.GetDocumentRequest{Id: f.documentA.String()}, false},
+		{pb.DocumentService_DeleteDocument_FullMethodName, &pb.DeleteDocumentRequest{Id: f.documentA.String()}, true},
+		{pb.NoteService_CreateNote_FullMethodName, &pb.CreateNoteRequest{OrganizationId: orgA}, false},
+		{pb.NoteService_ListNotes_FullMethodName, &pb.ListNotesRequest{OrganizationId: orgA}, false},
+		{pb.NoteService_GetNote_FullMethodName, &pb.GetNoteRequest{Id: f.noteA.String()}, false},
+		{pb.NoteService_DeleteNote_FullMethodName, &pb.DeleteNoteRequest{Id: f.noteA.String()}, true},
+		{pb.GeneratedContractService_ListContracts_FullMethodName, &pb.ListContractsRequest{OrganizationId: orgA}, false},
+		{pb.GeneratedContractService_GetContract_FullMethodName, &pb.GetContractRequest{Id: f.contractA.String()}, false},
+		{pb.GeneratedContractService_DeleteContract_FullMethodName, &pb.DeleteContractRequest{Id: f.contractA.String()}, true},
+		{pb.StorageService_GenerateUploadURL_FullMethodName, &pb.GenerateUploadURLRequest{OrganizationId: orgA}, false},
+		{pb.StorageService_GenerateDownloadURL_FullMethodName, &pb.GenerateDownloadURLRequest{S3Key: fmt.Sprintf("documents/%s/file.pdf", orgA)}, false},
+		{pb.AuditService_ListAuditEvents_FullMethodName, &pb.ListAuditEventsRequest{OrganizationId: orgA}, true},
+		{pb.ErasureService_GetOrganizationDeletion_FullMethodName, &pb.GetOrganizationDeletionRequest{OrganizationId: orgA}, true},
+		{pb.ErasureService_CancelOrganizationDeletion_FullMethodName, &pb.CancelOrganizationDeletionRequest{OrganizationId: orgA}, true},
+		{pb.ExportService_ExportOrganization_FullMethodName, &pb.ExportOrganizationRequest{OrganizationId: orgA}, true},
+		{pb.ExportService_GetOrganizationExport_FullMethodName, &pb.GetOrganizationExportRequest{OrganizationId: orgA}, true},
+		{pb.ExportService_ListOrganizationExports_FullMethodName, &pb.ListOrganizationExportsRequest{OrganizationId: orgA}, true},
+	}
+
+	callers := []struct {
+		name     string
+		userID   domain.ID
+		isMember bool
+		isAdmin  bool
+	}{
+		{"admin of A", f.adminA, true, true},
+		{"employee of A", f.employeeA, true, false},
+		{"inactive admin of A", f.inactiveA, false, false},
+		{"admin of B", f.adminB, false, false},
+		{"outsider", f.outsider, false, false},
+	}
+
+	for _, tc := range cases {
+		for _, caller := range callers {
+			t.Run(tc.method+"/"+caller.name, func(t *testing.T) {
+				allowed := caller.isMember && (!tc.adminOnly || caller.isAdmin)
+
+				err := f.call(caller.userID, tc.method, tc.req)
+				if allowed && err != nil {
+					t.Fatalf("expected access, got %v", err)
+				}
+				if !allowed && !errors.Is(err, domain.ErrForbidden) {
+					t.Fatalf("expected forbidden, got %v", err)
+				}
+			})
+		}
+	}
+}
+
+// TestAccessPolicyCustomRoles - собственная роль дает ровно свои права, а не права встроенной роли сотрудника
+func TestAccessPolicyCustomRoles(t *testing.T) {
+	f := newTenantFixture()
+	orgA := f.orgA.String()
+
+	cases := []struct {
+		name    string
+		userID  domain.ID
+		method  string
+		req     any
+		allowed bool
+	}{
+		{"accountant reads contracts", f.accountantA, pb.GeneratedContractService_ListContracts_FullMethodName, &pb.ListContractsRequest{OrganizationId: orgA}, true},
+		{"accountant opens a contract", f.accountantA, pb.GeneratedContractService_GetContract_FullMethodName, &pb.GetContractRequest{Id: f.contractA.String()}, true},
+		{"accountant lists documents", f.accountantA, pb.DocumentService_ListDocuments_FullMethodName, &pb.ListDocumentsRequest{OrganizationId: orgA}, true},
+		{"accountant registers a document", f.accountantA, pb.DocumentService_RegisterDocument_FullMethodName, &pb.RegisterDocumentRequest{OrganizationId: orgA}, false},
+		{"accountant uploads a chat attachment", f.accountantA, pb.StorageService_GenerateUploadURL_FullMethodName, &pb.GenerateUploadURLRequest{OrganizationId: orgA}, true},
+		{"accountant creates a note", f.accountantA, pb.NoteService_CreateNote_FullMethodName, &pb.CreateNoteRequest{OrganizationId: orgA}, false},
+		{"accountant invites", f.accountantA, pb.UserService_InviteUser_FullMethodName, &pb.InviteUserRequest{OrganizationId: orgA}, false},
+		{"recruiter invites", f.recruiterA, pb.UserService_InviteUser_FullMethodName, &pb.InviteUserRequest{OrganizationId: orgA}, true},
+		{"recruiter revokes an invitation", f.recruiterA, pb.UserService_RevokeInvitation_FullMethodName, &pb.RevokeInvitationRequest{Id: f.inviteA.String()}, true},
+		{"recruiter registers a document", f.recruiterA, pb.DocumentService_RegisterDocument_FullMethodName, &pb.RegisterDocumentRequest{OrganizationId: orgA}, true},
+		{"recruiter reads contracts", f.recruiterA, pb.GeneratedContractService_ListContracts_FullMethodName, &pb.ListContractsRequest{OrganizationId: orgA}, false},
+		{"recruiter changes roles", f.recruiterA, pb.UserService_UpdateUserRole_FullMethodName, &pb.UpdateUserRoleRequest{OrganizationId: orgA, Id: f.employeeA.String()}, false},
+		{"accountant leaves the organization", f.accountantA, pb.UserService_LeaveOrganization_FullMethodName, &pb.LeaveOrganizationRequest{OrganizationId: orgA}, true},
+		{"recruiter transfers ownership", f.recruiterA, pb.UserService_TransferOwnership_FullMethodName, &pb.TransferOwnershipRequest{OrganizationId: orgA, NewOwnerId: f.employeeA.String()}, false},
+		{"accountant reads the audit log", f.accountantA, pb.AuditService_ListAuditEvents_FullMethodName, &pb.ListAuditEventsRequest{OrganizationId: orgA}, false},
+		{"accountant cancels organization deletion", f.accountantA, pb.ErasureService_CancelOrganizationDeletion_FullMethodName, &pb.CancelOrganizationDeletionRequest{OrganizationId: orgA}, false},
+		{"accountant exports organization data", f.accountantA, pb.ExportService_ExportOrganization_FullMethodName, &pb.ExportOrganizationRequest{OrganizationId: orgA}, false},
+		{"recruiter in another organization", f.recruiterA, pb.UserService_InviteUser_FullMethodName, &pb.InviteUserRequest{OrganizationId: f.orgB.String()}, false},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := f.call(tc.userID, tc.method, tc.req)
+			if tc.allowed && err != nil {
+				t.Fatalf("expected access, got %v", err)
+			}
+			if !tc.allowed && !errors.Is(err, domain.ErrForbidden) {
+				t.Fatalf("expected forbidden, got %v", err)
+			}
+		})
+	}
+}
+
+// TestAccessPolicyUnscopedMethods - методы без организации доступны любому аутентифицированному пользователю
+func TestAccessPolicyUnscopedMethods(t *testing.T) {
+	f := newTenantFixture()
+
+	cases := []struct {
+		method string
+		req    any
+	}{
+		{pb.OrganizationService_ListMyOrganizations_FullMethodName, &pb.ListMyOrganizationsRequest{}},
+		{pb.OrganizationService_CreateOrganization_FullMethodName, &pb.CreateOrganizationRequest{}},
+		{pb.OrganizationService_GetOrganizationProfileSchema_FullMethodName, &pb.GetOrganizationProfileSchemaRequest{}},
+		{pb.ContractTemplateService_ListTemplates_FullMethodName, &pb.ListTemplatesRequest{}},
+		{pb.RoleService_ListPermissions_FullMethodName, &pb.ListPermissionsRequest{}},
+		{pb.AuditService_ListMyAuditEvents_FullMethodName, &pb.ListMyAuditEventsRequest{}},
+		{pb.ErasureService_DeleteMyAccount_FullMethodName, &pb.DeleteMyAccountRequest{}},
+		{pb.ErasureService_GetMyAccountDeletion_FullMethodName, &pb.GetMyAccountDeletionRequest{}},
+		{pb.ErasureService_CancelMyAccountDeletion_FullMethodName, &pb.CancelMyAccountDeletionRequest{}},
+		{pb.ErasureService_ListMyDeletionReports_FullMethodName, &pb.ListMyDeletionReportsRequest{}},
+		{pb.StorageService_GenerateDownloadURL_FullMethodName, &pb.GenerateDownloadURLRequest{S3Key: "templates/contract.docx"}},
+		{pb.AuthService_AuthenticateWithTelegram_FullMethodName, &pb.AuthenticateWithTelegramRequest{}},
+	}
+
+	for _, tc := range cases {
+		if err := f.call(f.outsider, tc.method, tc.req); err != nil {
+			t.Errorf("%s: expected access, got %v", tc.method, err)
+		}
+	}
+}
+
+func TestAccessPolicyInvalidRequests(t *testing.T) {
+	f := newTenantFixture()
+
+	err := f.call(f.adminA, pb.DocumentService_GetDocument_FullMethodName, &pb.GetDocumentRequest{Id: domain.NewID().String()})
+	if !errors.Is(err, domain.ErrNotFound) {
+		t.Errorf("unknown document: expected not found, got %v", err)
+	}
+
+	err = f.call(f.adminA, pb.DocumentService_ListDocuments_FullMethodName, &pb.ListDocumentsRequest{OrganizationId: "not-a-uuid"})
+	if !errors.Is(err, domain.ErrInvalidArgument) {
+		t.Errorf("malformed organization id: expected invalid argument, got %v", err)
+	}
+
+	err = f.call(f.adminA, pb.StorageService_GenerateDownloadURL_FullMethodName, &pb.GenerateDownloadURLRequest{S3Key: "documents/../secret"})
+	if !errors.Is(err, domain.ErrInvalidArgument) {
+		t.Errorf("path traversal key: expected invalid argument, got %v", err)
+	}
+
+	err = f.call(f.adminA, "/core.api.core.DocumentService/Unknown", &pb.ListDocumentsRequest{OrganizationId: f.orgA.String()})
+	if !errors.Is(err, domain.ErrForbidden) {
+		t.Errorf("method without rule: expected forbidden, got %v", err)
+	}
+}
+
+// TestAccessPolicyCoversAllMethods - каждый RPC из core.proto либо описан в политике, либо явно публичный
+func TestAccessPolicyCoversAllMethods(t *testing.T) {
+	policy := accessPolicy(&fakeOwners{})
+
+	public := make(map[string]bool, len(unprotectedMethods))
+	for _, method := range unprotectedMethods {
+		public[method] = true
+	}
+
+	services := pb.File_core_core_proto.Services()
+	for i := 0; i < services.Len(); i++ {
+		service := services.Get(i)
+		for j := 0; j < service.Methods().Len(); j++ {
+			method := fmt.Sprintf("/%s/%s", service.FullName(), service.Methods().Get(j).Name())
+			_, ok := policy[method]
+			if ok == public[method] {
+				t.Errorf("%s must be either in the access policy or unprotected", method)
+			}
+		}
+	}
+}
@@ -2,11 +2,16 @@ package app
 
 import (
 	"context"
+	"core-service/internal/app/core-service/api/audit"
 	"core-service/internal/app/core-service/api/auth"
 	"core-service/internal/app/core-service/api/contract"
 	"core-service/internal/app/core-service/api/document"
+	"core-service/internal/app/core-service/api/erasure"
+	"core-service/internal/app/core-service/api/export"
 	"core-service/internal/app/core-service/api/note"
+	"core-service/internal/app/core-service/api/notification"
 	"core-service/internal/app/core-service/api/organization"
+	"core-service/internal/app/core-service/api/role"
 	"core-service/internal/app/core-service/api/storage"
 	"core-service/internal/app/core-service/api/template"
 	"core-service/internal/app/core-service/api/user"
@@ -55,7 +60,14 @@ type App struct {
 	templateService *template.Service
 	contractService *contract.Service
 	storageService  *storage.Service
+	notifyService   *notification.Service
+	roleService     *role.Service
+	auditService    *audit.Service
+	erasureService  *erasure.Service
+	exportService   *export.Service
 	authService     *auth.Service
+	memberships     interceptors.MembershipProvider
+	resources       ResourceOwners
 	grpcServer      *grpc.Server
 	httpServer      *http.Server
 	options         *Options
@@ -69,6 +81,15 @@ type Services struct {
 	TemplateService     template.TemplateService
 	ContractService     contract.ContractService
 	StorageService      storage.StorageService
+	NotificationService notification.NotificationService
+	RoleService         role.RoleService
+	AuditService        audit.AuditService
+	ErasureService      erasure.ErasureService
+	ExportService       export.ExportService
+
+	// Memberships and Resources back the organization access policy
+	Memberships interceptors.MembershipProvider
+	Resources   ResourceOwners
 }
 
 func New(cfg *config.Config, jwtProvider *jwt.Provider, services Services, authSvc *auth.Service, opts ...OptionsFunc) *App {
@@ -87,7 +108,14 @@ func New(cfg *config.Config, jwtProvider *jwt.Provider, services Services, authS
 		templateService: template.NewService(services.TemplateService),
 		contractService: contract.NewService(services.ContractService),
 		storageService:  storage.NewService(services.StorageService),
+		notifyService:   notification.NewService(services.NotificationService),
+		roleService:     role.NewService(services.RoleService),
+		auditService:    audit.NewService(services.AuditService),
+		erasureService:  erasure.NewService(services.ErasureService),
+		exportService:   export.NewService(services.ExportService),
 		authService:     authSvc,
+		memberships:     services.Memberships,
+		resources:       services.Resources,
 		options:         options,
 	}
 
@@ -95,29 +123,35 @@ func New(cfg *config.Config, jwtProvider *jwt.Provider, services Services, authS
 	return app
 }
 
+// Unprotected methods (public endpoints)
+var unprotectedMethods = []string{
+	"/core.api.core.AuthService/AuthenticateWithTelegram",
+	"/core.api.core.AuthService/CompleteRegistration",
+	"/core.api.core.AuthService/AuthenticateBotUser",
+	"/core.api.core.AuthService/AuthenticateServiceUser",
+	"/core.api.core.ContractTemplateService/GetTemplate",
+	"/core.api.core.DocumentService/UpdateDocumentStatus",
+	"/core.api.core.GeneratedContractService/RegisterContract",
+}
+
 func (a *App) setupGRPC() {
-	// Unprotected methods (public endpoints)
-	unprotected := []string{
-		"/core.api.core.AuthService/AuthenticateWithTelegram",
-		"/core.api.core.AuthService/CompleteRegistration",
-		"/core.api.core.ContractTemplateService/GetTemplate",
-		"/core.api.core.DocumentService/UpdateDocumentStatus",
-		"/core.api.core.GeneratedContractService/RegisterContract",
-	}
+	authorizer := interceptors.NewAuthorizer(accessPolicy(a.resources), a.memberships, unprotectedMethods...)
 
 	// Setup interceptors
 	unaryInterceptors := []grpc.UnaryServerInterceptor{
 		interceptors.RecoveryInterceptor,
 		interceptors.TracingInterceptor,
 		interceptors.ErrCodesInterceptor,
-		interceptors.NewUnaryAuthInterceptor(a.jwtProvider, unprotected...),
+		interceptors.NewUnaryAuthInterceptor(a.jwtProvider, unprotectedMethods...),
+		authorizer.UnaryInterceptor(),
 	}
 
 	streamInterceptors := []grpc.StreamServerInterceptor{
 		interceptors.RecoveryStreamInterceptor,
 		interceptors.TracingStreamInterceptor,
 		interceptors.ErrCodesStreamInterceptor,
-		interceptors.NewStreamAuthInterceptor(a.jwtProvider, unprotected...),
+		interceptors.NewStreamAuthInterceptor(a.jwtProvider, unprotectedMethods...),
+		authorizer.StreamInterceptor(),
 	}
 
 	// Create gRPC server
@@ -135,6 +169,11 @@ func (a *App) setupGRPC() {
 	pb.RegisterContractTemplateServiceServer(a.grpcServer, a.templateService)
 	pb.RegisterGeneratedContractServiceServer(a.grpcServer, a.contractService)
 	pb.RegisterStorageServiceServer(a.grpcServer, a.storageService)
+	pb.RegisterNotificationServiceServer(a.grpcServer, a.notifyService)
+	pb.RegisterRoleServiceServer(a.grpcServer, a.roleService)
+	pb.RegisterAuditServiceServer(a.grpcServer, a.auditService)
+	pb.RegisterErasureServiceServer(a.grpcServer, a.erasureService)
+	pb.RegisterExportServiceServer(a.grpcServer, a.exportService)
 
 	// Register reflection for grpcurl
 	reflection.Register(a.grpcServer)
@@ -218,6 +257,21 @@ func (a *App) startHTTPGateway(ctx context.Context) error {
 	if err := pb.RegisterStorageServiceHandlerFromEndpoint(ctx, gatewayMux, grpcAddr, opts); err != nil {
 		return fmt.Errorf("failed to register storage handler: %w", err)
 	}
+	if err := pb.RegisterNotificationServiceHandlerFromEndpoint(ctx, gatewayMux, grpcAddr, opts); err != nil {
+		return fmt.Errorf("failed to register notification handler: %w", err)
+	}
+	if err := pb.RegisterRoleServiceHandlerFromEndpoint(ctx, gatewayMux, grpcAddr, opts); err != nil {
+		return fmt.Errorf("failed to register role handler: %w", err)
+	}
+	if err := pb.RegisterAuditServiceHandlerFromEndpoint(ctx, gatewayMux, grpcAddr, opts); err != nil {
+		return fmt.Errorf("failed to register audit handler: %w", err)
+	}
+	if err := pb.RegisterErasureServiceHandlerFromEndpoint(ctx, gatewayMux, grpcAddr, opts); err != nil {
+		return fmt.Errorf("failed to register erasure handler: %w", err)
+	}
+	if err := pb.RegisterExportServiceHandlerFromEndpoint(ctx, gatewayMux, grpcAddr, opts); err != nil {
+		return fmt.Errorf("failed to register export handler: %w", err)
+	}
 
 	// Setup CORS
 	corsHandler := cors.New(cors.Options{
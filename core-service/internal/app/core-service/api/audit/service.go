@@ -0,0 +1,139 @@
+package audit
+
+import (
+	"context"
+	"core-service/internal/app/interceptors"
+	"core-service/internal/domain"
+	pb "core-service/pkg/core"
+	"encoding/json"
+	"time"
+
+	"github.com/opentracing/opentracing-go"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+type Service struct {
+	pb.UnimplementedAuditServiceServer
+	auditService AuditService
+}
+
+type AuditService interface {
+	ListAuditEvents(ctx context.Context, organizationID domain.ID, actorUserID *domain.ID, action string, from, to *time.Time, page, pageSize int) ([]domain.AuditEvent, int, error)
+	ListUserAuditEvents(ctx context.Context, userID domain.ID, action string, from, to *time.Time, page, pageSize int) ([]domain.AuditEvent, int, error)
+}
+
+func NewService(auditService AuditService) *Service {
+	return &Service{
+		auditService: auditService,
+	}
+}
+
+func auditEventToProto(event domain.AuditEvent) *pb.AuditEvent {
+	metadata := "{}"
+	if len(event.Metadata) > 0 {
+		if raw, err := json.Marshal(event.Metadata); err == nil {
+			metadata = string(raw)
+		}
+	}
+
+	pbEvent := &pb.AuditEvent{
+		Id:           event.ID.String(),
+		Action:       string(event.Action),
+		Source:       event.Source,
+		ResourceType: event.ResourceType,
+		ResourceId:   event.ResourceID,
+		Metadata:     metadata,
+		OccurredAt:   timestamppb.New(event.OccurredAt),
+	}
+	if event.OrganizationID != nil {
+		orgID := event.OrganizationID.String()
+		pbEvent.OrganizationId = &orgID
+	}
+	if event.ActorUserID != nil {
+		actorID := event.ActorUserID.String()
+		pbEvent.ActorUserId = &actorID
+	}
+
+	return pbEvent
+}
+
+func timeFromProto(ts *timestamppb.Timestamp) *time.Time {
+	if ts == nil {
+		return nil
+	}
+	t := ts.AsTime()
+	return &t
+}
+
+func pagination(page, pageSize int32) (int, int) {
+	p := int(page)
+	if p < 1 {
+		p = 1
+	}
+	size := int(pageSize)
+	if size < 1 || size > 100 {
+		size = 20
+	}
+	return p, size
+}
+
+func listResponse(events []domain.AuditEvent, total, page, pageSize int) *pb.ListAuditEventsResponse {
+	pbEvents := make([]*pb.AuditEvent, 0, len(events))
+	for _, event := range events {
+		pbEvents = append(pbEvents, auditEventToProto(event))
+	}
+
+	return &pb.ListAuditEventsResponse{
+		Events:   pbEvents,
+		Total:    int32(total),
+		Page:     int32(page),
+		PageSize: int32(pageSize),
+	}
+}
+
+func (s *Service) ListAuditEvents(ctx context.Context, req *pb.ListAuditEventsRequest) (*pb.ListAuditEventsResponse, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "api.ListAuditEvents")
+	defer span.Finish()
+
+	orgID, err := domain.ParseID(req.OrganizationId)
+	if err != nil {
+		return nil, domain.ErrInvalidArgument
+	}
+
+	var actorUserID *domain.ID
+	if req.ActorUserId != nil {
+		id, err := domain.ParseID(*req.ActorUserId)
+		if err != nil {
+			return nil, domain.ErrInvalidArgument
+		}
+		actorUserID = &id
+	}
+
+	page, pageSize := pagination(req.Page, req.PageSize)
+
+	events, total, err := s.auditService.ListAuditEvents(ctx, orgID, actorUserID, req.GetAction(), timeFromProto(req.From), timeFromProto(req.To), page, pageSize)
+	if err != nil {
+		return nil, err
+	}
+
+	return listResponse(events, total, page, pageSize), nil
+}
+
+func (s *Service) ListMyAuditEvents(ctx context.Context, req *pb.ListMyAuditEventsRequest) (*pb.ListAuditEventsResponse, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "api.ListMyAuditEvents")
+	defer span.Finish()
+
+	userID, err := interceptors.UserIDFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	page, pageSize := pagination(req.Page, req.PageSize)
+
+	events, total, err := s.auditService.ListUserAuditEvents(ctx, userID, req.GetAction(), timeFromProto(req.From), timeFromProto(req.To), page, pageSize)
+	if err != nil {
+		return nil, err
+	}
+
+	return listResponse(events, total, page, pageSize), nil
+}
@@ -5,6 +5,7 @@ import (
 	"core-service/internal/app/interceptors"
 	"core-service/internal/domain"
 	pb "core-service/pkg/core"
+	"time"
 
 	"github.com/opentracing/opentracing-go"
 	"google.golang.org/protobuf/types/known/timestamppb"
@@ -19,6 +20,8 @@ type AuthService interface {
 	AuthenticateWithTelegram(ctx context.Context, initData string) (string, *domain.User, bool, error)
 	CompleteRegistration(ctx context.Context, userID domain.ID, firstName, lastName string) (*domain.User, error)
 	RefreshToken(ctx context.Context, userID domain.ID) (string, error)
+	AuthenticateBotUser(ctx context.Context, botToken, telegramID string) (string, time.Time, *domain.User, []domain.Organization, error)
+	AuthenticateServiceUser(ctx context.Context, serviceToken string, userID domain.ID) (string, time.Time, error)
 }
 
 func NewService(authService AuthService) *Service {
@@ -82,6 +85,55 @@ func (s *Service) RefreshToken(ctx context.Context, req *pb.RefreshTokenRequest)
 	}, nil
 }
 
+func (s *Service) AuthenticateBotUser(ctx context.Context, req *pb.AuthenticateBotUserRequest) (*pb.AuthenticateBotUserResponse, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "api.auth.AuthenticateBotUser")
+	defer span.Finish()
+
+	token, expiresAt, user, orgs, err := s.authService.AuthenticateBotUser(ctx, req.GetBotToken(), req.GetTelegramId())
+	if err != nil {
+		return nil, err
+	}
+
+	pbOrgs := make([]*pb.Organization, 0, len(orgs))
+	for _, org := range orgs {
+		pbOrgs = append(pbOrgs, &pb.Organization{
+			Id:        org.ID.String(),
+			Name:      org.Name,
+			Industry:  org.Industry,
+			Region:    org.Region,
+			CreatedAt: timestamppb.New(org.CreatedAt),
+			UpdatedAt: timestamppb.New(org.UpdatedAt),
+		})
+	}
+
+	return &pb.AuthenticateBotUserResponse{
+		AccessToken:   token,
+		ExpiresAt:     timestamppb.New(expiresAt),
+		User:          userToProto(user),
+		Organizations: pbOrgs,
+	}, nil
+}
+
+func (s *Service) AuthenticateServiceUser(ctx context.Context, req *pb.AuthenticateServiceUserRequest) (*pb.AuthenticateServiceUserResponse, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "api.auth.AuthenticateServiceUser")
+	defer span.Finish()
+
+	userID, err := domain.ParseID(req.GetUserId())
+	if err != nil {
+		return nil, domain.ErrInvalidArgument
+	}
+
+	token, expiresAt, err := s.authService.AuthenticateServiceUser(ctx, req.GetServiceToken(), userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &pb.AuthenticateServiceUserResponse{
+		AccessToken: token,
+		ExpiresAt:   timestamppb.New(expiresAt),
+	}, nil
+}
+
 func userToProto(user *domain.User) *pb.User {
 	return &pb.User{
 		Id:         user.ID.String(),
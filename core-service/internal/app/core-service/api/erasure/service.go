@@ -0,0 +1,228 @@
+package erasure
+
+import (
+	"context"
+	"core-service/internal/app/interceptors"
+	"core-service/internal/domain"
+	pb "core-service/pkg/core"
+	"time"
+
+	"github.com/opentracing/opentracing-go"
+	"google.golang.org/protobuf/types/known/emptypb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+type Service struct {
+	pb.UnimplementedErasureServiceServer
+	erasureService ErasureService
+}
+
+type ErasureService interface {
+	GetOrganizationErasure(ctx context.Context, organizationID domain.ID) (domain.DataErasure, error)
+	CancelOrganizationErasure(ctx context.Context, organizationID domain.ID) error
+	ScheduleAccountErasure(ctx context.Context, userID domain.ID) (domain.DataErasure, error)
+	GetAccountErasure(ctx context.Context, userID domain.ID) (domain.DataErasure, error)
+	CancelAccountErasure(ctx context.Context, userID domain.ID) error
+	ListRequestedErasures(ctx context.Context, userID domain.ID, page, pageSize int) ([]domain.DataErasure, int, error)
+}
+
+func NewService(erasureService ErasureService) *Service {
+	return &Service{
+		erasureService: erasureService,
+	}
+}
+
+func subjectToProto(subject domain.ErasureSubject) pb.ErasureSubject {
+	switch subject {
+	case domain.ErasureSubjectOrganization:
+		return pb.ErasureSubject_ERASURE_SUBJECT_ORGANIZATION
+	case domain.ErasureSubjectUser:
+		return pb.ErasureSubject_ERASURE_SUBJECT_USER
+	default:
+		return pb.ErasureSubject_ERASURE_SUBJECT_UNSPECIFIED
+	}
+}
+
+func statusToProto(status domain.ErasureStatus) pb.ErasureStatus {
+	switch status {
+	case domain.ErasureStatusScheduled:
+		return pb.ErasureStatus_ERASURE_STATUS_SCHEDULED
+	case domain.ErasureStatusInProgress:
+		return pb.ErasureStatus_ERASURE_STATUS_IN_PROGRESS
+	case domain.ErasureStatusCompleted:
+		return pb.ErasureStatus_ERASURE_STATUS_COMPLETED
+	case domain.ErasureStatusFailed:
+		return pb.ErasureStatus_ERASURE_STATUS_FAILED
+	case domain.ErasureStatusCancelled:
+		return pb.ErasureStatus_ERASURE_STATUS_CANCELLED
+	default:
+		return pb.ErasureStatus_ERASURE_STATUS_UNSPECIFIED
+	}
+}
+
+func stepStatusToProto(status domain.ErasureStepStatus) pb.ErasureStepStatus {
+	switch status {
+	case domain.ErasureStepPending:
+		return pb.ErasureStepStatus_ERASURE_STEP_STATUS_PENDING
+	case domain.ErasureStepCompleted:
+		return pb.ErasureStepStatus_ERASURE_STEP_STATUS_COMPLETED
+	case domain.ErasureStepFailed:
+		return pb.ErasureStepStatus_ERASURE_STEP_STATUS_FAILED
+	default:
+		return pb.ErasureStepStatus_ERASURE_STEP_STATUS_UNSPECIFIED
+	}
+}
+
+func timestampOrNil(t *time.Time) *timestamppb.Timestamp {
+	if t == nil {
+		return nil
+	}
+	return timestamppb.New(*t)
+}
+
+func erasureToProto(erasure domain.DataErasure) *pb.DataErasure {
+	steps := make([]*pb.ErasureStepReport, 0, len(erasure.Steps))
+	for _, step := range domain.ErasureSteps(erasure.SubjectType) {
+		report := erasure.Steps[step]
+		steps = append(steps, &pb.ErasureStepReport{
+			Step:         string(step),
+			Status:       stepStatusToProto(report.Status),
+			ItemsDeleted: int32(report.ItemsDeleted),
+			Error:        report.Error,
+			FinishedAt:   timestampOrNil(report.FinishedAt),
+		})
+	}
+
+	return &pb.DataErasure{
+		Id:           erasure.ID.String(),
+		SubjectType:  subjectToProto(erasure.SubjectType),
+		SubjectId:    erasure.SubjectID.String(),
+		SubjectName:  erasure.SubjectName,
+		Status:       statusToProto(erasure.Status),
+		PurgeAfter:   timestamppb.New(erasure.PurgeAfter),
+		Steps:        steps,
+		ItemsDeleted: int32(erasure.ItemsDeleted()),
+		CreatedAt:    timestamppb.New(erasure.CreatedAt),
+		StartedAt:    timestampOrNil(erasure.StartedAt),
+		CompletedAt:  timestampOrNil(erasure.CompletedAt),
+	}
+}
+
+func (s *Service) GetOrganizationDeletion(ctx context.Context, req *pb.GetOrganizationDeletionRequest) (*pb.GetOrganizationDeletionResponse, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "api.GetOrganizationDeletion")
+	defer span.Finish()
+
+	orgID, err := domain.ParseID(req.OrganizationId)
+	if err != nil {
+		return nil, domain.ErrInvalidArgument
+	}
+
+	erasure, err := s.erasureService.GetOrganizationErasure(ctx, orgID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &pb.GetOrganizationDeletionResponse{Erasure: erasureToProto(erasure)}, nil
+}
+
+func (s *Service) CancelOrganizationDeletion(ctx context.Context, req *pb.CancelOrganizationDeletionRequest) (*emptypb.Empty, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "api.CancelOrganizationDeletion")
+	defer span.Finish()
+
+	orgID, err := domain.ParseID(req.OrganizationId)
+	if err != nil {
+		return nil, domain.ErrInvalidArgument
+	}
+
+	if err := s.erasureService.CancelOrganizationErasure(ctx, orgID); err != nil {
+		return nil, err
+	}
+
+	return &emptypb.Empty{}, nil
+}
+
+func (s *Service) DeleteMyAccount(ctx context.Context, req *pb.DeleteMyAccountRequest) (*pb.DeleteMyAccountResponse, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "api.DeleteMyAccount")
+	defer span.Finish()
+
+	userID, err := interceptors.UserIDFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	erasure, err := s.erasureService.ScheduleAccountErasure(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &pb.DeleteMyAccountResponse{Erasure: erasureToProto(erasure)}, nil
+}
+
+func (s *Service) GetMyAccountDeletion(ctx context.Context, req *pb.GetMyAccountDeletionRequest) (*pb.GetMyAccountDeletionResponse, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "api.GetMyAccountDeletion")
+	defer span.Finish()
+
+	userID, err := interceptors.UserIDFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	erasure, err := s.erasureService.GetAccountErasure(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &pb.GetMyAccountDeletionResponse{Erasure: erasureToProto(erasure)}, nil
+}
+
+func (s *Service) CancelMyAccountDeletion(ctx context.Context, req *pb.CancelMyAccountDeletionRequest) (*emptypb.Empty, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "api.CancelMyAccountDeletion")
+	defer span.Finish()
+
+	userID, err := interceptors.UserIDFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.erasureService.CancelAccountErasure(ctx, userID); err != nil {
+		return nil, err
+	}
+
+	return &emptypb.Empty{}, nil
+}
+
+func (s *Service) ListMyDeletionReports(ctx context.Context, req *pb.ListMyDeletionReportsRequest) (*pb.ListMyDeletionReportsResponse, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "api.ListMyDeletionReports")
+	defer span.Finish()
+
+	userID, err := interceptors.UserIDFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	page := int(req.Page)
+	if page < 1 {
+		page = 1
+	}
+	pageSize := int(req.PageSize)
+	if pageSize < 1 || pageSize > 100 {
+		pageSize = 20
+	}
+
+	erasures, total, err := s.erasureService.ListRequestedErasures(ctx, userID, page, pageSize)
+	if err != nil {
+		return nil, err
+	}
+
+	pbErasures := make([]*pb.DataErasure, 0, len(erasures))
+	for _, erasure := range erasures {
+		pbErasures = append(pbErasures, erasureToProto(erasure))
+	}
+
+	return &pb.ListMyDeletionReportsResponse{
+		Erasures: pbErasures,
+		Total:    int32(total),
+		Page:     int32(page),
+		PageSize: int32(pageSize),
+	}, nil
+}
@@ -0,0 +1,185 @@
+package export
+
+import (
+	"context"
+	"core-service/internal/app/interceptors"
+	"core-service/internal/domain"
+	"core-service/internal/logger"
+	pb "core-service/pkg/core"
+	"time"
+
+	"github.com/opentracing/opentracing-go"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+type Service struct {
+	pb.UnimplementedExportServiceServer
+	exportService ExportService
+}
+
+type ExportService interface {
+	ExportOrganization(ctx context.Context, organizationID, requestedBy domain.ID) (domain.OrganizationExport, error)
+	GetExport(ctx context.Context, organizationID, exportID domain.ID) (domain.OrganizationExport, error)
+	ListExports(ctx context.Context, organizationID domain.ID, page, pageSize int) ([]domain.OrganizationExport, int, error)
+	DownloadURL(ctx context.Context, export domain.OrganizationExport) (string, error)
+}
+
+func NewService(exportService ExportService) *Service {
+	return &Service{
+		exportService: exportService,
+	}
+}
+
+func statusToProto(status domain.ExportStatus) pb.ExportStatus {
+	switch status {
+	case domain.ExportStatusPending:
+		return pb.ExportStatus_EXPORT_STATUS_PENDING
+	case domain.ExportStatusInProgress:
+		return pb.ExportStatus_EXPORT_STATUS_IN_PROGRESS
+	case domain.ExportStatusCompleted:
+		return pb.ExportStatus_EXPORT_STATUS_COMPLETED
+	case domain.ExportStatusFailed:
+		return pb.ExportStatus_EXPORT_STATUS_FAILED
+	case domain.ExportStatusExpired:
+		return pb.ExportStatus_EXPORT_STATUS_EXPIRED
+	default:
+		return pb.ExportStatus_EXPORT_STATUS_UNSPECIFIED
+	}
+}
+
+func stageToProto(stage domain.ExportStage) pb.ExportStage {
+	switch stage {
+	case domain.ExportStageAssistantData:
+		return pb.ExportStage_EXPORT_STAGE_ASSISTANT_DATA
+	case domain.ExportStageDatabase:
+		return pb.ExportStage_EXPORT_STAGE_DATABASE
+	case domain.ExportStageFiles:
+		return pb.ExportStage_EXPORT_STAGE_FILES
+	case domain.ExportStageUpload:
+		return pb.ExportStage_EXPORT_STAGE_UPLOAD
+	case domain.ExportStageDone:
+		return pb.ExportStage_EXPORT_STAGE_DONE
+	default:
+		return pb.ExportStage_EXPORT_STAGE_UNSPECIFIED
+	}
+}
+
+func timestampOrNil(t *time.Time) *timestamppb.Timestamp {
+	if t == nil {
+		return nil
+	}
+	return timestamppb.New(*t)
+}
+
+// exportToProto converts an export; a download link is generated while the archive is available
+func (s *Service) exportToProto(ctx context.Context, export domain.OrganizationExport) *pb.OrganizationExport {
+	pbExport := &pb.OrganizationExport{
+		Id:              export.ID.String(),
+		OrganizationId:  export.OrganizationID.String(),
+		Status:          statusToProto(export.Status),
+		Stage:           stageToProto(export.Stage),
+		ProcessedItems:  int32(export.ProcessedItems),
+		TotalItems:      int32(export.TotalItems),
+		ProgressPercent: int32(export.ProgressPercent()),
+		FileSize:        export.FileSize,
+		CreatedAt:       timestamppb.New(export.CreatedAt),
+		CompletedAt:     timestampOrNil(export.CompletedAt),
+		ExpiresAt:       timestampOrNil(export.ExpiresAt),
+	}
+
+	if export.RequestedBy != nil {
+		requestedBy := export.RequestedBy.String()
+		pbExport.RequestedBy = &requestedBy
+	}
+	if export.ErrorMessage != nil {
+		pbExport.Error = *export.ErrorMessage
+	}
+	if export.IsDownloadable(time.Now()) {
+		url, err := s.exportService.DownloadURL(ctx, export)
+		if err != nil {
+			logger.Errorf(ctx, "failed to generate download url for export %s: %v", export.ID, err)
+		}
+		pbExport.DownloadUrl = url
+	}
+
+	return pbExport
+}
+
+func (s *Service) ExportOrganization(ctx context.Context, req *pb.ExportOrganizationRequest) (*pb.ExportOrganizationResponse, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "api.ExportOrganization")
+	defer span.Finish()
+
+	orgID, err := domain.ParseID(req.OrganizationId)
+	if err != nil {
+		return nil, domain.ErrInvalidArgument
+	}
+
+	userID, err := interceptors.UserIDFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	export, err := s.exportService.ExportOrganization(ctx, orgID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &pb.ExportOrganizationResponse{Export: s.exportToProto(ctx, export)}, nil
+}
+
+func (s *Service) GetOrganizationExport(ctx context.Context, req *pb.GetOrganizationExportRequest) (*pb.GetOrganizationExportResponse, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "api.GetOrganizationExport")
+	defer span.Finish()
+
+	orgID, err := domain.ParseID(req.OrganizationId)
+	if err != nil {
+		return nil, domain.ErrInvalidArgument
+	}
+	exportID, err := domain.ParseID(req.ExportId)
+	if err != nil {
+		return nil, domain.ErrInvalidArgument
+	}
+
+	export, err := s.exportService.GetExport(ctx, orgID, exportID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &pb.GetOrganizationExportResponse{Export: s.exportToProto(ctx, export)}, nil
+}
+
+func (s *Service) ListOrganizationExports(ctx context.Context, req *pb.ListOrganizationExportsRequest) (*pb.ListOrganizationExportsResponse, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "api.ListOrganizationExports")
+	defer span.Finish()
+
+	orgID, err := domain.ParseID(req.OrganizationId)
+	if err != nil {
+		return nil, domain.ErrInvalidArgument
+	}
+
+	page := int(req.Page)
+	if page < 1 {
+		page = 1
+	}
+	pageSize := int(req.PageSize)
+	if pageSize < 1 || pageSize > 100 {
+		pageSize = 20
+	}
+
+	exports, total, err := s.exportService.ListExports(ctx, orgID, page, pageSize)
+	if err != nil {
+		return nil, err
+	}
+
+	pbExports := make([]*pb.OrganizationExport, 0, len(exports))
+	for _, export := range exports {
+		pbExports = append(pbExports, s.exportToProto(ctx, export))
+	}
+
+	return &pb.ListOrganizationExportsResponse{
+		Exports:  pbExports,
+		Total:    int32(total),
+		Page:     int32(page),
+		PageSize: int32(pageSize),
+	}, nil
+}
@@ -0,0 +1,127 @@
+package notification
+
+import (
+	"context"
+	"core-service/internal/app/interceptors"
+	"core-service/internal/domain"
+	pb "core-service/pkg/core"
+
+	"github.com/opentracing/opentracing-go"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+type Service struct {
+	pb.UnimplementedNotificationServiceServer
+	notificationService NotificationService
+}
+
+type NotificationService interface {
+	GetPreferences(ctx context.Context, userID domain.ID) (domain.NotificationPreferences, error)
+	UpdatePreferences(ctx context.Context, userID domain.ID, telegramEnabled bool, mutedEvents []domain.NotificationEventType) (domain.NotificationPreferences, error)
+}
+
+func NewService(notificationService NotificationService) *Service {
+	return &Service{
+		notificationService: notificationService,
+	}
+}
+
+func preferencesToProto(prefs domain.NotificationPreferences) *pb.NotificationPreferences {
+	muted := make([]pb.NotificationEventType, 0, len(prefs.MutedEvents))
+	for _, eventType := range prefs.MutedEvents {
+		if pbType := eventTypeToProto(eventType); pbType != pb.NotificationEventType_NOTIFICATION_EVENT_TYPE_UNSPECIFIED {
+			muted = append(muted, pbType)
+		}
+	}
+
+	return &pb.NotificationPreferences{
+		TelegramEnabled: prefs.TelegramEnabled,
+		MutedEvents:     muted,
+		UpdatedAt:       timestamppb.New(prefs.UpdatedAt),
+	}
+}
+
+func eventTypeToProto(eventType domain.NotificationEventType) pb.NotificationEventType {
+	switch eventType {
+	case domain.NotificationEventDocumentIndexed:
+		return pb.NotificationEventType_NOTIFICATION_EVENT_TYPE_DOCUMENT_INDEXED
+	case domain.NotificationEventDocumentFailed:
+		return pb.NotificationEventType_NOTIFICATION_EVENT_TYPE_DOCUMENT_FAILED
+	case domain.NotificationEventScheduledRun:
+		return pb.NotificationEventType_NOTIFICATION_EVENT_TYPE_SCHEDULED_RUN
+	case domain.NotificationEventInvitationAccepted:
+		return pb.NotificationEventType_NOTIFICATION_EVENT_TYPE_INVITATION_ACCEPTED
+	case domain.NotificationEventDataErased:
+		return pb.NotificationEventType_NOTIFICATION_EVENT_TYPE_DATA_ERASED
+	case domain.NotificationEventExportFinished:
+		return pb.NotificationEventType_NOTIFICATION_EVENT_TYPE_EXPORT_FINISHED
+	default:
+		return pb.NotificationEventType_NOTIFICATION_EVENT_TYPE_UNSPECIFIED
+	}
+}
+
+func eventTypeFromProto(eventType pb.NotificationEventType) (domain.NotificationEventType, error) {
+	switch eventType {
+	case pb.NotificationEventType_NOTIFICATION_EVENT_TYPE_DOCUMENT_INDEXED:
+		return domain.NotificationEventDocumentIndexed, nil
+	case pb.NotificationEventType_NOTIFICATION_EVENT_TYPE_DOCUMENT_FAILED:
+		return domain.NotificationEventDocumentFailed, nil
+	case pb.NotificationEventType_NOTIFICATION_EVENT_TYPE_SCHEDULED_RUN:
+		return domain.NotificationEventScheduledRun, nil
+	case pb.NotificationEventType_NOTIFICATION_EVENT_TYPE_INVITATION_ACCEPTED:
+		return domain.NotificationEventInvitationAccepted, nil
+	case pb.NotificationEventType_NOTIFICATION_EVENT_TYPE_DATA_ERASED:
+		return domain.NotificationEventDataErased, nil
+	case pb.NotificationEventType_NOTIFICATION_EVENT_TYPE_EXPORT_FINISHED:
+		return domain.NotificationEventExportFinished, nil
+	default:
+		return "", domain.NewInvalidArgumentError("unknown notification event type")
+	}
+}
+
+func (s *Service) GetNotificationPreferences(ctx context.Context, req *pb.GetNotificationPreferencesRequest) (*pb.GetNotificationPreferencesResponse, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "api.GetNotificationPreferences")
+	defer span.Finish()
+
+	userID, err := interceptors.UserIDFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	prefs, err := s.notificationService.GetPreferences(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &pb.GetNotificationPreferencesResponse{
+		Preferences: preferencesToProto(prefs),
+	}, nil
+}
+
+func (s *Service) UpdateNotificationPreferences(ctx context.Context, req *pb.UpdateNotificationPreferencesRequest) (*pb.UpdateNotificationPreferencesResponse, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "api.UpdateNotificationPreferences")
+	defer span.Finish()
+
+	userID, err := interceptors.UserIDFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	muted := make([]domain.NotificationEventType, 0, len(req.MutedEvents))
+	for _, pbType := range req.MutedEvents {
+		eventType, err := eventTypeFromProto(pbType)
+		if err != nil {
+			return nil, err
+		}
+		muted = append(muted, eventType)
+	}
+
+	prefs, err := s.notificationService.UpdatePreferences(ctx, userID, req.TelegramEnabled, muted)
+	if err != nil {
+		return nil, err
+	}
+
+	return &pb.UpdateNotificationPreferencesResponse{
+		Preferences: preferencesToProto(prefs),
+	}, nil
+}
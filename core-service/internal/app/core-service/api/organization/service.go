@@ -21,7 +21,7 @@ type OrganizationService interface {
 	GetOrganization(ctx context.Context, id domain.ID) (domain.Organization, error)
 	ListMyOrganizations(ctx context.Context, userID domain.ID) ([]domain.Organization, error)
 	UpdateOrganization(ctx context.Context, id domain.ID, name, industry, region, description, profileData *string) (domain.Organization, error)
-	DeleteOrganization(ctx context.Context, id domain.ID) error
+	DeleteOrganization(ctx context.Context, id, requestedBy domain.ID) error
 }
 
 func NewService(orgService OrganizationService) *Service {
@@ -113,7 +113,12 @@ func (s *Service) DeleteOrganization(ctx context.Context, req *pb.DeleteOrganiza
 		return nil, domain.ErrInvalidArgument
 	}
 
-	err = s.orgService.DeleteOrganization(ctx, id)
+	userID, err := interceptors.UserIDFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.orgService.DeleteOrganization(ctx, id, userID)
 	if err != nil {
 		return nil, err
 	}
@@ -144,3 +149,54 @@ func (s *Service) ListMyOrganizations(ctx context.Context, req *pb.ListMyOrganiz
 		Organizations: pbOrgs,
 	}, nil
 }
+
+func (s *Service) GetOrganizationProfileSchema(ctx context.Context, _ *pb.GetOrganizationProfileSchemaRequest) (*pb.GetOrganizationProfileSchemaResponse, error) {
+	span, _ := opentracing.StartSpanFromContext(ctx, "api.GetOrganizationProfileSchema")
+	defer span.Finish()
+
+	schema := domain.GetOrganizationProfileSchema()
+	fields := make([]*pb.ProfileField, 0, len(schema.Fields))
+	for _, field := range schema.Fields {
+		options := make([]*pb.ProfileFieldOption, 0, len(field.Options))
+		for _, option := range field.Options {
+			options = append(options, &pb.ProfileFieldOption{
+				Value: option.Value,
+				Title: option.Title,
+			})
+		}
+
+		fields = append(fields, &pb.ProfileField{
+			Key:         field.Key,
+			Title:       field.Title,
+			Description: field.Description,
+			Type:        profileFieldTypeToProto(field.Type),
+			Options:     options,
+			Pattern:     field.Pattern,
+			Min:         field.Min,
+			Max:         field.Max,
+			Check:       string(field.Check),
+		})
+	}
+
+	return &pb.GetOrganizationProfileSchemaResponse{
+		Schema: &pb.OrganizationProfileSchema{
+			Version: int32(schema.Version),
+			Fields:  fields,
+		},
+	}, nil
+}
+
+func profileFieldTypeToProto(fieldType domain.ProfileFieldType) pb.ProfileFieldType {
+	switch fieldType {
+	case domain.ProfileFieldString:
+		return pb.ProfileFieldType_PROFILE_FIELD_TYPE_STRING
+	case domain.ProfileFieldInteger:
+		return pb.ProfileFieldType_PROFILE_FIELD_TYPE_INTEGER
+	case domain.ProfileFieldNumber:
+		return pb.ProfileFieldType_PROFILE_FIELD_TYPE_NUMBER
+	case domain.ProfileFieldEnum:
+		return pb.ProfileFieldType_PROFILE_FIELD_TYPE_ENUM
+	default:
+		return pb.ProfileFieldType_PROFILE_FIELD_TYPE_UNSPECIFIED
+	}
+}
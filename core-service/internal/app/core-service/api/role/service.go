@@ -0,0 +1,174 @@
+package role
+
+import (
+	"context"
+	"core-service/internal/app/interceptors"
+	"core-service/internal/domain"
+	pb "core-service/pkg/core"
+
+	"github.com/opentracing/opentracing-go"
+	"google.golang.org/protobuf/types/known/emptypb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+type Service struct {
+	pb.UnimplementedRoleServiceServer
+	roleService RoleService
+}
+
+type RoleService interface {
+	ListRoles(ctx context.Context, organizationID domain.ID) ([]domain.CustomRole, error)
+	CreateRole(ctx context.Context, organizationID domain.ID, actor *domain.OrganizationMember, name, description string, permissions []domain.Permission) (domain.CustomRole, error)
+	UpdateRole(ctx context.Context, organizationID, id domain.ID, actor *domain.OrganizationMember, name, description string, permissions []domain.Permission) (domain.CustomRole, error)
+	DeleteRole(ctx context.Context, organizationID, id domain.ID) error
+}
+
+func NewService(roleService RoleService) *Service {
+	return &Service{
+		roleService: roleService,
+	}
+}
+
+func permissionsToProto(permissions []domain.Permission) []string {
+	result := make([]string, 0, len(permissions))
+	for _, p := range permissions {
+		result = append(result, string(p))
+	}
+	return result
+}
+
+func permissionsFromProto(permissions []string) []domain.Permission {
+	result := make([]domain.Permission, 0, len(permissions))
+	for _, p := range permissions {
+		result = append(result, domain.Permission(p))
+	}
+	return result
+}
+
+func customRoleToProto(role domain.CustomRole) *pb.Role {
+	return &pb.Role{
+		Id:             role.ID.String(),
+		OrganizationId: role.OrganizationID.String(),
+		Name:           role.Name,
+		Description:    role.Description,
+		Permissions:    permissionsToProto(role.Permissions),
+		BuiltinRole:    pb.UserRole_USER_ROLE_UNSPECIFIED,
+		CreatedAt:      timestamppb.New(role.CreatedAt),
+		UpdatedAt:      timestamppb.New(role.UpdatedAt),
+	}
+}
+
+func builtinRoleToProto(organizationID domain.ID, role domain.UserRole, builtin pb.UserRole, description string) *pb.Role {
+	return &pb.Role{
+		OrganizationId: organizationID.String(),
+		Name:           string(role),
+		Description:    description,
+		Permissions:    permissionsToProto(domain.RolePermissions(role)),
+		BuiltinRole:    builtin,
+	}
+}
+
+func (s *Service) ListPermissions(ctx context.Context, _ *pb.ListPermissionsRequest) (*pb.ListPermissionsResponse, error) {
+	span, _ := opentracing.StartSpanFromContext(ctx, "api.ListPermissions")
+	defer span.Finish()
+
+	permissions := domain.AllPermissions()
+	result := make([]*pb.PermissionInfo, 0, len(permissions))
+	for _, p := range permissions {
+		result = append(result, &pb.PermissionInfo{
+			Name:        string(p),
+			Description: p.Description(),
+		})
+	}
+
+	return &pb.ListPermissionsResponse{Permissions: result}, nil
+}
+
+func (s *Service) ListRoles(ctx context.Context, req *pb.ListRolesRequest) (*pb.ListRolesResponse, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "api.ListRoles")
+	defer span.Finish()
+
+	orgID, err := domain.ParseID(req.OrganizationId)
+	if err != nil {
+		return nil, domain.ErrInvalidArgument
+	}
+
+	custom, err := s.roleService.ListRoles(ctx, orgID)
+	if err != nil {
+		return nil, err
+	}
+
+	roles := []*pb.Role{
+		builtinRoleToProto(orgID, domain.UserRoleAdmin, pb.UserRole_USER_ROLE_ADMIN, "Все права в организации"),
+		builtinRoleToProto(orgID, domain.UserRoleEmployee, pb.UserRole_USER_ROLE_EMPLOYEE, "Работа с агентом, документами и договорами"),
+	}
+	for _, role := range custom {
+		roles = append(roles, customRoleToProto(role))
+	}
+
+	return &pb.ListRolesResponse{Roles: roles}, nil
+}
+
+func (s *Service) CreateRole(ctx context.Context, req *pb.CreateRoleRequest) (*pb.CreateRoleResponse, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "api.CreateRole")
+	defer span.Finish()
+
+	orgID, err := domain.ParseID(req.OrganizationId)
+	if err != nil {
+		return nil, domain.ErrInvalidArgument
+	}
+
+	actor, _ := interceptors.MembershipFromContext(ctx)
+
+	role, err := s.roleService.CreateRole(ctx, orgID, actor, req.Name, req.Description, permissionsFromProto(req.Permissions))
+	if err != nil {
+		return nil, err
+	}
+
+	return &pb.CreateRoleResponse{Role: customRoleToProto(role)}, nil
+}
+
+func (s *Service) UpdateRole(ctx context.Context, req *pb.UpdateRoleRequest) (*pb.UpdateRoleResponse, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "api.UpdateRole")
+	defer span.Finish()
+
+	orgID, err := domain.ParseID(req.OrganizationId)
+	if err != nil {
+		return nil, domain.ErrInvalidArgument
+	}
+
+	id, err := domain.ParseID(req.Id)
+	if err != nil {
+		return nil, domain.ErrInvalidArgument
+	}
+
+	actor, _ := interceptors.MembershipFromContext(ctx)
+
+	role, err := s.roleService.UpdateRole(ctx, orgID, id, actor, req.Name, req.Description, permissionsFromProto(req.Permissions))
+	if err != nil {
+		return nil, err
+	}
+
+	return &pb.UpdateRoleResponse{Role: customRoleToProto(role)}, nil
+}
+
+func (s *Service) DeleteRole(ctx context.Context, req *pb.DeleteRoleRequest) (*emptypb.Empty, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "api.DeleteRole")
+	defer span.Finish()
+
+	orgID, err := domain.ParseID(req.OrganizationId)
+	if err != nil {
+		return nil, domain.ErrInvalidArgument
+	}
+
+	id, err := domain.ParseID(req.Id)
+	if err != nil {
+		return nil, domain.ErrInvalidArgument
+	}
+
+	if err := s.roleService.DeleteRole(ctx, orgID, id); err != nil {
+		return nil, err
+	}
+
+	return &emptypb.Empty{}, nil
+}
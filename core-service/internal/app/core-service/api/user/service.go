@@ -6,6 +6,7 @@ import (
 	"core-service/internal/domain"
 	pb "core-service/pkg/core"
 	"fmt"
+	"time"
 
 	"github.com/opentracing/opentracing-go"
 	"google.golang.org/protobuf/types/known/emptypb"
@@ -19,14 +20,19 @@ type Service struct {
 }
 
 type UserService interface {
-	InviteUser(ctx context.Context, organizationID domain.ID, role domain.UserRole, invitedBy domain.ID) (*domain.Invitation, error)
+	InviteUser(ctx context.Context, organizationID domain.ID, actor *domain.OrganizationMember, role domain.UserRole, customRoleID *domain.ID, maxUses int, ttl time.Duration) (*domain.Invitation, error)
 	AcceptInvitation(ctx context.Context, userID domain.ID, token string) (*domain.User, error)
 	ListUsersByOrganization(ctx context.Context, organizationID domain.ID) ([]*domain.UserWithMembership, error)
 	ListInvitations(ctx context.Context, organizationID domain.ID, limit, offset int) ([]domain.Invitation, int, error)
 	DeleteInvitation(ctx context.Context, invitationID domain.ID, userID domain.ID) error
+	RevokeInvitation(ctx context.Context, invitationID domain.ID) (*domain.Invitation, error)
+	RegenerateInvitation(ctx context.Context, invitationID domain.ID, actor *domain.OrganizationMember, ttl time.Duration) (*domain.Invitation, error)
+	ListInvitationUses(ctx context.Context, invitationID domain.ID, page, pageSize int) ([]domain.InvitationUse, int, error)
 	GetUser(ctx context.Context, id domain.ID) (*domain.User, error)
-	UpdateUserRole(ctx context.Context, id domain.ID, role domain.UserRole) (*domain.User, error)
-	DeactivateUser(ctx context.Context, id domain.ID) error
+	UpdateUserRole(ctx context.Context, organizationID, id domain.ID, actor *domain.OrganizationMember, role domain.UserRole, customRoleID *domain.ID) (*domain.UserWithMembership, error)
+	DeactivateUser(ctx context.Context, organizationID, id domain.ID, actor *domain.OrganizationMember) error
+	LeaveOrganization(ctx context.Context, organizationID, userID domain.ID) error
+	TransferOwnership(ctx context.Context, organizationID domain.ID, actor *domain.OrganizationMember, newOwnerID domain.ID) (*domain.UserWithMembership, error)
 }
 
 func NewService(userService UserService, miniAppURL string) *Service {
@@ -37,16 +43,24 @@ func NewService(userService UserService, miniAppURL string) *Service {
 }
 
 func userToProto(user *domain.UserWithMembership) *pb.User {
-	return &pb.User{
-		Id:         user.User.ID.String(),
-		TelegramId: user.User.TelegramID,
-		FirstName:  user.User.FirstName,
-		LastName:   user.User.LastName,
-		Role:       userRoleToProto(user.OrganizationMember.Role),
-		Status:     userStatusToProto(user.OrganizationMember.Status),
-		CreatedAt:  timestamppb.New(user.User.CreatedAt),
-		UpdatedAt:  timestamppb.New(user.User.UpdatedAt),
+	result := &pb.User{
+		Id:             user.User.ID.String(),
+		TelegramId:     user.User.TelegramID,
+		FirstName:      user.User.FirstName,
+		LastName:       user.User.LastName,
+		Role:           userRoleToProto(user.OrganizationMember.Role),
+		Status:         userStatusToProto(user.OrganizationMember.Status),
+		CreatedAt:      timestamppb.New(user.User.CreatedAt),
+		UpdatedAt:      timestamppb.New(user.User.UpdatedAt),
+		CustomRoleName: user.OrganizationMember.CustomRoleName,
+	}
+	if user.OrganizationMember.CustomRoleID != nil {
+		result.CustomRoleId = user.OrganizationMember.CustomRoleID.String()
 	}
+	for _, p := range user.OrganizationMember.Permissions() {
+		result.Permissions = append(result.Permissions, string(p))
+	}
+	return result
 }
 
 func simpleUserToProto(user *domain.User) *pb.User {
@@ -92,6 +106,8 @@ func userStatusToProto(status domain.UserStatus) pb.UserStatus {
 		return pb.UserStatus_USER_STATUS_ACTIVE
 	case domain.UserStatusInactive:
 		return pb.UserStatus_USER_STATUS_INACTIVE
+	case domain.UserStatusLeft:
+		return pb.UserStatus_USER_STATUS_LEFT
 	default:
 		return pb.UserStatus_USER_STATUS_UNSPECIFIED
 	}
@@ -106,25 +122,31 @@ func (s *Service) InviteUser(ctx context.Context, req *pb.InviteUserRequest) (*p
 		return nil, domain.ErrInvalidArgument
 	}
 
-	invitedBy, err := interceptors.UserIDFromContext(ctx)
-	if err != nil {
-		return nil, err
+	var customRoleID *domain.ID
+	if req.CustomRoleId != nil {
+		id, err := domain.ParseID(*req.CustomRoleId)
+		if err != nil {
+			return nil, domain.ErrInvalidArgument
+		}
+		customRoleID = &id
 	}
 
+	actor, _ := interceptors.MembershipFromContext(ctx)
 	role := userRoleFromProto(req.Role)
+	ttl := time.Duration(req.ExpiresInHours) * time.Hour
 
-	invitation, err := s.userService.InviteUser(ctx, orgID, role, invitedBy)
+	invitation, err := s.userService.InviteUser(ctx, orgID, actor, role, customRoleID, int(req.MaxUses), ttl)
 	if err != nil {
 		return nil, err
 	}
 
-	// Generate Telegram Mini App URL with invitation token
-	invitationURL := fmt.Sprintf("%s?startapp=invitation_%s", s.miniAppURL, invitation.Token)
+	pbInvitation := s.invitationToProto(invitation)
 
 	return &pb.InviteUserResponse{
 		InvitationToken: invitation.Token,
-		InvitationUrl:   invitationURL,
+		InvitationUrl:   pbInvitation.InvitationUrl,
 		ExpiresAt:       timestamppb.New(invitation.ExpiresAt),
+		Invitation:      pbInvitation,
 	}, nil
 }
 
@@ -195,20 +217,34 @@ func (s *Service) UpdateUserRole(ctx context.Context, req *pb.UpdateUserRoleRequ
 	span, ctx := opentracing.StartSpanFromContext(ctx, "api.UpdateUserRole")
 	defer span.Finish()
 
+	orgID, err := domain.ParseID(req.OrganizationId)
+	if err != nil {
+		return nil, domain.ErrInvalidArgument
+	}
+
 	id, err := domain.ParseID(req.Id)
 	if err != nil {
 		return nil, domain.ErrInvalidArgument
 	}
 
-	role := userRoleFromProto(req.Role)
+	var customRoleID *domain.ID
+	if req.CustomRoleId != nil {
+		roleID, err := domain.ParseID(*req.CustomRoleId)
+		if err != nil {
+			return nil, domain.ErrInvalidArgument
+		}
+		customRoleID = &roleID
+	}
+
+	actor, _ := interceptors.MembershipFromContext(ctx)
 
-	user, err := s.userService.UpdateUserRole(ctx, id, role)
+	user, err := s.userService.UpdateUserRole(ctx, orgID, id, actor, userRoleFromProto(req.Role), customRoleID)
 	if err != nil {
 		return nil, err
 	}
 
 	return &pb.UpdateUserRoleResponse{
-		User: simpleUserToProto(user),
+		User: userToProto(user),
 	}, nil
 }
 
@@ -216,12 +252,41 @@ func (s *Service) DeactivateUser(ctx context.Context, req *pb.DeactivateUserRequ
 	span, ctx := opentracing.StartSpanFromContext(ctx, "api.DeactivateUser")
 	defer span.Finish()
 
+	orgID, err := domain.ParseID(req.OrganizationId)
+	if err != nil {
+		return nil, domain.ErrInvalidArgument
+	}
+
 	id, err := domain.ParseID(req.Id)
 	if err != nil {
 		return nil, domain.ErrInvalidArgument
 	}
 
-	err = s.userService.DeactivateUser(ctx, id)
+	actor, _ := interceptors.MembershipFromContext(ctx)
+
+	err = s.userService.DeactivateUser(ctx, orgID, id, actor)
+	if err != nil {
+		return nil, err
+	}
+
+	return &emptypb.Empty{}, nil
+}
+
+func (s *Service) LeaveOrganization(ctx context.Context, req *pb.LeaveOrganizationRequest) (*emptypb.Empty, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "api.LeaveOrganization")
+	defer span.Finish()
+
+	orgID, err := domain.ParseID(req.OrganizationId)
+	if err != nil {
+		return nil, domain.ErrInvalidArgument
+	}
+
+	userID, err := interceptors.UserIDFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.userService.LeaveOrganization(ctx, orgID, userID)
 	if err != nil {
 		return nil, err
 	}
@@ -229,6 +294,32 @@ func (s *Service) DeactivateUser(ctx context.Context, req *pb.DeactivateUserRequ
 	return &emptypb.Empty{}, nil
 }
 
+func (s *Service) TransferOwnership(ctx context.Context, req *pb.TransferOwnershipRequest) (*pb.TransferOwnershipResponse, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "api.TransferOwnership")
+	defer span.Finish()
+
+	orgID, err := domain.ParseID(req.OrganizationId)
+	if err != nil {
+		return nil, domain.ErrInvalidArgument
+	}
+
+	newOwnerID, err := domain.ParseID(req.NewOwnerId)
+	if err != nil {
+		return nil, domain.ErrInvalidArgument
+	}
+
+	actor, _ := interceptors.MembershipFromContext(ctx)
+
+	newOwner, err := s.userService.TransferOwnership(ctx, orgID, actor, newOwnerID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &pb.TransferOwnershipResponse{
+		NewOwner: userToProto(newOwner),
+	}, nil
+}
+
 func (s *Service) ListInvitations(ctx context.Context, req *pb.ListInvitationsRequest) (*pb.ListInvitationsResponse, error) {
 	span, ctx := opentracing.StartSpanFromContext(ctx, "api.ListInvitations")
 	defer span.Finish()
@@ -255,7 +346,7 @@ func (s *Service) ListInvitations(ctx context.Context, req *pb.ListInvitationsRe
 
 	pbInvitations := make([]*pb.Invitation, len(invitations))
 	for i, inv := range invitations {
-		pbInvitations[i] = invitationToProto(&inv)
+		pbInvitations[i] = s.invitationToProto(&inv)
 	}
 
 	return &pb.ListInvitationsResponse{
@@ -288,7 +379,95 @@ func (s *Service) DeleteInvitation(ctx context.Context, req *pb.DeleteInvitation
 	return &emptypb.Empty{}, nil
 }
 
-func invitationToProto(inv *domain.Invitation) *pb.Invitation {
+func (s *Service) RevokeInvitation(ctx context.Context, req *pb.RevokeInvitationRequest) (*pb.RevokeInvitationResponse, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "api.RevokeInvitation")
+	defer span.Finish()
+
+	invitationID, err := domain.ParseID(req.Id)
+	if err != nil {
+		return nil, domain.ErrInvalidArgument
+	}
+
+	invitation, err := s.userService.RevokeInvitation(ctx, invitationID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &pb.RevokeInvitationResponse{Invitation: s.invitationToProto(invitation)}, nil
+}
+
+func (s *Service) RegenerateInvitation(ctx context.Context, req *pb.RegenerateInvitationRequest) (*pb.RegenerateInvitationResponse, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "api.RegenerateInvitation")
+	defer span.Finish()
+
+	invitationID, err := domain.ParseID(req.Id)
+	if err != nil {
+		return nil, domain.ErrInvalidArgument
+	}
+
+	actor, _ := interceptors.MembershipFromContext(ctx)
+	ttl := time.Duration(req.ExpiresInHours) * time.Hour
+
+	invitation, err := s.userService.RegenerateInvitation(ctx, invitationID, actor, ttl)
+	if err != nil {
+		return nil, err
+	}
+
+	return &pb.RegenerateInvitationResponse{Invitation: s.invitationToProto(invitation)}, nil
+}
+
+func (s *Service) ListInvitationUses(ctx context.Context, req *pb.ListInvitationUsesRequest) (*pb.ListInvitationUsesResponse, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "api.ListInvitationUses")
+	defer span.Finish()
+
+	invitationID, err := domain.ParseID(req.Id)
+	if err != nil {
+		return nil, domain.ErrInvalidArgument
+	}
+
+	page := int(req.Page)
+	if page < 1 {
+		page = 1
+	}
+
+	uses, total, err := s.userService.ListInvitationUses(ctx, invitationID, page, int(req.PageSize))
+	if err != nil {
+		return nil, err
+	}
+
+	pbUses := make([]*pb.InvitationUse, 0, len(uses))
+	for _, use := range uses {
+		pbUses = append(pbUses, &pb.InvitationUse{
+			UserId:    use.UserID.String(),
+			FirstName: use.FirstName,
+			LastName:  use.LastName,
+			UsedAt:    timestamppb.New(use.UsedAt),
+		})
+	}
+
+	return &pb.ListInvitationUsesResponse{
+		Uses:  pbUses,
+		Total: int32(total),
+		Page:  int32(page),
+	}, nil
+}
+
+func invitationStatusToProto(status domain.InvitationStatus) pb.InvitationStatus {
+	switch status {
+	case domain.InvitationStatusActive:
+		return pb.InvitationStatus_INVITATION_STATUS_ACTIVE
+	case domain.InvitationStatusExpired:
+		return pb.InvitationStatus_INVITATION_STATUS_EXPIRED
+	case domain.InvitationStatusExhausted:
+		return pb.InvitationStatus_INVITATION_STATUS_EXHAUSTED
+	case domain.InvitationStatusRevoked:
+		return pb.InvitationStatus_INVITATION_STATUS_REVOKED
+	default:
+		return pb.InvitationStatus_INVITATION_STATUS_UNSPECIFIED
+	}
+}
+
+func (s *Service) invitationToProto(inv *domain.Invitation) *pb.Invitation {
 	result := &pb.Invitation{
 		Id:             inv.ID.String(),
 		OrganizationId: inv.OrganizationID.String(),
@@ -296,9 +475,24 @@ func invitationToProto(inv *domain.Invitation) *pb.Invitation {
 		Role:           userRoleToProto(inv.Role),
 		ExpiresAt:      timestamppb.New(inv.ExpiresAt),
 		CreatedAt:      timestamppb.New(inv.CreatedAt),
+		CustomRoleName: inv.CustomRoleName,
+		MaxUses:        int32(inv.MaxUses),
+		UsesCount:      int32(inv.UsesCount),
+		Status:         invitationStatusToProto(inv.Status()),
+		// Telegram Mini App URL with invitation token
+		InvitationUrl: fmt.Sprintf("%s?startapp=invitation_%s", s.miniAppURL, inv.Token),
 	}
 	if inv.UsedAt != nil {
 		result.UsedAt = timestamppb.New(*inv.UsedAt)
 	}
+	if inv.RevokedAt != nil {
+		result.RevokedAt = timestamppb.New(*inv.RevokedAt)
+	}
+	if inv.CustomRoleID != nil {
+		result.CustomRoleId = inv.CustomRoleID.String()
+	}
+	if inv.CreatedBy != nil {
+		result.CreatedBy = inv.CreatedBy.String()
+	}
 	return result
 }
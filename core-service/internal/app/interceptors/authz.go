@@ -0,0 +1,243 @@
+package interceptors
+
+// Checks that the caller is an active member of the organization the request is scoped to
+// and has the role and permission required by the access policy. Must run after the auth interceptor.
+
+import (
+	"context"
+	"core-service/internal/domain"
+	"core-service/internal/logger"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/opentracing/opentracing-go"
+	"google.golang.org/grpc"
+)
+
+// OrgResolver returns the organization a request is scoped to.
+// ok is false when the request is not bound to any organization.
+type OrgResolver func(ctx context.Context, req any) (orgID domain.ID, ok bool, err error)
+
+// AccessRule describes who may call a method
+type AccessRule struct {
+	// Org resolves the organization of the request; nil means any authenticated user may call the method
+	Org OrgResolver
+	// Role is the minimal role the caller must have in the organization
+	Role domain.UserRole
+	// Permission is required in the organization in addition to the role; empty means none
+	Permission domain.Permission
+}
+
+// AccessPolicy maps fully-qualified method names to access rules.
+// Methods missing from the policy are denied.
+type AccessPolicy map[string]AccessRule
+
+// MembershipProvider looks up organization membership; returns domain.ErrNotFound for non-members
+type MembershipProvider interface {
+	GetOrganizationMember(ctx context.Context, userID, organizationID domain.ID) (*domain.OrganizationMember, error)
+}
+
+// Authenticated allows the method to any authenticated user
+func Authenticated() AccessRule {
+	return AccessRule{}
+}
+
+// Member requires an active membership in the resolved organization
+func Member(org OrgResolver) AccessRule {
+	return AccessRule{Org: org, Role: domain.UserRoleEmployee}
+}
+
+// Admin requires an active admin membership in the resolved organization
+func Admin(org OrgResolver) AccessRule {
+	return AccessRule{Org: org, Role: domain.UserRoleAdmin}
+}
+
+// Permitted requires an active membership with the permission in the resolved organization
+func Permitted(org OrgResolver, permission domain.Permission) AccessRule {
+	return AccessRule{Org: org, Role: domain.UserRoleEmployee, Permission: permission}
+}
+
+// OrganizationIDField resolves the organization from the organization_id request field
+func OrganizationIDField(_ context.Context, req any) (domain.ID, bool, error) {
+	r, ok := req.(interface{ GetOrganizationId() string })
+	if !ok {
+		return domain.ID{}, false, domain.NewInvalidArgumentError("request has no organization_id")
+	}
+	return parseOrgID(r.GetOrganizationId())
+}
+
+// IDField resolves the organization from the id request field, for RPCs addressing the organization itself
+func IDField(_ context.Context, req any) (domain.ID, bool, error) {
+	r, ok := req.(interface{ GetId() string })
+	if !ok {
+		return domain.ID{}, false, domain.NewInvalidArgumentError("request has no id")
+	}
+	return parseOrgID(r.GetId())
+}
+
+// ResourceOwner resolves the organization of the resource addressed by the id request field
+func ResourceOwner(lookup func(ctx context.Context, id domain.ID) (domain.ID, error)) OrgResolver {
+	return func(ctx context.Context, req any) (domain.ID, bool, error) {
+		r, ok := req.(interface{ GetId() string })
+		if !ok {
+			return domain.ID{}, false, domain.NewInvalidArgumentError("request has no id")
+		}
+		id, err := domain.ParseID(r.GetId())
+		if err != nil {
+			return domain.ID{}, false, domain.NewInvalidArgumentError("invalid id")
+		}
+		orgID, err := lookup(ctx, id)
+		if err != nil {
+			return domain.ID{}, false, err
+		}
+		return orgID, true, nil
+	}
+}
+
+// S3KeyOwner resolves the organization from object keys like "documents/{organization_id}/...".
+// Keys without an organization segment (shared templates) are not bound to any organization.
+func S3KeyOwner(_ context.Context, req any) (domain.ID, bool, error) {
+	r, ok := req.(interface{ GetS3Key() string })
+	if !ok {
+		return domain.ID{}, false, domain.NewInvalidArgumentError("request has no s3_key")
+	}
+	key := r.GetS3Key()
+	if strings.Contains(key, "..") {
+		return domain.ID{}, false, domain.NewInvalidArgumentError("invalid s3 key")
+	}
+	parts := strings.SplitN(key, "/", 3)
+	if len(parts) < 3 {
+		return domain.ID{}, false, nil
+	}
+	orgID, err := domain.ParseID(parts[1])
+	if err != nil {
+		return domain.ID{}, false, nil
+	}
+	return orgID, true, nil
+}
+
+func parseOrgID(value string) (domain.ID, bool, error) {
+	orgID, err := domain.ParseID(value)
+	if err != nil {
+		return domain.ID{}, false, domain.NewInvalidArgumentError("invalid organization id")
+	}
+	return orgID, true, nil
+}
+
+// Authorizer checks requests against the access policy
+type Authorizer struct {
+	policy  AccessPolicy
+	members MembershipProvider
+	skip    map[string]struct{}
+}
+
+// NewAuthorizer creates an authorizer; unprotected methods bypass the policy like they bypass auth
+func NewAuthorizer(policy AccessPolicy, members MembershipProvider, unprotected ...string) *Authorizer {
+	skip := make(map[string]struct{}, len(unprotected))
+	for _, m := range unprotected {
+		skip[m] = struct{}{}
+	}
+	return &Authorizer{policy: policy, members: members, skip: skip}
+}
+
+// authorize checks a single request message and returns the context enriched with the caller's membership
+func (a *Authorizer) authorize(ctx context.Context, method string, req any) (context.Context, error) {
+	rule, ok := a.policy[method]
+	if !ok {
+		logger.Errorf(ctx, "access denied to %s: method has no access rule", method)
+		return ctx, domain.NewForbiddenError("method is not allowed")
+	}
+	if rule.Org == nil {
+		return ctx, nil
+	}
+
+	userID, err := UserIDFromContext(ctx)
+	if err != nil {
+		return ctx, err
+	}
+
+	orgID, ok, err := rule.Org(ctx, req)
+	if err != nil {
+		return ctx, err
+	}
+	if !ok {
+		return ctx, nil
+	}
+
+	member, err := a.members.GetOrganizationMember(ctx, userID, orgID)
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			logger.Warnf(ctx, "access denied to %s: user %s is not a member of organization %s", method, userID, orgID)
+			return ctx, domain.NewForbiddenError("you are not a member of this organization")
+		}
+		return ctx, err
+	}
+	if !member.IsActive() {
+		logger.Warnf(ctx, "access denied to %s: membership of user %s in organization %s is %s", method, userID, orgID, member.Status)
+		return ctx, domain.NewForbiddenError("your membership in this organization is not active")
+	}
+	if !member.HasRole(rule.Role) {
+		logger.Warnf(ctx, "access denied to %s: user %s has role %s in organization %s, %s required", method, userID, member.Role, orgID, rule.Role)
+		return ctx, domain.NewForbiddenError("insufficient role in this organization")
+	}
+	if rule.Permission != "" && !member.HasPermission(rule.Permission) {
+		logger.Warnf(ctx, "access denied to %s: user %s lacks permission %s in organization %s", method, userID, rule.Permission, orgID)
+		return ctx, domain.NewForbiddenError(fmt.Sprintf("permission %s is required in this organization", rule.Permission))
+	}
+
+	return WithMembership(ctx, member), nil
+}
+
+// UnaryInterceptor checks unary requests against the policy
+func (a *Authorizer) UnaryInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		if _, ok := a.skip[info.FullMethod]; ok {
+			return handler(ctx, req)
+		}
+
+		span, ctx := opentracing.StartSpanFromContext(ctx, "authz "+info.FullMethod)
+		ctx, err := a.authorize(ctx, info.FullMethod, req)
+		span.Finish()
+		if err != nil {
+			return nil, err
+		}
+
+		return handler(ctx, req)
+	}
+}
+
+// StreamInterceptor checks every message received from a stream against the policy
+func (a *Authorizer) StreamInterceptor() grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if _, ok := a.skip[info.FullMethod]; ok {
+			return handler(srv, ss)
+		}
+
+		rule, ok := a.policy[info.FullMethod]
+		if !ok {
+			logger.Errorf(ss.Context(), "access denied to %s: method has no access rule", info.FullMethod)
+			return domain.NewForbiddenError("method is not allowed")
+		}
+		if rule.Org == nil {
+			return handler(srv, ss)
+		}
+
+		return handler(srv, &authorizedServerStream{ServerStream: ss, authorizer: a, method: info.FullMethod})
+	}
+}
+
+// authorizedServerStream authorizes each incoming message before the handler sees it
+type authorizedServerStream struct {
+	grpc.ServerStream
+	authorizer *Authorizer
+	method     string
+}
+
+func (s *authorizedServerStream) RecvMsg(m any) error {
+	if err := s.ServerStream.RecvMsg(m); err != nil {
+		return err
+	}
+	_, err := s.authorizer.authorize(s.Context(), s.method, m)
+	return err
+}
@@ -0,0 +1,126 @@
+package interceptors
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"testing"
+
+	"core-service/internal/domain"
+	"core-service/internal/logger"
+	pb "core-service/pkg/core"
+
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestMain(m *testing.M) {
+	logger.Init()
+	os.Exit(m.Run())
+}
+
+const streamMethod = "/test.Service/Stream"
+
+type fakeMembers map[domain.ID]*domain.OrganizationMember
+
+func (f fakeMembers) GetOrganizationMember(_ context.Context, userID, organizationID domain.ID) (*domain.OrganizationMember, error) {
+	member, ok := f[userID]
+	if !ok || member.OrganizationID != organizationID {
+		return nil, domain.ErrNotFound
+	}
+	return member, nil
+}
+
+// fakeServerStream отдает заранее заданные сообщения и затем io.EOF
+type fakeServerStream struct {
+	grpc.ServerStream
+	ctx      context.Context
+	messages []*pb.ListDocumentsRequest
+}
+
+func (s *fakeServerStream) Context() context.Context {
+	return s.ctx
+}
+
+func (s *fakeServerStream) RecvMsg(m any) error {
+	if len(s.messages) == 0 {
+		return io.EOF
+	}
+	proto.Merge(m.(*pb.ListDocumentsRequest), s.messages[0])
+	s.messages = s.messages[1:]
+	return nil
+}
+
+// TestStreamAuthorizerChecksEveryMessage - организация проверяется в каждом сообщении стрима,
+// поэтому переключиться на чужую организацию посреди стрима нельзя
+func TestStreamAuthorizerChecksEveryMessage(t *testing.T) {
+	orgA, orgB := domain.NewID(), domain.NewID()
+	userID := domain.NewID()
+	members := fakeMembers{userID: {OrganizationID: orgA, UserID: userID, Role: domain.UserRoleEmployee, Status: domain.UserStatusActive}}
+
+	authorizer := NewAuthorizer(AccessPolicy{streamMethod: Member(OrganizationIDField)}, members)
+
+	stream := &fakeServerStream{
+		ctx: WithUserID(context.Background(), userID),
+		messages: []*pb.ListDocumentsRequest{
+			{OrganizationId: orgA.String()},
+			{OrganizationId: orgB.String()},
+		},
+	}
+
+	var received int
+	handler := func(_ any, ss grpc.ServerStream) error {
+		for {
+			if err := ss.RecvMsg(&pb.ListDocumentsRequest{}); err != nil {
+				return err
+			}
+			received++
+		}
+	}
+
+	err := authorizer.StreamInterceptor()(nil, stream, &grpc.StreamServerInfo{FullMethod: streamMethod}, handler)
+	if !errors.Is(err, domain.ErrForbidden) {
+		t.Fatalf("expected forbidden for foreign organization, got %v", err)
+	}
+	if received != 1 {
+		t.Fatalf("expected only the first message to reach the handler, got %d", received)
+	}
+}
+
+func TestStreamAuthorizerDeniesUnknownMethod(t *testing.T) {
+	authorizer := NewAuthorizer(AccessPolicy{}, fakeMembers{})
+	stream := &fakeServerStream{ctx: WithUserID(context.Background(), domain.NewID())}
+
+	called := false
+	handler := func(any, grpc.ServerStream) error {
+		called = true
+		return nil
+	}
+
+	err := authorizer.StreamInterceptor()(nil, stream, &grpc.StreamServerInfo{FullMethod: streamMethod}, handler)
+	if !errors.Is(err, domain.ErrForbidden) || called {
+		t.Fatalf("expected forbidden before the handler runs, got %v (handler called: %v)", err, called)
+	}
+}
+
+func TestUnaryAuthorizerStoresMembership(t *testing.T) {
+	orgID, userID := domain.NewID(), domain.NewID()
+	members := fakeMembers{userID: {OrganizationID: orgID, UserID: userID, Role: domain.UserRoleAdmin, Status: domain.UserStatusActive}}
+	authorizer := NewAuthorizer(AccessPolicy{streamMethod: Admin(OrganizationIDField)}, members)
+
+	var member *domain.OrganizationMember
+	handler := func(ctx context.Context, _ any) (any, error) {
+		member, _ = MembershipFromContext(ctx)
+		return nil, nil
+	}
+
+	ctx := WithUserID(context.Background(), userID)
+	_, err := authorizer.UnaryInterceptor()(ctx, &pb.ListDocumentsRequest{OrganizationId: orgID.String()}, &grpc.UnaryServerInfo{FullMethod: streamMethod}, handler)
+	if err != nil {
+		t.Fatalf("expected access, got %v", err)
+	}
+	if member == nil || member.Role != domain.UserRoleAdmin {
+		t.Fatalf("expected admin membership in context, got %+v", member)
+	}
+}
@@ -23,6 +23,7 @@ func (w *wrappedServerStream) Context() context.Context {
 const (
 	userIDContextKey      contextKey = "auth.user_id"
 	accessTokenContextKey contextKey = "auth.access_token"
+	membershipContextKey  contextKey = "authz.membership"
 )
 
 // WithUserID stores domain.ID in context for downstream handlers
@@ -60,3 +61,14 @@ func AccessTokenFromContext(ctx context.Context) (string, error) {
 	}
 	return token, nil
 }
+
+// WithMembership stores the caller's membership in the organization of the request
+func WithMembership(ctx context.Context, member *domain.OrganizationMember) context.Context {
+	return context.WithValue(ctx, membershipContextKey, member)
+}
+
+// MembershipFromContext extracts the membership checked by the authorization interceptor
+func MembershipFromContext(ctx context.Context) (*domain.OrganizationMember, bool) {
+	member, ok := ctx.Value(membershipContextKey).(*domain.OrganizationMember)
+	return member, ok
+}
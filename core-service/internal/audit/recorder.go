@@ -0,0 +1,50 @@
+// Package audit is the client side of the audit log: services describe what a user did
+// and the recorder publishes it to the audit queue, from which core-service stores it.
+package audit
+
+import (
+	"context"
+	"core-service/internal/domain"
+	"core-service/internal/logger"
+)
+
+type publisher interface {
+	PublishMessage(ctx context.Context, message interface{}) error
+}
+
+// ActorResolver returns the user on whose behalf the current request runs
+type ActorResolver func(ctx context.Context) (domain.ID, error)
+
+// Recorder publishes audit events. A nil Recorder records nothing, so services work with auditing disabled.
+type Recorder struct {
+	publisher publisher
+	source    string
+	actor     ActorResolver
+}
+
+// NewRecorder creates a recorder for the given source service; actor fills in events that do not name one
+func NewRecorder(publisher publisher, source string, actor ActorResolver) *Recorder {
+	return &Recorder{
+		publisher: publisher,
+		source:    source,
+		actor:     actor,
+	}
+}
+
+// Record publishes an audit event. Failures are logged and never fail the audited action.
+func (r *Recorder) Record(ctx context.Context, event domain.AuditEvent) {
+	if r == nil {
+		return
+	}
+
+	event.Source = r.source
+	if event.ActorUserID == nil && r.actor != nil {
+		if actorID, err := r.actor(ctx); err == nil {
+			event.ActorUserID = &actorID
+		}
+	}
+
+	if err := r.publisher.PublishMessage(ctx, event); err != nil {
+		logger.Errorf(ctx, "audit: failed to publish %s event for %s %s: %v", event.Action, event.ResourceType, event.ResourceID, err)
+	}
+}
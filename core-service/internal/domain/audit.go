@@ -0,0 +1,113 @@
+package domain
+
+import (
+	"strings"
+	"time"
+)
+
+// AuditAction - действие пользователя, которое фиксируется в журнале аудита
+type AuditAction string
+
+const (
+	AuditActionInvitationCreated             AuditAction = "invitation.created"              // Создано приглашение
+	AuditActionInvitationAccepted            AuditAction = "invitation.accepted"             // Пользователь вступил по приглашению
+	AuditActionInvitationDeleted             AuditAction = "invitation.deleted"              // Приглашение удалено
+	AuditActionInvitationRevoked             AuditAction = "invitation.revoked"              // Приглашение отозвано
+	AuditActionInvitationRegenerated         AuditAction = "invitation.regenerated"          // Приглашение перевыпущено
+	AuditActionMemberRoleChanged             AuditAction = "member.role_changed"             // Изменена роль участника
+	AuditActionMemberDeactivated             AuditAction = "member.deactivated"              // Участник деактивирован
+	AuditActionMemberLeft                    AuditAction = "member.left"                     // Участник покинул организацию
+	AuditActionOwnershipTransferred          AuditAction = "ownership.transferred"           // Администрирование передано другому участнику
+	AuditActionRoleCreated                   AuditAction = "role.created"                    // Создана роль организации
+	AuditActionRoleUpdated                   AuditAction = "role.updated"                    // Изменена роль организации
+	AuditActionRoleDeleted                   AuditAction = "role.deleted"                    // Удалена роль организации
+	AuditActionDocumentRegistered            AuditAction = "document.registered"             // Загружен документ
+	AuditActionDocumentDeleted               AuditAction = "document.deleted"                // Удален документ
+	AuditActionTemplateCreated               AuditAction = "template.created"                // Создан шаблон договора
+	AuditActionTemplateUpdated               AuditAction = "template.updated"                // Изменен шаблон договора
+	AuditActionTemplateDeleted               AuditAction = "template.deleted"                // Удален шаблон договора
+	AuditActionContractRegistered            AuditAction = "contract.registered"             // Сгенерирован договор
+	AuditActionContractDeleted               AuditAction = "contract.deleted"                // Удален договор
+	AuditActionOrganizationDeletionScheduled AuditAction = "organization.deletion_scheduled" // Организация удалена, данные будут стерты после льготного периода
+	AuditActionOrganizationDeletionCancelled AuditAction = "organization.deletion_cancelled" // Организация восстановлена в льготный период
+	AuditActionOrganizationErased            AuditAction = "organization.erased"             // Данные организации безвозвратно удалены
+	AuditActionOrganizationExportRequested   AuditAction = "organization.export_requested"   // Запрошена выгрузка всех данных организации
+	AuditActionOrganizationExported          AuditAction = "organization.exported"           // Архив с данными организации готов
+	AuditActionAccountDeletionScheduled      AuditAction = "account.deletion_scheduled"      // Пользователь запросил удаление учетной записи
+	AuditActionAccountDeletionCancelled      AuditAction = "account.deletion_cancelled"      // Пользователь отменил удаление учетной записи
+	AuditActionAccountErased                 AuditAction = "account.erased"                  // Учетная запись безвозвратно удалена
+	AuditActionAgentToolExecuted             AuditAction = "agent.tool_executed"             // Агент выполнил изменяющий инструмент от имени пользователя
+	AuditActionAgentToolDenied               AuditAction = "agent.tool_denied"               // Агенту отказано в инструменте из-за прав пользователя
+)
+
+// Типы ресурсов, над которыми выполняются действия
+const (
+	AuditResourceInvitation   = "invitation"
+	AuditResourceMember       = "member"
+	AuditResourceRole         = "role"
+	AuditResourceDocument     = "document"
+	AuditResourceTemplate     = "template"
+	AuditResourceContract     = "contract"
+	AuditResourceTool         = "tool"
+	AuditResourceOrganization = "organization"
+	AuditResourceUser         = "user"
+	AuditResourceExport       = "export"
+)
+
+// AuditEvent - запись журнала аудита. Журнал только дополняется: записи не изменяются и не удаляются.
+// События публикуются сервисами в очередь аудита и сохраняются core-service.
+type AuditEvent struct {
+	ID ID `db:"id" json:"-"`
+	// EventID - ключ дедупликации: повторная доставка события с тем же ID не создает новую запись
+	EventID        string      `db:"event_id" json:"event_id"`
+	Action         AuditAction `db:"action" json:"action"`
+	Source         string      `db:"source" json:"source"`
+	OrganizationID *ID         `db:"organization_id" json:"organization_id,omitempty"`
+	// ActorUserID - пользователь, выполнивший действие (или от чьего имени действовал агент)
+	ActorUserID  *ID            `db:"actor_user_id" json:"actor_user_id,omitempty"`
+	ResourceType string         `db:"resource_type" json:"resource_type"`
+	ResourceID   string         `db:"resource_id" json:"resource_id"`
+	Metadata     map[string]any `db:"metadata" json:"metadata,omitempty"`
+	OccurredAt   time.Time      `db:"occurred_at" json:"occurred_at"`
+	CreatedAt    time.Time      `db:"created_at" json:"-"`
+}
+
+// NewAuditEvent создает событие аудита с уникальным ключом дедупликации
+func NewAuditEvent(action AuditAction, organizationID *ID, resourceType, resourceID string, metadata map[string]any) AuditEvent {
+	return AuditEvent{
+		EventID:        string(action) + ":" + NewID().String(),
+		Action:         action,
+		OrganizationID: organizationID,
+		ResourceType:   resourceType,
+		ResourceID:     resourceID,
+		Metadata:       metadata,
+		OccurredAt:     time.Now().UTC(),
+	}
+}
+
+// Validate проверяет обязательные поля события из очереди
+func (e *AuditEvent) Validate() error {
+	if strings.TrimSpace(e.EventID) == "" {
+		return NewInvalidArgumentError("event_id is required")
+	}
+	if strings.TrimSpace(string(e.Action)) == "" {
+		return NewInvalidArgumentError("action is required")
+	}
+	if strings.TrimSpace(e.Source) == "" {
+		return NewInvalidArgumentError("source is required")
+	}
+	if e.OccurredAt.IsZero() {
+		return NewInvalidArgumentError("occurred_at is required")
+	}
+	return nil
+}
+
+// AuditEventFilter - условия выборки журнала аудита
+type AuditEventFilter struct {
+	OrganizationID *ID
+	ActorUserID    *ID
+	// Action - точное действие или категория с точкой на конце, например "document."
+	Action string
+	From   *time.Time
+	To     *time.Time
+}
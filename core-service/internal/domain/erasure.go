@@ -0,0 +1,210 @@
+package domain
+
+import "time"
+
+// ErasureSubject - что удаляется безвозвратно: организация со всеми данными или учетная запись пользователя
+type ErasureSubject string
+
+const (
+	ErasureSubjectOrganization ErasureSubject = "organization"
+	ErasureSubjectUser         ErasureSubject = "user"
+)
+
+// ErasureStatus - состояние удаления данных
+type ErasureStatus string
+
+const (
+	ErasureStatusScheduled  ErasureStatus = "scheduled"   // Ждет окончания льготного периода, можно отменить
+	ErasureStatusInProgress ErasureStatus = "in_progress" // Данные удаляются
+	ErasureStatusCompleted  ErasureStatus = "completed"   // Все данные удалены
+	ErasureStatusFailed     ErasureStatus = "failed"      // Шаг завершился ошибкой, удаление будет повторено
+	ErasureStatusCancelled  ErasureStatus = "cancelled"   // Отменено в льготный период
+)
+
+// ErasureStep - хранилище, из которого удаляются данные. Шаги выполняют разные сервисы
+// и сообщают результат через очередь; база core-service очищается последней
+type ErasureStep string
+
+const (
+	ErasureStepStorage     ErasureStep = "storage"      // Файлы документов и договоров в S3 (core-service)
+	ErasureStepSearchIndex ErasureStep = "search_index" // Чанки документов в OpenSearch (docs-processor)
+	ErasureStepLLMData     ErasureStep = "llm_data"     // Чаты, память, расписания и расход токенов (llm-service)
+	ErasureStepDatabase    ErasureStep = "database"     // Записи в базе core-service
+)
+
+// ErasureStepStatus - состояние шага удаления
+type ErasureStepStatus string
+
+const (
+	ErasureStepPending   ErasureStepStatus = "pending"
+	ErasureStepCompleted ErasureStepStatus = "completed"
+	ErasureStepFailed    ErasureStepStatus = "failed"
+)
+
+// ErasureStepReport - итог шага для отчета об удалении
+type ErasureStepReport struct {
+	Status       ErasureStepStatus `json:"status"`
+	ItemsDeleted int               `json:"items_deleted"`
+	Error        string            `json:"error,omitempty"`
+	FinishedAt   *time.Time        `json:"finished_at,omitempty"`
+}
+
+// DataErasure - заявка на безвозвратное удаление данных и отчет о ее выполнении.
+// Запись не ссылается на удаляемые данные, поэтому отчет остается после удаления
+type DataErasure struct {
+	ID          ID             `db:"id"`
+	SubjectType ErasureSubject `db:"subject_type"`
+	SubjectID   ID             `db:"subject_id"`
+	// SubjectName - название организации или имя пользователя на момент заявки, для отчета
+	SubjectName string                            `db:"subject_name"`
+	RequestedBy *ID                               `db:"requested_by"`
+	Status      ErasureStatus                     `db:"status"`
+	PurgeAfter  time.Time                         `db:"purge_after"`
+	Steps       map[ErasureStep]ErasureStepReport `db:"steps"`
+	CreatedAt   time.Time                         `db:"created_at"`
+	StartedAt   *time.Time                        `db:"started_at"`
+	CompletedAt *time.Time                        `db:"completed_at"`
+	UpdatedAt   time.Time                         `db:"updated_at"`
+}
+
+// NewDataErasure создает заявку на удаление, которая выполнится после льготного периода
+func NewDataErasure(subjectType ErasureSubject, subjectID ID, subjectName string, requestedBy *ID, gracePeriod time.Duration) DataErasure {
+	now := time.Now()
+	steps := make(map[ErasureStep]ErasureStepReport)
+	for _, step := range ErasureSteps(subjectType) {
+		steps[step] = ErasureStepReport{Status: ErasureStepPending}
+	}
+	return DataErasure{
+		ID:          NewID(),
+		SubjectType: subjectType,
+		SubjectID:   subjectID,
+		SubjectName: subjectName,
+		RequestedBy: requestedBy,
+		Status:      ErasureStatusScheduled,
+		PurgeAfter:  now.Add(gracePeriod),
+		Steps:       steps,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+}
+
+// ErasureSteps возвращает шаги удаления: у пользователя нет файлов и документов в поиске
+func ErasureSteps(subjectType ErasureSubject) []ErasureStep {
+	if subjectType == ErasureSubjectUser {
+		return []ErasureStep{ErasureStepLLMData, ErasureStepDatabase}
+	}
+	return []ErasureStep{ErasureStepStorage, ErasureStepSearchIndex, ErasureStepLLMData, ErasureStepDatabase}
+}
+
+// CanCancel проверяет, что удаление еще не началось
+func (e *DataErasure) CanCancel() bool {
+	return e.Status == ErasureStatusScheduled
+}
+
+// PendingSteps возвращает шаги, которые еще нужно выполнить (незавершенные и завершившиеся ошибкой)
+func (e *DataErasure) PendingSteps() []ErasureStep {
+	var pending []ErasureStep
+	for _, step := range ErasureSteps(e.SubjectType) {
+		if e.Steps[step].Status != ErasureStepCompleted {
+			pending = append(pending, step)
+		}
+	}
+	return pending
+}
+
+// ExternalStepsCompleted проверяет, что все хранилища, кроме базы core-service, очищены
+func (e *DataErasure) ExternalStepsCompleted() bool {
+	for _, step := range e.PendingSteps() {
+		if step != ErasureStepDatabase {
+			return false
+		}
+	}
+	return true
+}
+
+// HasFailedSteps проверяет, завершился ли какой-либо шаг ошибкой
+func (e *DataErasure) HasFailedSteps() bool {
+	for _, report := range e.Steps {
+		if report.Status == ErasureStepFailed {
+			return true
+		}
+	}
+	return false
+}
+
+// ItemsDeleted возвращает общее число удаленных объектов по всем шагам
+func (e *DataErasure) ItemsDeleted() int {
+	total := 0
+	for _, report := range e.Steps {
+		total += report.ItemsDeleted
+	}
+	return total
+}
+
+// ErasureRequest - сообщение сервисам-владельцам данных: удалить все данные организации или пользователя
+type ErasureRequest struct {
+	ErasureID   ID             `json:"erasure_id"`
+	SubjectType ErasureSubject `json:"subject_type"`
+	SubjectID   ID             `json:"subject_id"`
+	RequestedAt time.Time      `json:"requested_at"`
+}
+
+// ErasureStepResult - ответ сервиса о выполнении шага удаления
+type ErasureStepResult struct {
+	ErasureID    ID                `json:"erasure_id"`
+	Step         ErasureStep       `json:"step"`
+	Source       string            `json:"source"`
+	Status       ErasureStepStatus `json:"status"`
+	ItemsDeleted int               `json:"items_deleted"`
+	Error        string            `json:"error,omitempty"`
+	FinishedAt   time.Time         `json:"finished_at"`
+}
+
+// Validate проверяет обязательные поля ответа из очереди
+func (r *ErasureStepResult) Validate() error {
+	if r.ErasureID == (ID{}) {
+		return NewInvalidArgumentError("erasure_id is required")
+	}
+	switch r.Step {
+	case ErasureStepStorage, ErasureStepSearchIndex, ErasureStepLLMData, ErasureStepDatabase:
+	default:
+		return NewInvalidArgumentError("unknown erasure step: " + string(r.Step))
+	}
+	if r.Status != ErasureStepCompleted && r.Status != ErasureStepFailed {
+		return NewInvalidArgumentError("erasure step result must be completed or failed")
+	}
+	return nil
+}
+
+// Report возвращает итог шага для отчета
+func (r *ErasureStepResult) Report() ErasureStepReport {
+	finishedAt := r.FinishedAt
+	return ErasureStepReport{
+		Status:       r.Status,
+		ItemsDeleted: r.ItemsDeleted,
+		Error:        r.Error,
+		FinishedAt:   &finishedAt,
+	}
+}
+
+// OrganizationPurgeJob - задача docs-processor удалить чанки организации из всех индексов OpenSearch.
+// Результат docs-processor присылает как ErasureStepResult
+type OrganizationPurgeJob struct {
+	JobType        string `json:"job_type"`
+	OrganizationID ID     `json:"organization_id"`
+	ErasureID      ID     `json:"erasure_id"`
+	RetryCount     int    `json:"retry_count"`
+	MaxRetries     int    `json:"max_retries"`
+	CreatedAt      string `json:"created_at"`
+}
+
+func NewOrganizationPurgeJob(organizationID, erasureID ID) *OrganizationPurgeJob {
+	return &OrganizationPurgeJob{
+		JobType:        "organization_purge",
+		OrganizationID: organizationID,
+		ErasureID:      erasureID,
+		RetryCount:     0,
+		MaxRetries:     3,
+		CreatedAt:      time.Now().Format(time.RFC3339),
+	}
+}
@@ -0,0 +1,149 @@
+package domain
+
+import (
+	"fmt"
+	"path"
+	"strings"
+	"time"
+)
+
+// ExportStatus - состояние выгрузки данных организации
+type ExportStatus string
+
+const (
+	ExportStatusPending    ExportStatus = "pending"     // Ждет данные ассистента от llm-service
+	ExportStatusInProgress ExportStatus = "in_progress" // Собирается архив
+	ExportStatusCompleted  ExportStatus = "completed"   // Архив готов к скачиванию
+	ExportStatusFailed     ExportStatus = "failed"      // Выгрузка не удалась, ее можно запросить снова
+	ExportStatusExpired    ExportStatus = "expired"     // Архив удален по истечении срока хранения
+)
+
+// ExportStage - этап сборки архива, по нему отображается прогресс
+type ExportStage string
+
+const (
+	ExportStageAssistantData ExportStage = "assistant_data" // Выгрузка чатов и памяти в llm-service
+	ExportStageDatabase      ExportStage = "database"       // Профиль, участники, заметки и описи файлов
+	ExportStageFiles         ExportStage = "files"          // Оригиналы документов и договоров
+	ExportStageUpload        ExportStage = "upload"         // Загрузка архива в S3
+	ExportStageDone          ExportStage = "done"
+)
+
+// OrganizationExport - выгрузка всех данных организации в ZIP-архив с JSON-описями
+type OrganizationExport struct {
+	ID             ID `db:"id"`
+	OrganizationID ID `db:"organization_id"`
+	// RequestedBy - кому отправить ссылку на архив; пустой, если учетная запись удалена
+	RequestedBy *ID          `db:"requested_by"`
+	Status      ExportStatus `db:"status"`
+	Stage       ExportStage  `db:"stage"`
+	// ProcessedItems и TotalItems - прогресс текущего этапа
+	ProcessedItems int        `db:"processed_items"`
+	TotalItems     int        `db:"total_items"`
+	S3Key          *string    `db:"s3_key"`
+	FileSize       int64      `db:"file_size"`
+	ErrorMessage   *string    `db:"error_message"`
+	CreatedAt      time.Time  `db:"created_at"`
+	CompletedAt    *time.Time `db:"completed_at"`
+	ExpiresAt      *time.Time `db:"expires_at"`
+	UpdatedAt      time.Time  `db:"updated_at"`
+}
+
+// NewOrganizationExport создает выгрузку, которая начинается с запроса данных ассистента
+func NewOrganizationExport(organizationID, requestedBy ID) OrganizationExport {
+	now := time.Now()
+	return OrganizationExport{
+		ID:             NewID(),
+		OrganizationID: organizationID,
+		RequestedBy:    &requestedBy,
+		Status:         ExportStatusPending,
+		Stage:          ExportStageAssistantData,
+		CreatedAt:      now,
+		UpdatedAt:      now,
+	}
+}
+
+// IsActive проверяет, что выгрузка еще выполняется
+func (e *OrganizationExport) IsActive() bool {
+	return e.Status == ExportStatusPending || e.Status == ExportStatusInProgress
+}
+
+// IsDownloadable проверяет, что архив готов и еще не удален
+func (e *OrganizationExport) IsDownloadable(now time.Time) bool {
+	return e.Status == ExportStatusCompleted && e.S3Key != nil && (e.ExpiresAt == nil || now.Before(*e.ExpiresAt))
+}
+
+// ProgressPercent оценивает общий прогресс выгрузки по этапу и прогрессу внутри него
+func (e *OrganizationExport) ProgressPercent() int {
+	switch e.Status {
+	case ExportStatusCompleted, ExportStatusExpired:
+		return 100
+	case ExportStatusPending:
+		return 0
+	}
+
+	// Доли этапов: основное время уходит на скачивание файлов
+	start, size := 0, 0
+	switch e.Stage {
+	case ExportStageDatabase:
+		start, size = 5, 10
+	case ExportStageFiles:
+		start, size = 15, 75
+	case ExportStageUpload:
+		start, size = 90, 10
+	case ExportStageDone:
+		return 100
+	}
+	if e.TotalItems <= 0 {
+		return start
+	}
+	return start + size*min(e.ProcessedItems, e.TotalItems)/e.TotalItems
+}
+
+// ArchiveKey возвращает ключ архива в S3. Промежуточные файлы llm-service лежат под тем же префиксом
+func (e *OrganizationExport) ArchiveKey() string {
+	return fmt.Sprintf("exports/%s/%s.zip", e.OrganizationID, e.ID)
+}
+
+// ExportRequest - запрос к llm-service выгрузить чаты и память организации в S3
+type ExportRequest struct {
+	ExportID       ID        `json:"export_id"`
+	OrganizationID ID        `json:"organization_id"`
+	RequestedAt    time.Time `json:"requested_at"`
+}
+
+// ExportFile - файл, который llm-service положил в S3 для включения в архив
+type ExportFile struct {
+	// Name - путь файла внутри архива
+	Name  string `json:"name"`
+	S3Key string `json:"s3_key"`
+	Items int    `json:"items"`
+}
+
+// ExportPartResult - ответ llm-service о выгрузке его данных
+type ExportPartResult struct {
+	ExportID ID           `json:"export_id"`
+	Source   string       `json:"source"`
+	Status   ExportStatus `json:"status"`
+	Files    []ExportFile `json:"files"`
+	Error    string       `json:"error,omitempty"`
+}
+
+// Validate проверяет обязательные поля ответа из очереди
+func (r *ExportPartResult) Validate() error {
+	if r.ExportID == (ID{}) {
+		return NewInvalidArgumentError("export_id is required")
+	}
+	if r.Status != ExportStatusCompleted && r.Status != ExportStatusFailed {
+		return NewInvalidArgumentError("export part result must be completed or failed")
+	}
+	for _, file := range r.Files {
+		if file.Name == "" || file.S3Key == "" {
+			return NewInvalidArgumentError("export file name and s3_key are required")
+		}
+		if path.IsAbs(file.Name) || path.Clean(file.Name) != file.Name || strings.HasPrefix(file.Name, "..") {
+			return NewInvalidArgumentError("export file name must be a relative path: " + file.Name)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,94 @@
+package domain
+
+import (
+	"slices"
+	"time"
+)
+
+// NotificationEventType определяет тип доменного события, о котором уведомляется пользователь
+type NotificationEventType string
+
+const (
+	NotificationEventDocumentIndexed    NotificationEventType = "document.indexed"    // Документ проиндексирован
+	NotificationEventDocumentFailed     NotificationEventType = "document.failed"     // Ошибка обработки документа
+	NotificationEventScheduledRun       NotificationEventType = "agent.scheduled_run" // Результат задачи агента по расписанию
+	NotificationEventInvitationAccepted NotificationEventType = "invitation.accepted" // Приглашение принято
+	NotificationEventDataErased         NotificationEventType = "data.erased"         // Данные организации безвозвратно удалены
+	NotificationEventExportFinished     NotificationEventType = "export.finished"     // Выгрузка данных организации готова или не удалась
+)
+
+// IsValid проверяет, известен ли тип события
+func (t NotificationEventType) IsValid() bool {
+	switch t {
+	case NotificationEventDocumentIndexed,
+		NotificationEventDocumentFailed,
+		NotificationEventScheduledRun,
+		NotificationEventInvitationAccepted,
+		NotificationEventDataErased,
+		NotificationEventExportFinished:
+		return true
+	}
+	return false
+}
+
+// NotificationEvent представляет доменное событие из очереди уведомлений.
+// Публикуется core-service, docs-processor и llm-service.
+type NotificationEvent struct {
+	// EventID - ключ дедупликации: повторная доставка события с тем же ID не отправляется
+	EventID        string                `json:"event_id"`
+	Type           NotificationEventType `json:"type"`
+	Source         string                `json:"source"`
+	OrganizationID *ID                   `json:"organization_id,omitempty"`
+	// UserID - адресат; если не задан, уведомляются администраторы организации
+	UserID *ID `json:"user_id,omitempty"`
+	// ActorUserID - инициатор события, ему уведомление не отправляется
+	ActorUserID *ID       `json:"actor_user_id,omitempty"`
+	Title       string    `json:"title"`
+	Text        string    `json:"text"`
+	OccurredAt  time.Time `json:"occurred_at"`
+}
+
+// NotificationPreferences представляет настройки уведомлений пользователя
+type NotificationPreferences struct {
+	UserID          ID                      `db:"user_id"`
+	TelegramEnabled bool                    `db:"telegram_enabled"`
+	MutedEvents     []NotificationEventType `db:"muted_events"`
+	UpdatedAt       time.Time               `db:"updated_at"`
+}
+
+// DefaultNotificationPreferences возвращает настройки по умолчанию: все уведомления включены
+func DefaultNotificationPreferences(userID ID) NotificationPreferences {
+	return NotificationPreferences{
+		UserID:          userID,
+		TelegramEnabled: true,
+		MutedEvents:     []NotificationEventType{},
+		UpdatedAt:       time.Now(),
+	}
+}
+
+// Allows проверяет, хочет ли пользователь получать уведомления о событии
+func (p *NotificationPreferences) Allows(eventType NotificationEventType) bool {
+	return p.TelegramEnabled && !slices.Contains(p.MutedEvents, eventType)
+}
+
+// NotificationDeliveryStatus определяет результат доставки уведомления
+type NotificationDeliveryStatus string
+
+const (
+	NotificationDeliveryPending     NotificationDeliveryStatus = "pending"      // Взято в отправку
+	NotificationDeliverySent        NotificationDeliveryStatus = "sent"         // Отправлено
+	NotificationDeliveryMuted       NotificationDeliveryStatus = "muted"        // Отключено настройками пользователя
+	NotificationDeliveryRateLimited NotificationDeliveryStatus = "rate_limited" // Превышен лимит частоты
+	NotificationDeliveryNoChannel   NotificationDeliveryStatus = "no_channel"   // У пользователя нет Telegram
+	NotificationDeliveryFailed      NotificationDeliveryStatus = "failed"       // Ошибка отправки
+)
+
+// NotificationDelivery представляет запись журнала доставки уведомлений
+type NotificationDelivery struct {
+	EventID   string                     `db:"event_id"`
+	UserID    ID                         `db:"user_id"`
+	EventType NotificationEventType      `db:"event_type"`
+	Status    NotificationDeliveryStatus `db:"status"`
+	Error     string                     `db:"error"`
+	CreatedAt time.Time                  `db:"created_at"`
+}
@@ -0,0 +1,391 @@
+package domain
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"regexp"
+	"strings"
+)
+
+// OrganizationProfileSchemaVersion - текущая версия схемы анкеты организации.
+// Увеличивается при несовместимых изменениях полей
+const OrganizationProfileSchemaVersion = 1
+
+// ProfileSchemaVersionKey - служебное поле анкеты с версией схемы, по которой она проверена
+const ProfileSchemaVersionKey = "schema_version"
+
+// ProfileFieldType - тип значения поля анкеты
+type ProfileFieldType string
+
+const (
+	ProfileFieldString  ProfileFieldType = "string"
+	ProfileFieldInteger ProfileFieldType = "integer"
+	ProfileFieldNumber  ProfileFieldType = "number"
+	ProfileFieldEnum    ProfileFieldType = "enum"
+)
+
+// Ключи полей анкеты, на которые опираются проверки и агенты
+const (
+	ProfileFieldLegalForm      = "legal_form"
+	ProfileFieldINN            = "inn"
+	ProfileFieldKPP            = "kpp"
+	ProfileFieldOGRN           = "ogrn"
+	ProfileFieldOKVED          = "okved"
+	ProfileFieldTaxRegime      = "tax_regime"
+	ProfileFieldHeadcount      = "headcount"
+	ProfileFieldAnnualTurnover = "annual_turnover"
+	ProfileFieldFoundedYear    = "founded_year"
+)
+
+// ProfileCheck - дополнительная проверка значения поля, которую форма может повторить на клиенте
+type ProfileCheck string
+
+const (
+	ProfileCheckINN  ProfileCheck = "inn_checksum"
+	ProfileCheckOGRN ProfileCheck = "ogrn_checksum"
+)
+
+var profileChecks = map[ProfileCheck]func(string) error{
+	ProfileCheckINN:  ValidateINN,
+	ProfileCheckOGRN: ValidateOGRN,
+}
+
+// legalFormIndividual - индивидуальный предприниматель: ИНН из 12 цифр и ОГРНИП
+const legalFormIndividual = "ip"
+
+// ProfileFieldOption - допустимое значение поля-перечисления
+type ProfileFieldOption struct {
+	Value string
+	Title string
+}
+
+// ProfileField - поле анкеты организации
+type ProfileField struct {
+	Key         string
+	Title       string
+	Description string
+	Type        ProfileFieldType
+	Options     []ProfileFieldOption
+	// Pattern - регулярное выражение для строковых полей, его же использует форма в webapp
+	Pattern string
+	Min     *float64
+	Max     *float64
+	Check   ProfileCheck
+}
+
+// OrganizationProfileSchema - версионированная схема анкеты организации
+type OrganizationProfileSchema struct {
+	Version int
+	Fields  []ProfileField
+}
+
+func bound(v float64) *float64 {
+	return &v
+}
+
+var organizationProfileSchema = OrganizationProfileSchema{
+	Version: OrganizationProfileSchemaVersion,
+	Fields: []ProfileField{
+		{
+			Key:   ProfileFieldLegalForm,
+			Title: "Организационно-правовая форма",
+			Type:  ProfileFieldEnum,
+			Options: []ProfileFieldOption{
+				{Value: "ooo", Title: "ООО"},
+				{Value: "ao", Title: "АО"},
+				{Value: "pao", Title: "ПАО"},
+				{Value: legalFormIndividual, Title: "ИП"},
+				{Value: "other", Title: "Другая"},
+			},
+		},
+		{
+			Key:         ProfileFieldINN,
+			Title:       "ИНН",
+			Description: "10 цифр для юридического лица, 12 - для ИП",
+			Type:        ProfileFieldString,
+			Pattern:     `^(\d{10}|\d{12})$`,
+			Check:       ProfileCheckINN,
+		},
+		{
+			Key:         ProfileFieldKPP,
+			Title:       "КПП",
+			Description: "Только для юридических лиц",
+			Type:        ProfileFieldString,
+			Pattern:     `^\d{4}[\dA-Z]{2}\d{3}$`,
+		},
+		{
+			Key:         ProfileFieldOGRN,
+			Title:       "ОГРН / ОГРНИП",
+			Description: "13 цифр для юридического лица, 15 - для ИП",
+			Type:        ProfileFieldString,
+			Pattern:     `^(\d{13}|\d{15})$`,
+			Check:       ProfileCheckOGRN,
+		},
+		{
+			Key:         ProfileFieldOKVED,
+			Title:       "Основной код ОКВЭД",
+			Description: "Например, 62.01",
+			Type:        ProfileFieldString,
+			Pattern:     `^\d{2}(\.\d{1,2}){0,2}$`,
+		},
+		{
+			Key:   ProfileFieldTaxRegime,
+			Title: "Система налогообложения",
+			Type:  ProfileFieldEnum,
+			Options: []ProfileFieldOption{
+				{Value: "osno", Title: "ОСНО"},
+				{Value: "usn_income", Title: "УСН «Доходы»"},
+				{Value: "usn_income_expense", Title: "УСН «Доходы минус расходы»"},
+				{Value: "ausn", Title: "АУСН"},
+				{Value: "patent", Title: "Патентная система"},
+				{Value: "eshn", Title: "ЕСХН"},
+			},
+		},
+		{
+			Key:   ProfileFieldHeadcount,
+			Title: "Численность сотрудников",
+			Type:  ProfileFieldInteger,
+			Min:   bound(0),
+			Max:   bound(10_000_000),
+		},
+		{
+			Key:         ProfileFieldAnnualTurnover,
+			Title:       "Годовая выручка, руб.",
+			Description: "За последний полный год",
+			Type:        ProfileFieldNumber,
+			Min:         bound(0),
+		},
+		{
+			Key:   ProfileFieldFoundedYear,
+			Title: "Год основания",
+			Type:  ProfileFieldInteger,
+			Min:   bound(1800),
+			Max:   bound(2100),
+		},
+	},
+}
+
+// profilePatterns - скомпилированные шаблоны строковых полей
+var profilePatterns = func() map[string]*regexp.Regexp {
+	patterns := make(map[string]*regexp.Regexp)
+	for _, field := range organizationProfileSchema.Fields {
+		if field.Pattern != "" {
+			patterns[field.Key] = regexp.MustCompile(field.Pattern)
+		}
+	}
+	return patterns
+}()
+
+// GetOrganizationProfileSchema возвращает текущую схему анкеты
+func GetOrganizationProfileSchema() OrganizationProfileSchema {
+	return organizationProfileSchema
+}
+
+// NormalizeOrganizationProfile проверяет анкету по текущей схеме и приводит ее к каноническому виду:
+// значения полей схемы типизированы, пустые строки удалены, проставлена версия схемы.
+// Поля вне схемы сохраняются как есть - раньше анкета была произвольным JSON
+func NormalizeOrganizationProfile(profileData string) (string, error) {
+	values := make(map[string]json.RawMessage)
+	if trimmed := strings.TrimSpace(profileData); trimmed != "" {
+		decoder := json.NewDecoder(strings.NewReader(trimmed))
+		decoder.UseNumber()
+		if err := decoder.Decode(&values); err != nil || values == nil {
+			return "", NewInvalidArgumentError("profile_data must be a JSON object")
+		}
+	}
+
+	if raw, ok := values[ProfileSchemaVersionKey]; ok {
+		var version int
+		if err := json.Unmarshal(raw, &version); err != nil || version != OrganizationProfileSchemaVersion {
+			return "", NewInvalidArgumentError(fmt.Sprintf("unsupported profile schema version %s", raw))
+		}
+	}
+
+	for _, field := range organizationProfileSchema.Fields {
+		raw, ok := values[field.Key]
+		if !ok {
+			continue
+		}
+		value, err := field.normalize(raw)
+		if err != nil {
+			return "", NewInvalidArgumentError(fmt.Sprintf("profile field %s: %v", field.Key, err))
+		}
+		if value == nil {
+			delete(values, field.Key)
+			continue
+		}
+		values[field.Key] = value
+	}
+
+	if err := checkProfileConsistency(values); err != nil {
+		return "", err
+	}
+
+	values[ProfileSchemaVersionKey] = json.RawMessage(fmt.Sprint(OrganizationProfileSchemaVersion))
+	normalized, err := json.Marshal(values)
+	if err != nil {
+		return "", NewInvalidArgumentError("profile_data must be a JSON object")
+	}
+	return string(normalized), nil
+}
+
+// normalize проверяет значение поля; nil означает, что поле не заполнено
+func (f ProfileField) normalize(raw json.RawMessage) (json.RawMessage, error) {
+	if bytes.Equal(bytes.TrimSpace(raw), []byte("null")) {
+		return nil, nil
+	}
+
+	switch f.Type {
+	case ProfileFieldString, ProfileFieldEnum:
+		var value string
+		if err := json.Unmarshal(raw, &value); err != nil {
+			return nil, fmt.Errorf("must be a string")
+		}
+		value = strings.TrimSpace(value)
+		if value == "" {
+			return nil, nil
+		}
+		if err := f.checkString(value); err != nil {
+			return nil, err
+		}
+		return json.Marshal(value)
+	case ProfileFieldInteger, ProfileFieldNumber:
+		var number json.Number
+		if err := json.Unmarshal(raw, &number); err != nil {
+			return nil, fmt.Errorf("must be a number")
+		}
+		value, err := number.Float64()
+		if err != nil || math.IsInf(value, 0) || math.IsNaN(value) {
+			return nil, fmt.Errorf("must be a number")
+		}
+		if f.Type == ProfileFieldInteger && value != math.Trunc(value) {
+			return nil, fmt.Errorf("must be an integer")
+		}
+		if f.Min != nil && value < *f.Min {
+			return nil, fmt.Errorf("must be at least %v", *f.Min)
+		}
+		if f.Max != nil && value > *f.Max {
+			return nil, fmt.Errorf("must be at most %v", *f.Max)
+		}
+		return json.Marshal(value)
+	default:
+		return nil, fmt.Errorf("unknown field type %s", f.Type)
+	}
+}
+
+func (f ProfileField) checkString(value string) error {
+	if f.Type == ProfileFieldEnum {
+		for _, option := range f.Options {
+			if option.Value == value {
+				return nil
+			}
+		}
+		return fmt.Errorf("unknown value %q", value)
+	}
+
+	if pattern, ok := profilePatterns[f.Key]; ok && !pattern.MatchString(value) {
+		return fmt.Errorf("invalid format")
+	}
+	if check, ok := profileChecks[f.Check]; ok {
+		return check(value)
+	}
+	return nil
+}
+
+// checkProfileConsistency сверяет реквизиты между собой: у ИП ИНН из 12 цифр и ОГРНИП из 15,
+// у юридического лица - 10 и 13
+func checkProfileConsistency(values map[string]json.RawMessage) error {
+	var legalForm, inn, ogrn string
+	_ = json.Unmarshal(values[ProfileFieldLegalForm], &legalForm)
+	_ = json.Unmarshal(values[ProfileFieldINN], &inn)
+	_ = json.Unmarshal(values[ProfileFieldOGRN], &ogrn)
+
+	if inn != "" && ogrn != "" && (len(inn) == 12) != (len(ogrn) == 15) {
+		return NewInvalidArgumentError("profile fields inn and ogrn belong to different kinds of taxpayers")
+	}
+	if legalForm == "" {
+		return nil
+	}
+
+	individual := legalForm == legalFormIndividual
+	if inn != "" && (len(inn) == 12) != individual {
+		return NewInvalidArgumentError("profile field inn does not match legal_form")
+	}
+	if ogrn != "" && (len(ogrn) == 15) != individual {
+		return NewInvalidArgumentError("profile field ogrn does not match legal_form")
+	}
+	return nil
+}
+
+// ValidateINN проверяет контрольные цифры ИНН юридического лица (10 цифр) или физического лица (12 цифр)
+func ValidateINN(inn string) error {
+	digits, ok := parseDigits(inn)
+	if !ok {
+		return fmt.Errorf("inn must contain only digits")
+	}
+
+	switch len(digits) {
+	case 10:
+		if innCheckDigit(digits, []int{2, 4, 10, 3, 5, 9, 4, 6, 8}) != digits[9] {
+			return fmt.Errorf("invalid inn checksum")
+		}
+	case 12:
+		if innCheckDigit(digits, []int{7, 2, 4, 10, 3, 5, 9, 4, 6, 8}) != digits[10] ||
+			innCheckDigit(digits, []int{3, 7, 2, 4, 10, 3, 5, 9, 4, 6, 8}) != digits[11] {
+			return fmt.Errorf("invalid inn checksum")
+		}
+	default:
+		return fmt.Errorf("inn must contain 10 or 12 digits")
+	}
+	return nil
+}
+
+func innCheckDigit(digits, weights []int) int {
+	sum := 0
+	for i, w := range weights {
+		sum += digits[i] * w
+	}
+	return sum % 11 % 10
+}
+
+// ValidateOGRN проверяет контрольную цифру ОГРН (13 цифр) или ОГРНИП (15 цифр):
+// остаток от деления числа без последней цифры на 11 (на 13 для ОГРНИП)
+func ValidateOGRN(ogrn string) error {
+	digits, ok := parseDigits(ogrn)
+	if !ok {
+		return fmt.Errorf("ogrn must contain only digits")
+	}
+
+	var divisor int
+	switch len(digits) {
+	case 13:
+		divisor = 11
+	case 15:
+		divisor = 13
+	default:
+		return fmt.Errorf("ogrn must contain 13 or 15 digits")
+	}
+
+	// Остаток считаем по цифрам, не собирая число целиком
+	remainder := 0
+	for _, d := range digits[:len(digits)-1] {
+		remainder = (remainder*10 + d) % divisor
+	}
+	if remainder%10 != digits[len(digits)-1] {
+		return fmt.Errorf("invalid ogrn checksum")
+	}
+	return nil
+}
+
+func parseDigits(value string) ([]int, bool) {
+	digits := make([]int, 0, len(value))
+	for _, r := range value {
+		if r < '0' || r > '9' {
+			return nil, false
+		}
+		digits = append(digits, int(r-'0'))
+	}
+	return digits, true
+}
@@ -0,0 +1,178 @@
+package domain
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestValidateINN(t *testing.T) {
+	tests := []struct {
+		name    string
+		inn     string
+		wantErr bool
+	}{
+		{name: "юрлицо: Сбербанк", inn: "7707083893"},
+		{name: "юрлицо: Яндекс", inn: "7736207543"},
+		{name: "физлицо", inn: "500100732259"},
+		{name: "физлицо 2", inn: "773173084809"},
+		{name: "юрлицо: неверная контрольная цифра", inn: "7707083894", wantErr: true},
+		{name: "физлицо: неверная первая контрольная цифра", inn: "500100732269", wantErr: true},
+		{name: "физлицо: неверная вторая контрольная цифра", inn: "500100732258", wantErr: true},
+		{name: "11 цифр", inn: "77070838931", wantErr: true},
+		{name: "буквы", inn: "770708389A", wantErr: true},
+		{name: "пустой", inn: "", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateINN(tt.inn)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ValidateINN(%q) = %v, wantErr %v", tt.inn, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidateOGRN(t *testing.T) {
+	tests := []struct {
+		name    string
+		ogrn    string
+		wantErr bool
+	}{
+		{name: "ОГРН: Сбербанк", ogrn: "1027700132195"},
+		{name: "ОГРН: Яндекс", ogrn: "1027700229193"},
+		{name: "ОГРНИП", ogrn: "304500116000157"},
+		{name: "ОГРНИП 2", ogrn: "316861700133226"},
+		{name: "ОГРН: неверная контрольная цифра", ogrn: "1027700132196", wantErr: true},
+		// Для ОГРНИП делитель 13: контрольная цифра по модулю 11 не подходит
+		{name: "ОГРНИП: контрольная цифра по модулю 11", ogrn: "316861700133220", wantErr: true},
+		{name: "14 цифр", ogrn: "10277001321950", wantErr: true},
+		{name: "буквы", ogrn: "102770013219O", wantErr: true},
+		{name: "пустой", ogrn: "", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateOGRN(tt.ogrn)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ValidateOGRN(%q) = %v, wantErr %v", tt.ogrn, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestNormalizeOrganizationProfile(t *testing.T) {
+	tests := []struct {
+		name    string
+		profile string
+		want    map[string]any
+		wantErr bool
+	}{
+		{
+			name:    "пустая анкета",
+			profile: "",
+			want:    map[string]any{"schema_version": 1.0},
+		},
+		{
+			name:    "юрлицо",
+			profile: `{"legal_form": "ooo", "inn": " 7707083893 ", "kpp": "773601001", "ogrn": "1027700132195", "headcount": 250, "okved": ""}`,
+			want: map[string]any{
+				"schema_version": 1.0,
+				"legal_form":     "ooo",
+				"inn":            "7707083893",
+				"kpp":            "773601001",
+				"ogrn":           "1027700132195",
+				"headcount":      250.0,
+			},
+		},
+		{
+			name:    "ИП",
+			profile: `{"legal_form": "ip", "inn": "500100732259", "ogrn": "304500116000157", "tax_regime": "patent"}`,
+			want: map[string]any{
+				"schema_version": 1.0,
+				"legal_form":     "ip",
+				"inn":            "500100732259",
+				"ogrn":           "304500116000157",
+				"tax_regime":     "patent",
+			},
+		},
+		{
+			// Форма может прислать число строкой: в анкете оно сохраняется числом
+			name:    "число строкой",
+			profile: `{"headcount": "12", "annual_turnover": "1500000.50"}`,
+			want:    map[string]any{"schema_version": 1.0, "headcount": 12.0, "annual_turnover": 1500000.5},
+		},
+		{
+			name:    "поля вне схемы сохраняются",
+			profile: `{"schema_version": 1, "website": "example.ru", "inn": null}`,
+			want:    map[string]any{"schema_version": 1.0, "website": "example.ru"},
+		},
+		{name: "не объект", profile: `["inn"]`, wantErr: true},
+		{name: "неизвестная версия схемы", profile: `{"schema_version": 2}`, wantErr: true},
+		{name: "неверная контрольная сумма ИНН", profile: `{"inn": "7707083894"}`, wantErr: true},
+		{name: "неизвестная форма", profile: `{"legal_form": "zao"}`, wantErr: true},
+		{name: "дробная численность", profile: `{"headcount": 12.5}`, wantErr: true},
+		{name: "год вне диапазона", profile: `{"founded_year": 1700}`, wantErr: true},
+		{name: "не число", profile: `{"headcount": "много"}`, wantErr: true},
+		{name: "неверный формат КПП", profile: `{"kpp": "77360100"}`, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NormalizeOrganizationProfile(tt.profile)
+			if tt.wantErr {
+				if !errors.Is(err, ErrInvalidArgument) {
+					t.Fatalf("NormalizeOrganizationProfile = %s, %v, want ErrInvalidArgument", got, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("NormalizeOrganizationProfile: %v", err)
+			}
+
+			var values map[string]any
+			if err := json.Unmarshal([]byte(got), &values); err != nil {
+				t.Fatalf("normalized profile %s: %v", got, err)
+			}
+			if !reflect.DeepEqual(values, tt.want) {
+				t.Errorf("normalized profile = %v, want %v", values, tt.want)
+			}
+		})
+	}
+}
+
+func TestNormalizeOrganizationProfileConsistency(t *testing.T) {
+	tests := []struct {
+		name    string
+		profile string
+		wantErr bool
+	}{
+		{name: "ООО с ИНН и ОГРН юрлица", profile: `{"legal_form": "ooo", "inn": "7707083893", "ogrn": "1027700132195"}`},
+		{name: "ИП с ИНН и ОГРНИП", profile: `{"legal_form": "ip", "inn": "500100732259", "ogrn": "304500116000157"}`},
+		{name: "реквизиты без формы", profile: `{"inn": "500100732259", "ogrn": "304500116000157"}`},
+		{name: "форма без реквизитов", profile: `{"legal_form": "ip"}`},
+		{name: "ООО с ИНН физлица", profile: `{"legal_form": "ooo", "inn": "500100732259"}`, wantErr: true},
+		{name: "ООО с ОГРНИП", profile: `{"legal_form": "ao", "ogrn": "304500116000157"}`, wantErr: true},
+		{name: "ИП с ИНН юрлица", profile: `{"legal_form": "ip", "inn": "7707083893"}`, wantErr: true},
+		{name: "ИП с ОГРН юрлица", profile: `{"legal_form": "ip", "ogrn": "1027700132195"}`, wantErr: true},
+		{name: "ИНН юрлица и ОГРНИП без формы", profile: `{"inn": "7707083893", "ogrn": "304500116000157"}`, wantErr: true},
+		{name: "ИНН физлица и ОГРН без формы", profile: `{"inn": "500100732259", "ogrn": "1027700132195"}`, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NormalizeOrganizationProfile(tt.profile)
+			if tt.wantErr {
+				if !errors.Is(err, ErrInvalidArgument) {
+					t.Errorf("NormalizeOrganizationProfile(%s) = %v, want ErrInvalidArgument", tt.profile, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Errorf("NormalizeOrganizationProfile(%s): %v", tt.profile, err)
+			}
+		})
+	}
+}
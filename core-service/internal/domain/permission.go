@@ -0,0 +1,134 @@
+package domain
+
+import (
+	"slices"
+	"time"
+)
+
+// Permission - именованное право участника внутри организации
+type Permission string
+
+const (
+	PermissionOrganizationManage Permission = "organization.manage"
+	PermissionUsersInvite        Permission = "users.invite"
+	PermissionUsersManage        Permission = "users.manage"
+	PermissionRolesManage        Permission = "roles.manage"
+	PermissionDocumentsWrite     Permission = "documents.write"
+	PermissionDocumentsDelete    Permission = "documents.delete"
+	PermissionNotesWrite         Permission = "notes.write"
+	PermissionNotesDelete        Permission = "notes.delete"
+	PermissionContractsRead      Permission = "contracts.read"
+	PermissionContractsGenerate  Permission = "contracts.generate"
+	PermissionContractsDelete    Permission = "contracts.delete"
+	PermissionCRMRead            Permission = "crm.read"
+	PermissionCRMWrite           Permission = "crm.write"
+	PermissionMemoryEdit         Permission = "memory.edit"
+	PermissionChatsViewAll       Permission = "chats.view_all"
+	PermissionAnalyticsView      Permission = "analytics.view"
+	PermissionAuditView          Permission = "audit.view"
+)
+
+// permissionDescriptions описывает права для экрана настройки ролей; порядок задает AllPermissions
+var permissionDescriptions = []struct {
+	permission  Permission
+	description string
+}{
+	{PermissionOrganizationManage, "Изменение и удаление организации"},
+	{PermissionUsersInvite, "Приглашение пользователей"},
+	{PermissionUsersManage, "Смена ролей и деактивация участников"},
+	{PermissionRolesManage, "Создание и изменение ролей"},
+	{PermissionDocumentsWrite, "Загрузка документов"},
+	{PermissionDocumentsDelete, "Удаление документов"},
+	{PermissionNotesWrite, "Создание заметок"},
+	{PermissionNotesDelete, "Удаление заметок"},
+	{PermissionContractsRead, "Просмотр сгенерированных договоров"},
+	{PermissionContractsGenerate, "Генерация договоров"},
+	{PermissionContractsDelete, "Удаление договоров"},
+	{PermissionCRMRead, "Чтение данных CRM через агента"},
+	{PermissionCRMWrite, "Изменение данных CRM через агента"},
+	{PermissionMemoryEdit, "Изменение памяти агента об организации"},
+	{PermissionChatsViewAll, "Просмотр чатов всех участников"},
+	{PermissionAnalyticsView, "Просмотр аналитики по оценкам ответов"},
+	{PermissionAuditView, "Просмотр журнала аудита"},
+}
+
+// employeePermissions - права встроенной роли сотрудника (то, что сотрудник мог делать до появления ролей)
+var employeePermissions = []Permission{
+	PermissionDocumentsWrite,
+	PermissionNotesWrite,
+	PermissionContractsRead,
+	PermissionContractsGenerate,
+	PermissionCRMRead,
+	PermissionCRMWrite,
+	PermissionMemoryEdit,
+}
+
+// AllPermissions возвращает все известные права
+func AllPermissions() []Permission {
+	permissions := make([]Permission, 0, len(permissionDescriptions))
+	for _, p := range permissionDescriptions {
+		permissions = append(permissions, p.permission)
+	}
+	return permissions
+}
+
+// Description возвращает описание права
+func (p Permission) Description() string {
+	for _, d := range permissionDescriptions {
+		if d.permission == p {
+			return d.description
+		}
+	}
+	return ""
+}
+
+// IsValid проверяет, что право известно
+func (p Permission) IsValid() bool {
+	return p.Description() != ""
+}
+
+// RolePermissions возвращает права встроенной роли: администратору доступно все
+func RolePermissions(role UserRole) []Permission {
+	switch role {
+	case UserRoleAdmin:
+		return AllPermissions()
+	case UserRoleEmployee:
+		return slices.Clone(employeePermissions)
+	default:
+		return nil
+	}
+}
+
+// CustomRole - собственная роль организации с произвольным набором прав.
+// Назначается сотрудникам и заменяет права встроенной роли employee
+type CustomRole struct {
+	ID             ID           `db:"id"`
+	OrganizationID ID           `db:"organization_id"`
+	Name           string       `db:"name"`
+	Description    string       `db:"description"`
+	Permissions    []Permission `db:"permissions"`
+	CreatedAt      time.Time    `db:"created_at"`
+	UpdatedAt      time.Time    `db:"updated_at"`
+}
+
+// NewCustomRole создает роль организации
+func NewCustomRole(organizationID ID, name, description string, permissions []Permission) CustomRole {
+	now := time.Now()
+	return CustomRole{
+		ID:             NewID(),
+		OrganizationID: organizationID,
+		Name:           name,
+		Description:    description,
+		Permissions:    permissions,
+		CreatedAt:      now,
+		UpdatedAt:      now,
+	}
+}
+
+// Update меняет название, описание и права роли
+func (r *CustomRole) Update(name, description string, permissions []Permission) {
+	r.Name = name
+	r.Description = description
+	r.Permissions = permissions
+	r.UpdatedAt = time.Now()
+}
@@ -1,6 +1,9 @@
 package domain
 
-import "time"
+import (
+	"slices"
+	"time"
+)
 
 // UserRole определяет роль пользователя в организации
 type UserRole string
@@ -16,7 +19,8 @@ type UserStatus string
 const (
 	UserStatusPending  UserStatus = "pending"  // Приглашен, но не принял
 	UserStatusActive   UserStatus = "active"   // Активный пользователь
-	UserStatusInactive UserStatus = "inactive" // Деактивирован
+	UserStatusInactive UserStatus = "inactive" // Деактивирован администратором
+	UserStatusLeft     UserStatus = "left"     // Сам покинул организацию
 )
 
 // User представляет пользователя системы (независимо от организаций)
@@ -41,6 +45,11 @@ type OrganizationMember struct {
 	Status         UserStatus `db:"status"`
 	JoinedAt       time.Time  `db:"joined_at"`
 	UpdatedAt      time.Time  `db:"updated_at"`
+	// CustomRoleID - собственная роль организации; пока она назначена, права берутся из нее
+	CustomRoleID *ID `db:"custom_role_id"`
+	// CustomRoleName и CustomPermissions подгружаются из назначенной собственной роли
+	CustomRoleName    string       `db:"custom_role_name"`
+	CustomPermissions []Permission `db:"custom_permissions"`
 }
 
 // UserWithMembership объединяет пользователя с его членством в организации (для API)
@@ -91,9 +100,21 @@ func (u *User) Deactivate() {
 	u.UpdatedAt = time.Now()
 }
 
-// UpdateRole обновляет роль пользователя в организации
+// UpdateRole обновляет роль пользователя в организации и снимает собственную роль
 func (m *OrganizationMember) UpdateRole(role UserRole) {
 	m.Role = role
+	m.CustomRoleID = nil
+	m.CustomRoleName = ""
+	m.CustomPermissions = nil
+	m.UpdatedAt = time.Now()
+}
+
+// AssignCustomRole назначает сотруднику собственную роль организации
+func (m *OrganizationMember) AssignCustomRole(role CustomRole) {
+	m.Role = UserRoleEmployee
+	m.CustomRoleID = &role.ID
+	m.CustomRoleName = role.Name
+	m.CustomPermissions = role.Permissions
 	m.UpdatedAt = time.Now()
 }
 
@@ -103,46 +124,186 @@ func (m *OrganizationMember) Deactivate() {
 	m.UpdatedAt = time.Now()
 }
 
+// Leave отмечает, что участник сам покинул организацию
+func (m *OrganizationMember) Leave() {
+	m.Status = UserStatusLeft
+	m.UpdatedAt = time.Now()
+}
+
+// Reactivate восстанавливает членство при повторном вступлении в организацию
+func (m *OrganizationMember) Reactivate() {
+	now := time.Now()
+	m.Status = UserStatusActive
+	m.JoinedAt = now
+	m.UpdatedAt = now
+}
+
+// CanRejoinWith проверяет, можно ли вернуться в организацию по приглашению. Покинувший
+// организацию участник возвращается по любой действующей ссылке, деактивированный
+// администратором - только по ссылке, созданной после деактивации: старая многоразовая
+// ссылка не должна отменять решение администратора. Пока членство неактивно, UpdatedAt
+// меняется только при деактивации, поэтому служит ее временем
+func (m *OrganizationMember) CanRejoinWith(invitation Invitation) bool {
+	switch m.Status {
+	case UserStatusLeft:
+		return true
+	case UserStatusInactive:
+		return invitation.CreatedAt.After(m.UpdatedAt)
+	default:
+		return false
+	}
+}
+
+// IsActiveAdmin проверяет, что участник - действующий администратор организации
+func (m *OrganizationMember) IsActiveAdmin() bool {
+	return m.IsActive() && m.Role == UserRoleAdmin
+}
+
 // IsActive проверяет, активно ли членство
 func (m *OrganizationMember) IsActive() bool {
 	return m.Status == UserStatusActive
 }
 
-// Invitation представляет приглашение пользователя
+// roleRank задает старшинство ролей: администратор может все, что может сотрудник
+var roleRank = map[UserRole]int{
+	UserRoleEmployee: 1,
+	UserRoleAdmin:    2,
+}
+
+// HasRole проверяет, что роль участника не ниже требуемой
+func (m *OrganizationMember) HasRole(required UserRole) bool {
+	return roleRank[m.Role] >= roleRank[required] && roleRank[m.Role] > 0
+}
+
+// Permissions возвращает действующие права участника: администратору доступно все,
+// сотруднику с собственной ролью - права этой роли, остальным - права встроенной роли
+func (m *OrganizationMember) Permissions() []Permission {
+	if m.Role != UserRoleAdmin && m.CustomRoleID != nil {
+		return m.CustomPermissions
+	}
+	return RolePermissions(m.Role)
+}
+
+// HasPermission проверяет, что у участника есть право
+func (m *OrganizationMember) HasPermission(permission Permission) bool {
+	return slices.Contains(m.Permissions(), permission)
+}
+
+// CanGrant проверяет, что участник сам обладает всеми выдаваемыми правами,
+// чтобы через роли нельзя было расширить собственные права
+func (m *OrganizationMember) CanGrant(permissions []Permission) bool {
+	own := m.Permissions()
+	for _, p := range permissions {
+		if !slices.Contains(own, p) {
+			return false
+		}
+	}
+	return true
+}
+
+// Ограничения политики приглашений
+const (
+	DefaultInvitationTTL = 7 * 24 * time.Hour
+	MaxInvitationTTL     = 30 * 24 * time.Hour
+	MaxInvitationUses    = 1000
+)
+
+// InvitationStatus - состояние приглашения, вычисляется из сроков, счетчика и отзыва
+type InvitationStatus string
+
+const (
+	InvitationStatusActive    InvitationStatus = "active"
+	InvitationStatusExpired   InvitationStatus = "expired"
+	InvitationStatusExhausted InvitationStatus = "exhausted" // Использовано максимальное число раз
+	InvitationStatusRevoked   InvitationStatus = "revoked"
+)
+
+// Invitation представляет приглашение пользователя. Ссылка может быть многоразовой:
+// по ней вступают до MaxUses пользователей, пока она не истекла и не отозвана
 type Invitation struct {
-	ID             ID         `db:"id"`
-	OrganizationID ID         `db:"organization_id"`
-	Token          string     `db:"token"`
-	Role           UserRole   `db:"role"`
+	ID             ID       `db:"id"`
+	OrganizationID ID       `db:"organization_id"`
+	Token          string   `db:"token"`
+	Role           UserRole `db:"role"`
+	// CustomRoleID - собственная роль организации, которую получают вступившие; имя подгружается из роли
+	CustomRoleID   *ID        `db:"custom_role_id"`
+	CustomRoleName string     `db:"custom_role_name"`
+	MaxUses        int        `db:"max_uses"`
+	UsesCount      int        `db:"uses_count"`
+	CreatedBy      *ID        `db:"created_by"`
 	ExpiresAt      time.Time  `db:"expires_at"`
-	UsedAt         *time.Time `db:"used_at"`
+	UsedAt         *time.Time `db:"used_at"` // Время последнего использования
+	RevokedAt      *time.Time `db:"revoked_at"`
 	CreatedAt      time.Time  `db:"created_at"`
 }
 
 // NewInvitation создает новое приглашение
-func NewInvitation(organizationID ID, role UserRole, token string, expiresAt time.Time) Invitation {
+func NewInvitation(organizationID ID, role UserRole, token string, expiresAt time.Time, maxUses int, createdBy *ID) Invitation {
 	return Invitation{
 		ID:             NewID(),
 		OrganizationID: organizationID,
 		Token:          token,
 		Role:           role,
+		MaxUses:        maxUses,
+		CreatedBy:      createdBy,
 		ExpiresAt:      expiresAt,
 		CreatedAt:      time.Now(),
 	}
 }
 
+// AssignCustomRole заранее назначает вступающим собственную роль организации
+func (i *Invitation) AssignCustomRole(role CustomRole) {
+	i.Role = UserRoleEmployee
+	i.CustomRoleID = &role.ID
+	i.CustomRoleName = role.Name
+}
+
+// Regenerate выдает приглашению новую ссылку и открывает его заново: счетчик использований
+// сбрасывается, отзыв снимается. Время создания переносится на момент перевыпуска - для
+// проверки возврата деактивированных участников это новая ссылка
+func (i *Invitation) Regenerate(token string, expiresAt time.Time) {
+	i.Token = token
+	i.ExpiresAt = expiresAt
+	i.UsesCount = 0
+	i.RevokedAt = nil
+	i.CreatedAt = time.Now()
+}
+
 // IsExpired проверяет, истекло ли приглашение
 func (i *Invitation) IsExpired() bool {
 	return time.Now().After(i.ExpiresAt)
 }
 
-// IsUsed проверяет, использовано ли приглашение
+// IsUsed проверяет, исчерпан ли лимит использований приглашения
 func (i *Invitation) IsUsed() bool {
-	return i.UsedAt != nil
+	return i.UsesCount >= i.MaxUses
 }
 
-// MarkAsUsed помечает приглашение как использованное
-func (i *Invitation) MarkAsUsed() {
-	now := time.Now()
-	i.UsedAt = &now
+// IsRevoked проверяет, отозвано ли приглашение
+func (i *Invitation) IsRevoked() bool {
+	return i.RevokedAt != nil
+}
+
+// Status возвращает текущее состояние приглашения
+func (i *Invitation) Status() InvitationStatus {
+	switch {
+	case i.IsRevoked():
+		return InvitationStatusRevoked
+	case i.IsUsed():
+		return InvitationStatusExhausted
+	case i.IsExpired():
+		return InvitationStatusExpired
+	default:
+		return InvitationStatusActive
+	}
+}
+
+// InvitationUse - запись истории: пользователь вступил в организацию по приглашению
+type InvitationUse struct {
+	ID           ID        `db:"id"`
+	InvitationID ID        `db:"invitation_id"`
+	UserID       ID        `db:"user_id"`
+	FirstName    string    `db:"first_name"`
+	LastName     string    `db:"last_name"`
+	UsedAt       time.Time `db:"used_at"`
 }
@@ -0,0 +1,67 @@
+package domain
+
+import (
+	"testing"
+	"time"
+)
+
+func TestOrganizationMemberCanRejoinWith(t *testing.T) {
+	changedAt := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name      string
+		status    UserStatus
+		createdAt time.Time
+		want      bool
+	}{
+		{name: "покинул организацию, старая ссылка", status: UserStatusLeft, createdAt: changedAt.Add(-time.Hour), want: true},
+		{name: "покинул организацию, новая ссылка", status: UserStatusLeft, createdAt: changedAt.Add(time.Hour), want: true},
+		{name: "деактивирован, ссылка создана до деактивации", status: UserStatusInactive, createdAt: changedAt.Add(-time.Hour), want: false},
+		{name: "деактивирован, ссылка создана в момент деактивации", status: UserStatusInactive, createdAt: changedAt, want: false},
+		{name: "деактивирован, ссылка создана после деактивации", status: UserStatusInactive, createdAt: changedAt.Add(time.Minute), want: true},
+		{name: "активный участник", status: UserStatusActive, createdAt: changedAt.Add(time.Hour), want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			member := OrganizationMember{Status: tt.status, UpdatedAt: changedAt}
+			invitation := Invitation{CreatedAt: tt.createdAt}
+
+			if got := member.CanRejoinWith(invitation); got != tt.want {
+				t.Errorf("CanRejoinWith = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRegeneratedInvitationReadmitsDeactivatedMember(t *testing.T) {
+	deactivatedAt := time.Now().Add(-time.Hour)
+	member := OrganizationMember{Status: UserStatusInactive, UpdatedAt: deactivatedAt}
+
+	// Ссылка создана до деактивации, затем отозвана
+	revokedAt := deactivatedAt.Add(-time.Minute)
+	invitation := Invitation{
+		Token:     "old",
+		MaxUses:   1,
+		UsesCount: 1,
+		ExpiresAt: deactivatedAt.Add(time.Hour),
+		RevokedAt: &revokedAt,
+		CreatedAt: deactivatedAt.Add(-24 * time.Hour),
+	}
+	if member.CanRejoinWith(invitation) {
+		t.Fatalf("CanRejoinWith = true for an invitation created before deactivation")
+	}
+
+	// Администратор перевыпускает ссылку после деактивации
+	invitation.Regenerate("new", time.Now().Add(DefaultInvitationTTL))
+
+	if invitation.Status() != InvitationStatusActive {
+		t.Errorf("regenerated invitation status = %s, want %s", invitation.Status(), InvitationStatusActive)
+	}
+	if invitation.Token != "new" {
+		t.Errorf("regenerated invitation token = %q, want %q", invitation.Token, "new")
+	}
+	if !member.CanRejoinWith(invitation) {
+		t.Errorf("CanRejoinWith = false for a regenerated invitation")
+	}
+}
@@ -133,14 +133,25 @@ func (p *Provider) ParseToken(ctx context.Context, token string) (domain.ID, err
 	return domain.ParseID(subject)
 }
 
+// AccessTTL returns lifetime of issued access tokens.
+func (p *Provider) AccessTTL() time.Duration {
+	return p.opts.AccessTTL
+}
+
 // GenerateAccessToken creates short-lived access JWT for the given user with list of organizations.
-// The organizations list is for frontend use; backend validates organization_id from request.
+// The organizations list (role and effective permissions per organization) is for frontend use
+// and for llm-service; core-service validates organization_id from request against the database.
 func (p *Provider) GenerateAccessToken(ctx context.Context, userID domain.ID, memberships []*domain.OrganizationMember) (string, error) {
 	orgs := make([]map[string]interface{}, 0, len(memberships))
 	for _, m := range memberships {
+		permissions := make([]string, 0)
+		for _, p := range m.Permissions() {
+			permissions = append(permissions, string(p))
+		}
 		orgs = append(orgs, map[string]interface{}{
-			"id":   m.OrganizationID.String(),
-			"role": string(m.Role),
+			"id":          m.OrganizationID.String(),
+			"role":        string(m.Role),
+			"permissions": permissions,
 		})
 	}
 
@@ -0,0 +1,18 @@
+package notifier
+
+import "context"
+
+// Message is a notification addressed to a single Telegram chat
+type Message struct {
+	ChatID string
+	// Text is HTML formatted (Telegram parse_mode=HTML)
+	Text string
+	// ButtonText and ButtonURL add an inline button under the message when both are set
+	ButtonText string
+	ButtonURL  string
+}
+
+// Notifier delivers notifications to users
+type Notifier interface {
+	Send(ctx context.Context, message Message) error
+}
@@ -0,0 +1,110 @@
+package notifier
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// StubMessage is a message captured by StubServer
+type StubMessage struct {
+	Token       string          `json:"token"`
+	ChatID      string          `json:"chat_id"`
+	Text        string          `json:"text"`
+	ParseMode   string          `json:"parse_mode"`
+	ReplyMarkup json.RawMessage `json:"reply_markup,omitempty"`
+	ReceivedAt  time.Time       `json:"received_at"`
+}
+
+// StubServer is a local stand-in for the Telegram Bot API used in tests and local runs.
+// It accepts POST /bot{token}/sendMessage and records the messages, which can be
+// inspected with GET /messages and cleared with DELETE /messages.
+type StubServer struct {
+	mu       sync.Mutex
+	messages []StubMessage
+}
+
+// NewStubServer creates an empty stub
+func NewStubServer() *StubServer {
+	return &StubServer{}
+}
+
+// Messages returns a copy of the captured messages
+func (s *StubServer) Messages() []StubMessage {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]StubMessage(nil), s.messages...)
+}
+
+// Reset clears the captured messages
+func (s *StubServer) Reset() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.messages = nil
+}
+
+func (s *StubServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path == "/messages" {
+		switch r.Method {
+		case http.MethodGet:
+			writeJSON(w, http.StatusOK, s.Messages())
+		case http.MethodDelete:
+			s.Reset()
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			w.WriteHeader(http.StatusMethodNotAllowed)
+		}
+		return
+	}
+
+	token, method, ok := parseBotPath(r.URL.Path)
+	if !ok || token == "" {
+		writeJSON(w, http.StatusNotFound, apiResponse{Description: "Not Found"})
+		return
+	}
+	if method != "sendMessage" || r.Method != http.MethodPost {
+		writeJSON(w, http.StatusNotFound, apiResponse{Description: "Not Found: method not supported by stub"})
+		return
+	}
+
+	var req StubMessage
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeJSON(w, http.StatusBadRequest, apiResponse{Description: "Bad Request: invalid JSON"})
+		return
+	}
+	if req.ChatID == "" || strings.TrimSpace(req.Text) == "" {
+		writeJSON(w, http.StatusBadRequest, apiResponse{Description: "Bad Request: chat_id and text are required"})
+		return
+	}
+
+	req.Token = token
+	req.ReceivedAt = time.Now()
+
+	s.mu.Lock()
+	s.messages = append(s.messages, req)
+	messageID := len(s.messages)
+	s.mu.Unlock()
+
+	writeJSON(w, http.StatusOK, map[string]any{
+		"ok":     true,
+		"result": map[string]any{"message_id": messageID},
+	})
+}
+
+// parseBotPath splits /bot{token}/{method}
+func parseBotPath(path string) (token, method string, ok bool) {
+	rest, found := strings.CutPrefix(path, "/bot")
+	if !found {
+		return "", "", false
+	}
+	token, method, found = strings.Cut(rest, "/")
+	return token, method, found
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
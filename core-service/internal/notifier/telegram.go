@@ -0,0 +1,113 @@
+package notifier
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/opentracing/opentracing-go"
+)
+
+// RetryAfterError is returned when Telegram asks to slow down
+type RetryAfterError struct {
+	RetryAfter time.Duration
+}
+
+func (e *RetryAfterError) Error() string {
+	return fmt.Sprintf("telegram rate limit exceeded, retry after %s", e.RetryAfter)
+}
+
+// TelegramNotifier sends notifications through the Telegram Bot API
+type TelegramNotifier struct {
+	apiURL     string
+	token      string
+	httpClient *http.Client
+}
+
+// NewTelegramNotifier creates a notifier for the given bot; apiURL is usually https://api.telegram.org
+func NewTelegramNotifier(apiURL, token string) *TelegramNotifier {
+	return &TelegramNotifier{
+		apiURL:     strings.TrimRight(apiURL, "/"),
+		token:      token,
+		httpClient: &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+type sendMessageRequest struct {
+	ChatID                string                `json:"chat_id"`
+	Text                  string                `json:"text"`
+	ParseMode             string                `json:"parse_mode"`
+	DisableWebPagePreview bool                  `json:"disable_web_page_preview"`
+	ReplyMarkup           *inlineKeyboardMarkup `json:"reply_markup,omitempty"`
+}
+
+type inlineKeyboardMarkup struct {
+	InlineKeyboard [][]inlineKeyboardButton `json:"inline_keyboard"`
+}
+
+type inlineKeyboardButton struct {
+	Text string `json:"text"`
+	URL  string `json:"url"`
+}
+
+type apiResponse struct {
+	OK          bool   `json:"ok"`
+	Description string `json:"description"`
+	Parameters  *struct {
+		RetryAfter int `json:"retry_after"`
+	} `json:"parameters"`
+}
+
+// Send calls sendMessage of the Bot API
+func (n *TelegramNotifier) Send(ctx context.Context, message Message) error {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "notifier.TelegramNotifier.Send")
+	defer span.Finish()
+
+	req := sendMessageRequest{
+		ChatID:                message.ChatID,
+		Text:                  message.Text,
+		ParseMode:             "HTML",
+		DisableWebPagePreview: true,
+	}
+	if message.ButtonText != "" && message.ButtonURL != "" {
+		req.ReplyMarkup = &inlineKeyboardMarkup{
+			InlineKeyboard: [][]inlineKeyboardButton{{{Text: message.ButtonText, URL: message.ButtonURL}}},
+		}
+	}
+
+	body, err := json.Marshal(req)
+	if err != nil {
+		return fmt.Errorf("failed to marshal telegram request: %w", err)
+	}
+
+	url := fmt.Sprintf("%s/bot%s/sendMessage", n.apiURL, n.token)
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create telegram request: %w", err)
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+
+	resp, err := n.httpClient.Do(httpReq)
+	if err != nil {
+		return fmt.Errorf("failed to call telegram: %w", err)
+	}
+	defer resp.Body.Close()
+
+	var result apiResponse
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return fmt.Errorf("failed to decode telegram response (status %d): %w", resp.StatusCode, err)
+	}
+
+	if resp.StatusCode == http.StatusTooManyRequests && result.Parameters != nil {
+		return &RetryAfterError{RetryAfter: time.Duration(result.Parameters.RetryAfter) * time.Second}
+	}
+	if !result.OK {
+		return fmt.Errorf("telegram sendMessage failed (status %d): %s", resp.StatusCode, result.Description)
+	}
+
+	return nil
+}
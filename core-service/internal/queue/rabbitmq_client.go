@@ -5,10 +5,15 @@ import (
 	"encoding/json"
 	"fmt"
 
+	"core-service/internal/logger"
+
 	"github.com/opentracing/opentracing-go"
 	amqp "github.com/rabbitmq/amqp091-go"
 )
 
+// MessageHandler processes a raw message body taken from the queue
+type MessageHandler func(ctx context.Context, body []byte) error
+
 type RabbitMQClient struct {
 	conn      *amqp.Connection
 	channel   *amqp.Channel
@@ -85,3 +90,56 @@ func (c *RabbitMQClient) PublishMessage(ctx context.Context, message interface{}
 
 	return nil
 }
+
+// Consume delivers messages to the handler until the context is cancelled.
+// A failed message is requeued once and dropped if it fails again.
+func (c *RabbitMQClient) Consume(ctx context.Context, consumerTag string, prefetchCount int, handler MessageHandler) error {
+	if err := c.channel.Qos(prefetchCount, 0, false); err != nil {
+		return fmt.Errorf("failed to set QoS: %w", err)
+	}
+
+	msgs, err := c.channel.Consume(
+		c.queueName,
+		consumerTag,
+		false,
+		false,
+		false,
+		false,
+		nil,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to register consumer: %w", err)
+	}
+
+	logger.Info(ctx, "consumer started", "queue", c.queueName, "consumer", consumerTag)
+
+	for {
+		select {
+		case <-ctx.Done():
+			logger.Info(ctx, "consumer stopped", "queue", c.queueName)
+			return nil
+		case msg, ok := <-msgs:
+			if !ok {
+				return fmt.Errorf("queue %s: delivery channel closed", c.queueName)
+			}
+			c.handleMessage(ctx, msg, handler)
+		}
+	}
+}
+
+func (c *RabbitMQClient) handleMessage(ctx context.Context, msg amqp.Delivery, handler MessageHandler) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "queue.RabbitMQClient.handleMessage")
+	defer span.Finish()
+
+	if err := handler(ctx, msg.Body); err != nil {
+		logger.Error(ctx, "failed to handle message",
+			"queue", c.queueName,
+			"error", err,
+			"redelivered", msg.Redelivered,
+		)
+		msg.Nack(false, !msg.Redelivered)
+		return
+	}
+
+	msg.Ack(false)
+}
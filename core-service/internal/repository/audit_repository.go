@@ -0,0 +1,116 @@
+package repository
+
+import (
+	"context"
+	"core-service/internal/domain"
+	"core-service/internal/logger"
+	"fmt"
+	"strings"
+
+	"github.com/georgysavva/scany/v2/pgxscan"
+	"github.com/opentracing/opentracing-go"
+)
+
+// CreateAuditEvent appends an event to the audit log; returns false if an event with the same event_id is already there
+func (r *PGXRepository) CreateAuditEvent(ctx context.Context, event domain.AuditEvent) (bool, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "repository.CreateAuditEvent")
+	defer span.Finish()
+
+	metadata := event.Metadata
+	if metadata == nil {
+		metadata = map[string]any{}
+	}
+
+	engine := r.engineFactory.Get(ctx)
+	query := `
+        INSERT INTO audit_events (event_id, action, source, organization_id, actor_user_id, resource_type, resource_id, metadata, occurred_at)
+        VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+        ON CONFLICT (event_id) DO NOTHING
+    `
+
+	tag, err := engine.Exec(ctx, query,
+		event.EventID,
+		event.Action,
+		event.Source,
+		optionalUUIDToPgtype(event.OrganizationID),
+		optionalUUIDToPgtype(event.ActorUserID),
+		event.ResourceType,
+		event.ResourceID,
+		metadata,
+		event.OccurredAt,
+	)
+	if err != nil {
+		logger.Errorf(ctx, "failed to create audit event: %v", err)
+		return false, err
+	}
+
+	return tag.RowsAffected() > 0, nil
+}
+
+// ListAuditEvents retrieves audit events matching the filter, newest first
+func (r *PGXRepository) ListAuditEvents(ctx context.Context, filter domain.AuditEventFilter, limit, offset int) ([]domain.AuditEvent, int, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "repository.ListAuditEvents")
+	defer span.Finish()
+
+	var (
+		conditions []string
+		args       []any
+	)
+	addCondition := func(condition string, arg any) {
+		args = append(args, arg)
+		conditions = append(conditions, fmt.Sprintf(condition, len(args)))
+	}
+
+	if filter.OrganizationID != nil {
+		addCondition("organization_id = $%d", uuidToPgtype(*filter.OrganizationID))
+	}
+	if filter.ActorUserID != nil {
+		addCondition("actor_user_id = $%d", uuidToPgtype(*filter.ActorUserID))
+	}
+	if category, ok := strings.CutSuffix(filter.Action, "."); ok {
+		addCondition("starts_with(action, $%d)", category+".")
+	} else if filter.Action != "" {
+		addCondition("action = $%d", filter.Action)
+	}
+	if filter.From != nil {
+		addCondition("occurred_at >= $%d", *filter.From)
+	}
+	if filter.To != nil {
+		addCondition("occurred_at < $%d", *filter.To)
+	}
+
+	where := ""
+	if len(conditions) > 0 {
+		where = "WHERE " + strings.Join(conditions, " AND ")
+	}
+
+	engine := r.engineFactory.Get(ctx)
+
+	var total int
+	countQuery := `SELECT COUNT(*) FROM audit_events ` + where
+	if err := pgxscan.Get(ctx, engine, &total, countQuery, args...); err != nil {
+		logger.Errorf(ctx, "failed to count audit events: %v", err)
+		return nil, 0, err
+	}
+
+	query := fmt.Sprintf(`
+        SELECT id, event_id, action, source, organization_id, actor_user_id, resource_type, resource_id, metadata, occurred_at, created_at
+        FROM audit_events
+        %s
+        ORDER BY occurred_at DESC, id
+        LIMIT $%d OFFSET $%d
+    `, where, len(args)+1, len(args)+2)
+
+	var events []domain.AuditEvent
+	err := pgxscan.Select(ctx, engine, &events, query, append(args, limit, offset)...)
+	if err != nil {
+		logger.Errorf(ctx, "failed to list audit events: %v", err)
+		return nil, 0, err
+	}
+
+	if events == nil {
+		events = []domain.AuditEvent{}
+	}
+
+	return events, total, nil
+}
@@ -0,0 +1,238 @@
+package repository
+
+import (
+	"context"
+	"core-service/internal/domain"
+	"core-service/internal/logger"
+	"errors"
+	"time"
+
+	"github.com/georgysavva/scany/v2/pgxscan"
+	"github.com/jackc/pgx/v5"
+	"github.com/opentracing/opentracing-go"
+)
+
+const dataErasureColumns = `
+        id, subject_type, subject_id, subject_name, requested_by, status, purge_after, steps,
+        created_at, started_at, completed_at, updated_at
+`
+
+// CreateDataErasure schedules an erasure; returns ErrAlreadyExists if the subject already has an unfinished one
+func (r *PGXRepository) CreateDataErasure(ctx context.Context, erasure domain.DataErasure) (domain.DataErasure, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "repository.CreateDataErasure")
+	defer span.Finish()
+
+	engine := r.engineFactory.Get(ctx)
+	query := `
+        INSERT INTO data_erasures (id, subject_type, subject_id, subject_name, requested_by, status, purge_after, steps, created_at, updated_at)
+        VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+        ON CONFLICT (subject_type, subject_id) WHERE status IN ('scheduled', 'in_progress', 'failed') DO NOTHING
+        RETURNING ` + dataErasureColumns
+
+	var created domain.DataErasure
+	err := pgxscan.Get(ctx, engine, &created, query,
+		uuidToPgtype(erasure.ID),
+		erasure.SubjectType,
+		uuidToPgtype(erasure.SubjectID),
+		erasure.SubjectName,
+		optionalUUIDToPgtype(erasure.RequestedBy),
+		erasure.Status,
+		erasure.PurgeAfter,
+		erasure.Steps,
+		erasure.CreatedAt,
+		erasure.UpdatedAt,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return domain.DataErasure{}, domain.ErrAlreadyExists
+		}
+		logger.Errorf(ctx, "failed to create data erasure: %v", err)
+		return domain.DataErasure{}, err
+	}
+
+	return created, nil
+}
+
+// GetDataErasure retrieves an erasure by ID
+func (r *PGXRepository) GetDataErasure(ctx context.Context, id domain.ID) (domain.DataErasure, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "repository.GetDataErasure")
+	defer span.Finish()
+
+	engine := r.engineFactory.Get(ctx)
+	query := `SELECT ` + dataErasureColumns + ` FROM data_erasures WHERE id = $1`
+
+	var erasure domain.DataErasure
+	err := pgxscan.Get(ctx, engine, &erasure, query, uuidToPgtype(id))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return domain.DataErasure{}, domain.ErrNotFound
+		}
+		logger.Errorf(ctx, "failed to get data erasure: %v", err)
+		return domain.DataErasure{}, err
+	}
+
+	return erasure, nil
+}
+
+// GetActiveDataErasure retrieves the unfinished erasure of an organization or user
+func (r *PGXRepository) GetActiveDataErasure(ctx context.Context, subjectType domain.ErasureSubject, subjectID domain.ID) (domain.DataErasure, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "repository.GetActiveDataErasure")
+	defer span.Finish()
+
+	engine := r.engineFactory.Get(ctx)
+	query := `
+        SELECT ` + dataErasureColumns + `
+        FROM data_erasures
+        WHERE subject_type = $1 AND subject_id = $2 AND status IN ('scheduled', 'in_progress', 'failed')
+    `
+
+	var erasure domain.DataErasure
+	err := pgxscan.Get(ctx, engine, &erasure, query, subjectType, uuidToPgtype(subjectID))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return domain.DataErasure{}, domain.ErrNotFound
+		}
+		logger.Errorf(ctx, "failed to get active data erasure: %v", err)
+		return domain.DataErasure{}, err
+	}
+
+	return erasure, nil
+}
+
+// ClaimDueDataErasures moves erasures whose grace period is over to in_progress and returns them.
+// Failed and stalled erasures not updated since retryBefore are claimed again; concurrent instances skip claimed rows.
+func (r *PGXRepository) ClaimDueDataErasures(ctx context.Context, now, retryBefore time.Time, limit int) ([]domain.DataErasure, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "repository.ClaimDueDataErasures")
+	defer span.Finish()
+
+	engine := r.engineFactory.Get(ctx)
+	query := `
+        WITH due AS (
+            SELECT id FROM data_erasures
+            WHERE (status = 'scheduled' AND purge_after <= $1)
+               OR (status IN ('in_progress', 'failed') AND updated_at <= $2)
+            ORDER BY purge_after
+            LIMIT $3
+            FOR UPDATE SKIP LOCKED
+        )
+        UPDATE data_erasures e
+        SET status = 'in_progress', started_at = COALESCE(e.started_at, $1), updated_at = $1
+        FROM due
+        WHERE e.id = due.id
+        RETURNING e.id, e.subject_type, e.subject_id, e.subject_name, e.requested_by, e.status, e.purge_after, e.steps,
+                  e.created_at, e.started_at, e.completed_at, e.updated_at
+    `
+
+	var erasures []domain.DataErasure
+	err := pgxscan.Select(ctx, engine, &erasures, query, now, retryBefore, limit)
+	if err != nil {
+		logger.Errorf(ctx, "failed to claim due data erasures: %v", err)
+		return nil, err
+	}
+
+	return erasures, nil
+}
+
+// UpdateDataErasureStep records the outcome of one step and returns the erasure with all steps known so far
+func (r *PGXRepository) UpdateDataErasureStep(ctx context.Context, id domain.ID, step domain.ErasureStep, report domain.ErasureStepReport) (domain.DataErasure, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "repository.UpdateDataErasureStep")
+	defer span.Finish()
+
+	engine := r.engineFactory.Get(ctx)
+	query := `
+        UPDATE data_erasures
+        SET steps = jsonb_set(steps, ARRAY[$2::text], $3::jsonb), updated_at = NOW()
+        WHERE id = $1
+        RETURNING ` + dataErasureColumns
+
+	var updated domain.DataErasure
+	err := pgxscan.Get(ctx, engine, &updated, query, uuidToPgtype(id), string(step), report)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return domain.DataErasure{}, domain.ErrNotFound
+		}
+		logger.Errorf(ctx, "failed to update data erasure step: %v", err)
+		return domain.DataErasure{}, err
+	}
+
+	return updated, nil
+}
+
+// FinishDataErasure sets the final status of an erasure that is being executed
+func (r *PGXRepository) FinishDataErasure(ctx context.Context, id domain.ID, status domain.ErasureStatus) (domain.DataErasure, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "repository.FinishDataErasure")
+	defer span.Finish()
+
+	engine := r.engineFactory.Get(ctx)
+	query := `
+        UPDATE data_erasures
+        SET status = $2,
+            completed_at = CASE WHEN $2 = 'completed' THEN NOW() ELSE completed_at END,
+            updated_at = NOW()
+        WHERE id = $1 AND status IN ('in_progress', 'failed')
+        RETURNING ` + dataErasureColumns
+
+	var updated domain.DataErasure
+	err := pgxscan.Get(ctx, engine, &updated, query, uuidToPgtype(id), status)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return domain.DataErasure{}, domain.ErrNotFound
+		}
+		logger.Errorf(ctx, "failed to finish data erasure: %v", err)
+		return domain.DataErasure{}, err
+	}
+
+	return updated, nil
+}
+
+// CancelDataErasure cancels an erasure that has not started yet
+func (r *PGXRepository) CancelDataErasure(ctx context.Context, id domain.ID) error {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "repository.CancelDataErasure")
+	defer span.Finish()
+
+	engine := r.engineFactory.Get(ctx)
+	query := `UPDATE data_erasures SET status = 'cancelled', updated_at = NOW() WHERE id = $1 AND status = 'scheduled'`
+
+	tag, err := engine.Exec(ctx, query, uuidToPgtype(id))
+	if err != nil {
+		logger.Errorf(ctx, "failed to cancel data erasure: %v", err)
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return domain.ErrNotFound
+	}
+
+	return nil
+}
+
+// ListDataErasuresByRequester retrieves erasures requested by a user, newest first
+func (r *PGXRepository) ListDataErasuresByRequester(ctx context.Context, userID domain.ID, limit, offset int) ([]domain.DataErasure, int, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "repository.ListDataErasuresByRequester")
+	defer span.Finish()
+
+	engine := r.engineFactory.Get(ctx)
+
+	var total int
+	countQuery := `SELECT COUNT(*) FROM data_erasures WHERE requested_by = $1`
+	if err := pgxscan.Get(ctx, engine, &total, countQuery, uuidToPgtype(userID)); err != nil {
+		logger.Errorf(ctx, "failed to count data erasures: %v", err)
+		return nil, 0, err
+	}
+
+	query := `
+        SELECT ` + dataErasureColumns + `
+        FROM data_erasures
+        WHERE requested_by = $1
+        ORDER BY created_at DESC
+        LIMIT $2 OFFSET $3
+    `
+
+	var erasures []domain.DataErasure
+	err := pgxscan.Select(ctx, engine, &erasures, query, uuidToPgtype(userID), limit, offset)
+	if err != nil {
+		logger.Errorf(ctx, "failed to list data erasures: %v", err)
+		return nil, 0, err
+	}
+
+	return erasures, total, nil
+}
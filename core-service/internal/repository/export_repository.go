@@ -0,0 +1,271 @@
+package repository
+
+import (
+	"context"
+	"core-service/internal/domain"
+	"core-service/internal/logger"
+	"errors"
+	"time"
+
+	"github.com/georgysavva/scany/v2/pgxscan"
+	"github.com/jackc/pgx/v5"
+	"github.com/opentracing/opentracing-go"
+)
+
+const organizationExportColumns = `
+        id, organization_id, requested_by, status, stage, processed_items, total_items, s3_key, file_size,
+        error_message, created_at, completed_at, expires_at, updated_at
+`
+
+// CreateOrganizationExport creates an export; returns ErrAlreadyExists if the organization already has a running one
+func (r *PGXRepository) CreateOrganizationExport(ctx context.Context, export domain.OrganizationExport) (domain.OrganizationExport, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "repository.CreateOrganizationExport")
+	defer span.Finish()
+
+	engine := r.engineFactory.Get(ctx)
+	query := `
+        INSERT INTO organization_exports (id, organization_id, requested_by, status, stage, created_at, updated_at)
+        VALUES ($1, $2, $3, $4, $5, $6, $7)
+        ON CONFLICT (organization_id) WHERE status IN ('pending', 'in_progress') DO NOTHING
+        RETURNING ` + organizationExportColumns
+
+	var created domain.OrganizationExport
+	err := pgxscan.Get(ctx, engine, &created, query,
+		uuidToPgtype(export.ID),
+		uuidToPgtype(export.OrganizationID),
+		optionalUUIDToPgtype(export.RequestedBy),
+		export.Status,
+		export.Stage,
+		export.CreatedAt,
+		export.UpdatedAt,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return domain.OrganizationExport{}, domain.ErrAlreadyExists
+		}
+		logger.Errorf(ctx, "failed to create organization export: %v", err)
+		return domain.OrganizationExport{}, err
+	}
+
+	return created, nil
+}
+
+// GetOrganizationExport retrieves an export by ID
+func (r *PGXRepository) GetOrganizationExport(ctx context.Context, id domain.ID) (domain.OrganizationExport, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "repository.GetOrganizationExport")
+	defer span.Finish()
+
+	engine := r.engineFactory.Get(ctx)
+	query := `SELECT ` + organizationExportColumns + ` FROM organization_exports WHERE id = $1`
+
+	var export domain.OrganizationExport
+	err := pgxscan.Get(ctx, engine, &export, query, uuidToPgtype(id))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return domain.OrganizationExport{}, domain.ErrNotFound
+		}
+		logger.Errorf(ctx, "failed to get organization export: %v", err)
+		return domain.OrganizationExport{}, err
+	}
+
+	return export, nil
+}
+
+// ListOrganizationExports retrieves exports of an organization, newest first
+func (r *PGXRepository) ListOrganizationExports(ctx context.Context, organizationID domain.ID, limit, offset int) ([]domain.OrganizationExport, int, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "repository.ListOrganizationExports")
+	defer span.Finish()
+
+	engine := r.engineFactory.Get(ctx)
+
+	var total int
+	countQuery := `SELECT COUNT(*) FROM organization_exports WHERE organization_id = $1`
+	if err := pgxscan.Get(ctx, engine, &total, countQuery, uuidToPgtype(organizationID)); err != nil {
+		logger.Errorf(ctx, "failed to count organization exports: %v", err)
+		return nil, 0, err
+	}
+
+	query := `
+        SELECT ` + organizationExportColumns + `
+        FROM organization_exports
+        WHERE organization_id = $1
+        ORDER BY created_at DESC
+        LIMIT $2 OFFSET $3
+    `
+
+	var exports []domain.OrganizationExport
+	err := pgxscan.Select(ctx, engine, &exports, query, uuidToPgtype(organizationID), limit, offset)
+	if err != nil {
+		logger.Errorf(ctx, "failed to list organization exports: %v", err)
+		return nil, 0, err
+	}
+
+	return exports, total, nil
+}
+
+// StartOrganizationExport moves a pending export to in_progress; returns ErrNotFound if it is not pending,
+// so only one consumer builds the archive
+func (r *PGXRepository) StartOrganizationExport(ctx context.Context, id domain.ID) (domain.OrganizationExport, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "repository.StartOrganizationExport")
+	defer span.Finish()
+
+	engine := r.engineFactory.Get(ctx)
+	query := `
+        UPDATE organization_exports
+        SET status = 'in_progress', stage = 'database', processed_items = 0, total_items = 0, updated_at = NOW()
+        WHERE id = $1 AND status = 'pending'
+        RETURNING ` + organizationExportColumns
+
+	var started domain.OrganizationExport
+	err := pgxscan.Get(ctx, engine, &started, query, uuidToPgtype(id))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return domain.OrganizationExport{}, domain.ErrNotFound
+		}
+		logger.Errorf(ctx, "failed to start organization export: %v", err)
+		return domain.OrganizationExport{}, err
+	}
+
+	return started, nil
+}
+
+// UpdateOrganizationExportProgress records the current stage and its progress of a running export
+func (r *PGXRepository) UpdateOrganizationExportProgress(ctx context.Context, id domain.ID, stage domain.ExportStage, processed, total int) error {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "repository.UpdateOrganizationExportProgress")
+	defer span.Finish()
+
+	engine := r.engineFactory.Get(ctx)
+	query := `
+        UPDATE organization_exports
+        SET stage = $2, processed_items = $3, total_items = $4, updated_at = NOW()
+        WHERE id = $1 AND status = 'in_progress'
+    `
+
+	tag, err := engine.Exec(ctx, query, uuidToPgtype(id), stage, processed, total)
+	if err != nil {
+		logger.Errorf(ctx, "failed to update organization export progress: %v", err)
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return domain.ErrNotFound
+	}
+
+	return nil
+}
+
+// CompleteOrganizationExport stores the archive of a running export
+func (r *PGXRepository) CompleteOrganizationExport(ctx context.Context, id domain.ID, s3Key string, fileSize int64, expiresAt time.Time) (domain.OrganizationExport, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "repository.CompleteOrganizationExport")
+	defer span.Finish()
+
+	engine := r.engineFactory.Get(ctx)
+	query := `
+        UPDATE organization_exports
+        SET status = 'completed', stage = 'done', processed_items = total_items, s3_key = $2, file_size = $3,
+            expires_at = $4, completed_at = NOW(), updated_at = NOW()
+        WHERE id = $1 AND status = 'in_progress'
+        RETURNING ` + organizationExportColumns
+
+	var completed domain.OrganizationExport
+	err := pgxscan.Get(ctx, engine, &completed, query, uuidToPgtype(id), s3Key, fileSize, expiresAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return domain.OrganizationExport{}, domain.ErrNotFound
+		}
+		logger.Errorf(ctx, "failed to complete organization export: %v", err)
+		return domain.OrganizationExport{}, err
+	}
+
+	return completed, nil
+}
+
+// FailOrganizationExport marks a running export failed; returns ErrNotFound if it has already finished
+func (r *PGXRepository) FailOrganizationExport(ctx context.Context, id domain.ID, errorMessage string) (domain.OrganizationExport, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "repository.FailOrganizationExport")
+	defer span.Finish()
+
+	engine := r.engineFactory.Get(ctx)
+	query := `
+        UPDATE organization_exports
+        SET status = 'failed', error_message = $2, completed_at = NOW(), updated_at = NOW()
+        WHERE id = $1 AND status IN ('pending', 'in_progress')
+        RETURNING ` + organizationExportColumns
+
+	var failed domain.OrganizationExport
+	err := pgxscan.Get(ctx, engine, &failed, query, uuidToPgtype(id), errorMessage)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return domain.OrganizationExport{}, domain.ErrNotFound
+		}
+		logger.Errorf(ctx, "failed to fail organization export: %v", err)
+		return domain.OrganizationExport{}, err
+	}
+
+	return failed, nil
+}
+
+// FailStalledOrganizationExports marks failed the running exports not updated since the given time and returns them
+func (r *PGXRepository) FailStalledOrganizationExports(ctx context.Context, updatedBefore time.Time, errorMessage string) ([]domain.OrganizationExport, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "repository.FailStalledOrganizationExports")
+	defer span.Finish()
+
+	engine := r.engineFactory.Get(ctx)
+	query := `
+        UPDATE organization_exports
+        SET status = 'failed', error_message = $2, completed_at = NOW(), updated_at = NOW()
+        WHERE status IN ('pending', 'in_progress') AND updated_at <= $1
+        RETURNING ` + organizationExportColumns
+
+	var failed []domain.OrganizationExport
+	err := pgxscan.Select(ctx, engine, &failed, query, updatedBefore, errorMessage)
+	if err != nil {
+		logger.Errorf(ctx, "failed to fail stalled organization exports: %v", err)
+		return nil, err
+	}
+
+	return failed, nil
+}
+
+// ListExpiredOrganizationExports retrieves completed exports whose archive is past its expiry
+func (r *PGXRepository) ListExpiredOrganizationExports(ctx context.Context, now time.Time, limit int) ([]domain.OrganizationExport, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "repository.ListExpiredOrganizationExports")
+	defer span.Finish()
+
+	engine := r.engineFactory.Get(ctx)
+	query := `
+        SELECT ` + organizationExportColumns + `
+        FROM organization_exports
+        WHERE status = 'completed' AND expires_at <= $1
+        ORDER BY expires_at
+        LIMIT $2
+    `
+
+	var exports []domain.OrganizationExport
+	err := pgxscan.Select(ctx, engine, &exports, query, now, limit)
+	if err != nil {
+		logger.Errorf(ctx, "failed to list expired organization exports: %v", err)
+		return nil, err
+	}
+
+	return exports, nil
+}
+
+// ExpireOrganizationExport marks a completed export expired after its archive has been deleted
+func (r *PGXRepository) ExpireOrganizationExport(ctx context.Context, id domain.ID) error {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "repository.ExpireOrganizationExport")
+	defer span.Finish()
+
+	engine := r.engineFactory.Get(ctx)
+	query := `UPDATE organization_exports SET status = 'expired', s3_key = NULL, updated_at = NOW() WHERE id = $1 AND status = 'completed'`
+
+	tag, err := engine.Exec(ctx, query, uuidToPgtype(id))
+	if err != nil {
+		logger.Errorf(ctx, "failed to expire organization export: %v", err)
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return domain.ErrNotFound
+	}
+
+	return nil
+}
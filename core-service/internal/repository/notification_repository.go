@@ -0,0 +1,165 @@
+package repository
+
+import (
+	"context"
+	"core-service/internal/domain"
+	"core-service/internal/logger"
+	"errors"
+	"time"
+
+	"github.com/georgysavva/scany/v2/pgxscan"
+	"github.com/jackc/pgx/v5"
+	"github.com/opentracing/opentracing-go"
+)
+
+type notificationPreferencesRow struct {
+	UserID          domain.ID `db:"user_id"`
+	TelegramEnabled bool      `db:"telegram_enabled"`
+	MutedEvents     []string  `db:"muted_events"`
+	UpdatedAt       time.Time `db:"updated_at"`
+}
+
+func (r notificationPreferencesRow) toDomain() domain.NotificationPreferences {
+	muted := make([]domain.NotificationEventType, 0, len(r.MutedEvents))
+	for _, event := range r.MutedEvents {
+		muted = append(muted, domain.NotificationEventType(event))
+	}
+	return domain.NotificationPreferences{
+		UserID:          r.UserID,
+		TelegramEnabled: r.TelegramEnabled,
+		MutedEvents:     muted,
+		UpdatedAt:       r.UpdatedAt,
+	}
+}
+
+// GetNotificationPreferences retrieves notification preferences of a user; defaults if none saved
+func (r *PGXRepository) GetNotificationPreferences(ctx context.Context, userID domain.ID) (domain.NotificationPreferences, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "repository.GetNotificationPreferences")
+	defer span.Finish()
+
+	engine := r.engineFactory.Get(ctx)
+	query := `
+        SELECT user_id, telegram_enabled, muted_events, updated_at
+        FROM notification_preferences
+        WHERE user_id = $1
+    `
+
+	var row notificationPreferencesRow
+	err := pgxscan.Get(ctx, engine, &row, query, uuidToPgtype(userID))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return domain.DefaultNotificationPreferences(userID), nil
+		}
+		logger.Errorf(ctx, "failed to get notification preferences: %v", err)
+		return domain.NotificationPreferences{}, err
+	}
+
+	return row.toDomain(), nil
+}
+
+// UpsertNotificationPreferences saves notification preferences of a user
+func (r *PGXRepository) UpsertNotificationPreferences(ctx context.Context, prefs domain.NotificationPreferences) (domain.NotificationPreferences, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "repository.UpsertNotificationPreferences")
+	defer span.Finish()
+
+	engine := r.engineFactory.Get(ctx)
+	query := `
+        INSERT INTO notification_preferences (user_id, telegram_enabled, muted_events, updated_at)
+        VALUES ($1, $2, $3, $4)
+        ON CONFLICT (user_id) DO UPDATE
+        SET telegram_enabled = EXCLUDED.telegram_enabled,
+            muted_events = EXCLUDED.muted_events,
+            updated_at = EXCLUDED.updated_at
+        RETURNING user_id, telegram_enabled, muted_events, updated_at
+    `
+
+	muted := make([]string, 0, len(prefs.MutedEvents))
+	for _, event := range prefs.MutedEvents {
+		muted = append(muted, string(event))
+	}
+
+	var row notificationPreferencesRow
+	err := pgxscan.Get(ctx, engine, &row, query,
+		uuidToPgtype(prefs.UserID),
+		prefs.TelegramEnabled,
+		muted,
+		prefs.UpdatedAt,
+	)
+	if err != nil {
+		logger.Errorf(ctx, "failed to save notification preferences: %v", err)
+		return domain.NotificationPreferences{}, err
+	}
+
+	return row.toDomain(), nil
+}
+
+// ClaimNotificationDelivery records a delivery attempt; returns false if the event was already handled for the user.
+// A failed delivery can be claimed again, as can a pending one created before staleBefore: its handler died
+// before recording the result.
+func (r *PGXRepository) ClaimNotificationDelivery(ctx context.Context, delivery domain.NotificationDelivery, staleBefore time.Time) (bool, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "repository.ClaimNotificationDelivery")
+	defer span.Finish()
+
+	engine := r.engineFactory.Get(ctx)
+	query := `
+        INSERT INTO notification_deliveries (event_id, user_id, event_type, status, error, created_at)
+        VALUES ($1, $2, $3, $4, $5, $6)
+        ON CONFLICT (event_id, user_id) DO UPDATE
+        SET status = EXCLUDED.status, error = EXCLUDED.error, created_at = EXCLUDED.created_at
+        WHERE notification_deliveries.status = 'failed'
+           OR (notification_deliveries.status = 'pending' AND notification_deliveries.created_at < $7)
+    `
+
+	tag, err := engine.Exec(ctx, query,
+		delivery.EventID,
+		uuidToPgtype(delivery.UserID),
+		delivery.EventType,
+		delivery.Status,
+		delivery.Error,
+		delivery.CreatedAt,
+		staleBefore,
+	)
+	if err != nil {
+		logger.Errorf(ctx, "failed to claim notification delivery: %v", err)
+		return false, err
+	}
+
+	return tag.RowsAffected() == 1, nil
+}
+
+// UpdateNotificationDelivery updates the status of a delivery
+func (r *PGXRepository) UpdateNotificationDelivery(ctx context.Context, eventID string, userID domain.ID, status domain.NotificationDeliveryStatus, errorMessage string) error {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "repository.UpdateNotificationDelivery")
+	defer span.Finish()
+
+	engine := r.engineFactory.Get(ctx)
+	query := `UPDATE notification_deliveries SET status = $3, error = $4 WHERE event_id = $1 AND user_id = $2`
+
+	if _, err := engine.Exec(ctx, query, eventID, uuidToPgtype(userID), status, errorMessage); err != nil {
+		logger.Errorf(ctx, "failed to update notification delivery: %v", err)
+		return err
+	}
+
+	return nil
+}
+
+// CountSentNotifications counts notifications sent to a user since the given time
+func (r *PGXRepository) CountSentNotifications(ctx context.Context, userID domain.ID, since time.Time) (int, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "repository.CountSentNotifications")
+	defer span.Finish()
+
+	engine := r.engineFactory.Get(ctx)
+	query := `
+        SELECT COUNT(*)
+        FROM notification_deliveries
+        WHERE user_id = $1 AND status IN ('pending', 'sent') AND created_at >= $2
+    `
+
+	var count int
+	if err := pgxscan.Get(ctx, engine, &count, query, uuidToPgtype(userID), since); err != nil {
+		logger.Errorf(ctx, "failed to count sent notifications: %v", err)
+		return 0, err
+	}
+
+	return count, nil
+}
@@ -129,6 +129,60 @@ func (r *PGXRepository) DeleteOrganization(ctx context.Context, id domain.ID) er
 	return nil
 }
 
+// RestoreOrganization undoes a soft delete
+func (r *PGXRepository) RestoreOrganization(ctx context.Context, id domain.ID) error {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "repository.RestoreOrganization")
+	defer span.Finish()
+
+	engine := r.engineFactory.Get(ctx)
+	query := `UPDATE organizations SET deleted_at = NULL, updated_at = NOW() WHERE id = $1 AND deleted_at IS NOT NULL`
+
+	tag, err := engine.Exec(ctx, query, uuidToPgtype(id))
+	if err != nil {
+		logger.Errorf(ctx, "failed to restore organization: %v", err)
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return domain.ErrNotFound
+	}
+
+	return nil
+}
+
+// organizationPurgeQueries delete everything an organization owns; memberships go before roles,
+// because a role cannot be deleted while it is assigned
+var organizationPurgeQueries = []string{
+	`DELETE FROM generated_contracts WHERE organization_id = $1`,
+	`DELETE FROM contract_templates WHERE organization_id = $1`,
+	`DELETE FROM documents WHERE organization_id = $1`,
+	`DELETE FROM notes WHERE organization_id = $1`,
+	`DELETE FROM invitations WHERE organization_id = $1`,
+	`DELETE FROM organization_members WHERE organization_id = $1`,
+	`DELETE FROM organization_roles WHERE organization_id = $1`,
+	`DELETE FROM organizations WHERE id = $1`,
+}
+
+// PurgeOrganization permanently deletes an organization with all its records and returns how many rows were deleted.
+// Must run in a transaction.
+func (r *PGXRepository) PurgeOrganization(ctx context.Context, id domain.ID) (int, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "repository.PurgeOrganization")
+	defer span.Finish()
+
+	engine := r.engineFactory.Get(ctx)
+
+	deleted := 0
+	for _, query := range organizationPurgeQueries {
+		tag, err := engine.Exec(ctx, query, uuidToPgtype(id))
+		if err != nil {
+			logger.Errorf(ctx, "failed to purge organization: %v", err)
+			return 0, err
+		}
+		deleted += int(tag.RowsAffected())
+	}
+
+	return deleted, nil
+}
+
 // GetOrganizationsByUserID retrieves all organizations where the user is a member
 func (r *PGXRepository) GetOrganizationsByUserID(ctx context.Context, userID domain.ID) ([]domain.Organization, error) {
 	span, ctx := opentracing.StartSpanFromContext(ctx, "repository.GetOrganizationsByUserID")
@@ -4,6 +4,7 @@ import (
 	"context"
 	"core-service/internal/db"
 	"core-service/internal/domain"
+	"time"
 
 	"github.com/jackc/pgx/v5/pgtype"
 )
@@ -17,6 +18,11 @@ type Repository interface {
 	NoteRepository
 	ContractTemplateRepository
 	GeneratedContractRepository
+	NotificationRepository
+	RoleRepository
+	AuditRepository
+	ErasureRepository
+	ExportRepository
 }
 
 // OrganizationRepository defines methods for organization data access
@@ -26,6 +32,8 @@ type OrganizationRepository interface {
 	GetOrganizationsByUserID(ctx context.Context, userID domain.ID) ([]domain.Organization, error)
 	UpdateOrganization(ctx context.Context, org domain.Organization) (domain.Organization, error)
 	DeleteOrganization(ctx context.Context, id domain.ID) error
+	RestoreOrganization(ctx context.Context, id domain.ID) error
+	PurgeOrganization(ctx context.Context, id domain.ID) (int, error)
 }
 
 // UserRepository defines methods for user data access
@@ -37,6 +45,10 @@ type UserRepository interface {
 	UpdateUser(ctx context.Context, user domain.User) (domain.User, error)
 	UpdateUserRole(ctx context.Context, id domain.ID, role domain.UserRole) error
 	DeactivateUser(ctx context.Context, id domain.ID) error
+	ListOrganizationAdmins(ctx context.Context, organizationID domain.ID) ([]domain.User, error)
+	LockOrganizationAdmins(ctx context.Context, organizationID domain.ID) (int, error)
+	LeaveAllOrganizations(ctx context.Context, userID domain.ID) error
+	PurgeUser(ctx context.Context, id domain.ID) (int, error)
 }
 
 // InvitationRepository defines methods for invitation data access
@@ -45,7 +57,10 @@ type InvitationRepository interface {
 	GetInvitationByToken(ctx context.Context, token string) (domain.Invitation, error)
 	GetInvitationByID(ctx context.Context, id domain.ID) (domain.Invitation, error)
 	ListInvitations(ctx context.Context, organizationID domain.ID, limit, offset int) ([]domain.Invitation, int, error)
-	MarkInvitationAsUsed(ctx context.Context, id domain.ID) error
+	UseInvitation(ctx context.Context, id, userID domain.ID) error
+	RevokeInvitation(ctx context.Context, id domain.ID) error
+	RegenerateInvitation(ctx context.Context, invitation domain.Invitation) (domain.Invitation, error)
+	ListInvitationUses(ctx context.Context, invitationID domain.ID, limit, offset int) ([]domain.InvitationUse, int, error)
 	DeleteInvitation(ctx context.Context, id domain.ID) error
 }
 
@@ -84,6 +99,58 @@ type GeneratedContractRepository interface {
 	DeleteContract(ctx context.Context, id domain.ID) error
 }
 
+// NotificationRepository defines methods for notification preferences and delivery log access
+type NotificationRepository interface {
+	GetNotificationPreferences(ctx context.Context, userID domain.ID) (domain.NotificationPreferences, error)
+	UpsertNotificationPreferences(ctx context.Context, prefs domain.NotificationPreferences) (domain.NotificationPreferences, error)
+	ClaimNotificationDelivery(ctx context.Context, delivery domain.NotificationDelivery, staleBefore time.Time) (bool, error)
+	UpdateNotificationDelivery(ctx context.Context, eventID string, userID domain.ID, status domain.NotificationDeliveryStatus, errorMessage string) error
+	CountSentNotifications(ctx context.Context, userID domain.ID, since time.Time) (int, error)
+}
+
+// RoleRepository defines methods for custom organization role data access
+type RoleRepository interface {
+	CreateCustomRole(ctx context.Context, role domain.CustomRole) (domain.CustomRole, error)
+	GetCustomRole(ctx context.Context, id domain.ID) (domain.CustomRole, error)
+	ListCustomRoles(ctx context.Context, organizationID domain.ID) ([]domain.CustomRole, error)
+	UpdateCustomRole(ctx context.Context, role domain.CustomRole) (domain.CustomRole, error)
+	DeleteCustomRole(ctx context.Context, id domain.ID) error
+	CountCustomRoleMembers(ctx context.Context, id domain.ID) (int, error)
+	CountCustomRoleInvitations(ctx context.Context, id domain.ID) (int, error)
+}
+
+// AuditRepository defines methods for the append-only audit log
+type AuditRepository interface {
+	CreateAuditEvent(ctx context.Context, event domain.AuditEvent) (bool, error)
+	ListAuditEvents(ctx context.Context, filter domain.AuditEventFilter, limit, offset int) ([]domain.AuditEvent, int, error)
+}
+
+// ErasureRepository defines methods for organization and account erasure requests
+type ErasureRepository interface {
+	CreateDataErasure(ctx context.Context, erasure domain.DataErasure) (domain.DataErasure, error)
+	GetDataErasure(ctx context.Context, id domain.ID) (domain.DataErasure, error)
+	GetActiveDataErasure(ctx context.Context, subjectType domain.ErasureSubject, subjectID domain.ID) (domain.DataErasure, error)
+	ClaimDueDataErasures(ctx context.Context, now, retryBefore time.Time, limit int) ([]domain.DataErasure, error)
+	UpdateDataErasureStep(ctx context.Context, id domain.ID, step domain.ErasureStep, report domain.ErasureStepReport) (domain.DataErasure, error)
+	FinishDataErasure(ctx context.Context, id domain.ID, status domain.ErasureStatus) (domain.DataErasure, error)
+	CancelDataErasure(ctx context.Context, id domain.ID) error
+	ListDataErasuresByRequester(ctx context.Context, userID domain.ID, limit, offset int) ([]domain.DataErasure, int, error)
+}
+
+// ExportRepository defines methods for organization data exports
+type ExportRepository interface {
+	CreateOrganizationExport(ctx context.Context, export domain.OrganizationExport) (domain.OrganizationExport, error)
+	GetOrganizationExport(ctx context.Context, id domain.ID) (domain.OrganizationExport, error)
+	ListOrganizationExports(ctx context.Context, organizationID domain.ID, limit, offset int) ([]domain.OrganizationExport, int, error)
+	StartOrganizationExport(ctx context.Context, id domain.ID) (domain.OrganizationExport, error)
+	UpdateOrganizationExportProgress(ctx context.Context, id domain.ID, stage domain.ExportStage, processed, total int) error
+	CompleteOrganizationExport(ctx context.Context, id domain.ID, s3Key string, fileSize int64, expiresAt time.Time) (domain.OrganizationExport, error)
+	FailOrganizationExport(ctx context.Context, id domain.ID, errorMessage string) (domain.OrganizationExport, error)
+	FailStalledOrganizationExports(ctx context.Context, updatedBefore time.Time, errorMessage string) ([]domain.OrganizationExport, error)
+	ListExpiredOrganizationExports(ctx context.Context, now time.Time, limit int) ([]domain.OrganizationExport, error)
+	ExpireOrganizationExport(ctx context.Context, id domain.ID) error
+}
+
 // PGXRepository implements Repository using PostgreSQL
 type PGXRepository struct {
 	engineFactory db.EngineFactory
@@ -105,3 +172,11 @@ func uuidToPgtype(id domain.ID) pgtype.UUID {
 		Valid: true,
 	}
 }
+
+// Helper function to convert an optional domain.ID to pgtype.UUID; nil becomes NULL
+func optionalUUIDToPgtype(id *domain.ID) pgtype.UUID {
+	if id == nil {
+		return pgtype.UUID{}
+	}
+	return uuidToPgtype(*id)
+}
@@ -0,0 +1,190 @@
+package repository
+
+import (
+	"context"
+	"core-service/internal/domain"
+	"core-service/internal/logger"
+	"errors"
+
+	"github.com/georgysavva/scany/v2/pgxscan"
+	"github.com/jackc/pgx/v5"
+	"github.com/opentracing/opentracing-go"
+)
+
+func permissionsToStrings(permissions []domain.Permission) []string {
+	values := make([]string, 0, len(permissions))
+	for _, p := range permissions {
+		values = append(values, string(p))
+	}
+	return values
+}
+
+// CreateCustomRole creates a custom role of an organization
+func (r *PGXRepository) CreateCustomRole(ctx context.Context, role domain.CustomRole) (domain.CustomRole, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "repository.CreateCustomRole")
+	defer span.Finish()
+
+	engine := r.engineFactory.Get(ctx)
+	query := `
+        INSERT INTO organization_roles (id, organization_id, name, description, permissions, created_at, updated_at)
+        VALUES ($1, $2, $3, $4, $5, $6, $7)
+        RETURNING id, organization_id, name, description, permissions, created_at, updated_at
+    `
+
+	var created domain.CustomRole
+	err := pgxscan.Get(ctx, engine, &created, query,
+		uuidToPgtype(role.ID),
+		uuidToPgtype(role.OrganizationID),
+		role.Name,
+		role.Description,
+		permissionsToStrings(role.Permissions),
+		role.CreatedAt,
+		role.UpdatedAt,
+	)
+	if err != nil {
+		logger.Errorf(ctx, "failed to create custom role: %v", err)
+		return domain.CustomRole{}, err
+	}
+
+	return created, nil
+}
+
+// GetCustomRole retrieves a custom role by ID
+func (r *PGXRepository) GetCustomRole(ctx context.Context, id domain.ID) (domain.CustomRole, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "repository.GetCustomRole")
+	defer span.Finish()
+
+	engine := r.engineFactory.Get(ctx)
+	query := `
+        SELECT id, organization_id, name, description, permissions, created_at, updated_at
+        FROM organization_roles
+        WHERE id = $1
+    `
+
+	var role domain.CustomRole
+	err := pgxscan.Get(ctx, engine, &role, query, uuidToPgtype(id))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return domain.CustomRole{}, domain.ErrNotFound
+		}
+		logger.Errorf(ctx, "failed to get custom role: %v", err)
+		return domain.CustomRole{}, err
+	}
+
+	return role, nil
+}
+
+// ListCustomRoles lists custom roles of an organization ordered by name
+func (r *PGXRepository) ListCustomRoles(ctx context.Context, organizationID domain.ID) ([]domain.CustomRole, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "repository.ListCustomRoles")
+	defer span.Finish()
+
+	engine := r.engineFactory.Get(ctx)
+	query := `
+        SELECT id, organization_id, name, description, permissions, created_at, updated_at
+        FROM organization_roles
+        WHERE organization_id = $1
+        ORDER BY name
+    `
+
+	var roles []domain.CustomRole
+	err := pgxscan.Select(ctx, engine, &roles, query, uuidToPgtype(organizationID))
+	if err != nil {
+		logger.Errorf(ctx, "failed to list custom roles: %v", err)
+		return nil, err
+	}
+
+	return roles, nil
+}
+
+// UpdateCustomRole updates name, description and permissions of a custom role
+func (r *PGXRepository) UpdateCustomRole(ctx context.Context, role domain.CustomRole) (domain.CustomRole, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "repository.UpdateCustomRole")
+	defer span.Finish()
+
+	engine := r.engineFactory.Get(ctx)
+	query := `
+        UPDATE organization_roles
+        SET name = $2, description = $3, permissions = $4, updated_at = $5
+        WHERE id = $1
+        RETURNING id, organization_id, name, description, permissions, created_at, updated_at
+    `
+
+	var updated domain.CustomRole
+	err := pgxscan.Get(ctx, engine, &updated, query,
+		uuidToPgtype(role.ID),
+		role.Name,
+		role.Description,
+		permissionsToStrings(role.Permissions),
+		role.UpdatedAt,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return domain.CustomRole{}, domain.ErrNotFound
+		}
+		logger.Errorf(ctx, "failed to update custom role: %v", err)
+		return domain.CustomRole{}, err
+	}
+
+	return updated, nil
+}
+
+// DeleteCustomRole deletes a custom role
+func (r *PGXRepository) DeleteCustomRole(ctx context.Context, id domain.ID) error {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "repository.DeleteCustomRole")
+	defer span.Finish()
+
+	engine := r.engineFactory.Get(ctx)
+	query := `DELETE FROM organization_roles WHERE id = $1`
+
+	tag, err := engine.Exec(ctx, query, uuidToPgtype(id))
+	if err != nil {
+		logger.Errorf(ctx, "failed to delete custom role: %v", err)
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return domain.ErrNotFound
+	}
+
+	return nil
+}
+
+// CountCustomRoleMembers counts memberships the custom role is assigned to
+func (r *PGXRepository) CountCustomRoleMembers(ctx context.Context, id domain.ID) (int, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "repository.CountCustomRoleMembers")
+	defer span.Finish()
+
+	engine := r.engineFactory.Get(ctx)
+	query := `SELECT COUNT(*) FROM organization_members WHERE custom_role_id = $1`
+
+	var count int
+	err := pgxscan.Get(ctx, engine, &count, query, uuidToPgtype(id))
+	if err != nil {
+		logger.Errorf(ctx, "failed to count custom role members: %v", err)
+		return 0, err
+	}
+
+	return count, nil
+}
+
+// CountCustomRoleInvitations counts invitations that pre-assign the custom role, including expired
+// and revoked ones: those can be regenerated and would then admit members with the custom role dropped
+func (r *PGXRepository) CountCustomRoleInvitations(ctx context.Context, id domain.ID) (int, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "repository.CountCustomRoleInvitations")
+	defer span.Finish()
+
+	engine := r.engineFactory.Get(ctx)
+	query := `
+        SELECT COUNT(*) FROM invitations
+        WHERE custom_role_id = $1
+    `
+
+	var count int
+	err := pgxscan.Get(ctx, engine, &count, query, uuidToPgtype(id))
+	if err != nil {
+		logger.Errorf(ctx, "failed to count custom role invitations: %v", err)
+		return 0, err
+	}
+
+	return count, nil
+}
@@ -124,11 +124,15 @@ func (r *PGXRepository) ListUsers(ctx context.Context, organizationID domain.ID,
             om.role as "organization_member.role",
             om.status as "organization_member.status",
             om.joined_at as "organization_member.joined_at",
-            om.updated_at as "organization_member.updated_at"
+            om.updated_at as "organization_member.updated_at",
+            om.custom_role_id as "organization_member.custom_role_id",
+            COALESCE(cr.name, '') as "organization_member.custom_role_name",
+            cr.permissions as "organization_member.custom_permissions"
         FROM users u
         INNER JOIN organization_members om ON u.id = om.user_id
+        LEFT JOIN organization_roles cr ON cr.id = om.custom_role_id
         WHERE om.organization_id = $1
-        ORDER BY om.joined_at DESC
+        ORDER BY om.joined_at DESC, om.id
         LIMIT $2 OFFSET $3
     `
 
@@ -216,6 +220,103 @@ func (r *PGXRepository) DeactivateUser(ctx context.Context, id domain.ID) error
 	return nil
 }
 
+// LeaveAllOrganizations ends all active memberships of a user
+func (r *PGXRepository) LeaveAllOrganizations(ctx context.Context, userID domain.ID) error {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "repository.LeaveAllOrganizations")
+	defer span.Finish()
+
+	engine := r.engineFactory.Get(ctx)
+	query := `UPDATE organization_members SET status = $2, updated_at = NOW() WHERE user_id = $1 AND status = $3`
+
+	_, err := engine.Exec(ctx, query, uuidToPgtype(userID), domain.UserStatusLeft, domain.UserStatusActive)
+	if err != nil {
+		logger.Errorf(ctx, "failed to leave organizations: %v", err)
+		return err
+	}
+
+	return nil
+}
+
+// PurgeUser permanently deletes a user with memberships, notification settings and invitation history
+// and returns how many rows were deleted. Must run in a transaction.
+func (r *PGXRepository) PurgeUser(ctx context.Context, id domain.ID) (int, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "repository.PurgeUser")
+	defer span.Finish()
+
+	engine := r.engineFactory.Get(ctx)
+	queries := []string{
+		`DELETE FROM organization_members WHERE user_id = $1`,
+		`DELETE FROM users WHERE id = $1`,
+	}
+
+	deleted := 0
+	for _, query := range queries {
+		tag, err := engine.Exec(ctx, query, uuidToPgtype(id))
+		if err != nil {
+			logger.Errorf(ctx, "failed to purge user: %v", err)
+			return 0, err
+		}
+		deleted += int(tag.RowsAffected())
+	}
+
+	return deleted, nil
+}
+
+// ListOrganizationAdmins retrieves active admins of an organization
+func (r *PGXRepository) ListOrganizationAdmins(ctx context.Context, organizationID domain.ID) ([]domain.User, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "repository.ListOrganizationAdmins")
+	defer span.Finish()
+
+	engine := r.engineFactory.Get(ctx)
+	query := `
+        SELECT u.id, u.telegram_id, u.first_name, u.last_name, u.is_active, u.registration_completed, u.created_at, u.updated_at
+        FROM users u
+        JOIN organization_members om ON om.user_id = u.id
+        WHERE om.organization_id = $1 AND om.role = $2 AND om.status = $3 AND u.is_active
+        ORDER BY om.joined_at
+    `
+
+	var users []domain.User
+	err := pgxscan.Select(ctx, engine, &users, query, uuidToPgtype(organizationID), domain.UserRoleAdmin, domain.UserStatusActive)
+	if err != nil {
+		logger.Errorf(ctx, "failed to list organization admins: %v", err)
+		return nil, err
+	}
+
+	return users, nil
+}
+
+// LockOrganizationAdmins counts active admins of an organization and locks their memberships
+// until the end of the transaction, so concurrent demotions cannot leave the organization without an admin
+func (r *PGXRepository) LockOrganizationAdmins(ctx context.Context, organizationID domain.ID) (int, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "repository.LockOrganizationAdmins")
+	defer span.Finish()
+
+	engine := r.engineFactory.Get(ctx)
+	query := `
+        SELECT COUNT(*) FROM (
+            SELECT id FROM organization_members
+            WHERE organization_id = $1 AND role = $2 AND status = $3
+            FOR UPDATE
+        ) admins
+    `
+
+	var count int
+	err := pgxscan.Get(ctx, engine, &count, query, uuidToPgtype(organizationID), domain.UserRoleAdmin, domain.UserStatusActive)
+	if err != nil {
+		logger.Errorf(ctx, "failed to lock organization admins: %v", err)
+		return 0, err
+	}
+
+	return count, nil
+}
+
+// invitationColumns selects an invitation with the name of its pre-assigned custom role
+const invitationColumns = `
+        i.id, i.organization_id, i.token, i.role, i.custom_role_id, COALESCE(cr.name, '') AS custom_role_name,
+        i.max_uses, i.uses_count, i.created_by, i.expires_at, i.used_at, i.revoked_at, i.created_at
+`
+
 // CreateInvitation inserts a new invitation
 func (r *PGXRepository) CreateInvitation(ctx context.Context, invitation domain.Invitation) (domain.Invitation, error) {
 	span, ctx := opentracing.StartSpanFromContext(ctx, "repository.CreateInvitation")
@@ -223,9 +324,14 @@ func (r *PGXRepository) CreateInvitation(ctx context.Context, invitation domain.
 
 	engine := r.engineFactory.Get(ctx)
 	query := `
-        INSERT INTO invitations (id, organization_id, token, role, expires_at, created_at)
-        VALUES ($1, $2, $3, $4, $5, $6)
-        RETURNING id, organization_id, token, role, expires_at, used_at, created_at
+        WITH i AS (
+            INSERT INTO invitations (id, organization_id, token, role, custom_role_id, max_uses, created_by, expires_at, created_at)
+            VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+            RETURNING *
+        )
+        SELECT ` + invitationColumns + `
+        FROM i
+        LEFT JOIN organization_roles cr ON cr.id = i.custom_role_id
     `
 
 	var created domain.Invitation
@@ -234,6 +340,9 @@ func (r *PGXRepository) CreateInvitation(ctx context.Context, invitation domain.
 		uuidToPgtype(invitation.OrganizationID),
 		invitation.Token,
 		invitation.Role,
+		optionalUUIDToPgtype(invitation.CustomRoleID),
+		invitation.MaxUses,
+		optionalUUIDToPgtype(invitation.CreatedBy),
 		invitation.ExpiresAt,
 		invitation.CreatedAt,
 	)
@@ -252,9 +361,10 @@ func (r *PGXRepository) GetInvitationByToken(ctx context.Context, token string)
 
 	engine := r.engineFactory.Get(ctx)
 	query := `
-        SELECT id, organization_id, token, role, expires_at, used_at, created_at
-        FROM invitations
-        WHERE token = $1
+        SELECT ` + invitationColumns + `
+        FROM invitations i
+        LEFT JOIN organization_roles cr ON cr.id = i.custom_role_id
+        WHERE i.token = $1
     `
 
 	var invitation domain.Invitation
@@ -292,10 +402,11 @@ func (r *PGXRepository) ListInvitations(ctx context.Context, organizationID doma
 
 	// Get invitations
 	query := `
-		SELECT id, organization_id, token, role, expires_at, used_at, created_at
-		FROM invitations
-		WHERE organization_id = $1
-		ORDER BY created_at DESC
+		SELECT ` + invitationColumns + `
+		FROM invitations i
+		LEFT JOIN organization_roles cr ON cr.id = i.custom_role_id
+		WHERE i.organization_id = $1
+		ORDER BY i.created_at DESC
 		LIMIT $2 OFFSET $3
 	`
 
@@ -317,17 +428,47 @@ func (r *PGXRepository) ListInvitations(ctx context.Context, organizationID doma
 	return invitations, total, nil
 }
 
-// MarkInvitationAsUsed marks an invitation as used
-func (r *PGXRepository) MarkInvitationAsUsed(ctx context.Context, id domain.ID) error {
-	span, ctx := opentracing.StartSpanFromContext(ctx, "repository.MarkInvitationAsUsed")
+// UseInvitation counts a use of a valid invitation and records who joined through it.
+// Returns domain.ErrNotFound if the invitation is revoked, expired or has no uses left.
+func (r *PGXRepository) UseInvitation(ctx context.Context, id, userID domain.ID) error {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "repository.UseInvitation")
+	defer span.Finish()
+
+	engine := r.engineFactory.Get(ctx)
+	query := `
+        WITH used AS (
+            UPDATE invitations
+            SET uses_count = uses_count + 1, used_at = NOW()
+            WHERE id = $1 AND revoked_at IS NULL AND expires_at > NOW() AND uses_count < max_uses
+            RETURNING id
+        )
+        INSERT INTO invitation_uses (invitation_id, user_id)
+        SELECT id, $2 FROM used
+    `
+
+	tag, err := engine.Exec(ctx, query, uuidToPgtype(id), uuidToPgtype(userID))
+	if err != nil {
+		logger.Errorf(ctx, "failed to use invitation: %v", err)
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return domain.ErrNotFound
+	}
+
+	return nil
+}
+
+// RevokeInvitation marks an invitation as revoked; its link stops working but the history is kept
+func (r *PGXRepository) RevokeInvitation(ctx context.Context, id domain.ID) error {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "repository.RevokeInvitation")
 	defer span.Finish()
 
 	engine := r.engineFactory.Get(ctx)
-	query := `UPDATE invitations SET used_at = NOW() WHERE id = $1 AND used_at IS NULL`
+	query := `UPDATE invitations SET revoked_at = NOW() WHERE id = $1 AND revoked_at IS NULL`
 
 	tag, err := engine.Exec(ctx, query, uuidToPgtype(id))
 	if err != nil {
-		logger.Errorf(ctx, "failed to mark invitation as used: %v", err)
+		logger.Errorf(ctx, "failed to revoke invitation: %v", err)
 		return err
 	}
 	if tag.RowsAffected() == 0 {
@@ -337,6 +478,76 @@ func (r *PGXRepository) MarkInvitationAsUsed(ctx context.Context, id domain.ID)
 	return nil
 }
 
+// RegenerateInvitation saves the new token, expiry and creation time of a regenerated invitation
+// and reopens it: the old link stops working, the use counter starts over, the usage history is kept
+func (r *PGXRepository) RegenerateInvitation(ctx context.Context, invitation domain.Invitation) (domain.Invitation, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "repository.RegenerateInvitation")
+	defer span.Finish()
+
+	engine := r.engineFactory.Get(ctx)
+	query := `
+        WITH i AS (
+            UPDATE invitations
+            SET token = $2, expires_at = $3, uses_count = 0, revoked_at = NULL, created_at = $4
+            WHERE id = $1
+            RETURNING *
+        )
+        SELECT ` + invitationColumns + `
+        FROM i
+        LEFT JOIN organization_roles cr ON cr.id = i.custom_role_id
+    `
+
+	var regenerated domain.Invitation
+	err := pgxscan.Get(ctx, engine, &regenerated, query,
+		uuidToPgtype(invitation.ID), invitation.Token, invitation.ExpiresAt, invitation.CreatedAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return domain.Invitation{}, domain.ErrNotFound
+		}
+		logger.Errorf(ctx, "failed to regenerate invitation: %v", err)
+		return domain.Invitation{}, err
+	}
+
+	return regenerated, nil
+}
+
+// ListInvitationUses retrieves who joined through an invitation, newest first
+func (r *PGXRepository) ListInvitationUses(ctx context.Context, invitationID domain.ID, limit, offset int) ([]domain.InvitationUse, int, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "repository.ListInvitationUses")
+	defer span.Finish()
+
+	engine := r.engineFactory.Get(ctx)
+
+	var total int
+	countQuery := `SELECT COUNT(*) FROM invitation_uses WHERE invitation_id = $1`
+	if err := pgxscan.Get(ctx, engine, &total, countQuery, uuidToPgtype(invitationID)); err != nil {
+		logger.Errorf(ctx, "failed to count invitation uses: %v", err)
+		return nil, 0, err
+	}
+
+	query := `
+		SELECT iu.id, iu.invitation_id, iu.user_id, u.first_name, u.last_name, iu.used_at
+		FROM invitation_uses iu
+		JOIN users u ON u.id = iu.user_id
+		WHERE iu.invitation_id = $1
+		ORDER BY iu.used_at DESC
+		LIMIT $2 OFFSET $3
+	`
+
+	var uses []domain.InvitationUse
+	err := pgxscan.Select(ctx, engine, &uses, query, uuidToPgtype(invitationID), limit, offset)
+	if err != nil {
+		logger.Errorf(ctx, "failed to list invitation uses: %v", err)
+		return nil, 0, err
+	}
+
+	if uses == nil {
+		uses = []domain.InvitationUse{}
+	}
+
+	return uses, total, nil
+}
+
 // GetInvitationByID retrieves an invitation by ID
 func (r *PGXRepository) GetInvitationByID(ctx context.Context, id domain.ID) (domain.Invitation, error) {
 	span, ctx := opentracing.StartSpanFromContext(ctx, "repository.GetInvitationByID")
@@ -344,9 +555,10 @@ func (r *PGXRepository) GetInvitationByID(ctx context.Context, id domain.ID) (do
 
 	engine := r.engineFactory.Get(ctx)
 	query := `
-		SELECT id, organization_id, token, role, expires_at, used_at, created_at
-		FROM invitations
-		WHERE id = $1
+		SELECT ` + invitationColumns + `
+		FROM invitations i
+		LEFT JOIN organization_roles cr ON cr.id = i.custom_role_id
+		WHERE i.id = $1
 	`
 
 	var invitation domain.Invitation
@@ -389,9 +601,14 @@ func (r *PGXRepository) CreateOrganizationMember(ctx context.Context, member dom
 
 	engine := r.engineFactory.Get(ctx)
 	query := `
-        INSERT INTO organization_members (id, organization_id, user_id, email, role, status, joined_at, updated_at)
-        VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
-        RETURNING id, organization_id, user_id, email, role, status, joined_at, updated_at
+        WITH created AS (
+            INSERT INTO organization_members (id, organization_id, user_id, email, role, status, joined_at, updated_at, custom_role_id)
+            VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+            RETURNING id, organization_id, user_id, email, role, status, joined_at, updated_at, custom_role_id
+        )
+        SELECT c.*, COALESCE(cr.name, '') AS custom_role_name, cr.permissions AS custom_permissions
+        FROM created c
+        LEFT JOIN organization_roles cr ON cr.id = c.custom_role_id
     `
 
 	var created domain.OrganizationMember
@@ -404,6 +621,7 @@ func (r *PGXRepository) CreateOrganizationMember(ctx context.Context, member dom
 		member.Status,
 		member.JoinedAt,
 		member.UpdatedAt,
+		optionalUUIDToPgtype(member.CustomRoleID),
 	)
 	if err != nil {
 		logger.Errorf(ctx, "failed to create organization member: %v", err)
@@ -420,9 +638,11 @@ func (r *PGXRepository) GetOrganizationMember(ctx context.Context, userID, organ
 
 	engine := r.engineFactory.Get(ctx)
 	query := `
-        SELECT id, organization_id, user_id, email, role, status, joined_at, updated_at
-        FROM organization_members
-        WHERE user_id = $1 AND organization_id = $2
+        SELECT om.id, om.organization_id, om.user_id, om.email, om.role, om.status, om.joined_at, om.updated_at,
+               om.custom_role_id, COALESCE(cr.name, '') AS custom_role_name, cr.permissions AS custom_permissions
+        FROM organization_members om
+        LEFT JOIN organization_roles cr ON cr.id = om.custom_role_id
+        WHERE om.user_id = $1 AND om.organization_id = $2
     `
 
 	var member domain.OrganizationMember
@@ -445,10 +665,15 @@ func (r *PGXRepository) UpdateOrganizationMember(ctx context.Context, member dom
 
 	engine := r.engineFactory.Get(ctx)
 	query := `
-        UPDATE organization_members
-        SET email = $2, role = $3, status = $4, updated_at = $5
-        WHERE id = $1
-        RETURNING id, organization_id, user_id, email, role, status, joined_at, updated_at
+        WITH updated AS (
+            UPDATE organization_members
+            SET email = $2, role = $3, status = $4, updated_at = $5, custom_role_id = $6
+            WHERE id = $1
+            RETURNING id, organization_id, user_id, email, role, status, joined_at, updated_at, custom_role_id
+        )
+        SELECT u.*, COALESCE(cr.name, '') AS custom_role_name, cr.permissions AS custom_permissions
+        FROM updated u
+        LEFT JOIN organization_roles cr ON cr.id = u.custom_role_id
     `
 
 	var updated domain.OrganizationMember
@@ -458,6 +683,7 @@ func (r *PGXRepository) UpdateOrganizationMember(ctx context.Context, member dom
 		member.Role,
 		member.Status,
 		member.UpdatedAt,
+		optionalUUIDToPgtype(member.CustomRoleID),
 	)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
@@ -477,10 +703,12 @@ func (r *PGXRepository) ListOrganizationMembersByUser(ctx context.Context, userI
 
 	engine := r.engineFactory.Get(ctx)
 	query := `
-        SELECT id, organization_id, user_id, email, role, status, joined_at, updated_at
-        FROM organization_members
-        WHERE user_id = $1
-        ORDER BY joined_at DESC
+        SELECT om.id, om.organization_id, om.user_id, om.email, om.role, om.status, om.joined_at, om.updated_at,
+               om.custom_role_id, COALESCE(cr.name, '') AS custom_role_name, cr.permissions AS custom_permissions
+        FROM organization_members om
+        LEFT JOIN organization_roles cr ON cr.id = om.custom_role_id
+        WHERE om.user_id = $1
+        ORDER BY om.joined_at DESC
     `
 
 	var members []*domain.OrganizationMember
@@ -0,0 +1,109 @@
+package audit
+
+import (
+	"context"
+	"core-service/internal/domain"
+	"core-service/internal/logger"
+	"encoding/json"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/opentracing/opentracing-go"
+)
+
+type repository interface {
+	CreateAuditEvent(ctx context.Context, event domain.AuditEvent) (bool, error)
+	ListAuditEvents(ctx context.Context, filter domain.AuditEventFilter, limit, offset int) ([]domain.AuditEvent, int, error)
+}
+
+// Service stores audit events from the audit queue and serves the audit log
+type Service struct {
+	repo repository
+}
+
+func New(repo repository) *Service {
+	return &Service{repo: repo}
+}
+
+// HandleMessage decodes an audit event from the queue and stores it.
+// Malformed events are dropped, storage errors are returned so the message is retried.
+func (s *Service) HandleMessage(ctx context.Context, body []byte) error {
+	var event domain.AuditEvent
+	if err := json.Unmarshal(body, &event); err != nil {
+		logger.Warnf(ctx, "audit: dropping malformed event: %v", err)
+		return nil
+	}
+
+	err := s.HandleEvent(ctx, event)
+	if errors.Is(err, domain.ErrInvalidArgument) {
+		logger.Warnf(ctx, "audit: dropping invalid event %q: %v", event.EventID, err)
+		return nil
+	}
+	return err
+}
+
+// HandleEvent appends an event to the audit log; redelivered events are stored once
+func (s *Service) HandleEvent(ctx context.Context, event domain.AuditEvent) error {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "service.audit.HandleEvent")
+	defer span.Finish()
+
+	if err := event.Validate(); err != nil {
+		return err
+	}
+
+	created, err := s.repo.CreateAuditEvent(ctx, event)
+	if err != nil {
+		return err
+	}
+	if !created {
+		logger.Debugf(ctx, "audit: event %q already recorded", event.EventID)
+	}
+
+	return nil
+}
+
+// ListAuditEvents returns the audit log of an organization, newest first
+func (s *Service) ListAuditEvents(ctx context.Context, organizationID domain.ID, actorUserID *domain.ID, action string, from, to *time.Time, page, pageSize int) ([]domain.AuditEvent, int, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "service.audit.ListAuditEvents")
+	defer span.Finish()
+
+	return s.list(ctx, domain.AuditEventFilter{
+		OrganizationID: &organizationID,
+		ActorUserID:    actorUserID,
+		Action:         action,
+		From:           from,
+		To:             to,
+	}, page, pageSize)
+}
+
+// ListUserAuditEvents returns actions performed by a user across all organizations, newest first
+func (s *Service) ListUserAuditEvents(ctx context.Context, userID domain.ID, action string, from, to *time.Time, page, pageSize int) ([]domain.AuditEvent, int, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "service.audit.ListUserAuditEvents")
+	defer span.Finish()
+
+	return s.list(ctx, domain.AuditEventFilter{
+		ActorUserID: &userID,
+		Action:      action,
+		From:        from,
+		To:          to,
+	}, page, pageSize)
+}
+
+func (s *Service) list(ctx context.Context, filter domain.AuditEventFilter, page, pageSize int) ([]domain.AuditEvent, int, error) {
+	filter.Action = strings.TrimSpace(filter.Action)
+	if filter.From != nil && filter.To != nil && !filter.From.Before(*filter.To) {
+		return nil, 0, domain.NewInvalidArgumentError("from must be before to")
+	}
+
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 || pageSize > 100 {
+		pageSize = 20
+	}
+
+	offset := (page - 1) * pageSize
+
+	return s.repo.ListAuditEvents(ctx, filter, pageSize, offset)
+}
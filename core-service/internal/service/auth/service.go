@@ -2,8 +2,10 @@ package auth
 
 import (
 	"context"
+	"crypto/subtle"
 	"errors"
 	"strconv"
+	"time"
 
 	"core-service/internal/domain"
 	"core-service/internal/jwt"
@@ -20,20 +22,23 @@ type userRepository interface {
 	UpdateUser(ctx context.Context, user domain.User) (domain.User, error)
 	GetOrganizationMember(ctx context.Context, organizationID, userID domain.ID) (*domain.OrganizationMember, error)
 	ListOrganizationMembersByUser(ctx context.Context, userID domain.ID) ([]*domain.OrganizationMember, error)
+	GetOrganization(ctx context.Context, id domain.ID) (domain.Organization, error)
 }
 
 // Service handles authentication flows.
 type Service struct {
-	users     userRepository
-	jwt       *jwt.Provider
-	validator *telegram.Validator
+	users         userRepository
+	jwt           *jwt.Provider
+	validator     *telegram.Validator
+	serviceTokens []string
 }
 
-func New(users userRepository, jwtProvider *jwt.Provider, validator *telegram.Validator) *Service {
+func New(users userRepository, jwtProvider *jwt.Provider, validator *telegram.Validator, serviceTokens []string) *Service {
 	return &Service{
-		users:     users,
-		jwt:       jwtProvider,
-		validator: validator,
+		users:         users,
+		jwt:           jwtProvider,
+		validator:     validator,
+		serviceTokens: serviceTokens,
 	}
 }
 
@@ -163,3 +168,116 @@ func (s *Service) RefreshToken(ctx context.Context, userID domain.ID) (string, e
 	logger.Infof(ctx, "token refreshed for user %s with %d organizations", userID, len(activeMemberships))
 	return token, nil
 }
+
+// AuthenticateBotUser issues a token on behalf of a user writing to the Telegram bot.
+// The bot proves itself with its own token; the user must have completed registration in the mini app.
+// Returns the token, its expiry, the user and organizations the user is an active member of.
+func (s *Service) AuthenticateBotUser(ctx context.Context, botToken, telegramID string) (string, time.Time, *domain.User, []domain.Organization, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "service.auth.AuthenticateBotUser")
+	defer span.Finish()
+
+	if !s.validator.VerifyBotToken(botToken) {
+		logger.Warn(ctx, "bot authentication rejected: unknown bot token")
+		return "", time.Time{}, nil, nil, domain.ErrUnauthorized
+	}
+
+	user, err := s.users.GetUserByTelegramID(ctx, telegramID)
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			return "", time.Time{}, nil, nil, domain.NewNotFoundError("user not found")
+		}
+		logger.Errorf(ctx, "failed to get user by telegram ID: %v", err)
+		return "", time.Time{}, nil, nil, err
+	}
+
+	if !user.RegistrationCompleted {
+		return "", time.Time{}, nil, nil, domain.NewNotFoundError("user has not completed registration")
+	}
+
+	memberships, err := s.users.ListOrganizationMembersByUser(ctx, user.ID)
+	if err != nil {
+		logger.Errorf(ctx, "failed to get user memberships: %v", err)
+		return "", time.Time{}, nil, nil, err
+	}
+
+	activeMemberships := make([]*domain.OrganizationMember, 0, len(memberships))
+	organizations := make([]domain.Organization, 0, len(memberships))
+	for _, m := range memberships {
+		if m.Status != domain.UserStatusActive {
+			continue
+		}
+		org, err := s.users.GetOrganization(ctx, m.OrganizationID)
+		if err != nil {
+			logger.Errorf(ctx, "failed to get organization %s: %v", m.OrganizationID, err)
+			return "", time.Time{}, nil, nil, err
+		}
+		activeMemberships = append(activeMemberships, m)
+		organizations = append(organizations, org)
+	}
+
+	expiresAt := time.Now().Add(s.jwt.AccessTTL())
+	token, err := s.jwt.GenerateAccessToken(ctx, user.ID, activeMemberships)
+	if err != nil {
+		logger.Errorf(ctx, "failed to generate token: %v", err)
+		return "", time.Time{}, nil, nil, err
+	}
+
+	logger.Infof(ctx, "bot authenticated user %s with %d organizations", user.ID, len(activeMemberships))
+	return token, expiresAt, &user, organizations, nil
+}
+
+// AuthenticateServiceUser issues a token on behalf of a user for an internal service running
+// work the user scheduled (scheduled agent runs). The service proves itself with its own token.
+// The token carries only organizations the user is still an active member of.
+func (s *Service) AuthenticateServiceUser(ctx context.Context, serviceToken string, userID domain.ID) (string, time.Time, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "service.auth.AuthenticateServiceUser")
+	defer span.Finish()
+
+	if !s.verifyServiceToken(serviceToken) {
+		logger.Warn(ctx, "service authentication rejected: unknown service token")
+		return "", time.Time{}, domain.ErrUnauthorized
+	}
+
+	if _, err := s.users.GetUser(ctx, userID); err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			return "", time.Time{}, domain.NewNotFoundError("user not found")
+		}
+		logger.Errorf(ctx, "failed to get user: %v", err)
+		return "", time.Time{}, err
+	}
+
+	memberships, err := s.users.ListOrganizationMembersByUser(ctx, userID)
+	if err != nil {
+		logger.Errorf(ctx, "failed to get user memberships: %v", err)
+		return "", time.Time{}, err
+	}
+
+	activeMemberships := make([]*domain.OrganizationMember, 0, len(memberships))
+	for _, m := range memberships {
+		if m.Status == domain.UserStatusActive {
+			activeMemberships = append(activeMemberships, m)
+		}
+	}
+
+	expiresAt := time.Now().Add(s.jwt.AccessTTL())
+	token, err := s.jwt.GenerateAccessToken(ctx, userID, activeMemberships)
+	if err != nil {
+		logger.Errorf(ctx, "failed to generate token: %v", err)
+		return "", time.Time{}, err
+	}
+
+	logger.Infof(ctx, "service authenticated user %s with %d organizations", userID, len(activeMemberships))
+	return token, expiresAt, nil
+}
+
+func (s *Service) verifyServiceToken(token string) bool {
+	if token == "" {
+		return false
+	}
+	for _, serviceToken := range s.serviceTokens {
+		if subtle.ConstantTimeCompare([]byte(token), []byte(serviceToken)) == 1 {
+			return true
+		}
+	}
+	return false
+}
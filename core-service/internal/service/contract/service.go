@@ -16,12 +16,17 @@ type repository interface {
 	DeleteContract(ctx context.Context, id domain.ID) error
 }
 
+type auditRecorder interface {
+	Record(ctx context.Context, event domain.AuditEvent)
+}
+
 type Service struct {
-	repo repository
+	repo  repository
+	audit auditRecorder
 }
 
-func New(repo repository) *Service {
-	return &Service{repo: repo}
+func New(repo repository, audit auditRecorder) *Service {
+	return &Service{repo: repo, audit: audit}
 }
 
 // RegisterGeneratedContract registers a generated contract (called by LLM Service)
@@ -44,6 +49,9 @@ func (s *Service) RegisterGeneratedContract(ctx context.Context, organizationID,
 		return domain.GeneratedContract{}, err
 	}
 
+	s.audit.Record(ctx, domain.NewAuditEvent(domain.AuditActionContractRegistered, &organizationID,
+		domain.AuditResourceContract, created.ID.String(), map[string]any{"name": created.Name, "template_id": templateID.String()}))
+
 	return created, nil
 }
 
@@ -77,7 +85,19 @@ func (s *Service) DeleteGeneratedContract(ctx context.Context, id domain.ID) err
 	span, ctx := opentracing.StartSpanFromContext(ctx, "service.contract.DeleteGeneratedContract")
 	defer span.Finish()
 
-	return s.repo.DeleteContract(ctx, id)
+	contract, err := s.repo.GetContract(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if err := s.repo.DeleteContract(ctx, id); err != nil {
+		return err
+	}
+
+	s.audit.Record(ctx, domain.NewAuditEvent(domain.AuditActionContractDeleted, &contract.OrganizationID,
+		domain.AuditResourceContract, id.String(), map[string]any{"name": contract.Name}))
+
+	return nil
 }
 
 // ListGeneratedContractsByOrganization retrieves all contracts for an organization (without pagination)
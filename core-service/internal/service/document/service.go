@@ -21,17 +21,23 @@ type queueClient interface {
 	PublishMessage(ctx context.Context, message interface{}) error
 }
 
+type auditRecorder interface {
+	Record(ctx context.Context, event domain.AuditEvent)
+}
+
 type Service struct {
 	repo      repository
 	queue     queueClient
 	queueName string
+	audit     auditRecorder
 }
 
-func New(repo repository, queue queueClient, queueName string) *Service {
+func New(repo repository, queue queueClient, queueName string, audit auditRecorder) *Service {
 	return &Service{
 		repo:      repo,
 		queue:     queue,
 		queueName: queueName,
+		audit:     audit,
 	}
 }
 
@@ -78,6 +84,9 @@ func (s *Service) RegisterDocument(ctx context.Context, organizationID domain.ID
 		}
 	}
 
+	s.audit.Record(ctx, domain.NewAuditEvent(domain.AuditActionDocumentRegistered, &organizationID,
+		domain.AuditResourceDocument, created.ID.String(), map[string]any{"name": created.Name, "file_type": created.FileType}))
+
 	return created, nil
 }
 
@@ -128,7 +137,19 @@ func (s *Service) DeleteDocument(ctx context.Context, id domain.ID) error {
 	span, ctx := opentracing.StartSpanFromContext(ctx, "service.document.DeleteDocument")
 	defer span.Finish()
 
-	return s.repo.DeleteDocument(ctx, id)
+	doc, err := s.repo.GetDocument(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if err := s.repo.DeleteDocument(ctx, id); err != nil {
+		return err
+	}
+
+	s.audit.Record(ctx, domain.NewAuditEvent(domain.AuditActionDocumentDeleted, &doc.OrganizationID,
+		domain.AuditResourceDocument, id.String(), map[string]any{"name": doc.Name}))
+
+	return nil
 }
 
 // publishProcessingJob publishes a document processing job to RabbitMQ
@@ -0,0 +1,517 @@
+package erasure
+
+import (
+	"context"
+	"core-service/internal/domain"
+	"core-service/internal/logger"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/opentracing/opentracing-go"
+)
+
+type repository interface {
+	GetOrganization(ctx context.Context, id domain.ID) (domain.Organization, error)
+	DeleteOrganization(ctx context.Context, id domain.ID) error
+	RestoreOrganization(ctx context.Context, id domain.ID) error
+	PurgeOrganization(ctx context.Context, id domain.ID) (int, error)
+	GetUser(ctx context.Context, id domain.ID) (domain.User, error)
+	UpdateUser(ctx context.Context, user domain.User) (domain.User, error)
+	DeactivateUser(ctx context.Context, id domain.ID) error
+	ListOrganizationMembersByUser(ctx context.Context, userID domain.ID) ([]*domain.OrganizationMember, error)
+	LockOrganizationAdmins(ctx context.Context, organizationID domain.ID) (int, error)
+	LeaveAllOrganizations(ctx context.Context, userID domain.ID) error
+	PurgeUser(ctx context.Context, id domain.ID) (int, error)
+	CreateDataErasure(ctx context.Context, erasure domain.DataErasure) (domain.DataErasure, error)
+	GetDataErasure(ctx context.Context, id domain.ID) (domain.DataErasure, error)
+	GetActiveDataErasure(ctx context.Context, subjectType domain.ErasureSubject, subjectID domain.ID) (domain.DataErasure, error)
+	ClaimDueDataErasures(ctx context.Context, now, retryBefore time.Time, limit int) ([]domain.DataErasure, error)
+	UpdateDataErasureStep(ctx context.Context, id domain.ID, step domain.ErasureStep, report domain.ErasureStepReport) (domain.DataErasure, error)
+	FinishDataErasure(ctx context.Context, id domain.ID, status domain.ErasureStatus) (domain.DataErasure, error)
+	CancelDataErasure(ctx context.Context, id domain.ID) error
+	ListDataErasuresByRequester(ctx context.Context, userID domain.ID, limit, offset int) ([]domain.DataErasure, int, error)
+}
+
+type transactor interface {
+	Do(ctx context.Context, f func(ctx context.Context) error) error
+}
+
+type objectStorage interface {
+	DeletePrefix(ctx context.Context, prefix string) (int, error)
+}
+
+type publisher interface {
+	PublishMessage(ctx context.Context, message interface{}) error
+}
+
+type auditRecorder interface {
+	Record(ctx context.Context, event domain.AuditEvent)
+}
+
+type config interface {
+	GetErasureGracePeriod() time.Duration
+	GetErasureRetryInterval() time.Duration
+	GetErasureBatchSize() int
+}
+
+type Service struct {
+	repo        repository
+	tx          transactor
+	storage     objectStorage
+	searchJobs  publisher
+	llmRequests publisher
+	events      publisher
+	audit       auditRecorder
+	cfg         config
+}
+
+// New creates the erasure service. searchJobs is the docs-processor job queue, llmRequests is the
+// queue llm-service reads erasure requests from; events may be nil when notifications are disabled.
+func New(repo repository, tx transactor, storage objectStorage, searchJobs, llmRequests, events publisher, audit auditRecorder, cfg config) *Service {
+	return &Service{
+		repo:        repo,
+		tx:          tx,
+		storage:     storage,
+		searchJobs:  searchJobs,
+		llmRequests: llmRequests,
+		events:      events,
+		audit:       audit,
+		cfg:         cfg,
+	}
+}
+
+// ScheduleOrganizationErasure soft deletes an organization and schedules the purge of all its data
+// after the grace period. Until then the deletion can be cancelled.
+func (s *Service) ScheduleOrganizationErasure(ctx context.Context, organizationID, requestedBy domain.ID) (domain.DataErasure, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "service.erasure.ScheduleOrganizationErasure")
+	defer span.Finish()
+
+	org, err := s.repo.GetOrganization(ctx, organizationID)
+	if err != nil {
+		return domain.DataErasure{}, err
+	}
+
+	erasure := domain.NewDataErasure(domain.ErasureSubjectOrganization, organizationID, org.Name, &requestedBy, s.cfg.GetErasureGracePeriod())
+
+	var created domain.DataErasure
+	err = s.tx.Do(ctx, func(txCtx context.Context) error {
+		if err := s.repo.DeleteOrganization(txCtx, organizationID); err != nil {
+			return err
+		}
+		created, err = s.repo.CreateDataErasure(txCtx, erasure)
+		return err
+	})
+	if err != nil {
+		if errors.Is(err, domain.ErrAlreadyExists) {
+			return domain.DataErasure{}, domain.NewAlreadyExistsError("organization deletion is already scheduled")
+		}
+		return domain.DataErasure{}, err
+	}
+
+	s.audit.Record(ctx, domain.NewAuditEvent(domain.AuditActionOrganizationDeletionScheduled, &organizationID,
+		domain.AuditResourceOrganization, organizationID.String(), map[string]any{"purge_after": created.PurgeAfter}))
+
+	return created, nil
+}
+
+// CancelOrganizationErasure restores an organization whose data has not been purged yet
+func (s *Service) CancelOrganizationErasure(ctx context.Context, organizationID domain.ID) error {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "service.erasure.CancelOrganizationErasure")
+	defer span.Finish()
+
+	erasure, err := s.repo.GetActiveDataErasure(ctx, domain.ErasureSubjectOrganization, organizationID)
+	if err != nil {
+		return err
+	}
+	if !erasure.CanCancel() {
+		return domain.NewInvalidArgumentError("organization data is already being erased")
+	}
+
+	err = s.tx.Do(ctx, func(txCtx context.Context) error {
+		if err := s.repo.CancelDataErasure(txCtx, erasure.ID); err != nil {
+			return err
+		}
+		return s.repo.RestoreOrganization(txCtx, organizationID)
+	})
+	if err != nil {
+		return err
+	}
+
+	s.audit.Record(ctx, domain.NewAuditEvent(domain.AuditActionOrganizationDeletionCancelled, &organizationID,
+		domain.AuditResourceOrganization, organizationID.String(), nil))
+
+	return nil
+}
+
+// GetOrganizationErasure returns the pending or running erasure of an organization
+func (s *Service) GetOrganizationErasure(ctx context.Context, organizationID domain.ID) (domain.DataErasure, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "service.erasure.GetOrganizationErasure")
+	defer span.Finish()
+
+	return s.repo.GetActiveDataErasure(ctx, domain.ErasureSubjectOrganization, organizationID)
+}
+
+// ScheduleAccountErasure deactivates the user's account, ends all memberships and schedules the purge
+// of the account after the grace period. The last administrator of an organization has to transfer
+// ownership or delete the organization first.
+func (s *Service) ScheduleAccountErasure(ctx context.Context, userID domain.ID) (domain.DataErasure, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "service.erasure.ScheduleAccountErasure")
+	defer span.Finish()
+
+	user, err := s.repo.GetUser(ctx, userID)
+	if err != nil {
+		return domain.DataErasure{}, err
+	}
+	memberships, err := s.repo.ListOrganizationMembersByUser(ctx, userID)
+	if err != nil {
+		return domain.DataErasure{}, err
+	}
+
+	name := strings.TrimSpace(user.FirstName + " " + user.LastName)
+	erasure := domain.NewDataErasure(domain.ErasureSubjectUser, userID, name, &userID, s.cfg.GetErasureGracePeriod())
+
+	var created domain.DataErasure
+	err = s.tx.Do(ctx, func(txCtx context.Context) error {
+		for _, member := range memberships {
+			if !member.IsActiveAdmin() {
+				continue
+			}
+			org, err := s.repo.GetOrganization(txCtx, member.OrganizationID)
+			if errors.Is(err, domain.ErrNotFound) {
+				continue
+			}
+			if err != nil {
+				return err
+			}
+			admins, err := s.repo.LockOrganizationAdmins(txCtx, member.OrganizationID)
+			if err != nil {
+				return err
+			}
+			if admins <= 1 {
+				return domain.NewInvalidArgumentError(fmt.Sprintf(
+					"you are the last administrator of %q: transfer ownership or delete the organization first", org.Name))
+			}
+		}
+		if err := s.repo.LeaveAllOrganizations(txCtx, userID); err != nil {
+			return err
+		}
+		if err := s.repo.DeactivateUser(txCtx, userID); err != nil {
+			return err
+		}
+		created, err = s.repo.CreateDataErasure(txCtx, erasure)
+		return err
+	})
+	if err != nil {
+		if errors.Is(err, domain.ErrAlreadyExists) {
+			return domain.DataErasure{}, domain.NewAlreadyExistsError("account deletion is already scheduled")
+		}
+		return domain.DataErasure{}, err
+	}
+
+	for _, member := range memberships {
+		if member.IsActive() {
+			s.audit.Record(ctx, domain.NewAuditEvent(domain.AuditActionMemberLeft, &member.OrganizationID,
+				domain.AuditResourceMember, userID.String(), nil))
+		}
+	}
+	s.audit.Record(ctx, domain.NewAuditEvent(domain.AuditActionAccountDeletionScheduled, nil,
+		domain.AuditResourceUser, userID.String(), map[string]any{"purge_after": created.PurgeAfter}))
+
+	return created, nil
+}
+
+// CancelAccountErasure reactivates the account during the grace period.
+// Memberships are not restored: the user has to be invited again.
+func (s *Service) CancelAccountErasure(ctx context.Context, userID domain.ID) error {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "service.erasure.CancelAccountErasure")
+	defer span.Finish()
+
+	erasure, err := s.repo.GetActiveDataErasure(ctx, domain.ErasureSubjectUser, userID)
+	if err != nil {
+		return err
+	}
+	if !erasure.CanCancel() {
+		return domain.NewInvalidArgumentError("account data is already being erased")
+	}
+
+	err = s.tx.Do(ctx, func(txCtx context.Context) error {
+		if err := s.repo.CancelDataErasure(txCtx, erasure.ID); err != nil {
+			return err
+		}
+		user, err := s.repo.GetUser(txCtx, userID)
+		if err != nil {
+			return err
+		}
+		user.IsActive = true
+		user.UpdatedAt = time.Now()
+		_, err = s.repo.UpdateUser(txCtx, user)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	s.audit.Record(ctx, domain.NewAuditEvent(domain.AuditActionAccountDeletionCancelled, nil,
+		domain.AuditResourceUser, userID.String(), nil))
+
+	return nil
+}
+
+// GetAccountErasure returns the pending or running erasure of the user's account
+func (s *Service) GetAccountErasure(ctx context.Context, userID domain.ID) (domain.DataErasure, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "service.erasure.GetAccountErasure")
+	defer span.Finish()
+
+	return s.repo.GetActiveDataErasure(ctx, domain.ErasureSubjectUser, userID)
+}
+
+// ListRequestedErasures lists erasures requested by the user, including completion reports
+func (s *Service) ListRequestedErasures(ctx context.Context, userID domain.ID, page, pageSize int) ([]domain.DataErasure, int, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "service.erasure.ListRequestedErasures")
+	defer span.Finish()
+
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 || pageSize > 100 {
+		pageSize = 20
+	}
+
+	offset := (page - 1) * pageSize
+	return s.repo.ListDataErasuresByRequester(ctx, userID, pageSize, offset)
+}
+
+// RunDueErasures starts erasures whose grace period is over and retries failed or stalled ones.
+// Storage is purged in place; the search index and llm-service data are purged by their owners,
+// which report back through the results queue.
+func (s *Service) RunDueErasures(ctx context.Context) error {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "service.erasure.RunDueErasures")
+	defer span.Finish()
+
+	now := time.Now()
+	erasures, err := s.repo.ClaimDueDataErasures(ctx, now, now.Add(-s.cfg.GetErasureRetryInterval()), s.cfg.GetErasureBatchSize())
+	if err != nil {
+		return err
+	}
+
+	for _, erasure := range erasures {
+		logger.Infof(ctx, "erasure: running %s erasure %s for %s", erasure.SubjectType, erasure.ID, erasure.SubjectID)
+		if err := s.execute(ctx, erasure); err != nil {
+			logger.Errorf(ctx, "erasure: failed to run erasure %s: %v", erasure.ID, err)
+		}
+	}
+
+	return nil
+}
+
+// execute starts all steps that have not completed yet and advances the erasure
+func (s *Service) execute(ctx context.Context, erasure domain.DataErasure) error {
+	var err error
+	for _, step := range erasure.PendingSteps() {
+		switch step {
+		case domain.ErasureStepStorage:
+			erasure, err = s.purgeStorage(ctx, erasure)
+		case domain.ErasureStepSearchIndex:
+			erasure, err = s.request(ctx, erasure, step, domain.NewOrganizationPurgeJob(erasure.SubjectID, erasure.ID), s.searchJobs)
+		case domain.ErasureStepLLMData:
+			erasure, err = s.request(ctx, erasure, step, domain.ErasureRequest{
+				ErasureID:   erasure.ID,
+				SubjectType: erasure.SubjectType,
+				SubjectID:   erasure.SubjectID,
+				RequestedAt: time.Now(),
+			}, s.llmRequests)
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	return s.advance(ctx, erasure)
+}
+
+// purgeStorage deletes the organization's documents, generated contracts and export archives from S3
+func (s *Service) purgeStorage(ctx context.Context, erasure domain.DataErasure) (domain.DataErasure, error) {
+	report := domain.ErasureStepReport{Status: domain.ErasureStepCompleted}
+	for _, prefix := range []string{"documents/", "contracts/", "exports/"} {
+		deleted, err := s.storage.DeletePrefix(ctx, prefix+erasure.SubjectID.String()+"/")
+		report.ItemsDeleted += deleted
+		if err != nil {
+			report.Status = domain.ErasureStepFailed
+			report.Error = err.Error()
+			break
+		}
+	}
+
+	return s.recordStep(ctx, erasure.ID, domain.ErasureStepStorage, report)
+}
+
+// request asks the owner of a step to purge its data. A failed step is reset to pending first,
+// so the erasure is not marked failed again while the retry is running.
+func (s *Service) request(ctx context.Context, erasure domain.DataErasure, step domain.ErasureStep, message interface{}, queue publisher) (domain.DataErasure, error) {
+	if erasure.Steps[step].Status == domain.ErasureStepFailed {
+		updated, err := s.repo.UpdateDataErasureStep(ctx, erasure.ID, step, domain.ErasureStepReport{Status: domain.ErasureStepPending})
+		if err != nil {
+			return erasure, err
+		}
+		erasure = updated
+	}
+
+	if err := queue.PublishMessage(ctx, message); err != nil {
+		return s.recordStep(ctx, erasure.ID, step, domain.ErasureStepReport{
+			Status: domain.ErasureStepFailed,
+			Error:  fmt.Sprintf("failed to publish request: %v", err),
+		})
+	}
+
+	return erasure, nil
+}
+
+func (s *Service) recordStep(ctx context.Context, id domain.ID, step domain.ErasureStep, report domain.ErasureStepReport) (domain.DataErasure, error) {
+	if report.FinishedAt == nil {
+		now := time.Now()
+		report.FinishedAt = &now
+	}
+	return s.repo.UpdateDataErasureStep(ctx, id, step, report)
+}
+
+// HandleMessage decodes a step result from the results queue and records it.
+// Malformed results are dropped, storage errors are returned so the message is retried.
+func (s *Service) HandleMessage(ctx context.Context, body []byte) error {
+	var result domain.ErasureStepResult
+	if err := json.Unmarshal(body, &result); err != nil {
+		logger.Warnf(ctx, "erasure: dropping malformed step result: %v", err)
+		return nil
+	}
+
+	err := s.HandleStepResult(ctx, result)
+	if errors.Is(err, domain.ErrInvalidArgument) || errors.Is(err, domain.ErrNotFound) {
+		logger.Warnf(ctx, "erasure: dropping step result for erasure %s: %v", result.ErasureID, err)
+		return nil
+	}
+	return err
+}
+
+// HandleStepResult records the result of a step reported by another service and, once all
+// external data is gone, purges the core-service database
+func (s *Service) HandleStepResult(ctx context.Context, result domain.ErasureStepResult) error {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "service.erasure.HandleStepResult")
+	defer span.Finish()
+
+	if err := result.Validate(); err != nil {
+		return err
+	}
+
+	erasure, err := s.repo.GetDataErasure(ctx, result.ErasureID)
+	if err != nil {
+		return err
+	}
+	if erasure.Status == domain.ErasureStatusCompleted || erasure.Status == domain.ErasureStatusCancelled {
+		logger.Infof(ctx, "erasure: ignoring %s result from %s for %s erasure %s", result.Step, result.Source, erasure.Status, erasure.ID)
+		return nil
+	}
+
+	erasure, err = s.repo.UpdateDataErasureStep(ctx, erasure.ID, result.Step, result.Report())
+	if err != nil {
+		return err
+	}
+
+	return s.advance(ctx, erasure)
+}
+
+// advance marks the erasure failed if any step failed, or completes it by purging the core-service
+// database once every other storage has been cleared
+func (s *Service) advance(ctx context.Context, erasure domain.DataErasure) error {
+	if erasure.HasFailedSteps() {
+		_, err := s.repo.FinishDataErasure(ctx, erasure.ID, domain.ErasureStatusFailed)
+		if errors.Is(err, domain.ErrNotFound) {
+			return nil
+		}
+		return err
+	}
+	if !erasure.ExternalStepsCompleted() {
+		return nil
+	}
+
+	var completed domain.DataErasure
+	err := s.tx.Do(ctx, func(txCtx context.Context) error {
+		var (
+			deleted int
+			err     error
+		)
+		if erasure.SubjectType == domain.ErasureSubjectUser {
+			deleted, err = s.repo.PurgeUser(txCtx, erasure.SubjectID)
+		} else {
+			deleted, err = s.repo.PurgeOrganization(txCtx, erasure.SubjectID)
+		}
+		if err != nil {
+			return err
+		}
+		now := time.Now()
+		_, err = s.repo.UpdateDataErasureStep(txCtx, erasure.ID, domain.ErasureStepDatabase, domain.ErasureStepReport{
+			Status:       domain.ErasureStepCompleted,
+			ItemsDeleted: deleted,
+			FinishedAt:   &now,
+		})
+		if err != nil {
+			return err
+		}
+		completed, err = s.repo.FinishDataErasure(txCtx, erasure.ID, domain.ErasureStatusCompleted)
+		return err
+	})
+	if errors.Is(err, domain.ErrNotFound) {
+		// Another instance has already completed the erasure
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	s.report(ctx, completed)
+	return nil
+}
+
+// report records the completion in the audit log and notifies the user who deleted the organization
+func (s *Service) report(ctx context.Context, erasure domain.DataErasure) {
+	metadata := map[string]any{"name": erasure.SubjectName, "items_deleted": erasure.ItemsDeleted()}
+	for step, report := range erasure.Steps {
+		metadata[string(step)] = report.ItemsDeleted
+	}
+
+	if erasure.SubjectType == domain.ErasureSubjectUser {
+		s.audit.Record(ctx, domain.NewAuditEvent(domain.AuditActionAccountErased, nil,
+			domain.AuditResourceUser, erasure.SubjectID.String(), metadata))
+		return
+	}
+
+	s.audit.Record(ctx, domain.NewAuditEvent(domain.AuditActionOrganizationErased, &erasure.SubjectID,
+		domain.AuditResourceOrganization, erasure.SubjectID.String(), metadata))
+
+	if s.events == nil || erasure.RequestedBy == nil {
+		return
+	}
+
+	steps := erasure.Steps
+	event := domain.NotificationEvent{
+		EventID:        "data.erased:" + erasure.ID.String(),
+		Type:           domain.NotificationEventDataErased,
+		Source:         "core-service",
+		OrganizationID: &erasure.SubjectID,
+		UserID:         erasure.RequestedBy,
+		Title:          "Данные организации удалены",
+		Text: fmt.Sprintf("Данные организации %s удалены безвозвратно: файлов %d, фрагментов в поиске %d, записей ассистента %d, записей в базе %d",
+			erasure.SubjectName,
+			steps[domain.ErasureStepStorage].ItemsDeleted,
+			steps[domain.ErasureStepSearchIndex].ItemsDeleted,
+			steps[domain.ErasureStepLLMData].ItemsDeleted,
+			steps[domain.ErasureStepDatabase].ItemsDeleted,
+		),
+		OccurredAt: time.Now(),
+	}
+
+	if err := s.events.PublishMessage(ctx, event); err != nil {
+		logger.Errorf(ctx, "failed to publish data erased event: %v", err)
+	}
+}
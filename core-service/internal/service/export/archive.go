@@ -0,0 +1,433 @@
+package export
+
+import (
+	"archive/zip"
+	"context"
+	"core-service/internal/domain"
+	"core-service/internal/logger"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"strings"
+	"time"
+)
+
+const (
+	// formatVersion - version of the archive layout, bumped on incompatible manifest changes
+	formatVersion = 1
+	// listPageSize - page size for reading organization data
+	listPageSize = 100
+	// notesLimit - notes are short and not paginated, so they are read in one request
+	notesLimit = 10000
+	// progressEvery - how often file progress is saved; every save also keeps the export from timing out
+	progressEvery = 10
+)
+
+// errArchiveWrite means the archive itself is broken, unlike a file that is missing from storage
+var errArchiveWrite = errors.New("failed to write archive")
+
+// manifest describes the archive contents, it is written to manifest.json
+type manifest struct {
+	FormatVersion    int            `json:"format_version"`
+	OrganizationID   domain.ID      `json:"organization_id"`
+	OrganizationName string         `json:"organization_name"`
+	RequestedBy      *domain.ID     `json:"requested_by,omitempty"`
+	ExportedAt       time.Time      `json:"exported_at"`
+	Contents         []manifestPart `json:"contents"`
+	// MissingFiles - files that were listed in the database but could not be read from storage
+	MissingFiles int `json:"missing_files"`
+}
+
+type manifestPart struct {
+	Name  string `json:"name"`
+	Items int    `json:"items"`
+}
+
+type organizationRecord struct {
+	ID          domain.ID       `json:"id"`
+	Name        string          `json:"name"`
+	Industry    string          `json:"industry"`
+	Region      string          `json:"region"`
+	Description string          `json:"description"`
+	Profile     json.RawMessage `json:"profile"`
+	CreatedAt   time.Time       `json:"created_at"`
+	UpdatedAt   time.Time       `json:"updated_at"`
+}
+
+type memberRecord struct {
+	UserID     domain.ID         `json:"user_id"`
+	FirstName  string            `json:"first_name"`
+	LastName   string            `json:"last_name"`
+	TelegramID string            `json:"telegram_id"`
+	Email      string            `json:"email"`
+	Role       domain.UserRole   `json:"role"`
+	CustomRole string            `json:"custom_role,omitempty"`
+	Status     domain.UserStatus `json:"status"`
+	JoinedAt   time.Time         `json:"joined_at"`
+}
+
+// fileRecord is shared by documents and contracts: File is the path of the original inside the archive
+type fileRecord struct {
+	File      string `json:"file,omitempty"`
+	FileError string `json:"file_error,omitempty"`
+}
+
+type documentRecord struct {
+	ID           domain.ID             `json:"id"`
+	Name         string                `json:"name"`
+	FileType     string                `json:"file_type"`
+	FileSize     int64                 `json:"file_size"`
+	Status       domain.DocumentStatus `json:"status"`
+	ErrorMessage *string               `json:"error_message,omitempty"`
+	CreatedAt    time.Time             `json:"created_at"`
+	fileRecord
+	s3Key string
+}
+
+type noteRecord struct {
+	ID        domain.ID `json:"id"`
+	Content   string    `json:"content"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type contractRecord struct {
+	ID         domain.ID       `json:"id"`
+	TemplateID domain.ID       `json:"template_id"`
+	Name       string          `json:"name"`
+	FilledData json.RawMessage `json:"filled_data"`
+	FileType   string          `json:"file_type"`
+	CreatedAt  time.Time       `json:"created_at"`
+	fileRecord
+	s3Key string
+}
+
+// archiveFile is an object from storage to be copied into the archive
+type archiveFile struct {
+	name   string
+	s3Key  string
+	record *fileRecord
+}
+
+// build collects the organization data and the files exported by llm-service into a ZIP archive,
+// uploads it and returns its key and size
+func (s *Service) build(ctx context.Context, export domain.OrganizationExport, assistantFiles []domain.ExportFile) (string, int64, error) {
+	tmp, err := os.CreateTemp("", "export-*.zip")
+	if err != nil {
+		return "", 0, fmt.Errorf("failed to create temp file: %w", err)
+	}
+	defer func() {
+		tmp.Close()
+		os.Remove(tmp.Name())
+	}()
+
+	zw := zip.NewWriter(tmp)
+	m, err := s.writeContents(ctx, zw, export, assistantFiles)
+	if err != nil {
+		return "", 0, err
+	}
+	if err := writeJSON(zw, "manifest.json", m); err != nil {
+		return "", 0, err
+	}
+	if err := zw.Close(); err != nil {
+		return "", 0, fmt.Errorf("failed to finish archive: %w", err)
+	}
+
+	if err := s.repo.UpdateOrganizationExportProgress(ctx, export.ID, domain.ExportStageUpload, 0, 1); err != nil {
+		return "", 0, err
+	}
+
+	size, err := tmp.Seek(0, io.SeekEnd)
+	if err != nil {
+		return "", 0, fmt.Errorf("failed to measure archive: %w", err)
+	}
+	if _, err := tmp.Seek(0, io.SeekStart); err != nil {
+		return "", 0, fmt.Errorf("failed to rewind archive: %w", err)
+	}
+
+	key := export.ArchiveKey()
+	if err := s.storage.PutObject(ctx, key, tmp, "application/zip"); err != nil {
+		return "", 0, err
+	}
+
+	// Files of llm-service are in the archive now
+	if _, err := s.storage.DeletePrefix(ctx, strings.TrimSuffix(key, ".zip")+"/"); err != nil {
+		logger.Warnf(ctx, "export: failed to delete intermediate files of export %s: %v", export.ID, err)
+	}
+
+	return key, size, nil
+}
+
+// writeContents writes the JSON manifests of every kind of data and then copies the original files
+func (s *Service) writeContents(ctx context.Context, zw *zip.Writer, export domain.OrganizationExport, assistantFiles []domain.ExportFile) (manifest, error) {
+	if err := s.repo.UpdateOrganizationExportProgress(ctx, export.ID, domain.ExportStageDatabase, 0, 0); err != nil {
+		return manifest{}, err
+	}
+
+	org, err := s.repo.GetOrganization(ctx, export.OrganizationID)
+	if err != nil {
+		return manifest{}, fmt.Errorf("failed to get organization: %w", err)
+	}
+
+	m := manifest{
+		FormatVersion:    formatVersion,
+		OrganizationID:   org.ID,
+		OrganizationName: org.Name,
+		RequestedBy:      export.RequestedBy,
+		ExportedAt:       time.Now().UTC(),
+	}
+	add := func(name string, items int, v any) error {
+		m.Contents = append(m.Contents, manifestPart{Name: name, Items: items})
+		return writeJSON(zw, name, v)
+	}
+
+	if err := add("organization.json", 1, organizationRecord{
+		ID:          org.ID,
+		Name:        org.Name,
+		Industry:    org.Industry,
+		Region:      org.Region,
+		Description: org.Description,
+		Profile:     rawJSON(org.ProfileData),
+		CreatedAt:   org.CreatedAt,
+		UpdatedAt:   org.UpdatedAt,
+	}); err != nil {
+		return manifest{}, err
+	}
+
+	members, err := s.members(ctx, org.ID)
+	if err != nil {
+		return manifest{}, err
+	}
+	if err := add("members.json", len(members), members); err != nil {
+		return manifest{}, err
+	}
+
+	documents, err := s.documents(ctx, org.ID)
+	if err != nil {
+		return manifest{}, err
+	}
+	notes, err := s.repo.ListNotes(ctx, org.ID, notesLimit)
+	if err != nil {
+		return manifest{}, fmt.Errorf("failed to list notes: %w", err)
+	}
+	contracts, err := s.contracts(ctx, org.ID)
+	if err != nil {
+		return manifest{}, err
+	}
+
+	// Originals are copied before the manifests that reference them, so the manifests record missing files
+	var files []archiveFile
+	for i := range documents {
+		doc := &documents[i]
+		doc.File = path.Join("documents", doc.ID.String(), safeFileName(doc.Name, doc.ID.String()))
+		files = append(files, archiveFile{name: doc.File, s3Key: doc.s3Key, record: &doc.fileRecord})
+	}
+	for i := range contracts {
+		c := &contracts[i]
+		c.File = path.Join("contracts", c.ID.String(), contractFileName(c.Name, c.s3Key, c.ID.String()))
+		files = append(files, archiveFile{name: c.File, s3Key: c.s3Key, record: &c.fileRecord})
+	}
+	// llm-service writes its files next to the archive; anything else must not get into it
+	prefix := strings.TrimSuffix(export.ArchiveKey(), ".zip") + "/"
+	for _, file := range assistantFiles {
+		if !strings.HasPrefix(file.S3Key, prefix) {
+			return manifest{}, fmt.Errorf("assistant file %s is outside of the export prefix", file.Name)
+		}
+		files = append(files, archiveFile{name: file.Name, s3Key: file.S3Key})
+		m.Contents = append(m.Contents, manifestPart{Name: file.Name, Items: file.Items})
+	}
+
+	missing, err := s.copyFiles(ctx, zw, export.ID, files)
+	if err != nil {
+		return manifest{}, err
+	}
+	m.MissingFiles = missing
+
+	noteRecords := make([]noteRecord, 0, len(notes))
+	for _, note := range notes {
+		noteRecords = append(noteRecords, noteRecord{ID: note.ID, Content: note.Content, CreatedAt: note.CreatedAt})
+	}
+	if err := add("documents.json", len(documents), documents); err != nil {
+		return manifest{}, err
+	}
+	if err := add("notes.json", len(noteRecords), noteRecords); err != nil {
+		return manifest{}, err
+	}
+	if err := add("contracts.json", len(contracts), contracts); err != nil {
+		return manifest{}, err
+	}
+
+	return m, nil
+}
+
+func (s *Service) members(ctx context.Context, organizationID domain.ID) ([]memberRecord, error) {
+	var records []memberRecord
+	for offset := 0; ; offset += listPageSize {
+		users, total, err := s.repo.ListUsers(ctx, organizationID, listPageSize, offset)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list members: %w", err)
+		}
+		for _, u := range users {
+			records = append(records, memberRecord{
+				UserID:     u.User.ID,
+				FirstName:  u.FirstName,
+				LastName:   u.LastName,
+				TelegramID: u.TelegramID,
+				Email:      u.Email,
+				Role:       u.Role,
+				CustomRole: u.CustomRoleName,
+				Status:     u.Status,
+				JoinedAt:   u.JoinedAt,
+			})
+		}
+		if len(users) == 0 || offset+len(users) >= total {
+			return records, nil
+		}
+	}
+}
+
+func (s *Service) documents(ctx context.Context, organizationID domain.ID) ([]documentRecord, error) {
+	var records []documentRecord
+	for offset := 0; ; offset += listPageSize {
+		docs, total, err := s.repo.ListDocuments(ctx, organizationID, nil, listPageSize, offset)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list documents: %w", err)
+		}
+		for _, doc := range docs {
+			records = append(records, documentRecord{
+				ID:           doc.ID,
+				Name:         doc.Name,
+				FileType:     doc.FileType,
+				FileSize:     doc.FileSize,
+				Status:       doc.Status,
+				ErrorMessage: doc.ErrorMessage,
+				CreatedAt:    doc.CreatedAt,
+				s3Key:        doc.S3Key,
+			})
+		}
+		if len(docs) == 0 || offset+len(docs) >= total {
+			return records, nil
+		}
+	}
+}
+
+func (s *Service) contracts(ctx context.Context, organizationID domain.ID) ([]contractRecord, error) {
+	var records []contractRecord
+	for offset := 0; ; offset += listPageSize {
+		contracts, total, err := s.repo.ListContracts(ctx, organizationID, listPageSize, offset)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list contracts: %w", err)
+		}
+		for _, c := range contracts {
+			records = append(records, contractRecord{
+				ID:         c.ID,
+				TemplateID: c.TemplateID,
+				Name:       c.Name,
+				FilledData: rawJSON(c.FilledData),
+				FileType:   c.FileType,
+				CreatedAt:  c.CreatedAt,
+				s3Key:      c.S3Key,
+			})
+		}
+		if len(contracts) == 0 || offset+len(contracts) >= total {
+			return records, nil
+		}
+	}
+}
+
+// copyFiles copies objects from storage into the archive and returns how many could not be read.
+// A missing original does not fail the export: the error is recorded next to the file in its manifest.
+func (s *Service) copyFiles(ctx context.Context, zw *zip.Writer, exportID domain.ID, files []archiveFile) (int, error) {
+	missing := 0
+	for i, file := range files {
+		if i%progressEvery == 0 {
+			if err := s.repo.UpdateOrganizationExportProgress(ctx, exportID, domain.ExportStageFiles, i, len(files)); err != nil {
+				return 0, err
+			}
+		}
+
+		err := s.copyFile(ctx, zw, file.name, file.s3Key)
+		if err == nil {
+			continue
+		}
+		if file.record == nil {
+			// Chats and memory are the files llm-service has just written, losing them is a failure
+			return 0, err
+		}
+		if errors.Is(err, errArchiveWrite) {
+			return 0, err
+		}
+
+		logger.Warnf(ctx, "export: failed to copy %s into export %s: %v", file.s3Key, exportID, err)
+		file.record.File = ""
+		file.record.FileError = "file is not available in storage"
+		missing++
+	}
+
+	return missing, s.repo.UpdateOrganizationExportProgress(ctx, exportID, domain.ExportStageFiles, len(files), len(files))
+}
+
+func (s *Service) copyFile(ctx context.Context, zw *zip.Writer, name, key string) error {
+	body, err := s.storage.GetObject(ctx, key)
+	if err != nil {
+		return err
+	}
+	defer body.Close()
+
+	w, err := zw.Create(name)
+	if err != nil {
+		return fmt.Errorf("%w: %v", errArchiveWrite, err)
+	}
+	if _, err := io.Copy(w, body); err != nil {
+		return fmt.Errorf("%w: %v", errArchiveWrite, err)
+	}
+
+	return nil
+}
+
+func writeJSON(zw *zip.Writer, name string, v any) error {
+	w, err := zw.Create(name)
+	if err != nil {
+		return fmt.Errorf("failed to add %s: %w", name, err)
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(v); err != nil {
+		return fmt.Errorf("failed to write %s: %w", name, err)
+	}
+
+	return nil
+}
+
+// rawJSON embeds stored JSON as is; anything else is written as a string
+func rawJSON(data string) json.RawMessage {
+	if data == "" {
+		return json.RawMessage("null")
+	}
+	if json.Valid([]byte(data)) {
+		return json.RawMessage(data)
+	}
+	encoded, _ := json.Marshal(data)
+	return encoded
+}
+
+// safeFileName keeps the original file name but strips directories so it cannot escape its folder
+func safeFileName(name, fallback string) string {
+	name = path.Base(strings.ReplaceAll(name, "\\", "/"))
+	if name == "." || name == "/" || name == ".." || name == "" {
+		return fallback
+	}
+	return name
+}
+
+// contractFileName names a generated contract after the contract, with the extension of the stored file
+func contractFileName(name, s3Key, fallback string) string {
+	name = safeFileName(name, fallback)
+	if ext := path.Ext(s3Key); ext != "" && !strings.HasSuffix(name, ext) {
+		name += ext
+	}
+	return name
+}
@@ -0,0 +1,336 @@
+package export
+
+import (
+	"context"
+	"core-service/internal/domain"
+	"core-service/internal/logger"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/opentracing/opentracing-go"
+)
+
+// expiredBatchSize - how many expired archives are deleted per maintenance run
+const expiredBatchSize = 100
+
+type repository interface {
+	GetOrganization(ctx context.Context, id domain.ID) (domain.Organization, error)
+	ListUsers(ctx context.Context, organizationID domain.ID, limit, offset int) ([]domain.UserWithMembership, int, error)
+	ListDocuments(ctx context.Context, organizationID domain.ID, status *domain.DocumentStatus, limit, offset int) ([]domain.Document, int, error)
+	ListNotes(ctx context.Context, organizationID domain.ID, limit int) ([]domain.Note, error)
+	ListContracts(ctx context.Context, organizationID domain.ID, limit, offset int) ([]domain.GeneratedContract, int, error)
+	CreateOrganizationExport(ctx context.Context, export domain.OrganizationExport) (domain.OrganizationExport, error)
+	GetOrganizationExport(ctx context.Context, id domain.ID) (domain.OrganizationExport, error)
+	ListOrganizationExports(ctx context.Context, organizationID domain.ID, limit, offset int) ([]domain.OrganizationExport, int, error)
+	StartOrganizationExport(ctx context.Context, id domain.ID) (domain.OrganizationExport, error)
+	UpdateOrganizationExportProgress(ctx context.Context, id domain.ID, stage domain.ExportStage, processed, total int) error
+	CompleteOrganizationExport(ctx context.Context, id domain.ID, s3Key string, fileSize int64, expiresAt time.Time) (domain.OrganizationExport, error)
+	FailOrganizationExport(ctx context.Context, id domain.ID, errorMessage string) (domain.OrganizationExport, error)
+	FailStalledOrganizationExports(ctx context.Context, updatedBefore time.Time, errorMessage string) ([]domain.OrganizationExport, error)
+	ListExpiredOrganizationExports(ctx context.Context, now time.Time, limit int) ([]domain.OrganizationExport, error)
+	ExpireOrganizationExport(ctx context.Context, id domain.ID) error
+}
+
+type objectStorage interface {
+	GetObject(ctx context.Context, key string) (io.ReadCloser, error)
+	PutObject(ctx context.Context, key string, body io.ReadSeeker, contentType string) error
+	DeleteObject(ctx context.Context, key string) error
+	DeletePrefix(ctx context.Context, prefix string) (int, error)
+	GeneratePresignedDownloadURL(ctx context.Context, key string, expiration time.Duration) (string, error)
+}
+
+type publisher interface {
+	PublishMessage(ctx context.Context, message interface{}) error
+}
+
+type auditRecorder interface {
+	Record(ctx context.Context, event domain.AuditEvent)
+}
+
+type config interface {
+	GetExportLinkTTL() time.Duration
+	GetExportTimeout() time.Duration
+}
+
+type Service struct {
+	repo        repository
+	storage     objectStorage
+	llmRequests publisher
+	events      publisher
+	audit       auditRecorder
+	cfg         config
+}
+
+// New creates the export service. llmRequests is the queue llm-service reads export requests from;
+// events may be nil when notifications are disabled.
+func New(repo repository, storage objectStorage, llmRequests, events publisher, audit auditRecorder, cfg config) *Service {
+	return &Service{
+		repo:        repo,
+		storage:     storage,
+		llmRequests: llmRequests,
+		events:      events,
+		audit:       audit,
+		cfg:         cfg,
+	}
+}
+
+// ExportOrganization starts an export of all organization data. Chats and memory are exported by
+// llm-service first; the archive is built when its files arrive through the results queue.
+func (s *Service) ExportOrganization(ctx context.Context, organizationID, requestedBy domain.ID) (domain.OrganizationExport, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "service.export.ExportOrganization")
+	defer span.Finish()
+
+	if _, err := s.repo.GetOrganization(ctx, organizationID); err != nil {
+		return domain.OrganizationExport{}, err
+	}
+
+	created, err := s.repo.CreateOrganizationExport(ctx, domain.NewOrganizationExport(organizationID, requestedBy))
+	if err != nil {
+		if errors.Is(err, domain.ErrAlreadyExists) {
+			return domain.OrganizationExport{}, domain.NewAlreadyExistsError("organization export is already running")
+		}
+		return domain.OrganizationExport{}, err
+	}
+
+	err = s.llmRequests.PublishMessage(ctx, domain.ExportRequest{
+		ExportID:       created.ID,
+		OrganizationID: organizationID,
+		RequestedAt:    time.Now(),
+	})
+	if err != nil {
+		logger.Errorf(ctx, "export: failed to request assistant data for export %s: %v", created.ID, err)
+		if _, failErr := s.repo.FailOrganizationExport(ctx, created.ID, "failed to request assistant data"); failErr != nil {
+			logger.Errorf(ctx, "export: failed to mark export %s failed: %v", created.ID, failErr)
+		}
+		return domain.OrganizationExport{}, fmt.Errorf("failed to start export: %w", err)
+	}
+
+	s.audit.Record(ctx, domain.NewAuditEvent(domain.AuditActionOrganizationExportRequested, &organizationID,
+		domain.AuditResourceExport, created.ID.String(), nil))
+
+	return created, nil
+}
+
+// GetExport returns an export of the organization
+func (s *Service) GetExport(ctx context.Context, organizationID, exportID domain.ID) (domain.OrganizationExport, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "service.export.GetExport")
+	defer span.Finish()
+
+	export, err := s.repo.GetOrganizationExport(ctx, exportID)
+	if err != nil {
+		return domain.OrganizationExport{}, err
+	}
+	if export.OrganizationID != organizationID {
+		return domain.OrganizationExport{}, domain.ErrNotFound
+	}
+
+	return export, nil
+}
+
+// ListExports lists exports of the organization, newest first
+func (s *Service) ListExports(ctx context.Context, organizationID domain.ID, page, pageSize int) ([]domain.OrganizationExport, int, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "service.export.ListExports")
+	defer span.Finish()
+
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 || pageSize > 100 {
+		pageSize = 20
+	}
+
+	offset := (page - 1) * pageSize
+	return s.repo.ListOrganizationExports(ctx, organizationID, pageSize, offset)
+}
+
+// DownloadURL returns a presigned link to the archive that is valid until the archive expires
+func (s *Service) DownloadURL(ctx context.Context, export domain.OrganizationExport) (string, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "service.export.DownloadURL")
+	defer span.Finish()
+
+	now := time.Now()
+	if !export.IsDownloadable(now) {
+		return "", domain.NewInvalidArgumentError("export archive is not available")
+	}
+
+	expiration := s.cfg.GetExportLinkTTL()
+	if export.ExpiresAt != nil && export.ExpiresAt.Sub(now) < expiration {
+		expiration = export.ExpiresAt.Sub(now)
+	}
+
+	return s.storage.GeneratePresignedDownloadURL(ctx, *export.S3Key, expiration)
+}
+
+// HandleMessage decodes a result from the export results queue and builds the archive.
+// Malformed results are dropped, storage errors are returned so the message is retried.
+func (s *Service) HandleMessage(ctx context.Context, body []byte) error {
+	var result domain.ExportPartResult
+	if err := json.Unmarshal(body, &result); err != nil {
+		logger.Warnf(ctx, "export: dropping malformed result: %v", err)
+		return nil
+	}
+
+	err := s.HandlePartResult(ctx, result)
+	if errors.Is(err, domain.ErrInvalidArgument) || errors.Is(err, domain.ErrNotFound) {
+		logger.Warnf(ctx, "export: dropping result for export %s: %v", result.ExportID, err)
+		return nil
+	}
+	return err
+}
+
+// HandlePartResult builds the archive once llm-service has exported its data.
+// Results for exports that are no longer pending are ignored, so redelivered messages are harmless.
+func (s *Service) HandlePartResult(ctx context.Context, result domain.ExportPartResult) error {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "service.export.HandlePartResult")
+	defer span.Finish()
+
+	if err := result.Validate(); err != nil {
+		return err
+	}
+
+	if result.Status == domain.ExportStatusFailed {
+		s.fail(ctx, result.ExportID, "failed to export assistant data: "+result.Error)
+		return nil
+	}
+
+	export, err := s.repo.StartOrganizationExport(ctx, result.ExportID)
+	if errors.Is(err, domain.ErrNotFound) {
+		logger.Infof(ctx, "export: ignoring result from %s for export %s that is not pending", result.Source, result.ExportID)
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	key, size, err := s.build(ctx, export, result.Files)
+	if err != nil {
+		logger.Errorf(ctx, "export: failed to build archive for export %s: %v", export.ID, err)
+		s.fail(ctx, export.ID, err.Error())
+		return nil
+	}
+
+	completed, err := s.repo.CompleteOrganizationExport(ctx, export.ID, key, size, time.Now().Add(s.cfg.GetExportLinkTTL()))
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			// The export timed out while the archive was uploaded
+			logger.Warnf(ctx, "export: export %s finished after it was marked failed", export.ID)
+			s.removeArchive(ctx, key)
+			return nil
+		}
+		return err
+	}
+
+	s.audit.Record(ctx, domain.NewAuditEvent(domain.AuditActionOrganizationExported, &completed.OrganizationID,
+		domain.AuditResourceExport, completed.ID.String(), map[string]any{"file_size": completed.FileSize}))
+	s.notifyCompleted(ctx, completed)
+
+	return nil
+}
+
+// RunMaintenance fails exports that have stopped making progress and deletes expired archives
+func (s *Service) RunMaintenance(ctx context.Context) error {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "service.export.RunMaintenance")
+	defer span.Finish()
+
+	stalled, err := s.repo.FailStalledOrganizationExports(ctx, time.Now().Add(-s.cfg.GetExportTimeout()), "export timed out")
+	if err != nil {
+		return err
+	}
+	for _, export := range stalled {
+		logger.Warnf(ctx, "export: export %s of organization %s timed out", export.ID, export.OrganizationID)
+		s.notifyFailed(ctx, export)
+	}
+
+	expired, err := s.repo.ListExpiredOrganizationExports(ctx, time.Now(), expiredBatchSize)
+	if err != nil {
+		return err
+	}
+	for _, export := range expired {
+		if export.S3Key != nil {
+			if err := s.storage.DeleteObject(ctx, *export.S3Key); err != nil {
+				logger.Errorf(ctx, "export: failed to delete expired archive of export %s: %v", export.ID, err)
+				continue
+			}
+		}
+		if err := s.repo.ExpireOrganizationExport(ctx, export.ID); err != nil && !errors.Is(err, domain.ErrNotFound) {
+			logger.Errorf(ctx, "export: failed to expire export %s: %v", export.ID, err)
+		}
+	}
+
+	return nil
+}
+
+// fail marks the export failed and notifies the requester; exports that have already finished are left as is
+func (s *Service) fail(ctx context.Context, id domain.ID, message string) {
+	failed, err := s.repo.FailOrganizationExport(ctx, id, message)
+	if errors.Is(err, domain.ErrNotFound) {
+		return
+	}
+	if err != nil {
+		logger.Errorf(ctx, "export: failed to mark export %s failed: %v", id, err)
+		return
+	}
+
+	s.notifyFailed(ctx, failed)
+}
+
+func (s *Service) notifyCompleted(ctx context.Context, export domain.OrganizationExport) {
+	url, err := s.DownloadURL(ctx, export)
+	if err != nil {
+		logger.Errorf(ctx, "export: failed to generate download url for export %s: %v", export.ID, err)
+		return
+	}
+
+	s.notify(ctx, export, "Выгрузка данных готова", fmt.Sprintf(
+		"Архив с данными организации %s готов (%.1f МБ). Ссылка действует до %s:\n%s",
+		s.organizationName(ctx, export.OrganizationID),
+		float64(export.FileSize)/(1024*1024),
+		export.ExpiresAt.Format("02.01.2006 15:04"),
+		url,
+	))
+}
+
+func (s *Service) notifyFailed(ctx context.Context, export domain.OrganizationExport) {
+	s.notify(ctx, export, "Не удалось выгрузить данные", fmt.Sprintf(
+		"Выгрузка данных организации %s не удалась. Запросите ее еще раз",
+		s.organizationName(ctx, export.OrganizationID),
+	))
+}
+
+func (s *Service) notify(ctx context.Context, export domain.OrganizationExport, title, text string) {
+	if s.events == nil || export.RequestedBy == nil {
+		return
+	}
+
+	event := domain.NotificationEvent{
+		EventID:        "export.finished:" + export.ID.String(),
+		Type:           domain.NotificationEventExportFinished,
+		Source:         "core-service",
+		OrganizationID: &export.OrganizationID,
+		UserID:         export.RequestedBy,
+		Title:          title,
+		Text:           text,
+		OccurredAt:     time.Now(),
+	}
+
+	if err := s.events.PublishMessage(ctx, event); err != nil {
+		logger.Errorf(ctx, "failed to publish export finished event: %v", err)
+	}
+}
+
+func (s *Service) organizationName(ctx context.Context, organizationID domain.ID) string {
+	org, err := s.repo.GetOrganization(ctx, organizationID)
+	if err != nil {
+		return organizationID.String()
+	}
+	return org.Name
+}
+
+func (s *Service) removeArchive(ctx context.Context, key string) {
+	if err := s.storage.DeleteObject(ctx, key); err != nil {
+		logger.Errorf(ctx, "export: failed to delete archive %s: %v", key, err)
+	}
+}
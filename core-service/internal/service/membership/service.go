@@ -0,0 +1,91 @@
+package membership
+
+import (
+	"context"
+	"core-service/internal/domain"
+	"sync"
+	"time"
+
+	"github.com/opentracing/opentracing-go"
+)
+
+type repository interface {
+	GetOrganizationMember(ctx context.Context, userID, organizationID domain.ID) (*domain.OrganizationMember, error)
+}
+
+type memberKey struct {
+	userID         domain.ID
+	organizationID domain.ID
+}
+
+type cachedMember struct {
+	member    domain.OrganizationMember
+	expiresAt time.Time
+}
+
+// Service answers membership lookups for request authorization.
+// Found memberships are cached for ttl, so role changes and deactivation take effect within ttl;
+// misses are never cached, so a freshly created organization or accepted invitation works immediately.
+type Service struct {
+	repo repository
+	ttl  time.Duration
+
+	mu          sync.Mutex
+	members     map[memberKey]cachedMember
+	lastEvicted time.Time
+}
+
+// New creates the membership service; ttl <= 0 disables caching
+func New(repo repository, ttl time.Duration) *Service {
+	return &Service{
+		repo:    repo,
+		ttl:     ttl,
+		members: make(map[memberKey]cachedMember),
+	}
+}
+
+// GetOrganizationMember returns the membership of a user in an organization or domain.ErrNotFound
+func (s *Service) GetOrganizationMember(ctx context.Context, userID, organizationID domain.ID) (*domain.OrganizationMember, error) {
+	key := memberKey{userID: userID, organizationID: organizationID}
+	now := time.Now()
+
+	s.mu.Lock()
+	cached, ok := s.members[key]
+	s.mu.Unlock()
+	if ok && now.Before(cached.expiresAt) {
+		member := cached.member
+		return &member, nil
+	}
+
+	span, ctx := opentracing.StartSpanFromContext(ctx, "service.membership.GetOrganizationMember")
+	defer span.Finish()
+
+	member, err := s.repo.GetOrganizationMember(ctx, userID, organizationID)
+	if err != nil {
+		return nil, err
+	}
+
+	if s.ttl > 0 {
+		s.mu.Lock()
+		s.evictExpired(now)
+		s.members[key] = cachedMember{member: *member, expiresAt: now.Add(s.ttl)}
+		s.mu.Unlock()
+	}
+
+	return member, nil
+}
+
+// evictExpired drops stale entries at most once per ttl so the cache does not grow with every user seen;
+// caller holds mu
+func (s *Service) evictExpired(now time.Time) {
+	if now.Sub(s.lastEvicted) < s.ttl {
+		return
+	}
+	s.lastEvicted = now
+
+	for key, cached := range s.members {
+		if !now.Before(cached.expiresAt) {
+			delete(s.members, key)
+		}
+	}
+}
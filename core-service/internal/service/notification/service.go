@@ -0,0 +1,271 @@
+package notification
+
+import (
+	"context"
+	"core-service/internal/domain"
+	"core-service/internal/logger"
+	"core-service/internal/notifier"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html"
+	"slices"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/opentracing/opentracing-go"
+)
+
+// maxTextLength keeps messages below the Telegram limit of 4096 characters
+const maxTextLength = 3500
+
+type repository interface {
+	GetUser(ctx context.Context, id domain.ID) (domain.User, error)
+	ListOrganizationAdmins(ctx context.Context, organizationID domain.ID) ([]domain.User, error)
+	GetNotificationPreferences(ctx context.Context, userID domain.ID) (domain.NotificationPreferences, error)
+	UpsertNotificationPreferences(ctx context.Context, prefs domain.NotificationPreferences) (domain.NotificationPreferences, error)
+	ClaimNotificationDelivery(ctx context.Context, delivery domain.NotificationDelivery, staleBefore time.Time) (bool, error)
+	UpdateNotificationDelivery(ctx context.Context, eventID string, userID domain.ID, status domain.NotificationDeliveryStatus, errorMessage string) error
+	CountSentNotifications(ctx context.Context, userID domain.ID, since time.Time) (int, error)
+}
+
+type config interface {
+	GetNotificationsRateLimit() int
+	GetNotificationsRateWindow() time.Duration
+	GetNotificationsPendingTimeout() time.Duration
+	GetTelegramMiniAppURL() string
+}
+
+type Service struct {
+	repo     repository
+	notifier notifier.Notifier
+	cfg      config
+}
+
+func New(repo repository, notifier notifier.Notifier, cfg config) *Service {
+	return &Service{
+		repo:     repo,
+		notifier: notifier,
+		cfg:      cfg,
+	}
+}
+
+// HandleMessage decodes a domain event from the notifications queue and delivers it.
+// Malformed events are dropped, delivery errors are returned so the message is retried.
+func (s *Service) HandleMessage(ctx context.Context, body []byte) error {
+	var event domain.NotificationEvent
+	if err := json.Unmarshal(body, &event); err != nil {
+		logger.Warnf(ctx, "notification: dropping malformed event: %v", err)
+		return nil
+	}
+
+	err := s.HandleEvent(ctx, event)
+	if errors.Is(err, domain.ErrInvalidArgument) {
+		logger.Warnf(ctx, "notification: dropping invalid event %q: %v", event.EventID, err)
+		return nil
+	}
+	return err
+}
+
+// HandleEvent notifies the recipients of an event: the addressed user or, if none, the organization admins
+func (s *Service) HandleEvent(ctx context.Context, event domain.NotificationEvent) error {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "service.notification.HandleEvent")
+	defer span.Finish()
+
+	if event.EventID == "" {
+		return domain.NewInvalidArgumentError("event_id is required")
+	}
+	if !event.Type.IsValid() {
+		return domain.NewInvalidArgumentError(fmt.Sprintf("unknown event type %q", event.Type))
+	}
+	if strings.TrimSpace(event.Title) == "" && strings.TrimSpace(event.Text) == "" {
+		return domain.NewInvalidArgumentError("event has no title or text")
+	}
+
+	recipients, err := s.recipients(ctx, event)
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	for _, user := range recipients {
+		if err := s.deliver(ctx, event, user); err != nil {
+			errs = append(errs, fmt.Errorf("user %s: %w", user.ID, err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// recipients resolves who should be notified about the event
+func (s *Service) recipients(ctx context.Context, event domain.NotificationEvent) ([]domain.User, error) {
+	var users []domain.User
+
+	switch {
+	case event.UserID != nil:
+		user, err := s.repo.GetUser(ctx, *event.UserID)
+		if err != nil {
+			if errors.Is(err, domain.ErrNotFound) {
+				return nil, domain.NewInvalidArgumentError("recipient not found")
+			}
+			return nil, err
+		}
+		users = append(users, user)
+	case event.OrganizationID != nil:
+		admins, err := s.repo.ListOrganizationAdmins(ctx, *event.OrganizationID)
+		if err != nil {
+			return nil, err
+		}
+		users = admins
+	default:
+		return nil, domain.NewInvalidArgumentError("event has neither user_id nor organization_id")
+	}
+
+	recipients := make([]domain.User, 0, len(users))
+	for _, user := range users {
+		if event.ActorUserID != nil && user.ID == *event.ActorUserID {
+			continue
+		}
+		recipients = append(recipients, user)
+	}
+
+	return recipients, nil
+}
+
+// deliver sends the event to one user at most once. A delivery left pending longer than
+// the pending timeout belongs to a handler that died before recording the result and is retried.
+func (s *Service) deliver(ctx context.Context, event domain.NotificationEvent, user domain.User) error {
+	now := time.Now()
+	claimed, err := s.repo.ClaimNotificationDelivery(ctx, domain.NotificationDelivery{
+		EventID:   event.EventID,
+		UserID:    user.ID,
+		EventType: event.Type,
+		Status:    domain.NotificationDeliveryPending,
+		CreatedAt: now,
+	}, now.Add(-s.cfg.GetNotificationsPendingTimeout()))
+	if err != nil {
+		return err
+	}
+	if !claimed {
+		logger.Debugf(ctx, "notification: event %s already delivered to user %s", event.EventID, user.ID)
+		return nil
+	}
+
+	status, sendErr := s.send(ctx, event, user)
+
+	errorMessage := ""
+	if sendErr != nil {
+		status = domain.NotificationDeliveryFailed
+		errorMessage = sendErr.Error()
+	}
+
+	if err := s.repo.UpdateNotificationDelivery(ctx, event.EventID, user.ID, status, errorMessage); err != nil {
+		return err
+	}
+
+	if status != domain.NotificationDeliverySent {
+		logger.Info(ctx, "notification not sent",
+			"event_id", event.EventID,
+			"event_type", event.Type,
+			"user_id", user.ID,
+			"status", status,
+		)
+	}
+
+	return sendErr
+}
+
+// send applies preferences and the rate limit and sends the message
+func (s *Service) send(ctx context.Context, event domain.NotificationEvent, user domain.User) (domain.NotificationDeliveryStatus, error) {
+	if !user.IsActive || user.TelegramID == "" {
+		return domain.NotificationDeliveryNoChannel, nil
+	}
+
+	prefs, err := s.repo.GetNotificationPreferences(ctx, user.ID)
+	if err != nil {
+		return "", err
+	}
+	if !prefs.Allows(event.Type) {
+		return domain.NotificationDeliveryMuted, nil
+	}
+
+	if limit := s.cfg.GetNotificationsRateLimit(); limit > 0 {
+		// The count includes the delivery claimed for this event
+		count, err := s.repo.CountSentNotifications(ctx, user.ID, time.Now().Add(-s.cfg.GetNotificationsRateWindow()))
+		if err != nil {
+			return "", err
+		}
+		if count > limit {
+			return domain.NotificationDeliveryRateLimited, nil
+		}
+	}
+
+	if err := s.notifier.Send(ctx, s.message(event, user)); err != nil {
+		return "", err
+	}
+
+	return domain.NotificationDeliverySent, nil
+}
+
+func (s *Service) message(event domain.NotificationEvent, user domain.User) notifier.Message {
+	var text strings.Builder
+	if title := strings.TrimSpace(event.Title); title != "" {
+		text.WriteString("<b>" + html.EscapeString(title) + "</b>")
+	}
+	if body := strings.TrimSpace(event.Text); body != "" {
+		if text.Len() > 0 {
+			text.WriteString("\n\n")
+		}
+		text.WriteString(html.EscapeString(truncate(body, maxTextLength)))
+	}
+
+	message := notifier.Message{
+		ChatID: user.TelegramID,
+		Text:   text.String(),
+	}
+	if url := s.cfg.GetTelegramMiniAppURL(); url != "" {
+		message.ButtonText = "Открыть"
+		message.ButtonURL = url
+	}
+
+	return message
+}
+
+// GetPreferences returns notification preferences of a user
+func (s *Service) GetPreferences(ctx context.Context, userID domain.ID) (domain.NotificationPreferences, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "service.notification.GetPreferences")
+	defer span.Finish()
+
+	return s.repo.GetNotificationPreferences(ctx, userID)
+}
+
+// UpdatePreferences replaces notification preferences of a user
+func (s *Service) UpdatePreferences(ctx context.Context, userID domain.ID, telegramEnabled bool, mutedEvents []domain.NotificationEventType) (domain.NotificationPreferences, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "service.notification.UpdatePreferences")
+	defer span.Finish()
+
+	muted := make([]domain.NotificationEventType, 0, len(mutedEvents))
+	for _, eventType := range mutedEvents {
+		if !eventType.IsValid() {
+			return domain.NotificationPreferences{}, domain.NewInvalidArgumentError(fmt.Sprintf("unknown event type %q", eventType))
+		}
+		if !slices.Contains(muted, eventType) {
+			muted = append(muted, eventType)
+		}
+	}
+
+	return s.repo.UpsertNotificationPreferences(ctx, domain.NotificationPreferences{
+		UserID:          userID,
+		TelegramEnabled: telegramEnabled,
+		MutedEvents:     muted,
+		UpdatedAt:       time.Now(),
+	})
+}
+
+func truncate(s string, limit int) string {
+	if utf8.RuneCountInString(s) <= limit {
+		return s
+	}
+	return string([]rune(s)[:limit]) + "…"
+}
@@ -13,16 +13,20 @@ type repository interface {
 	GetOrganization(ctx context.Context, id domain.ID) (domain.Organization, error)
 	GetOrganizationsByUserID(ctx context.Context, userID domain.ID) ([]domain.Organization, error)
 	UpdateOrganization(ctx context.Context, org domain.Organization) (domain.Organization, error)
-	DeleteOrganization(ctx context.Context, id domain.ID) error
 	CreateOrganizationMember(ctx context.Context, member domain.OrganizationMember) (domain.OrganizationMember, error)
 }
 
+type erasureScheduler interface {
+	ScheduleOrganizationErasure(ctx context.Context, organizationID, requestedBy domain.ID) (domain.DataErasure, error)
+}
+
 type Service struct {
-	repo repository
+	repo     repository
+	erasures erasureScheduler
 }
 
-func New(repo repository) *Service {
-	return &Service{repo: repo}
+func New(repo repository, erasures erasureScheduler) *Service {
+	return &Service{repo: repo, erasures: erasures}
 }
 
 // CreateOrganization creates a new organization and assigns creator as admin
@@ -35,6 +39,11 @@ func (s *Service) CreateOrganization(ctx context.Context, userID domain.ID, name
 		return domain.Organization{}, domain.NewInvalidArgumentError("organization name is required")
 	}
 
+	profileData, err := domain.NormalizeOrganizationProfile(profileData)
+	if err != nil {
+		return domain.Organization{}, err
+	}
+
 	org := domain.NewOrganization(name, industry, region, description, profileData)
 
 	created, err := s.repo.CreateOrganization(ctx, org)
@@ -90,7 +99,11 @@ func (s *Service) UpdateOrganization(ctx context.Context, id domain.ID, name, in
 		org.Description = *description
 	}
 	if profileData != nil {
-		org.ProfileData = *profileData
+		normalized, err := domain.NormalizeOrganizationProfile(*profileData)
+		if err != nil {
+			return domain.Organization{}, err
+		}
+		org.ProfileData = normalized
 	}
 
 	updated, err := s.repo.UpdateOrganization(ctx, org)
@@ -101,12 +114,14 @@ func (s *Service) UpdateOrganization(ctx context.Context, id domain.ID, name, in
 	return updated, nil
 }
 
-// DeleteOrganization soft deletes an organization
-func (s *Service) DeleteOrganization(ctx context.Context, id domain.ID) error {
+// DeleteOrganization hides an organization right away and schedules the purge of all its data
+// after the grace period
+func (s *Service) DeleteOrganization(ctx context.Context, id, requestedBy domain.ID) error {
 	span, ctx := opentracing.StartSpanFromContext(ctx, "service.organization.DeleteOrganization")
 	defer span.Finish()
 
-	return s.repo.DeleteOrganization(ctx, id)
+	_, err := s.erasures.ScheduleOrganizationErasure(ctx, id, requestedBy)
+	return err
 }
 
 // ListMyOrganizations retrieves all organizations where the user is a member
@@ -5,6 +5,7 @@ import (
 	"core-service/internal/domain"
 	"fmt"
 	"path/filepath"
+	"strings"
 	"time"
 
 	"github.com/opentracing/opentracing-go"
@@ -65,6 +66,11 @@ func (s *Service) GenerateDownloadURL(ctx context.Context, s3Key string) (string
 		return "", domain.NewInvalidArgumentError("s3 key is required")
 	}
 
+	// Архивы выгрузок содержат все данные организации и выдаются только через ExportService
+	if strings.HasPrefix(s3Key, "exports/") {
+		return "", domain.NewForbiddenError("export archives are downloaded through the export service")
+	}
+
 	url, err := s.s3Client.GeneratePresignedDownloadURL(ctx, s3Key, PresignedURLExpiration)
 	if err != nil {
 		return "", domain.NewInternalError("failed to generate download URL", err)
@@ -22,17 +22,23 @@ type queuePublisher interface {
 	PublishMessage(ctx context.Context, message interface{}) error
 }
 
+type auditRecorder interface {
+	Record(ctx context.Context, event domain.AuditEvent)
+}
+
 type Service struct {
 	repo  repository
 	queue queuePublisher
 	tx    *db.ContextManager
+	audit auditRecorder
 }
 
-func New(repo repository, queue queuePublisher, tx *db.ContextManager) *Service {
+func New(repo repository, queue queuePublisher, tx *db.ContextManager, audit auditRecorder) *Service {
 	return &Service{
 		repo:  repo,
 		queue: queue,
 		tx:    tx,
+		audit: audit,
 	}
 }
 
@@ -75,6 +81,10 @@ func (s *Service) CreateTemplate(ctx context.Context, name, description, templat
 		return domain.ContractTemplate{}, err
 	}
 
+	// Templates are shared by all organizations, so the event is not scoped to one
+	s.audit.Record(ctx, domain.NewAuditEvent(domain.AuditActionTemplateCreated, nil,
+		domain.AuditResourceTemplate, created.ID.String(), map[string]any{"name": created.Name}))
+
 	return created, nil
 }
 
@@ -122,6 +132,9 @@ func (s *Service) UpdateTemplate(ctx context.Context, id domain.ID, name, descri
 		return domain.ContractTemplate{}, err
 	}
 
+	s.audit.Record(ctx, domain.NewAuditEvent(domain.AuditActionTemplateUpdated, nil,
+		domain.AuditResourceTemplate, updated.ID.String(), map[string]any{"name": updated.Name}))
+
 	return updated, nil
 }
 
@@ -148,6 +161,9 @@ func (s *Service) DeleteTemplate(ctx context.Context, id domain.ID) error {
 		return err
 	}
 
+	s.audit.Record(ctx, domain.NewAuditEvent(domain.AuditActionTemplateDeleted, nil,
+		domain.AuditResourceTemplate, id.String(), nil))
+
 	return nil
 }
 
@@ -3,8 +3,10 @@ package user
 import (
 	"context"
 	"core-service/internal/domain"
+	"core-service/internal/logger"
 	"crypto/rand"
 	"encoding/base64"
+	"errors"
 	"fmt"
 	"time"
 
@@ -17,48 +19,102 @@ type repository interface {
 	GetUserByTelegramID(ctx context.Context, telegramID string) (domain.User, error)
 	ListUsers(ctx context.Context, organizationID domain.ID, limit, offset int) ([]domain.UserWithMembership, int, error)
 	UpdateUser(ctx context.Context, user domain.User) (domain.User, error)
-	UpdateUserRole(ctx context.Context, id domain.ID, role domain.UserRole) error
-	DeactivateUser(ctx context.Context, id domain.ID) error
 	CreateInvitation(ctx context.Context, invitation domain.Invitation) (domain.Invitation, error)
 	GetInvitationByToken(ctx context.Context, token string) (domain.Invitation, error)
 	GetInvitationByID(ctx context.Context, id domain.ID) (domain.Invitation, error)
 	ListInvitations(ctx context.Context, organizationID domain.ID, limit, offset int) ([]domain.Invitation, int, error)
-	MarkInvitationAsUsed(ctx context.Context, id domain.ID) error
+	UseInvitation(ctx context.Context, id, userID domain.ID) error
+	RevokeInvitation(ctx context.Context, id domain.ID) error
+	RegenerateInvitation(ctx context.Context, invitation domain.Invitation) (domain.Invitation, error)
+	ListInvitationUses(ctx context.Context, invitationID domain.ID, limit, offset int) ([]domain.InvitationUse, int, error)
 	DeleteInvitation(ctx context.Context, id domain.ID) error
 	CreateOrganizationMember(ctx context.Context, member domain.OrganizationMember) (domain.OrganizationMember, error)
 	GetOrganizationMember(ctx context.Context, userID, organizationID domain.ID) (*domain.OrganizationMember, error)
 	UpdateOrganizationMember(ctx context.Context, member domain.OrganizationMember) (domain.OrganizationMember, error)
+	LockOrganizationAdmins(ctx context.Context, organizationID domain.ID) (int, error)
+	GetOrganization(ctx context.Context, id domain.ID) (domain.Organization, error)
+	GetCustomRole(ctx context.Context, id domain.ID) (domain.CustomRole, error)
+}
+
+type transactor interface {
+	Do(ctx context.Context, f func(ctx context.Context) error) error
+}
+
+type eventPublisher interface {
+	PublishMessage(ctx context.Context, message interface{}) error
+}
+
+type auditRecorder interface {
+	Record(ctx context.Context, event domain.AuditEvent)
 }
 
 type Service struct {
-	repo repository
+	repo   repository
+	tx     transactor
+	events eventPublisher
+	audit  auditRecorder
 }
 
-func New(repo repository) *Service {
-	return &Service{repo: repo}
+// New creates the user service; events may be nil when notifications are disabled
+func New(repo repository, tx transactor, events eventPublisher, audit auditRecorder) *Service {
+	return &Service{repo: repo, tx: tx, events: events, audit: audit}
 }
 
-// InviteUser creates a new invitation for a user
-func (s *Service) InviteUser(ctx context.Context, organizationID domain.ID, role domain.UserRole, invitedBy domain.ID) (*domain.Invitation, error) {
+// InviteUser creates an invitation link. By default the link is single-use and expires in 7 days;
+// maxUses and ttl make it reusable for onboarding a whole team. Joining users get the built-in role
+// or, when customRoleID is set, the custom role of the organization.
+func (s *Service) InviteUser(ctx context.Context, organizationID domain.ID, actor *domain.OrganizationMember, role domain.UserRole, customRoleID *domain.ID, maxUses int, ttl time.Duration) (*domain.Invitation, error) {
 	span, ctx := opentracing.StartSpanFromContext(ctx, "service.user.InviteUser")
 	defer span.Finish()
 
+	if actor == nil {
+		return nil, domain.NewForbiddenError("you are not a member of this organization")
+	}
+	if maxUses == 0 {
+		maxUses = 1
+	}
+	if maxUses < 0 || maxUses > domain.MaxInvitationUses {
+		return nil, domain.NewInvalidArgumentError(fmt.Sprintf("max uses must be between 1 and %d", domain.MaxInvitationUses))
+	}
+	expiresAt, err := invitationExpiry(ttl)
+	if err != nil {
+		return nil, err
+	}
+
 	// Generate secure token
 	token, err := generateToken()
 	if err != nil {
 		return nil, domain.NewInternalError("failed to generate invitation token", err)
 	}
 
-	// Invitation expires in 7 days
-	expiresAt := time.Now().Add(7 * 24 * time.Hour)
+	invitation := domain.NewInvitation(organizationID, role, token, expiresAt, maxUses, &actor.UserID)
+	if customRoleID != nil {
+		if role == domain.UserRoleAdmin {
+			return nil, domain.NewInvalidArgumentError("custom roles can only be assigned to employees")
+		}
+		customRole, err := s.organizationRole(ctx, organizationID, *customRoleID)
+		if err != nil {
+			return nil, err
+		}
+		invitation.AssignCustomRole(customRole)
+	}
 
-	invitation := domain.NewInvitation(organizationID, role, token, expiresAt)
+	permissions, err := s.invitationPermissions(ctx, invitation)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkCanInvite(actor, invitation.Role, permissions); err != nil {
+		return nil, err
+	}
 
 	created, err := s.repo.CreateInvitation(ctx, invitation)
 	if err != nil {
 		return nil, err
 	}
 
+	s.audit.Record(ctx, domain.NewAuditEvent(domain.AuditActionInvitationCreated, &organizationID,
+		domain.AuditResourceInvitation, created.ID.String(), invitationMetadata(created)))
+
 	return &created, nil
 }
 
@@ -74,11 +130,8 @@ func (s *Service) AcceptInvitation(ctx context.Context, userID domain.ID, token
 	}
 
 	// Validate invitation
-	if invitation.IsExpired() {
-		return nil, domain.NewInvalidArgumentError("invitation has expired")
-	}
-	if invitation.IsUsed() {
-		return nil, domain.NewInvalidArgumentError("invitation has already been used")
+	if err := checkInvitationUsable(invitation); err != nil {
+		return nil, err
 	}
 
 	// Get user
@@ -94,26 +147,88 @@ func (s *Service) AcceptInvitation(ctx context.Context, userID domain.ID, token
 
 	// Check if user already member of this organization
 	existingMember, err := s.repo.GetOrganizationMember(ctx, userID, invitation.OrganizationID)
-	if err == nil && existingMember != nil && existingMember.Status == domain.UserStatusActive {
+	if err != nil && !errors.Is(err, domain.ErrNotFound) {
+		return nil, err
+	}
+	if existingMember != nil && existingMember.IsActive() {
 		return nil, domain.NewInvalidArgumentError("user is already a member of this organization")
 	}
+	if existingMember != nil && !existingMember.CanRejoinWith(invitation) {
+		return nil, domain.NewForbiddenError("membership was deactivated by an administrator, a new invitation is required")
+	}
 
-	// Create organization membership
+	// Create organization membership with the role pre-assigned by the link;
+	// a former member who left, or was deactivated before the link was created, gets the previous membership back
 	member := domain.NewOrganizationMember(invitation.OrganizationID, userID, "", invitation.Role)
-	_, err = s.repo.CreateOrganizationMember(ctx, member)
-	if err != nil {
-		return nil, err
+	if existingMember != nil {
+		member = *existingMember
+		member.Reactivate()
+		member.UpdateRole(invitation.Role)
+	}
+	if invitation.CustomRoleID != nil {
+		customRole, err := s.repo.GetCustomRole(ctx, *invitation.CustomRoleID)
+		if err != nil {
+			return nil, err
+		}
+		member.AssignCustomRole(customRole)
 	}
 
-	// Mark invitation as used
-	err = s.repo.MarkInvitationAsUsed(ctx, invitation.ID)
+	// Claiming a use and joining happen together, so concurrent joins cannot exceed the limit
+	err = s.tx.Do(ctx, func(txCtx context.Context) error {
+		if err := s.repo.UseInvitation(txCtx, invitation.ID, userID); err != nil {
+			if errors.Is(err, domain.ErrNotFound) {
+				return domain.NewInvalidArgumentError("invitation is no longer valid")
+			}
+			return err
+		}
+		if existingMember != nil {
+			_, err := s.repo.UpdateOrganizationMember(txCtx, member)
+			return err
+		}
+		_, err := s.repo.CreateOrganizationMember(txCtx, member)
+		return err
+	})
 	if err != nil {
 		return nil, err
 	}
 
+	s.publishInvitationAccepted(ctx, invitation, user)
+
+	event := domain.NewAuditEvent(domain.AuditActionInvitationAccepted, &invitation.OrganizationID,
+		domain.AuditResourceInvitation, invitation.ID.String(), invitationMetadata(invitation))
+	event.ActorUserID = &userID
+	s.audit.Record(ctx, event)
+
 	return &user, nil
 }
 
+// publishInvitationAccepted notifies organization admins that a user joined; failures are only logged
+func (s *Service) publishInvitationAccepted(ctx context.Context, invitation domain.Invitation, user domain.User) {
+	if s.events == nil {
+		return
+	}
+
+	orgName := "организации"
+	if org, err := s.repo.GetOrganization(ctx, invitation.OrganizationID); err == nil {
+		orgName = fmt.Sprintf("организации «%s»", org.Name)
+	}
+
+	event := domain.NotificationEvent{
+		EventID:        "invitation.accepted:" + invitation.ID.String() + ":" + user.ID.String(),
+		Type:           domain.NotificationEventInvitationAccepted,
+		Source:         "core-service",
+		OrganizationID: &invitation.OrganizationID,
+		ActorUserID:    &user.ID,
+		Title:          "Приглашение принято",
+		Text:           fmt.Sprintf("%s %s присоединился к %s", user.FirstName, user.LastName, orgName),
+		OccurredAt:     time.Now(),
+	}
+
+	if err := s.events.PublishMessage(ctx, event); err != nil {
+		logger.Errorf(ctx, "failed to publish invitation accepted event: %v", err)
+	}
+}
+
 // ListUsers retrieves users for an organization
 func (s *Service) ListUsers(ctx context.Context, organizationID domain.ID, page, pageSize int) ([]domain.UserWithMembership, int, error) {
 	span, ctx := opentracing.StartSpanFromContext(ctx, "service.user.ListUsers")
@@ -172,29 +287,228 @@ func (s *Service) GetUserByTelegramID(ctx context.Context, telegramID string) (*
 	return &user, nil
 }
 
-// UpdateUserRole updates a user's role
-func (s *Service) UpdateUserRole(ctx context.Context, id domain.ID, role domain.UserRole) (*domain.User, error) {
+// UpdateUserRole assigns a built-in role or, when customRoleID is set, a custom role of the organization.
+// Only admins may grant or revoke the admin role; other actors may only grant permissions they hold.
+func (s *Service) UpdateUserRole(ctx context.Context, organizationID, id domain.ID, actor *domain.OrganizationMember, role domain.UserRole, customRoleID *domain.ID) (*domain.UserWithMembership, error) {
 	span, ctx := opentracing.StartSpanFromContext(ctx, "service.user.UpdateUserRole")
 	defer span.Finish()
 
-	if err := s.repo.UpdateUserRole(ctx, id, role); err != nil {
+	if actor == nil {
+		return nil, domain.NewForbiddenError("you are not a member of this organization")
+	}
+
+	member, err := s.repo.GetOrganizationMember(ctx, id, organizationID)
+	if err != nil {
+		return nil, err
+	}
+	wasAdmin := member.Role == domain.UserRoleAdmin
+	wasActiveAdmin := member.IsActiveAdmin()
+	previousRole := roleLabel(member)
+
+	if customRoleID != nil {
+		if role == domain.UserRoleAdmin {
+			return nil, domain.NewInvalidArgumentError("custom roles can only be assigned to employees")
+		}
+		customRole, err := s.repo.GetCustomRole(ctx, *customRoleID)
+		if err != nil && !errors.Is(err, domain.ErrNotFound) {
+			return nil, err
+		}
+		if err != nil || customRole.OrganizationID != organizationID {
+			return nil, domain.NewNotFoundError("role not found")
+		}
+		member.AssignCustomRole(customRole)
+	} else {
+		member.UpdateRole(role)
+	}
+
+	if (wasAdmin || member.Role == domain.UserRoleAdmin) && actor.Role != domain.UserRoleAdmin {
+		return nil, domain.NewForbiddenError("only administrators can grant or revoke the admin role")
+	}
+	if !actor.CanGrant(member.Permissions()) {
+		return nil, domain.NewForbiddenError("you cannot grant permissions you do not have")
+	}
+
+	var updated domain.OrganizationMember
+	err = s.tx.Do(ctx, func(txCtx context.Context) error {
+		if wasActiveAdmin && member.Role != domain.UserRoleAdmin {
+			if err := s.checkAdminRemains(txCtx, organizationID, "the last administrator cannot be demoted"); err != nil {
+				return err
+			}
+		}
+		updated, err = s.repo.UpdateOrganizationMember(txCtx, *member)
+		return err
+	})
+	if err != nil {
 		return nil, err
 	}
 
+	s.audit.Record(ctx, domain.NewAuditEvent(domain.AuditActionMemberRoleChanged, &organizationID,
+		domain.AuditResourceMember, id.String(), map[string]any{"from": previousRole, "to": roleLabel(member)}))
+
 	user, err := s.repo.GetUser(ctx, id)
 	if err != nil {
 		return nil, err
 	}
 
-	return &user, nil
+	return &domain.UserWithMembership{User: user, OrganizationMember: updated}, nil
 }
 
-// DeactivateUser deactivates a user
-func (s *Service) DeactivateUser(ctx context.Context, id domain.ID) error {
+// DeactivateUser deactivates the user's membership in the organization. The user account itself
+// and memberships in other organizations stay active; members leave on their own via LeaveOrganization.
+func (s *Service) DeactivateUser(ctx context.Context, organizationID, id domain.ID, actor *domain.OrganizationMember) error {
 	span, ctx := opentracing.StartSpanFromContext(ctx, "service.user.DeactivateUser")
 	defer span.Finish()
 
-	return s.repo.DeactivateUser(ctx, id)
+	if actor == nil {
+		return domain.NewForbiddenError("you are not a member of this organization")
+	}
+	if actor.UserID == id {
+		return domain.NewInvalidArgumentError("use leave organization to deactivate your own membership")
+	}
+
+	member, err := s.repo.GetOrganizationMember(ctx, id, organizationID)
+	if err != nil {
+		return err
+	}
+	if !member.IsActive() {
+		return domain.NewInvalidArgumentError("membership is already inactive")
+	}
+	if member.Role == domain.UserRoleAdmin && actor.Role != domain.UserRoleAdmin {
+		return domain.NewForbiddenError("only administrators can deactivate administrators")
+	}
+
+	wasActiveAdmin := member.IsActiveAdmin()
+	member.Deactivate()
+
+	err = s.tx.Do(ctx, func(txCtx context.Context) error {
+		if wasActiveAdmin {
+			if err := s.checkAdminRemains(txCtx, organizationID, "the last administrator cannot be deactivated"); err != nil {
+				return err
+			}
+		}
+		_, err := s.repo.UpdateOrganizationMember(txCtx, *member)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	s.audit.Record(ctx, domain.NewAuditEvent(domain.AuditActionMemberDeactivated, &organizationID,
+		domain.AuditResourceMember, id.String(), nil))
+
+	return nil
+}
+
+// LeaveOrganization ends the user's own membership. The last administrator cannot leave:
+// ownership has to be transferred or the organization deleted first.
+func (s *Service) LeaveOrganization(ctx context.Context, organizationID, userID domain.ID) error {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "service.user.LeaveOrganization")
+	defer span.Finish()
+
+	member, err := s.repo.GetOrganizationMember(ctx, userID, organizationID)
+	if err != nil {
+		return err
+	}
+	if !member.IsActive() {
+		return domain.NewInvalidArgumentError("you are not an active member of this organization")
+	}
+
+	wasActiveAdmin := member.IsActiveAdmin()
+	member.Leave()
+
+	err = s.tx.Do(ctx, func(txCtx context.Context) error {
+		if wasActiveAdmin {
+			if err := s.checkAdminRemains(txCtx, organizationID, "the last administrator cannot leave; transfer ownership or delete the organization"); err != nil {
+				return err
+			}
+		}
+		_, err := s.repo.UpdateOrganizationMember(txCtx, *member)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	s.audit.Record(ctx, domain.NewAuditEvent(domain.AuditActionMemberLeft, &organizationID,
+		domain.AuditResourceMember, userID.String(), map[string]any{"role": roleLabel(member)}))
+
+	return nil
+}
+
+// TransferOwnership makes another active member an administrator and demotes the acting administrator
+// to an employee in one transaction, so the organization is never left without an administrator
+func (s *Service) TransferOwnership(ctx context.Context, organizationID domain.ID, actor *domain.OrganizationMember, newOwnerID domain.ID) (*domain.UserWithMembership, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "service.user.TransferOwnership")
+	defer span.Finish()
+
+	if actor == nil {
+		return nil, domain.NewForbiddenError("you are not a member of this organization")
+	}
+	if actor.UserID == newOwnerID {
+		return nil, domain.NewInvalidArgumentError("ownership cannot be transferred to yourself")
+	}
+
+	var newOwner domain.OrganizationMember
+	err := s.tx.Do(ctx, func(txCtx context.Context) error {
+		// Admin memberships stay locked until commit, so the checks below cannot be raced
+		if _, err := s.repo.LockOrganizationAdmins(txCtx, organizationID); err != nil {
+			return err
+		}
+
+		previousOwner, err := s.repo.GetOrganizationMember(txCtx, actor.UserID, organizationID)
+		if err != nil {
+			return err
+		}
+		if !previousOwner.IsActiveAdmin() {
+			return domain.NewForbiddenError("only administrators can transfer ownership")
+		}
+
+		target, err := s.repo.GetOrganizationMember(txCtx, newOwnerID, organizationID)
+		if err != nil {
+			if errors.Is(err, domain.ErrNotFound) {
+				return domain.NewNotFoundError("member not found")
+			}
+			return err
+		}
+		if !target.IsActive() {
+			return domain.NewInvalidArgumentError("ownership can only be transferred to an active member")
+		}
+
+		target.UpdateRole(domain.UserRoleAdmin)
+		if newOwner, err = s.repo.UpdateOrganizationMember(txCtx, *target); err != nil {
+			return err
+		}
+
+		previousOwner.UpdateRole(domain.UserRoleEmployee)
+		_, err = s.repo.UpdateOrganizationMember(txCtx, *previousOwner)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.audit.Record(ctx, domain.NewAuditEvent(domain.AuditActionOwnershipTransferred, &organizationID,
+		domain.AuditResourceMember, newOwnerID.String(), map[string]any{"from": actor.UserID.String(), "to": newOwnerID.String()}))
+
+	user, err := s.repo.GetUser(ctx, newOwnerID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &domain.UserWithMembership{User: user, OrganizationMember: newOwner}, nil
+}
+
+// checkAdminRemains must run inside a transaction before an active administrator stops being one:
+// it locks the organization's admins and refuses if that administrator is the last one
+func (s *Service) checkAdminRemains(ctx context.Context, organizationID domain.ID, message string) error {
+	admins, err := s.repo.LockOrganizationAdmins(ctx, organizationID)
+	if err != nil {
+		return err
+	}
+	if admins <= 1 {
+		return domain.NewInvalidArgumentError(message)
+	}
+	return nil
 }
 
 // ListInvitations retrieves invitations for an organization
@@ -228,7 +542,191 @@ func (s *Service) DeleteInvitation(ctx context.Context, invitationID domain.ID,
 	}
 
 	// Delete invitation
-	return s.repo.DeleteInvitation(ctx, invitationID)
+	if err := s.repo.DeleteInvitation(ctx, invitationID); err != nil {
+		return err
+	}
+
+	event := domain.NewAuditEvent(domain.AuditActionInvitationDeleted, &invitation.OrganizationID,
+		domain.AuditResourceInvitation, invitationID.String(), nil)
+	event.ActorUserID = &userID
+	s.audit.Record(ctx, event)
+
+	return nil
+}
+
+// RevokeInvitation stops an invitation link from working; who already joined stays in its history
+func (s *Service) RevokeInvitation(ctx context.Context, invitationID domain.ID) (*domain.Invitation, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "service.user.RevokeInvitation")
+	defer span.Finish()
+
+	invitation, err := s.repo.GetInvitationByID(ctx, invitationID)
+	if err != nil {
+		return nil, err
+	}
+	if invitation.IsRevoked() {
+		return nil, domain.NewInvalidArgumentError("invitation is already revoked")
+	}
+
+	if err := s.repo.RevokeInvitation(ctx, invitationID); err != nil {
+		return nil, err
+	}
+
+	s.audit.Record(ctx, domain.NewAuditEvent(domain.AuditActionInvitationRevoked, &invitation.OrganizationID,
+		domain.AuditResourceInvitation, invitationID.String(), invitationMetadata(invitation)))
+
+	revoked, err := s.repo.GetInvitationByID(ctx, invitationID)
+	if err != nil {
+		return nil, err
+	}
+	return &revoked, nil
+}
+
+// RegenerateInvitation issues a new link for an invitation with the same role and use limit.
+// The old link stops working, the use counter starts over and a revoked invitation becomes active again.
+// The regenerated link counts as newly created, so it also readmits members deactivated before it.
+func (s *Service) RegenerateInvitation(ctx context.Context, invitationID domain.ID, actor *domain.OrganizationMember, ttl time.Duration) (*domain.Invitation, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "service.user.RegenerateInvitation")
+	defer span.Finish()
+
+	if actor == nil {
+		return nil, domain.NewForbiddenError("you are not a member of this organization")
+	}
+
+	invitation, err := s.repo.GetInvitationByID(ctx, invitationID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Reopening a link is as good as creating it, so the same role limits apply
+	permissions, err := s.invitationPermissions(ctx, invitation)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkCanInvite(actor, invitation.Role, permissions); err != nil {
+		return nil, err
+	}
+
+	expiresAt, err := invitationExpiry(ttl)
+	if err != nil {
+		return nil, err
+	}
+	token, err := generateToken()
+	if err != nil {
+		return nil, domain.NewInternalError("failed to generate invitation token", err)
+	}
+
+	invitation.Regenerate(token, expiresAt)
+	regenerated, err := s.repo.RegenerateInvitation(ctx, invitation)
+	if err != nil {
+		return nil, err
+	}
+
+	s.audit.Record(ctx, domain.NewAuditEvent(domain.AuditActionInvitationRegenerated, &regenerated.OrganizationID,
+		domain.AuditResourceInvitation, invitationID.String(), invitationMetadata(regenerated)))
+
+	return &regenerated, nil
+}
+
+// ListInvitationUses returns who joined through an invitation, newest first
+func (s *Service) ListInvitationUses(ctx context.Context, invitationID domain.ID, page, pageSize int) ([]domain.InvitationUse, int, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "service.user.ListInvitationUses")
+	defer span.Finish()
+
+	if _, err := s.repo.GetInvitationByID(ctx, invitationID); err != nil {
+		return nil, 0, err
+	}
+
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 || pageSize > 100 {
+		pageSize = 20
+	}
+
+	offset := (page - 1) * pageSize
+
+	return s.repo.ListInvitationUses(ctx, invitationID, pageSize, offset)
+}
+
+// organizationRole loads a custom role and makes sure it belongs to the organization
+func (s *Service) organizationRole(ctx context.Context, organizationID, id domain.ID) (domain.CustomRole, error) {
+	customRole, err := s.repo.GetCustomRole(ctx, id)
+	if err != nil && !errors.Is(err, domain.ErrNotFound) {
+		return domain.CustomRole{}, err
+	}
+	if err != nil || customRole.OrganizationID != organizationID {
+		return domain.CustomRole{}, domain.NewNotFoundError("role not found")
+	}
+	return customRole, nil
+}
+
+// invitationPermissions returns the permissions users get by joining through the invitation
+func (s *Service) invitationPermissions(ctx context.Context, invitation domain.Invitation) ([]domain.Permission, error) {
+	if invitation.CustomRoleID == nil {
+		return domain.RolePermissions(invitation.Role), nil
+	}
+	customRole, err := s.organizationRole(ctx, invitation.OrganizationID, *invitation.CustomRoleID)
+	if err != nil {
+		return nil, err
+	}
+	return customRole.Permissions, nil
+}
+
+// checkCanInvite applies the role assignment rules to invitations: only admins may invite admins,
+// other actors may only hand out permissions they hold
+func checkCanInvite(actor *domain.OrganizationMember, role domain.UserRole, permissions []domain.Permission) error {
+	if role == domain.UserRoleAdmin && actor.Role != domain.UserRoleAdmin {
+		return domain.NewForbiddenError("only administrators can invite administrators")
+	}
+	if !actor.CanGrant(permissions) {
+		return domain.NewForbiddenError("you cannot invite with permissions you do not have")
+	}
+	return nil
+}
+
+// checkInvitationUsable explains why an invitation can no longer be accepted
+func checkInvitationUsable(invitation domain.Invitation) error {
+	switch invitation.Status() {
+	case domain.InvitationStatusRevoked:
+		return domain.NewInvalidArgumentError("invitation has been revoked")
+	case domain.InvitationStatusExhausted:
+		return domain.NewInvalidArgumentError("invitation has already been used")
+	case domain.InvitationStatusExpired:
+		return domain.NewInvalidArgumentError("invitation has expired")
+	default:
+		return nil
+	}
+}
+
+// invitationExpiry turns the requested lifetime into an expiry time; zero means the default 7 days
+func invitationExpiry(ttl time.Duration) (time.Time, error) {
+	if ttl == 0 {
+		ttl = domain.DefaultInvitationTTL
+	}
+	if ttl < 0 || ttl > domain.MaxInvitationTTL {
+		return time.Time{}, domain.NewInvalidArgumentError("invitation lifetime must be between 1 hour and 30 days")
+	}
+	return time.Now().Add(ttl), nil
+}
+
+// invitationMetadata describes the invitation for the audit log
+func invitationMetadata(invitation domain.Invitation) map[string]any {
+	metadata := map[string]any{
+		"role":     string(invitation.Role),
+		"max_uses": invitation.MaxUses,
+	}
+	if invitation.CustomRoleID != nil {
+		metadata["custom_role"] = invitation.CustomRoleName
+	}
+	return metadata
+}
+
+// roleLabel describes the member's role for the audit log: the custom role name or the built-in role
+func roleLabel(member *domain.OrganizationMember) string {
+	if member.CustomRoleID != nil {
+		return member.CustomRoleName
+	}
+	return string(member.Role)
 }
 
 // generateToken generates a secure random token
@@ -3,6 +3,7 @@ package storage
 import (
 	"context"
 	"fmt"
+	"io"
 	"time"
 
 	"github.com/aws/aws-sdk-go/aws"
@@ -71,3 +72,100 @@ func (c *S3Client) GeneratePresignedDownloadURL(ctx context.Context, key string,
 
 	return url, nil
 }
+
+// GetObject открывает объект из S3 на чтение; вызывающий закрывает поток
+func (c *S3Client) GetObject(ctx context.Context, key string) (io.ReadCloser, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "storage.S3Client.GetObject")
+	defer span.Finish()
+
+	out, err := c.client.GetObjectWithContext(ctx, &s3.GetObjectInput{
+		Bucket: aws.String(c.bucket),
+		Key:    aws.String(key),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get object %s: %w", key, err)
+	}
+
+	return out.Body, nil
+}
+
+// PutObject загружает объект в S3
+func (c *S3Client) PutObject(ctx context.Context, key string, body io.ReadSeeker, contentType string) error {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "storage.S3Client.PutObject")
+	defer span.Finish()
+
+	_, err := c.client.PutObjectWithContext(ctx, &s3.PutObjectInput{
+		Bucket:      aws.String(c.bucket),
+		Key:         aws.String(key),
+		Body:        body,
+		ContentType: aws.String(contentType),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to put object %s: %w", key, err)
+	}
+
+	return nil
+}
+
+// DeleteObject удаляет объект из S3
+func (c *S3Client) DeleteObject(ctx context.Context, key string) error {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "storage.S3Client.DeleteObject")
+	defer span.Finish()
+
+	_, err := c.client.DeleteObjectWithContext(ctx, &s3.DeleteObjectInput{
+		Bucket: aws.String(c.bucket),
+		Key:    aws.String(key),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to delete object %s: %w", key, err)
+	}
+
+	return nil
+}
+
+// DeletePrefix удаляет все объекты с префиксом и возвращает их количество
+func (c *S3Client) DeletePrefix(ctx context.Context, prefix string) (int, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "storage.S3Client.DeletePrefix")
+	defer span.Finish()
+
+	deleted := 0
+	var pageErr error
+	err := c.client.ListObjectsV2PagesWithContext(ctx, &s3.ListObjectsV2Input{
+		Bucket: aws.String(c.bucket),
+		Prefix: aws.String(prefix),
+	}, func(page *s3.ListObjectsV2Output, _ bool) bool {
+		if len(page.Contents) == 0 {
+			return true
+		}
+
+		objects := make([]*s3.ObjectIdentifier, 0, len(page.Contents))
+		for _, obj := range page.Contents {
+			objects = append(objects, &s3.ObjectIdentifier{Key: obj.Key})
+		}
+
+		// Страница листинга не больше 1000 объектов - столько же принимает DeleteObjects
+		out, err := c.client.DeleteObjectsWithContext(ctx, &s3.DeleteObjectsInput{
+			Bucket: aws.String(c.bucket),
+			Delete: &s3.Delete{Objects: objects, Quiet: aws.Bool(true)},
+		})
+		if err != nil {
+			pageErr = err
+			return false
+		}
+		if len(out.Errors) > 0 {
+			pageErr = fmt.Errorf("failed to delete %s: %s", aws.StringValue(out.Errors[0].Key), aws.StringValue(out.Errors[0].Message))
+			return false
+		}
+
+		deleted += len(objects)
+		return true
+	})
+	if err != nil {
+		return deleted, fmt.Errorf("failed to list objects: %w", err)
+	}
+	if pageErr != nil {
+		return deleted, fmt.Errorf("failed to delete objects: %w", pageErr)
+	}
+
+	return deleted, nil
+}
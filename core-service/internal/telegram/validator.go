@@ -2,6 +2,7 @@ package telegram
 
 import (
 	"context"
+	"crypto/subtle"
 	"time"
 
 	"core-service/internal/domain"
@@ -87,3 +88,16 @@ parseinitdata:
 
 	return result, nil
 }
+
+// VerifyBotToken reports whether token belongs to one of the configured bots.
+func (v *Validator) VerifyBotToken(token string) bool {
+	if token == "" {
+		return false
+	}
+	for _, botToken := range v.botTokens {
+		if subtle.ConstantTimeCompare([]byte(token), []byte(botToken)) == 1 {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,31 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- Собственные роли организации с произвольным набором прав
+CREATE TABLE IF NOT EXISTS organization_roles (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    organization_id UUID NOT NULL REFERENCES organizations(id) ON DELETE CASCADE,
+    name VARCHAR(100) NOT NULL,
+    description TEXT NOT NULL DEFAULT '',
+    permissions TEXT[] NOT NULL DEFAULT '{}',
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    UNIQUE(organization_id, name)
+);
+
+-- Участник с собственной ролью получает ее права вместо прав встроенной роли;
+-- роль нельзя удалить, пока она назначена
+ALTER TABLE organization_members
+    ADD COLUMN custom_role_id UUID REFERENCES organization_roles(id) ON DELETE RESTRICT;
+
+CREATE INDEX idx_organization_members_custom_role_id ON organization_members(custom_role_id)
+WHERE custom_role_id IS NOT NULL;
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_organization_members_custom_role_id;
+ALTER TABLE organization_members DROP COLUMN IF EXISTS custom_role_id;
+DROP TABLE IF EXISTS organization_roles;
+-- +goose StatementEnd
@@ -0,0 +1,40 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- Многоразовые приглашения: лимит использований, заранее назначенная собственная роль,
+-- автор и явный отзыв. По умолчанию приглашение остается одноразовым.
+-- used_at теперь хранит время последнего использования
+ALTER TABLE invitations
+    ADD COLUMN max_uses INT NOT NULL DEFAULT 1 CHECK (max_uses > 0),
+    ADD COLUMN uses_count INT NOT NULL DEFAULT 0,
+    ADD COLUMN custom_role_id UUID REFERENCES organization_roles(id) ON DELETE SET NULL,
+    ADD COLUMN created_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    ADD COLUMN revoked_at TIMESTAMP;
+
+UPDATE invitations SET uses_count = 1 WHERE used_at IS NOT NULL;
+
+DROP INDEX IF EXISTS idx_invitations_used_at;
+
+-- История использований: кто и когда вступил по приглашению
+CREATE TABLE IF NOT EXISTS invitation_uses (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    invitation_id UUID NOT NULL REFERENCES invitations(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    used_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_invitation_uses_invitation_id ON invitation_uses(invitation_id, used_at DESC);
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS invitation_uses;
+CREATE INDEX idx_invitations_used_at ON invitations(used_at) WHERE used_at IS NULL;
+ALTER TABLE invitations
+    DROP COLUMN IF EXISTS revoked_at,
+    DROP COLUMN IF EXISTS created_by,
+    DROP COLUMN IF EXISTS custom_role_id,
+    DROP COLUMN IF EXISTS uses_count,
+    DROP COLUMN IF EXISTS max_uses;
+-- +goose StatementEnd
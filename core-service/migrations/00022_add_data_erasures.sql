@@ -0,0 +1,33 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- Заявки на безвозвратное удаление организаций и учетных записей и отчеты об их выполнении.
+-- Ссылок на удаляемые данные нет, чтобы отчет пережил удаление
+CREATE TABLE IF NOT EXISTS data_erasures (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    subject_type VARCHAR(20) NOT NULL CHECK (subject_type IN ('organization', 'user')),
+    subject_id UUID NOT NULL,
+    subject_name VARCHAR(255) NOT NULL DEFAULT '',
+    requested_by UUID,
+    status VARCHAR(20) NOT NULL DEFAULT 'scheduled',
+    purge_after TIMESTAMP NOT NULL,
+    steps JSONB NOT NULL DEFAULT '{}',
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    started_at TIMESTAMP,
+    completed_at TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+-- Одновременно у организации или пользователя может быть только одна незавершенная заявка
+CREATE UNIQUE INDEX idx_data_erasures_active_subject ON data_erasures(subject_type, subject_id)
+WHERE status IN ('scheduled', 'in_progress', 'failed');
+
+CREATE INDEX idx_data_erasures_status_purge_after ON data_erasures(status, purge_after);
+CREATE INDEX idx_data_erasures_requested_by ON data_erasures(requested_by, created_at DESC);
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS data_erasures;
+-- +goose StatementEnd
@@ -0,0 +1,34 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- Выгрузки данных организаций в ZIP-архивы
+CREATE TABLE IF NOT EXISTS organization_exports (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    organization_id UUID NOT NULL REFERENCES organizations(id) ON DELETE CASCADE,
+    requested_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending',
+    stage VARCHAR(20) NOT NULL DEFAULT 'assistant_data',
+    processed_items INTEGER NOT NULL DEFAULT 0,
+    total_items INTEGER NOT NULL DEFAULT 0,
+    s3_key VARCHAR(500),
+    file_size BIGINT NOT NULL DEFAULT 0,
+    error_message TEXT,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    completed_at TIMESTAMP,
+    expires_at TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+-- Одновременно у организации может выполняться только одна выгрузка
+CREATE UNIQUE INDEX idx_organization_exports_active ON organization_exports(organization_id)
+WHERE status IN ('pending', 'in_progress');
+
+CREATE INDEX idx_organization_exports_organization ON organization_exports(organization_id, created_at DESC);
+CREATE INDEX idx_organization_exports_status_updated ON organization_exports(status, updated_at);
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS organization_exports;
+-- +goose StatementEnd
@@ -7,10 +7,13 @@ import (
 	"os"
 
 	"docs-processor/internal/app"
+	"docs-processor/internal/chunker"
 	"docs-processor/internal/config"
 	"docs-processor/internal/embeddings"
 	"docs-processor/internal/logger"
+	"docs-processor/internal/parser"
 	"docs-processor/internal/service"
+	"docs-processor/internal/storage"
 	"docs-processor/internal/tracer"
 	"docs-processor/internal/vectordb"
 
@@ -54,28 +57,61 @@ func main() {
 		logger.Fatal(ctx, "Failed to create OpenSearch client", "error", err)
 	}
 
+	embeddingsCli, previousEmbeddings, err := embeddings.NewProvidersFromConfig(cfg)
+	if err != nil {
+		logger.Fatal(ctx, "Failed to create embeddings providers", "error", err)
+	}
+
+	indexRouter, err := service.NewIndexRouter(vectorDB, cfg.GetEmbeddingsLegacyModel(), embeddingsCli, previousEmbeddings...)
+	if err != nil {
+		logger.Fatal(ctx, "Failed to create index router", "error", err)
+	}
+	if err := indexRouter.EnsureActiveIndex(ctx); err != nil {
+		logger.Fatal(ctx, "Failed to ensure OpenSearch index", "error", err)
+	}
+
 	templatesDB, err := vectordb.NewTemplatesClient(
 		cfg.GetOpenSearchAddresses(),
 		cfg.GetOpenSearchUsername(),
 		cfg.GetOpenSearchPassword(),
-		cfg.GetOpenSearchTemplatesIndex(),
+		vectordb.IndexName(cfg.GetOpenSearchTemplatesIndex(), embeddingsCli.Model(), embeddingsCli.Dimension()),
+		embeddingsCli.Dimension(),
 	)
 	if err != nil {
 		logger.Fatal(ctx, "Failed to create templates OpenSearch client", "error", err)
 	}
 
-	embeddingsCli := embeddings.NewClient(
-		cfg.GetEmbeddingsBaseURL(),
-		cfg.GetEmbeddingsAPIKey(),
-		cfg.GetEmbeddingsModel(),
+	// Шаблоны, проиндексированные до появления индексов по моделям, ищутся в старом индексе до миграции
+	legacyTemplates := &service.EmbeddingIndex{Name: cfg.GetOpenSearchTemplatesIndex(), Provider: indexRouter.Legacy().Provider}
+
+	s3Client, err := storage.NewS3Client(
+		cfg.GetS3Endpoint(),
+		cfg.GetS3AccessKey(),
+		cfg.GetS3SecretKey(),
+		cfg.GetS3Region(),
+		cfg.GetS3Bucket(),
+		cfg.GetS3UseSSL(),
 	)
+	if err != nil {
+		logger.Fatal(ctx, "Failed to create S3 client", "error", err)
+	}
 
-	searchService := service.NewSearchService(embeddingsCli, vectorDB)
-	templateProcessor := service.NewTemplateProcessor(embeddingsCli, templatesDB)
+	searchService := service.NewSearchService(indexRouter, vectorDB)
+	templateProcessor := service.NewTemplateProcessor(embeddingsCli, templatesDB, legacyTemplates)
+	chatAttachmentService := service.NewChatAttachmentService(
+		s3Client,
+		parser.NewRegistry(),
+		chunker.New(cfg.GetChunkingMaxChunkSize(), cfg.GetChunkingOverlapSize()),
+		indexRouter,
+		vectorDB,
+		cfg.GetEmbeddingsBatchSize(),
+		cfg.GetChatAttachmentsInlineMaxChars(),
+	)
 
 	application := app.New(
 		searchService,
 		templateProcessor,
+		chatAttachmentService,
 		app.WithGrpcPort(cfg.GetGRPCPort()),
 		app.WithGatewayPort(cfg.GetHTTPPort()),
 		app.WithEnableGateway(cfg.GetEnableGateway()),
@@ -0,0 +1,145 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"io"
+	"log"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"docs-processor/internal/config"
+	"docs-processor/internal/domain"
+	"docs-processor/internal/embeddings"
+	"docs-processor/internal/logger"
+	"docs-processor/internal/service"
+	"docs-processor/internal/vectordb"
+
+	"github.com/joho/godotenv"
+)
+
+func init() {
+	logger.Init()
+	godotenv.Load()
+	log.SetOutput(io.Discard)
+}
+
+// Перевекторизует организацию активной моделью из конфига и переключает ее алиас:
+//
+//	migrate-embeddings -org <id>                  # из текущего индекса организации
+//	migrate-embeddings -all -from documents       # все организации из старого индекса
+//	migrate-embeddings -templates-from templates  # шаблоны договоров
+func main() {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	orgFlag := flag.String("org", "", "organization ID to migrate")
+	allFlag := flag.Bool("all", false, "migrate every organization found in -from index")
+	fromFlag := flag.String("from", "", "source index (defaults to the organization's current index)")
+	deleteSource := flag.Bool("delete-source", false, "delete organization chunks from the source index after the alias swap")
+	templatesFrom := flag.String("templates-from", "", "re-embed contract templates from this index")
+	flag.Parse()
+
+	configPath := os.Getenv("CONFIG_PATH")
+	if configPath == "" {
+		configPath = "config.yaml"
+	}
+
+	if err := config.Initialize(configPath); err != nil {
+		logger.Fatal(ctx, "Failed to initialize config", "error", err)
+	}
+
+	cfg := config.Get()
+
+	sigChan := make(chan os.Signal, 1)
+	signal.Notify(sigChan, os.Interrupt, syscall.SIGTERM)
+	go func() {
+		<-sigChan
+		cancel()
+	}()
+
+	vectorDB, err := vectordb.NewOpenSearchClient(
+		cfg.GetOpenSearchAddresses(),
+		cfg.GetOpenSearchUsername(),
+		cfg.GetOpenSearchPassword(),
+		cfg.GetOpenSearchIndexName(),
+	)
+	if err != nil {
+		logger.Fatal(ctx, "Failed to create OpenSearch client", "error", err)
+	}
+
+	embeddingsCli, previousEmbeddings, err := embeddings.NewProvidersFromConfig(cfg)
+	if err != nil {
+		logger.Fatal(ctx, "Failed to create embeddings providers", "error", err)
+	}
+
+	indexRouter, err := service.NewIndexRouter(vectorDB, cfg.GetEmbeddingsLegacyModel(), embeddingsCli, previousEmbeddings...)
+	if err != nil {
+		logger.Fatal(ctx, "Failed to create index router", "error", err)
+	}
+	migrator := service.NewEmbeddingMigrator(indexRouter, vectorDB, cfg.GetEmbeddingsBatchSize())
+
+	if *templatesFrom != "" {
+		templatesDB, err := vectordb.NewTemplatesClient(
+			cfg.GetOpenSearchAddresses(),
+			cfg.GetOpenSearchUsername(),
+			cfg.GetOpenSearchPassword(),
+			vectordb.IndexName(cfg.GetOpenSearchTemplatesIndex(), embeddingsCli.Model(), embeddingsCli.Dimension()),
+			embeddingsCli.Dimension(),
+		)
+		if err != nil {
+			logger.Fatal(ctx, "Failed to create templates OpenSearch client", "error", err)
+		}
+
+		templateProcessor := service.NewTemplateProcessor(embeddingsCli, templatesDB, nil)
+		if _, err := templateProcessor.MigrateTemplates(ctx, *templatesFrom, cfg.GetEmbeddingsBatchSize()); err != nil {
+			logger.Fatal(ctx, "Failed to migrate templates", "error", err)
+		}
+	}
+
+	var organizations []domain.ID
+	switch {
+	case *allFlag:
+		if *fromFlag == "" {
+			logger.Fatal(ctx, "-all requires -from")
+		}
+		organizations, err = migrator.ListOrganizations(ctx, *fromFlag)
+		if err != nil {
+			logger.Fatal(ctx, "Failed to list organizations", "error", err)
+		}
+	case *orgFlag != "":
+		organizationID, err := domain.ParseID(*orgFlag)
+		if err != nil {
+			logger.Fatal(ctx, "Invalid organization ID", "error", err)
+		}
+		organizations = []domain.ID{organizationID}
+	case *templatesFrom == "":
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	failed := 0
+	for _, organizationID := range organizations {
+		result, err := migrator.MigrateOrganization(ctx, organizationID, *fromFlag, *deleteSource)
+		if err != nil {
+			failed++
+			logger.Error(ctx, "Failed to migrate organization", "organization_id", organizationID, "error", err)
+			continue
+		}
+
+		logger.Info(ctx, "Organization migration finished",
+			"organization_id", organizationID,
+			"from_index", result.FromIndex,
+			"to_index", result.ToIndex,
+			"chunks_migrated", result.ChunksMigrated,
+			"skipped", result.Skipped,
+		)
+	}
+
+	if failed > 0 {
+		logger.Fatal(ctx, "Migration finished with errors", "failed", failed, "total", len(organizations))
+	}
+
+	logger.Info(ctx, "Migration finished", "total", len(organizations))
+}
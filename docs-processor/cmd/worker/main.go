@@ -73,22 +73,30 @@ func main() {
 		logger.Fatal(ctx, "Failed to create OpenSearch client", "error", err)
 	}
 
+	embeddingsCli, previousEmbeddings, err := embeddings.NewProvidersFromConfig(cfg)
+	if err != nil {
+		logger.Fatal(ctx, "Failed to create embeddings providers", "error", err)
+	}
+
+	indexRouter, err := service.NewIndexRouter(vectorDB, cfg.GetEmbeddingsLegacyModel(), embeddingsCli, previousEmbeddings...)
+	if err != nil {
+		logger.Fatal(ctx, "Failed to create index router", "error", err)
+	}
+	if err := indexRouter.EnsureActiveIndex(ctx); err != nil {
+		logger.Fatal(ctx, "Failed to ensure OpenSearch index", "error", err)
+	}
+
 	templatesDB, err := vectordb.NewTemplatesClient(
 		cfg.GetOpenSearchAddresses(),
 		cfg.GetOpenSearchUsername(),
 		cfg.GetOpenSearchPassword(),
-		cfg.GetOpenSearchTemplatesIndex(),
+		vectordb.IndexName(cfg.GetOpenSearchTemplatesIndex(), embeddingsCli.Model(), embeddingsCli.Dimension()),
+		embeddingsCli.Dimension(),
 	)
 	if err != nil {
 		logger.Fatal(ctx, "Failed to create templates OpenSearch client", "error", err)
 	}
 
-	embeddingsCli := embeddings.NewClient(
-		cfg.GetEmbeddingsBaseURL(),
-		cfg.GetEmbeddingsAPIKey(),
-		cfg.GetEmbeddingsModel(),
-	)
-
 	parserRegistry := parser.NewRegistry()
 	textChunker := chunker.New(
 		cfg.GetChunkingMaxChunkSize(),
@@ -105,7 +113,7 @@ func main() {
 		s3Client,
 		parserRegistry,
 		textChunker,
-		embeddingsCli,
+		indexRouter,
 		vectorDB,
 		coreClient,
 		cfg.GetEmbeddingsBatchSize(),
@@ -114,12 +122,7 @@ func main() {
 	templateProcessor := service.NewTemplateProcessor(
 		embeddingsCli,
 		templatesDB,
-	)
-
-	jobProcessor := service.NewJobProcessor(
-		documentProcessor,
-		templateProcessor,
-		coreClient,
+		nil,
 	)
 
 	rabbitMQ, err := queue.NewRabbitMQClient(
@@ -131,6 +134,34 @@ func main() {
 	}
 	defer rabbitMQ.Close()
 
+	notificationQueue, err := queue.NewRabbitMQClient(
+		cfg.GetRabbitMQURL(),
+		cfg.GetNotificationsQueueName(),
+	)
+	if err != nil {
+		logger.Fatal(ctx, "Failed to create notifications queue client", "error", err)
+	}
+	defer notificationQueue.Close()
+
+	erasureResults, err := queue.NewRabbitMQClient(
+		cfg.GetRabbitMQURL(),
+		cfg.GetErasureResultsQueueName(),
+	)
+	if err != nil {
+		logger.Fatal(ctx, "Failed to create erasure results queue client", "error", err)
+	}
+	defer erasureResults.Close()
+
+	organizationPurger := service.NewOrganizationPurger(vectorDB, erasureResults)
+
+	jobProcessor := service.NewJobProcessor(
+		documentProcessor,
+		templateProcessor,
+		organizationPurger,
+		coreClient,
+		notificationQueue,
+	)
+
 	sigChan := make(chan os.Signal, 1)
 	signal.Notify(sigChan, os.Interrupt, syscall.SIGTERM)
 
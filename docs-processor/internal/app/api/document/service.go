@@ -13,14 +13,20 @@ import (
 
 type Service struct {
 	desc.UnimplementedDocumentServiceServer
-	searchService     *service.SearchService
-	templateProcessor *service.TemplateProcessor
+	searchService         *service.SearchService
+	templateProcessor     *service.TemplateProcessor
+	chatAttachmentService *service.ChatAttachmentService
 }
 
-func NewService(searchService *service.SearchService, templateProcessor *service.TemplateProcessor) *Service {
+func NewService(
+	searchService *service.SearchService,
+	templateProcessor *service.TemplateProcessor,
+	chatAttachmentService *service.ChatAttachmentService,
+) *Service {
 	return &Service{
-		searchService:     searchService,
-		templateProcessor: templateProcessor,
+		searchService:         searchService,
+		templateProcessor:     templateProcessor,
+		chatAttachmentService: chatAttachmentService,
 	}
 }
 
@@ -42,7 +48,16 @@ func (s *Service) SearchChunks(ctx context.Context, req *desc.SearchChunksReques
 		minScore = 0.5
 	}
 
-	results, err := s.searchService.SearchChunks(ctx, organizationID, req.Query, limit, minScore)
+	var chatID *domain.ID
+	if req.ChatId != nil {
+		id, err := domain.ParseID(req.GetChatId())
+		if err != nil {
+			return nil, fmt.Errorf("invalid chat_id: %w", err)
+		}
+		chatID = &id
+	}
+
+	results, err := s.searchService.SearchChunks(ctx, organizationID, chatID, req.Query, limit, minScore)
 	if err != nil {
 		return nil, err
 	}
@@ -95,3 +110,69 @@ func (s *Service) SearchTemplates(ctx context.Context, req *desc.SearchTemplates
 		Templates: templates,
 	}, nil
 }
+
+func (s *Service) IndexChatAttachment(ctx context.Context, req *desc.IndexChatAttachmentRequest) (*desc.IndexChatAttachmentResponse, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "api.document.Service.IndexChatAttachment")
+	defer span.Finish()
+
+	organizationID, err := domain.ParseID(req.GetOrganizationId())
+	if err != nil {
+		return nil, fmt.Errorf("invalid organization_id: %w", err)
+	}
+	chatID, err := domain.ParseID(req.GetChatId())
+	if err != nil {
+		return nil, fmt.Errorf("invalid chat_id: %w", err)
+	}
+	attachmentID, err := domain.ParseID(req.GetAttachmentId())
+	if err != nil {
+		return nil, fmt.Errorf("invalid attachment_id: %w", err)
+	}
+
+	docType := domain.DocumentType(req.GetDocumentType())
+	switch docType {
+	case domain.DocumentTypePDF, domain.DocumentTypeDOCX, domain.DocumentTypeTXT:
+	default:
+		return nil, fmt.Errorf("unsupported document_type: %s", req.GetDocumentType())
+	}
+
+	result, err := s.chatAttachmentService.IndexAttachment(ctx, organizationID, chatID, attachmentID, req.GetS3Key(), req.GetFileName(), docType)
+	if err != nil {
+		return nil, err
+	}
+
+	return &desc.IndexChatAttachmentResponse{
+		Inline:     result.Inline,
+		Text:       result.Text,
+		ChunkCount: int32(result.ChunkCount),
+		TextLength: int32(result.TextLength),
+	}, nil
+}
+
+func (s *Service) DeleteChatAttachments(ctx context.Context, req *desc.DeleteChatAttachmentsRequest) (*desc.DeleteChatAttachmentsResponse, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "api.document.Service.DeleteChatAttachments")
+	defer span.Finish()
+
+	organizationID, err := domain.ParseID(req.GetOrganizationId())
+	if err != nil {
+		return nil, fmt.Errorf("invalid organization_id: %w", err)
+	}
+	chatID, err := domain.ParseID(req.GetChatId())
+	if err != nil {
+		return nil, fmt.Errorf("invalid chat_id: %w", err)
+	}
+
+	var attachmentID *domain.ID
+	if req.AttachmentId != nil {
+		id, err := domain.ParseID(req.GetAttachmentId())
+		if err != nil {
+			return nil, fmt.Errorf("invalid attachment_id: %w", err)
+		}
+		attachmentID = &id
+	}
+
+	if err := s.chatAttachmentService.DeleteAttachments(ctx, organizationID, chatID, attachmentID); err != nil {
+		return nil, err
+	}
+
+	return &desc.DeleteChatAttachmentsResponse{}, nil
+}
@@ -91,6 +91,7 @@ type App struct {
 func New(
 	searchService *service.SearchService,
 	templateProcessor *service.TemplateProcessor,
+	chatAttachmentService *service.ChatAttachmentService,
 	options ...OptionsFunc,
 ) *App {
 	opts := defaultOptions
@@ -98,7 +99,7 @@ func New(
 		o(opts)
 	}
 	return &App{
-		documentService: document.NewService(searchService, templateProcessor),
+		documentService: document.NewService(searchService, templateProcessor, chatAttachmentService),
 		options:         opts,
 	}
 }
@@ -28,6 +28,14 @@ type RabbitMQ struct {
 	PrefetchCount int    `mapstructure:"prefetch_count"`
 }
 
+type Notifications struct {
+	QueueName string `mapstructure:"queue_name"`
+}
+
+type Erasure struct {
+	ResultsQueueName string `mapstructure:"results_queue_name"`
+}
+
 type S3 struct {
 	Endpoint  string `mapstructure:"endpoint"`
 	AccessKey string `mapstructure:"access_key"`
@@ -45,11 +53,31 @@ type OpenSearch struct {
 	TemplatesIndex string   `mapstructure:"templates_index"`
 }
 
-type Embeddings struct {
+type EmbeddingsCache struct {
+	Enabled    bool `mapstructure:"enabled"`
+	MaxEntries int  `mapstructure:"max_entries"`
+	// Файл персистентного кэша; пустой путь - только кэш в памяти
+	Path                 string `mapstructure:"path"`
+	PersistentMaxEntries int    `mapstructure:"persistent_max_entries"`
+}
+
+type EmbeddingModel struct {
+	Provider  string `mapstructure:"provider"`
 	BaseURL   string `mapstructure:"base_url"`
 	APIKey    string `mapstructure:"api_key"`
 	Model     string `mapstructure:"model"`
-	BatchSize int    `mapstructure:"batch_size"`
+	Dimension int    `mapstructure:"dimension"`
+}
+
+type Embeddings struct {
+	EmbeddingModel `mapstructure:",squash"`
+	BatchSize      int             `mapstructure:"batch_size"`
+	Cache          EmbeddingsCache `mapstructure:"cache"`
+	// Модели, чьи индексы еще обслуживают не мигрированные организации
+	PreviousModels []EmbeddingModel `mapstructure:"previous_models"`
+	// Модель (активная или одна из previous_models), которой построены индексы без суффикса
+	// модели - index_name и templates_index; по умолчанию активная
+	LegacyModel string `mapstructure:"legacy_model"`
 }
 
 type Chunking struct {
@@ -57,6 +85,12 @@ type Chunking struct {
 	OverlapSize  int `mapstructure:"overlap_size"`
 }
 
+// ChatAttachments - файлы, приложенные к чату
+type ChatAttachments struct {
+	// Текст не длиннее этого лимита (в символах) возвращается целиком без индексации
+	InlineMaxChars int `mapstructure:"inline_max_chars"`
+}
+
 type Jaeger struct {
 	Endpoint string `mapstructure:"endpoint"`
 }
@@ -78,6 +112,11 @@ type Config struct {
 	Chunking    Chunking    `mapstructure:"chunking"`
 	Jaeger      Jaeger      `mapstructure:"jaeger"`
 	CoreService CoreService `mapstructure:"core_service"`
+
+	Notifications Notifications `mapstructure:"notifications"`
+	Erasure       Erasure       `mapstructure:"erasure"`
+
+	ChatAttachments ChatAttachments `mapstructure:"chat_attachments"`
 }
 
 var (
@@ -104,13 +143,22 @@ func (c *Config) loadDefaults() {
 	c.RabbitMQ.QueueName = "document_processing"
 	c.RabbitMQ.ConsumerTag = "doc-processor-worker"
 	c.RabbitMQ.PrefetchCount = 1
+	c.Notifications.QueueName = "notifications"
+	c.Erasure.ResultsQueueName = "erasure_results"
 	c.S3.Region = "us-east-1"
 	c.S3.Bucket = "documents"
 	c.OpenSearch.IndexName = "documents"
+	c.OpenSearch.TemplatesIndex = "templates"
+	c.Embeddings.Provider = "openai"
 	c.Embeddings.Model = "text-embedding-3-small"
+	c.Embeddings.Dimension = 1024
 	c.Embeddings.BatchSize = 100
+	c.Embeddings.Cache.Enabled = true
+	c.Embeddings.Cache.MaxEntries = 50000
+	c.Embeddings.Cache.PersistentMaxEntries = 500000
 	c.Chunking.MaxChunkSize = 1000
 	c.Chunking.OverlapSize = 200
+	c.ChatAttachments.InlineMaxChars = 20000
 	c.CoreService.Address = "localhost:50051"
 }
 
@@ -200,6 +248,20 @@ func (c *Config) GetRabbitMQPrefetchCount() int {
 	return c.RabbitMQ.PrefetchCount
 }
 
+// GetNotificationsQueueName возвращает очередь событий для сервиса уведомлений
+func (c *Config) GetNotificationsQueueName() string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.Notifications.QueueName
+}
+
+// GetErasureResultsQueueName возвращает очередь, в которую core-service ждет результаты удаления данных
+func (c *Config) GetErasureResultsQueueName() string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.Erasure.ResultsQueueName
+}
+
 func (c *Config) GetS3Endpoint() string {
 	c.mu.RLock()
 	defer c.mu.RUnlock()
@@ -284,12 +346,65 @@ func (c *Config) GetEmbeddingsModel() string {
 	return c.Embeddings.Model
 }
 
+func (c *Config) GetEmbeddingsProvider() string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.Embeddings.Provider
+}
+
+func (c *Config) GetEmbeddingsDimension() int {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.Embeddings.Dimension
+}
+
+func (c *Config) GetEmbeddingsPreviousModels() []EmbeddingModel {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	models := make([]EmbeddingModel, len(c.Embeddings.PreviousModels))
+	copy(models, c.Embeddings.PreviousModels)
+	return models
+}
+
+func (c *Config) GetEmbeddingsLegacyModel() string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	if c.Embeddings.LegacyModel == "" {
+		return c.Embeddings.Model
+	}
+	return c.Embeddings.LegacyModel
+}
+
 func (c *Config) GetEmbeddingsBatchSize() int {
 	c.mu.RLock()
 	defer c.mu.RUnlock()
 	return c.Embeddings.BatchSize
 }
 
+func (c *Config) GetEmbeddingsCacheEnabled() bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.Embeddings.Cache.Enabled
+}
+
+func (c *Config) GetEmbeddingsCacheMaxEntries() int {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.Embeddings.Cache.MaxEntries
+}
+
+func (c *Config) GetEmbeddingsCachePath() string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.Embeddings.Cache.Path
+}
+
+func (c *Config) GetEmbeddingsCachePersistentMaxEntries() int {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.Embeddings.Cache.PersistentMaxEntries
+}
+
 func (c *Config) GetChunkingMaxChunkSize() int {
 	c.mu.RLock()
 	defer c.mu.RUnlock()
@@ -302,6 +417,12 @@ func (c *Config) GetChunkingOverlapSize() int {
 	return c.Chunking.OverlapSize
 }
 
+func (c *Config) GetChatAttachmentsInlineMaxChars() int {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.ChatAttachments.InlineMaxChars
+}
+
 func (c *Config) GetJaegerEndpoint() string {
 	c.mu.RLock()
 	defer c.mu.RUnlock()
@@ -7,6 +7,9 @@ type Chunk struct {
 	Position   int
 	Embedding  []float32
 	Metadata   map[string]string
+	// ChatID задан у чанков вложений чата: они видны только в этом чате
+	// и не попадают в общий поиск по документам организации
+	ChatID *ID
 }
 
 func NewChunk(documentID ID, content string, position int) *Chunk {
@@ -24,6 +27,11 @@ func (c *Chunk) WithMetadata(key, value string) *Chunk {
 	return c
 }
 
+func (c *Chunk) WithChat(chatID ID) *Chunk {
+	c.ChatID = &chatID
+	return c
+}
+
 func (c *Chunk) WithEmbedding(embedding []float32) *Chunk {
 	c.Embedding = embedding
 	return c
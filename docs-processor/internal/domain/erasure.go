@@ -0,0 +1,25 @@
+package domain
+
+import "time"
+
+// ErasureStepSearchIndex - шаг удаления данных организации, который выполняет docs-processor
+const ErasureStepSearchIndex = "search_index"
+
+// ErasureStepStatus - итог шага удаления
+type ErasureStepStatus string
+
+const (
+	ErasureStepCompleted ErasureStepStatus = "completed"
+	ErasureStepFailed    ErasureStepStatus = "failed"
+)
+
+// ErasureStepResult - ответ core-service о выполнении шага удаления данных
+type ErasureStepResult struct {
+	ErasureID    ID                `json:"erasure_id"`
+	Step         string            `json:"step"`
+	Source       string            `json:"source"`
+	Status       ErasureStepStatus `json:"status"`
+	ItemsDeleted int               `json:"items_deleted"`
+	Error        string            `json:"error,omitempty"`
+	FinishedAt   time.Time         `json:"finished_at"`
+}
@@ -0,0 +1,24 @@
+package domain
+
+import "time"
+
+// NotificationEventType - тип доменного события для сервиса уведомлений core-service
+type NotificationEventType string
+
+const (
+	NotificationEventDocumentIndexed NotificationEventType = "document.indexed"
+	NotificationEventDocumentFailed  NotificationEventType = "document.failed"
+)
+
+// NotificationEvent - событие, публикуемое в очередь уведомлений.
+// Без user_id уведомляются администраторы организации.
+type NotificationEvent struct {
+	EventID        string                `json:"event_id"`
+	Type           NotificationEventType `json:"type"`
+	Source         string                `json:"source"`
+	OrganizationID *ID                   `json:"organization_id,omitempty"`
+	UserID         *ID                   `json:"user_id,omitempty"`
+	Title          string                `json:"title"`
+	Text           string                `json:"text"`
+	OccurredAt     time.Time             `json:"occurred_at"`
+}
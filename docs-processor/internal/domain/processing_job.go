@@ -8,6 +8,8 @@ const (
 	JobTypeDocument       JobType = "document"
 	JobTypeTemplateIndex  JobType = "template_index"
 	JobTypeTemplateDelete JobType = "template_delete"
+	// JobTypeOrganizationPurge - удаление всех чанков организации при безвозвратном удалении ее данных
+	JobTypeOrganizationPurge JobType = "organization_purge"
 )
 
 type ProcessingJob struct {
@@ -27,6 +29,9 @@ type ProcessingJob struct {
 	TemplateType *string `json:"template_type,omitempty"`
 	FieldsCount  *int    `json:"fields_count,omitempty"`
 
+	// Для удаления данных организации: заявка, по которой core-service ждет результат
+	ErasureID *ID `json:"erasure_id,omitempty"`
+
 	RetryCount int       `json:"retry_count"`
 	MaxRetries int       `json:"max_retries"`
 	CreatedAt  time.Time `json:"created_at"`
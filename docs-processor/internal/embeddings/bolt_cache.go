@@ -0,0 +1,213 @@
+package embeddings
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/opentracing/opentracing-go"
+	bolt "go.etcd.io/bbolt"
+
+	"docs-processor/internal/logger"
+)
+
+var (
+	// boltVectorsBucket ключ кэша -> порядковый номер записи и вектор
+	boltVectorsBucket = []byte("vectors")
+	// boltOrderBucket порядковый номер -> ключ: самые старые записи вытесняются первыми
+	boltOrderBucket = []byte("order")
+	boltMetaBucket  = []byte("meta")
+	boltCountKey    = []byte("count")
+)
+
+var _ Cache = (*BoltCache)(nil)
+
+// BoltCache персистентный кэш embeddings в локальном файле bbolt. Переживает перезапуск,
+// поэтому повторная индексация тех же текстов после деплоя не обращается к API.
+// Размер ограничен maxEntries: при переполнении удаляются самые старые записи.
+type BoltCache struct {
+	db         *bolt.DB
+	maxEntries int
+}
+
+// OpenBoltCache открывает или создает файл кэша. Файл может открыть только один процесс
+func OpenBoltCache(path string, maxEntries int) (*BoltCache, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create embeddings cache directory: %w", err)
+	}
+
+	db, err := bolt.Open(path, 0o600, &bolt.Options{Timeout: time.Second})
+	if err != nil {
+		return nil, fmt.Errorf("failed to open embeddings cache %s: %w", path, err)
+	}
+
+	err = db.Update(func(tx *bolt.Tx) error {
+		for _, name := range [][]byte{boltVectorsBucket, boltOrderBucket, boltMetaBucket} {
+			if _, err := tx.CreateBucketIfNotExists(name); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to init embeddings cache: %w", err)
+	}
+
+	return &BoltCache{db: db, maxEntries: maxEntries}, nil
+}
+
+func (c *BoltCache) Get(ctx context.Context, key string) ([]float32, bool) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "embeddings.BoltCache.Get")
+	defer span.Finish()
+
+	var embedding []float32
+	err := c.db.View(func(tx *bolt.Tx) error {
+		value := tx.Bucket(boltVectorsBucket).Get([]byte(key))
+		if value == nil {
+			return nil
+		}
+		if len(value) < 8 {
+			return fmt.Errorf("corrupted embeddings cache entry of %d bytes", len(value))
+		}
+		var err error
+		embedding, err = decodeVector(value[8:])
+		return err
+	})
+	if err != nil {
+		logger.Warn(ctx, "failed to read embeddings cache", "error", err)
+		return nil, false
+	}
+
+	return embedding, embedding != nil
+}
+
+func (c *BoltCache) Set(ctx context.Context, key string, embedding []float32) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "embeddings.BoltCache.Set")
+	defer span.Finish()
+
+	// Batch объединяет записи параллельных запросов в одну транзакцию
+	err := c.db.Batch(func(tx *bolt.Tx) error {
+		vectors := tx.Bucket(boltVectorsBucket)
+		if vectors.Get([]byte(key)) != nil {
+			return nil
+		}
+
+		order := tx.Bucket(boltOrderBucket)
+		seq, err := order.NextSequence()
+		if err != nil {
+			return err
+		}
+
+		seqKey := make([]byte, 8)
+		binary.BigEndian.PutUint64(seqKey, seq)
+
+		if err := order.Put(seqKey, []byte(key)); err != nil {
+			return err
+		}
+		if err := vectors.Put([]byte(key), append(seqKey, encodeVector(embedding)...)); err != nil {
+			return err
+		}
+
+		meta := tx.Bucket(boltMetaBucket)
+		count := readCount(meta) + 1
+
+		// Ключи order упорядочены по номеру записи: первый - самый старый
+		cursor := order.Cursor()
+		for k, v := cursor.First(); k != nil && c.maxEntries > 0 && count > c.maxEntries; k, v = cursor.Next() {
+			if err := vectors.Delete(v); err != nil {
+				return err
+			}
+			if err := cursor.Delete(); err != nil {
+				return err
+			}
+			count--
+		}
+
+		return writeCount(meta, count)
+	})
+	if err != nil {
+		logger.Warn(ctx, "failed to write embeddings cache", "error", err)
+	}
+}
+
+// Len возвращает количество записей в кэше
+func (c *BoltCache) Len() int {
+	count := 0
+	_ = c.db.View(func(tx *bolt.Tx) error {
+		count = readCount(tx.Bucket(boltMetaBucket))
+		return nil
+	})
+	return count
+}
+
+func (c *BoltCache) Close() error {
+	return c.db.Close()
+}
+
+func readCount(meta *bolt.Bucket) int {
+	value := meta.Get(boltCountKey)
+	if len(value) != 8 {
+		return 0
+	}
+	return int(binary.BigEndian.Uint64(value))
+}
+
+func writeCount(meta *bolt.Bucket, count int) error {
+	value := make([]byte, 8)
+	binary.BigEndian.PutUint64(value, uint64(count))
+	return meta.Put(boltCountKey, value)
+}
+
+func encodeVector(embedding []float32) []byte {
+	buf := make([]byte, 4*len(embedding))
+	for i, v := range embedding {
+		binary.LittleEndian.PutUint32(buf[4*i:], math.Float32bits(v))
+	}
+	return buf
+}
+
+func decodeVector(buf []byte) ([]float32, error) {
+	if len(buf)%4 != 0 {
+		return nil, fmt.Errorf("corrupted embeddings cache entry of %d bytes", len(buf))
+	}
+	embedding := make([]float32, len(buf)/4)
+	for i := range embedding {
+		embedding[i] = math.Float32frombits(binary.LittleEndian.Uint32(buf[4*i:]))
+	}
+	return embedding, nil
+}
+
+var _ Cache = (*LayeredCache)(nil)
+
+// LayeredCache читает из быстрого кэша в памяти, а при промахе - из персистентного,
+// поднимая найденную запись в память
+type LayeredCache struct {
+	front Cache
+	back  Cache
+}
+
+func NewLayeredCache(front, back Cache) *LayeredCache {
+	return &LayeredCache{front: front, back: back}
+}
+
+func (c *LayeredCache) Get(ctx context.Context, key string) ([]float32, bool) {
+	if embedding, ok := c.front.Get(ctx, key); ok {
+		return embedding, true
+	}
+
+	embedding, ok := c.back.Get(ctx, key)
+	if ok {
+		c.front.Set(ctx, key, embedding)
+	}
+	return embedding, ok
+}
+
+func (c *LayeredCache) Set(ctx context.Context, key string, embedding []float32) {
+	c.front.Set(ctx, key, embedding)
+	c.back.Set(ctx, key, embedding)
+}
@@ -0,0 +1,234 @@
+package embeddings
+
+import (
+	"container/list"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sync"
+	"sync/atomic"
+
+	"github.com/opentracing/opentracing-go"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+
+	"docs-processor/internal/logger"
+)
+
+var cacheRequests = promauto.NewCounterVec(prometheus.CounterOpts{
+	Name: "docs_processor_embeddings_cache_requests_total",
+	Help: "Embedding cache lookups by result (hit/miss).",
+}, []string{"model", "result"})
+
+// Cache хранит embeddings по ключу, вычисленному из модели и текста
+type Cache interface {
+	Get(ctx context.Context, key string) ([]float32, bool)
+	Set(ctx context.Context, key string, embedding []float32)
+}
+
+// CacheKey возвращает ключ кэша для текста. Имя модели входит в ключ,
+// поэтому смена модели автоматически инвалидирует старые записи.
+func CacheKey(model, text string) string {
+	h := sha256.New()
+	h.Write([]byte(model))
+	h.Write([]byte{0})
+	h.Write([]byte(text))
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// CacheStats накопленная статистика обращений к кэшу
+type CacheStats struct {
+	Hits   int64
+	Misses int64
+}
+
+// HitRatio доля попаданий среди всех обращений
+func (s CacheStats) HitRatio() float64 {
+	total := s.Hits + s.Misses
+	if total == 0 {
+		return 0
+	}
+	return float64(s.Hits) / float64(total)
+}
+
+var _ Provider = (*CachedClient)(nil)
+
+// CachedClient кэширует embeddings поверх Provider и не отправляет
+// повторяющиеся тексты в API больше одного раза за запрос.
+type CachedClient struct {
+	client Provider
+	cache  Cache
+	hits   atomic.Int64
+	misses atomic.Int64
+}
+
+func NewCachedClient(client Provider, cache Cache) *CachedClient {
+	return &CachedClient{
+		client: client,
+		cache:  cache,
+	}
+}
+
+func (c *CachedClient) Model() string {
+	return c.client.Model()
+}
+
+func (c *CachedClient) Dimension() int {
+	return c.client.Dimension()
+}
+
+func (c *CachedClient) Stats() CacheStats {
+	return CacheStats{
+		Hits:   c.hits.Load(),
+		Misses: c.misses.Load(),
+	}
+}
+
+func (c *CachedClient) GenerateEmbeddings(ctx context.Context, texts []string) ([][]float32, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "embeddings.CachedClient.GenerateEmbeddings")
+	defer span.Finish()
+
+	if len(texts) == 0 {
+		return [][]float32{}, nil
+	}
+
+	model := c.client.Model()
+	result := make([][]float32, len(texts))
+
+	// Тексты, которых нет в кэше, дедуплицируются по ключу:
+	// одинаковые чанки отправляются в API один раз
+	missKeys := make([]string, 0)
+	missTexts := make([]string, 0)
+	positions := make(map[string][]int)
+
+	var hits, misses int64
+	for i, text := range texts {
+		key := CacheKey(model, text)
+		if embedding, ok := c.cache.Get(ctx, key); ok {
+			result[i] = embedding
+			hits++
+			continue
+		}
+
+		misses++
+		if _, seen := positions[key]; !seen {
+			missKeys = append(missKeys, key)
+			missTexts = append(missTexts, text)
+		}
+		positions[key] = append(positions[key], i)
+	}
+
+	c.hits.Add(hits)
+	c.misses.Add(misses)
+	cacheRequests.WithLabelValues(model, "hit").Add(float64(hits))
+	cacheRequests.WithLabelValues(model, "miss").Add(float64(misses))
+
+	span.SetTag("cache_hits", hits)
+	span.SetTag("cache_misses", misses)
+
+	if len(missTexts) > 0 {
+		embeddings, err := c.client.GenerateEmbeddings(ctx, missTexts)
+		if err != nil {
+			return nil, err
+		}
+
+		for j, key := range missKeys {
+			if j >= len(embeddings) || embeddings[j] == nil {
+				continue
+			}
+			c.cache.Set(ctx, key, embeddings[j])
+			for _, i := range positions[key] {
+				result[i] = embeddings[j]
+			}
+		}
+	}
+
+	stats := c.Stats()
+	logger.Info(ctx, "embeddings cache lookup",
+		"model", model,
+		"texts_count", len(texts),
+		"hits", hits,
+		"misses", misses,
+		"unique_requested", len(missTexts),
+		"total_hits", stats.Hits,
+		"total_misses", stats.Misses,
+		"hit_ratio", stats.HitRatio())
+
+	return result, nil
+}
+
+func (c *CachedClient) GenerateEmbedding(ctx context.Context, text string) ([]float32, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "embeddings.CachedClient.GenerateEmbedding")
+	defer span.Finish()
+
+	embeddings, err := c.GenerateEmbeddings(ctx, []string{text})
+	if err != nil {
+		return nil, err
+	}
+
+	if len(embeddings) == 0 || embeddings[0] == nil {
+		return nil, fmt.Errorf("no embeddings returned from API")
+	}
+
+	return embeddings[0], nil
+}
+
+// MemoryCache локальный LRU-кэш с ограничением по количеству записей
+type MemoryCache struct {
+	mu         sync.Mutex
+	maxEntries int
+	ll         *list.List
+	items      map[string]*list.Element
+}
+
+type memoryCacheEntry struct {
+	key       string
+	embedding []float32
+}
+
+func NewMemoryCache(maxEntries int) *MemoryCache {
+	return &MemoryCache{
+		maxEntries: maxEntries,
+		ll:         list.New(),
+		items:      make(map[string]*list.Element),
+	}
+}
+
+func (m *MemoryCache) Get(_ context.Context, key string) ([]float32, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	el, ok := m.items[key]
+	if !ok {
+		return nil, false
+	}
+
+	m.ll.MoveToFront(el)
+	return el.Value.(*memoryCacheEntry).embedding, true
+}
+
+func (m *MemoryCache) Set(_ context.Context, key string, embedding []float32) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if el, ok := m.items[key]; ok {
+		m.ll.MoveToFront(el)
+		el.Value.(*memoryCacheEntry).embedding = embedding
+		return
+	}
+
+	m.items[key] = m.ll.PushFront(&memoryCacheEntry{key: key, embedding: embedding})
+
+	if m.maxEntries > 0 && m.ll.Len() > m.maxEntries {
+		oldest := m.ll.Back()
+		m.ll.Remove(oldest)
+		delete(m.items, oldest.Value.(*memoryCacheEntry).key)
+	}
+}
+
+func (m *MemoryCache) Len() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.ll.Len()
+}
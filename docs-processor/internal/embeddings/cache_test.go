@@ -0,0 +1,243 @@
+package embeddings
+
+import (
+	"context"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// countingProvider возвращает для текста вектор из его длины и запоминает запросы
+type countingProvider struct {
+	model    string
+	requests [][]string
+}
+
+func (p *countingProvider) Model() string {
+	return p.model
+}
+
+func (p *countingProvider) Dimension() int {
+	return 1
+}
+
+func (p *countingProvider) GenerateEmbeddings(_ context.Context, texts []string) ([][]float32, error) {
+	p.requests = append(p.requests, append([]string(nil), texts...))
+	result := make([][]float32, len(texts))
+	for i, text := range texts {
+		result[i] = []float32{float32(len(text))}
+	}
+	return result, nil
+}
+
+func (p *countingProvider) GenerateEmbedding(ctx context.Context, text string) ([]float32, error) {
+	embeddings, err := p.GenerateEmbeddings(ctx, []string{text})
+	if err != nil {
+		return nil, err
+	}
+	return embeddings[0], nil
+}
+
+func TestMemoryCacheEvictsLeastRecentlyUsed(t *testing.T) {
+	ctx := context.Background()
+	cache := NewMemoryCache(2)
+
+	cache.Set(ctx, "a", []float32{1})
+	cache.Set(ctx, "b", []float32{2})
+	// Чтение делает "a" самой свежей записью, поэтому вытесняется "b"
+	if _, ok := cache.Get(ctx, "a"); !ok {
+		t.Fatal("Get(a) missed before eviction")
+	}
+	cache.Set(ctx, "c", []float32{3})
+
+	if cache.Len() != 2 {
+		t.Errorf("Len = %d, want 2", cache.Len())
+	}
+	if _, ok := cache.Get(ctx, "b"); ok {
+		t.Error("Get(b) hit, want evicted")
+	}
+	for _, key := range []string{"a", "c"} {
+		if _, ok := cache.Get(ctx, key); !ok {
+			t.Errorf("Get(%s) missed, want kept", key)
+		}
+	}
+}
+
+func TestCachedClient(t *testing.T) {
+	tests := []struct {
+		name         string
+		warm         []string
+		texts        []string
+		wantRequests [][]string
+		wantHits     int64
+		wantMisses   int64
+	}{
+		{
+			name:         "дубликаты в батче запрашиваются один раз",
+			texts:        []string{"one", "three", "one", "one"},
+			wantRequests: [][]string{{"one", "three"}},
+			wantMisses:   4,
+		},
+		{
+			name:         "закэшированные тексты не запрашиваются",
+			warm:         []string{"one"},
+			texts:        []string{"one", "three"},
+			wantRequests: [][]string{{"one"}, {"three"}},
+			wantHits:     1,
+			wantMisses:   1,
+		},
+		{
+			name:         "все тексты в кэше",
+			warm:         []string{"one", "three"},
+			texts:        []string{"three", "one", "three"},
+			wantRequests: [][]string{{"one", "three"}},
+			wantHits:     3,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			provider := &countingProvider{model: "bge-m3"}
+			client := NewCachedClient(provider, NewMemoryCache(100))
+
+			if len(tt.warm) > 0 {
+				if _, err := client.GenerateEmbeddings(ctx, tt.warm); err != nil {
+					t.Fatalf("warm GenerateEmbeddings: %v", err)
+				}
+			}
+			warmStats := client.Stats()
+
+			got, err := client.GenerateEmbeddings(ctx, tt.texts)
+			if err != nil {
+				t.Fatalf("GenerateEmbeddings: %v", err)
+			}
+
+			for i, text := range tt.texts {
+				if len(got[i]) != 1 || got[i][0] != float32(len(text)) {
+					t.Errorf("embedding[%d] = %v, want [%d]", i, got[i], len(text))
+				}
+			}
+			if !reflect.DeepEqual(provider.requests, tt.wantRequests) {
+				t.Errorf("provider requests = %v, want %v", provider.requests, tt.wantRequests)
+			}
+
+			stats := client.Stats()
+			if hits := stats.Hits - warmStats.Hits; hits != tt.wantHits {
+				t.Errorf("hits = %d, want %d", hits, tt.wantHits)
+			}
+			if misses := stats.Misses - warmStats.Misses; misses != tt.wantMisses {
+				t.Errorf("misses = %d, want %d", misses, tt.wantMisses)
+			}
+		})
+	}
+}
+
+func TestCachedClientModelInKey(t *testing.T) {
+	ctx := context.Background()
+	cache := NewMemoryCache(100)
+
+	oldProvider := &countingProvider{model: "text-embedding-3-small"}
+	if _, err := NewCachedClient(oldProvider, cache).GenerateEmbeddings(ctx, []string{"text"}); err != nil {
+		t.Fatalf("GenerateEmbeddings: %v", err)
+	}
+
+	// Общий кэш, но другая модель: запись старой модели не должна вернуться
+	newProvider := &countingProvider{model: "bge-m3"}
+	client := NewCachedClient(newProvider, cache)
+	if _, err := client.GenerateEmbeddings(ctx, []string{"text"}); err != nil {
+		t.Fatalf("GenerateEmbeddings: %v", err)
+	}
+
+	if len(newProvider.requests) != 1 {
+		t.Errorf("new model requests = %d, want 1", len(newProvider.requests))
+	}
+	if stats := client.Stats(); stats.Hits != 0 || stats.Misses != 1 {
+		t.Errorf("stats = %+v, want 0 hits and 1 miss", stats)
+	}
+	if CacheKey("bge-m3", "text") == CacheKey("text-embedding-3-small", "text") {
+		t.Error("CacheKey does not depend on model")
+	}
+}
+
+func openTestBoltCache(t *testing.T, path string, maxEntries int) *BoltCache {
+	t.Helper()
+
+	cache, err := OpenBoltCache(path, maxEntries)
+	if err != nil {
+		t.Fatalf("OpenBoltCache: %v", err)
+	}
+	return cache
+}
+
+func TestBoltCachePersistsAcrossReopen(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "cache", "embeddings.db")
+	want := []float32{0.25, -1.5, 3}
+
+	cache := openTestBoltCache(t, path, 10)
+	cache.Set(ctx, "key", want)
+	if err := cache.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+
+	reopened := openTestBoltCache(t, path, 10)
+	defer reopened.Close()
+
+	got, ok := reopened.Get(ctx, "key")
+	if !ok {
+		t.Fatal("Get after reopen missed")
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Get = %v, want %v", got, want)
+	}
+	if _, ok := reopened.Get(ctx, "missing"); ok {
+		t.Error("Get(missing) hit")
+	}
+}
+
+func TestBoltCacheEvictsOldest(t *testing.T) {
+	ctx := context.Background()
+	cache := openTestBoltCache(t, filepath.Join(t.TempDir(), "embeddings.db"), 2)
+	defer cache.Close()
+
+	cache.Set(ctx, "a", []float32{1})
+	cache.Set(ctx, "b", []float32{2})
+	// Повторная запись существующего ключа не добавляет запись
+	cache.Set(ctx, "a", []float32{1})
+	cache.Set(ctx, "c", []float32{3})
+
+	if cache.Len() != 2 {
+		t.Errorf("Len = %d, want 2", cache.Len())
+	}
+	if _, ok := cache.Get(ctx, "a"); ok {
+		t.Error("Get(a) hit, want evicted")
+	}
+	for _, key := range []string{"b", "c"} {
+		if _, ok := cache.Get(ctx, key); !ok {
+			t.Errorf("Get(%s) missed, want kept", key)
+		}
+	}
+}
+
+func TestLayeredCachePromotesPersistentHits(t *testing.T) {
+	ctx := context.Background()
+	front := NewMemoryCache(10)
+	back := openTestBoltCache(t, filepath.Join(t.TempDir(), "embeddings.db"), 10)
+	defer back.Close()
+
+	back.Set(ctx, "key", []float32{7})
+	cache := NewLayeredCache(front, back)
+
+	if got, ok := cache.Get(ctx, "key"); !ok || !reflect.DeepEqual(got, []float32{7}) {
+		t.Fatalf("Get = %v, %v, want [7], true", got, ok)
+	}
+	if _, ok := front.Get(ctx, "key"); !ok {
+		t.Error("persistent hit was not promoted to memory")
+	}
+
+	cache.Set(ctx, "other", []float32{8})
+	if _, ok := back.Get(ctx, "other"); !ok {
+		t.Error("Set did not reach persistent cache")
+	}
+}
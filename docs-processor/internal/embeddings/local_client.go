@@ -0,0 +1,172 @@
+package embeddings
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/opentracing/opentracing-go"
+
+	"docs-processor/internal/logger"
+)
+
+var _ Provider = (*LocalClient)(nil)
+
+// LocalClient работает с self-hosted серверами embeddings:
+// text-embeddings-inference (POST /embed) и Ollama (POST /api/embed)
+type LocalClient struct {
+	kind       ProviderType
+	baseURL    string
+	apiKey     string
+	model      string
+	dimension  int
+	httpClient *http.Client
+}
+
+func NewLocalClient(kind ProviderType, baseURL, apiKey, model string, dimension int) *LocalClient {
+	return &LocalClient{
+		kind:      kind,
+		baseURL:   baseURL,
+		apiKey:    apiKey,
+		model:     model,
+		dimension: dimension,
+		httpClient: &http.Client{
+			Timeout: 120 * time.Second,
+		},
+	}
+}
+
+func (c *LocalClient) Model() string {
+	return c.model
+}
+
+func (c *LocalClient) Dimension() int {
+	return c.dimension
+}
+
+type teiEmbedRequest struct {
+	Inputs   []string `json:"inputs"`
+	Truncate bool     `json:"truncate"`
+}
+
+type ollamaEmbedRequest struct {
+	Model string   `json:"model"`
+	Input []string `json:"input"`
+}
+
+type ollamaEmbedResponse struct {
+	Embeddings [][]float32 `json:"embeddings"`
+}
+
+func (c *LocalClient) GenerateEmbeddings(ctx context.Context, texts []string) ([][]float32, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "embeddings.LocalClient.GenerateEmbeddings")
+	defer span.Finish()
+
+	if len(texts) == 0 {
+		return [][]float32{}, nil
+	}
+
+	logger.Info(ctx, "generating embeddings", "provider", string(c.kind), "texts_count", len(texts))
+
+	var (
+		path    string
+		reqBody any
+	)
+	switch c.kind {
+	case ProviderTypeTEI:
+		path = "/embed"
+		reqBody = teiEmbedRequest{Inputs: texts, Truncate: true}
+	case ProviderTypeOllama:
+		path = "/api/embed"
+		reqBody = ollamaEmbedRequest{Model: c.model, Input: texts}
+	default:
+		return nil, fmt.Errorf("unsupported local embeddings provider: %s", c.kind)
+	}
+
+	jsonData, err := json.Marshal(reqBody)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal embeddings request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", c.baseURL+path, bytes.NewBuffer(jsonData))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create embeddings request: %w", err)
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	if c.apiKey != "" {
+		req.Header.Set("Authorization", "Bearer "+c.apiKey)
+	}
+
+	start := time.Now()
+	resp, err := c.httpClient.Do(req)
+	duration := time.Since(start)
+
+	if err != nil {
+		logger.Error(ctx, "failed to send embeddings request",
+			"provider", string(c.kind),
+			"error", err,
+			"duration", duration,
+			"texts_count", len(texts))
+		return nil, fmt.Errorf("failed to send embeddings request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		logger.Error(ctx, "embeddings API error",
+			"provider", string(c.kind),
+			"status_code", resp.StatusCode,
+			"response_body", string(body))
+		return nil, fmt.Errorf("embeddings API error (status %d): %s", resp.StatusCode, string(body))
+	}
+
+	var embeddings [][]float32
+	switch c.kind {
+	case ProviderTypeTEI:
+		err = json.NewDecoder(resp.Body).Decode(&embeddings)
+	case ProviderTypeOllama:
+		var ollamaResp ollamaEmbedResponse
+		err = json.NewDecoder(resp.Body).Decode(&ollamaResp)
+		embeddings = ollamaResp.Embeddings
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode embeddings response: %w", err)
+	}
+
+	if len(embeddings) != len(texts) {
+		return nil, fmt.Errorf("embeddings API returned %d vectors for %d texts", len(embeddings), len(texts))
+	}
+
+	if err := checkDimension(c.model, c.dimension, embeddings); err != nil {
+		logger.Error(ctx, "embeddings dimension mismatch", "error", err)
+		return nil, err
+	}
+
+	logger.Info(ctx, "embeddings generated successfully",
+		"provider", string(c.kind),
+		"embeddings_count", len(embeddings),
+		"total_duration", duration)
+
+	return embeddings, nil
+}
+
+func (c *LocalClient) GenerateEmbedding(ctx context.Context, text string) ([]float32, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "embeddings.LocalClient.GenerateEmbedding")
+	defer span.Finish()
+
+	embeddings, err := c.GenerateEmbeddings(ctx, []string{text})
+	if err != nil {
+		return nil, err
+	}
+
+	if len(embeddings) == 0 {
+		return nil, fmt.Errorf("no embeddings returned from API")
+	}
+
+	return embeddings[0], nil
+}
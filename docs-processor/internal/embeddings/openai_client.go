@@ -14,38 +14,51 @@ import (
 	"docs-processor/internal/logger"
 )
 
-type Client struct {
+var _ Provider = (*OpenAIClient)(nil)
+
+// OpenAIClient работает с любым OpenAI-совместимым эндпоинтом /embeddings
+type OpenAIClient struct {
 	baseURL    string
 	apiKey     string
 	model      string
+	dimension  int
 	httpClient *http.Client
 }
 
-func NewClient(baseURL, apiKey, model string) *Client {
-	return &Client{
-		baseURL: baseURL,
-		apiKey:  apiKey,
-		model:   model,
+func NewOpenAIClient(baseURL, apiKey, model string, dimension int) *OpenAIClient {
+	return &OpenAIClient{
+		baseURL:   baseURL,
+		apiKey:    apiKey,
+		model:     model,
+		dimension: dimension,
 		httpClient: &http.Client{
 			Timeout: 60 * time.Second,
 		},
 	}
 }
 
-type embeddingRequest struct {
+func (c *OpenAIClient) Model() string {
+	return c.model
+}
+
+func (c *OpenAIClient) Dimension() int {
+	return c.dimension
+}
+
+type openAIEmbeddingRequest struct {
 	Input []string `json:"input"`
 	Model string   `json:"model"`
 }
 
-type embeddingResponse struct {
+type openAIEmbeddingResponse struct {
 	Data []struct {
 		Embedding []float32 `json:"embedding"`
 		Index     int       `json:"index"`
 	} `json:"data"`
 }
 
-func (c *Client) GenerateEmbeddings(ctx context.Context, texts []string) ([][]float32, error) {
-	span, ctx := opentracing.StartSpanFromContext(ctx, "embeddings.Client.GenerateEmbeddings")
+func (c *OpenAIClient) GenerateEmbeddings(ctx context.Context, texts []string) ([][]float32, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "embeddings.OpenAIClient.GenerateEmbeddings")
 	defer span.Finish()
 
 	if len(texts) == 0 {
@@ -54,7 +67,7 @@ func (c *Client) GenerateEmbeddings(ctx context.Context, texts []string) ([][]fl
 
 	logger.Info(ctx, "generating embeddings", "texts_count", len(texts))
 
-	reqBody := embeddingRequest{
+	reqBody := openAIEmbeddingRequest{
 		Input: texts,
 		Model: c.model,
 	}
@@ -100,7 +113,7 @@ func (c *Client) GenerateEmbeddings(ctx context.Context, texts []string) ([][]fl
 		return nil, fmt.Errorf("embeddings API error (status %d): %s", resp.StatusCode, string(body))
 	}
 
-	var embResp embeddingResponse
+	var embResp openAIEmbeddingResponse
 	if err := json.NewDecoder(resp.Body).Decode(&embResp); err != nil {
 		logger.Error(ctx, "failed to decode embeddings response", "error", err)
 		return nil, fmt.Errorf("failed to decode embeddings response: %w", err)
@@ -113,6 +126,11 @@ func (c *Client) GenerateEmbeddings(ctx context.Context, texts []string) ([][]fl
 		}
 	}
 
+	if err := checkDimension(c.model, c.dimension, embeddings); err != nil {
+		logger.Error(ctx, "embeddings dimension mismatch", "error", err)
+		return nil, err
+	}
+
 	logger.Info(ctx, "embeddings generated successfully",
 		"embeddings_count", len(embeddings),
 		"total_duration", duration)
@@ -120,8 +138,8 @@ func (c *Client) GenerateEmbeddings(ctx context.Context, texts []string) ([][]fl
 	return embeddings, nil
 }
 
-func (c *Client) GenerateEmbedding(ctx context.Context, text string) ([]float32, error) {
-	span, ctx := opentracing.StartSpanFromContext(ctx, "embeddings.Client.GenerateEmbedding")
+func (c *OpenAIClient) GenerateEmbedding(ctx context.Context, text string) ([]float32, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "embeddings.OpenAIClient.GenerateEmbedding")
 	defer span.Finish()
 
 	embeddings, err := c.GenerateEmbeddings(ctx, []string{text})
@@ -0,0 +1,137 @@
+package embeddings
+
+import (
+	"context"
+	"fmt"
+
+	"docs-processor/internal/config"
+	"docs-processor/internal/logger"
+)
+
+// Provider генерирует embeddings одной конкретной моделью.
+// Model и Dimension определяют, в какой индекс OpenSearch попадут векторы.
+type Provider interface {
+	GenerateEmbeddings(ctx context.Context, texts []string) ([][]float32, error)
+	GenerateEmbedding(ctx context.Context, text string) ([]float32, error)
+	Model() string
+	Dimension() int
+}
+
+type ProviderType string
+
+const (
+	// ProviderTypeOpenAI любой OpenAI-совместимый API (/embeddings)
+	ProviderTypeOpenAI ProviderType = "openai"
+	// ProviderTypeTEI HuggingFace text-embeddings-inference (/embed)
+	ProviderTypeTEI ProviderType = "tei"
+	// ProviderTypeOllama Ollama (/api/embed)
+	ProviderTypeOllama ProviderType = "ollama"
+)
+
+type ProviderConfig struct {
+	Type      ProviderType
+	BaseURL   string
+	APIKey    string
+	Model     string
+	Dimension int
+}
+
+func NewProvider(cfg ProviderConfig) (Provider, error) {
+	if cfg.Model == "" {
+		return nil, fmt.Errorf("embeddings model is required")
+	}
+	if cfg.Dimension <= 0 {
+		return nil, fmt.Errorf("embeddings dimension must be positive for model %s", cfg.Model)
+	}
+
+	switch cfg.Type {
+	case ProviderTypeOpenAI, "":
+		return NewOpenAIClient(cfg.BaseURL, cfg.APIKey, cfg.Model, cfg.Dimension), nil
+	case ProviderTypeTEI, ProviderTypeOllama:
+		return NewLocalClient(cfg.Type, cfg.BaseURL, cfg.APIKey, cfg.Model, cfg.Dimension), nil
+	default:
+		return nil, fmt.Errorf("unknown embeddings provider: %s", cfg.Type)
+	}
+}
+
+// checkDimension защищает индекс от векторов неверной размерности,
+// если в конфиге указана размерность, не совпадающая с моделью
+func checkDimension(model string, dimension int, embeddings [][]float32) error {
+	for i, e := range embeddings {
+		if e != nil && len(e) != dimension {
+			return fmt.Errorf("embedding %d of model %s has dimension %d, expected %d", i, model, len(e), dimension)
+		}
+	}
+	return nil
+}
+
+// NewProvidersFromConfig создает провайдер активной модели и провайдеры прошлых
+// моделей. Если кэш включен, все провайдеры используют общий кэш: ключ содержит
+// имя модели, поэтому записи разных моделей не пересекаются.
+func NewProvidersFromConfig(cfg *config.Config) (Provider, []Provider, error) {
+	var cache Cache
+	if cfg.GetEmbeddingsCacheEnabled() {
+		cache = newCacheFromConfig(cfg)
+	}
+
+	build := func(m config.EmbeddingModel) (Provider, error) {
+		p, err := NewProvider(ProviderConfig{
+			Type:      ProviderType(m.Provider),
+			BaseURL:   m.BaseURL,
+			APIKey:    m.APIKey,
+			Model:     m.Model,
+			Dimension: m.Dimension,
+		})
+		if err != nil {
+			return nil, err
+		}
+		if cache != nil {
+			return NewCachedClient(p, cache), nil
+		}
+		return p, nil
+	}
+
+	active, err := build(config.EmbeddingModel{
+		Provider:  cfg.GetEmbeddingsProvider(),
+		BaseURL:   cfg.GetEmbeddingsBaseURL(),
+		APIKey:    cfg.GetEmbeddingsAPIKey(),
+		Model:     cfg.GetEmbeddingsModel(),
+		Dimension: cfg.GetEmbeddingsDimension(),
+	})
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create embeddings provider: %w", err)
+	}
+
+	previous := make([]Provider, 0)
+	for _, m := range cfg.GetEmbeddingsPreviousModels() {
+		p, err := build(m)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to create previous embeddings provider: %w", err)
+		}
+		previous = append(previous, p)
+	}
+
+	return active, previous, nil
+}
+
+// newCacheFromConfig создает кэш в памяти и, если задан путь, персистентный кэш за ним.
+// Если файл кэша занят другим процессом, используется только кэш в памяти
+func newCacheFromConfig(cfg *config.Config) Cache {
+	memory := NewMemoryCache(cfg.GetEmbeddingsCacheMaxEntries())
+
+	path := cfg.GetEmbeddingsCachePath()
+	if path == "" {
+		return memory
+	}
+
+	persistent, err := OpenBoltCache(path, cfg.GetEmbeddingsCachePersistentMaxEntries())
+	if err != nil {
+		logger.Warn(context.Background(), "Persistent embeddings cache is unavailable, using memory cache only",
+			"path", path,
+			"error", err,
+		)
+		return memory
+	}
+
+	return NewLayeredCache(memory, persistent)
+}
@@ -0,0 +1,186 @@
+package embeddings
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"docs-processor/internal/logger"
+)
+
+func TestMain(m *testing.M) {
+	logger.Init()
+	os.Exit(m.Run())
+}
+
+// embeddingServer фейковый сервер embeddings: запоминает последний запрос и отвечает body
+type embeddingServer struct {
+	path   string
+	auth   string
+	body   map[string]interface{}
+	status int
+	reply  string
+}
+
+func newEmbeddingServer(t *testing.T, status int, reply string) (*embeddingServer, string) {
+	t.Helper()
+
+	s := &embeddingServer{status: status, reply: reply}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.path = r.URL.Path
+		s.auth = r.Header.Get("Authorization")
+		raw, _ := io.ReadAll(r.Body)
+		s.body = nil
+		_ = json.Unmarshal(raw, &s.body)
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(s.status)
+		_, _ = w.Write([]byte(s.reply))
+	}))
+	t.Cleanup(server.Close)
+
+	return s, server.URL
+}
+
+func TestNewProvider(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     ProviderConfig
+		want    string
+		wantErr bool
+	}{
+		{name: "openai", cfg: ProviderConfig{Type: ProviderTypeOpenAI, Model: "text-embedding-3-small", Dimension: 1024}, want: "*embeddings.OpenAIClient"},
+		{name: "тип по умолчанию", cfg: ProviderConfig{Model: "text-embedding-3-small", Dimension: 1024}, want: "*embeddings.OpenAIClient"},
+		{name: "tei", cfg: ProviderConfig{Type: ProviderTypeTEI, Model: "BAAI/bge-m3", Dimension: 1024}, want: "*embeddings.LocalClient"},
+		{name: "ollama", cfg: ProviderConfig{Type: ProviderTypeOllama, Model: "nomic-embed-text", Dimension: 768}, want: "*embeddings.LocalClient"},
+		{name: "неизвестный тип", cfg: ProviderConfig{Type: "cohere", Model: "embed", Dimension: 1024}, wantErr: true},
+		{name: "без модели", cfg: ProviderConfig{Type: ProviderTypeOpenAI, Dimension: 1024}, wantErr: true},
+		{name: "без размерности", cfg: ProviderConfig{Type: ProviderTypeOpenAI, Model: "text-embedding-3-small"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, err := NewProvider(tt.cfg)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("NewProvider(%+v) = %T, want error", tt.cfg, p)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("NewProvider(%+v): %v", tt.cfg, err)
+			}
+			if got := fmt.Sprintf("%T", p); got != tt.want {
+				t.Errorf("provider type = %s, want %s", got, tt.want)
+			}
+			if p.Model() != tt.cfg.Model || p.Dimension() != tt.cfg.Dimension {
+				t.Errorf("provider = %s/%d, want %s/%d", p.Model(), p.Dimension(), tt.cfg.Model, tt.cfg.Dimension)
+			}
+		})
+	}
+}
+
+func TestOpenAIClientOrdersEmbeddingsByIndex(t *testing.T) {
+	server, url := newEmbeddingServer(t, http.StatusOK, `{"data": [
+		{"index": 1, "embedding": [0.3, 0.4]},
+		{"index": 0, "embedding": [0.1, 0.2]}
+	]}`)
+
+	client := NewOpenAIClient(url, "secret", "text-embedding-3-small", 2)
+	got, err := client.GenerateEmbeddings(context.Background(), []string{"первый", "второй"})
+	if err != nil {
+		t.Fatalf("GenerateEmbeddings: %v", err)
+	}
+
+	if server.path != "/embeddings" {
+		t.Errorf("path = %s, want /embeddings", server.path)
+	}
+	if server.auth != "Bearer secret" {
+		t.Errorf("authorization = %q, want bearer token", server.auth)
+	}
+	if server.body["model"] != "text-embedding-3-small" {
+		t.Errorf("request model = %v", server.body["model"])
+	}
+	if input, _ := server.body["input"].([]interface{}); len(input) != 2 || input[0] != "первый" {
+		t.Errorf("request input = %v", server.body["input"])
+	}
+
+	if len(got) != 2 || got[0][0] != 0.1 || got[1][0] != 0.3 {
+		t.Errorf("embeddings = %v, want ordered by index", got)
+	}
+}
+
+func TestLocalClientRequests(t *testing.T) {
+	tests := []struct {
+		name      string
+		kind      ProviderType
+		reply     string
+		wantPath  string
+		wantField string
+	}{
+		{name: "tei", kind: ProviderTypeTEI, reply: `[[0.1, 0.2], [0.3, 0.4]]`, wantPath: "/embed", wantField: "inputs"},
+		{name: "ollama", kind: ProviderTypeOllama, reply: `{"embeddings": [[0.1, 0.2], [0.3, 0.4]]}`, wantPath: "/api/embed", wantField: "input"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server, url := newEmbeddingServer(t, http.StatusOK, tt.reply)
+
+			client := NewLocalClient(tt.kind, url, "", "bge-m3", 2)
+			got, err := client.GenerateEmbeddings(context.Background(), []string{"a", "b"})
+			if err != nil {
+				t.Fatalf("GenerateEmbeddings: %v", err)
+			}
+
+			if server.path != tt.wantPath {
+				t.Errorf("path = %s, want %s", server.path, tt.wantPath)
+			}
+			if server.auth != "" {
+				t.Errorf("authorization = %q, want none without api key", server.auth)
+			}
+			if _, ok := server.body[tt.wantField]; !ok {
+				t.Errorf("request body %v has no %s", server.body, tt.wantField)
+			}
+			if len(got) != 2 || got[1][1] != 0.4 {
+				t.Errorf("embeddings = %v", got)
+			}
+		})
+	}
+}
+
+func TestProviderErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		kind    ProviderType
+		status  int
+		reply   string
+		wantErr string
+	}{
+		{name: "неверная размерность", kind: ProviderTypeOpenAI, status: http.StatusOK, reply: `{"data": [{"index": 0, "embedding": [0.1, 0.2, 0.3]}]}`, wantErr: "dimension 3, expected 2"},
+		{name: "ошибка API", kind: ProviderTypeOpenAI, status: http.StatusTooManyRequests, reply: `{"error": "rate limit"}`, wantErr: "status 429"},
+		{name: "tei: неверная размерность", kind: ProviderTypeTEI, status: http.StatusOK, reply: `[[0.1]]`, wantErr: "dimension 1, expected 2"},
+		{name: "ollama: не все векторы", kind: ProviderTypeOllama, status: http.StatusOK, reply: `{"embeddings": []}`, wantErr: "returned 0 vectors for 1 texts"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, url := newEmbeddingServer(t, tt.status, tt.reply)
+
+			p, err := NewProvider(ProviderConfig{Type: tt.kind, BaseURL: url, Model: "model", Dimension: 2})
+			if err != nil {
+				t.Fatalf("NewProvider: %v", err)
+			}
+
+			_, err = p.GenerateEmbedding(context.Background(), "текст")
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("GenerateEmbedding error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
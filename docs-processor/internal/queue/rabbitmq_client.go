@@ -89,6 +89,35 @@ func (c *RabbitMQClient) PublishJob(ctx context.Context, job *domain.ProcessingJ
 	return nil
 }
 
+// PublishMessage публикует произвольное JSON-сообщение в очередь клиента
+func (c *RabbitMQClient) PublishMessage(ctx context.Context, message interface{}) error {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "queue.RabbitMQClient.PublishMessage")
+	defer span.Finish()
+
+	body, err := json.Marshal(message)
+	if err != nil {
+		return fmt.Errorf("failed to marshal message: %w", err)
+	}
+
+	err = c.channel.PublishWithContext(
+		ctx,
+		"",
+		c.queueName,
+		false,
+		false,
+		amqp.Publishing{
+			ContentType:  "application/json",
+			Body:         body,
+			DeliveryMode: amqp.Persistent,
+		},
+	)
+	if err != nil {
+		return fmt.Errorf("failed to publish message: %w", err)
+	}
+
+	return nil
+}
+
 type JobHandler func(context.Context, *domain.ProcessingJob) error
 
 func (c *RabbitMQClient) ConsumeJobs(ctx context.Context, consumerTag string, prefetchCount int, handler JobHandler) error {
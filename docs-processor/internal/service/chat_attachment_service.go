@@ -0,0 +1,135 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"docs-processor/internal/chunker"
+	"docs-processor/internal/domain"
+	"docs-processor/internal/logger"
+	"docs-processor/internal/parser"
+	"docs-processor/internal/storage"
+	"docs-processor/internal/vectordb"
+
+	"github.com/opentracing/opentracing-go"
+)
+
+// ChatAttachmentResult результат обработки вложения чата
+type ChatAttachmentResult struct {
+	// Inline - текст возвращен целиком и не индексировался
+	Inline     bool
+	Text       string
+	TextLength int
+	ChunkCount int
+}
+
+// ChatAttachmentService обрабатывает файлы, приложенные к чату: в отличие от
+// документов организации они не регистрируются в core-service и индексируются
+// с привязкой к чату, поэтому не попадают в общий RAG
+type ChatAttachmentService struct {
+	s3Client       *storage.S3Client
+	parserRegistry *parser.Registry
+	chunker        *chunker.Chunker
+	indexRouter    *IndexRouter
+	vectorDB       *vectordb.OpenSearchClient
+	batchSize      int
+	inlineMaxChars int
+}
+
+func NewChatAttachmentService(
+	s3Client *storage.S3Client,
+	parserRegistry *parser.Registry,
+	chunker *chunker.Chunker,
+	indexRouter *IndexRouter,
+	vectorDB *vectordb.OpenSearchClient,
+	batchSize int,
+	inlineMaxChars int,
+) *ChatAttachmentService {
+	return &ChatAttachmentService{
+		s3Client:       s3Client,
+		parserRegistry: parserRegistry,
+		chunker:        chunker,
+		indexRouter:    indexRouter,
+		vectorDB:       vectorDB,
+		batchSize:      batchSize,
+		inlineMaxChars: inlineMaxChars,
+	}
+}
+
+// IndexAttachment разбирает файл; короткий текст возвращает целиком,
+// длинный разбивает на чанки и индексирует с привязкой к чату
+func (s *ChatAttachmentService) IndexAttachment(
+	ctx context.Context,
+	organizationID, chatID, attachmentID domain.ID,
+	s3Key, fileName string,
+	docType domain.DocumentType,
+) (*ChatAttachmentResult, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "service.ChatAttachmentService.IndexAttachment")
+	defer span.Finish()
+
+	// Файлы организации загружаются в documents/{organization_id}/
+	if !strings.HasPrefix(s3Key, fmt.Sprintf("documents/%s/", organizationID.String())) || strings.Contains(s3Key, "..") {
+		return nil, fmt.Errorf("attachment does not belong to organization")
+	}
+
+	reader, err := s.s3Client.GetObject(ctx, s3Key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get attachment from storage: %w", err)
+	}
+	defer reader.Close()
+
+	text, err := s.parserRegistry.Parse(ctx, docType, reader)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse attachment: %w", err)
+	}
+
+	text = strings.TrimSpace(text)
+	result := &ChatAttachmentResult{TextLength: utf8.RuneCountInString(text)}
+
+	if result.TextLength <= s.inlineMaxChars {
+		result.Inline = true
+		result.Text = text
+		return result, nil
+	}
+
+	chunks, err := s.chunker.ChunkText(ctx, attachmentID, text)
+	if err != nil {
+		return nil, fmt.Errorf("failed to chunk attachment: %w", err)
+	}
+	for _, chunk := range chunks {
+		chunk.WithChat(chatID)
+	}
+
+	// Вложение векторизуется той же моделью, что и запросы к документам организации
+	index, err := s.indexRouter.BindOrganization(ctx, organizationID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve organization index: %w", err)
+	}
+	if err := s.vectorDB.EnsureIndex(ctx, index.Name, index.Provider.Dimension()); err != nil {
+		return nil, fmt.Errorf("failed to prepare index: %w", err)
+	}
+
+	if err := embedAndIndexChunks(ctx, s.vectorDB, s.indexRouter, index, chunks, organizationID, fileName, s.batchSize); err != nil {
+		return nil, err
+	}
+
+	result.ChunkCount = len(chunks)
+
+	logger.Info(ctx, "Chat attachment indexed",
+		"chat_id", chatID,
+		"attachment_id", attachmentID,
+		"chunk_count", result.ChunkCount,
+	)
+
+	return result, nil
+}
+
+// DeleteAttachments удаляет чанки вложений чата; с attachmentID - только одного вложения
+func (s *ChatAttachmentService) DeleteAttachments(ctx context.Context, organizationID, chatID domain.ID, attachmentID *domain.ID) error {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "service.ChatAttachmentService.DeleteAttachments")
+	defer span.Finish()
+
+	return s.vectorDB.DeleteChatChunks(ctx, organizationID, chatID, attachmentID)
+}
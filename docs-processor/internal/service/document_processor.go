@@ -8,7 +8,6 @@ import (
 	"docs-processor/internal/chunker"
 	"docs-processor/internal/coreservice"
 	"docs-processor/internal/domain"
-	"docs-processor/internal/embeddings"
 	"docs-processor/internal/logger"
 	"docs-processor/internal/parser"
 	"docs-processor/internal/storage"
@@ -22,7 +21,7 @@ type DocumentProcessor struct {
 	s3Client       *storage.S3Client
 	parserRegistry *parser.Registry
 	chunker        *chunker.Chunker
-	embeddingsCli  *embeddings.Client
+	indexRouter    *IndexRouter
 	vectorDB       *vectordb.OpenSearchClient
 	coreClient     *coreservice.Client
 	batchSize      int
@@ -32,7 +31,7 @@ func NewDocumentProcessor(
 	s3Client *storage.S3Client,
 	parserRegistry *parser.Registry,
 	chunker *chunker.Chunker,
-	embeddingsCli *embeddings.Client,
+	indexRouter *IndexRouter,
 	vectorDB *vectordb.OpenSearchClient,
 	coreClient *coreservice.Client,
 	batchSize int,
@@ -41,7 +40,7 @@ func NewDocumentProcessor(
 		s3Client:       s3Client,
 		parserRegistry: parserRegistry,
 		chunker:        chunker,
-		embeddingsCli:  embeddingsCli,
+		indexRouter:    indexRouter,
 		vectorDB:       vectorDB,
 		coreClient:     coreClient,
 		batchSize:      batchSize,
@@ -119,8 +118,76 @@ func (p *DocumentProcessor) generateAndIndexEmbeddings(ctx context.Context, chun
 	span, ctx := opentracing.StartSpanFromContext(ctx, "service.DocumentProcessor.generateAndIndexEmbeddings")
 	defer span.Finish()
 
-	for i := 0; i < len(chunks); i += p.batchSize {
-		end := i + p.batchSize
+	index, err := p.indexRouter.BindOrganization(ctx, job.OrganizationID)
+	if err != nil {
+		logger.Error(ctx, "Failed to resolve organization index", "error", err)
+		return fmt.Errorf("failed to resolve organization index: %w", err)
+	}
+
+	span.SetTag("index", index.Name)
+
+	return embedAndIndexChunks(ctx, p.vectorDB, p.indexRouter, index, chunks, job.OrganizationID, job.DocumentName, p.batchSize)
+}
+
+// maxIndexReroutes - сколько раз запись чанков повторяется, если алиас организации
+// переключили во время записи
+const maxIndexReroutes = 2
+
+// chunkWriter сохраняет чанки в индекс
+type chunkWriter interface {
+	IndexChunk(ctx context.Context, indexName string, chunk *domain.Chunk, organizationID domain.ID, documentName string) error
+}
+
+// embedAndIndexChunks векторизует чанки батчами и сохраняет их в индекс организации.
+// Если миграция переключила алиас организации, пока чанки записывались в прежний индекс,
+// чанки с теми же ID повторно векторизуются и записываются в новый индекс
+func embedAndIndexChunks(
+	ctx context.Context,
+	vectorDB chunkWriter,
+	indexRouter *IndexRouter,
+	index *EmbeddingIndex,
+	chunks []*domain.Chunk,
+	organizationID domain.ID,
+	documentName string,
+	batchSize int,
+) error {
+	for reroutes := 0; ; reroutes++ {
+		if err := indexChunks(ctx, vectorDB, index, chunks, organizationID, documentName, batchSize); err != nil {
+			return err
+		}
+
+		current, err := indexRouter.BindOrganization(ctx, organizationID)
+		if err != nil {
+			return fmt.Errorf("failed to resolve organization index: %w", err)
+		}
+		if current.Name == index.Name {
+			return nil
+		}
+		if reroutes == maxIndexReroutes {
+			return fmt.Errorf("organization index keeps changing during indexing")
+		}
+
+		logger.Info(ctx, "Organization index changed during indexing, reindexing chunks",
+			"organization_id", organizationID,
+			"from_index", index.Name,
+			"to_index", current.Name,
+		)
+		index = current
+	}
+}
+
+// indexChunks векторизует чанки батчами и сохраняет их в индекс
+func indexChunks(
+	ctx context.Context,
+	vectorDB chunkWriter,
+	index *EmbeddingIndex,
+	chunks []*domain.Chunk,
+	organizationID domain.ID,
+	documentName string,
+	batchSize int,
+) error {
+	for i := 0; i < len(chunks); i += batchSize {
+		end := i + batchSize
 		if end > len(chunks) {
 			end = len(chunks)
 		}
@@ -131,7 +198,7 @@ func (p *DocumentProcessor) generateAndIndexEmbeddings(ctx context.Context, chun
 			texts[j] = chunk.Content
 		}
 
-		embeddings, err := p.embeddingsCli.GenerateEmbeddings(ctx, texts)
+		embeddings, err := index.Provider.GenerateEmbeddings(ctx, texts)
 		if err != nil {
 			logger.Error(ctx, "Failed to generate embeddings", "error", err)
 			return fmt.Errorf("failed to generate embeddings: %w", err)
@@ -142,7 +209,7 @@ func (p *DocumentProcessor) generateAndIndexEmbeddings(ctx context.Context, chun
 				chunk.WithEmbedding(embeddings[j])
 			}
 
-			if err := p.vectorDB.IndexChunk(ctx, chunk, job.OrganizationID, job.DocumentName); err != nil {
+			if err := vectorDB.IndexChunk(ctx, index.Name, chunk, organizationID, documentName); err != nil {
 				logger.Error(ctx, "Failed to index chunk", "error", err, "chunk_id", chunk.ID)
 				return fmt.Errorf("failed to index chunk: %w", err)
 			}
@@ -0,0 +1,218 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"docs-processor/internal/domain"
+	"docs-processor/internal/logger"
+	"docs-processor/internal/vectordb"
+
+	"github.com/opentracing/opentracing-go"
+)
+
+// MigrationResult итог переноса организации в индекс активной модели
+type MigrationResult struct {
+	OrganizationID domain.ID
+	FromIndex      string
+	ToIndex        string
+	ChunksMigrated int
+	Skipped        bool
+}
+
+// migrationIndexes операции с индексами, которые нужны для переноса организации
+type migrationIndexes interface {
+	ResolveOrganizationIndex(ctx context.Context, organizationID domain.ID) (string, bool, error)
+	SwapOrganizationAlias(ctx context.Context, organizationID domain.ID, indexName string) error
+	HasOrganizationChunks(ctx context.Context, indexName string, organizationID domain.ID) (bool, error)
+	ScrollOrganizationChunks(ctx context.Context, indexName string, organizationID domain.ID, batchSize int, fn func([]*vectordb.StoredChunk) error) error
+	IndexChunk(ctx context.Context, indexName string, chunk *domain.Chunk, organizationID domain.ID, documentName string) error
+	DeleteChunks(ctx context.Context, indexName string, chunkIDs []domain.ID) error
+	DeleteOrganizationChunks(ctx context.Context, indexName string, organizationID domain.ID) error
+	ListOrganizations(ctx context.Context, indexName string) ([]domain.ID, error)
+}
+
+var _ migrationIndexes = (*vectordb.OpenSearchClient)(nil)
+
+// EmbeddingMigrator перевекторизует чанки организации активной моделью,
+// складывает их в индекс этой модели и переключает алиас организации
+type EmbeddingMigrator struct {
+	indexRouter *IndexRouter
+	vectorDB    migrationIndexes
+	batchSize   int
+}
+
+func NewEmbeddingMigrator(
+	indexRouter *IndexRouter,
+	vectorDB migrationIndexes,
+	batchSize int,
+) *EmbeddingMigrator {
+	return &EmbeddingMigrator{
+		indexRouter: indexRouter,
+		vectorDB:    vectorDB,
+		batchSize:   batchSize,
+	}
+}
+
+// MigrateOrganization переносит организацию из fromIndex (по умолчанию — текущий
+// индекс алиаса или старый индекс) в индекс активной модели. Если deleteSource, чанки
+// организации удаляются из исходного индекса после переключения алиаса.
+//
+// Запись в организацию во время миграции не блокируется. Чанки, записанные в исходный
+// индекс между копированием и переключением алиаса, дописываются повторным проходом
+// после переключения; чанки, удаленные во время копирования, удаляются из нового индекса.
+// Запись, начатая до переключения и закончившаяся после него, переносится самим
+// писателем (см. embedAndIndexChunks).
+func (m *EmbeddingMigrator) MigrateOrganization(ctx context.Context, organizationID domain.ID, fromIndex string, deleteSource bool) (*MigrationResult, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "service.EmbeddingMigrator.MigrateOrganization")
+	defer span.Finish()
+
+	target := m.indexRouter.Active()
+	result := &MigrationResult{
+		OrganizationID: organizationID,
+		FromIndex:      fromIndex,
+		ToIndex:        target.Name,
+	}
+
+	if result.FromIndex == "" {
+		current, found, err := m.vectorDB.ResolveOrganizationIndex(ctx, organizationID)
+		if err != nil {
+			return nil, err
+		}
+		if found {
+			result.FromIndex = current
+		} else {
+			inLegacy, err := m.vectorDB.HasOrganizationChunks(ctx, m.indexRouter.Legacy().Name, organizationID)
+			if err != nil {
+				return nil, err
+			}
+			if !inLegacy {
+				logger.Info(ctx, "Organization has no index, nothing to migrate", "organization_id", organizationID)
+				result.Skipped = true
+				return result, nil
+			}
+			result.FromIndex = m.indexRouter.Legacy().Name
+		}
+	}
+
+	if result.FromIndex == target.Name {
+		logger.Info(ctx, "Organization already uses active index", "organization_id", organizationID, "index", target.Name)
+		result.Skipped = true
+		return result, nil
+	}
+
+	if err := m.indexRouter.EnsureActiveIndex(ctx); err != nil {
+		return nil, fmt.Errorf("failed to ensure target index: %w", err)
+	}
+
+	logger.Info(ctx, "Migrating organization embeddings",
+		"organization_id", organizationID,
+		"from_index", result.FromIndex,
+		"to_index", target.Name,
+		"model", target.Provider.Model(),
+	)
+
+	// copied - чанки, перенесенные в индекс активной модели
+	copied := make(map[domain.ID]struct{})
+	copyChunks := func(batch []*vectordb.StoredChunk) error {
+		texts := make([]string, len(batch))
+		for i, stored := range batch {
+			texts[i] = stored.Chunk.Content
+		}
+
+		embeddings, err := target.Provider.GenerateEmbeddings(ctx, texts)
+		if err != nil {
+			return fmt.Errorf("failed to generate embeddings: %w", err)
+		}
+
+		for i, stored := range batch {
+			if i < len(embeddings) {
+				stored.Chunk.WithEmbedding(embeddings[i])
+			}
+			if err := m.vectorDB.IndexChunk(ctx, target.Name, stored.Chunk, organizationID, stored.DocumentName); err != nil {
+				return fmt.Errorf("failed to index chunk %s: %w", stored.Chunk.ID, err)
+			}
+			copied[stored.Chunk.ID] = struct{}{}
+		}
+
+		result.ChunksMigrated += len(batch)
+		logger.Info(ctx, "Migration batch indexed", "organization_id", organizationID, "chunks_migrated", result.ChunksMigrated)
+		return nil
+	}
+
+	if err := m.vectorDB.ScrollOrganizationChunks(ctx, result.FromIndex, organizationID, m.batchSize, copyChunks); err != nil {
+		return nil, err
+	}
+
+	if err := m.vectorDB.SwapOrganizationAlias(ctx, organizationID, target.Name); err != nil {
+		return nil, fmt.Errorf("failed to swap organization alias: %w", err)
+	}
+
+	// После переключения новые чанки пишутся в индекс активной модели, и исходный индекс
+	// больше не меняется: переносим то, что в него успели записать после копирования
+	if err := m.copyDelta(ctx, result.FromIndex, organizationID, copied, copyChunks); err != nil {
+		return nil, fmt.Errorf("failed to copy chunks written during migration: %w", err)
+	}
+
+	if deleteSource {
+		if err := m.vectorDB.DeleteOrganizationChunks(ctx, result.FromIndex, organizationID); err != nil {
+			return nil, fmt.Errorf("failed to delete source chunks: %w", err)
+		}
+	}
+
+	logger.Info(ctx, "Organization embeddings migrated",
+		"organization_id", organizationID,
+		"from_index", result.FromIndex,
+		"to_index", target.Name,
+		"chunks_migrated", result.ChunksMigrated,
+	)
+
+	return result, nil
+}
+
+// copyDelta переносит чанки исходного индекса, которых нет среди скопированных, и удаляет
+// из индекса активной модели скопированные чанки, которых в исходном индексе уже нет:
+// их документ удалили, пока шло копирование
+func (m *EmbeddingMigrator) copyDelta(
+	ctx context.Context,
+	fromIndex string,
+	organizationID domain.ID,
+	copied map[domain.ID]struct{},
+	copyChunks func([]*vectordb.StoredChunk) error,
+) error {
+	present := make(map[domain.ID]struct{}, len(copied))
+	err := m.vectorDB.ScrollOrganizationChunks(ctx, fromIndex, organizationID, m.batchSize, func(batch []*vectordb.StoredChunk) error {
+		missing := make([]*vectordb.StoredChunk, 0)
+		for _, stored := range batch {
+			present[stored.Chunk.ID] = struct{}{}
+			if _, ok := copied[stored.Chunk.ID]; !ok {
+				missing = append(missing, stored)
+			}
+		}
+		if len(missing) == 0 {
+			return nil
+		}
+		return copyChunks(missing)
+	})
+	if err != nil {
+		return err
+	}
+
+	stale := make([]domain.ID, 0)
+	for id := range copied {
+		if _, ok := present[id]; !ok {
+			stale = append(stale, id)
+		}
+	}
+	if len(stale) == 0 {
+		return nil
+	}
+
+	logger.Info(ctx, "Removing chunks deleted during migration", "organization_id", organizationID, "chunks", len(stale))
+	return m.vectorDB.DeleteChunks(ctx, m.indexRouter.Active().Name, stale)
+}
+
+// ListOrganizations возвращает организации с чанками в индексе fromIndex
+func (m *EmbeddingMigrator) ListOrganizations(ctx context.Context, fromIndex string) ([]domain.ID, error) {
+	return m.vectorDB.ListOrganizations(ctx, fromIndex)
+}
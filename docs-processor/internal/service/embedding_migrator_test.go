@@ -0,0 +1,143 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"docs-processor/internal/domain"
+	"docs-processor/internal/vectordb"
+)
+
+// assertMigrated проверяет, что в индексе активной модели ровно want чанков, все построены активной моделью
+func assertMigrated(t *testing.T, env *routerEnv, organizationID domain.ID, want []*domain.Chunk) {
+	t.Helper()
+
+	migrated := env.indexes.chunks(env.activeIndex(), organizationID)
+	if len(migrated) != len(want) {
+		t.Errorf("chunks in active index = %d, want %d", len(migrated), len(want))
+	}
+	for _, chunk := range want {
+		stored, ok := migrated[chunk.ID]
+		if !ok {
+			t.Errorf("chunk %s is not migrated", chunk.ID)
+			continue
+		}
+		if len(stored.chunk.Embedding) != 1 || stored.chunk.Embedding[0] != env.active.marker {
+			t.Errorf("chunk %s embedding = %v, want active model", chunk.ID, stored.chunk.Embedding)
+		}
+		if stored.chunk.Content != chunk.Content || stored.documentName != "договор.pdf" {
+			t.Errorf("chunk %s = %q/%q, want %q", chunk.ID, stored.chunk.Content, stored.documentName, chunk.Content)
+		}
+	}
+
+	alias, found, _ := env.indexes.ResolveOrganizationIndex(context.Background(), organizationID)
+	if !found || alias != env.activeIndex() {
+		t.Errorf("alias = %q, want %s", alias, env.activeIndex())
+	}
+}
+
+func TestMigrateOrganization(t *testing.T) {
+	env := newRouterEnv(t)
+	ctx := context.Background()
+	migrator := NewEmbeddingMigrator(env.router, env.indexes, 2)
+
+	organizationID := domain.NewID()
+	other := domain.NewID()
+	env.indexes.aliases[organizationID] = env.previousIndex()
+	chunks := env.indexes.seedChunks(t, env.previousIndex(), organizationID, env.previous, 5)
+	env.indexes.seedChunks(t, env.previousIndex(), other, env.previous, 3)
+
+	result, err := migrator.MigrateOrganization(ctx, organizationID, "", true)
+	if err != nil {
+		t.Fatalf("MigrateOrganization: %v", err)
+	}
+
+	if result.Skipped || result.FromIndex != env.previousIndex() || result.ToIndex != env.activeIndex() || result.ChunksMigrated != 5 {
+		t.Errorf("result = %+v", result)
+	}
+	if env.indexes.ensured[env.activeIndex()] == 0 {
+		t.Error("active index is not ensured before migration")
+	}
+	assertMigrated(t, env, organizationID, chunks)
+
+	if left := env.indexes.chunks(env.previousIndex(), organizationID); len(left) != 0 {
+		t.Errorf("source chunks left = %d, want 0 with deleteSource", len(left))
+	}
+	if left := env.indexes.chunks(env.previousIndex(), other); len(left) != 3 {
+		t.Errorf("other organization chunks = %d, want 3", len(left))
+	}
+
+	// Повторная миграция ничего не делает
+	again, err := migrator.MigrateOrganization(ctx, organizationID, "", false)
+	if err != nil || !again.Skipped {
+		t.Errorf("second migration = %+v, %v, want skipped", again, err)
+	}
+}
+
+func TestMigrateOrganizationFromLegacyIndex(t *testing.T) {
+	env := newRouterEnv(t)
+	ctx := context.Background()
+	migrator := NewEmbeddingMigrator(env.router, env.indexes, 10)
+
+	organizationID := domain.NewID()
+	chunks := env.indexes.seedChunks(t, "documents", organizationID, env.previous, 3)
+
+	result, err := migrator.MigrateOrganization(ctx, organizationID, "", false)
+	if err != nil {
+		t.Fatalf("MigrateOrganization: %v", err)
+	}
+	if result.FromIndex != "documents" {
+		t.Errorf("from index = %s, want legacy index", result.FromIndex)
+	}
+	assertMigrated(t, env, organizationID, chunks)
+
+	// Без чанков в старом индексе и без алиаса переносить нечего
+	empty, err := migrator.MigrateOrganization(ctx, domain.NewID(), "", false)
+	if err != nil || !empty.Skipped {
+		t.Errorf("migration of empty organization = %+v, %v, want skipped", empty, err)
+	}
+}
+
+func TestMigrateOrganizationCopiesWritesDuringMigration(t *testing.T) {
+	env := newRouterEnv(t)
+	ctx := context.Background()
+	migrator := NewEmbeddingMigrator(env.router, env.indexes, 2)
+
+	organizationID := domain.NewID()
+	env.indexes.aliases[organizationID] = env.previousIndex()
+	chunks := env.indexes.seedChunks(t, env.previousIndex(), organizationID, env.previous, 4)
+
+	// Во время копирования воркер, получивший алиас до переключения, дописывает документ
+	// в исходный индекс, а пользователь удаляет уже прочитанный мигратором документ
+	// Нулевой ID сортируется первым: удаленный документ попадает в первую страницу
+	deleted := domain.NewChunk(domain.NewID(), "удаленный", 0)
+	deleted.ID = domain.ID{}
+	env.indexes.put(env.previousIndex(), deleted, organizationID, "договор.pdf")
+
+	var written []*domain.Chunk
+	pages := 0
+	env.indexes.beforeBatch = func(indexName string, batch []*vectordb.StoredChunk) {
+		if indexName != env.previousIndex() {
+			return
+		}
+		pages++
+		if pages != 1 {
+			return
+		}
+		for _, stored := range batch {
+			if stored.Chunk.ID == deleted.ID {
+				env.indexes.deleteDocument(deleted.DocumentID)
+			}
+		}
+		written = env.indexes.seedChunks(t, env.previousIndex(), organizationID, env.previous, 3)
+	}
+
+	if _, err := migrator.MigrateOrganization(ctx, organizationID, "", false); err != nil {
+		t.Fatalf("MigrateOrganization: %v", err)
+	}
+
+	assertMigrated(t, env, organizationID, append(chunks, written...))
+	if _, ok := env.indexes.chunks(env.activeIndex(), organizationID)[deleted.ID]; ok {
+		t.Error("chunk deleted during migration is resurrected in active index")
+	}
+}
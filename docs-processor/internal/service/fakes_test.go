@@ -0,0 +1,293 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"sync"
+	"testing"
+
+	"docs-processor/internal/domain"
+	"docs-processor/internal/logger"
+	"docs-processor/internal/vectordb"
+)
+
+func TestMain(m *testing.M) {
+	logger.Init()
+	os.Exit(m.Run())
+}
+
+// fakeProvider векторизует текст одним числом - маркером модели, чтобы по вектору
+// было видно, какой моделью построен чанк
+type fakeProvider struct {
+	model     string
+	dimension int
+	marker    float32
+	calls     int
+}
+
+func (p *fakeProvider) GenerateEmbeddings(_ context.Context, texts []string) ([][]float32, error) {
+	p.calls++
+	embeddings := make([][]float32, len(texts))
+	for i := range texts {
+		embeddings[i] = []float32{p.marker}
+	}
+	return embeddings, nil
+}
+
+func (p *fakeProvider) GenerateEmbedding(ctx context.Context, text string) ([]float32, error) {
+	embeddings, err := p.GenerateEmbeddings(ctx, []string{text})
+	if err != nil {
+		return nil, err
+	}
+	return embeddings[0], nil
+}
+
+func (p *fakeProvider) Model() string  { return p.model }
+func (p *fakeProvider) Dimension() int { return p.dimension }
+
+type storedChunk struct {
+	chunk          domain.Chunk
+	organizationID domain.ID
+	documentName   string
+}
+
+// memIndexes - in-memory индексы чанков и алиасы организаций с семантикой OpenSearchClient
+type memIndexes struct {
+	mu      sync.Mutex
+	aliases map[domain.ID]string
+	indexes map[string]map[domain.ID]storedChunk
+	ensured map[string]int
+
+	// beforeBatch вызывается перед передачей очередной страницы ScrollOrganizationChunks
+	beforeBatch func(indexName string, batch []*vectordb.StoredChunk)
+	// afterIndex вызывается после записи чанка
+	afterIndex func(indexName string, chunk *domain.Chunk)
+}
+
+var (
+	_ organizationIndexes = (*memIndexes)(nil)
+	_ migrationIndexes    = (*memIndexes)(nil)
+)
+
+func newMemIndexes() *memIndexes {
+	return &memIndexes{
+		aliases: make(map[domain.ID]string),
+		indexes: make(map[string]map[domain.ID]storedChunk),
+		ensured: make(map[string]int),
+	}
+}
+
+func (m *memIndexes) IndexNameFor(model string, dimension int) string {
+	return vectordb.IndexName("documents", model, dimension)
+}
+
+func (m *memIndexes) LegacyIndexName() string {
+	return "documents"
+}
+
+func (m *memIndexes) EnsureIndex(_ context.Context, indexName string, _ int) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.ensured[indexName]++
+	return nil
+}
+
+func (m *memIndexes) ResolveOrganizationIndex(_ context.Context, organizationID domain.ID) (string, bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	name, ok := m.aliases[organizationID]
+	return name, ok, nil
+}
+
+func (m *memIndexes) SwapOrganizationAlias(_ context.Context, organizationID domain.ID, indexName string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.aliases[organizationID] = indexName
+	return nil
+}
+
+func (m *memIndexes) HasOrganizationChunks(_ context.Context, indexName string, organizationID domain.ID) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, stored := range m.indexes[indexName] {
+		if stored.organizationID == organizationID {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (m *memIndexes) ScrollOrganizationChunks(_ context.Context, indexName string, organizationID domain.ID, batchSize int, fn func([]*vectordb.StoredChunk) error) error {
+	// Как search_after: каждая страница читается заново после последнего ID предыдущей
+	var after string
+	for {
+		batch := m.page(indexName, organizationID, after, batchSize)
+		if len(batch) == 0 {
+			return nil
+		}
+		if m.beforeBatch != nil {
+			m.beforeBatch(indexName, batch)
+		}
+		if err := fn(batch); err != nil {
+			return err
+		}
+		after = batch[len(batch)-1].Chunk.ID.String()
+	}
+}
+
+func (m *memIndexes) page(indexName string, organizationID domain.ID, after string, size int) []*vectordb.StoredChunk {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var ids []string
+	for id, stored := range m.indexes[indexName] {
+		if stored.organizationID == organizationID && id.String() > after {
+			ids = append(ids, id.String())
+		}
+	}
+	sort.Strings(ids)
+	if len(ids) > size {
+		ids = ids[:size]
+	}
+
+	batch := make([]*vectordb.StoredChunk, 0, len(ids))
+	for _, id := range ids {
+		chunkID, _ := domain.ParseID(id)
+		stored := m.indexes[indexName][chunkID]
+		chunk := stored.chunk
+		chunk.Embedding = nil
+		batch = append(batch, &vectordb.StoredChunk{
+			Chunk:          &chunk,
+			OrganizationID: stored.organizationID,
+			DocumentName:   stored.documentName,
+		})
+	}
+	return batch
+}
+
+func (m *memIndexes) IndexChunk(_ context.Context, indexName string, chunk *domain.Chunk, organizationID domain.ID, documentName string) error {
+	m.put(indexName, chunk, organizationID, documentName)
+	if m.afterIndex != nil {
+		m.afterIndex(indexName, chunk)
+	}
+	return nil
+}
+
+func (m *memIndexes) put(indexName string, chunk *domain.Chunk, organizationID domain.ID, documentName string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.indexes[indexName] == nil {
+		m.indexes[indexName] = make(map[domain.ID]storedChunk)
+	}
+	m.indexes[indexName][chunk.ID] = storedChunk{chunk: *chunk, organizationID: organizationID, documentName: documentName}
+}
+
+func (m *memIndexes) DeleteChunks(_ context.Context, indexName string, chunkIDs []domain.ID) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, id := range chunkIDs {
+		delete(m.indexes[indexName], id)
+	}
+	return nil
+}
+
+// deleteDocument удаляет чанки документа из всех индексов, как DeleteDocumentChunks
+func (m *memIndexes) deleteDocument(documentID domain.ID) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, chunks := range m.indexes {
+		for id, stored := range chunks {
+			if stored.chunk.DocumentID == documentID {
+				delete(chunks, id)
+			}
+		}
+	}
+}
+
+func (m *memIndexes) DeleteOrganizationChunks(_ context.Context, indexName string, organizationID domain.ID) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for id, stored := range m.indexes[indexName] {
+		if stored.organizationID == organizationID {
+			delete(m.indexes[indexName], id)
+		}
+	}
+	return nil
+}
+
+func (m *memIndexes) ListOrganizations(_ context.Context, indexName string) ([]domain.ID, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	seen := make(map[domain.ID]struct{})
+	var ids []domain.ID
+	for _, stored := range m.indexes[indexName] {
+		if _, ok := seen[stored.organizationID]; !ok {
+			seen[stored.organizationID] = struct{}{}
+			ids = append(ids, stored.organizationID)
+		}
+	}
+	return ids, nil
+}
+
+// chunks возвращает чанки организации в индексе по ID
+func (m *memIndexes) chunks(indexName string, organizationID domain.ID) map[domain.ID]storedChunk {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	result := make(map[domain.ID]storedChunk)
+	for id, stored := range m.indexes[indexName] {
+		if stored.organizationID == organizationID {
+			result[id] = stored
+		}
+	}
+	return result
+}
+
+// seedChunks кладет в индекс n чанков одного документа организации, построенных провайдером p
+func (m *memIndexes) seedChunks(t *testing.T, indexName string, organizationID domain.ID, p *fakeProvider, n int) []*domain.Chunk {
+	t.Helper()
+
+	documentID := domain.NewID()
+	chunks := make([]*domain.Chunk, 0, n)
+	for i := 0; i < n; i++ {
+		chunk := domain.NewChunk(documentID, fmt.Sprintf("чанк %d", i), i)
+		chunk.WithEmbedding([]float32{p.marker})
+		m.put(indexName, chunk, organizationID, "договор.pdf")
+		chunks = append(chunks, chunk)
+	}
+	return chunks
+}
+
+type routerEnv struct {
+	indexes  *memIndexes
+	router   *IndexRouter
+	active   *fakeProvider
+	previous *fakeProvider
+}
+
+// newRouterEnv создает маршрутизатор с активной моделью и одной прошлой, которой построен старый индекс
+func newRouterEnv(t *testing.T) *routerEnv {
+	t.Helper()
+
+	env := &routerEnv{
+		indexes:  newMemIndexes(),
+		active:   &fakeProvider{model: "bge-m3", dimension: 1024, marker: 2},
+		previous: &fakeProvider{model: "text-embedding-3-small", dimension: 1024, marker: 1},
+	}
+
+	router, err := NewIndexRouter(env.indexes, env.previous.Model(), env.active, env.previous)
+	if err != nil {
+		t.Fatalf("NewIndexRouter: %v", err)
+	}
+	env.router = router
+	return env
+}
+
+func (e *routerEnv) activeIndex() string {
+	return e.indexes.IndexNameFor(e.active.Model(), e.active.Dimension())
+}
+
+func (e *routerEnv) previousIndex() string {
+	return e.indexes.IndexNameFor(e.previous.Model(), e.previous.Dimension())
+}
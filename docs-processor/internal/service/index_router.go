@@ -0,0 +1,157 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"docs-processor/internal/domain"
+	"docs-processor/internal/embeddings"
+	"docs-processor/internal/logger"
+	"docs-processor/internal/vectordb"
+
+	"github.com/opentracing/opentracing-go"
+)
+
+// EmbeddingIndex индекс чанков и провайдер, которым построены его векторы
+type EmbeddingIndex struct {
+	Name     string
+	Provider embeddings.Provider
+}
+
+// organizationIndexes индексы чанков и алиасы организаций
+type organizationIndexes interface {
+	IndexNameFor(model string, dimension int) string
+	LegacyIndexName() string
+	EnsureIndex(ctx context.Context, indexName string, dimension int) error
+	ResolveOrganizationIndex(ctx context.Context, organizationID domain.ID) (string, bool, error)
+	SwapOrganizationAlias(ctx context.Context, organizationID domain.ID, indexName string) error
+	HasOrganizationChunks(ctx context.Context, indexName string, organizationID domain.ID) (bool, error)
+}
+
+var _ organizationIndexes = (*vectordb.OpenSearchClient)(nil)
+
+// IndexRouter определяет, в каком индексе лежат чанки организации и какой
+// моделью нужно векторизовать запросы к нему. Новые организации попадают
+// в индекс активной модели; существующие остаются на своем индексе до миграции.
+// Организации без алиаса, проиндексированные до появления индексов по моделям,
+// читаются из старого индекса и при следующей записи привязываются к нему.
+type IndexRouter struct {
+	vectorDB organizationIndexes
+	active   *EmbeddingIndex
+	legacy   *EmbeddingIndex
+	indexes  map[string]*EmbeddingIndex
+}
+
+// NewIndexRouter создает маршрутизатор индексов. legacyModel - модель, которой построен
+// старый индекс; она должна быть активной или одной из прошлых моделей
+func NewIndexRouter(
+	vectorDB organizationIndexes,
+	legacyModel string,
+	active embeddings.Provider,
+	previous ...embeddings.Provider,
+) (*IndexRouter, error) {
+	r := &IndexRouter{
+		vectorDB: vectorDB,
+		indexes:  make(map[string]*EmbeddingIndex),
+	}
+
+	for _, p := range previous {
+		name := vectorDB.IndexNameFor(p.Model(), p.Dimension())
+		r.indexes[name] = &EmbeddingIndex{Name: name, Provider: p}
+	}
+
+	name := vectorDB.IndexNameFor(active.Model(), active.Dimension())
+	r.active = &EmbeddingIndex{Name: name, Provider: active}
+	r.indexes[name] = r.active
+
+	for _, p := range append([]embeddings.Provider{active}, previous...) {
+		if p.Model() == legacyModel {
+			r.legacy = &EmbeddingIndex{Name: vectorDB.LegacyIndexName(), Provider: p}
+			break
+		}
+	}
+	if r.legacy == nil {
+		return nil, fmt.Errorf("legacy embeddings model %s is neither active nor previous", legacyModel)
+	}
+	r.indexes[r.legacy.Name] = r.legacy
+
+	return r, nil
+}
+
+// EnsureActiveIndex создает индекс активной модели, если его еще нет
+func (r *IndexRouter) EnsureActiveIndex(ctx context.Context) error {
+	return r.vectorDB.EnsureIndex(ctx, r.active.Name, r.active.Provider.Dimension())
+}
+
+func (r *IndexRouter) Active() *EmbeddingIndex {
+	return r.active
+}
+
+// Legacy возвращает старый индекс и модель, которой он построен
+func (r *IndexRouter) Legacy() *EmbeddingIndex {
+	return r.legacy
+}
+
+// ForIndex возвращает провайдер для индекса, построенного одной из сконфигурированных моделей
+func (r *IndexRouter) ForIndex(name string) (*EmbeddingIndex, error) {
+	idx, ok := r.indexes[name]
+	if !ok {
+		return nil, fmt.Errorf("no embeddings provider configured for index %s", name)
+	}
+	return idx, nil
+}
+
+// ForOrganization возвращает индекс для чтения чанков организации
+func (r *IndexRouter) ForOrganization(ctx context.Context, organizationID domain.ID) (*EmbeddingIndex, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "service.IndexRouter.ForOrganization")
+	defer span.Finish()
+
+	name, found, err := r.vectorDB.ResolveOrganizationIndex(ctx, organizationID)
+	if err != nil {
+		return nil, err
+	}
+	if !found {
+		// Без алиаса чанки организации, если они есть, лежат в старом индексе;
+		// отсутствующий старый индекс при поиске считается пустым
+		return r.legacy, nil
+	}
+
+	return r.ForIndex(name)
+}
+
+// BindOrganization возвращает индекс для записи чанков организации и
+// привязывает к активному индексу организации, у которых еще нет алиаса
+func (r *IndexRouter) BindOrganization(ctx context.Context, organizationID domain.ID) (*EmbeddingIndex, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "service.IndexRouter.BindOrganization")
+	defer span.Finish()
+
+	name, found, err := r.vectorDB.ResolveOrganizationIndex(ctx, organizationID)
+	if err != nil {
+		return nil, err
+	}
+	if found {
+		return r.ForIndex(name)
+	}
+
+	// Организация, проиндексированная до появления индексов по моделям, остается
+	// в старом индексе до миграции, чтобы новые чанки искались вместе со старыми
+	target := r.active
+	inLegacy, err := r.vectorDB.HasOrganizationChunks(ctx, r.legacy.Name, organizationID)
+	if err != nil {
+		return nil, err
+	}
+	if inLegacy {
+		target = r.legacy
+	}
+
+	if err := r.vectorDB.SwapOrganizationAlias(ctx, organizationID, target.Name); err != nil {
+		return nil, fmt.Errorf("failed to bind organization to index: %w", err)
+	}
+
+	logger.Info(ctx, "Organization bound to embeddings index",
+		"organization_id", organizationID,
+		"index", target.Name,
+	)
+
+	return target, nil
+}
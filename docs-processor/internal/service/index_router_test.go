@@ -0,0 +1,182 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"docs-processor/internal/domain"
+)
+
+func TestNewIndexRouterLegacyModel(t *testing.T) {
+	indexes := newMemIndexes()
+	active := &fakeProvider{model: "bge-m3", dimension: 1024, marker: 2}
+	previous := &fakeProvider{model: "text-embedding-3-small", dimension: 1024, marker: 1}
+
+	tests := []struct {
+		name        string
+		legacyModel string
+		want        *fakeProvider
+		wantErr     bool
+	}{
+		{name: "активная модель", legacyModel: "bge-m3", want: active},
+		{name: "прошлая модель", legacyModel: "text-embedding-3-small", want: previous},
+		{name: "неизвестная модель", legacyModel: "ada-002", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router, err := NewIndexRouter(indexes, tt.legacyModel, active, previous)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("NewIndexRouter succeeded, want error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("NewIndexRouter: %v", err)
+			}
+			if router.Legacy().Name != "documents" || router.Legacy().Provider != tt.want {
+				t.Errorf("legacy = %s/%s, want documents/%s", router.Legacy().Name, router.Legacy().Provider.Model(), tt.want.Model())
+			}
+			if idx, err := router.ForIndex("documents"); err != nil || idx != router.Legacy() {
+				t.Errorf("ForIndex(documents) = %v, %v, want legacy index", idx, err)
+			}
+		})
+	}
+}
+
+func TestIndexRouterForOrganization(t *testing.T) {
+	env := newRouterEnv(t)
+	ctx := context.Background()
+
+	withoutAlias := domain.NewID()
+	onPrevious := domain.NewID()
+	onActive := domain.NewID()
+	onUnknown := domain.NewID()
+	env.indexes.aliases[onPrevious] = env.previousIndex()
+	env.indexes.aliases[onActive] = env.activeIndex()
+	env.indexes.aliases[onUnknown] = "documents_ada-002_1536"
+
+	tests := []struct {
+		name           string
+		organizationID domain.ID
+		wantIndex      string
+		wantProvider   *fakeProvider
+		wantErr        bool
+	}{
+		{name: "без алиаса - старый индекс", organizationID: withoutAlias, wantIndex: "documents", wantProvider: env.previous},
+		{name: "индекс прошлой модели", organizationID: onPrevious, wantIndex: env.previousIndex(), wantProvider: env.previous},
+		{name: "индекс активной модели", organizationID: onActive, wantIndex: env.activeIndex(), wantProvider: env.active},
+		{name: "индекс неизвестной модели", organizationID: onUnknown, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			idx, err := env.router.ForOrganization(ctx, tt.organizationID)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("ForOrganization = %s, want error", idx.Name)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ForOrganization: %v", err)
+			}
+			if idx.Name != tt.wantIndex || idx.Provider != tt.wantProvider {
+				t.Errorf("ForOrganization = %s/%s, want %s/%s", idx.Name, idx.Provider.Model(), tt.wantIndex, tt.wantProvider.Model())
+			}
+		})
+	}
+
+	// Чтение не привязывает организацию к индексу
+	if _, found, _ := env.indexes.ResolveOrganizationIndex(ctx, withoutAlias); found {
+		t.Error("ForOrganization created an alias")
+	}
+}
+
+func TestIndexRouterBindOrganization(t *testing.T) {
+	env := newRouterEnv(t)
+	ctx := context.Background()
+
+	legacyOrg := domain.NewID()
+	env.indexes.seedChunks(t, "documents", legacyOrg, env.previous, 2)
+	newOrg := domain.NewID()
+	boundOrg := domain.NewID()
+	env.indexes.aliases[boundOrg] = env.previousIndex()
+
+	tests := []struct {
+		name           string
+		organizationID domain.ID
+		wantIndex      string
+	}{
+		{name: "чанки в старом индексе", organizationID: legacyOrg, wantIndex: "documents"},
+		{name: "новая организация", organizationID: newOrg, wantIndex: env.activeIndex()},
+		{name: "уже привязана", organizationID: boundOrg, wantIndex: env.previousIndex()},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			idx, err := env.router.BindOrganization(ctx, tt.organizationID)
+			if err != nil {
+				t.Fatalf("BindOrganization: %v", err)
+			}
+			if idx.Name != tt.wantIndex {
+				t.Errorf("BindOrganization = %s, want %s", idx.Name, tt.wantIndex)
+			}
+
+			alias, found, _ := env.indexes.ResolveOrganizationIndex(ctx, tt.organizationID)
+			if !found || alias != tt.wantIndex {
+				t.Errorf("alias = %q (found %v), want %s", alias, found, tt.wantIndex)
+			}
+
+			// После привязки чтение идет из того же индекса
+			read, err := env.router.ForOrganization(ctx, tt.organizationID)
+			if err != nil || read.Name != tt.wantIndex {
+				t.Errorf("ForOrganization after bind = %v, %v, want %s", read, err, tt.wantIndex)
+			}
+		})
+	}
+}
+
+func TestEmbedAndIndexChunksFollowsAliasSwap(t *testing.T) {
+	env := newRouterEnv(t)
+	ctx := context.Background()
+	organizationID := domain.NewID()
+	env.indexes.aliases[organizationID] = env.previousIndex()
+
+	documentID := domain.NewID()
+	chunks := []*domain.Chunk{
+		domain.NewChunk(documentID, "первый", 0),
+		domain.NewChunk(documentID, "второй", 1),
+	}
+
+	// Миграция переключает алиас, пока документ записывается в индекс прошлой модели
+	env.indexes.afterIndex = func(indexName string, _ *domain.Chunk) {
+		if indexName == env.previousIndex() {
+			env.indexes.aliases[organizationID] = env.activeIndex()
+		}
+	}
+
+	index, err := env.router.BindOrganization(ctx, organizationID)
+	if err != nil {
+		t.Fatalf("BindOrganization: %v", err)
+	}
+	if err := embedAndIndexChunks(ctx, env.indexes, env.router, index, chunks, organizationID, "договор.pdf", 1); err != nil {
+		t.Fatalf("embedAndIndexChunks: %v", err)
+	}
+
+	moved := env.indexes.chunks(env.activeIndex(), organizationID)
+	if len(moved) != len(chunks) {
+		t.Fatalf("chunks in active index = %d, want %d", len(moved), len(chunks))
+	}
+	for _, chunk := range chunks {
+		stored, ok := moved[chunk.ID]
+		if !ok {
+			t.Errorf("chunk %s is missing in active index", chunk.ID)
+			continue
+		}
+		if stored.chunk.Embedding[0] != env.active.marker {
+			t.Errorf("chunk %s embedded by marker %v, want active model", chunk.ID, stored.chunk.Embedding[0])
+		}
+	}
+}
@@ -3,6 +3,7 @@ package service
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"docs-processor/internal/domain"
 	"docs-processor/internal/logger"
@@ -15,21 +16,31 @@ type coreClient interface {
 	UpdateDocumentStatus(ctx context.Context, documentID string, status pb.DocumentStatus, errorMessage string) error
 }
 
+type eventPublisher interface {
+	PublishMessage(ctx context.Context, message interface{}) error
+}
+
 type JobProcessor struct {
-	documentProcessor *DocumentProcessor
-	templateProcessor *TemplateProcessor
-	coreClient        coreClient
+	documentProcessor  *DocumentProcessor
+	templateProcessor  *TemplateProcessor
+	organizationPurger *OrganizationPurger
+	coreClient         coreClient
+	events             eventPublisher
 }
 
 func NewJobProcessor(
 	documentProcessor *DocumentProcessor,
 	templateProcessor *TemplateProcessor,
+	organizationPurger *OrganizationPurger,
 	coreClient coreClient,
+	events eventPublisher,
 ) *JobProcessor {
 	return &JobProcessor{
-		documentProcessor: documentProcessor,
-		templateProcessor: templateProcessor,
-		coreClient:        coreClient,
+		documentProcessor:  documentProcessor,
+		templateProcessor:  templateProcessor,
+		organizationPurger: organizationPurger,
+		coreClient:         coreClient,
+		events:             events,
 	}
 }
 
@@ -52,6 +63,8 @@ func (p *JobProcessor) ProcessJob(ctx context.Context, job *domain.ProcessingJob
 		err = p.templateProcessor.IndexTemplate(ctx, job)
 	case domain.JobTypeTemplateDelete:
 		err = p.templateProcessor.DeleteTemplate(ctx, job)
+	case domain.JobTypeOrganizationPurge:
+		err = p.organizationPurger.PurgeOrganization(ctx, job)
 	default:
 		err = fmt.Errorf("unknown job type: %s", job.JobType)
 	}
@@ -62,6 +75,10 @@ func (p *JobProcessor) ProcessJob(ctx context.Context, job *domain.ProcessingJob
 			"error", err,
 			"retry_count", job.RetryCount,
 		)
+		// Уведомляем только о последней попытке, промежуточные ошибки пользователю не интересны
+		if job.JobType == domain.JobTypeDocument && !job.CanRetry() {
+			p.publishDocumentEvent(ctx, job, domain.NotificationEventDocumentFailed, err)
+		}
 		return err
 	}
 
@@ -69,5 +86,42 @@ func (p *JobProcessor) ProcessJob(ctx context.Context, job *domain.ProcessingJob
 		"job_type", string(job.JobType),
 	)
 
+	if job.JobType == domain.JobTypeDocument {
+		p.publishDocumentEvent(ctx, job, domain.NotificationEventDocumentIndexed, nil)
+	}
+
 	return nil
 }
+
+// publishDocumentEvent сообщает сервису уведомлений об итоге обработки документа.
+// Ошибка публикации не влияет на результат задачи.
+func (p *JobProcessor) publishDocumentEvent(ctx context.Context, job *domain.ProcessingJob, eventType domain.NotificationEventType, jobErr error) {
+	if p.events == nil {
+		return
+	}
+
+	event := domain.NotificationEvent{
+		EventID:        fmt.Sprintf("%s:%s", eventType, job.DocumentID),
+		Type:           eventType,
+		Source:         "docs-processor",
+		OrganizationID: &job.OrganizationID,
+		OccurredAt:     time.Now(),
+	}
+
+	switch eventType {
+	case domain.NotificationEventDocumentIndexed:
+		event.Title = "Документ обработан"
+		event.Text = fmt.Sprintf("Документ «%s» проиндексирован и доступен ассистенту.", job.DocumentName)
+	case domain.NotificationEventDocumentFailed:
+		event.Title = "Не удалось обработать документ"
+		event.Text = fmt.Sprintf("Документ «%s» не удалось обработать: %v", job.DocumentName, jobErr)
+	}
+
+	if err := p.events.PublishMessage(ctx, event); err != nil {
+		logger.Error(ctx, "Failed to publish notification event",
+			"event_type", string(eventType),
+			"document_id", job.DocumentID,
+			"error", err,
+		)
+	}
+}
@@ -0,0 +1,85 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"docs-processor/internal/domain"
+	"docs-processor/internal/logger"
+
+	"github.com/opentracing/opentracing-go"
+)
+
+type organizationIndex interface {
+	PurgeOrganization(ctx context.Context, organizationID domain.ID) (int, error)
+}
+
+// OrganizationPurger удаляет чанки организации из поискового индекса по заявке core-service
+// и сообщает ему результат
+type OrganizationPurger struct {
+	vectorDB organizationIndex
+	results  eventPublisher
+}
+
+func NewOrganizationPurger(vectorDB organizationIndex, results eventPublisher) *OrganizationPurger {
+	return &OrganizationPurger{
+		vectorDB: vectorDB,
+		results:  results,
+	}
+}
+
+// PurgeOrganization удаляет чанки организации. Об ошибке core-service узнает только после
+// последней попытки, иначе заявка была бы помечена неудачной до повтора
+func (p *OrganizationPurger) PurgeOrganization(ctx context.Context, job *domain.ProcessingJob) error {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "service.OrganizationPurger.PurgeOrganization")
+	defer span.Finish()
+
+	if job.ErasureID == nil || job.OrganizationID == (domain.ID{}) {
+		return fmt.Errorf("invalid organization purge job: missing required fields")
+	}
+
+	logger.Info(ctx, "Purging organization chunks",
+		"organization_id", job.OrganizationID,
+		"erasure_id", *job.ErasureID,
+	)
+
+	deleted, err := p.vectorDB.PurgeOrganization(ctx, job.OrganizationID)
+	if err != nil {
+		if !job.CanRetry() {
+			p.publishResult(ctx, job, domain.ErasureStepFailed, deleted, err)
+		}
+		return err
+	}
+
+	logger.Info(ctx, "Organization chunks purged",
+		"organization_id", job.OrganizationID,
+		"deleted", deleted,
+	)
+
+	p.publishResult(ctx, job, domain.ErasureStepCompleted, deleted, nil)
+	return nil
+}
+
+// publishResult отправляет core-service итог шага. Если ответ потерян, core-service
+// повторит заявку; повторное удаление безопасно
+func (p *OrganizationPurger) publishResult(ctx context.Context, job *domain.ProcessingJob, status domain.ErasureStepStatus, deleted int, jobErr error) {
+	result := domain.ErasureStepResult{
+		ErasureID:    *job.ErasureID,
+		Step:         domain.ErasureStepSearchIndex,
+		Source:       "docs-processor",
+		Status:       status,
+		ItemsDeleted: deleted,
+		FinishedAt:   time.Now(),
+	}
+	if jobErr != nil {
+		result.Error = jobErr.Error()
+	}
+
+	if err := p.results.PublishMessage(ctx, result); err != nil {
+		logger.Error(ctx, "Failed to publish erasure result",
+			"erasure_id", *job.ErasureID,
+			"error", err,
+		)
+	}
+}
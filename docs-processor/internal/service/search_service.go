@@ -5,37 +5,43 @@ import (
 	"fmt"
 
 	"docs-processor/internal/domain"
-	"docs-processor/internal/embeddings"
 	"docs-processor/internal/vectordb"
 
 	"github.com/opentracing/opentracing-go"
 )
 
 type SearchService struct {
-	embeddingsCli *embeddings.Client
-	vectorDB      *vectordb.OpenSearchClient
+	indexRouter *IndexRouter
+	vectorDB    *vectordb.OpenSearchClient
 }
 
 func NewSearchService(
-	embeddingsCli *embeddings.Client,
+	indexRouter *IndexRouter,
 	vectorDB *vectordb.OpenSearchClient,
 ) *SearchService {
 	return &SearchService{
-		embeddingsCli: embeddingsCli,
-		vectorDB:      vectorDB,
+		indexRouter: indexRouter,
+		vectorDB:    vectorDB,
 	}
 }
 
-func (s *SearchService) SearchChunks(ctx context.Context, organizationID domain.ID, query string, limit int, minScore float32) ([]*domain.SearchResult, error) {
+// SearchChunks ищет по документам организации, а при заданном chatID - по вложениям чата
+func (s *SearchService) SearchChunks(ctx context.Context, organizationID domain.ID, chatID *domain.ID, query string, limit int, minScore float32) ([]*domain.SearchResult, error) {
 	span, ctx := opentracing.StartSpanFromContext(ctx, "service.SearchService.SearchChunks")
 	defer span.Finish()
 
-	queryEmbedding, err := s.embeddingsCli.GenerateEmbedding(ctx, query)
+	// Запрос векторизуется той же моделью, которой построен индекс организации
+	index, err := s.indexRouter.ForOrganization(ctx, organizationID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve organization index: %w", err)
+	}
+
+	queryEmbedding, err := index.Provider.GenerateEmbedding(ctx, query)
 	if err != nil {
 		return nil, fmt.Errorf("failed to generate query embedding: %w", err)
 	}
 
-	results, err := s.vectorDB.SearchChunks(ctx, organizationID, queryEmbedding, limit, minScore)
+	results, err := s.vectorDB.SearchChunks(ctx, index.Name, organizationID, chatID, queryEmbedding, limit, minScore)
 	if err != nil {
 		return nil, fmt.Errorf("failed to search chunks: %w", err)
 	}
@@ -3,6 +3,7 @@ package service
 import (
 	"context"
 	"fmt"
+	"sync/atomic"
 	"time"
 
 	"docs-processor/internal/domain"
@@ -15,17 +16,24 @@ import (
 )
 
 type TemplateProcessor struct {
-	embeddingsClient *embeddings.Client
+	embeddingsClient embeddings.Provider
 	templatesDB      *vectordb.TemplatesClient
+	// legacy - индекс шаблонов без суффикса модели; поиск идет по нему, пока шаблоны
+	// не перенесены в индекс активной модели
+	legacy   *EmbeddingIndex
+	migrated atomic.Bool
 }
 
+// NewTemplateProcessor создает обработчик шаблонов; legacy нужен только для поиска и может быть nil
 func NewTemplateProcessor(
-	embeddingsClient *embeddings.Client,
+	embeddingsClient embeddings.Provider,
 	templatesDB *vectordb.TemplatesClient,
+	legacy *EmbeddingIndex,
 ) *TemplateProcessor {
 	return &TemplateProcessor{
 		embeddingsClient: embeddingsClient,
 		templatesDB:      templatesDB,
+		legacy:           legacy,
 	}
 }
 
@@ -103,6 +111,24 @@ func (p *TemplateProcessor) SearchTemplates(ctx context.Context, query string, l
 	span, ctx := opentracing.StartSpanFromContext(ctx, "service.TemplateProcessor.SearchTemplates")
 	defer span.Finish()
 
+	legacy, err := p.useLegacyIndex(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if legacy {
+		embedding, err := p.legacy.Provider.GenerateEmbedding(ctx, query)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get query embedding: %w", err)
+		}
+
+		results, err := p.templatesDB.SearchTemplatesIn(ctx, p.legacy.Name, embedding, limit)
+		if err != nil {
+			return nil, fmt.Errorf("failed to search legacy templates: %w", err)
+		}
+
+		return results, nil
+	}
+
 	embedding, err := p.embeddingsClient.GenerateEmbedding(ctx, query)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get query embedding: %w", err)
@@ -115,3 +141,65 @@ func (p *TemplateProcessor) SearchTemplates(ctx context.Context, query string, l
 
 	return results, nil
 }
+
+// useLegacyIndex сообщает, что шаблоны еще не перенесены в индекс активной модели
+// и искать нужно в старом индексе. Индекс активной модели, в котором появились
+// шаблоны, считается перенесенным до перезапуска
+func (p *TemplateProcessor) useLegacyIndex(ctx context.Context) (bool, error) {
+	if p.legacy == nil || p.migrated.Load() {
+		return false, nil
+	}
+
+	count, err := p.templatesDB.CountTemplates(ctx)
+	if err != nil {
+		return false, fmt.Errorf("failed to count templates: %w", err)
+	}
+	if count > 0 {
+		p.migrated.Store(true)
+		return false, nil
+	}
+
+	return true, nil
+}
+
+// MigrateTemplates перевекторизует шаблоны из индекса прошлой модели
+// и индексирует их в текущий индекс шаблонов
+func (p *TemplateProcessor) MigrateTemplates(ctx context.Context, fromIndex string, batchSize int) (int, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "service.TemplateProcessor.MigrateTemplates")
+	defer span.Finish()
+
+	migrated := 0
+	err := p.templatesDB.ScrollTemplates(ctx, fromIndex, batchSize, func(templates []*domain.Template) error {
+		texts := make([]string, len(templates))
+		for i, t := range templates {
+			texts[i] = t.Name
+			if t.Description != "" {
+				texts[i] += " " + t.Description
+			}
+		}
+
+		embeddings, err := p.embeddingsClient.GenerateEmbeddings(ctx, texts)
+		if err != nil {
+			return fmt.Errorf("failed to get embeddings: %w", err)
+		}
+
+		for i, t := range templates {
+			if i >= len(embeddings) {
+				break
+			}
+			if err := p.templatesDB.IndexTemplate(ctx, t, embeddings[i]); err != nil {
+				return fmt.Errorf("failed to index template %s: %w", t.ID, err)
+			}
+		}
+
+		migrated += len(templates)
+		return nil
+	})
+	if err != nil {
+		return migrated, err
+	}
+
+	logger.Info(ctx, "Templates migrated", "from_index", fromIndex, "templates_migrated", migrated)
+
+	return migrated, nil
+}
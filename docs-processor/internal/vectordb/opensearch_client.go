@@ -8,6 +8,7 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"regexp"
 	"strings"
 
 	"docs-processor/internal/domain"
@@ -17,12 +18,22 @@ import (
 	"github.com/opentracing/opentracing-go"
 )
 
+var indexNameSanitizer = regexp.MustCompile(`[^a-z0-9_-]+`)
+
+// IndexName возвращает имя индекса для модели embeddings: у каждой пары
+// модель/размерность свой индекс со своим маппингом knn_vector
+func IndexName(base, model string, dimension int) string {
+	m := indexNameSanitizer.ReplaceAllString(strings.ToLower(model), "-")
+	m = strings.Trim(m, "-")
+	return fmt.Sprintf("%s_%s_%d", base, m, dimension)
+}
+
 type OpenSearchClient struct {
-	client    *opensearch.Client
-	indexName string
+	client   *opensearch.Client
+	baseName string
 }
 
-func NewOpenSearchClient(addresses []string, username, password, indexName string) (*OpenSearchClient, error) {
+func NewOpenSearchClient(addresses []string, username, password, baseName string) (*OpenSearchClient, error) {
 	cfg := opensearch.Config{
 		Addresses: addresses,
 		Username:  username,
@@ -37,21 +48,38 @@ func NewOpenSearchClient(addresses []string, username, password, indexName strin
 		return nil, err
 	}
 
-	osc := &OpenSearchClient{
-		client:    client,
-		indexName: indexName,
-	}
+	return &OpenSearchClient{
+		client:   client,
+		baseName: baseName,
+	}, nil
+}
 
-	if err := osc.ensureIndex(context.Background()); err != nil {
-		return nil, err
-	}
+// IndexNameFor возвращает имя индекса чанков для модели
+func (c *OpenSearchClient) IndexNameFor(model string, dimension int) string {
+	return IndexName(c.baseName, model, dimension)
+}
+
+// LegacyIndexName возвращает индекс без суффикса модели, в котором лежат чанки,
+// проиндексированные до появления индексов по моделям
+func (c *OpenSearchClient) LegacyIndexName() string {
+	return c.baseName
+}
 
-	return osc, nil
+// chunkIndices возвращает индексы всех моделей и старый индекс
+func (c *OpenSearchClient) chunkIndices() []string {
+	return []string{c.LegacyIndexName(), c.baseName + "_*"}
 }
 
-func (c *OpenSearchClient) ensureIndex(ctx context.Context) error {
+// OrganizationAlias возвращает имя алиаса, указывающего на индекс,
+// в котором сейчас лежат чанки организации
+func (c *OpenSearchClient) OrganizationAlias(organizationID domain.ID) string {
+	return fmt.Sprintf("%s_org_%s", c.baseName, organizationID.String())
+}
+
+// EnsureIndex создает индекс с маппингом под заданную размерность, если его нет
+func (c *OpenSearchClient) EnsureIndex(ctx context.Context, indexName string, dimension int) error {
 	req := opensearchapi.IndicesExistsRequest{
-		Index: []string{c.indexName},
+		Index: []string{indexName},
 	}
 
 	res, err := req.Do(ctx, c.client)
@@ -61,13 +89,45 @@ func (c *OpenSearchClient) ensureIndex(ctx context.Context) error {
 	defer res.Body.Close()
 
 	if res.StatusCode == 404 {
-		return c.createIndex(ctx)
+		return c.createIndex(ctx, indexName, dimension)
+	}
+
+	// Индексы, созданные до появления вложений чатов, дополняем полем chat_id
+	return c.ensureChatMapping(ctx, indexName)
+}
+
+func (c *OpenSearchClient) ensureChatMapping(ctx context.Context, indexName string) error {
+	body, err := json.Marshal(map[string]interface{}{
+		"properties": map[string]interface{}{
+			"chat_id": map[string]interface{}{
+				"type": "keyword",
+			},
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	req := opensearchapi.IndicesPutMappingRequest{
+		Index: []string{indexName},
+		Body:  bytes.NewReader(body),
+	}
+
+	res, err := req.Do(ctx, c.client)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	if res.IsError() {
+		bodyBytes, _ := io.ReadAll(res.Body)
+		return fmt.Errorf("failed to update index mapping: %s", string(bodyBytes))
 	}
 
 	return nil
 }
 
-func (c *OpenSearchClient) createIndex(ctx context.Context) error {
+func (c *OpenSearchClient) createIndex(ctx context.Context, indexName string, dimension int) error {
 	indexBody := map[string]interface{}{
 		"mappings": map[string]interface{}{
 			"properties": map[string]interface{}{
@@ -80,6 +140,9 @@ func (c *OpenSearchClient) createIndex(ctx context.Context) error {
 				"organization_id": map[string]interface{}{
 					"type": "keyword",
 				},
+				"chat_id": map[string]interface{}{
+					"type": "keyword",
+				},
 				"document_name": map[string]interface{}{
 					"type": "text",
 				},
@@ -91,7 +154,7 @@ func (c *OpenSearchClient) createIndex(ctx context.Context) error {
 				},
 				"embedding": map[string]interface{}{
 					"type":      "knn_vector",
-					"dimension": 1024,
+					"dimension": dimension,
 				},
 				"metadata": map[string]interface{}{
 					"type": "object",
@@ -111,7 +174,7 @@ func (c *OpenSearchClient) createIndex(ctx context.Context) error {
 	}
 
 	req := opensearchapi.IndicesCreateRequest{
-		Index: c.indexName,
+		Index: indexName,
 		Body:  bytes.NewReader(body),
 	}
 
@@ -138,9 +201,10 @@ type IndexChunkRequest struct {
 	Position       int               `json:"position"`
 	Embedding      []float32         `json:"embedding"`
 	Metadata       map[string]string `json:"metadata"`
+	ChatID         string            `json:"chat_id,omitempty"`
 }
 
-func (c *OpenSearchClient) IndexChunk(ctx context.Context, chunk *domain.Chunk, organizationID domain.ID, documentName string) error {
+func (c *OpenSearchClient) IndexChunk(ctx context.Context, indexName string, chunk *domain.Chunk, organizationID domain.ID, documentName string) error {
 	span, ctx := opentracing.StartSpanFromContext(ctx, "vectordb.OpenSearchClient.IndexChunk")
 	defer span.Finish()
 
@@ -154,6 +218,9 @@ func (c *OpenSearchClient) IndexChunk(ctx context.Context, chunk *domain.Chunk,
 		Embedding:      chunk.Embedding,
 		Metadata:       chunk.Metadata,
 	}
+	if chunk.ChatID != nil {
+		doc.ChatID = chunk.ChatID.String()
+	}
 
 	body, err := json.Marshal(doc)
 	if err != nil {
@@ -161,7 +228,7 @@ func (c *OpenSearchClient) IndexChunk(ctx context.Context, chunk *domain.Chunk,
 	}
 
 	req := opensearchapi.IndexRequest{
-		Index:      c.indexName,
+		Index:      indexName,
 		DocumentID: chunk.ID.String(),
 		Body:       bytes.NewReader(body),
 		Refresh:    "true",
@@ -181,31 +248,47 @@ func (c *OpenSearchClient) IndexChunk(ctx context.Context, chunk *domain.Chunk,
 	return nil
 }
 
-func (c *OpenSearchClient) SearchChunks(ctx context.Context, organizationID domain.ID, queryEmbedding []float32, limit int, minScore float32) ([]*domain.SearchResult, error) {
+// SearchChunks ищет чанки организации. Без chatID ищет по базе знаний организации,
+// с chatID - только по вложениям этого чата.
+func (c *OpenSearchClient) SearchChunks(ctx context.Context, indexName string, organizationID domain.ID, chatID *domain.ID, queryEmbedding []float32, limit int, minScore float32) ([]*domain.SearchResult, error) {
 	span, ctx := opentracing.StartSpanFromContext(ctx, "vectordb.OpenSearchClient.SearchChunks")
 	defer span.Finish()
 
-	query := map[string]interface{}{
-		"size": limit,
-		"query": map[string]interface{}{
-			"bool": map[string]interface{}{
-				"must": []interface{}{
-					map[string]interface{}{
-						"term": map[string]interface{}{
-							"organization_id": organizationID.String(),
-						},
-					},
-					map[string]interface{}{
-						"knn": map[string]interface{}{
-							"embedding": map[string]interface{}{
-								"vector": queryEmbedding,
-								"k":      limit,
-							},
-						},
+	boolQuery := map[string]interface{}{
+		"must": []interface{}{
+			map[string]interface{}{
+				"term": map[string]interface{}{
+					"organization_id": organizationID.String(),
+				},
+			},
+			map[string]interface{}{
+				"knn": map[string]interface{}{
+					"embedding": map[string]interface{}{
+						"vector": queryEmbedding,
+						"k":      limit,
 					},
 				},
 			},
 		},
+	}
+
+	chatFilter := map[string]interface{}{
+		"exists": map[string]interface{}{"field": "chat_id"},
+	}
+	if chatID != nil {
+		chatFilter = map[string]interface{}{
+			"term": map[string]interface{}{"chat_id": chatID.String()},
+		}
+		boolQuery["filter"] = []interface{}{chatFilter}
+	} else {
+		boolQuery["must_not"] = []interface{}{chatFilter}
+	}
+
+	query := map[string]interface{}{
+		"size": limit,
+		"query": map[string]interface{}{
+			"bool": boolQuery,
+		},
 		"min_score": minScore,
 	}
 
@@ -214,9 +297,12 @@ func (c *OpenSearchClient) SearchChunks(ctx context.Context, organizationID doma
 		return nil, fmt.Errorf("failed to marshal search query: %w", err)
 	}
 
+	// Старого индекса может не быть: организация без алиаса читает из него до миграции
+	ignoreUnavailable := true
 	req := opensearchapi.SearchRequest{
-		Index: []string{c.indexName},
-		Body:  bytes.NewReader(body),
+		Index:             []string{indexName},
+		Body:              bytes.NewReader(body),
+		IgnoreUnavailable: &ignoreUnavailable,
 	}
 
 	res, err := req.Do(ctx, c.client)
@@ -270,9 +356,14 @@ func (c *OpenSearchClient) DeleteDocumentChunks(ctx context.Context, documentID
 		return fmt.Errorf("failed to marshal delete query: %w", err)
 	}
 
+	// Чанки документа лежат в индексе одной из моделей или в старом индексе, удаляем во всех
+	ignoreUnavailable := true
+	allowNoIndices := true
 	req := opensearchapi.DeleteByQueryRequest{
-		Index: []string{c.indexName},
-		Body:  strings.NewReader(string(body)),
+		Index:             c.chunkIndices(),
+		Body:              strings.NewReader(string(body)),
+		IgnoreUnavailable: &ignoreUnavailable,
+		AllowNoIndices:    &allowNoIndices,
 	}
 
 	res, err := req.Do(ctx, c.client)
@@ -289,6 +380,52 @@ func (c *OpenSearchClient) DeleteDocumentChunks(ctx context.Context, documentID
 	return nil
 }
 
+// DeleteChatChunks удаляет чанки вложений чата; с documentID - только одного вложения
+func (c *OpenSearchClient) DeleteChatChunks(ctx context.Context, organizationID, chatID domain.ID, documentID *domain.ID) error {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "vectordb.OpenSearchClient.DeleteChatChunks")
+	defer span.Finish()
+
+	filters := []interface{}{
+		map[string]interface{}{"term": map[string]interface{}{"organization_id": organizationID.String()}},
+		map[string]interface{}{"term": map[string]interface{}{"chat_id": chatID.String()}},
+	}
+	if documentID != nil {
+		filters = append(filters, map[string]interface{}{"term": map[string]interface{}{"document_id": documentID.String()}})
+	}
+
+	body, err := json.Marshal(map[string]interface{}{
+		"query": map[string]interface{}{
+			"bool": map[string]interface{}{"filter": filters},
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to marshal delete query: %w", err)
+	}
+
+	// Как и документы, чанки чата могут лежать в индексе любой модели или в старом индексе
+	ignoreUnavailable := true
+	allowNoIndices := true
+	req := opensearchapi.DeleteByQueryRequest{
+		Index:             c.chunkIndices(),
+		Body:              bytes.NewReader(body),
+		IgnoreUnavailable: &ignoreUnavailable,
+		AllowNoIndices:    &allowNoIndices,
+	}
+
+	res, err := req.Do(ctx, c.client)
+	if err != nil {
+		return fmt.Errorf("failed to delete chat chunks: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.IsError() {
+		bodyBytes, _ := io.ReadAll(res.Body)
+		return fmt.Errorf("delete chat chunks failed (status %d): %s", res.StatusCode, string(bodyBytes))
+	}
+
+	return nil
+}
+
 type searchResponse struct {
 	Hits struct {
 		Hits []struct {
@@ -0,0 +1,119 @@
+package vectordb
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+
+	"docs-processor/internal/domain"
+)
+
+func TestIndexName(t *testing.T) {
+	tests := []struct {
+		model     string
+		dimension int
+		want      string
+	}{
+		{model: "text-embedding-3-small", dimension: 1024, want: "documents_text-embedding-3-small_1024"},
+		{model: "BAAI/bge-m3", dimension: 1024, want: "documents_baai-bge-m3_1024"},
+		{model: "nomic-embed-text:latest", dimension: 768, want: "documents_nomic-embed-text-latest_768"},
+	}
+
+	for _, tt := range tests {
+		if got := IndexName("documents", tt.model, tt.dimension); got != tt.want {
+			t.Errorf("IndexName(%q, %d) = %s, want %s", tt.model, tt.dimension, got, tt.want)
+		}
+	}
+}
+
+func TestDeleteChunksIncludeLegacyIndex(t *testing.T) {
+	organizationID := domain.NewID()
+	documentID := domain.NewID()
+	chatID := domain.NewID()
+
+	tests := []struct {
+		name   string
+		delete func(ctx context.Context, client *OpenSearchClient) error
+		want   string
+	}{
+		{
+			name: "документ",
+			delete: func(ctx context.Context, client *OpenSearchClient) error {
+				return client.DeleteDocumentChunks(ctx, documentID)
+			},
+			want: documentID.String(),
+		},
+		{
+			name: "вложения чата",
+			delete: func(ctx context.Context, client *OpenSearchClient) error {
+				return client.DeleteChatChunks(ctx, organizationID, chatID, nil)
+			},
+			want: chatID.String(),
+		},
+		{
+			name: "одно вложение",
+			delete: func(ctx context.Context, client *OpenSearchClient) error {
+				return client.DeleteChatChunks(ctx, organizationID, chatID, &documentID)
+			},
+			want: documentID.String(),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake, client := newFakeOpenSearch(t)
+			fake.respond(http.MethodPost, "/documents,documents_*/_delete_by_query", http.StatusOK, `{"deleted": 1}`)
+
+			if err := tt.delete(context.Background(), client); err != nil {
+				t.Fatalf("delete: %v", err)
+			}
+
+			deletes := fake.find(http.MethodPost, "/_delete_by_query")
+			if len(deletes) != 1 {
+				t.Fatalf("delete_by_query requests = %d, want 1", len(deletes))
+			}
+			if deletes[0].Path != "/documents,documents_*/_delete_by_query" {
+				t.Errorf("path = %s, want legacy and per-model indexes", deletes[0].Path)
+			}
+			if !strings.Contains(deletes[0].Query, "ignore_unavailable=true") {
+				t.Errorf("query %q does not ignore a missing legacy index", deletes[0].Query)
+			}
+			if !strings.Contains(deletes[0].Body, tt.want) {
+				t.Errorf("body %s does not filter by %s", deletes[0].Body, tt.want)
+			}
+		})
+	}
+}
+
+func TestHasOrganizationChunks(t *testing.T) {
+	tests := []struct {
+		name   string
+		status int
+		reply  string
+		want   bool
+	}{
+		{name: "есть чанки", status: http.StatusOK, reply: `{"count": 12}`, want: true},
+		{name: "нет чанков", status: http.StatusOK, reply: `{"count": 0}`, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake, client := newFakeOpenSearch(t)
+			fake.respond(http.MethodPost, "/documents/_count", tt.status, tt.reply)
+
+			got, err := client.HasOrganizationChunks(context.Background(), "documents", domain.NewID())
+			if err != nil {
+				t.Fatalf("HasOrganizationChunks: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("HasOrganizationChunks = %v, want %v", got, tt.want)
+			}
+
+			counts := fake.find(http.MethodPost, "/_count")
+			if len(counts) != 1 || !strings.Contains(counts[0].Query, "ignore_unavailable=true") {
+				t.Errorf("count requests = %+v, want one ignoring a missing index", counts)
+			}
+		})
+	}
+}
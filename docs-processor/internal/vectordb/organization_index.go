@@ -0,0 +1,490 @@
+package vectordb
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"docs-processor/internal/domain"
+
+	opensearchapi "github.com/opensearch-project/opensearch-go/v2/opensearchapi"
+	"github.com/opentracing/opentracing-go"
+)
+
+// StoredChunk чанк, прочитанный из индекса (без вектора)
+type StoredChunk struct {
+	Chunk          *domain.Chunk
+	OrganizationID domain.ID
+	DocumentName   string
+}
+
+// ResolveOrganizationIndex возвращает индекс, на который указывает алиас организации.
+// found=false, если организация еще не привязана ни к одному индексу.
+func (c *OpenSearchClient) ResolveOrganizationIndex(ctx context.Context, organizationID domain.ID) (string, bool, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "vectordb.OpenSearchClient.ResolveOrganizationIndex")
+	defer span.Finish()
+
+	req := opensearchapi.IndicesGetAliasRequest{
+		Name: []string{c.OrganizationAlias(organizationID)},
+	}
+
+	res, err := req.Do(ctx, c.client)
+	if err != nil {
+		return "", false, fmt.Errorf("failed to get organization alias: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode == 404 {
+		return "", false, nil
+	}
+
+	if res.IsError() {
+		bodyBytes, _ := io.ReadAll(res.Body)
+		return "", false, fmt.Errorf("get alias failed (status %d): %s", res.StatusCode, string(bodyBytes))
+	}
+
+	var aliases map[string]json.RawMessage
+	if err := json.NewDecoder(res.Body).Decode(&aliases); err != nil {
+		return "", false, fmt.Errorf("failed to decode alias response: %w", err)
+	}
+
+	for index := range aliases {
+		return index, true, nil
+	}
+
+	return "", false, nil
+}
+
+// SwapOrganizationAlias атомарно переключает алиас организации на indexName
+func (c *OpenSearchClient) SwapOrganizationAlias(ctx context.Context, organizationID domain.ID, indexName string) error {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "vectordb.OpenSearchClient.SwapOrganizationAlias")
+	defer span.Finish()
+
+	alias := c.OrganizationAlias(organizationID)
+
+	current, found, err := c.ResolveOrganizationIndex(ctx, organizationID)
+	if err != nil {
+		return err
+	}
+	if found && current == indexName {
+		return nil
+	}
+
+	actions := make([]interface{}, 0, 2)
+	if found {
+		actions = append(actions, map[string]interface{}{
+			"remove": map[string]interface{}{
+				"index": current,
+				"alias": alias,
+			},
+		})
+	}
+	actions = append(actions, map[string]interface{}{
+		"add": map[string]interface{}{
+			"index": indexName,
+			"alias": alias,
+		},
+	})
+
+	body, err := json.Marshal(map[string]interface{}{"actions": actions})
+	if err != nil {
+		return fmt.Errorf("failed to marshal alias actions: %w", err)
+	}
+
+	req := opensearchapi.IndicesUpdateAliasesRequest{
+		Body: bytes.NewReader(body),
+	}
+
+	res, err := req.Do(ctx, c.client)
+	if err != nil {
+		return fmt.Errorf("failed to update aliases: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.IsError() {
+		bodyBytes, _ := io.ReadAll(res.Body)
+		return fmt.Errorf("update aliases failed (status %d): %s", res.StatusCode, string(bodyBytes))
+	}
+
+	return nil
+}
+
+// ScrollOrganizationChunks постранично читает все чанки организации из индекса
+func (c *OpenSearchClient) ScrollOrganizationChunks(ctx context.Context, indexName string, organizationID domain.ID, batchSize int, fn func([]*StoredChunk) error) error {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "vectordb.OpenSearchClient.ScrollOrganizationChunks")
+	defer span.Finish()
+
+	var searchAfter []interface{}
+	for {
+		query := map[string]interface{}{
+			"size": batchSize,
+			"query": map[string]interface{}{
+				"term": map[string]interface{}{
+					"organization_id": organizationID.String(),
+				},
+			},
+			"sort": []interface{}{
+				map[string]interface{}{"chunk_id": "asc"},
+			},
+			"_source": map[string]interface{}{
+				"excludes": []string{"embedding"},
+			},
+		}
+		if searchAfter != nil {
+			query["search_after"] = searchAfter
+		}
+
+		body, err := json.Marshal(query)
+		if err != nil {
+			return fmt.Errorf("failed to marshal scroll query: %w", err)
+		}
+
+		req := opensearchapi.SearchRequest{
+			Index: []string{indexName},
+			Body:  bytes.NewReader(body),
+		}
+
+		res, err := req.Do(ctx, c.client)
+		if err != nil {
+			return fmt.Errorf("failed to read chunks: %w", err)
+		}
+
+		if res.IsError() {
+			bodyBytes, _ := io.ReadAll(res.Body)
+			res.Body.Close()
+			return fmt.Errorf("read chunks failed (status %d): %s", res.StatusCode, string(bodyBytes))
+		}
+
+		var page scrollResponse
+		err = json.NewDecoder(res.Body).Decode(&page)
+		res.Body.Close()
+		if err != nil {
+			return fmt.Errorf("failed to decode chunks: %w", err)
+		}
+
+		if len(page.Hits.Hits) == 0 {
+			return nil
+		}
+
+		chunks := make([]*StoredChunk, 0, len(page.Hits.Hits))
+		for _, hit := range page.Hits.Hits {
+			chunkID, _ := domain.ParseID(hit.Source.ChunkID)
+			documentID, _ := domain.ParseID(hit.Source.DocumentID)
+			metadata := hit.Source.Metadata
+			if metadata == nil {
+				metadata = make(map[string]string)
+			}
+			chunk := &domain.Chunk{
+				ID:         chunkID,
+				DocumentID: documentID,
+				Content:    hit.Source.Content,
+				Position:   hit.Source.Position,
+				Metadata:   metadata,
+			}
+			// Вложения чатов переносятся при миграции вместе со своей привязкой к чату
+			if hit.Source.ChatID != "" {
+				if chatID, err := domain.ParseID(hit.Source.ChatID); err == nil {
+					chunk.WithChat(chatID)
+				}
+			}
+			chunks = append(chunks, &StoredChunk{
+				Chunk:          chunk,
+				OrganizationID: organizationID,
+				DocumentName:   hit.Source.DocumentName,
+			})
+		}
+
+		if err := fn(chunks); err != nil {
+			return err
+		}
+
+		searchAfter = page.Hits.Hits[len(page.Hits.Hits)-1].Sort
+	}
+}
+
+// HasOrganizationChunks проверяет, есть ли в индексе чанки организации.
+// Отсутствующий индекс считается пустым
+func (c *OpenSearchClient) HasOrganizationChunks(ctx context.Context, indexName string, organizationID domain.ID) (bool, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "vectordb.OpenSearchClient.HasOrganizationChunks")
+	defer span.Finish()
+
+	body, err := json.Marshal(map[string]interface{}{
+		"query": map[string]interface{}{
+			"term": map[string]interface{}{
+				"organization_id": organizationID.String(),
+			},
+		},
+	})
+	if err != nil {
+		return false, fmt.Errorf("failed to marshal count query: %w", err)
+	}
+
+	ignoreUnavailable := true
+	req := opensearchapi.CountRequest{
+		Index:             []string{indexName},
+		Body:              bytes.NewReader(body),
+		IgnoreUnavailable: &ignoreUnavailable,
+	}
+
+	res, err := req.Do(ctx, c.client)
+	if err != nil {
+		return false, fmt.Errorf("failed to count organization chunks: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.IsError() {
+		bodyBytes, _ := io.ReadAll(res.Body)
+		return false, fmt.Errorf("count organization chunks failed (status %d): %s", res.StatusCode, string(bodyBytes))
+	}
+
+	var countResp struct {
+		Count int `json:"count"`
+	}
+	if err := json.NewDecoder(res.Body).Decode(&countResp); err != nil {
+		return false, fmt.Errorf("failed to decode count response: %w", err)
+	}
+
+	return countResp.Count > 0, nil
+}
+
+// DeleteChunks удаляет чанки из индекса по их ID
+func (c *OpenSearchClient) DeleteChunks(ctx context.Context, indexName string, chunkIDs []domain.ID) error {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "vectordb.OpenSearchClient.DeleteChunks")
+	defer span.Finish()
+
+	if len(chunkIDs) == 0 {
+		return nil
+	}
+
+	ids := make([]string, len(chunkIDs))
+	for i, id := range chunkIDs {
+		ids[i] = id.String()
+	}
+
+	body, err := json.Marshal(map[string]interface{}{
+		"query": map[string]interface{}{
+			"terms": map[string]interface{}{
+				"chunk_id": ids,
+			},
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to marshal delete query: %w", err)
+	}
+
+	refresh := true
+	req := opensearchapi.DeleteByQueryRequest{
+		Index:     []string{indexName},
+		Body:      bytes.NewReader(body),
+		Conflicts: "proceed",
+		Refresh:   &refresh,
+	}
+
+	res, err := req.Do(ctx, c.client)
+	if err != nil {
+		return fmt.Errorf("failed to delete chunks: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.IsError() {
+		bodyBytes, _ := io.ReadAll(res.Body)
+		return fmt.Errorf("delete chunks failed (status %d): %s", res.StatusCode, string(bodyBytes))
+	}
+
+	return nil
+}
+
+// DeleteOrganizationChunks удаляет все чанки организации из индекса
+func (c *OpenSearchClient) DeleteOrganizationChunks(ctx context.Context, indexName string, organizationID domain.ID) error {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "vectordb.OpenSearchClient.DeleteOrganizationChunks")
+	defer span.Finish()
+
+	query := map[string]interface{}{
+		"query": map[string]interface{}{
+			"term": map[string]interface{}{
+				"organization_id": organizationID.String(),
+			},
+		},
+	}
+
+	body, err := json.Marshal(query)
+	if err != nil {
+		return fmt.Errorf("failed to marshal delete query: %w", err)
+	}
+
+	req := opensearchapi.DeleteByQueryRequest{
+		Index: []string{indexName},
+		Body:  bytes.NewReader(body),
+	}
+
+	res, err := req.Do(ctx, c.client)
+	if err != nil {
+		return fmt.Errorf("failed to delete organization chunks: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.IsError() {
+		bodyBytes, _ := io.ReadAll(res.Body)
+		return fmt.Errorf("delete organization chunks failed (status %d): %s", res.StatusCode, string(bodyBytes))
+	}
+
+	return nil
+}
+
+// ListOrganizations возвращает организации, у которых есть чанки в индексе
+func (c *OpenSearchClient) ListOrganizations(ctx context.Context, indexName string) ([]domain.ID, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "vectordb.OpenSearchClient.ListOrganizations")
+	defer span.Finish()
+
+	query := map[string]interface{}{
+		"size": 0,
+		"aggs": map[string]interface{}{
+			"organizations": map[string]interface{}{
+				"terms": map[string]interface{}{
+					"field": "organization_id",
+					"size":  10000,
+				},
+			},
+		},
+	}
+
+	body, err := json.Marshal(query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal aggregation query: %w", err)
+	}
+
+	req := opensearchapi.SearchRequest{
+		Index: []string{indexName},
+		Body:  bytes.NewReader(body),
+	}
+
+	res, err := req.Do(ctx, c.client)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list organizations: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.IsError() {
+		bodyBytes, _ := io.ReadAll(res.Body)
+		return nil, fmt.Errorf("list organizations failed (status %d): %s", res.StatusCode, string(bodyBytes))
+	}
+
+	var aggResp struct {
+		Aggregations struct {
+			Organizations struct {
+				Buckets []struct {
+					Key string `json:"key"`
+				} `json:"buckets"`
+			} `json:"organizations"`
+		} `json:"aggregations"`
+	}
+	if err := json.NewDecoder(res.Body).Decode(&aggResp); err != nil {
+		return nil, fmt.Errorf("failed to decode aggregation response: %w", err)
+	}
+
+	ids := make([]domain.ID, 0, len(aggResp.Aggregations.Organizations.Buckets))
+	for _, b := range aggResp.Aggregations.Organizations.Buckets {
+		id, err := domain.ParseID(b.Key)
+		if err != nil {
+			continue
+		}
+		ids = append(ids, id)
+	}
+
+	return ids, nil
+}
+
+type scrollResponse struct {
+	Hits struct {
+		Hits []struct {
+			Sort   []interface{} `json:"sort"`
+			Source struct {
+				ChunkID      string            `json:"chunk_id"`
+				DocumentID   string            `json:"document_id"`
+				DocumentName string            `json:"document_name"`
+				Content      string            `json:"content"`
+				Position     int               `json:"position"`
+				Metadata     map[string]string `json:"metadata"`
+				ChatID       string            `json:"chat_id"`
+			} `json:"_source"`
+		} `json:"hits"`
+	} `json:"hits"`
+}
+
+// PurgeOrganization удаляет чанки организации из индексов всех моделей и ее алиас.
+// Возвращает количество удаленных чанков
+func (c *OpenSearchClient) PurgeOrganization(ctx context.Context, organizationID domain.ID) (int, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "vectordb.OpenSearchClient.PurgeOrganization")
+	defer span.Finish()
+
+	body, err := json.Marshal(map[string]interface{}{
+		"query": map[string]interface{}{
+			"term": map[string]interface{}{
+				"organization_id": organizationID.String(),
+			},
+		},
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to marshal delete query: %w", err)
+	}
+
+	// Организация могла быть на индексе любой модели, в том числе посреди миграции,
+	// или еще не перенесена из старого индекса; отсутствующий старый индекс не ошибка
+	refresh := true
+	ignoreUnavailable := true
+	allowNoIndices := true
+	req := opensearchapi.DeleteByQueryRequest{
+		Index:             c.chunkIndices(),
+		Body:              bytes.NewReader(body),
+		Conflicts:         "proceed",
+		Refresh:           &refresh,
+		IgnoreUnavailable: &ignoreUnavailable,
+		AllowNoIndices:    &allowNoIndices,
+	}
+
+	res, err := req.Do(ctx, c.client)
+	if err != nil {
+		return 0, fmt.Errorf("failed to purge organization chunks: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.IsError() {
+		bodyBytes, _ := io.ReadAll(res.Body)
+		return 0, fmt.Errorf("purge organization chunks failed (status %d): %s", res.StatusCode, string(bodyBytes))
+	}
+
+	var deleteResp struct {
+		Deleted int `json:"deleted"`
+	}
+	if err := json.NewDecoder(res.Body).Decode(&deleteResp); err != nil {
+		return 0, fmt.Errorf("failed to decode delete response: %w", err)
+	}
+
+	current, found, err := c.ResolveOrganizationIndex(ctx, organizationID)
+	if err != nil {
+		return deleteResp.Deleted, err
+	}
+	if !found {
+		return deleteResp.Deleted, nil
+	}
+
+	aliasReq := opensearchapi.IndicesDeleteAliasRequest{
+		Index: []string{current},
+		Name:  []string{c.OrganizationAlias(organizationID)},
+	}
+
+	aliasRes, err := aliasReq.Do(ctx, c.client)
+	if err != nil {
+		return deleteResp.Deleted, fmt.Errorf("failed to delete organization alias: %w", err)
+	}
+	defer aliasRes.Body.Close()
+
+	if aliasRes.IsError() && aliasRes.StatusCode != 404 {
+		bodyBytes, _ := io.ReadAll(aliasRes.Body)
+		return deleteResp.Deleted, fmt.Errorf("delete organization alias failed (status %d): %s", aliasRes.StatusCode, string(bodyBytes))
+	}
+
+	return deleteResp.Deleted, nil
+}
@@ -0,0 +1,159 @@
+package vectordb
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"docs-processor/internal/domain"
+)
+
+// recordedRequest запрос, полученный фейковым OpenSearch
+type recordedRequest struct {
+	Method string
+	Path   string
+	Query  string
+	Body   string
+}
+
+// fakeOpenSearch отвечает на запросы клиента заготовленными ответами по пути и методу
+// и запоминает все запросы
+type fakeOpenSearch struct {
+	mu        sync.Mutex
+	requests  []recordedRequest
+	responses map[string]fakeResponse
+}
+
+type fakeResponse struct {
+	status int
+	body   string
+}
+
+func newFakeOpenSearch(t *testing.T) (*fakeOpenSearch, *OpenSearchClient) {
+	t.Helper()
+
+	fake := &fakeOpenSearch{responses: make(map[string]fakeResponse)}
+	server := httptest.NewServer(http.HandlerFunc(fake.serveHTTP))
+	t.Cleanup(server.Close)
+
+	client, err := NewOpenSearchClient([]string{server.URL}, "", "", "documents")
+	if err != nil {
+		t.Fatalf("NewOpenSearchClient: %v", err)
+	}
+
+	return fake, client
+}
+
+// respond задает ответ на запросы method к пути path
+func (f *fakeOpenSearch) respond(method, path string, status int, body string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.responses[method+" "+path] = fakeResponse{status: status, body: body}
+}
+
+func (f *fakeOpenSearch) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	body, _ := io.ReadAll(r.Body)
+
+	f.mu.Lock()
+	f.requests = append(f.requests, recordedRequest{
+		Method: r.Method,
+		Path:   r.URL.Path,
+		Query:  r.URL.RawQuery,
+		Body:   string(body),
+	})
+	resp, ok := f.responses[r.Method+" "+r.URL.Path]
+	f.mu.Unlock()
+
+	if !ok {
+		resp = fakeResponse{status: http.StatusNotFound, body: `{}`}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(resp.status)
+	_, _ = w.Write([]byte(resp.body))
+}
+
+// find возвращает запросы, путь которых заканчивается на suffix
+func (f *fakeOpenSearch) find(method, suffix string) []recordedRequest {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	var found []recordedRequest
+	for _, r := range f.requests {
+		if r.Method == method && strings.HasSuffix(r.Path, suffix) {
+			found = append(found, r)
+		}
+	}
+	return found
+}
+
+func TestChunkIndicesIncludeLegacyIndex(t *testing.T) {
+	client := &OpenSearchClient{baseName: "documents"}
+
+	got := client.chunkIndices()
+	want := []string{"documents", "documents_*"}
+	if strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Errorf("chunkIndices() = %v, want %v", got, want)
+	}
+}
+
+func TestPurgeOrganizationDeletesFromLegacyAndModelIndexes(t *testing.T) {
+	fake, client := newFakeOpenSearch(t)
+	organizationID := domain.NewID()
+	alias := client.OrganizationAlias(organizationID)
+
+	fake.respond(http.MethodPost, "/documents,documents_*/_delete_by_query", http.StatusOK, `{"deleted": 7}`)
+	fake.respond(http.MethodGet, "/_alias/"+alias, http.StatusOK, `{"documents_bge-m3_1024": {"aliases": {"`+alias+`": {}}}}`)
+	fake.respond(http.MethodDelete, "/documents_bge-m3_1024/_aliases/"+alias, http.StatusOK, `{"acknowledged": true}`)
+
+	deleted, err := client.PurgeOrganization(context.Background(), organizationID)
+	if err != nil {
+		t.Fatalf("PurgeOrganization: %v", err)
+	}
+	if deleted != 7 {
+		t.Errorf("deleted = %d, want 7", deleted)
+	}
+
+	deletes := fake.find(http.MethodPost, "/_delete_by_query")
+	if len(deletes) != 1 {
+		t.Fatalf("delete_by_query requests = %d, want 1", len(deletes))
+	}
+	req := deletes[0]
+	if req.Path != "/documents,documents_*/_delete_by_query" {
+		t.Errorf("delete_by_query path = %s, want legacy and per-model indexes", req.Path)
+	}
+	for _, param := range []string{"ignore_unavailable=true", "allow_no_indices=true", "conflicts=proceed"} {
+		if !strings.Contains(req.Query, param) {
+			t.Errorf("delete_by_query query %q does not contain %s", req.Query, param)
+		}
+	}
+	if !strings.Contains(req.Body, organizationID.String()) {
+		t.Errorf("delete_by_query body %s does not filter by organization", req.Body)
+	}
+
+	if len(fake.find(http.MethodDelete, "/_aliases/"+alias)) != 1 {
+		t.Error("organization alias is not deleted")
+	}
+}
+
+func TestPurgeOrganizationWithoutAlias(t *testing.T) {
+	fake, client := newFakeOpenSearch(t)
+	organizationID := domain.NewID()
+
+	fake.respond(http.MethodPost, "/documents,documents_*/_delete_by_query", http.StatusOK, `{"deleted": 3}`)
+
+	deleted, err := client.PurgeOrganization(context.Background(), organizationID)
+	if err != nil {
+		t.Fatalf("PurgeOrganization: %v", err)
+	}
+	if deleted != 3 {
+		t.Errorf("deleted = %d, want 3", deleted)
+	}
+	if len(fake.find(http.MethodDelete, "/_aliases/"+client.OrganizationAlias(organizationID))) != 0 {
+		t.Error("alias delete requested for an organization without alias")
+	}
+}
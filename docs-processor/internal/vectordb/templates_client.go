@@ -8,6 +8,7 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"time"
 
 	"docs-processor/internal/domain"
 
@@ -19,9 +20,10 @@ import (
 type TemplatesClient struct {
 	client    *opensearch.Client
 	indexName string
+	dimension int
 }
 
-func NewTemplatesClient(addresses []string, username, password, indexName string) (*TemplatesClient, error) {
+func NewTemplatesClient(addresses []string, username, password, indexName string, dimension int) (*TemplatesClient, error) {
 	cfg := opensearch.Config{
 		Addresses: addresses,
 		Username:  username,
@@ -39,6 +41,7 @@ func NewTemplatesClient(addresses []string, username, password, indexName string
 	tc := &TemplatesClient{
 		client:    client,
 		indexName: indexName,
+		dimension: dimension,
 	}
 
 	if err := tc.ensureIndex(context.Background()); err != nil {
@@ -87,7 +90,7 @@ func (c *TemplatesClient) createIndex(ctx context.Context) error {
 				},
 				"embedding": map[string]interface{}{
 					"type":      "knn_vector",
-					"dimension": 1024,
+					"dimension": c.dimension,
 				},
 				"created_at": map[string]interface{}{
 					"type": "date",
@@ -179,6 +182,49 @@ func (c *TemplatesClient) SearchTemplates(ctx context.Context, queryEmbedding []
 	span, ctx := opentracing.StartSpanFromContext(ctx, "vectordb.TemplatesClient.SearchTemplates")
 	defer span.Finish()
 
+	return c.searchTemplates(ctx, c.indexName, queryEmbedding, limit)
+}
+
+// SearchTemplatesIn ищет шаблоны в другом индексе шаблонов (например, построенном прошлой
+// моделью до миграции). Отсутствующий индекс считается пустым
+func (c *TemplatesClient) SearchTemplatesIn(ctx context.Context, indexName string, queryEmbedding []float32, limit int) ([]*TemplateSearchResult, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "vectordb.TemplatesClient.SearchTemplatesIn")
+	defer span.Finish()
+
+	return c.searchTemplates(ctx, indexName, queryEmbedding, limit)
+}
+
+// CountTemplates возвращает количество шаблонов в индексе
+func (c *TemplatesClient) CountTemplates(ctx context.Context) (int, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "vectordb.TemplatesClient.CountTemplates")
+	defer span.Finish()
+
+	req := opensearchapi.CountRequest{
+		Index: []string{c.indexName},
+	}
+
+	res, err := req.Do(ctx, c.client)
+	if err != nil {
+		return 0, fmt.Errorf("failed to count templates: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.IsError() {
+		bodyBytes, _ := io.ReadAll(res.Body)
+		return 0, fmt.Errorf("count templates failed (status %d): %s", res.StatusCode, string(bodyBytes))
+	}
+
+	var countResp struct {
+		Count int `json:"count"`
+	}
+	if err := json.NewDecoder(res.Body).Decode(&countResp); err != nil {
+		return 0, fmt.Errorf("failed to decode count response: %w", err)
+	}
+
+	return countResp.Count, nil
+}
+
+func (c *TemplatesClient) searchTemplates(ctx context.Context, indexName string, queryEmbedding []float32, limit int) ([]*TemplateSearchResult, error) {
 	query := map[string]interface{}{
 		"size": limit,
 		"query": map[string]interface{}{
@@ -196,9 +242,11 @@ func (c *TemplatesClient) SearchTemplates(ctx context.Context, queryEmbedding []
 		return nil, fmt.Errorf("failed to marshal search query: %w", err)
 	}
 
+	ignoreUnavailable := true
 	req := opensearchapi.SearchRequest{
-		Index: []string{c.indexName},
-		Body:  bytes.NewReader(body),
+		Index:             []string{indexName},
+		Body:              bytes.NewReader(body),
+		IgnoreUnavailable: &ignoreUnavailable,
 	}
 
 	res, err := req.Do(ctx, c.client)
@@ -257,6 +305,84 @@ func (c *TemplatesClient) DeleteTemplate(ctx context.Context, templateID domain.
 	return nil
 }
 
+// ScrollTemplates постранично читает шаблоны из другого индекса шаблонов
+// (например, построенного прошлой моделью embeddings)
+func (c *TemplatesClient) ScrollTemplates(ctx context.Context, fromIndex string, batchSize int, fn func([]*domain.Template) error) error {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "vectordb.TemplatesClient.ScrollTemplates")
+	defer span.Finish()
+
+	var searchAfter []interface{}
+	for {
+		query := map[string]interface{}{
+			"size": batchSize,
+			"query": map[string]interface{}{
+				"match_all": map[string]interface{}{},
+			},
+			"sort": []interface{}{
+				map[string]interface{}{"template_id": "asc"},
+			},
+			"_source": map[string]interface{}{
+				"excludes": []string{"embedding"},
+			},
+		}
+		if searchAfter != nil {
+			query["search_after"] = searchAfter
+		}
+
+		body, err := json.Marshal(query)
+		if err != nil {
+			return fmt.Errorf("failed to marshal scroll query: %w", err)
+		}
+
+		req := opensearchapi.SearchRequest{
+			Index: []string{fromIndex},
+			Body:  bytes.NewReader(body),
+		}
+
+		res, err := req.Do(ctx, c.client)
+		if err != nil {
+			return fmt.Errorf("failed to read templates: %w", err)
+		}
+
+		if res.IsError() {
+			bodyBytes, _ := io.ReadAll(res.Body)
+			res.Body.Close()
+			return fmt.Errorf("read templates failed (status %d): %s", res.StatusCode, string(bodyBytes))
+		}
+
+		var page templateScrollResponse
+		err = json.NewDecoder(res.Body).Decode(&page)
+		res.Body.Close()
+		if err != nil {
+			return fmt.Errorf("failed to decode templates: %w", err)
+		}
+
+		if len(page.Hits.Hits) == 0 {
+			return nil
+		}
+
+		templates := make([]*domain.Template, 0, len(page.Hits.Hits))
+		for _, hit := range page.Hits.Hits {
+			templateID, _ := domain.ParseID(hit.Source.TemplateID)
+			createdAt, _ := time.Parse(time.RFC3339, hit.Source.CreatedAt)
+			templates = append(templates, &domain.Template{
+				ID:           templateID,
+				Name:         hit.Source.Name,
+				Description:  hit.Source.Description,
+				TemplateType: hit.Source.TemplateType,
+				FieldsCount:  hit.Source.FieldsCount,
+				CreatedAt:    createdAt,
+			})
+		}
+
+		if err := fn(templates); err != nil {
+			return err
+		}
+
+		searchAfter = page.Hits.Hits[len(page.Hits.Hits)-1].Sort
+	}
+}
+
 type TemplateSearchResult struct {
 	TemplateID   domain.ID
 	Name         string
@@ -280,3 +406,19 @@ type templateSearchResponse struct {
 		} `json:"hits"`
 	} `json:"hits"`
 }
+
+type templateScrollResponse struct {
+	Hits struct {
+		Hits []struct {
+			Sort   []interface{} `json:"sort"`
+			Source struct {
+				TemplateID   string `json:"template_id"`
+				Name         string `json:"name"`
+				Description  string `json:"description"`
+				TemplateType string `json:"template_type"`
+				FieldsCount  int    `json:"fields_count"`
+				CreatedAt    string `json:"created_at"`
+			} `json:"_source"`
+		} `json:"hits"`
+	} `json:"hits"`
+}
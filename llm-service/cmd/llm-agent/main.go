@@ -15,6 +15,7 @@ import (
 	agentapi "llm-service/internal/app/llm-agent/api/agent"
 	contractsapi "llm-service/internal/app/llm-agent/api/contracts"
 	memoryapi "llm-service/internal/app/llm-agent/api/memory"
+	"llm-service/internal/audit"
 	"llm-service/internal/config"
 	"llm-service/internal/contracts"
 	"llm-service/internal/coreservice"
@@ -22,21 +23,37 @@ import (
 	"llm-service/internal/docx"
 	"llm-service/internal/domain"
 	"llm-service/internal/jwt"
+	"llm-service/internal/llm"
+	anthropic_llm "llm-service/internal/llm/anthropic"
+	"llm-service/internal/llm/cassette"
 	openai_llm "llm-service/internal/llm/openai"
+	"llm-service/internal/llm/router"
 	"llm-service/internal/logger"
 	"llm-service/internal/mcp"
+	"llm-service/internal/notify"
+	"llm-service/internal/queue"
 	"llm-service/internal/rag"
 	"llm-service/internal/repository"
+	"llm-service/internal/service"
 	"llm-service/internal/service/agent"
+	"llm-service/internal/service/attachment"
 	"llm-service/internal/service/chat"
 	contextbuilder "llm-service/internal/service/context"
+	"llm-service/internal/service/erasure"
 	"llm-service/internal/service/executor"
+	"llm-service/internal/service/export"
+	"llm-service/internal/service/feedback"
+	"llm-service/internal/service/membership"
+	"llm-service/internal/service/orgexport"
 	"llm-service/internal/service/orgmemory"
 	"llm-service/internal/service/quota"
+	"llm-service/internal/service/scheduler"
 	"llm-service/internal/service/subagent"
 	"llm-service/internal/service/tool"
 	"llm-service/internal/storage"
+	"llm-service/internal/telegrambot"
 	"llm-service/internal/tracer"
+	"llm-service/internal/transcription"
 	"llm-service/internal/websearch"
 
 	"github.com/jackc/pgx/v5/pgxpool"
@@ -87,12 +104,16 @@ func Run() error {
 		logger.Fatal(ctx, err.Error())
 	}
 
-	openAIClient, err := getOpenAIClient(cfg)
+	llmRouter, err := getLLMRouter(cfg)
 	if err != nil {
-		return fmt.Errorf("failed to create OpenAI client: %w", err)
+		return fmt.Errorf("failed to create LLM router: %w", err)
 	}
 
-	llmClient := openai_llm.New(openAIClient, cfg)
+	var llmClient llm.CompletionProvider = llmRouter
+	if recordPath := cfg.GetLLMRecordPath(); recordPath != "" {
+		logger.Warn(ctx, "Recording LLM interactions to cassette", "path", recordPath)
+		llmClient = cassette.NewRecorder(llmRouter, recordPath)
+	}
 
 	transactor := db.NewContextManager(pool)
 
@@ -115,7 +136,18 @@ func Run() error {
 	var messageRepo repository.MessageRepository = repo
 	var toolRepo repository.ToolCallRepository = repo
 
-	chatManager := chat.NewManager(chatRepo, messageRepo, toolRepo)
+	var chatShareRepo repository.ChatShareRepository = repo
+
+	// Initialize core-service client for contracts and organization members
+	coreServiceClient, err := coreservice.NewClient(cfg.GetCoreServiceAddress())
+	if err != nil {
+		return fmt.Errorf("failed to create core-service client: %w", err)
+	}
+
+	// Членство пользователей в организациях: для авторизации запросов, доступа к чатам и прав агента в Telegram-боте
+	membershipService := membership.New(coreServiceClient, cfg.GetAuthzMembershipCacheTTL())
+
+	chatManager := chat.NewManager(chatRepo, messageRepo, toolRepo, chatShareRepo, membershipService, cfg)
 
 	// Initialize RAG client
 	ragClient, err := rag.NewClient(cfg.GetDocsProcessorAddress())
@@ -124,12 +156,6 @@ func Run() error {
 	}
 	defer ragClient.Close()
 
-	// Initialize core-service client for contracts
-	coreServiceClient, err := coreservice.NewClient(cfg.GetCoreServiceAddress())
-	if err != nil {
-		return fmt.Errorf("failed to create core-service client: %w", err)
-	}
-
 	// Initialize S3 client
 	s3Client, err := storage.NewS3Client(
 		cfg.GetS3Endpoint(),
@@ -148,10 +174,10 @@ func Run() error {
 
 	// Initialize contract services
 	contractSearchService := contracts.NewSearchService(ragClient.GetClient(), coreServiceClient)
-	contractGeneratorService := contracts.NewGeneratorService(coreServiceClient, s3Client, docxProcessor)
+	contractGeneratorService := contracts.NewGeneratorService(coreServiceClient, s3Client, docxProcessor, contracts.NewFieldExtractor(llmClient))
 
 	// Initialize context builder
-	ctxBuilder := contextbuilder.NewBuilder(ragClient, orgMemoryService)
+	ctxBuilder := contextbuilder.NewBuilder(ragClient, orgMemoryService, coreServiceClient)
 
 	// Initialize subagent manager
 	subagentManager := subagent.NewManager(chatManager, agentManager)
@@ -181,8 +207,56 @@ func Run() error {
 	}
 	logger.Info(ctx, "AmoCRM MCP tools synced successfully")
 
+	// Вложения сообщений: проверка в S3, временный индекс документов чата в docs-processor
+	// Распознавание голосовых сообщений; без провайдера аудиовложения отклоняются
+	var transcriber transcription.Transcriber
+	switch provider := cfg.GetTranscriptionProvider(); provider {
+	case "openai":
+		transcriber = transcription.NewOpenAIClient(
+			cfg.GetTranscriptionBaseURL(),
+			cfg.GetTranscriptionAPIKey(),
+			cfg.GetTranscriptionModel(),
+			cfg.GetTranscriptionLanguage(),
+			cfg.GetTranscriptionTimeout(),
+		)
+	case "whisper_server":
+		transcriber = transcription.NewWhisperServerClient(
+			cfg.GetTranscriptionBaseURL(),
+			cfg.GetTranscriptionLanguage(),
+			cfg.GetTranscriptionTimeout(),
+		)
+	case "":
+		logger.Info(ctx, "transcription provider is not configured, voice messages are disabled")
+	default:
+		logger.Fatalf(ctx, "unknown transcription provider %q", provider)
+	}
+
+	attachmentService := attachment.New(s3Client, ragClient, coreServiceClient, chatManager, transcriber)
+
+	// Initialize audit log publisher for agent tool calls
+	var auditRecorder service.AuditRecorder = audit.NopRecorder{}
+	if url := cfg.GetAuditRabbitMQURL(); url != "" {
+		auditQueue, err := queue.NewRabbitMQClient(url, cfg.GetAuditQueueName())
+		if err != nil {
+			logger.Fatalf(ctx, "failed to connect to audit queue: %v", err)
+		}
+		defer auditQueue.Close()
+		auditRecorder = audit.NewQueueRecorder(auditQueue)
+	} else {
+		logger.Info(ctx, "audit rabbitmq url is not configured, agent tool calls are not audited")
+	}
+
 	// Initialize tool executor
-	toolExecutor := tool.NewExecutor(agentManager, subagentManager, tavilyClient, orgMemoryService, mcpClient, contractSearchService, contractGeneratorService)
+	toolExecutor := tool.NewExecutor(agentManager, subagentManager, tavilyClient, orgMemoryService, mcpClient, contractSearchService, contractGeneratorService, attachmentService, auditRecorder)
+
+	// Факты об организации извлекаются из сообщений в фоне, токены списываются с квоты автора
+	factExtractionWorker := orgmemory.NewExtractionWorker(
+		orgmemory.NewFactExtractor(llmClient, orgMemoryService),
+		quotaService,
+		cfg.GetLLMFactExtractionWorkers(),
+		cfg.GetLLMFactExtractionQueueSize(),
+	)
+	go factExtractionWorker.Run(ctx)
 
 	// Initialize agent executor
 	agentExecutor := executor.NewExecutor(
@@ -193,11 +267,106 @@ func Run() error {
 		subagentManager,
 		llmClient,
 		quotaService,
+		attachmentService,
+		factExtractionWorker,
 		cfg,
 	)
 
 	// Create API services
-	agentAPIService := agentapi.NewService(chatManager, agentExecutor, quotaService)
+	exportService := export.New(chatManager, agentManager, s3Client, coreServiceClient, docxProcessor, cfg)
+
+	feedbackService := feedback.New(repo, chatManager, agentManager)
+
+	var scheduleRepo repository.ScheduleRepository = repo
+	scheduleService := scheduler.New(scheduleRepo, agentManager, cfg)
+
+	// Уведомления уходят в очередь сервиса уведомлений core-service; без RabbitMQ - только в лог
+	var notificationSink service.NotificationSink = notify.NewLogSink()
+	if url := cfg.GetNotificationsRabbitMQURL(); url != "" {
+		notificationQueue, err := queue.NewRabbitMQClient(url, cfg.GetNotificationsQueueName())
+		if err != nil {
+			logger.Fatalf(ctx, "failed to connect to notifications queue: %v", err)
+		}
+		defer notificationQueue.Close()
+		notificationSink = notify.NewQueueSink(notificationQueue)
+	}
+
+	// Удаление данных по заявкам core-service: удаленные организации и учетные записи после льготного периода
+	if url := cfg.GetErasureRabbitMQURL(); url != "" {
+		erasureRequests, err := queue.NewRabbitMQClient(url, cfg.GetErasureRequestsQueueName())
+		if err != nil {
+			logger.Fatalf(ctx, "failed to connect to erasure requests queue: %v", err)
+		}
+		defer erasureRequests.Close()
+		erasureResults, err := queue.NewRabbitMQClient(url, cfg.GetErasureResultsQueueName())
+		if err != nil {
+			logger.Fatalf(ctx, "failed to connect to erasure results queue: %v", err)
+		}
+		defer erasureResults.Close()
+
+		var erasureRepo repository.ErasureRepository = repo
+		erasureService := erasure.New(erasureRepo, transactor, erasureResults)
+		go func() {
+			err := erasureRequests.Consume(ctx, cfg.GetErasureConsumerTag(), cfg.GetErasurePrefetchCount(), erasureService.HandleMessage)
+			if err != nil {
+				logger.Errorf(ctx, "erasure consumer stopped: %v", err)
+			}
+		}()
+	} else {
+		logger.Info(ctx, "erasure rabbitmq url is not configured, data erasure requests are not processed")
+	}
+
+	// Выгрузка чатов и памяти ассистента для архива данных организации по запросам core-service
+	if url := cfg.GetOrgExportRabbitMQURL(); url != "" {
+		exportRequests, err := queue.NewRabbitMQClient(url, cfg.GetOrgExportRequestsQueueName())
+		if err != nil {
+			logger.Fatalf(ctx, "failed to connect to organization export requests queue: %v", err)
+		}
+		defer exportRequests.Close()
+		exportResults, err := queue.NewRabbitMQClient(url, cfg.GetOrgExportResultsQueueName())
+		if err != nil {
+			logger.Fatalf(ctx, "failed to connect to organization export results queue: %v", err)
+		}
+		defer exportResults.Close()
+
+		orgExportService := orgexport.New(repo, repo, s3Client, exportResults)
+		go func() {
+			err := exportRequests.Consume(ctx, cfg.GetOrgExportConsumerTag(), cfg.GetOrgExportPrefetchCount(), orgExportService.HandleMessage)
+			if err != nil {
+				logger.Errorf(ctx, "organization export consumer stopped: %v", err)
+			}
+		}()
+	} else {
+		logger.Info(ctx, "organization export rabbitmq url is not configured, organization data exports are not processed")
+	}
+
+	// Запуски по расписанию выполняются от имени владельца: токен для него выдает core-service по токену сервиса
+	if cfg.GetSchedulerEnabled() && cfg.GetSchedulerServiceToken() != "" {
+		worker := scheduler.NewWorker(scheduleRepo, agentExecutor, quotaService, coreServiceClient, notificationSink, cfg)
+		go worker.Run(ctx)
+	} else if cfg.GetSchedulerEnabled() {
+		logger.Warn(ctx, "scheduler.service_token is not configured, scheduled agent runs are not processed")
+	}
+
+	// Telegram-бот: тот же агент в личных сообщениях, пользователь авторизуется через core-service
+	if cfg.GetTelegramBotEnabled() {
+		if cfg.GetTelegramBotToken() == "" {
+			logger.Fatal(ctx, "telegram_bot.bot_token is required when telegram bot is enabled")
+		}
+		var telegramBindingRepo repository.TelegramBindingRepository = repo
+		bot := telegrambot.New(
+			telegrambot.NewAPI(cfg.GetTelegramBotAPIURL(), cfg.GetTelegramBotToken()),
+			coreServiceClient,
+			telegramBindingRepo,
+			membershipService,
+			agentExecutor,
+			s3Client,
+			cfg,
+		)
+		go bot.Run(ctx)
+	}
+
+	agentAPIService := agentapi.NewService(chatManager, agentExecutor, quotaService, attachmentService, exportService, feedbackService, scheduleService)
 	memoryAPIService := memoryapi.NewService(orgMemoryService)
 	contractsAPIService := contractsapi.NewService(contractGeneratorService)
 
@@ -234,6 +403,7 @@ func Run() error {
 		app.WithGatewayPort(cfg.GetHTTPPort()),
 		app.WithEnableGateway(cfg.GetHTTPEnabled()),
 		app.WithWSGrpcConn(grpcConn),
+		app.WithMembershipProvider(membershipService),
 	)
 
 	if err := app.Run(ctx); err != nil {
@@ -257,29 +427,75 @@ func (t *ProxyRoundTripper) RoundTrip(req *http.Request) (*http.Response, error)
 	return transport.RoundTrip(req)
 }
 
-func getOpenAIClient(cfg *config.Config) (openai.Client, error) {
-	proxyURL := cfg.GetProxyUrl()
-	apiKey := cfg.GetLLMAPIKey()
-	if apiKey == "" {
-		return openai.Client{}, fmt.Errorf("LLM API key is not set")
+// getLLMRouter builds a provider per configured backend and wraps them into a failover router
+func getLLMRouter(cfg *config.Config) (*router.Router, error) {
+	httpClient := &http.Client{
+		Transport: &ProxyRoundTripper{
+			proxy: cfg.GetProxyUrl(),
+		},
+	}
+
+	var backends []router.Backend
+	for _, p := range cfg.GetLLMProviders() {
+		if p.APIKey == "" {
+			return nil, fmt.Errorf("LLM API key is not set for provider %q", p.Name)
+		}
+
+		var provider llm.CompletionProvider
+		switch p.Type {
+		case config.LLMProviderOpenAI, "":
+			openAIClient := getOpenAIClient(httpClient, p.APIKey, p.BaseURL)
+			opts := []openai_llm.Option{openai_llm.WithName(p.Name)}
+			if p.Model != "" {
+				opts = append(opts, openai_llm.WithModel(p.Model))
+			}
+			if p.ReasoningEffort != "" {
+				opts = append(opts, openai_llm.WithReasoningEffort(p.ReasoningEffort))
+			}
+			provider = openai_llm.New(openAIClient, cfg, opts...)
+		case config.LLMProviderAnthropic:
+			if p.Model == "" {
+				return nil, fmt.Errorf("model is required for anthropic provider %q", p.Name)
+			}
+			provider = anthropic_llm.New(
+				httpClient,
+				p.APIKey,
+				p.Model,
+				anthropic_llm.WithName(p.Name),
+				anthropic_llm.WithBaseURL(p.BaseURL),
+				anthropic_llm.WithMaxTokens(p.MaxTokens),
+			)
+		default:
+			return nil, fmt.Errorf("unknown LLM provider type %q for provider %q", p.Type, p.Name)
+		}
+
+		backends = append(backends, router.Backend{
+			Name:     p.Name,
+			Provider: provider,
+			Models:   p.Models,
+		})
 	}
 
-	baseURL := cfg.GetLLMBaseURL()
+	return router.New(
+		backends,
+		router.WithRetry(cfg.GetLLMRetryMaxAttempts(), cfg.GetLLMRetryInitialBackoff(), cfg.GetLLMRetryMaxBackoff()),
+		router.WithHealth(cfg.GetLLMHealthFailureThreshold(), cfg.GetLLMHealthCooldown()),
+	)
+}
 
+func getOpenAIClient(httpClient *http.Client, apiKey, baseURL string) openai.Client {
 	options := []option.RequestOption{
 		option.WithAPIKey(apiKey),
-		option.WithHTTPClient(&http.Client{
-			Transport: &ProxyRoundTripper{
-				proxy: proxyURL,
-			},
-		}),
+		option.WithHTTPClient(httpClient),
+		// Retries and failover are handled by the LLM router
+		option.WithMaxRetries(0),
 	}
 
 	if baseURL != "" {
 		options = append(options, option.WithBaseURL(baseURL))
 	}
 
-	return openai.NewClient(options...), nil
+	return openai.NewClient(options...)
 }
 
 func main() {
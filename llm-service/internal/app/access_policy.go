@@ -0,0 +1,72 @@
+package app
+
+import (
+	"context"
+
+	"llm-service/internal/app/interceptors"
+	"llm-service/internal/domain"
+	desc "llm-service/pkg/agent"
+)
+
+// unprotectedMethods не требуют аутентификации
+var unprotectedMethods = []string{
+	"/grpc.reflection.v1alpha.ServerReflection/ServerReflectionInfo",
+	"/grpc.reflection.v1.ServerReflection/ServerReflectionInfo",
+}
+
+// streamMessageOrg - организация сообщения StreamMessage берется из org_id полезной нагрузки,
+// поэтому проверяется каждое сообщение стрима
+func streamMessageOrg(ctx context.Context, req any) (domain.ID, bool, error) {
+	r, ok := req.(*desc.StreamMessageRequest)
+	if !ok {
+		return domain.ID{}, false, domain.NewInvalidArgumentError("unexpected stream message")
+	}
+
+	switch {
+	case r.GetNewMessage() != nil:
+		return interceptors.OrgIDField(ctx, r.GetNewMessage())
+	case r.GetEditMessage() != nil:
+		return interceptors.OrgIDField(ctx, r.GetEditMessage())
+	case r.GetRegenerate() != nil:
+		return interceptors.OrgIDField(ctx, r.GetRegenerate())
+	default:
+		return domain.ID{}, false, domain.NewInvalidArgumentError("stream message has no payload")
+	}
+}
+
+// accessPolicy задает для каждого RPC организацию запроса и требуемое право в ней.
+// Доступ к конкретному чату внутри организации дополнительно проверяет chat.Manager,
+// а права на инструменты агента - tool.Executor по ExecutionContext.
+func accessPolicy() interceptors.AccessPolicy {
+	orgField := interceptors.OrgIDField
+
+	return interceptors.AccessPolicy{
+		desc.AgentService_GetChat_FullMethodName:               interceptors.Member(orgField),
+		desc.AgentService_ListChats_FullMethodName:             interceptors.Member(orgField),
+		desc.AgentService_SearchChats_FullMethodName:           interceptors.Member(orgField),
+		desc.AgentService_DeleteChat_FullMethodName:            interceptors.Member(orgField),
+		desc.AgentService_PromoteChatAttachment_FullMethodName: interceptors.Permitted(orgField, domain.PermissionDocumentsWrite),
+		desc.AgentService_ShareChat_FullMethodName:             interceptors.Member(orgField),
+		desc.AgentService_UnshareChat_FullMethodName:           interceptors.Member(orgField),
+		desc.AgentService_ListChatShares_FullMethodName:        interceptors.Member(orgField),
+		desc.AgentService_ListSharedChats_FullMethodName:       interceptors.Member(orgField),
+		desc.AgentService_ListOrganizationChats_FullMethodName: interceptors.Permitted(orgField, domain.PermissionChatsViewAll),
+		desc.AgentService_ExportChat_FullMethodName:            interceptors.Member(orgField),
+		desc.AgentService_GetMessages_FullMethodName:           interceptors.Member(orgField),
+		desc.AgentService_SwitchMessageBranch_FullMethodName:   interceptors.Member(orgField),
+		desc.AgentService_RateMessage_FullMethodName:           interceptors.Member(orgField),
+		desc.AgentService_GetFeedbackReport_FullMethodName:     interceptors.Permitted(orgField, domain.PermissionAnalyticsView),
+		desc.AgentService_CreateAgentSchedule_FullMethodName:   interceptors.Member(orgField),
+		desc.AgentService_ListAgentSchedules_FullMethodName:    interceptors.Member(orgField),
+		desc.AgentService_UpdateAgentSchedule_FullMethodName:   interceptors.Member(orgField),
+		desc.AgentService_DeleteAgentSchedule_FullMethodName:   interceptors.Member(orgField),
+		desc.AgentService_GetLLMLimits_FullMethodName:          interceptors.Authenticated(),
+		desc.AgentService_StreamMessage_FullMethodName:         interceptors.Member(streamMessageOrg),
+
+		desc.MemoryService_ListMemoryFacts_FullMethodName:  interceptors.Member(orgField),
+		desc.MemoryService_CreateMemoryFact_FullMethodName: interceptors.Permitted(orgField, domain.PermissionMemoryEdit),
+		desc.MemoryService_DeleteMemoryFact_FullMethodName: interceptors.Permitted(orgField, domain.PermissionMemoryEdit),
+
+		desc.ContractsService_TestGenerateContract_FullMethodName: interceptors.Permitted(orgField, domain.PermissionContractsGenerate),
+	}
+}
@@ -0,0 +1,378 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"testing"
+	"time"
+
+	"llm-service/internal/app/interceptors"
+	"llm-service/internal/domain"
+	"llm-service/internal/jwt"
+	"llm-service/internal/logger"
+	desc "llm-service/pkg/agent"
+
+	jwtlib "github.com/golang-jwt/jwt/v5"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+func TestMain(m *testing.M) {
+	logger.Init()
+	os.Exit(m.Run())
+}
+
+const testSecret = "test-secret"
+
+// fakeMemberships - членства, которые знает core-service, но которых еще нет в токене
+type fakeMemberships map[domain.ID]domain.Membership
+
+func (f fakeMemberships) GetMembership(_ context.Context, userID, organizationID domain.ID) (*domain.Membership, error) {
+	membership, ok := f[userID]
+	if !ok || membership.OrganizationID != organizationID {
+		return nil, domain.ErrNotFound
+	}
+	return &membership, nil
+}
+
+// caller - пользователь с access-токеном, в котором перечислены его организации
+type caller struct {
+	name     string
+	userID   domain.ID
+	token    string
+	isMember bool
+	isAdmin  bool
+}
+
+func newCaller(t *testing.T, name string, isMember, isAdmin bool, orgs map[domain.ID]domain.OrganizationRole) caller {
+	t.Helper()
+
+	claimOrgs := make([]map[string]any, 0, len(orgs))
+	for orgID, role := range orgs {
+		claimOrgs = append(claimOrgs, map[string]any{"id": orgID.String(), "role": string(role)})
+	}
+	return newCallerWithClaims(t, name, isMember, isAdmin, claimOrgs)
+}
+
+// newCallerWithClaims выпускает токен с заданным claim "orgs"
+func newCallerWithClaims(t *testing.T, name string, isMember, isAdmin bool, claimOrgs []map[string]any) caller {
+	t.Helper()
+
+	userID := domain.NewID()
+	token, err := jwt.NewSecretCredentials(testSecret).NewWithClaims(jwtlib.MapClaims{
+		"sub":  userID.String(),
+		"orgs": claimOrgs,
+		"iat":  time.Now().Unix(),
+		"exp":  time.Now().Add(time.Hour).Unix(),
+	})
+	if err != nil {
+		t.Fatalf("failed to sign token: %v", err)
+	}
+
+	return caller{name: name, userID: userID, token: token, isMember: isMember, isAdmin: isAdmin}
+}
+
+// tenantFixture - организации A и B и набор пользователей с разным доступом к A
+type tenantFixture struct {
+	orgA, orgB domain.ID
+	callers    []caller
+	provider   *jwt.Provider
+	authorizer *interceptors.Authorizer
+}
+
+func newTenantFixture(t *testing.T) *tenantFixture {
+	f := &tenantFixture{orgA: domain.NewID(), orgB: domain.NewID()}
+
+	adminA := newCaller(t, "admin of A", true, true, map[domain.ID]domain.OrganizationRole{f.orgA: domain.OrganizationRoleAdmin})
+	employeeA := newCaller(t, "employee of A", true, false, map[domain.ID]domain.OrganizationRole{
+		f.orgA: domain.OrganizationRoleEmployee,
+		f.orgB: domain.OrganizationRoleAdmin,
+	})
+	adminB := newCaller(t, "admin of B", false, false, map[domain.ID]domain.OrganizationRole{f.orgB: domain.OrganizationRoleAdmin})
+	outsider := newCaller(t, "outsider", false, false, nil)
+	// Вступил в A после выдачи токена - членство находится через core-service
+	joinedLater := newCaller(t, "employee of A missing from token", true, false, nil)
+	// Деактивирован в A; в токене A нет
+	deactivated := newCaller(t, "deactivated member of A", false, false, nil)
+
+	members := fakeMemberships{
+		joinedLater.userID: {
+			OrganizationID: f.orgA,
+			Role:           domain.OrganizationRoleEmployee,
+			Active:         true,
+			Permissions:    domain.RolePermissions(domain.OrganizationRoleEmployee),
+		},
+		deactivated.userID: {OrganizationID: f.orgA, Role: domain.OrganizationRoleAdmin, Active: false},
+	}
+
+	f.callers = []caller{adminA, employeeA, adminB, outsider, joinedLater, deactivated}
+	f.provider = jwt.NewProvider(jwt.WithCredentials(jwt.NewSecretCredentials(testSecret)))
+	f.authorizer = interceptors.NewAuthorizer(accessPolicy(), members, unprotectedMethods...)
+	return f
+}
+
+// callUnary прогоняет запрос через auth и authz интерцепторы, как это делает gRPC-сервер и gateway
+func (f *tenantFixture) callUnary(c caller, method string, req any) error {
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("authorization", "Bearer "+c.token))
+	info := &grpc.UnaryServerInfo{FullMethod: method}
+
+	auth := interceptors.NewUnaryAuthInterceptor(f.provider, unprotectedMethods...)
+	authz := f.authorizer.UnaryInterceptor()
+
+	handler := func(ctx context.Context, req any) (any, error) {
+		return authz(ctx, req, info, func(context.Context, any) (any, error) { return "ok", nil })
+	}
+	_, err := auth(ctx, req, info, handler)
+	return err
+}
+
+// fakeServerStream отдает заранее заданные сообщения и затем io.EOF
+type fakeServerStream struct {
+	grpc.ServerStream
+	ctx      context.Context
+	messages []*desc.StreamMessageRequest
+}
+
+func (s *fakeServerStream) Context() context.Context {
+	return s.ctx
+}
+
+func (s *fakeServerStream) RecvMsg(m any) error {
+	if len(s.messages) == 0 {
+		return io.EOF
+	}
+	proto.Merge(m.(*desc.StreamMessageRequest), s.messages[0])
+	s.messages = s.messages[1:]
+	return nil
+}
+
+// callStream отправляет сообщения в StreamMessage и возвращает, сколько из них дошло до обработчика
+func (f *tenantFixture) callStream(c caller, messages ...*desc.StreamMessageRequest) (int, error) {
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("authorization", "Bearer "+c.token))
+	stream := &fakeServerStream{ctx: ctx, messages: messages}
+	info := &grpc.StreamServerInfo{FullMethod: desc.AgentService_StreamMessage_FullMethodName, IsClientStream: true, IsServerStream: true}
+
+	auth := interceptors.NewStreamAuthInterceptor(f.provider, unprotectedMethods...)
+	authz := f.authorizer.StreamInterceptor()
+
+	var received int
+	handler := func(_ any, ss grpc.ServerStream) error {
+		for {
+			if err := ss.RecvMsg(&desc.StreamMessageRequest{}); err != nil {
+				if errors.Is(err, io.EOF) {
+					return nil
+				}
+				return err
+			}
+			received++
+		}
+	}
+
+	err := auth(nil, stream, info, func(srv any, ss grpc.ServerStream) error {
+		return authz(srv, ss, info, handler)
+	})
+	return received, err
+}
+
+func newMessage(orgID domain.ID) *desc.StreamMessageRequest {
+	return &desc.StreamMessageRequest{Pyaload: &desc.StreamMessageRequest_NewMessage{
+		NewMessage: &desc.NewMessagePayload{OrgId: orgID.String(), Content: "Привет"},
+	}}
+}
+
+func regenerate(orgID domain.ID) *desc.StreamMessageRequest {
+	return &desc.StreamMessageRequest{Pyaload: &desc.StreamMessageRequest_Regenerate{
+		Regenerate: &desc.RegenerateMessagePayload{OrgId: orgID.String(), ChatId: domain.NewID().String(), MessageId: domain.NewID().String()},
+	}}
+}
+
+func expectAccess(t *testing.T, allowed bool, err error) {
+	t.Helper()
+	if allowed && err != nil {
+		t.Fatalf("expected access, got %v", err)
+	}
+	if !allowed && !errors.Is(err, domain.ErrForbidden) {
+		t.Fatalf("expected forbidden, got %v", err)
+	}
+}
+
+// TestAccessPolicyCrossTenant проверяет матрицу доступа к unary RPC организации A
+func TestAccessPolicyCrossTenant(t *testing.T) {
+	f := newTenantFixture(t)
+	orgA := f.orgA.String()
+	chatID := domain.NewID().String()
+
+	cases := []struct {
+		method    string
+		req       any
+		adminOnly bool
+	}{
+		{desc.AgentService_GetChat_FullMethodName, &desc.GetChatRequest{ChatId: chatID, OrgId: orgA}, false},
+		{desc.AgentService_ListChats_FullMethodName, &desc.ListChatsRequest{OrgId: orgA}, false},
+		{desc.AgentService_DeleteChat_FullMethodName, &desc.DeleteChatRequest{ChatId: chatID, OrgId: orgA}, false},
+		{desc.AgentService_GetMessages_FullMethodName, &desc.GetMessagesRequest{ChatId: chatID, OrgId: orgA}, false},
+		{desc.AgentService_ExportChat_FullMethodName, &desc.ExportChatRequest{ChatId: chatID, OrgId: orgA}, false},
+		{desc.AgentService_ShareChat_FullMethodName, &desc.ShareChatRequest{ChatId: chatID, OrgId: orgA}, false},
+		{desc.AgentService_ListAgentSchedules_FullMethodName, &desc.ListAgentSchedulesRequest{OrgId: orgA}, false},
+		{desc.AgentService_ListOrganizationChats_FullMethodName, &desc.ListOrganizationChatsRequest{OrgId: orgA}, true},
+		{desc.AgentService_GetFeedbackReport_FullMethodName, &desc.GetFeedbackReportRequest{OrgId: orgA}, true},
+		{desc.MemoryService_ListMemoryFacts_FullMethodName, &desc.ListMemoryFactsRequest{OrgId: orgA}, false},
+		{desc.MemoryService_CreateMemoryFact_FullMethodName, &desc.CreateMemoryFactRequest{OrgId: orgA}, false},
+		{desc.MemoryService_DeleteMemoryFact_FullMethodName, &desc.DeleteMemoryFactRequest{Id: domain.NewID().String(), OrgId: orgA}, false},
+		{desc.ContractsService_TestGenerateContract_FullMethodName, &desc.TestGenerateContractRequest{OrgId: orgA}, false},
+	}
+
+	for _, tc := range cases {
+		for _, c := range f.callers {
+			t.Run(tc.method+"/"+c.name, func(t *testing.T) {
+				allowed := c.isMember && (!tc.adminOnly || c.isAdmin)
+				expectAccess(t, allowed, f.callUnary(c, tc.method, tc.req))
+			})
+		}
+	}
+}
+
+// TestAccessPolicyCustomRolePermissions - права собственной роли из токена заменяют права сотрудника по умолчанию
+func TestAccessPolicyCustomRolePermissions(t *testing.T) {
+	f := newTenantFixture(t)
+	orgA := f.orgA.String()
+
+	accountant := newCallerWithClaims(t, "accountant of A", true, false, []map[string]any{
+		{"id": orgA, "role": "employee", "permissions": []string{string(domain.PermissionContractsRead)}},
+	})
+
+	cases := []struct {
+		method  string
+		req     any
+		allowed bool
+	}{
+		{desc.AgentService_ListChats_FullMethodName, &desc.ListChatsRequest{OrgId: orgA}, true},
+		{desc.MemoryService_ListMemoryFacts_FullMethodName, &desc.ListMemoryFactsRequest{OrgId: orgA}, true},
+		{desc.MemoryService_CreateMemoryFact_FullMethodName, &desc.CreateMemoryFactRequest{OrgId: orgA}, false},
+		{desc.ContractsService_TestGenerateContract_FullMethodName, &desc.TestGenerateContractRequest{OrgId: orgA}, false},
+		{desc.AgentService_PromoteChatAttachment_FullMethodName, &desc.PromoteChatAttachmentRequest{OrgId: orgA}, false},
+		{desc.AgentService_ListOrganizationChats_FullMethodName, &desc.ListOrganizationChatsRequest{OrgId: orgA}, false},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.method, func(t *testing.T) {
+			expectAccess(t, tc.allowed, f.callUnary(accountant, tc.method, tc.req))
+		})
+	}
+}
+
+// TestAccessPolicyStreamMembershipInContext - обработчик стрима видит права пользователя
+// в организации последнего принятого сообщения
+func TestAccessPolicyStreamMembershipInContext(t *testing.T) {
+	f := newTenantFixture(t)
+	employeeA := f.callers[1]
+
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("authorization", "Bearer "+employeeA.token))
+	stream := &fakeServerStream{ctx: ctx, messages: []*desc.StreamMessageRequest{newMessage(f.orgA), newMessage(f.orgB)}}
+	info := &grpc.StreamServerInfo{FullMethod: desc.AgentService_StreamMessage_FullMethodName, IsClientStream: true, IsServerStream: true}
+
+	var seen []domain.OrganizationRole
+	handler := func(_ any, ss grpc.ServerStream) error {
+		for {
+			if err := ss.RecvMsg(&desc.StreamMessageRequest{}); err != nil {
+				if errors.Is(err, io.EOF) {
+					return nil
+				}
+				return err
+			}
+			membership, ok := interceptors.MembershipFromContext(ss.Context())
+			if !ok {
+				return errors.New("membership is missing from stream context")
+			}
+			seen = append(seen, membership.Role)
+		}
+	}
+
+	auth := interceptors.NewStreamAuthInterceptor(f.provider, unprotectedMethods...)
+	authz := f.authorizer.StreamInterceptor()
+	err := auth(nil, stream, info, func(srv any, ss grpc.ServerStream) error {
+		return authz(srv, ss, info, handler)
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := []domain.OrganizationRole{domain.OrganizationRoleEmployee, domain.OrganizationRoleAdmin}
+	if fmt.Sprint(seen) != fmt.Sprint(want) {
+		t.Fatalf("expected roles %v, got %v", want, seen)
+	}
+}
+
+// TestAccessPolicyStreamMessage - org_id проверяется в каждом сообщении стрима
+func TestAccessPolicyStreamMessage(t *testing.T) {
+	f := newTenantFixture(t)
+
+	for _, c := range f.callers {
+		t.Run(c.name, func(t *testing.T) {
+			received, err := f.callStream(c, newMessage(f.orgA), regenerate(f.orgA))
+			expectAccess(t, c.isMember, err)
+			if c.isMember && received != 2 {
+				t.Fatalf("expected both messages to reach the handler, got %d", received)
+			}
+			if !c.isMember && received != 0 {
+				t.Fatalf("expected no messages to reach the handler, got %d", received)
+			}
+		})
+	}
+}
+
+// TestAccessPolicyStreamSwitchToForeignOrganization - нельзя начать стрим в своей организации
+// и отправить следующее сообщение в чужую
+func TestAccessPolicyStreamSwitchToForeignOrganization(t *testing.T) {
+	f := newTenantFixture(t)
+	adminA := f.callers[0]
+
+	received, err := f.callStream(adminA, newMessage(f.orgA), newMessage(f.orgB))
+	if !errors.Is(err, domain.ErrForbidden) {
+		t.Fatalf("expected forbidden, got %v", err)
+	}
+	if received != 1 {
+		t.Fatalf("expected only the first message to reach the handler, got %d", received)
+	}
+}
+
+func TestAccessPolicyInvalidRequests(t *testing.T) {
+	f := newTenantFixture(t)
+	adminA := f.callers[0]
+
+	err := f.callUnary(adminA, desc.AgentService_ListChats_FullMethodName, &desc.ListChatsRequest{OrgId: "not-a-uuid"})
+	if !errors.Is(err, domain.ErrInvalidArgument) {
+		t.Errorf("malformed org_id: expected invalid argument, got %v", err)
+	}
+
+	_, err = f.callStream(adminA, &desc.StreamMessageRequest{})
+	if !errors.Is(err, domain.ErrInvalidArgument) {
+		t.Errorf("empty stream payload: expected invalid argument, got %v", err)
+	}
+
+	err = f.callUnary(f.callers[3], desc.AgentService_GetLLMLimits_FullMethodName, &emptypb.Empty{})
+	if err != nil {
+		t.Errorf("limits are not scoped to an organization: expected access, got %v", err)
+	}
+}
+
+// TestAccessPolicyCoversAllMethods - каждый RPC из agent.proto описан в политике
+func TestAccessPolicyCoversAllMethods(t *testing.T) {
+	policy := accessPolicy()
+
+	services := desc.File_agent_agent_proto.Services()
+	for i := 0; i < services.Len(); i++ {
+		service := services.Get(i)
+		for j := 0; j < service.Methods().Len(); j++ {
+			method := fmt.Sprintf("/%s/%s", service.FullName(), service.Methods().Get(j).Name())
+			if _, ok := policy[method]; !ok {
+				t.Errorf("%s has no access rule", method)
+			}
+		}
+	}
+}
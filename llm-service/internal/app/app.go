@@ -41,6 +41,7 @@ type Options struct {
 	bypassCors       bool
 	jwtProvider      *jwt.Provider
 	wsGrpcConn       *grpc.ClientConn
+	memberships      interceptors.MembershipProvider
 }
 
 var defaultOptions = &Options{
@@ -101,6 +102,13 @@ func WithWSGrpcConn(conn *grpc.ClientConn) OptionsFunc {
 	}
 }
 
+// WithMembershipProvider sets the lookup for organizations missing from the caller's access token
+func WithMembershipProvider(members interceptors.MembershipProvider) OptionsFunc {
+	return func(o *Options) {
+		o.memberships = members
+	}
+}
+
 type App struct {
 	agentService     *agent.Service
 	memoryService    *memory.Service
@@ -134,24 +142,22 @@ func (a *App) Run(ctx context.Context) error {
 	grpcEndpoint := fmt.Sprintf(":%d", a.options.grpcPort)
 	httpEndpoint := fmt.Sprintf(":%d", a.options.gatewayPort)
 
-	// Define unprotected methods (no auth), e.g., gRPC reflection
-	unprotected := []string{
-		"/grpc.reflection.v1alpha.ServerReflection/ServerReflectionInfo",
-		"/grpc.reflection.v1.ServerReflection/ServerReflectionInfo",
-	}
+	authorizer := interceptors.NewAuthorizer(accessPolicy(), a.options.memberships, unprotectedMethods...)
 
 	srv := grpc.NewServer(
 		grpc.ChainUnaryInterceptor(
 			interceptors.TracingInterceptor,
 			interceptors.RecoveryInterceptor,
 			interceptors.ErrCodesInterceptor,
-			interceptors.NewUnaryAuthInterceptor(a.jwtProvider, unprotected...),
+			interceptors.NewUnaryAuthInterceptor(a.jwtProvider, unprotectedMethods...),
+			authorizer.UnaryInterceptor(),
 		),
 		grpc.ChainStreamInterceptor(
 			interceptors.TracingStreamInterceptor,
 			interceptors.RecoveryStreamInterceptor,
 			interceptors.ErrCodesStreamInterceptor,
-			interceptors.NewStreamAuthInterceptor(a.jwtProvider, unprotected...),
+			interceptors.NewStreamAuthInterceptor(a.jwtProvider, unprotectedMethods...),
+			authorizer.StreamInterceptor(),
 		),
 	)
 
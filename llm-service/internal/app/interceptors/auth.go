@@ -36,15 +36,16 @@ func NewUnaryAuthInterceptor(provider *jwt.Provider, unprotected ...string) grpc
 			return nil, domain.ErrUnauthorized
 		}
 
-		userID, err := provider.ParseToken(ctx, token)
+		claims, err := provider.ParseClaims(ctx, token)
 		if err != nil {
 			logger.Warnf(ctx, "token parse failed in %s: %v", info.FullMethod, err)
 			return nil, err
 		}
 
-		span.SetTag("user_id", userID.String())
+		span.SetTag("user_id", claims.UserID.String())
 
-		ctx = WithUserID(ctx, userID)
+		ctx = WithUserID(ctx, claims.UserID)
+		ctx = WithTokenMemberships(ctx, claims.Memberships)
 		return handler(ctx, req)
 	}
 }
@@ -70,15 +71,16 @@ func NewStreamAuthInterceptor(provider *jwt.Provider, unprotected ...string) grp
 			return domain.ErrUnauthorized
 		}
 
-		userID, err := provider.ParseToken(ss.Context(), token)
+		claims, err := provider.ParseClaims(ss.Context(), token)
 		if err != nil {
 			logger.Warnf(ctx, "token parse failed in stream %s: %v", info.FullMethod, err)
 			return err
 		}
 
-		span.SetTag("user_id", userID.String())
+		span.SetTag("user_id", claims.UserID.String())
 
-		ctx = WithUserID(ctx, userID)
+		ctx = WithUserID(ctx, claims.UserID)
+		ctx = WithTokenMemberships(ctx, claims.Memberships)
 		ctx = WithAccessToken(ctx, token)
 
 		wrapped := &wrappedServerStream{ServerStream: ss, ctx: ctx}
@@ -0,0 +1,217 @@
+package interceptors
+
+// Checks that the caller is an active member of the organization the request is scoped to
+// and has the role and permission required by the access policy. Must run after the auth interceptor.
+//
+// Memberships are taken from the access token first; organizations missing from the token
+// (joined after the token was issued) are looked up through the membership provider.
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"llm-service/internal/domain"
+	"llm-service/internal/logger"
+
+	"github.com/opentracing/opentracing-go"
+	"google.golang.org/grpc"
+)
+
+// OrgResolver returns the organization a request is scoped to.
+// ok is false when the request is not bound to any organization.
+type OrgResolver func(ctx context.Context, req any) (orgID domain.ID, ok bool, err error)
+
+// AccessRule describes who may call a method
+type AccessRule struct {
+	// Org resolves the organization of the request; nil means any authenticated user may call the method
+	Org OrgResolver
+	// Role is the minimal role the caller must have in the organization
+	Role domain.OrganizationRole
+	// Permission is required in the organization in addition to the role; empty means none
+	Permission domain.Permission
+}
+
+// AccessPolicy maps fully-qualified method names to access rules.
+// Methods missing from the policy are denied.
+type AccessPolicy map[string]AccessRule
+
+// MembershipProvider looks up organization membership; returns domain.ErrNotFound for non-members
+type MembershipProvider interface {
+	GetMembership(ctx context.Context, userID, organizationID domain.ID) (*domain.Membership, error)
+}
+
+// Authenticated allows the method to any authenticated user
+func Authenticated() AccessRule {
+	return AccessRule{}
+}
+
+// Member requires an active membership in the resolved organization
+func Member(org OrgResolver) AccessRule {
+	return AccessRule{Org: org, Role: domain.OrganizationRoleEmployee}
+}
+
+// Admin requires an active admin membership in the resolved organization
+func Admin(org OrgResolver) AccessRule {
+	return AccessRule{Org: org, Role: domain.OrganizationRoleAdmin}
+}
+
+// Permitted requires an active membership with the permission in the resolved organization
+func Permitted(org OrgResolver, permission domain.Permission) AccessRule {
+	return AccessRule{Org: org, Role: domain.OrganizationRoleEmployee, Permission: permission}
+}
+
+// OrgIDField resolves the organization from the org_id request field
+func OrgIDField(_ context.Context, req any) (domain.ID, bool, error) {
+	r, ok := req.(interface{ GetOrgId() string })
+	if !ok {
+		return domain.ID{}, false, domain.NewInvalidArgumentError("request has no org_id")
+	}
+	orgID, err := domain.ParseID(r.GetOrgId())
+	if err != nil {
+		return domain.ID{}, false, domain.NewInvalidArgumentError("invalid org_id")
+	}
+	return orgID, true, nil
+}
+
+// Authorizer checks requests against the access policy
+type Authorizer struct {
+	policy  AccessPolicy
+	members MembershipProvider
+	skip    map[string]struct{}
+}
+
+// NewAuthorizer creates an authorizer; unprotected methods bypass the policy like they bypass auth.
+// members may be nil, then only memberships from the access token are accepted.
+func NewAuthorizer(policy AccessPolicy, members MembershipProvider, unprotected ...string) *Authorizer {
+	skip := make(map[string]struct{}, len(unprotected))
+	for _, m := range unprotected {
+		skip[m] = struct{}{}
+	}
+	return &Authorizer{policy: policy, members: members, skip: skip}
+}
+
+// authorize checks a single request message and returns the context enriched with the caller's membership
+func (a *Authorizer) authorize(ctx context.Context, method string, req any) (context.Context, error) {
+	rule, ok := a.policy[method]
+	if !ok {
+		logger.Errorf(ctx, "access denied to %s: method has no access rule", method)
+		return ctx, domain.NewForbiddenError("method is not allowed")
+	}
+	if rule.Org == nil {
+		return ctx, nil
+	}
+
+	userID, err := UserIDFromContext(ctx)
+	if err != nil {
+		return ctx, err
+	}
+
+	orgID, ok, err := rule.Org(ctx, req)
+	if err != nil {
+		return ctx, err
+	}
+	if !ok {
+		return ctx, nil
+	}
+
+	member, err := a.membership(ctx, userID, orgID)
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			logger.Warnf(ctx, "access denied to %s: user %s is not a member of organization %s", method, userID, orgID)
+			return ctx, domain.NewForbiddenError("you are not a member of this organization")
+		}
+		return ctx, err
+	}
+	if !member.Active {
+		logger.Warnf(ctx, "access denied to %s: membership of user %s in organization %s is not active", method, userID, orgID)
+		return ctx, domain.NewForbiddenError("your membership in this organization is not active")
+	}
+	if !member.HasRole(rule.Role) {
+		logger.Warnf(ctx, "access denied to %s: user %s has role %s in organization %s, %s required", method, userID, member.Role, orgID, rule.Role)
+		return ctx, domain.NewForbiddenError("insufficient role in this organization")
+	}
+	if rule.Permission != "" && !member.HasPermission(rule.Permission) {
+		logger.Warnf(ctx, "access denied to %s: user %s lacks permission %s in organization %s", method, userID, rule.Permission, orgID)
+		return ctx, domain.NewForbiddenError(fmt.Sprintf("permission %s is required in this organization", rule.Permission))
+	}
+
+	return WithMembership(ctx, member), nil
+}
+
+func (a *Authorizer) membership(ctx context.Context, userID, orgID domain.ID) (*domain.Membership, error) {
+	if member, ok := tokenMembership(ctx, orgID); ok {
+		return member, nil
+	}
+	if a.members == nil {
+		return nil, domain.ErrNotFound
+	}
+	return a.members.GetMembership(ctx, userID, orgID)
+}
+
+// UnaryInterceptor checks unary requests against the policy
+func (a *Authorizer) UnaryInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		if _, ok := a.skip[info.FullMethod]; ok {
+			return handler(ctx, req)
+		}
+
+		span, ctx := opentracing.StartSpanFromContext(ctx, "authz "+info.FullMethod)
+		ctx, err := a.authorize(ctx, info.FullMethod, req)
+		span.Finish()
+		if err != nil {
+			return nil, err
+		}
+
+		return handler(ctx, req)
+	}
+}
+
+// StreamInterceptor checks every message received from a stream against the policy
+func (a *Authorizer) StreamInterceptor() grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if _, ok := a.skip[info.FullMethod]; ok {
+			return handler(srv, ss)
+		}
+
+		rule, ok := a.policy[info.FullMethod]
+		if !ok {
+			logger.Errorf(ss.Context(), "access denied to %s: method has no access rule", info.FullMethod)
+			return domain.NewForbiddenError("method is not allowed")
+		}
+		if rule.Org == nil {
+			return handler(srv, ss)
+		}
+
+		return handler(srv, &authorizedServerStream{ServerStream: ss, authorizer: a, method: info.FullMethod})
+	}
+}
+
+// authorizedServerStream authorizes each incoming message before the handler sees it.
+// Context returns the context of the last authorized message, so the handler can read
+// the caller's membership in the organization that message is scoped to.
+type authorizedServerStream struct {
+	grpc.ServerStream
+	authorizer *Authorizer
+	method     string
+	ctx        context.Context
+}
+
+func (s *authorizedServerStream) Context() context.Context {
+	if s.ctx != nil {
+		return s.ctx
+	}
+	return s.ServerStream.Context()
+}
+
+func (s *authorizedServerStream) RecvMsg(m any) error {
+	if err := s.ServerStream.RecvMsg(m); err != nil {
+		return err
+	}
+	ctx, err := s.authorizer.authorize(s.ServerStream.Context(), s.method, m)
+	if err != nil {
+		return err
+	}
+	s.ctx = ctx
+	return nil
+}
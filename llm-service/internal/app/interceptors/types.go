@@ -24,6 +24,8 @@ func (w *wrappedServerStream) Context() context.Context {
 const (
 	userIDContextKey      contextKey = "auth.user_id"
 	accessTokenContextKey contextKey = "auth.access_token"
+	tokenMembershipsKey   contextKey = "auth.token_memberships"
+	membershipContextKey  contextKey = "authz.membership"
 )
 
 // WithUserID stores domain.ID in context for downstream handlers
@@ -61,3 +63,30 @@ func AccessTokenFromContext(ctx context.Context) (string, error) {
 	}
 	return token, nil
 }
+
+// WithTokenMemberships stores organization memberships carried by the access token
+func WithTokenMemberships(ctx context.Context, memberships []domain.Membership) context.Context {
+	return context.WithValue(ctx, tokenMembershipsKey, memberships)
+}
+
+// tokenMembership returns the membership in the organization from the access token, if present
+func tokenMembership(ctx context.Context, organizationID domain.ID) (*domain.Membership, bool) {
+	memberships, _ := ctx.Value(tokenMembershipsKey).([]domain.Membership)
+	for i := range memberships {
+		if memberships[i].OrganizationID == organizationID {
+			return &memberships[i], true
+		}
+	}
+	return nil, false
+}
+
+// WithMembership stores the caller's membership in the organization of the request
+func WithMembership(ctx context.Context, member *domain.Membership) context.Context {
+	return context.WithValue(ctx, membershipContextKey, member)
+}
+
+// MembershipFromContext extracts the membership checked by the authorization interceptor
+func MembershipFromContext(ctx context.Context) (*domain.Membership, bool) {
+	member, ok := ctx.Value(membershipContextKey).(*domain.Membership)
+	return member, ok
+}
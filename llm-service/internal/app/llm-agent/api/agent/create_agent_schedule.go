@@ -0,0 +1,53 @@
+package agent
+
+import (
+	"context"
+
+	"llm-service/internal/app/interceptors"
+	"llm-service/internal/app/llm-agent/mappers"
+	"llm-service/internal/domain"
+	"llm-service/internal/domain/dto"
+	desc "llm-service/pkg/agent"
+
+	"github.com/opentracing/opentracing-go"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (s *Service) CreateAgentSchedule(ctx context.Context, req *desc.CreateAgentScheduleRequest) (*desc.CreateAgentScheduleResponse, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "api.agent.CreateAgentSchedule")
+	defer span.Finish()
+
+	userID, err := interceptors.UserIDFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	orgID, err := domain.ParseID(req.GetOrgId())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid organization_id")
+	}
+
+	enabled := true
+	if req.Enabled != nil {
+		enabled = req.GetEnabled()
+	}
+
+	schedule, err := s.scheduleService.CreateSchedule(ctx, dto.CreateScheduleDTO{
+		OrganizationID: orgID,
+		UserID:         userID,
+		Title:          req.GetTitle(),
+		AgentKey:       req.GetAgentKey(),
+		Prompt:         req.GetPrompt(),
+		CronExpr:       req.GetCronExpr(),
+		Timezone:       req.GetTimezone(),
+		Enabled:        enabled,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &desc.CreateAgentScheduleResponse{
+		Schedule: mappers.AgentScheduleToProto(schedule),
+	}, nil
+}
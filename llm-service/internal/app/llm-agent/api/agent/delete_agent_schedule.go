@@ -0,0 +1,40 @@
+package agent
+
+import (
+	"context"
+
+	"llm-service/internal/app/interceptors"
+	"llm-service/internal/domain"
+	desc "llm-service/pkg/agent"
+
+	"github.com/opentracing/opentracing-go"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+func (s *Service) DeleteAgentSchedule(ctx context.Context, req *desc.DeleteAgentScheduleRequest) (*emptypb.Empty, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "api.agent.DeleteAgentSchedule")
+	defer span.Finish()
+
+	userID, err := interceptors.UserIDFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	orgID, err := domain.ParseID(req.GetOrgId())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid organization_id")
+	}
+
+	scheduleID, err := domain.ParseID(req.GetScheduleId())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid schedule_id")
+	}
+
+	if err := s.scheduleService.DeleteSchedule(ctx, scheduleID, orgID, userID); err != nil {
+		return nil, err
+	}
+
+	return &emptypb.Empty{}, nil
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"llm-service/internal/app/interceptors"
 	"llm-service/internal/domain"
+	"llm-service/internal/logger"
 	desc "llm-service/pkg/agent"
 
 	"github.com/opentracing/opentracing-go"
@@ -37,5 +38,10 @@ func (s *Service) DeleteChat(ctx context.Context, req *desc.DeleteChatRequest) (
 		return nil, fmt.Errorf("failed to delete chat: %w", err)
 	}
 
+	// Временный индекс файлов чата больше не нужен
+	if err := s.attachmentService.CleanupChat(ctx, orgID, chatID); err != nil {
+		logger.Warn(ctx, "failed to cleanup chat files", "chat_id", chatID, "error", err)
+	}
+
 	return &emptypb.Empty{}, nil
 }
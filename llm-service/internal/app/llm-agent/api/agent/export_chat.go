@@ -0,0 +1,53 @@
+package agent
+
+import (
+	"context"
+
+	"llm-service/internal/app/interceptors"
+	"llm-service/internal/app/llm-agent/mappers"
+	"llm-service/internal/domain"
+	desc "llm-service/pkg/agent"
+
+	"github.com/opentracing/opentracing-go"
+	"github.com/samber/lo"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (s *Service) ExportChat(ctx context.Context, req *desc.ExportChatRequest) (*desc.ExportChatResponse, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "api.agent.ExportChat")
+	defer span.Finish()
+
+	userID, err := interceptors.UserIDFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	chatID, err := domain.ParseID(req.GetChatId())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid chat_id")
+	}
+
+	orgID, err := domain.ParseID(req.GetOrgId())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid organization_id")
+	}
+
+	export, err := s.exportService.Export(ctx, orgID, userID, chatID, domain.ChatExportOptions{
+		Format:           mappers.ChatExportFormatFromProto(req.GetFormat()),
+		IncludeSubagents: req.GetIncludeSubagents(),
+		RegisterDocument: req.GetRegisterDocument(),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &desc.ExportChatResponse{
+		DownloadUrl: export.DownloadURL,
+		S3Key:       export.S3Key,
+		FileName:    export.FileName,
+		ContentType: export.ContentType,
+		Size:        export.Size,
+		DocumentId:  lo.Ternary(export.DocumentID != "", &export.DocumentID, nil),
+	}, nil
+}
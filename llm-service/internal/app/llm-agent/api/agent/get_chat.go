@@ -16,12 +16,12 @@ func (s *Service) GetChat(ctx context.Context, req *desc.GetChatRequest) (*desc.
 	span, ctx := opentracing.StartSpanFromContext(ctx, "api.agent.GetChat")
 	defer span.Finish()
 
-	_, err := interceptors.UserIDFromContext(ctx)
+	userID, err := interceptors.UserIDFromContext(ctx)
 	if err != nil {
 		return nil, err
 	}
 
-	_, err = domain.ParseID(req.GetOrgId())
+	orgID, err := domain.ParseID(req.GetOrgId())
 	if err != nil {
 		return nil, fmt.Errorf("invalid organization ID: %w", err)
 	}
@@ -37,6 +37,14 @@ func (s *Service) GetChat(ctx context.Context, req *desc.GetChatRequest) (*desc.
 		return nil, err
 	}
 
+	access, err := s.chatManager.GetChatAccess(ctx, chat, userID, orgID)
+	if err != nil {
+		return nil, err
+	}
+	if !access.CanRead() {
+		return nil, domain.NewNotFoundError("chat not found")
+	}
+
 	return &desc.GetChatResponse{
 		Chat: mappers.DomainChatToProto(chat),
 	}, nil
@@ -0,0 +1,42 @@
+package agent
+
+import (
+	"context"
+
+	"llm-service/internal/app/llm-agent/mappers"
+	"llm-service/internal/domain"
+	desc "llm-service/pkg/agent"
+
+	"github.com/opentracing/opentracing-go"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (s *Service) GetFeedbackReport(ctx context.Context, req *desc.GetFeedbackReportRequest) (*desc.GetFeedbackReportResponse, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "api.agent.GetFeedbackReport")
+	defer span.Finish()
+
+	orgID, err := domain.ParseID(req.GetOrgId())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid organization_id")
+	}
+
+	query := domain.FeedbackReportQuery{
+		OrganizationID: orgID,
+		AgentKey:       req.GetAgentKey(),
+		CommentsLimit:  int(req.GetCommentsLimit()),
+	}
+	if req.From != nil {
+		query.From = req.GetFrom().AsTime()
+	}
+	if req.To != nil {
+		query.To = req.GetTo().AsTime()
+	}
+
+	report, err := s.feedbackService.GetReport(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+
+	return mappers.FeedbackReportToProto(report), nil
+}
@@ -51,9 +51,26 @@ func (s *Service) GetMessages(ctx context.Context, req *desc.GetMessagesRequest)
 		return nil, status.Error(codes.Internal, "internal error")
 	}
 
+	messageIDs := make([]domain.ID, 0, len(messages))
+	for _, msg := range messages {
+		if msg.Role == domain.MessageRoleAssistant {
+			messageIDs = append(messageIDs, msg.ID)
+		}
+	}
+
+	feedback, err := s.feedbackService.GetUserFeedback(ctx, userID, messageIDs)
+	if err != nil {
+		logger.Error(ctx, "failed to get message feedback", "error", err)
+		return nil, status.Error(codes.Internal, "internal error")
+	}
+
 	pbMessages := make([]*desc.Message, 0, len(messages))
 	for _, msg := range messages {
-		pbMessages = append(pbMessages, mappers.DomainMessageToProto(msg))
+		pbMessage := mappers.DomainMessageToProto(msg)
+		if f, ok := feedback[msg.ID]; ok {
+			pbMessage.MyFeedback = mappers.MessageFeedbackToProto(f)
+		}
+		pbMessages = append(pbMessages, pbMessage)
 	}
 
 	return &desc.GetMessagesResponse{
@@ -0,0 +1,43 @@
+package agent
+
+import (
+	"context"
+
+	"llm-service/internal/app/interceptors"
+	"llm-service/internal/app/llm-agent/mappers"
+	"llm-service/internal/domain"
+	desc "llm-service/pkg/agent"
+
+	"github.com/opentracing/opentracing-go"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (s *Service) ListAgentSchedules(ctx context.Context, req *desc.ListAgentSchedulesRequest) (*desc.ListAgentSchedulesResponse, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "api.agent.ListAgentSchedules")
+	defer span.Finish()
+
+	userID, err := interceptors.UserIDFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	orgID, err := domain.ParseID(req.GetOrgId())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid organization_id")
+	}
+
+	schedules, err := s.scheduleService.ListSchedules(ctx, orgID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	pbSchedules := make([]*desc.AgentSchedule, 0, len(schedules))
+	for _, schedule := range schedules {
+		pbSchedules = append(pbSchedules, mappers.AgentScheduleToProto(schedule))
+	}
+
+	return &desc.ListAgentSchedulesResponse{
+		Schedules: pbSchedules,
+	}, nil
+}
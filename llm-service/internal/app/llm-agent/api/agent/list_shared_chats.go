@@ -0,0 +1,94 @@
+package agent
+
+import (
+	"context"
+	"fmt"
+	"llm-service/internal/app/interceptors"
+	"llm-service/internal/app/llm-agent/mappers"
+	"llm-service/internal/domain"
+	desc "llm-service/pkg/agent"
+
+	"github.com/opentracing/opentracing-go"
+)
+
+func (s *Service) ListSharedChats(ctx context.Context, req *desc.ListSharedChatsRequest) (*desc.ListSharedChatsResponse, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "api.agent.ListSharedChats")
+	defer span.Finish()
+
+	userID, err := interceptors.UserIDFromContext(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user ID from context: %w", err)
+	}
+
+	orgID, err := domain.ParseID(req.GetOrgId())
+	if err != nil {
+		return nil, fmt.Errorf("invalid organization ID: %w", err)
+	}
+
+	page, pageSize := normalizePage(int(req.Page), int(req.PageSize))
+
+	chats, total, err := s.chatManager.ListSharedChats(ctx, orgID, userID, page, pageSize)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list shared chats: %w", err)
+	}
+
+	pbChats := make([]*desc.SharedChat, 0, len(chats))
+	for _, chat := range chats {
+		pbChats = append(pbChats, mappers.SharedChatToProto(chat))
+	}
+
+	return &desc.ListSharedChatsResponse{
+		Chats:    pbChats,
+		Total:    int32(total),
+		Page:     int32(page),
+		PageSize: int32(pageSize),
+	}, nil
+}
+
+func (s *Service) ListOrganizationChats(ctx context.Context, req *desc.ListOrganizationChatsRequest) (*desc.ListChatsResponse, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "api.agent.ListOrganizationChats")
+	defer span.Finish()
+
+	userID, err := interceptors.UserIDFromContext(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user ID from context: %w", err)
+	}
+
+	orgID, err := domain.ParseID(req.GetOrgId())
+	if err != nil {
+		return nil, fmt.Errorf("invalid organization ID: %w", err)
+	}
+
+	page, pageSize := normalizePage(int(req.Page), int(req.PageSize))
+
+	chats, total, err := s.chatManager.ListOrganizationChats(ctx, orgID, userID, page, pageSize)
+	if err != nil {
+		return nil, err
+	}
+
+	pbChats := make([]*desc.Chat, 0, len(chats))
+	for _, chat := range chats {
+		pbChats = append(pbChats, mappers.DomainChatToProto(chat))
+	}
+
+	return &desc.ListChatsResponse{
+		Chats:    pbChats,
+		Total:    int32(total),
+		Page:     int32(page),
+		PageSize: int32(pageSize),
+	}, nil
+}
+
+// normalizePage приводит параметры пагинации к допустимым значениям
+func normalizePage(page, pageSize int) (int, int) {
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 {
+		pageSize = 20
+	}
+	if pageSize > 100 {
+		pageSize = 100
+	}
+	return page, pageSize
+}
@@ -0,0 +1,65 @@
+package agent
+
+import (
+	"context"
+
+	"llm-service/internal/app/interceptors"
+	"llm-service/internal/app/llm-agent/mappers"
+	"llm-service/internal/domain"
+	desc "llm-service/pkg/agent"
+
+	"github.com/opentracing/opentracing-go"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (s *Service) PromoteChatAttachment(ctx context.Context, req *desc.PromoteChatAttachmentRequest) (*desc.PromoteChatAttachmentResponse, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "api.agent.PromoteChatAttachment")
+	defer span.Finish()
+
+	userID, err := interceptors.UserIDFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	chatID, err := domain.ParseID(req.GetChatId())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid chat_id")
+	}
+
+	attachmentID, err := domain.ParseID(req.GetAttachmentId())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid attachment_id")
+	}
+
+	orgID, err := domain.ParseID(req.GetOrgId())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid organization_id")
+	}
+
+	chat, err := s.chatManager.GetChat(ctx, chatID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Перенос в базу знаний меняет сообщение, поэтому читателям недоступен
+	access, err := s.chatManager.GetChatAccess(ctx, chat, userID, orgID)
+	if err != nil {
+		return nil, err
+	}
+	if !access.CanRead() {
+		return nil, domain.NewNotFoundError("chat not found")
+	}
+	if !access.CanWrite() {
+		return nil, domain.NewForbiddenError("read-only access to chat")
+	}
+
+	attachment, err := s.attachmentService.Promote(ctx, orgID, userID, chatID, attachmentID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &desc.PromoteChatAttachmentResponse{
+		Attachment: mappers.DomainAttachmentToProto(attachment),
+	}, nil
+}
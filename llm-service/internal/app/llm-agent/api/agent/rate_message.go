@@ -0,0 +1,55 @@
+package agent
+
+import (
+	"context"
+
+	"llm-service/internal/app/interceptors"
+	"llm-service/internal/app/llm-agent/mappers"
+	"llm-service/internal/domain"
+	desc "llm-service/pkg/agent"
+
+	"github.com/opentracing/opentracing-go"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (s *Service) RateMessage(ctx context.Context, req *desc.RateMessageRequest) (*desc.RateMessageResponse, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "api.agent.RateMessage")
+	defer span.Finish()
+
+	userID, err := interceptors.UserIDFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	chatID, err := domain.ParseID(req.GetChatId())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid chat_id")
+	}
+
+	orgID, err := domain.ParseID(req.GetOrgId())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid organization_id")
+	}
+
+	messageID, err := domain.ParseID(req.GetMessageId())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid message_id")
+	}
+
+	feedback, err := s.feedbackService.RateMessage(ctx, domain.MessageFeedback{
+		MessageID:      messageID,
+		UserID:         userID,
+		OrganizationID: orgID,
+		Rating:         mappers.FeedbackRatingFromProto(req.GetRating()),
+		Reasons:        mappers.FeedbackReasonsFromProto(req.GetReasons()),
+		Comment:        req.GetComment(),
+	}, chatID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &desc.RateMessageResponse{
+		Feedback: mappers.MessageFeedbackToProto(feedback),
+	}, nil
+}
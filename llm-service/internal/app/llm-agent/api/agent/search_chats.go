@@ -0,0 +1,66 @@
+package agent
+
+import (
+	"context"
+	"fmt"
+
+	"llm-service/internal/app/interceptors"
+	"llm-service/internal/app/llm-agent/mappers"
+	"llm-service/internal/domain"
+	desc "llm-service/pkg/agent"
+
+	"github.com/opentracing/opentracing-go"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (s *Service) SearchChats(ctx context.Context, req *desc.SearchChatsRequest) (*desc.SearchChatsResponse, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "api.agent.SearchChats")
+	defer span.Finish()
+
+	userID, err := interceptors.UserIDFromContext(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user ID from context: %w", err)
+	}
+
+	orgID, err := domain.ParseID(req.GetOrgId())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid organization_id")
+	}
+
+	page := int(req.GetPage())
+	if page < 1 {
+		page = 1
+	}
+
+	pageSize := int(req.GetPageSize())
+	if pageSize < 1 {
+		pageSize = 20
+	}
+	if pageSize > 100 {
+		pageSize = 100
+	}
+
+	hits, total, err := s.chatManager.SearchChats(ctx, domain.ChatSearchQuery{
+		OrganizationID:        orgID,
+		UserID:                userID,
+		Query:                 req.GetQuery(),
+		IncludeToolMessages:   req.GetIncludeToolMessages(),
+		IncludeSystemMessages: req.GetIncludeSystemMessages(),
+	}, page, pageSize)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]*desc.ChatSearchResult, 0, len(hits))
+	for _, hit := range hits {
+		results = append(results, mappers.ChatSearchHitToProto(hit))
+	}
+
+	return &desc.SearchChatsResponse{
+		Results:  results,
+		Total:    int32(total),
+		Page:     int32(page),
+		PageSize: int32(pageSize),
+	}, nil
+}
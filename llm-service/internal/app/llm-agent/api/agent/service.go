@@ -6,6 +6,7 @@ import (
 
 	"llm-service/internal/app/interceptors"
 	"llm-service/internal/domain"
+	"llm-service/internal/domain/dto"
 	"llm-service/internal/logger"
 	"llm-service/internal/service"
 	pb "llm-service/pkg/agent"
@@ -15,9 +16,13 @@ import (
 )
 
 type Service struct {
-	chatManager   service.ChatManager
-	agentExecutor service.AgentExecutor
-	quotaService  QuotaService
+	chatManager       service.ChatManager
+	agentExecutor     service.AgentExecutor
+	quotaService      QuotaService
+	attachmentService AttachmentService
+	exportService     ExportService
+	feedbackService   FeedbackService
+	scheduleService   ScheduleService
 
 	pb.UnimplementedAgentServiceServer
 }
@@ -26,15 +31,45 @@ type QuotaService interface {
 	GetLimits(ctx context.Context, userID domain.ID) (domain.LLMLimits, error)
 }
 
+type AttachmentService interface {
+	Promote(ctx context.Context, organizationID, userID, chatID, attachmentID domain.ID) (domain.Attachment, error)
+	CleanupChat(ctx context.Context, organizationID, chatID domain.ID) error
+}
+
+type ExportService interface {
+	Export(ctx context.Context, organizationID, userID, chatID domain.ID, opts domain.ChatExportOptions) (*domain.ChatExport, error)
+}
+
+type FeedbackService interface {
+	RateMessage(ctx context.Context, feedback domain.MessageFeedback, chatID domain.ID) (domain.MessageFeedback, error)
+	GetUserFeedback(ctx context.Context, userID domain.ID, messageIDs []domain.ID) (map[domain.ID]domain.MessageFeedback, error)
+	GetReport(ctx context.Context, query domain.FeedbackReportQuery) (domain.FeedbackReport, error)
+}
+
+type ScheduleService interface {
+	CreateSchedule(ctx context.Context, req dto.CreateScheduleDTO) (*domain.AgentSchedule, error)
+	ListSchedules(ctx context.Context, organizationID, userID domain.ID) ([]*domain.AgentSchedule, error)
+	UpdateSchedule(ctx context.Context, req dto.UpdateScheduleDTO) (*domain.AgentSchedule, error)
+	DeleteSchedule(ctx context.Context, scheduleID, organizationID, userID domain.ID) error
+}
+
 func NewService(
 	chatManager service.ChatManager,
 	agentExecutor service.AgentExecutor,
 	quotaService QuotaService,
+	attachmentService AttachmentService,
+	exportService ExportService,
+	feedbackService FeedbackService,
+	scheduleService ScheduleService,
 ) *Service {
 	return &Service{
-		chatManager:   chatManager,
-		agentExecutor: agentExecutor,
-		quotaService:  quotaService,
+		chatManager:       chatManager,
+		agentExecutor:     agentExecutor,
+		quotaService:      quotaService,
+		attachmentService: attachmentService,
+		exportService:     exportService,
+		feedbackService:   feedbackService,
+		scheduleService:   scheduleService,
 	}
 }
 
@@ -0,0 +1,114 @@
+package agent
+
+import (
+	"context"
+
+	"llm-service/internal/app/interceptors"
+	"llm-service/internal/app/llm-agent/mappers"
+	"llm-service/internal/domain"
+	desc "llm-service/pkg/agent"
+
+	"github.com/opentracing/opentracing-go"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+func (s *Service) ShareChat(ctx context.Context, req *desc.ShareChatRequest) (*desc.ShareChatResponse, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "api.agent.ShareChat")
+	defer span.Finish()
+
+	ownerID, err := interceptors.UserIDFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	chatID, err := domain.ParseID(req.GetChatId())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid chat_id")
+	}
+
+	orgID, err := domain.ParseID(req.GetOrgId())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid organization_id")
+	}
+
+	userID, err := domain.ParseID(req.GetUserId())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid user_id")
+	}
+
+	share, err := s.chatManager.ShareChat(ctx, chatID, ownerID, orgID, userID, mappers.ChatShareRoleFromProto(req.GetRole()))
+	if err != nil {
+		return nil, err
+	}
+
+	return &desc.ShareChatResponse{
+		Share: mappers.ChatShareToProto(share),
+	}, nil
+}
+
+func (s *Service) UnshareChat(ctx context.Context, req *desc.UnshareChatRequest) (*emptypb.Empty, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "api.agent.UnshareChat")
+	defer span.Finish()
+
+	requesterID, err := interceptors.UserIDFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	chatID, err := domain.ParseID(req.GetChatId())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid chat_id")
+	}
+
+	orgID, err := domain.ParseID(req.GetOrgId())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid organization_id")
+	}
+
+	userID, err := domain.ParseID(req.GetUserId())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid user_id")
+	}
+
+	if err := s.chatManager.UnshareChat(ctx, chatID, requesterID, orgID, userID); err != nil {
+		return nil, err
+	}
+
+	return &emptypb.Empty{}, nil
+}
+
+func (s *Service) ListChatShares(ctx context.Context, req *desc.ListChatSharesRequest) (*desc.ListChatSharesResponse, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "api.agent.ListChatShares")
+	defer span.Finish()
+
+	userID, err := interceptors.UserIDFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	chatID, err := domain.ParseID(req.GetChatId())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid chat_id")
+	}
+
+	orgID, err := domain.ParseID(req.GetOrgId())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid organization_id")
+	}
+
+	shares, err := s.chatManager.ListChatShares(ctx, chatID, userID, orgID)
+	if err != nil {
+		return nil, err
+	}
+
+	pbShares := make([]*desc.ChatShare, 0, len(shares))
+	for _, share := range shares {
+		pbShares = append(pbShares, mappers.ChatShareToProto(share))
+	}
+
+	return &desc.ListChatSharesResponse{
+		Shares: pbShares,
+	}, nil
+}
@@ -2,6 +2,7 @@ package agent
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 
@@ -79,10 +80,12 @@ func (s *Service) StreamMessage(stream desc.AgentService_StreamMessageServer) er
 
 			// Формируем DTO и запускаем обработку сообщения
 			executeDTO := dto.SendMessageDTO{
-				ChatID:  chatID,
-				UserID:  userID,
-				OrgID:   orgID,
-				Content: nm.GetContent(),
+				ChatID:      chatID,
+				UserID:      userID,
+				OrgID:       orgID,
+				Content:     nm.GetContent(),
+				Attachments: mappers.ProtoAttachmentRefsToDTO(nm.GetAttachments()),
+				Membership:  streamMembership(stream),
 			}
 
 			logger.Infof(ctx, "StreamMessage: calling agentExecutor.SendMessageStream")
@@ -99,6 +102,60 @@ func (s *Service) StreamMessage(stream desc.AgentService_StreamMessageServer) er
 			continue
 		}
 
+		if em := req.GetEditMessage(); em != nil {
+			logger.Infof(ctx, "StreamMessage: processing edit_message: chatId=%s, messageId=%s, content_len=%d",
+				em.GetChatId(), em.GetMessageId(), len(em.GetContent()))
+
+			chatID, orgID, messageID, err := parseBranchIDs(em.GetChatId(), em.GetOrgId(), em.GetMessageId())
+			if err != nil {
+				if sendErr := streamAdapter.SendError(err); sendErr != nil {
+					return sendErr
+				}
+				continue
+			}
+
+			if err := s.agentExecutor.EditMessageStream(ctx, dto.EditMessageDTO{
+				ChatID:     chatID,
+				MessageID:  messageID,
+				UserID:     userID,
+				OrgID:      orgID,
+				Content:    em.GetContent(),
+				Membership: streamMembership(stream),
+			}, streamAdapter); err != nil {
+				logger.Errorf(ctx, "StreamMessage: edit message failed: %v", err)
+				if sendErr := streamAdapter.SendError(fmt.Errorf("failed to execute agent: %w", err)); sendErr != nil {
+					return sendErr
+				}
+			}
+			continue
+		}
+
+		if rg := req.GetRegenerate(); rg != nil {
+			logger.Infof(ctx, "StreamMessage: processing regenerate: chatId=%s, messageId=%s", rg.GetChatId(), rg.GetMessageId())
+
+			chatID, orgID, messageID, err := parseBranchIDs(rg.GetChatId(), rg.GetOrgId(), rg.GetMessageId())
+			if err != nil {
+				if sendErr := streamAdapter.SendError(err); sendErr != nil {
+					return sendErr
+				}
+				continue
+			}
+
+			if err := s.agentExecutor.RegenerateStream(ctx, dto.RegenerateMessageDTO{
+				ChatID:     chatID,
+				MessageID:  messageID,
+				UserID:     userID,
+				OrgID:      orgID,
+				Membership: streamMembership(stream),
+			}, streamAdapter); err != nil {
+				logger.Errorf(ctx, "StreamMessage: regenerate failed: %v", err)
+				if sendErr := streamAdapter.SendError(fmt.Errorf("failed to execute agent: %w", err)); sendErr != nil {
+					return sendErr
+				}
+			}
+			continue
+		}
+
 		// Неподдерживаемый тип запроса
 		logger.Errorf(ctx, "StreamMessage: unsupported request payload: %+v", req)
 		if sendErr := streamAdapter.SendError(fmt.Errorf("unsupported request payload")); sendErr != nil {
@@ -107,6 +164,27 @@ func (s *Service) StreamMessage(stream desc.AgentService_StreamMessageServer) er
 	}
 }
 
+// streamMembership возвращает членство пользователя в организации последнего принятого сообщения.
+// Членство кладет в контекст стрима интерсептор авторизации после проверки каждого сообщения
+func streamMembership(stream desc.AgentService_StreamMessageServer) *domain.Membership {
+	member, _ := interceptors.MembershipFromContext(stream.Context())
+	return member
+}
+
+// parseBranchIDs разбирает идентификаторы запросов правки и перегенерации
+func parseBranchIDs(rawChatID, rawOrgID, rawMessageID string) (chatID, orgID, messageID domain.ID, err error) {
+	if chatID, err = domain.ParseID(rawChatID); err != nil {
+		return chatID, orgID, messageID, fmt.Errorf("invalid chat ID: %w", err)
+	}
+	if orgID, err = domain.ParseID(rawOrgID); err != nil {
+		return chatID, orgID, messageID, fmt.Errorf("invalid org ID: %w", err)
+	}
+	if messageID, err = domain.ParseID(rawMessageID); err != nil {
+		return chatID, orgID, messageID, fmt.Errorf("invalid message ID: %w", err)
+	}
+	return chatID, orgID, messageID, nil
+}
+
 // streamAdapter адаптер для передачи результатов в gRPC stream
 type streamAdapter struct {
 	stream desc.AgentService_StreamMessageServer
@@ -170,6 +248,10 @@ func (a *streamAdapter) SendError(err error) error {
 		code = "quota_exceeded"
 	} else if domain.IsNotFoundError(err) {
 		code = "not_found"
+	} else if errors.Is(err, domain.ErrInvalidArgument) {
+		code = "invalid_argument"
+	} else if errors.Is(err, domain.ErrForbidden) {
+		code = "forbidden"
 	}
 
 	sendErr := a.stream.Send(&desc.StreamMessageResponse{
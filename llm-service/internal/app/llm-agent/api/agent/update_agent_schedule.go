@@ -0,0 +1,54 @@
+package agent
+
+import (
+	"context"
+
+	"llm-service/internal/app/interceptors"
+	"llm-service/internal/app/llm-agent/mappers"
+	"llm-service/internal/domain"
+	"llm-service/internal/domain/dto"
+	desc "llm-service/pkg/agent"
+
+	"github.com/opentracing/opentracing-go"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (s *Service) UpdateAgentSchedule(ctx context.Context, req *desc.UpdateAgentScheduleRequest) (*desc.UpdateAgentScheduleResponse, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "api.agent.UpdateAgentSchedule")
+	defer span.Finish()
+
+	userID, err := interceptors.UserIDFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	orgID, err := domain.ParseID(req.GetOrgId())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid organization_id")
+	}
+
+	scheduleID, err := domain.ParseID(req.GetScheduleId())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid schedule_id")
+	}
+
+	schedule, err := s.scheduleService.UpdateSchedule(ctx, dto.UpdateScheduleDTO{
+		ScheduleID:     scheduleID,
+		OrganizationID: orgID,
+		UserID:         userID,
+		Title:          req.Title,
+		AgentKey:       req.AgentKey,
+		Prompt:         req.Prompt,
+		CronExpr:       req.CronExpr,
+		Timezone:       req.Timezone,
+		Enabled:        req.Enabled,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &desc.UpdateAgentScheduleResponse{
+		Schedule: mappers.AgentScheduleToProto(schedule),
+	}, nil
+}
@@ -52,3 +52,76 @@ func ChatStatusToProto(status domain.ChatStatus) pb.ChatStatus {
 		return pb.ChatStatus_CHAT_STATUS_UNSPECIFIED
 	}
 }
+
+// ChatSearchHitToProto конвертирует результат поиска по чатам в proto
+func ChatSearchHitToProto(hit domain.ChatSearchHit) *pb.ChatSearchResult {
+	result := &pb.ChatSearchResult{
+		Chat:           DomainChatToProto(hit.Chat),
+		Snippet:        hit.Snippet,
+		TitleHighlight: hit.TitleHighlight,
+		Rank:           float32(hit.Rank),
+	}
+	if hit.MessageID != nil {
+		messageID := hit.MessageID.String()
+		result.MessageId = &messageID
+		result.MessageRole = MessageRoleToProto(hit.MessageRole)
+	}
+	return result
+}
+
+// ChatShareToProto конвертирует доступ к чату в proto
+func ChatShareToProto(share domain.ChatShare) *pb.ChatShare {
+	return &pb.ChatShare{
+		ChatId:    share.ChatID.String(),
+		UserId:    share.UserID.String(),
+		Role:      ChatShareRoleToProto(share.Role),
+		SharedBy:  share.SharedBy.String(),
+		CreatedAt: timestamppb.New(share.CreatedAt),
+	}
+}
+
+// SharedChatToProto конвертирует чат, которым поделились с пользователем, в proto
+func SharedChatToProto(shared domain.SharedChat) *pb.SharedChat {
+	return &pb.SharedChat{
+		Chat:  DomainChatToProto(shared.Chat),
+		Share: ChatShareToProto(shared.Share),
+	}
+}
+
+// ChatShareRoleToProto конвертирует domain.ChatShareRole в proto ChatShareRole
+func ChatShareRoleToProto(role domain.ChatShareRole) pb.ChatShareRole {
+	switch role {
+	case domain.ChatShareRoleViewer:
+		return pb.ChatShareRole_CHAT_SHARE_ROLE_VIEWER
+	case domain.ChatShareRoleCollaborator:
+		return pb.ChatShareRole_CHAT_SHARE_ROLE_COLLABORATOR
+	default:
+		return pb.ChatShareRole_CHAT_SHARE_ROLE_UNSPECIFIED
+	}
+}
+
+// ChatShareRoleFromProto конвертирует proto ChatShareRole в domain.ChatShareRole
+func ChatShareRoleFromProto(role pb.ChatShareRole) domain.ChatShareRole {
+	switch role {
+	case pb.ChatShareRole_CHAT_SHARE_ROLE_VIEWER:
+		return domain.ChatShareRoleViewer
+	case pb.ChatShareRole_CHAT_SHARE_ROLE_COLLABORATOR:
+		return domain.ChatShareRoleCollaborator
+	default:
+		return ""
+	}
+}
+
+// ChatExportFormatFromProto конвертирует proto ChatExportFormat в domain.ChatExportFormat
+func ChatExportFormatFromProto(format pb.ChatExportFormat) domain.ChatExportFormat {
+	switch format {
+	case pb.ChatExportFormat_CHAT_EXPORT_FORMAT_MARKDOWN:
+		return domain.ChatExportFormatMarkdown
+	case pb.ChatExportFormat_CHAT_EXPORT_FORMAT_DOCX:
+		return domain.ChatExportFormatDOCX
+	case pb.ChatExportFormat_CHAT_EXPORT_FORMAT_PDF:
+		return domain.ChatExportFormatPDF
+	default:
+		return ""
+	}
+}
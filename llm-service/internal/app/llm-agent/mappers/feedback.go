@@ -0,0 +1,146 @@
+package mappers
+
+import (
+	"sort"
+
+	"llm-service/internal/domain"
+	pb "llm-service/pkg/agent"
+
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+var feedbackReasonsToProto = map[domain.FeedbackReason]pb.FeedbackReason{
+	domain.FeedbackReasonIncorrect:  pb.FeedbackReason_FEEDBACK_REASON_INCORRECT,
+	domain.FeedbackReasonIncomplete: pb.FeedbackReason_FEEDBACK_REASON_INCOMPLETE,
+	domain.FeedbackReasonIrrelevant: pb.FeedbackReason_FEEDBACK_REASON_IRRELEVANT,
+	domain.FeedbackReasonOutdated:   pb.FeedbackReason_FEEDBACK_REASON_OUTDATED,
+	domain.FeedbackReasonToolError:  pb.FeedbackReason_FEEDBACK_REASON_TOOL_ERROR,
+	domain.FeedbackReasonTooLong:    pb.FeedbackReason_FEEDBACK_REASON_TOO_LONG,
+	domain.FeedbackReasonHelpful:    pb.FeedbackReason_FEEDBACK_REASON_HELPFUL,
+	domain.FeedbackReasonOther:      pb.FeedbackReason_FEEDBACK_REASON_OTHER,
+}
+
+// FeedbackRatingToProto конвертирует domain.FeedbackRating в proto FeedbackRating
+func FeedbackRatingToProto(rating domain.FeedbackRating) pb.FeedbackRating {
+	switch rating {
+	case domain.FeedbackRatingPositive:
+		return pb.FeedbackRating_FEEDBACK_RATING_POSITIVE
+	case domain.FeedbackRatingNegative:
+		return pb.FeedbackRating_FEEDBACK_RATING_NEGATIVE
+	default:
+		return pb.FeedbackRating_FEEDBACK_RATING_UNSPECIFIED
+	}
+}
+
+// FeedbackRatingFromProto конвертирует proto FeedbackRating в domain.FeedbackRating
+func FeedbackRatingFromProto(rating pb.FeedbackRating) domain.FeedbackRating {
+	switch rating {
+	case pb.FeedbackRating_FEEDBACK_RATING_POSITIVE:
+		return domain.FeedbackRatingPositive
+	case pb.FeedbackRating_FEEDBACK_RATING_NEGATIVE:
+		return domain.FeedbackRatingNegative
+	default:
+		return ""
+	}
+}
+
+// FeedbackReasonToProto конвертирует domain.FeedbackReason в proto FeedbackReason
+func FeedbackReasonToProto(reason domain.FeedbackReason) pb.FeedbackReason {
+	return feedbackReasonsToProto[reason]
+}
+
+// FeedbackReasonsFromProto конвертирует proto причины в domain; неизвестные значения сохраняются
+// как есть, чтобы сервис вернул ошибку валидации
+func FeedbackReasonsFromProto(reasons []pb.FeedbackReason) []domain.FeedbackReason {
+	result := make([]domain.FeedbackReason, 0, len(reasons))
+	for _, reason := range reasons {
+		converted := domain.FeedbackReason(reason.String())
+		for domainReason, pbReason := range feedbackReasonsToProto {
+			if pbReason == reason {
+				converted = domainReason
+				break
+			}
+		}
+		result = append(result, converted)
+	}
+	return result
+}
+
+// MessageFeedbackToProto конвертирует domain.MessageFeedback в proto
+func MessageFeedbackToProto(feedback domain.MessageFeedback) *pb.MessageFeedback {
+	reasons := make([]pb.FeedbackReason, 0, len(feedback.Reasons))
+	for _, reason := range feedback.Reasons {
+		reasons = append(reasons, FeedbackReasonToProto(reason))
+	}
+
+	return &pb.MessageFeedback{
+		MessageId: feedback.MessageID.String(),
+		ChatId:    feedback.ChatID.String(),
+		UserId:    feedback.UserID.String(),
+		AgentKey:  feedback.AgentKey,
+		Rating:    FeedbackRatingToProto(feedback.Rating),
+		Reasons:   reasons,
+		Comment:   feedback.Comment,
+		ToolNames: feedback.ToolNames,
+		CreatedAt: timestamppb.New(feedback.CreatedAt),
+		UpdatedAt: timestamppb.New(feedback.UpdatedAt),
+	}
+}
+
+// FeedbackStatsToProto конвертирует domain.FeedbackStats в proto
+func FeedbackStatsToProto(stats domain.FeedbackStats) *pb.FeedbackStats {
+	return &pb.FeedbackStats{
+		Positive:         int32(stats.Positive),
+		Negative:         int32(stats.Negative),
+		SatisfactionRate: stats.SatisfactionRate(),
+	}
+}
+
+// FeedbackReportToProto конвертирует отчет по оценкам в proto
+func FeedbackReportToProto(report domain.FeedbackReport) *pb.GetFeedbackReportResponse {
+	agents := make([]*pb.AgentFeedbackStats, 0, len(report.Agents))
+	for _, agent := range report.Agents {
+		reasons := make([]*pb.FeedbackReasonCount, 0, len(agent.Reasons))
+		for reason, count := range agent.Reasons {
+			reasons = append(reasons, &pb.FeedbackReasonCount{
+				Reason: FeedbackReasonToProto(reason),
+				Count:  int32(count),
+			})
+		}
+		sort.Slice(reasons, func(i, j int) bool {
+			if reasons[i].Count != reasons[j].Count {
+				return reasons[i].Count > reasons[j].Count
+			}
+			return reasons[i].Reason < reasons[j].Reason
+		})
+
+		agents = append(agents, &pb.AgentFeedbackStats{
+			AgentKey:  agent.AgentKey,
+			AgentName: agent.AgentName,
+			Stats:     FeedbackStatsToProto(agent.FeedbackStats),
+			Reasons:   reasons,
+		})
+	}
+
+	tools := make([]*pb.ToolFeedbackStats, 0, len(report.Tools))
+	for _, tool := range report.Tools {
+		tools = append(tools, &pb.ToolFeedbackStats{
+			ToolName: tool.ToolName,
+			Stats:    FeedbackStatsToProto(tool.FeedbackStats),
+		})
+	}
+
+	comments := make([]*pb.MessageFeedback, 0, len(report.Comments))
+	for _, comment := range report.Comments {
+		comments = append(comments, MessageFeedbackToProto(comment))
+	}
+
+	return &pb.GetFeedbackReportResponse{
+		Totals:   FeedbackStatsToProto(report.Totals),
+		Agents:   agents,
+		Tools:    tools,
+		Comments: comments,
+		From:     timestamppb.New(report.From),
+		To:       timestamppb.New(report.To),
+	}
+}
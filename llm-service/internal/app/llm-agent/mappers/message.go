@@ -3,8 +3,10 @@ package mappers
 import (
 	"encoding/json"
 	"llm-service/internal/domain"
+	"llm-service/internal/domain/dto"
 	pb "llm-service/pkg/agent"
 
+	"github.com/samber/lo"
 	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
@@ -29,16 +31,79 @@ func DomainMessageToProto(msg *domain.Message) *pb.Message {
 		sender = *msg.Sender
 	}
 
+	var authorUserID string
+	if msg.AuthorUserID != nil {
+		authorUserID = msg.AuthorUserID.String()
+	}
+
+	var parentMessageID string
+	if msg.ParentMessageID != nil {
+		parentMessageID = msg.ParentMessageID.String()
+	}
+
+	siblingIDs := make([]string, 0, len(msg.SiblingIDs))
+	for _, id := range msg.SiblingIDs {
+		siblingIDs = append(siblingIDs, id.String())
+	}
+
 	return &pb.Message{
-		Id:         msg.ID.String(),
-		ChatId:     msg.ChatID.String(),
-		Role:       MessageRoleToProto(msg.Role),
-		Content:    msg.Content,
-		Sender:     sender,
-		ToolCalls:  toolCalls,
-		ToolCallId: toolCallID,
-		CreatedAt:  timestamppb.New(msg.CreatedAt),
+		Id:              msg.ID.String(),
+		ChatId:          msg.ChatID.String(),
+		Role:            MessageRoleToProto(msg.Role),
+		Content:         msg.Content,
+		Sender:          sender,
+		ToolCalls:       toolCalls,
+		ToolCallId:      toolCallID,
+		CreatedAt:       timestamppb.New(msg.CreatedAt),
+		Attachments:     DomainAttachmentsToProto(msg.Attachments),
+		AuthorUserId:    authorUserID,
+		ParentMessageId: parentMessageID,
+		SiblingIds:      siblingIDs,
+	}
+}
+
+// DomainAttachmentsToProto конвертирует вложения сообщения в proto
+func DomainAttachmentsToProto(attachments []domain.Attachment) []*pb.Attachment {
+	if len(attachments) == 0 {
+		return nil
+	}
+
+	result := make([]*pb.Attachment, 0, len(attachments))
+	for _, a := range attachments {
+		result = append(result, DomainAttachmentToProto(a))
+	}
+	return result
+}
+
+// DomainAttachmentToProto конвертирует вложение сообщения в proto
+func DomainAttachmentToProto(a domain.Attachment) *pb.Attachment {
+	return &pb.Attachment{
+		S3Key:       a.S3Key,
+		FileName:    a.FileName,
+		ContentType: a.ContentType,
+		Size:        a.Size,
+		Id:          lo.Ternary(a.ID == domain.ID{}, "", a.ID.String()),
+		Delivery:    string(lo.Ternary(a.Delivery == "", domain.AttachmentDeliveryFile, a.Delivery)),
+		DocumentId:  lo.Ternary(a.IsPromoted(), &a.DocumentID, nil),
+		Transcript:  lo.Ternary(a.IsTranscribed(), &a.Text, nil),
+	}
+}
+
+// ProtoAttachmentRefsToDTO конвертирует ссылки на вложения нового сообщения в DTO
+func ProtoAttachmentRefsToDTO(refs []*pb.AttachmentRef) []dto.AttachmentDTO {
+	if len(refs) == 0 {
+		return nil
+	}
+
+	result := make([]dto.AttachmentDTO, 0, len(refs))
+	for _, ref := range refs {
+		result = append(result, dto.AttachmentDTO{
+			S3Key:       ref.GetS3Key(),
+			FileName:    ref.GetFileName(),
+			ContentType: ref.GetContentType(),
+		})
 	}
+	return result
 }
 
 // MessageRoleToProto конвертирует domain.MessageRole в proto MessageRole
@@ -0,0 +1,57 @@
+package mappers
+
+import (
+	"llm-service/internal/domain"
+	pb "llm-service/pkg/agent"
+
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// ScheduleRunStatusToProto конвертирует domain.ScheduleRunStatus в proto ScheduleRunStatus
+func ScheduleRunStatusToProto(status domain.ScheduleRunStatus) pb.ScheduleRunStatus {
+	switch status {
+	case domain.ScheduleRunStatusSuccess:
+		return pb.ScheduleRunStatus_SCHEDULE_RUN_STATUS_SUCCESS
+	case domain.ScheduleRunStatusFailed:
+		return pb.ScheduleRunStatus_SCHEDULE_RUN_STATUS_FAILED
+	case domain.ScheduleRunStatusSkipped:
+		return pb.ScheduleRunStatus_SCHEDULE_RUN_STATUS_SKIPPED
+	default:
+		return pb.ScheduleRunStatus_SCHEDULE_RUN_STATUS_UNSPECIFIED
+	}
+}
+
+// AgentScheduleToProto конвертирует domain.AgentSchedule в proto AgentSchedule
+func AgentScheduleToProto(schedule *domain.AgentSchedule) *pb.AgentSchedule {
+	if schedule == nil {
+		return nil
+	}
+
+	result := &pb.AgentSchedule{
+		Id:             schedule.ID.String(),
+		OrganizationId: schedule.OrganizationID.String(),
+		UserId:         schedule.UserID.String(),
+		Title:          schedule.Title,
+		AgentKey:       schedule.AgentKey,
+		Prompt:         schedule.Prompt,
+		CronExpr:       schedule.CronExpr,
+		Timezone:       schedule.Timezone,
+		Enabled:        schedule.Enabled,
+		LastRunStatus:  ScheduleRunStatusToProto(schedule.LastRunStatus),
+		LastError:      schedule.LastError,
+		CreatedAt:      timestamppb.New(schedule.CreatedAt),
+		UpdatedAt:      timestamppb.New(schedule.UpdatedAt),
+	}
+
+	if schedule.NextRunAt != nil {
+		result.NextRunAt = timestamppb.New(*schedule.NextRunAt)
+	}
+	if schedule.LastRunAt != nil {
+		result.LastRunAt = timestamppb.New(*schedule.LastRunAt)
+	}
+	if schedule.LastChatID != nil {
+		result.LastChatId = schedule.LastChatID.String()
+	}
+
+	return result
+}
@@ -0,0 +1,34 @@
+package audit
+
+import (
+	"context"
+
+	"llm-service/internal/domain"
+	"llm-service/internal/logger"
+)
+
+type publisher interface {
+	PublishMessage(ctx context.Context, message interface{}) error
+}
+
+// QueueRecorder публикует события аудита в очередь RabbitMQ, откуда их сохраняет core-service
+type QueueRecorder struct {
+	publisher publisher
+}
+
+func NewQueueRecorder(publisher publisher) *QueueRecorder {
+	return &QueueRecorder{publisher: publisher}
+}
+
+// Record публикует событие; ошибки только логируются, чтобы не срывать вызов инструмента
+func (r *QueueRecorder) Record(ctx context.Context, event domain.AuditEvent) {
+	event.Source = "llm-service"
+	if err := r.publisher.PublishMessage(ctx, event); err != nil {
+		logger.Errorf(ctx, "audit: failed to publish %s event for %s %s: %v", event.Action, event.ResourceType, event.ResourceID, err)
+	}
+}
+
+// NopRecorder - журнал аудита отключен
+type NopRecorder struct{}
+
+func (NopRecorder) Record(context.Context, domain.AuditEvent) {}
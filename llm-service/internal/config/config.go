@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"net/url"
 	"sync"
+	"time"
 
 	"llm-service/internal/logger"
 
@@ -39,6 +40,51 @@ type LLM struct {
 	TokenLimit      int    `mapstructure:"token_limit"`
 	// Специальные модели для конкретных задач
 	TitleGenerationModel string `mapstructure:"title_generation_model"`
+	// Автоматически извлекать факты об организации из сообщений пользователя
+	FactExtraction bool `mapstructure:"fact_extraction"`
+	// Количество параллельных извлечений и размер очереди: при переполнении сообщение пропускается
+	FactExtractionWorkers   int `mapstructure:"fact_extraction_workers"`
+	FactExtractionQueueSize int `mapstructure:"fact_extraction_queue_size"`
+	// Несколько провайдеров с failover; если пусто — используется один
+	// OpenAI-совместимый провайдер из полей выше
+	Providers []LLMProvider `mapstructure:"providers"`
+	Retry     LLMRetry      `mapstructure:"retry"`
+	Health    LLMHealth     `mapstructure:"health"`
+	// Путь к файлу кассеты: если задан, все запросы и ответы LLM записываются
+	// для последующего воспроизведения в тестах
+	RecordPath string `mapstructure:"record_path"`
+}
+
+type LLMProviderType string
+
+const (
+	LLMProviderOpenAI    LLMProviderType = "openai"
+	LLMProviderAnthropic LLMProviderType = "anthropic"
+)
+
+// LLMProvider — один бэкенд в роутере провайдеров
+type LLMProvider struct {
+	Name            string          `mapstructure:"name"`
+	Type            LLMProviderType `mapstructure:"type"`
+	BaseURL         string          `mapstructure:"base_url"`
+	APIKey          string          `mapstructure:"api_key"`
+	Model           string          `mapstructure:"model"`
+	Models          []string        `mapstructure:"models"`
+	ReasoningEffort string          `mapstructure:"reasoning_effort"`
+	MaxTokens       int             `mapstructure:"max_tokens"`
+}
+
+// LLMRetry — повторы запроса к одному провайдеру до переключения на следующий
+type LLMRetry struct {
+	MaxAttempts    int           `mapstructure:"max_attempts"`
+	InitialBackoff time.Duration `mapstructure:"initial_backoff"`
+	MaxBackoff     time.Duration `mapstructure:"max_backoff"`
+}
+
+// LLMHealth — когда провайдер считается недоступным и на сколько исключается из ротации
+type LLMHealth struct {
+	FailureThreshold int           `mapstructure:"failure_threshold"`
+	Cooldown         time.Duration `mapstructure:"cooldown"`
 }
 
 type JWT struct {
@@ -94,6 +140,101 @@ type S3 struct {
 	UseSSL    bool   `mapstructure:"use_ssl"`
 }
 
+// Chats — политика доступа к чатам организации
+type Chats struct {
+	// Участники с правом chats.view_all могут читать все чаты организации
+	AdminVisibility bool `mapstructure:"admin_visibility"`
+}
+
+// Authz — проверка членства в организациях
+type Authz struct {
+	// Сколько кэшируется членство, найденное через core-service (организации, которых нет в токене)
+	MembershipCacheTTL time.Duration `mapstructure:"membership_cache_ttl"`
+}
+
+// Export — экспорт чатов в файлы
+type Export struct {
+	// TTF шрифты с кириллицей для PDF
+	PDFFontPath     string `mapstructure:"pdf_font_path"`
+	PDFBoldFontPath string `mapstructure:"pdf_bold_font_path"`
+}
+
+// Scheduler — запуск агентов по расписанию
+type Scheduler struct {
+	Enabled bool `mapstructure:"enabled"`
+	// Как часто воркер ищет расписания, которым пора выполниться
+	PollInterval time.Duration `mapstructure:"poll_interval"`
+	// Ограничение на время одного запуска агента
+	RunTimeout time.Duration `mapstructure:"run_timeout"`
+	// Минимальный интервал между запусками одного расписания
+	MinInterval time.Duration `mapstructure:"min_interval"`
+	// Максимум расписаний у пользователя в организации
+	MaxSchedulesPerUser int `mapstructure:"max_schedules_per_user"`
+	// Сколько расписаний воркер берет за один проход
+	BatchSize int `mapstructure:"batch_size"`
+	// Токен, по которому core-service выдает воркеру токен владельца расписания
+	ServiceToken string `mapstructure:"service_token"`
+}
+
+// Notifications — публикация событий в очередь сервиса уведомлений core-service
+type Notifications struct {
+	// Пустой URL - уведомления только пишутся в лог
+	RabbitMQURL string `mapstructure:"rabbitmq_url"`
+	QueueName   string `mapstructure:"queue_name"`
+}
+
+// Audit — публикация событий в очередь журнала аудита core-service
+type Audit struct {
+	// Пустой URL - вызовы инструментов не попадают в журнал аудита
+	RabbitMQURL string `mapstructure:"rabbitmq_url"`
+	QueueName   string `mapstructure:"queue_name"`
+}
+
+// Erasure — удаление данных организаций и пользователей по заявкам core-service
+type Erasure struct {
+	// Пустой URL - заявки на удаление не принимаются
+	RabbitMQURL       string `mapstructure:"rabbitmq_url"`
+	RequestsQueueName string `mapstructure:"requests_queue_name"`
+	ResultsQueueName  string `mapstructure:"results_queue_name"`
+	ConsumerTag       string `mapstructure:"consumer_tag"`
+	PrefetchCount     int    `mapstructure:"prefetch_count"`
+}
+
+// OrgExport — выгрузка чатов и памяти организации по запросам core-service (в отличие от Export - экспорта одного чата)
+type OrgExport struct {
+	// Пустой URL - запросы на выгрузку не принимаются
+	RabbitMQURL       string `mapstructure:"rabbitmq_url"`
+	RequestsQueueName string `mapstructure:"requests_queue_name"`
+	ResultsQueueName  string `mapstructure:"results_queue_name"`
+	ConsumerTag       string `mapstructure:"consumer_tag"`
+	PrefetchCount     int    `mapstructure:"prefetch_count"`
+}
+
+// Transcription — распознавание голосовых сообщений
+type Transcription struct {
+	// openai - OpenAI-совместимый /audio/transcriptions, whisper_server - локальный whisper.cpp server,
+	// пусто - голосовые сообщения не принимаются
+	Provider string        `mapstructure:"provider"`
+	BaseURL  string        `mapstructure:"base_url"`
+	APIKey   string        `mapstructure:"api_key"`
+	Model    string        `mapstructure:"model"`
+	Language string        `mapstructure:"language"`
+	Timeout  time.Duration `mapstructure:"timeout"`
+}
+
+// TelegramBot — чат с агентом через Telegram-бота
+type TelegramBot struct {
+	Enabled  bool   `mapstructure:"enabled"`
+	BotToken string `mapstructure:"bot_token"`
+	APIURL   string `mapstructure:"api_url"`
+	// Ссылка на mini app: регистрация и работа с организациями
+	WebAppURL string `mapstructure:"webapp_url"`
+	// Как часто обновлять сообщение с ответом во время генерации
+	EditInterval time.Duration `mapstructure:"edit_interval"`
+	// Таймаут long polling getUpdates
+	PollTimeout time.Duration `mapstructure:"poll_timeout"`
+}
+
 // Config holds all runtime-configurable settings
 type Config struct {
 	mu sync.RWMutex
@@ -111,6 +252,16 @@ type Config struct {
 	Tavily        Tavily        `mapstructure:"tavily"`
 	AmoCRMMCP     AmoCRMMCP     `mapstructure:"amocrm_mcp"`
 	S3            S3            `mapstructure:"s3"`
+	Chats         Chats         `mapstructure:"chats"`
+	Authz         Authz         `mapstructure:"authz"`
+	Export        Export        `mapstructure:"export"`
+	Scheduler     Scheduler     `mapstructure:"scheduler"`
+	Notifications Notifications `mapstructure:"notifications"`
+	Audit         Audit         `mapstructure:"audit"`
+	Erasure       Erasure       `mapstructure:"erasure"`
+	OrgExport     OrgExport     `mapstructure:"organization_export"`
+	TelegramBot   TelegramBot   `mapstructure:"telegram_bot"`
+	Transcription Transcription `mapstructure:"transcription"`
 }
 
 var (
@@ -170,10 +321,50 @@ func (c *Config) loadDefaults() {
 	viper.SetDefault("jaeger.endpoint", "http://localhost:14268/api/traces")
 	viper.SetDefault("db.ssl_mode", "disable")
 	viper.SetDefault("llm.token_limit", 500000)
+	viper.SetDefault("llm.retry.max_attempts", 3)
+	viper.SetDefault("llm.retry.initial_backoff", "500ms")
+	viper.SetDefault("llm.retry.max_backoff", "8s")
+	viper.SetDefault("llm.health.failure_threshold", 3)
+	viper.SetDefault("llm.health.cooldown", "30s")
+	viper.SetDefault("llm.fact_extraction_workers", 2)
+	viper.SetDefault("llm.fact_extraction_queue_size", 100)
 	viper.SetDefault("jwt.secret", "")
 	viper.SetDefault("core_service.address", "localhost:50051")
 	viper.SetDefault("docs_processor.address", "localhost:50052")
 	viper.SetDefault("amocrm_mcp.address", "localhost:50053")
+	viper.SetDefault("chats.admin_visibility", false)
+	viper.SetDefault("authz.membership_cache_ttl", "30s")
+
+	viper.SetDefault("export.pdf_font_path", "/usr/share/fonts/dejavu/DejaVuSans.ttf")
+	viper.SetDefault("export.pdf_bold_font_path", "/usr/share/fonts/dejavu/DejaVuSans-Bold.ttf")
+
+	viper.SetDefault("scheduler.enabled", true)
+	viper.SetDefault("scheduler.poll_interval", "30s")
+	viper.SetDefault("scheduler.run_timeout", "10m")
+	viper.SetDefault("scheduler.min_interval", "1h")
+	viper.SetDefault("scheduler.max_schedules_per_user", 20)
+	viper.SetDefault("scheduler.batch_size", 5)
+	viper.SetDefault("scheduler.service_token", "")
+	viper.SetDefault("notifications.queue_name", "notifications")
+	viper.SetDefault("audit.queue_name", "audit_events")
+	viper.SetDefault("erasure.requests_queue_name", "llm_erasure_requests")
+	viper.SetDefault("erasure.results_queue_name", "erasure_results")
+	viper.SetDefault("erasure.consumer_tag", "llm-service-erasure")
+	viper.SetDefault("erasure.prefetch_count", 1)
+	viper.SetDefault("organization_export.requests_queue_name", "llm_export_requests")
+	viper.SetDefault("organization_export.results_queue_name", "export_results")
+	viper.SetDefault("organization_export.consumer_tag", "llm-service-export")
+	viper.SetDefault("organization_export.prefetch_count", 1)
+
+	viper.SetDefault("telegram_bot.enabled", false)
+	viper.SetDefault("telegram_bot.api_url", "https://api.telegram.org")
+	viper.SetDefault("telegram_bot.edit_interval", "1500ms")
+	viper.SetDefault("telegram_bot.poll_timeout", "30s")
+
+	viper.SetDefault("transcription.base_url", "https://api.openai.com/v1")
+	viper.SetDefault("transcription.model", "whisper-1")
+	viper.SetDefault("transcription.language", "ru")
+	viper.SetDefault("transcription.timeout", "60s")
 }
 
 // reload reads the config file and updates all values
@@ -307,6 +498,355 @@ func (c *Config) GetLLMTitleGenerationModel() string {
 	return c.LLM.TitleGenerationModel
 }
 
+// GetLLMFactExtraction reports whether facts are extracted from user messages automatically
+func (c *Config) GetLLMFactExtraction() bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.LLM.FactExtraction
+}
+
+// GetLLMFactExtractionWorkers returns how many fact extractions run concurrently
+func (c *Config) GetLLMFactExtractionWorkers() int {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.LLM.FactExtractionWorkers
+}
+
+// GetLLMFactExtractionQueueSize returns how many messages may wait for fact extraction
+func (c *Config) GetLLMFactExtractionQueueSize() int {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.LLM.FactExtractionQueueSize
+}
+
+// GetChatsAdminVisibility reports whether members with chats.view_all can read all organization chats
+func (c *Config) GetChatsAdminVisibility() bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.Chats.AdminVisibility
+}
+
+// GetAuthzMembershipCacheTTL returns how long memberships looked up in core-service are cached
+func (c *Config) GetAuthzMembershipCacheTTL() time.Duration {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.Authz.MembershipCacheTTL
+}
+
+// GetExportPDFFontPath returns the regular TTF font used for PDF exports
+func (c *Config) GetExportPDFFontPath() string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.Export.PDFFontPath
+}
+
+// GetExportPDFBoldFontPath returns the bold TTF font used for PDF exports
+func (c *Config) GetExportPDFBoldFontPath() string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.Export.PDFBoldFontPath
+}
+
+// GetSchedulerEnabled reports whether the scheduled agent runs worker is started
+func (c *Config) GetSchedulerEnabled() bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.Scheduler.Enabled
+}
+
+// GetSchedulerPollInterval returns how often the worker looks for due schedules
+func (c *Config) GetSchedulerPollInterval() time.Duration {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.Scheduler.PollInterval
+}
+
+// GetSchedulerRunTimeout returns the time limit for a single scheduled run
+func (c *Config) GetSchedulerRunTimeout() time.Duration {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.Scheduler.RunTimeout
+}
+
+// GetSchedulerMinInterval returns the minimal allowed interval between runs of one schedule
+func (c *Config) GetSchedulerMinInterval() time.Duration {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.Scheduler.MinInterval
+}
+
+// GetSchedulerMaxSchedulesPerUser returns the schedule limit per user in an organization
+func (c *Config) GetSchedulerMaxSchedulesPerUser() int {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.Scheduler.MaxSchedulesPerUser
+}
+
+// GetSchedulerBatchSize returns how many due schedules the worker claims per poll
+func (c *Config) GetSchedulerBatchSize() int {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.Scheduler.BatchSize
+}
+
+// GetSchedulerServiceToken returns the token the worker uses to act on behalf of schedule owners in core-service
+func (c *Config) GetSchedulerServiceToken() string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.Scheduler.ServiceToken
+}
+
+// GetNotificationsRabbitMQURL returns the RabbitMQ URL for notification events; empty disables publishing
+func (c *Config) GetNotificationsRabbitMQURL() string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.Notifications.RabbitMQURL
+}
+
+// GetNotificationsQueueName returns the queue consumed by the core-service notification service
+func (c *Config) GetNotificationsQueueName() string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.Notifications.QueueName
+}
+
+// GetAuditRabbitMQURL returns the RabbitMQ URL for audit events; empty disables publishing
+func (c *Config) GetAuditRabbitMQURL() string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.Audit.RabbitMQURL
+}
+
+// GetAuditQueueName returns the queue consumed by the core-service audit log
+func (c *Config) GetAuditQueueName() string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.Audit.QueueName
+}
+
+// GetErasureRabbitMQURL returns the RabbitMQ URL for erasure requests; empty disables the consumer
+func (c *Config) GetErasureRabbitMQURL() string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.Erasure.RabbitMQURL
+}
+
+// GetErasureRequestsQueueName returns the queue core-service publishes erasure requests to
+func (c *Config) GetErasureRequestsQueueName() string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.Erasure.RequestsQueueName
+}
+
+// GetErasureResultsQueueName returns the queue core-service reads erasure step results from
+func (c *Config) GetErasureResultsQueueName() string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.Erasure.ResultsQueueName
+}
+
+// GetErasureConsumerTag returns the consumer tag of the erasure requests consumer
+func (c *Config) GetErasureConsumerTag() string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.Erasure.ConsumerTag
+}
+
+// GetErasurePrefetchCount returns how many erasure requests are processed concurrently
+func (c *Config) GetErasurePrefetchCount() int {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	if c.Erasure.PrefetchCount <= 0 {
+		return 1
+	}
+	return c.Erasure.PrefetchCount
+}
+
+// GetOrgExportRabbitMQURL returns the RabbitMQ URL for organization export requests; empty disables the consumer
+func (c *Config) GetOrgExportRabbitMQURL() string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.OrgExport.RabbitMQURL
+}
+
+// GetOrgExportRequestsQueueName returns the queue core-service publishes export requests to
+func (c *Config) GetOrgExportRequestsQueueName() string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.OrgExport.RequestsQueueName
+}
+
+// GetOrgExportResultsQueueName returns the queue core-service reads exported files from
+func (c *Config) GetOrgExportResultsQueueName() string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.OrgExport.ResultsQueueName
+}
+
+// GetOrgExportConsumerTag returns the consumer tag of the export requests consumer
+func (c *Config) GetOrgExportConsumerTag() string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.OrgExport.ConsumerTag
+}
+
+// GetOrgExportPrefetchCount returns how many export requests are processed concurrently
+func (c *Config) GetOrgExportPrefetchCount() int {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	if c.OrgExport.PrefetchCount <= 0 {
+		return 1
+	}
+	return c.OrgExport.PrefetchCount
+}
+
+// GetTelegramBotEnabled reports whether the Telegram bot chat mode is started
+func (c *Config) GetTelegramBotEnabled() bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.TelegramBot.Enabled
+}
+
+// GetTelegramBotToken returns the Telegram Bot API token
+func (c *Config) GetTelegramBotToken() string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.TelegramBot.BotToken
+}
+
+// GetTelegramBotAPIURL returns the Telegram Bot API base URL
+func (c *Config) GetTelegramBotAPIURL() string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.TelegramBot.APIURL
+}
+
+// GetTelegramBotWebAppURL returns the mini app URL shown to users in bot messages
+func (c *Config) GetTelegramBotWebAppURL() string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.TelegramBot.WebAppURL
+}
+
+// GetTelegramBotEditInterval returns the minimal interval between edits of a streamed reply
+func (c *Config) GetTelegramBotEditInterval() time.Duration {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.TelegramBot.EditInterval
+}
+
+// GetTelegramBotPollTimeout returns the long polling timeout for getUpdates
+func (c *Config) GetTelegramBotPollTimeout() time.Duration {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.TelegramBot.PollTimeout
+}
+
+// GetTranscriptionProvider returns the speech-to-text backend: openai, whisper_server or empty when disabled
+func (c *Config) GetTranscriptionProvider() string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.Transcription.Provider
+}
+
+// GetTranscriptionBaseURL returns the speech-to-text service URL
+func (c *Config) GetTranscriptionBaseURL() string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.Transcription.BaseURL
+}
+
+// GetTranscriptionAPIKey returns the API key of the OpenAI-compatible speech-to-text service
+func (c *Config) GetTranscriptionAPIKey() string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.Transcription.APIKey
+}
+
+// GetTranscriptionModel returns the speech-to-text model name
+func (c *Config) GetTranscriptionModel() string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.Transcription.Model
+}
+
+// GetTranscriptionLanguage returns the expected language of voice messages
+func (c *Config) GetTranscriptionLanguage() string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.Transcription.Language
+}
+
+// GetTranscriptionTimeout returns the time limit for transcribing one audio file
+func (c *Config) GetTranscriptionTimeout() time.Duration {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.Transcription.Timeout
+}
+
+// GetLLMProviders returns configured LLM backends.
+// Falls back to a single OpenAI-compatible backend built from base llm settings;
+// its model and reasoning effort are left empty to keep reading them from config.
+func (c *Config) GetLLMProviders() []LLMProvider {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if len(c.LLM.Providers) == 0 {
+		return []LLMProvider{{
+			Name:    string(LLMProviderOpenAI),
+			Type:    LLMProviderOpenAI,
+			BaseURL: c.LLM.BaseURL,
+			APIKey:  c.LLM.APIKey,
+		}}
+	}
+
+	providers := make([]LLMProvider, len(c.LLM.Providers))
+	copy(providers, c.LLM.Providers)
+	return providers
+}
+
+// GetLLMRetryMaxAttempts returns the number of attempts per LLM backend
+func (c *Config) GetLLMRetryMaxAttempts() int {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.LLM.Retry.MaxAttempts
+}
+
+// GetLLMRetryInitialBackoff returns the delay before the first retry
+func (c *Config) GetLLMRetryInitialBackoff() time.Duration {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.LLM.Retry.InitialBackoff
+}
+
+// GetLLMRetryMaxBackoff returns the upper bound of the retry delay
+func (c *Config) GetLLMRetryMaxBackoff() time.Duration {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.LLM.Retry.MaxBackoff
+}
+
+// GetLLMHealthFailureThreshold returns consecutive failures after which a backend is skipped
+func (c *Config) GetLLMHealthFailureThreshold() int {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.LLM.Health.FailureThreshold
+}
+
+// GetLLMHealthCooldown returns how long an unhealthy backend is skipped
+func (c *Config) GetLLMHealthCooldown() time.Duration {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.LLM.Health.Cooldown
+}
+
+// GetLLMRecordPath returns the cassette file LLM interactions are recorded to
+func (c *Config) GetLLMRecordPath() string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.LLM.RecordPath
+}
+
 // GetJWTSecret returns the JWT secret from config
 func (c *Config) GetJWTSecret() string {
 	c.mu.RLock()
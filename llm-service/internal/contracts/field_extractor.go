@@ -0,0 +1,174 @@
+package contracts
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"llm-service/internal/llm"
+
+	"github.com/opentracing/opentracing-go"
+)
+
+const fieldExtractionPrompt = `Ты извлекаешь значения полей договора из текста пользователя (реквизиты сторон, условия сделки и т.п.).
+Заполняй поле только если значение явно есть в тексте, ничего не придумывай. Если значения нет, верни null.
+Даты возвращай в формате ДД.ММ.ГГГГ, числа - без пробелов и единиц измерения.`
+
+// FieldExtractor извлекает значения полей шаблона из свободного текста через LLM
+type FieldExtractor struct {
+	llmProvider llm.CompletionProvider
+}
+
+func NewFieldExtractor(llmProvider llm.CompletionProvider) *FieldExtractor {
+	return &FieldExtractor{llmProvider: llmProvider}
+}
+
+// ExtractFields возвращает значения полей, найденные в source.
+// Поля, которых нет в тексте, в результат не попадают.
+func (e *FieldExtractor) ExtractFields(ctx context.Context, fields []TemplateField, source string) (map[string]interface{}, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "contracts.FieldExtractor.ExtractFields")
+	defer span.Finish()
+
+	if len(fields) == 0 || strings.TrimSpace(source) == "" {
+		return map[string]interface{}{}, nil
+	}
+
+	params := llm.ChatParams{
+		Messages: []llm.MessageParam{
+			{Role: llm.RoleSystem, Content: fieldExtractionPrompt},
+			{Role: llm.RoleUser, Content: source},
+		},
+	}
+
+	values, _, err := llm.CompleteStructured(ctx, e.llmProvider, params, fieldsResponseFormat(fields), func(values map[string]interface{}) error {
+		return checkFieldValues(fields, values)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to extract contract fields: %w", err)
+	}
+
+	result := make(map[string]interface{}, len(values))
+	for name, value := range values {
+		if value != nil {
+			result[name] = value
+		}
+	}
+
+	return result, nil
+}
+
+// fieldsResponseFormat строит JSON Schema ответа по полям шаблона.
+// Все поля обязательны и допускают null - так схема подходит для strict режима.
+func fieldsResponseFormat(fields []TemplateField) llm.ResponseFormat {
+	properties := make(map[string]any, len(fields))
+	required := make([]string, 0, len(fields))
+
+	for _, field := range fields {
+		property := map[string]any{
+			"type": []string{fieldJSONType(field.Type), "null"},
+		}
+
+		description := field.Label
+		if field.Description != "" {
+			description = strings.TrimSpace(description + ". " + field.Description)
+		}
+		if description != "" {
+			property["description"] = description
+		}
+
+		properties[field.Name] = property
+		required = append(required, field.Name)
+	}
+
+	return llm.ResponseFormat{
+		Name:        "contract_fields",
+		Description: "Значения полей шаблона договора",
+		Schema: map[string]any{
+			"type":                 "object",
+			"properties":           properties,
+			"required":             required,
+			"additionalProperties": false,
+		},
+		Strict: true,
+	}
+}
+
+// fieldJSONType маппит тип поля шаблона в тип JSON Schema; даты и прочее передаются строками
+func fieldJSONType(fieldType string) string {
+	switch fieldType {
+	case "number":
+		return "number"
+	case "integer":
+		return "integer"
+	case "boolean":
+		return "boolean"
+	default:
+		return "string"
+	}
+}
+
+// checkFieldValues проверяет извлеченные значения по типам и ограничениям шаблона.
+// Min/Max ограничивают длину строки или значение числа.
+func checkFieldValues(fields []TemplateField, values map[string]interface{}) error {
+	known := make(map[string]TemplateField, len(fields))
+	for _, field := range fields {
+		known[field.Name] = field
+	}
+
+	for name, value := range values {
+		field, ok := known[name]
+		if !ok {
+			return fmt.Errorf("unknown field '%s'", name)
+		}
+		if value == nil {
+			continue
+		}
+
+		switch fieldJSONType(field.Type) {
+		case "number", "integer":
+			number, ok := value.(float64)
+			if !ok {
+				return fmt.Errorf("field '%s' must be a number", name)
+			}
+			if field.Min != nil && number < float64(*field.Min) {
+				return fmt.Errorf("field '%s' must be at least %d", name, *field.Min)
+			}
+			if field.Max != nil && number > float64(*field.Max) {
+				return fmt.Errorf("field '%s' must be at most %d", name, *field.Max)
+			}
+		case "boolean":
+			if _, ok := value.(bool); !ok {
+				return fmt.Errorf("field '%s' must be a boolean", name)
+			}
+		default:
+			text, ok := value.(string)
+			if !ok {
+				return fmt.Errorf("field '%s' must be a string", name)
+			}
+			length := utf8.RuneCountInString(text)
+			if field.Min != nil && length < *field.Min {
+				return fmt.Errorf("field '%s' must be at least %d characters", name, *field.Min)
+			}
+			if field.Max != nil && length > *field.Max {
+				return fmt.Errorf("field '%s' must be at most %d characters", name, *field.Max)
+			}
+		}
+	}
+
+	return nil
+}
+
+// parseFieldsSchema разбирает fields_schema шаблона
+func parseFieldsSchema(fieldsSchemaJSON string) ([]TemplateField, error) {
+	var schema struct {
+		Fields []TemplateField `json:"fields"`
+	}
+
+	if err := json.Unmarshal([]byte(fieldsSchemaJSON), &schema); err != nil {
+		return nil, fmt.Errorf("failed to parse fields schema: %w", err)
+	}
+
+	return schema.Fields, nil
+}
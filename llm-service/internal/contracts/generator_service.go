@@ -19,17 +19,20 @@ type GeneratorService struct {
 	coreServiceClient coreservice.Client
 	s3Client          *storage.S3Client
 	docxProcessor     *docx.Processor
+	fieldExtractor    *FieldExtractor
 }
 
 func NewGeneratorService(
 	coreServiceClient coreservice.Client,
 	s3Client *storage.S3Client,
 	docxProcessor *docx.Processor,
+	fieldExtractor *FieldExtractor,
 ) *GeneratorService {
 	return &GeneratorService{
 		coreServiceClient: coreServiceClient,
 		s3Client:          s3Client,
 		docxProcessor:     docxProcessor,
+		fieldExtractor:    fieldExtractor,
 	}
 }
 
@@ -100,16 +103,13 @@ func (s *GeneratorService) GenerateContract(
 }
 
 func (s *GeneratorService) validateFilledData(fieldsSchemaJSON string, filledData map[string]interface{}) error {
-	var schema struct {
-		Fields []TemplateField `json:"fields"`
-	}
-
-	if err := json.Unmarshal([]byte(fieldsSchemaJSON), &schema); err != nil {
-		return fmt.Errorf("failed to parse fields schema: %w", err)
+	fields, err := parseFieldsSchema(fieldsSchemaJSON)
+	if err != nil {
+		return err
 	}
 
 	// Проверяем обязательные поля
-	for _, field := range schema.Fields {
+	for _, field := range fields {
 		if field.Required {
 			if _, ok := filledData[field.Name]; !ok {
 				return fmt.Errorf("required field '%s' is missing", field.Name)
@@ -120,6 +120,28 @@ func (s *GeneratorService) validateFilledData(fieldsSchemaJSON string, filledDat
 	return nil
 }
 
+// ExtractFields извлекает значения полей шаблона из свободного текста (реквизиты, описание сделки)
+func (s *GeneratorService) ExtractFields(ctx context.Context, templateID, source string) (map[string]interface{}, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "contracts.GeneratorService.ExtractFields")
+	defer span.Finish()
+
+	if s.fieldExtractor == nil {
+		return nil, fmt.Errorf("field extraction is not configured")
+	}
+
+	template, err := s.coreServiceClient.GetTemplate(ctx, templateID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get template: %w", err)
+	}
+
+	fields, err := parseFieldsSchema(template.FieldsSchema)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.fieldExtractor.ExtractFields(ctx, fields, source)
+}
+
 // ListContracts возвращает список сгенерированных договоров
 func (s *GeneratorService) ListContracts(ctx context.Context, organizationID string, limit, offset int) ([]*ContractListItem, int, error) {
 	span, ctx := opentracing.StartSpanFromContext(ctx, "contracts.GeneratorService.ListContracts")
@@ -2,19 +2,49 @@ package coreservice
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"strconv"
+	"sync"
 	"time"
 
 	pb "llm-service/pkg/core"
 
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
 )
 
 type Client interface {
 	GetTemplate(ctx context.Context, templateID string) (*Template, error)
 	RegisterContract(ctx context.Context, organizationID, templateID, name, filledData, s3Key, fileType string) (*Contract, error)
 	ListContracts(ctx context.Context, organizationID string, limit, offset int) ([]*Contract, int, error)
+	RegisterDocument(ctx context.Context, organizationID, name, s3Key, fileType string, fileSize int64) (*Document, error)
+	ListOrganizationMembers(ctx context.Context, organizationID string) ([]*Member, error)
+	GetOrganizationProfile(ctx context.Context, organizationID string) (*OrganizationProfile, error)
+	GenerateDownloadURL(ctx context.Context, s3Key string) (string, error)
+	AuthenticateBotUser(ctx context.Context, botToken, telegramID string) (*BotSession, error)
+	AuthenticateServiceUser(ctx context.Context, serviceToken, userID string) (*ServiceSession, error)
+}
+
+// MemberRole - роль пользователя в организации
+type MemberRole string
+
+const (
+	MemberRoleAdmin    MemberRole = "admin"
+	MemberRoleEmployee MemberRole = "employee"
+)
+
+// Member - участник организации
+type Member struct {
+	UserID    string
+	Email     string
+	FirstName string
+	LastName  string
+	Role      MemberRole
+	Active    bool
+	// Permissions - действующие права в организации (встроенной или собственной роли)
+	Permissions []string
 }
 
 type Template struct {
@@ -39,22 +69,90 @@ type Contract struct {
 	CreatedAt      time.Time
 }
 
+type Document struct {
+	ID             string
+	OrganizationID string
+	Name           string
+	S3Key          string
+	FileType       string
+	FileSize       int64
+	CreatedAt      time.Time
+}
+
+// BotSession - токен, выданный Telegram-боту для работы от имени пользователя
+type BotSession struct {
+	AccessToken   string
+	ExpiresAt     time.Time
+	UserID        string
+	FirstName     string
+	Organizations []*Organization
+}
+
+// ServiceSession - токен пользователя, выданный внутреннему сервису для фоновой работы от его имени
+type ServiceSession struct {
+	AccessToken string
+	ExpiresAt   time.Time
+}
+
+// Organization - организация, в которой пользователь активный участник
+type Organization struct {
+	ID   string
+	Name string
+}
+
+// OrganizationProfile - карточка организации и поля анкеты, проверенные core-service по схеме
+type OrganizationProfile struct {
+	Name        string
+	Industry    string
+	Region      string
+	Description string
+	Fields      []ProfileField
+}
+
+// ProfileField - заполненное поле анкеты с названием из схемы
+type ProfileField struct {
+	Key   string
+	Title string
+	Value string
+}
+
+// profileSchemaVersionKey - поле анкеты с версией схемы, по которой core-service ее проверил
+const profileSchemaVersionKey = "schema_version"
+
 type grpcClient struct {
 	conn                  *grpc.ClientConn
+	organizationClient    pb.OrganizationServiceClient
 	templateServiceClient pb.ContractTemplateServiceClient
 	contractServiceClient pb.GeneratedContractServiceClient
+	documentServiceClient pb.DocumentServiceClient
+	userServiceClient     pb.UserServiceClient
+	storageServiceClient  pb.StorageServiceClient
+	authServiceClient     pb.AuthServiceClient
+
+	// Схема анкеты меняется только с версией core-service, поэтому запрашивается один раз
+	schemaMu      sync.Mutex
+	profileSchema *pb.OrganizationProfileSchema
 }
 
 func NewClient(address string) (Client, error) {
-	conn, err := grpc.NewClient(address, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	conn, err := grpc.NewClient(
+		address,
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithUnaryInterceptor(forwardAuthInterceptor),
+	)
 	if err != nil {
 		return nil, fmt.Errorf("failed to connect to core-service: %w", err)
 	}
 
 	return &grpcClient{
 		conn:                  conn,
+		organizationClient:    pb.NewOrganizationServiceClient(conn),
 		templateServiceClient: pb.NewContractTemplateServiceClient(conn),
 		contractServiceClient: pb.NewGeneratedContractServiceClient(conn),
+		documentServiceClient: pb.NewDocumentServiceClient(conn),
+		userServiceClient:     pb.NewUserServiceClient(conn),
+		storageServiceClient:  pb.NewStorageServiceClient(conn),
+		authServiceClient:     pb.NewAuthServiceClient(conn),
 	}, nil
 }
 
@@ -135,3 +233,235 @@ func (c *grpcClient) ListContracts(ctx context.Context, organizationID string, l
 
 	return contracts, int(resp.Total), nil
 }
+
+func (c *grpcClient) RegisterDocument(ctx context.Context, organizationID, name, s3Key, fileType string, fileSize int64) (*Document, error) {
+	resp, err := c.documentServiceClient.RegisterDocument(ctx, &pb.RegisterDocumentRequest{
+		OrganizationId: organizationID,
+		Name:           name,
+		S3Key:          s3Key,
+		FileType:       fileType,
+		FileSize:       fileSize,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &Document{
+		ID:             resp.Document.Id,
+		OrganizationID: resp.Document.OrganizationId,
+		Name:           resp.Document.Name,
+		S3Key:          resp.Document.S3Key,
+		FileType:       resp.Document.FileType,
+		FileSize:       resp.Document.FileSize,
+		CreatedAt:      resp.Document.CreatedAt.AsTime(),
+	}, nil
+}
+
+// GenerateDownloadURL возвращает presigned ссылку на скачивание файла из S3
+func (c *grpcClient) GenerateDownloadURL(ctx context.Context, s3Key string) (string, error) {
+	resp, err := c.storageServiceClient.GenerateDownloadURL(ctx, &pb.GenerateDownloadURLRequest{
+		S3Key: s3Key,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return resp.GetDownloadUrl(), nil
+}
+
+// membersPageSize - максимальный размер страницы ListUsers в core-service
+const membersPageSize = 100
+
+// ListOrganizationMembers возвращает всех участников организации, запрашивая ListUsers постранично
+func (c *grpcClient) ListOrganizationMembers(ctx context.Context, organizationID string) ([]*Member, error) {
+	var members []*Member
+	for page := 1; ; page++ {
+		resp, err := c.userServiceClient.ListUsers(ctx, &pb.ListUsersRequest{
+			OrganizationId: organizationID,
+			Page:           int32(page),
+			PageSize:       membersPageSize,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		for _, u := range resp.Users {
+			role := MemberRoleEmployee
+			if u.Role == pb.UserRole_USER_ROLE_ADMIN {
+				role = MemberRoleAdmin
+			}
+
+			members = append(members, &Member{
+				UserID:      u.Id,
+				Email:       u.Email,
+				FirstName:   u.FirstName,
+				LastName:    u.LastName,
+				Role:        role,
+				Active:      u.Status == pb.UserStatus_USER_STATUS_ACTIVE,
+				Permissions: u.Permissions,
+			})
+		}
+
+		if len(resp.Users) < membersPageSize || len(members) >= int(resp.Total) {
+			return members, nil
+		}
+	}
+}
+
+// GetOrganizationProfile возвращает карточку организации и заполненные поля анкеты.
+// Анкеты, сохраненные до появления схемы или по другой ее версии, не проверены и пропускаются
+func (c *grpcClient) GetOrganizationProfile(ctx context.Context, organizationID string) (*OrganizationProfile, error) {
+	resp, err := c.organizationClient.GetOrganization(ctx, &pb.GetOrganizationRequest{
+		Id: organizationID,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	org := resp.GetOrganization()
+	profile := &OrganizationProfile{
+		Name:        org.GetName(),
+		Industry:    org.GetIndustry(),
+		Region:      org.GetRegion(),
+		Description: org.GetDescription(),
+	}
+
+	var values map[string]json.RawMessage
+	if err := json.Unmarshal([]byte(org.GetProfileData()), &values); err != nil || values == nil {
+		return profile, nil
+	}
+	var version int32
+	if err := json.Unmarshal(values[profileSchemaVersionKey], &version); err != nil {
+		return profile, nil
+	}
+
+	schema, err := c.getProfileSchema(ctx, version)
+	if err != nil {
+		return nil, err
+	}
+	if schema.GetVersion() != version {
+		return profile, nil
+	}
+
+	for _, field := range schema.GetFields() {
+		raw, ok := values[field.GetKey()]
+		if !ok {
+			continue
+		}
+		value, ok := formatProfileValue(field, raw)
+		if !ok {
+			continue
+		}
+		profile.Fields = append(profile.Fields, ProfileField{
+			Key:   field.GetKey(),
+			Title: field.GetTitle(),
+			Value: value,
+		})
+	}
+
+	return profile, nil
+}
+
+// getProfileSchema возвращает схему анкеты; запрашивает ее заново, если анкета сохранена
+// по более новой версии, чем известная клиенту (core-service обновился)
+func (c *grpcClient) getProfileSchema(ctx context.Context, version int32) (*pb.OrganizationProfileSchema, error) {
+	c.schemaMu.Lock()
+	defer c.schemaMu.Unlock()
+
+	if c.profileSchema != nil && c.profileSchema.GetVersion() >= version {
+		return c.profileSchema, nil
+	}
+
+	resp, err := c.organizationClient.GetOrganizationProfileSchema(ctx, &pb.GetOrganizationProfileSchemaRequest{})
+	if err != nil {
+		return nil, err
+	}
+	c.profileSchema = resp.GetSchema()
+
+	return c.profileSchema, nil
+}
+
+// formatProfileValue приводит значение поля к тексту; значения перечислений заменяются названиями
+func formatProfileValue(field *pb.ProfileField, raw json.RawMessage) (string, bool) {
+	switch field.GetType() {
+	case pb.ProfileFieldType_PROFILE_FIELD_TYPE_STRING:
+		var value string
+		if err := json.Unmarshal(raw, &value); err != nil || value == "" {
+			return "", false
+		}
+		return value, true
+	case pb.ProfileFieldType_PROFILE_FIELD_TYPE_ENUM:
+		var value string
+		if err := json.Unmarshal(raw, &value); err != nil {
+			return "", false
+		}
+		for _, option := range field.GetOptions() {
+			if option.GetValue() == value {
+				return option.GetTitle(), true
+			}
+		}
+		return "", false
+	case pb.ProfileFieldType_PROFILE_FIELD_TYPE_INTEGER, pb.ProfileFieldType_PROFILE_FIELD_TYPE_NUMBER:
+		var value float64
+		if err := json.Unmarshal(raw, &value); err != nil {
+			return "", false
+		}
+		return strconv.FormatFloat(value, 'f', -1, 64), true
+	default:
+		return "", false
+	}
+}
+
+// AuthenticateBotUser получает токен пользователя по его Telegram ID; бот подтверждает себя своим токеном
+func (c *grpcClient) AuthenticateBotUser(ctx context.Context, botToken, telegramID string) (*BotSession, error) {
+	resp, err := c.authServiceClient.AuthenticateBotUser(ctx, &pb.AuthenticateBotUserRequest{
+		BotToken:   botToken,
+		TelegramId: telegramID,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	orgs := make([]*Organization, 0, len(resp.Organizations))
+	for _, o := range resp.Organizations {
+		orgs = append(orgs, &Organization{
+			ID:   o.Id,
+			Name: o.Name,
+		})
+	}
+
+	return &BotSession{
+		AccessToken:   resp.AccessToken,
+		ExpiresAt:     resp.ExpiresAt.AsTime(),
+		UserID:        resp.User.GetId(),
+		FirstName:     resp.User.GetFirstName(),
+		Organizations: orgs,
+	}, nil
+}
+
+// AuthenticateServiceUser получает токен пользователя для фоновой работы; сервис подтверждает себя своим токеном
+func (c *grpcClient) AuthenticateServiceUser(ctx context.Context, serviceToken, userID string) (*ServiceSession, error) {
+	resp, err := c.authServiceClient.AuthenticateServiceUser(ctx, &pb.AuthenticateServiceUserRequest{
+		ServiceToken: serviceToken,
+		UserId:       userID,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &ServiceSession{
+		AccessToken: resp.AccessToken,
+		ExpiresAt:   resp.ExpiresAt.AsTime(),
+	}, nil
+}
+
+// forwardAuthInterceptor передает токен пользователя из входящего запроса в core-service,
+// чтобы core-service проверял доступ от имени того же пользователя
+func forwardAuthInterceptor(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if values := md.Get("authorization"); len(values) > 0 {
+			ctx = metadata.AppendToOutgoingContext(ctx, "authorization", values[0])
+		}
+	}
+	return invoker(ctx, method, req, reply, cc, opts...)
+}
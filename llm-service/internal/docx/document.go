@@ -0,0 +1,156 @@
+package docx
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/xml"
+	"fmt"
+	"strings"
+
+	"github.com/opentracing/opentracing-go"
+)
+
+// ParagraphStyle - стиль абзаца из styles.xml
+type ParagraphStyle string
+
+const (
+	StyleNormal   ParagraphStyle = "Normal"
+	StyleTitle    ParagraphStyle = "Title"
+	StyleHeading1 ParagraphStyle = "Heading1"
+	StyleHeading2 ParagraphStyle = "Heading2"
+	StyleHeading3 ParagraphStyle = "Heading3"
+)
+
+// Run - фрагмент текста с одинаковым форматированием
+type Run struct {
+	Text   string
+	Bold   bool
+	Italic bool
+}
+
+// Paragraph - абзац документа
+type Paragraph struct {
+	Style ParagraphStyle
+	// Indent - уровень отступа слева (1 уровень = 1 см)
+	Indent int
+	Runs   []Run
+}
+
+// Document - простой документ из заголовков и абзацев, собираемый с нуля (без шаблона)
+type Document struct {
+	Paragraphs []Paragraph
+}
+
+// Add добавляет абзац
+func (d *Document) Add(style ParagraphStyle, indent int, runs ...Run) {
+	d.Paragraphs = append(d.Paragraphs, Paragraph{Style: style, Indent: indent, Runs: runs})
+}
+
+// indentTwips - 1 см в twips
+const indentTwips = 567
+
+// Render собирает DOCX файл из документа
+func (p *Processor) Render(ctx context.Context, doc *Document) ([]byte, error) {
+	span, _ := opentracing.StartSpanFromContext(ctx, "docx.Processor.Render")
+	defer span.Finish()
+
+	var body strings.Builder
+	for _, paragraph := range doc.Paragraphs {
+		writeParagraph(&body, paragraph)
+	}
+
+	files := []struct {
+		name    string
+		content string
+	}{
+		{"[Content_Types].xml", contentTypesXML},
+		{"_rels/.rels", relsXML},
+		{"word/styles.xml", stylesXML},
+		{"word/document.xml", fmt.Sprintf(documentXML, body.String())},
+	}
+
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for _, file := range files {
+		w, err := zw.Create(file.name)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create %s: %w", file.name, err)
+		}
+		if _, err := w.Write([]byte(file.content)); err != nil {
+			return nil, fmt.Errorf("failed to write %s: %w", file.name, err)
+		}
+	}
+	if err := zw.Close(); err != nil {
+		return nil, fmt.Errorf("failed to write DOCX: %w", err)
+	}
+
+	return buf.Bytes(), nil
+}
+
+func writeParagraph(b *strings.Builder, p Paragraph) {
+	b.WriteString("<w:p><w:pPr>")
+	if p.Style != "" && p.Style != StyleNormal {
+		fmt.Fprintf(b, `<w:pStyle w:val="%s"/>`, p.Style)
+	}
+	if p.Indent > 0 {
+		fmt.Fprintf(b, `<w:ind w:left="%d"/>`, p.Indent*indentTwips)
+	}
+	b.WriteString("</w:pPr>")
+
+	for _, run := range p.Runs {
+		b.WriteString("<w:r>")
+		if run.Bold || run.Italic {
+			b.WriteString("<w:rPr>")
+			if run.Bold {
+				b.WriteString("<w:b/>")
+			}
+			if run.Italic {
+				b.WriteString("<w:i/>")
+			}
+			b.WriteString("</w:rPr>")
+		}
+		// Переносы строк внутри абзаца
+		for i, line := range strings.Split(run.Text, "\n") {
+			if i > 0 {
+				b.WriteString("<w:br/>")
+			}
+			b.WriteString(`<w:t xml:space="preserve">`)
+			_ = xml.EscapeText(b, []byte(line))
+			b.WriteString("</w:t>")
+		}
+		b.WriteString("</w:r>")
+	}
+	b.WriteString("</w:p>")
+}
+
+const contentTypesXML = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">
+<Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/>
+<Default Extension="xml" ContentType="application/xml"/>
+<Override PartName="/word/document.xml" ContentType="application/vnd.openxmlformats-officedocument.wordprocessingml.document.main+xml"/>
+<Override PartName="/word/styles.xml" ContentType="application/vnd.openxmlformats-officedocument.wordprocessingml.styles+xml"/>
+</Types>`
+
+const relsXML = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">
+<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="word/document.xml"/>
+</Relationships>`
+
+const documentXML = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<w:document xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main">
+<w:body>%s<w:sectPr><w:pgSz w:w="11906" w:h="16838"/><w:pgMar w:top="1134" w:right="850" w:bottom="1134" w:left="1701" w:header="708" w:footer="708" w:gutter="0"/></w:sectPr></w:body>
+</w:document>`
+
+const stylesXML = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<w:styles xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main">
+<w:docDefaults>
+<w:rPrDefault><w:rPr><w:rFonts w:ascii="Calibri" w:hAnsi="Calibri" w:cs="Calibri"/><w:sz w:val="22"/><w:lang w:val="ru-RU"/></w:rPr></w:rPrDefault>
+<w:pPrDefault><w:pPr><w:spacing w:after="120" w:line="276" w:lineRule="auto"/></w:pPr></w:pPrDefault>
+</w:docDefaults>
+<w:style w:type="paragraph" w:default="1" w:styleId="Normal"><w:name w:val="Normal"/></w:style>
+<w:style w:type="paragraph" w:styleId="Title"><w:name w:val="Title"/><w:basedOn w:val="Normal"/><w:pPr><w:spacing w:after="240"/></w:pPr><w:rPr><w:b/><w:sz w:val="36"/></w:rPr></w:style>
+<w:style w:type="paragraph" w:styleId="Heading1"><w:name w:val="heading 1"/><w:basedOn w:val="Normal"/><w:pPr><w:keepNext/><w:spacing w:before="240"/><w:outlineLvl w:val="0"/></w:pPr><w:rPr><w:b/><w:sz w:val="30"/></w:rPr></w:style>
+<w:style w:type="paragraph" w:styleId="Heading2"><w:name w:val="heading 2"/><w:basedOn w:val="Normal"/><w:pPr><w:keepNext/><w:spacing w:before="200"/><w:outlineLvl w:val="1"/></w:pPr><w:rPr><w:b/><w:sz w:val="26"/></w:rPr></w:style>
+<w:style w:type="paragraph" w:styleId="Heading3"><w:name w:val="heading 3"/><w:basedOn w:val="Normal"/><w:pPr><w:keepNext/><w:spacing w:before="160"/><w:outlineLvl w:val="2"/></w:pPr><w:rPr><w:b/><w:color w:val="444444"/></w:rPr></w:style>
+</w:styles>`
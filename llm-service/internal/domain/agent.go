@@ -19,6 +19,8 @@ const (
 	ToolNameSearchContractTemplates ToolName = "search_contract_templates"
 	ToolNameGenerateContract        ToolName = "generate_contract"
 	ToolNameListGeneratedContracts  ToolName = "list_generated_contracts"
+	// Инструменты работы с файлами чата
+	ToolNameSearchChatFiles ToolName = "search_chat_files"
 )
 
 const AmoCRMMCPToolPrefix = "ammo-crm-"
@@ -79,6 +81,8 @@ type AgentDefinition struct {
 	AllowedTools     []ToolName `yaml:"allowed_tools" json:"allowed_tools"`
 	CanCallSubagents bool       `yaml:"can_call_subagents" json:"can_call_subagents"`
 	IsSubagent       bool       `yaml:"is_subagent" json:"is_subagent"`
+	// Model - опциональная модель агента; если пусто, используется модель провайдера по умолчанию
+	Model string `yaml:"model,omitempty" json:"model,omitempty"`
 }
 
 // GetSystemPrompt - возвращает system prompt для агента
@@ -132,9 +136,81 @@ type ExecutionContext struct {
 	OrganizationID    ID
 	UserID            ID
 	ChatID            ID
+	RootChatID        ID // основной чат пользователя; файлы чата индексируются по нему
 	AgentKey          string
 	TaskDescription   string // для субагентов - описание задачи от родителя
 	AdditionalContext map[string]any
+	// Role и Permissions - роль и права пользователя в организации; агент действует от его имени
+	// и не может вызвать инструмент, на который у пользователя нет права
+	Role        OrganizationRole
+	Permissions []Permission
+}
+
+// SetMembership переносит роль и права пользователя в контекст выполнения
+func (ec *ExecutionContext) SetMembership(member *Membership) {
+	if member == nil {
+		return
+	}
+	ec.Role = member.Role
+	ec.Permissions = member.Permissions
+}
+
+// HasPermission проверяет право пользователя, от имени которого работает агент; администратору доступно все
+func (ec *ExecutionContext) HasPermission(permission Permission) bool {
+	return ec.Role == OrganizationRoleAdmin || slices.Contains(ec.Permissions, permission)
+}
+
+// MissingToolPermission возвращает право, которого не хватает пользователю для вызова инструмента
+func (ec *ExecutionContext) MissingToolPermission(toolName string) (Permission, bool) {
+	permission, ok := RequiredToolPermission(toolName)
+	if !ok || ec.HasPermission(permission) {
+		return "", false
+	}
+	return permission, true
+}
+
+// RequiredToolPermission возвращает право пользователя, без которого агент не может вызвать инструмент.
+// Для инструментов amoCRM чтение (*_get) и изменение данных требуют разных прав
+func RequiredToolPermission(toolName string) (Permission, bool) {
+	if strings.HasPrefix(toolName, AmoCRMMCPToolPrefix) {
+		if strings.HasSuffix(toolName, "_get") {
+			return PermissionCRMRead, true
+		}
+		return PermissionCRMWrite, true
+	}
+
+	switch ToolName(toolName) {
+	case ToolNameSaveOrganizationNote:
+		return PermissionMemoryEdit, true
+	case ToolNameSearchContractTemplates, ToolNameGenerateContract:
+		return PermissionContractsGenerate, true
+	case ToolNameListGeneratedContracts:
+		return PermissionContractsRead, true
+	default:
+		return "", false
+	}
+}
+
+// IsWriteTool сообщает, изменяет ли инструмент данные организации; такие вызовы попадают в журнал аудита
+func IsWriteTool(toolName string) bool {
+	if strings.HasPrefix(toolName, AmoCRMMCPToolPrefix) {
+		return !strings.HasSuffix(toolName, "_get")
+	}
+
+	switch ToolName(toolName) {
+	case ToolNameSaveOrganizationNote, ToolNameGenerateContract:
+		return true
+	default:
+		return false
+	}
+}
+
+// FilesChatID возвращает ID чата, к которому привязаны файлы пользователя
+func (ec *ExecutionContext) FilesChatID() ID {
+	if ec.RootChatID == (ID{}) {
+		return ec.ChatID
+	}
+	return ec.RootChatID
 }
 
 // IsSubagentContext - проверяет, является ли контекст субагентом
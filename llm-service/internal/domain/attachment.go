@@ -0,0 +1,125 @@
+package domain
+
+import (
+	"fmt"
+	"strings"
+)
+
+const (
+	// MaxAttachmentsPerMessage ограничение количества вложений в одном сообщении
+	MaxAttachmentsPerMessage = 5
+	// MaxAttachmentSize максимальный размер одного вложения (байт)
+	MaxAttachmentSize = 10 << 20
+)
+
+// AttachmentType - тип вложения по тому, как оно передается модели
+type AttachmentType string
+
+const (
+	AttachmentTypeImage    AttachmentType = "image"
+	AttachmentTypeDocument AttachmentType = "document"
+	// AttachmentTypeAudio - голосовое сообщение, модели передается его расшифровка
+	AttachmentTypeAudio AttachmentType = "audio"
+)
+
+// AttachmentDelivery - способ передачи содержимого документа агенту
+type AttachmentDelivery string
+
+const (
+	// AttachmentDeliveryFile - файл передается модели целиком (изображения, сканы PDF)
+	AttachmentDeliveryFile AttachmentDelivery = "file"
+	// AttachmentDeliveryInline - извлеченный текст небольшого документа встраивается в сообщение
+	AttachmentDeliveryInline AttachmentDelivery = "inline"
+	// AttachmentDeliveryIndex - документ проиндексирован во временный индекс чата,
+	// агент ищет по нему инструментом search_chat_files
+	AttachmentDeliveryIndex AttachmentDelivery = "index"
+	// AttachmentDeliveryTranscript - аудио распознано, модели передается текст расшифровки
+	AttachmentDeliveryTranscript AttachmentDelivery = "transcript"
+)
+
+const docxContentType = "application/vnd.openxmlformats-officedocument.wordprocessingml.document"
+
+// attachmentContentTypes - допустимые MIME типы вложений
+var attachmentContentTypes = map[string]AttachmentType{
+	"image/jpeg":      AttachmentTypeImage,
+	"image/png":       AttachmentTypeImage,
+	"image/webp":      AttachmentTypeImage,
+	"image/gif":       AttachmentTypeImage,
+	"application/pdf": AttachmentTypeDocument,
+	docxContentType:   AttachmentTypeDocument,
+	"text/plain":      AttachmentTypeDocument,
+	"audio/ogg":       AttachmentTypeAudio,
+	"audio/mpeg":      AttachmentTypeAudio,
+	"audio/mp4":       AttachmentTypeAudio,
+	"audio/x-m4a":     AttachmentTypeAudio,
+	"audio/wav":       AttachmentTypeAudio,
+	"audio/x-wav":     AttachmentTypeAudio,
+	"audio/webm":      AttachmentTypeAudio,
+}
+
+// documentTypes - типы документов docs-processor по MIME типу
+var documentTypes = map[string]string{
+	"application/pdf": "pdf",
+	docxContentType:   "docx",
+	"text/plain":      "txt",
+}
+
+// Attachment - файл, приложенный к сообщению пользователя (фото счета, скан документа).
+// Сам файл лежит в S3, загружается клиентом через GenerateUploadURL core-service.
+// Документы индексируются docs-processor во временный индекс чата и удаляются вместе с чатом,
+// если пользователь не перенес их в базу знаний организации. Голосовые сообщения распознаются
+// до сохранения сообщения: расшифровка хранится в Text рядом со ссылкой на исходный файл.
+type Attachment struct {
+	ID          ID                 `json:"id"`
+	S3Key       string             `json:"s3_key"`
+	FileName    string             `json:"file_name"`
+	ContentType string             `json:"content_type"`
+	Size        int64              `json:"size"`
+	Delivery    AttachmentDelivery `json:"delivery,omitempty"`
+	Text        string             `json:"text,omitempty"`        // текст документа при Delivery = inline, расшифровка при transcript
+	DocumentID  string             `json:"document_id,omitempty"` // документ базы знаний после переноса
+}
+
+// IsTranscribed - голосовое вложение уже распознано
+func (a Attachment) IsTranscribed() bool {
+	return a.Delivery == AttachmentDeliveryTranscript
+}
+
+// Type возвращает тип вложения по MIME типу
+func (a Attachment) Type() AttachmentType {
+	return attachmentContentTypes[a.ContentType]
+}
+
+// DocumentType возвращает тип документа для docs-processor (pdf, docx, txt)
+func (a Attachment) DocumentType() string {
+	return documentTypes[a.ContentType]
+}
+
+// IsPromoted - документ перенесен в базу знаний организации
+func (a Attachment) IsPromoted() bool {
+	return a.DocumentID != ""
+}
+
+// Validate проверяет тип и размер вложения
+func (a Attachment) Validate() error {
+	if _, ok := attachmentContentTypes[a.ContentType]; !ok {
+		return NewInvalidArgumentError(fmt.Sprintf("attachment %s: unsupported content type %q", a.FileName, a.ContentType))
+	}
+	if a.Size <= 0 {
+		return NewInvalidArgumentError(fmt.Sprintf("attachment %s is empty", a.FileName))
+	}
+	if a.Size > MaxAttachmentSize {
+		return NewInvalidArgumentError(fmt.Sprintf("attachment %s is larger than %d MB", a.FileName, MaxAttachmentSize>>20))
+	}
+	return nil
+}
+
+// AttachmentKeyPrefix - префикс S3 ключей файлов организации (см. GenerateUploadURL в core-service)
+func AttachmentKeyPrefix(organizationID ID) string {
+	return fmt.Sprintf("documents/%s/", organizationID.String())
+}
+
+// BelongsTo проверяет, что файл загружен в пространство организации
+func (a Attachment) BelongsTo(organizationID ID) bool {
+	return strings.HasPrefix(a.S3Key, AttachmentKeyPrefix(organizationID)) && !strings.Contains(a.S3Key, "..")
+}
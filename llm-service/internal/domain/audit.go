@@ -0,0 +1,55 @@
+package domain
+
+import "time"
+
+// AuditAction - действие, которое фиксируется в журнале аудита core-service
+type AuditAction string
+
+const (
+	AuditActionAgentToolExecuted AuditAction = "agent.tool_executed" // Агент выполнил изменяющий инструмент от имени пользователя
+	AuditActionAgentToolDenied   AuditAction = "agent.tool_denied"   // Агенту отказано в инструменте из-за прав пользователя
+)
+
+// AuditResourceTool - тип ресурса для событий об инструментах агента
+const AuditResourceTool = "tool"
+
+// AuditEvent - событие журнала аудита (формат очереди аудита core-service)
+type AuditEvent struct {
+	EventID        string         `json:"event_id"`
+	Action         AuditAction    `json:"action"`
+	Source         string         `json:"source"`
+	OrganizationID *ID            `json:"organization_id,omitempty"`
+	ActorUserID    *ID            `json:"actor_user_id,omitempty"`
+	ResourceType   string         `json:"resource_type"`
+	ResourceID     string         `json:"resource_id"`
+	Metadata       map[string]any `json:"metadata,omitempty"`
+	OccurredAt     time.Time      `json:"occurred_at"`
+}
+
+// NewToolAuditEvent создает событие о вызове инструмента агентом от имени пользователя
+func NewToolAuditEvent(action AuditAction, toolName string, execCtx *ExecutionContext, metadata map[string]any) AuditEvent {
+	if metadata == nil {
+		metadata = map[string]any{}
+	}
+	metadata["agent"] = execCtx.AgentKey
+	metadata["chat_id"] = execCtx.ChatID.String()
+
+	event := AuditEvent{
+		EventID:      string(action) + ":" + NewID().String(),
+		Action:       action,
+		ResourceType: AuditResourceTool,
+		ResourceID:   toolName,
+		Metadata:     metadata,
+		OccurredAt:   time.Now().UTC(),
+	}
+	if execCtx.OrganizationID != (ID{}) {
+		orgID := execCtx.OrganizationID
+		event.OrganizationID = &orgID
+	}
+	if execCtx.UserID != (ID{}) {
+		userID := execCtx.UserID
+		event.ActorUserID = &userID
+	}
+
+	return event
+}
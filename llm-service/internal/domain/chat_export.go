@@ -0,0 +1,76 @@
+package domain
+
+// ChatExportFormat - формат экспорта чата
+type ChatExportFormat string
+
+const (
+	ChatExportFormatMarkdown ChatExportFormat = "markdown"
+	ChatExportFormatDOCX     ChatExportFormat = "docx"
+	ChatExportFormatPDF      ChatExportFormat = "pdf"
+)
+
+// IsValid проверяет формат экспорта
+func (f ChatExportFormat) IsValid() bool {
+	switch f {
+	case ChatExportFormatMarkdown, ChatExportFormatDOCX, ChatExportFormatPDF:
+		return true
+	default:
+		return false
+	}
+}
+
+// Extension - расширение файла экспорта
+func (f ChatExportFormat) Extension() string {
+	switch f {
+	case ChatExportFormatMarkdown:
+		return ".md"
+	case ChatExportFormatDOCX:
+		return ".docx"
+	default:
+		return ".pdf"
+	}
+}
+
+// ContentType - MIME тип файла экспорта
+func (f ChatExportFormat) ContentType() string {
+	switch f {
+	case ChatExportFormatMarkdown:
+		return "text/markdown; charset=utf-8"
+	case ChatExportFormatDOCX:
+		return docxContentType
+	default:
+		return "application/pdf"
+	}
+}
+
+// DocumentType - тип документа docs-processor; Markdown индексируется как текст
+func (f ChatExportFormat) DocumentType() string {
+	switch f {
+	case ChatExportFormatMarkdown:
+		return "txt"
+	case ChatExportFormatDOCX:
+		return "docx"
+	default:
+		return "pdf"
+	}
+}
+
+// ChatExportOptions - параметры экспорта чата
+type ChatExportOptions struct {
+	Format ChatExportFormat
+	// IncludeSubagents - включить переписку с субагентами в место их вызова
+	IncludeSubagents bool
+	// RegisterDocument - сохранить экспорт в документы организации
+	RegisterDocument bool
+}
+
+// ChatExport - результат экспорта чата
+type ChatExport struct {
+	S3Key       string
+	FileName    string
+	ContentType string
+	Size        int64
+	DownloadURL string
+	// DocumentID - ID документа организации, если экспорт зарегистрирован
+	DocumentID string
+}
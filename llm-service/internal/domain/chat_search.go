@@ -0,0 +1,56 @@
+package domain
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// MaxChatSearchQueryLength ограничение длины поискового запроса по чатам
+const MaxChatSearchQueryLength = 200
+
+// ChatSearchQuery - параметры полнотекстового поиска по истории чатов пользователя
+type ChatSearchQuery struct {
+	OrganizationID ID
+	UserID         ID
+	Query          string
+	// По умолчанию ищем только в репликах пользователя и агента
+	IncludeToolMessages   bool
+	IncludeSystemMessages bool
+}
+
+// Validate проверяет поисковый запрос
+func (q ChatSearchQuery) Validate() error {
+	query := strings.TrimSpace(q.Query)
+	if query == "" {
+		return NewInvalidArgumentError("search query is required")
+	}
+	if utf8.RuneCountInString(query) > MaxChatSearchQueryLength {
+		return NewInvalidArgumentError("search query is too long")
+	}
+	return nil
+}
+
+// Roles возвращает роли сообщений, по которым выполняется поиск
+func (q ChatSearchQuery) Roles() []MessageRole {
+	roles := []MessageRole{MessageRoleUser, MessageRoleAssistant}
+	if q.IncludeToolMessages {
+		roles = append(roles, MessageRoleTool)
+	}
+	if q.IncludeSystemMessages {
+		roles = append(roles, MessageRoleSystem)
+	}
+	return roles
+}
+
+// ChatSearchHit - чат, найденный поиском, с лучшим совпадением.
+// Сообщения субагентов относятся к основному чату.
+type ChatSearchHit struct {
+	Chat *Chat
+	// MessageID - сообщение с лучшим совпадением; nil, если совпало только название чата
+	MessageID   *ID
+	MessageRole MessageRole
+	// Snippet и TitleHighlight - HTML-экранированный текст, совпадения обернуты в <mark>
+	Snippet        string
+	TitleHighlight string
+	Rank           float64
+}
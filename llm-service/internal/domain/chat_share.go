@@ -0,0 +1,70 @@
+package domain
+
+import "time"
+
+// ChatShareRole - роль пользователя, с которым поделились чатом
+type ChatShareRole string
+
+const (
+	// ChatShareRoleViewer - только чтение
+	ChatShareRoleViewer ChatShareRole = "viewer"
+	// ChatShareRoleCollaborator - может писать в чат от своего имени
+	ChatShareRoleCollaborator ChatShareRole = "collaborator"
+)
+
+// IsValid проверяет роль
+func (r ChatShareRole) IsValid() bool {
+	return r == ChatShareRoleViewer || r == ChatShareRoleCollaborator
+}
+
+// ChatShare - доступ сотрудника организации к чужому чату
+type ChatShare struct {
+	ChatID    ID
+	UserID    ID
+	Role      ChatShareRole
+	SharedBy  ID
+	CreatedAt time.Time
+}
+
+func NewChatShare(chatID, userID, sharedBy ID, role ChatShareRole) ChatShare {
+	return ChatShare{
+		ChatID:    chatID,
+		UserID:    userID,
+		Role:      role,
+		SharedBy:  sharedBy,
+		CreatedAt: time.Now(),
+	}
+}
+
+// SharedChat - чат, которым поделились с пользователем
+type SharedChat struct {
+	Chat  *Chat
+	Share ChatShare
+}
+
+// ChatAccess - уровень доступа пользователя к чату
+type ChatAccess string
+
+const (
+	ChatAccessNone         ChatAccess = ""
+	ChatAccessOwner        ChatAccess = "owner"
+	ChatAccessCollaborator ChatAccess = "collaborator"
+	ChatAccessViewer       ChatAccess = "viewer"
+	// ChatAccessAdmin - участник с правом chats.view_all видит все чаты, если это разрешено политикой
+	ChatAccessAdmin ChatAccess = "admin"
+)
+
+// CanRead - пользователь может читать чат
+func (a ChatAccess) CanRead() bool {
+	return a != ChatAccessNone
+}
+
+// CanWrite - пользователь может отправлять сообщения в чат
+func (a ChatAccess) CanWrite() bool {
+	return a == ChatAccessOwner || a == ChatAccessCollaborator
+}
+
+// CanManage - пользователь может управлять доступом и удалять чат
+func (a ChatAccess) CanManage() bool {
+	return a == ChatAccessOwner
+}
@@ -16,10 +16,41 @@ type CreateChatDTO struct {
 
 // SendMessageDTO - DTO для отправки сообщения
 type SendMessageDTO struct {
-	ChatID  *ID
-	UserID  ID
-	OrgID   ID
-	Content string
+	ChatID      *ID
+	UserID      ID
+	OrgID       ID
+	Content     string
+	Attachments []AttachmentDTO
+	// Membership - роль и права пользователя в организации, в пределах которых агент вызывает инструменты
+	Membership *domain.Membership
+}
+
+// EditMessageDTO - DTO для редактирования сообщения пользователя
+type EditMessageDTO struct {
+	ChatID    ID
+	MessageID ID
+	UserID    ID
+	OrgID     ID
+	Content   string
+	// Membership - роль и права пользователя в организации
+	Membership *domain.Membership
+}
+
+// RegenerateMessageDTO - DTO для повторной генерации ответа ассистента
+type RegenerateMessageDTO struct {
+	ChatID    ID
+	MessageID ID
+	UserID    ID
+	OrgID     ID
+	// Membership - роль и права пользователя в организации
+	Membership *domain.Membership
+}
+
+// AttachmentDTO - ссылка на файл в S3, загруженный клиентом через GenerateUploadURL
+type AttachmentDTO struct {
+	S3Key       string
+	FileName    string
+	ContentType string
 }
 
 // ChatUsageDTO - DTO статистики использования токенов
@@ -41,8 +72,12 @@ type ExecuteAgentDTO struct {
 	OrganizationID ID
 	UserID         ID
 	Task           string
-	ChatID         *ID
-	Context        map[string]interface{}
+	// Title - заголовок нового чата; по умолчанию используется Task
+	Title   string
+	ChatID  *ID
+	Context map[string]interface{}
+	// Membership - роль и права пользователя в организации, в пределах которых агент вызывает инструменты
+	Membership *domain.Membership
 }
 
 // ID - тип для идентификаторов
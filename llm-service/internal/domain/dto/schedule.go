@@ -0,0 +1,26 @@
+package dto
+
+// CreateScheduleDTO - DTO для создания расписания агента
+type CreateScheduleDTO struct {
+	OrganizationID ID
+	UserID         ID
+	Title          string
+	AgentKey       string
+	Prompt         string
+	CronExpr       string
+	Timezone       string
+	Enabled        bool
+}
+
+// UpdateScheduleDTO - DTO для изменения расписания; nil-поля не меняются
+type UpdateScheduleDTO struct {
+	ScheduleID     ID
+	OrganizationID ID
+	UserID         ID
+	Title          *string
+	AgentKey       *string
+	Prompt         *string
+	CronExpr       *string
+	Timezone       *string
+	Enabled        *bool
+}
@@ -0,0 +1,41 @@
+package domain
+
+import "time"
+
+// ErasureSubject - чьи данные удаляются безвозвратно: организации или пользователя
+type ErasureSubject string
+
+const (
+	ErasureSubjectOrganization ErasureSubject = "organization"
+	ErasureSubjectUser         ErasureSubject = "user"
+)
+
+// ErasureStepLLMData - шаг удаления данных, который выполняет llm-service
+const ErasureStepLLMData = "llm_data"
+
+// ErasureStepStatus - итог шага удаления
+type ErasureStepStatus string
+
+const (
+	ErasureStepCompleted ErasureStepStatus = "completed"
+	ErasureStepFailed    ErasureStepStatus = "failed"
+)
+
+// ErasureRequest - заявка core-service удалить все данные организации или пользователя
+type ErasureRequest struct {
+	ErasureID   ID             `json:"erasure_id"`
+	SubjectType ErasureSubject `json:"subject_type"`
+	SubjectID   ID             `json:"subject_id"`
+	RequestedAt time.Time      `json:"requested_at"`
+}
+
+// ErasureStepResult - ответ core-service о выполнении шага удаления
+type ErasureStepResult struct {
+	ErasureID    ID                `json:"erasure_id"`
+	Step         string            `json:"step"`
+	Source       string            `json:"source"`
+	Status       ErasureStepStatus `json:"status"`
+	ItemsDeleted int               `json:"items_deleted"`
+	Error        string            `json:"error,omitempty"`
+	FinishedAt   time.Time         `json:"finished_at"`
+}
@@ -0,0 +1,118 @@
+package domain
+
+import "time"
+
+// FeedbackRating - оценка ответа ассистента
+type FeedbackRating string
+
+const (
+	FeedbackRatingPositive FeedbackRating = "positive"
+	FeedbackRatingNegative FeedbackRating = "negative"
+)
+
+// IsValid проверяет оценку
+func (r FeedbackRating) IsValid() bool {
+	return r == FeedbackRatingPositive || r == FeedbackRatingNegative
+}
+
+// FeedbackReason - причина оценки, выбираемая пользователем из списка
+type FeedbackReason string
+
+const (
+	FeedbackReasonIncorrect  FeedbackReason = "incorrect"
+	FeedbackReasonIncomplete FeedbackReason = "incomplete"
+	FeedbackReasonIrrelevant FeedbackReason = "irrelevant"
+	FeedbackReasonOutdated   FeedbackReason = "outdated"
+	FeedbackReasonToolError  FeedbackReason = "tool_error"
+	FeedbackReasonTooLong    FeedbackReason = "too_long"
+	FeedbackReasonHelpful    FeedbackReason = "helpful"
+	FeedbackReasonOther      FeedbackReason = "other"
+)
+
+// IsValid проверяет причину
+func (r FeedbackReason) IsValid() bool {
+	switch r {
+	case FeedbackReasonIncorrect, FeedbackReasonIncomplete, FeedbackReasonIrrelevant, FeedbackReasonOutdated,
+		FeedbackReasonToolError, FeedbackReasonTooLong, FeedbackReasonHelpful, FeedbackReasonOther:
+		return true
+	default:
+		return false
+	}
+}
+
+// MaxFeedbackCommentLength - максимальная длина комментария к оценке
+const MaxFeedbackCommentLength = 2000
+
+// MessageFeedback - оценка пользователем ответа ассистента. Один пользователь - одна оценка
+// на сообщение, повторная оценка заменяет предыдущую.
+type MessageFeedback struct {
+	MessageID      ID
+	UserID         ID
+	OrganizationID ID
+	ChatID         ID
+	// AgentKey - агент, давший ответ
+	AgentKey string
+	Rating   FeedbackRating
+	Reasons  []FeedbackReason
+	Comment  string
+	// ToolNames - инструменты, вызванные при подготовке ответа (заполняется при сохранении)
+	ToolNames []string
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// FeedbackReportQuery - фильтр отчета по оценкам
+type FeedbackReportQuery struct {
+	OrganizationID ID
+	// AgentKey - если задан, отчет только по одному агенту
+	AgentKey string
+	From     time.Time
+	To       time.Time
+	// CommentsLimit - сколько последних отрицательных отзывов с комментариями вернуть
+	CommentsLimit int
+}
+
+// FeedbackStats - количество оценок
+type FeedbackStats struct {
+	Positive int
+	Negative int
+}
+
+// Total - всего оценок
+func (s FeedbackStats) Total() int {
+	return s.Positive + s.Negative
+}
+
+// SatisfactionRate - доля положительных оценок
+func (s FeedbackStats) SatisfactionRate() float64 {
+	if s.Total() == 0 {
+		return 0
+	}
+	return float64(s.Positive) / float64(s.Total())
+}
+
+// AgentFeedbackStats - оценки ответов агента
+type AgentFeedbackStats struct {
+	AgentKey  string
+	AgentName string
+	FeedbackStats
+	// Reasons - количество упоминаний каждой причины в отрицательных оценках
+	Reasons map[FeedbackReason]int
+}
+
+// ToolFeedbackStats - оценки ответов, при подготовке которых вызывался инструмент
+type ToolFeedbackStats struct {
+	ToolName string
+	FeedbackStats
+}
+
+// FeedbackReport - сводный отчет по оценкам за период
+type FeedbackReport struct {
+	// From, To - фактический период отчета
+	From     time.Time
+	To       time.Time
+	Totals   FeedbackStats
+	Agents   []AgentFeedbackStats
+	Tools    []ToolFeedbackStats
+	Comments []MessageFeedback
+}
@@ -0,0 +1,95 @@
+package domain
+
+import "slices"
+
+// OrganizationRole - роль пользователя в организации (совпадает с ролями core-service)
+type OrganizationRole string
+
+const (
+	OrganizationRoleAdmin    OrganizationRole = "admin"
+	OrganizationRoleEmployee OrganizationRole = "employee"
+)
+
+// organizationRoleRank задает старшинство ролей: администратор может все, что может сотрудник
+var organizationRoleRank = map[OrganizationRole]int{
+	OrganizationRoleEmployee: 1,
+	OrganizationRoleAdmin:    2,
+}
+
+// Permission - именованное право участника в организации; выдается ролями core-service
+type Permission string
+
+// Права, которые проверяет llm-service; полный список и роли ведет core-service
+const (
+	PermissionDocumentsWrite    Permission = "documents.write"
+	PermissionContractsRead     Permission = "contracts.read"
+	PermissionContractsGenerate Permission = "contracts.generate"
+	PermissionCRMRead           Permission = "crm.read"
+	PermissionCRMWrite          Permission = "crm.write"
+	PermissionMemoryEdit        Permission = "memory.edit"
+	PermissionChatsViewAll      Permission = "chats.view_all"
+	PermissionAnalyticsView     Permission = "analytics.view"
+)
+
+// adminPermissions - все права, которые проверяет llm-service
+var adminPermissions = []Permission{
+	PermissionDocumentsWrite,
+	PermissionContractsRead,
+	PermissionContractsGenerate,
+	PermissionCRMRead,
+	PermissionCRMWrite,
+	PermissionMemoryEdit,
+	PermissionChatsViewAll,
+	PermissionAnalyticsView,
+}
+
+// employeePermissions - права встроенной роли сотрудника; используются для токенов,
+// выданных до появления прав в claim "orgs"
+var employeePermissions = []Permission{
+	PermissionDocumentsWrite,
+	PermissionContractsRead,
+	PermissionContractsGenerate,
+	PermissionCRMRead,
+	PermissionCRMWrite,
+	PermissionMemoryEdit,
+}
+
+// RolePermissions возвращает права встроенной роли
+func RolePermissions(role OrganizationRole) []Permission {
+	switch role {
+	case OrganizationRoleAdmin:
+		return slices.Clone(adminPermissions)
+	case OrganizationRoleEmployee:
+		return slices.Clone(employeePermissions)
+	default:
+		return nil
+	}
+}
+
+// Membership - членство пользователя в организации, по которому проверяется доступ к RPC
+type Membership struct {
+	OrganizationID ID
+	Role           OrganizationRole
+	Active         bool
+	// Permissions - действующие права участника (встроенной или собственной роли)
+	Permissions []Permission
+}
+
+// HasRole проверяет, что роль участника не ниже требуемой
+func (m *Membership) HasRole(required OrganizationRole) bool {
+	return organizationRoleRank[m.Role] > 0 && organizationRoleRank[m.Role] >= organizationRoleRank[required]
+}
+
+// HasPermission проверяет право участника; администратору доступно все
+func (m *Membership) HasPermission(permission Permission) bool {
+	return m.Role == OrganizationRoleAdmin || slices.Contains(m.Permissions, permission)
+}
+
+// ParsePermissions преобразует права из ответа core-service
+func ParsePermissions(raw []string) []Permission {
+	permissions := make([]Permission, 0, len(raw))
+	for _, p := range raw {
+		permissions = append(permissions, Permission(p))
+	}
+	return permissions
+}
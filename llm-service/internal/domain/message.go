@@ -13,12 +13,22 @@ const (
 // Message - сообщение в чате
 type Message struct {
 	Model
-	ChatID     ID
-	Role       MessageRole
-	Content    string
-	Sender     *string // null для user, agent_key для агентов
-	ToolCalls  []*ToolCall
-	ToolCallID *ID // для tool результатов
+	ChatID  ID
+	Role    MessageRole
+	Content string
+	Sender  *string // null для user, agent_key для агентов
+	// AuthorUserID - пользователь, написавший сообщение (в общем чате их может быть несколько)
+	AuthorUserID *ID
+	ToolCalls    []*ToolCall
+	ToolCallID   *ID // для tool результатов
+	// Attachments - изображения и документы, приложенные пользователем
+	Attachments []Attachment
+	// ParentMessageID - предыдущее сообщение ветки; если не задан при сохранении,
+	// сообщение продолжает активную ветку чата
+	ParentMessageID *ID
+	// SiblingIDs - альтернативные версии сообщения (включая само сообщение) в порядке создания;
+	// заполняется, только если вариантов больше одного
+	SiblingIDs []ID
 }
 
 // IsUserMessage - проверяет, является ли сообщение пользовательским
@@ -36,6 +46,11 @@ func (m *Message) IsToolResult() bool {
 	return m.Role == MessageRoleTool
 }
 
+// HasAttachments - проверяет, есть ли в сообщении вложения
+func (m *Message) HasAttachments() bool {
+	return len(m.Attachments) > 0
+}
+
 // HasToolCalls - проверяет, есть ли в сообщении вызовы инструментов
 func (m *Message) HasToolCalls() bool {
 	return len(m.ToolCalls) > 0
@@ -0,0 +1,83 @@
+package domain
+
+import (
+	"slices"
+	"time"
+)
+
+// MessageLink - узел дерева сообщений чата
+type MessageLink struct {
+	ID        ID
+	ParentID  *ID
+	Role      MessageRole
+	CreatedAt time.Time
+}
+
+// MessageTree - дерево сообщений одного чата. Правка сообщения и перегенерация ответа
+// добавляют новую ветку от родителя, старые ветки сохраняются.
+type MessageTree struct {
+	nodes    map[ID]MessageLink
+	children map[ID][]ID
+}
+
+// NewMessageTree строит дерево из связей сообщений
+func NewMessageTree(links []MessageLink) *MessageTree {
+	slices.SortStableFunc(links, func(a, b MessageLink) int {
+		return a.CreatedAt.Compare(b.CreatedAt)
+	})
+
+	tree := &MessageTree{
+		nodes:    make(map[ID]MessageLink, len(links)),
+		children: make(map[ID][]ID),
+	}
+	for _, link := range links {
+		tree.nodes[link.ID] = link
+		if link.ParentID != nil {
+			tree.children[*link.ParentID] = append(tree.children[*link.ParentID], link.ID)
+		}
+	}
+	return tree
+}
+
+// Get возвращает узел по ID
+func (t *MessageTree) Get(id ID) (MessageLink, bool) {
+	link, ok := t.nodes[id]
+	return link, ok
+}
+
+// Siblings возвращает альтернативные версии сообщения (включая его само) в порядке создания
+func (t *MessageTree) Siblings(id ID) []ID {
+	link, ok := t.nodes[id]
+	if !ok || link.ParentID == nil {
+		return []ID{id}
+	}
+	return t.children[*link.ParentID]
+}
+
+// LatestLeaf спускается от сообщения по самым новым ответам до конца ветки
+func (t *MessageTree) LatestLeaf(id ID) ID {
+	for {
+		children := t.children[id]
+		if len(children) == 0 {
+			return id
+		}
+		id = children[len(children)-1]
+	}
+}
+
+// NearestAncestor возвращает ближайшее к сообщению (включая его само) сообщение с ролью role
+func (t *MessageTree) NearestAncestor(id ID, role MessageRole) (MessageLink, bool) {
+	for {
+		link, ok := t.nodes[id]
+		if !ok {
+			return MessageLink{}, false
+		}
+		if link.Role == role {
+			return link, true
+		}
+		if link.ParentID == nil {
+			return MessageLink{}, false
+		}
+		id = *link.ParentID
+	}
+}
@@ -0,0 +1,22 @@
+package domain
+
+// NotificationKind - тип уведомления пользователю
+type NotificationKind string
+
+const (
+	// NotificationKindScheduledRun - готов результат задачи по расписанию
+	NotificationKindScheduledRun NotificationKind = "scheduled_run"
+)
+
+// Notification - уведомление пользователю, доставляемое вне интерфейса чата (Telegram и т.п.)
+type Notification struct {
+	// Key - ключ дедупликации: повторная отправка с тем же ключом не доходит до пользователя
+	Key            string
+	Kind           NotificationKind
+	OrganizationID ID
+	UserID         ID
+	Title          string
+	Text           string
+	// ChatID - чат, к которому относится уведомление
+	ChatID *ID
+}
@@ -0,0 +1,35 @@
+package domain
+
+import "time"
+
+// OrganizationExportStatus - итог выгрузки данных llm-service
+type OrganizationExportStatus string
+
+const (
+	OrganizationExportCompleted OrganizationExportStatus = "completed"
+	OrganizationExportFailed    OrganizationExportStatus = "failed"
+)
+
+// OrganizationExportRequest - запрос core-service выгрузить чаты и память организации для архива данных
+type OrganizationExportRequest struct {
+	ExportID       ID        `json:"export_id"`
+	OrganizationID ID        `json:"organization_id"`
+	RequestedAt    time.Time `json:"requested_at"`
+}
+
+// OrganizationExportFile - файл в S3, который core-service кладет в архив
+type OrganizationExportFile struct {
+	// Name - путь файла внутри архива
+	Name  string `json:"name"`
+	S3Key string `json:"s3_key"`
+	Items int    `json:"items"`
+}
+
+// OrganizationExportResult - ответ core-service с выгруженными файлами
+type OrganizationExportResult struct {
+	ExportID ID                       `json:"export_id"`
+	Source   string                   `json:"source"`
+	Status   OrganizationExportStatus `json:"status"`
+	Files    []OrganizationExportFile `json:"files"`
+	Error    string                   `json:"error,omitempty"`
+}
@@ -0,0 +1,47 @@
+package domain
+
+import "time"
+
+// ScheduleRunStatus - результат последнего запуска расписания
+type ScheduleRunStatus string
+
+const (
+	ScheduleRunStatusSuccess ScheduleRunStatus = "success"
+	ScheduleRunStatusFailed  ScheduleRunStatus = "failed"
+	// ScheduleRunStatusSkipped - запуск пропущен, например из-за исчерпанной квоты токенов
+	ScheduleRunStatusSkipped ScheduleRunStatus = "skipped"
+)
+
+// AgentSchedule - регулярная задача агента: по cron-выражению в часовом поясе пользователя
+// агент выполняет промпт без участия пользователя, результат сохраняется новым чатом
+type AgentSchedule struct {
+	Model
+	OrganizationID ID
+	UserID         ID
+	Title          string
+	AgentKey       string
+	Prompt         string
+	// CronExpr - cron-выражение из 5 полей (минута, час, день месяца, месяц, день недели)
+	CronExpr string
+	// Timezone - часовой пояс IANA, в котором интерпретируется CronExpr
+	Timezone  string
+	Enabled   bool
+	NextRunAt *time.Time
+
+	LastRunAt     *time.Time
+	LastRunStatus ScheduleRunStatus
+	LastChatID    *ID
+	LastError     string
+}
+
+// RecordRun сохраняет результат запуска
+func (s *AgentSchedule) RecordRun(at time.Time, status ScheduleRunStatus, chatID *ID, err error) {
+	s.LastRunAt = &at
+	s.LastRunStatus = status
+	s.LastChatID = chatID
+	s.LastError = ""
+	if err != nil {
+		s.LastError = err.Error()
+	}
+	s.UpdatedAt = time.Now().UTC()
+}
@@ -0,0 +1,14 @@
+package domain
+
+import "time"
+
+// TelegramBinding - связь личного чата с Telegram-ботом и чата агента в организации.
+// У пользователя бота по одной связи на организацию; активна последняя выбранная (UpdatedAt).
+type TelegramBinding struct {
+	TelegramChatID int64
+	OrganizationID ID
+	UserID         ID
+	// ChatID - текущий чат агента; nil - следующее сообщение начнет новый чат
+	ChatID    *ID
+	UpdatedAt time.Time
+}
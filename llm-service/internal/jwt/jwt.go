@@ -111,24 +111,90 @@ func NewProvider(opts ...OptionsFunc) *Provider {
 	return p
 }
 
+// Claims - проверенные данные access-токена core-service
+type Claims struct {
+	UserID domain.ID
+	// Memberships - активные членства в организациях на момент выдачи токена (claim "orgs");
+	// обновляются при RefreshToken
+	Memberships []domain.Membership
+}
+
 func (p *Provider) ParseToken(ctx context.Context, token string) (domain.ID, error) {
+	claims, err := p.ParseClaims(ctx, token)
+	if err != nil {
+		return domain.ID{}, err
+	}
+	return claims.UserID, nil
+}
+
+// ParseClaims проверяет токен и возвращает пользователя и его членства в организациях
+func (p *Provider) ParseClaims(ctx context.Context, token string) (*Claims, error) {
 	claims := jwt.MapClaims{}
 	tokenParsed, err := jwt.ParseWithClaims(token, &claims, p.opts.Credentials.GetKey)
 	if err != nil {
 		logger.Errorf(ctx, "failed to parse token: %v", err)
-		return domain.ID{}, domain.ErrUnauthorized
+		return nil, domain.ErrUnauthorized
 	}
 
 	if !tokenParsed.Valid {
 		logger.Errorf(ctx, "token is invalid")
-		return domain.ID{}, domain.ErrUnauthorized
+		return nil, domain.ErrUnauthorized
 	}
 
 	subject, err := claims.GetSubject()
 	if err != nil {
 		logger.Errorf(ctx, "token subject not found: %v", err)
-		return domain.ID{}, domain.ErrUnauthorized
+		return nil, domain.ErrUnauthorized
+	}
+
+	userID, err := domain.ParseID(subject)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Claims{
+		UserID:      userID,
+		Memberships: parseMemberships(claims["orgs"]),
+	}, nil
+}
+
+// parseMemberships разбирает claim "orgs" вида [{"id": "...", "role": "admin", "permissions": ["crm.read"]}];
+// некорректные элементы пропускаются. В токенах без permissions права берутся из встроенной роли
+func parseMemberships(raw any) []domain.Membership {
+	items, ok := raw.([]any)
+	if !ok {
+		return nil
+	}
+
+	memberships := make([]domain.Membership, 0, len(items))
+	for _, item := range items {
+		org, ok := item.(map[string]any)
+		if !ok {
+			continue
+		}
+		id, _ := org["id"].(string)
+		role, _ := org["role"].(string)
+
+		orgID, err := domain.ParseID(id)
+		if err != nil {
+			continue
+		}
+		membership := domain.Membership{
+			OrganizationID: orgID,
+			Role:           domain.OrganizationRole(role),
+			Active:         true,
+		}
+		if permissions, ok := org["permissions"].([]any); ok {
+			for _, p := range permissions {
+				if permission, ok := p.(string); ok {
+					membership.Permissions = append(membership.Permissions, domain.Permission(permission))
+				}
+			}
+		} else {
+			membership.Permissions = domain.RolePermissions(membership.Role)
+		}
+		memberships = append(memberships, membership)
 	}
 
-	return domain.ParseID(subject)
+	return memberships
 }
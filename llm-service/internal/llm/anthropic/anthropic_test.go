@@ -0,0 +1,319 @@
+package anthropic_llm
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"llm-service/internal/llm"
+)
+
+// anthropicServer answers every request with status and body and remembers the last request
+type anthropicServer struct {
+	header http.Header
+	body   messagesRequest
+}
+
+func newAnthropicServer(t *testing.T, status int, reply string) (*anthropicServer, *CompletionProvider) {
+	t.Helper()
+
+	s := &anthropicServer{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/messages" {
+			t.Errorf("path = %s, want /v1/messages", r.URL.Path)
+		}
+		s.header = r.Header.Clone()
+		raw, _ := io.ReadAll(r.Body)
+		if err := json.Unmarshal(raw, &s.body); err != nil {
+			t.Errorf("request body: %v", err)
+		}
+		w.WriteHeader(status)
+		_, _ = w.Write([]byte(reply))
+	}))
+	t.Cleanup(server.Close)
+
+	return s, New(server.Client(), "test-key", "claude-default", WithBaseURL(server.URL+"/"), WithName("anthropic-test"))
+}
+
+func TestNewRequest(t *testing.T) {
+	model := "claude-sonnet"
+
+	tests := []struct {
+		name       string
+		params     llm.ChatParams
+		wantModel  string
+		wantSystem string
+		wantMsgs   []message
+		wantTools  []toolParam
+	}{
+		{
+			name: "system поднимается в отдельное поле",
+			params: llm.ChatParams{Messages: []llm.MessageParam{
+				{Role: llm.RoleSystem, Content: "Ты помощник"},
+				{Role: llm.RoleSystem, Content: "Отвечай кратко"},
+				{Role: llm.RoleUser, Content: "Привет"},
+			}},
+			wantModel:  "claude-default",
+			wantSystem: "Ты помощник\n\nОтвечай кратко",
+			wantMsgs:   []message{{Role: "user", Content: []contentBlock{{Type: "text", Text: "Привет"}}}},
+		},
+		{
+			name: "вызовы и результаты инструментов",
+			params: llm.ChatParams{
+				Model: &model,
+				Messages: []llm.MessageParam{
+					{Role: llm.RoleUser, Content: "Найди новости"},
+					{Role: llm.RoleAssistant, Content: "Ищу", ToolCalls: []llm.ToolCall{
+						{ID: "toolu_1", Name: "web_search", Arguments: `{"query":"новости"}`},
+						{ID: "toolu_2", Name: "list_facts"},
+					}},
+					{Role: llm.RoleTool, ToolCallID: "toolu_1", Content: "результаты"},
+					{Role: llm.RoleTool, ToolCallID: "toolu_2", Content: "[]"},
+					{Role: llm.RoleUser, Content: "Спасибо"},
+				},
+			},
+			wantModel: "claude-sonnet",
+			wantMsgs: []message{
+				{Role: "user", Content: []contentBlock{{Type: "text", Text: "Найди новости"}}},
+				{Role: "assistant", Content: []contentBlock{
+					{Type: "text", Text: "Ищу"},
+					{Type: "tool_use", ID: "toolu_1", Name: "web_search", Input: json.RawMessage(`{"query":"новости"}`)},
+					{Type: "tool_use", ID: "toolu_2", Name: "list_facts", Input: json.RawMessage(`{}`)},
+				}},
+				// Tool results and the next user message are merged into one user turn
+				{Role: "user", Content: []contentBlock{
+					{Type: "tool_result", ToolUseID: "toolu_1", Content: "результаты"},
+					{Type: "tool_result", ToolUseID: "toolu_2", Content: "[]"},
+					{Type: "text", Text: "Спасибо"},
+				}},
+			},
+		},
+		{
+			name: "вложения и пустые сообщения",
+			params: llm.ChatParams{Messages: []llm.MessageParam{
+				{Role: llm.RoleUser, Parts: []llm.ContentPart{
+					llm.TextPart("Что на скане?"),
+					llm.TextPart(""),
+					{Type: llm.ContentPartImage, Data: []byte("png"), MediaType: "image/png"},
+					{Type: llm.ContentPartFile, Data: []byte("pdf"), MediaType: "application/pdf", FileName: "scan.pdf"},
+				}},
+				{Role: llm.RoleAssistant},
+			}},
+			wantModel: "claude-default",
+			wantMsgs: []message{{Role: "user", Content: []contentBlock{
+				{Type: "text", Text: "Что на скане?"},
+				{Type: "image", Source: &source{Type: "base64", MediaType: "image/png", Data: "cG5n"}},
+				{Type: "document", Source: &source{Type: "base64", MediaType: "application/pdf", Data: "cGRm"}},
+			}}},
+		},
+		{
+			name: "описание инструментов",
+			params: llm.ChatParams{
+				Messages: []llm.MessageParam{{Role: llm.RoleUser, Content: "Привет"}},
+				Tools: []llm.ToolDefinition{
+					{Name: "web_search", Description: "Поиск", Parameters: map[string]any{"type": "object", "required": []any{"query"}}},
+					{Name: "list_facts"},
+				},
+			},
+			wantModel: "claude-default",
+			wantMsgs:  []message{{Role: "user", Content: []contentBlock{{Type: "text", Text: "Привет"}}}},
+			wantTools: []toolParam{
+				{Name: "web_search", Description: "Поиск", InputSchema: map[string]any{"type": "object", "required": []any{"query"}}},
+				{Name: "list_facts", InputSchema: map[string]any{"type": "object", "properties": map[string]any{}}},
+			},
+		},
+	}
+
+	c := New(http.DefaultClient, "test-key", "claude-default")
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := c.newRequest(tt.params, false)
+
+			if req.Model != tt.wantModel {
+				t.Errorf("model = %q, want %q", req.Model, tt.wantModel)
+			}
+			if req.MaxTokens != defaultMaxTokens {
+				t.Errorf("max_tokens = %d, want %d", req.MaxTokens, defaultMaxTokens)
+			}
+			if req.System != tt.wantSystem {
+				t.Errorf("system = %q, want %q", req.System, tt.wantSystem)
+			}
+			if !reflect.DeepEqual(req.Messages, tt.wantMsgs) {
+				t.Errorf("messages = %+v, want %+v", req.Messages, tt.wantMsgs)
+			}
+			if !reflect.DeepEqual(req.Tools, tt.wantTools) {
+				t.Errorf("tools = %+v, want %+v", req.Tools, tt.wantTools)
+			}
+		})
+	}
+}
+
+func TestNewRequestResponseFormat(t *testing.T) {
+	c := New(http.DefaultClient, "test-key", "claude-default", WithMaxTokens(1024))
+	req := c.newRequest(llm.ChatParams{
+		Messages: []llm.MessageParam{
+			{Role: llm.RoleSystem, Content: "Извлеки факты"},
+			{Role: llm.RoleUser, Content: "Мы продаем кофе"},
+		},
+		ResponseFormat: &llm.ResponseFormat{Name: "facts", Schema: map[string]any{"type": "object"}},
+	}, true)
+
+	// Messages API has no response_format: the schema is appended to the system prompt
+	if !strings.HasPrefix(req.System, "Извлеки факты\n\n") || !strings.Contains(req.System, `{"type":"object"}`) {
+		t.Errorf("system = %q, want prompt followed by schema", req.System)
+	}
+	if !req.Stream || req.MaxTokens != 1024 {
+		t.Errorf("stream = %v, max_tokens = %d, want true and 1024", req.Stream, req.MaxTokens)
+	}
+}
+
+func TestCreateCompletion(t *testing.T) {
+	server, c := newAnthropicServer(t, http.StatusOK, `{
+		"content": [
+			{"type": "text", "text": "Добрый "},
+			{"type": "tool_use", "id": "toolu_1", "name": "web_search", "input": {}},
+			{"type": "text", "text": "день"}
+		],
+		"usage": {"input_tokens": 12, "output_tokens": 5}
+	}`)
+
+	text, usage, err := c.CreateCompletion(context.Background(), llm.ChatParams{
+		Messages: []llm.MessageParam{{Role: llm.RoleUser, Content: "Привет"}},
+	})
+	if err != nil {
+		t.Fatalf("CreateCompletion: %v", err)
+	}
+
+	if text != "Добрый день" {
+		t.Errorf("text = %q, want text blocks joined", text)
+	}
+	if want := (llm.Usage{PromptTokens: 12, CompletionTokens: 5, TotalTokens: 17}); usage != want {
+		t.Errorf("usage = %+v, want %+v", usage, want)
+	}
+	if got := server.header.Get("x-api-key"); got != "test-key" {
+		t.Errorf("x-api-key = %q, want test-key", got)
+	}
+	if got := server.header.Get("anthropic-version"); got != apiVersion {
+		t.Errorf("anthropic-version = %q, want %s", got, apiVersion)
+	}
+	if server.body.Model != "claude-default" || server.body.Stream {
+		t.Errorf("request model = %q, stream = %v", server.body.Model, server.body.Stream)
+	}
+}
+
+func TestCreateCompletionErrors(t *testing.T) {
+	tests := []struct {
+		name          string
+		status        int
+		reply         string
+		wantStatus    int
+		wantRetryable bool
+	}{
+		{name: "rate limit", status: http.StatusTooManyRequests, reply: `{"type":"error"}`, wantStatus: 429, wantRetryable: true},
+		{name: "overloaded", status: 529, reply: `{"type":"error"}`, wantStatus: 529, wantRetryable: true},
+		{name: "invalid request", status: http.StatusBadRequest, reply: `{"type":"error"}`, wantStatus: 400},
+		{name: "empty answer", status: http.StatusOK, reply: `{"content": [], "usage": {}}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, c := newAnthropicServer(t, tt.status, tt.reply)
+
+			_, _, err := c.CreateCompletion(context.Background(), llm.ChatParams{
+				Messages: []llm.MessageParam{{Role: llm.RoleUser, Content: "Привет"}},
+			})
+			if err == nil {
+				t.Fatal("CreateCompletion = nil error, want error")
+			}
+			if got := llm.IsRetryable(err); got != tt.wantRetryable {
+				t.Errorf("IsRetryable(%v) = %v, want %v", err, got, tt.wantRetryable)
+			}
+
+			var pe *llm.ProviderError
+			if tt.wantStatus != 0 {
+				if !errors.As(err, &pe) || pe.StatusCode != tt.wantStatus || pe.Provider != "anthropic-test" {
+					t.Errorf("error = %v, want ProviderError %d from anthropic-test", err, tt.wantStatus)
+				}
+			}
+		})
+	}
+}
+
+func sse(events ...string) string {
+	var b strings.Builder
+	for _, ev := range events {
+		b.WriteString("event: message\ndata: " + ev + "\n\n")
+	}
+	return b.String()
+}
+
+func TestCreateCompletionStream(t *testing.T) {
+	_, c := newAnthropicServer(t, http.StatusOK, sse(
+		`{"type":"message_start","message":{"usage":{"input_tokens":20,"output_tokens":1}}}`,
+		`{"type":"content_block_start","index":0,"content_block":{"type":"text","text":""}}`,
+		`{"type":"content_block_delta","index":0,"delta":{"type":"text_delta","text":"Ищу"}}`,
+		`{"type":"content_block_start","index":1,"content_block":{"type":"tool_use","id":"toolu_1","name":"web_search"}}`,
+		`{"type":"content_block_delta","index":1,"delta":{"type":"input_json_delta","partial_json":"{\"query\":"}}`,
+		`{"type":"content_block_delta","index":1,"delta":{"type":"input_json_delta","partial_json":"\"кофе\"}"}}`,
+		`{"type":"message_delta","delta":{"type":"","text":""},"usage":{"output_tokens":9}}`,
+		`{"type":"message_stop"}`,
+	))
+
+	stream, err := c.CreateCompletionStream(context.Background(), llm.ChatParams{
+		Messages:     []llm.MessageParam{{Role: llm.RoleUser, Content: "Найди"}},
+		IncludeUsage: true,
+	})
+	if err != nil {
+		t.Fatalf("CreateCompletionStream: %v", err)
+	}
+	defer stream.Close()
+
+	var chunks []llm.ChatDelta
+	for stream.Next() {
+		chunks = append(chunks, stream.Chunk())
+	}
+	if err := stream.Err(); err != nil {
+		t.Fatalf("stream error: %v", err)
+	}
+
+	want := []llm.ChatDelta{
+		{Content: "Ищу"},
+		{ToolCalls: []llm.ToolCallDelta{{Index: 1, ID: "toolu_1", Name: "web_search"}}},
+		{ToolCalls: []llm.ToolCallDelta{{Index: 1, Arguments: `{"query":`}}},
+		{ToolCalls: []llm.ToolCallDelta{{Index: 1, Arguments: `"кофе"}`}}},
+		{Usage: llm.Usage{PromptTokens: 20, CompletionTokens: 9, TotalTokens: 29}},
+	}
+	if !reflect.DeepEqual(chunks, want) {
+		t.Errorf("chunks = %+v, want %+v", chunks, want)
+	}
+}
+
+func TestCreateCompletionStreamOverloaded(t *testing.T) {
+	_, c := newAnthropicServer(t, http.StatusOK, sse(
+		`{"type":"message_start","message":{"usage":{"input_tokens":20,"output_tokens":1}}}`,
+		`{"type":"error","error":{"type":"overloaded_error","message":"Overloaded"}}`,
+	))
+
+	stream, err := c.CreateCompletionStream(context.Background(), llm.ChatParams{
+		Messages: []llm.MessageParam{{Role: llm.RoleUser, Content: "Привет"}},
+	})
+	if err != nil {
+		t.Fatalf("CreateCompletionStream: %v", err)
+	}
+	defer stream.Close()
+
+	if stream.Next() {
+		t.Fatalf("Next = true with chunk %+v, want false", stream.Chunk())
+	}
+
+	var pe *llm.ProviderError
+	if !errors.As(stream.Err(), &pe) || pe.StatusCode != 529 || !llm.IsRetryable(pe) {
+		t.Errorf("stream error = %v, want retryable ProviderError 529", stream.Err())
+	}
+}
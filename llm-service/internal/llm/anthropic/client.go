@@ -0,0 +1,108 @@
+package anthropic_llm
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"llm-service/internal/llm"
+)
+
+// Ensure implementation
+var _ llm.CompletionProvider = (*CompletionProvider)(nil)
+
+const (
+	defaultBaseURL   = "https://api.anthropic.com"
+	defaultMaxTokens = 8192
+	apiVersion       = "2023-06-01"
+)
+
+// CompletionProvider implements llm.CompletionProvider over the Anthropic Messages API
+type CompletionProvider struct {
+	httpClient *http.Client
+	baseURL    string
+	apiKey     string
+	model      string
+	maxTokens  int
+	name       string
+}
+
+type Option func(*CompletionProvider)
+
+// WithName sets the provider name used in errors and logs
+func WithName(name string) Option {
+	return func(c *CompletionProvider) {
+		c.name = name
+	}
+}
+
+// WithBaseURL overrides the default API endpoint
+func WithBaseURL(baseURL string) Option {
+	return func(c *CompletionProvider) {
+		if baseURL != "" {
+			c.baseURL = strings.TrimSuffix(baseURL, "/")
+		}
+	}
+}
+
+// WithMaxTokens sets max_tokens, which is required by the Messages API
+func WithMaxTokens(maxTokens int) Option {
+	return func(c *CompletionProvider) {
+		if maxTokens > 0 {
+			c.maxTokens = maxTokens
+		}
+	}
+}
+
+func New(httpClient *http.Client, apiKey, model string, opts ...Option) *CompletionProvider {
+	c := &CompletionProvider{
+		httpClient: httpClient,
+		baseURL:    defaultBaseURL,
+		apiKey:     apiKey,
+		model:      model,
+		maxTokens:  defaultMaxTokens,
+		name:       "anthropic",
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// do sends a Messages API request and returns the response for successful status codes
+func (c *CompletionProvider) do(ctx context.Context, body messagesRequest) (*http.Response, error) {
+	data, err := json.Marshal(body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal messages request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL+"/v1/messages", bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create messages request: %w", err)
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("x-api-key", c.apiKey)
+	req.Header.Set("anthropic-version", apiVersion)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, &llm.ProviderError{Provider: c.name, Err: err}
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		defer resp.Body.Close()
+		respBody, _ := io.ReadAll(resp.Body)
+		return nil, &llm.ProviderError{
+			Provider:   c.name,
+			StatusCode: resp.StatusCode,
+			Err:        fmt.Errorf("%s", strings.TrimSpace(string(respBody))),
+		}
+	}
+
+	return resp, nil
+}
@@ -0,0 +1,41 @@
+package anthropic_llm
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"llm-service/internal/llm"
+
+	"github.com/opentracing/opentracing-go"
+)
+
+func (c *CompletionProvider) CreateCompletion(ctx context.Context, p llm.ChatParams) (string, llm.Usage, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "llm.anthropic.CreateCompletion")
+	defer span.Finish()
+
+	resp, err := c.do(ctx, c.newRequest(p, false))
+	if err != nil {
+		return "", llm.Usage{}, fmt.Errorf("failed to create message: %w", err)
+	}
+	defer resp.Body.Close()
+
+	var out messagesResponse
+	if err := json.NewDecoder(resp.Body).Decode(&out); err != nil {
+		return "", llm.Usage{}, fmt.Errorf("failed to decode message response: %w", err)
+	}
+
+	var text strings.Builder
+	for _, block := range out.Content {
+		if block.Type == "text" {
+			text.WriteString(block.Text)
+		}
+	}
+
+	if text.Len() == 0 {
+		return "", llm.Usage{}, fmt.Errorf("empty response from Anthropic")
+	}
+
+	return text.String(), out.Usage.toLLM(), nil
+}
@@ -0,0 +1,165 @@
+package anthropic_llm
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"llm-service/internal/llm"
+
+	"github.com/opentracing/opentracing-go"
+)
+
+// CreateCompletionStream implements llm.CompletionProvider via Messages API server-sent events.
+func (c *CompletionProvider) CreateCompletionStream(ctx context.Context, p llm.ChatParams) (llm.ChatStream, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "llm.anthropic.CreateCompletionStream")
+	defer span.Finish()
+
+	resp, err := c.do(ctx, c.newRequest(p, true))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create message stream: %w", err)
+	}
+
+	scanner := bufio.NewScanner(resp.Body)
+	scanner.Buffer(make([]byte, 0, 64*1024), 4*1024*1024)
+
+	return &chatStream{
+		body:         resp.Body,
+		scanner:      scanner,
+		provider:     c.name,
+		includeUsage: p.IncludeUsage,
+	}, nil
+}
+
+type streamEvent struct {
+	Type  string `json:"type"`
+	Index int    `json:"index"`
+
+	Message *struct {
+		Usage usage `json:"usage"`
+	} `json:"message,omitempty"`
+
+	ContentBlock *contentBlock `json:"content_block,omitempty"`
+
+	Delta *struct {
+		Type        string `json:"type"`
+		Text        string `json:"text"`
+		PartialJSON string `json:"partial_json"`
+	} `json:"delta,omitempty"`
+
+	Usage *usage `json:"usage,omitempty"`
+
+	Error *struct {
+		Type    string `json:"type"`
+		Message string `json:"message"`
+	} `json:"error,omitempty"`
+}
+
+type chatStream struct {
+	body         io.ReadCloser
+	scanner      *bufio.Scanner
+	provider     string
+	includeUsage bool
+
+	current llm.ChatDelta
+	usage   usage
+	err     error
+	done    bool
+}
+
+func (s *chatStream) Chunk() llm.ChatDelta { return s.current }
+func (s *chatStream) Err() error           { return s.err }
+func (s *chatStream) Close() error         { return s.body.Close() }
+
+// Next reads SSE events until one of them produces a delta for the caller
+func (s *chatStream) Next() bool {
+	if s.done {
+		return false
+	}
+
+	for s.scanner.Scan() {
+		line := s.scanner.Text()
+		if !strings.HasPrefix(line, "data:") {
+			continue
+		}
+
+		var ev streamEvent
+		if err := json.Unmarshal([]byte(strings.TrimSpace(strings.TrimPrefix(line, "data:"))), &ev); err != nil {
+			s.err = fmt.Errorf("failed to decode stream event: %w", err)
+			s.done = true
+			return false
+		}
+
+		delta, ok := s.handle(ev)
+		if s.done {
+			return ok
+		}
+		if ok {
+			s.current = delta
+			return true
+		}
+	}
+
+	if err := s.scanner.Err(); err != nil {
+		s.err = &llm.ProviderError{Provider: s.provider, Err: err}
+	}
+	s.done = true
+	return false
+}
+
+func (s *chatStream) handle(ev streamEvent) (llm.ChatDelta, bool) {
+	switch ev.Type {
+	case "message_start":
+		if ev.Message != nil {
+			s.usage = ev.Message.Usage
+		}
+	case "content_block_start":
+		if ev.ContentBlock != nil && ev.ContentBlock.Type == "tool_use" {
+			return llm.ChatDelta{ToolCalls: []llm.ToolCallDelta{{
+				Index: ev.Index,
+				ID:    ev.ContentBlock.ID,
+				Name:  ev.ContentBlock.Name,
+			}}}, true
+		}
+	case "content_block_delta":
+		if ev.Delta == nil {
+			return llm.ChatDelta{}, false
+		}
+		switch ev.Delta.Type {
+		case "text_delta":
+			return llm.ChatDelta{Content: ev.Delta.Text}, ev.Delta.Text != ""
+		case "input_json_delta":
+			return llm.ChatDelta{ToolCalls: []llm.ToolCallDelta{{
+				Index:     ev.Index,
+				Arguments: ev.Delta.PartialJSON,
+			}}}, ev.Delta.PartialJSON != ""
+		}
+	case "message_delta":
+		if ev.Usage != nil {
+			s.usage.OutputTokens = ev.Usage.OutputTokens
+		}
+	case "message_stop":
+		s.done = true
+		if s.includeUsage {
+			s.current = llm.ChatDelta{Usage: s.usage.toLLM()}
+			return s.current, true
+		}
+	case "error":
+		s.done = true
+		status := http.StatusInternalServerError
+		msg := "stream error"
+		if ev.Error != nil {
+			msg = ev.Error.Type + ": " + ev.Error.Message
+			if ev.Error.Type == "overloaded_error" {
+				status = 529
+			}
+		}
+		s.err = &llm.ProviderError{Provider: s.provider, StatusCode: status, Err: fmt.Errorf("%s", msg)}
+	}
+
+	return llm.ChatDelta{}, false
+}
@@ -0,0 +1,181 @@
+package anthropic_llm
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"llm-service/internal/llm"
+)
+
+type messagesRequest struct {
+	Model     string      `json:"model"`
+	MaxTokens int         `json:"max_tokens"`
+	System    string      `json:"system,omitempty"`
+	Messages  []message   `json:"messages"`
+	Tools     []toolParam `json:"tools,omitempty"`
+	Stream    bool        `json:"stream,omitempty"`
+}
+
+type message struct {
+	Role    string         `json:"role"`
+	Content []contentBlock `json:"content"`
+}
+
+type contentBlock struct {
+	Type string `json:"type"`
+	// text
+	Text string `json:"text,omitempty"`
+	// tool_use
+	ID    string          `json:"id,omitempty"`
+	Name  string          `json:"name,omitempty"`
+	Input json.RawMessage `json:"input,omitempty"`
+	// tool_result
+	ToolUseID string `json:"tool_use_id,omitempty"`
+	Content   string `json:"content,omitempty"`
+	// image, document
+	Source *source `json:"source,omitempty"`
+}
+
+type source struct {
+	Type      string `json:"type"`
+	MediaType string `json:"media_type"`
+	Data      string `json:"data"`
+}
+
+type toolParam struct {
+	Name        string         `json:"name"`
+	Description string         `json:"description,omitempty"`
+	InputSchema map[string]any `json:"input_schema"`
+}
+
+type usage struct {
+	InputTokens  int `json:"input_tokens"`
+	OutputTokens int `json:"output_tokens"`
+}
+
+func (u usage) toLLM() llm.Usage {
+	return llm.Usage{
+		PromptTokens:     u.InputTokens,
+		CompletionTokens: u.OutputTokens,
+		TotalTokens:      u.InputTokens + u.OutputTokens,
+	}
+}
+
+type messagesResponse struct {
+	Content []contentBlock `json:"content"`
+	Usage   usage          `json:"usage"`
+}
+
+// toContentBlocks maps multi-part user content; images and PDF documents are sent inline as base64
+func toContentBlocks(parts []llm.ContentPart) []contentBlock {
+	blocks := make([]contentBlock, 0, len(parts))
+	for _, part := range parts {
+		switch part.Type {
+		case llm.ContentPartText:
+			if part.Text != "" {
+				blocks = append(blocks, contentBlock{Type: "text", Text: part.Text})
+			}
+		case llm.ContentPartImage:
+			blocks = append(blocks, contentBlock{Type: "image", Source: newBase64Source(part)})
+		case llm.ContentPartFile:
+			blocks = append(blocks, contentBlock{Type: "document", Source: newBase64Source(part)})
+		}
+	}
+	return blocks
+}
+
+func newBase64Source(part llm.ContentPart) *source {
+	return &source{Type: "base64", MediaType: part.MediaType, Data: base64.StdEncoding.EncodeToString(part.Data)}
+}
+
+// newRequest maps generic chat params to a Messages API request.
+// System messages are lifted into the top-level system field; tool results
+// become tool_result blocks of a user turn; consecutive turns of the same
+// role are merged because the API expects alternating roles.
+func (c *CompletionProvider) newRequest(p llm.ChatParams, stream bool) messagesRequest {
+	model := c.model
+	if p.Model != nil {
+		model = *p.Model
+	}
+
+	req := messagesRequest{
+		Model:     model,
+		MaxTokens: c.maxTokens,
+		Stream:    stream,
+	}
+
+	var system []string
+	for _, m := range p.Messages {
+		var (
+			role   string
+			blocks []contentBlock
+		)
+
+		switch m.Role {
+		case llm.RoleSystem:
+			if m.Content != "" {
+				system = append(system, m.Content)
+			}
+			continue
+		case llm.RoleUser:
+			role = "user"
+			if len(m.Parts) > 0 {
+				blocks = append(blocks, toContentBlocks(m.Parts)...)
+			} else if m.Content != "" {
+				blocks = append(blocks, contentBlock{Type: "text", Text: m.Content})
+			}
+		case llm.RoleAssistant:
+			role = "assistant"
+			if m.Content != "" {
+				blocks = append(blocks, contentBlock{Type: "text", Text: m.Content})
+			}
+			for _, tc := range m.ToolCalls {
+				input := json.RawMessage(tc.Arguments)
+				if strings.TrimSpace(tc.Arguments) == "" {
+					input = json.RawMessage("{}")
+				}
+				blocks = append(blocks, contentBlock{Type: "tool_use", ID: tc.ID, Name: tc.Name, Input: input})
+			}
+		case llm.RoleTool:
+			role = "user"
+			blocks = append(blocks, contentBlock{Type: "tool_result", ToolUseID: m.ToolCallID, Content: m.Content})
+		default:
+			continue
+		}
+
+		if len(blocks) == 0 {
+			continue
+		}
+
+		if n := len(req.Messages); n > 0 && req.Messages[n-1].Role == role {
+			req.Messages[n-1].Content = append(req.Messages[n-1].Content, blocks...)
+			continue
+		}
+		req.Messages = append(req.Messages, message{Role: role, Content: blocks})
+	}
+
+	// Messages API has no response_format, so the schema goes into the system prompt
+	// and the caller validates the answer
+	if p.ResponseFormat != nil {
+		schema, _ := json.Marshal(p.ResponseFormat.Schema)
+		system = append(system, fmt.Sprintf("Answer with a single JSON object matching this JSON Schema, without markdown or any other text:\n%s", schema))
+	}
+
+	req.System = strings.Join(system, "\n\n")
+
+	for _, t := range p.Tools {
+		schema := t.Parameters
+		if schema == nil {
+			schema = map[string]any{"type": "object", "properties": map[string]any{}}
+		}
+		req.Tools = append(req.Tools, toolParam{
+			Name:        t.Name,
+			Description: t.Description,
+			InputSchema: schema,
+		})
+	}
+
+	return req
+}
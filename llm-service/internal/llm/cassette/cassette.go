@@ -0,0 +1,206 @@
+package cassette
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+
+	"llm-service/internal/llm"
+)
+
+// Cassette is a sequence of recorded provider calls stored as JSON
+type Cassette struct {
+	Interactions []Interaction `json:"interactions"`
+}
+
+// Interaction is a single CreateCompletion or CreateCompletionStream call
+type Interaction struct {
+	Request Request `json:"request"`
+	Stream  bool    `json:"stream"`
+
+	// Stream responses
+	Chunks []Chunk `json:"chunks,omitempty"`
+
+	// Non-stream responses
+	Content string `json:"content,omitempty"`
+	Usage   *Usage `json:"usage,omitempty"`
+
+	// Error ends the interaction. For streams without chunks it is returned
+	// when the stream is opened, otherwise from Err after the last chunk.
+	Error *Error `json:"error,omitempty"`
+}
+
+// Request keeps what was sent to the model, for debugging and diffing cassettes
+type Request struct {
+	Model    string    `json:"model,omitempty"`
+	Messages []Message `json:"messages"`
+	Tools    []string  `json:"tools,omitempty"`
+	// ResponseFormat is the name of the requested JSON schema
+	ResponseFormat string `json:"response_format,omitempty"`
+}
+
+type Message struct {
+	Role       string     `json:"role"`
+	Content    string     `json:"content,omitempty"`
+	Parts      []Part     `json:"parts,omitempty"`
+	ToolCalls  []ToolCall `json:"tool_calls,omitempty"`
+	ToolCallID string     `json:"tool_call_id,omitempty"`
+}
+
+// Part is a content part; binary data is not stored, only its size
+type Part struct {
+	Type      string `json:"type"`
+	Text      string `json:"text,omitempty"`
+	MediaType string `json:"media_type,omitempty"`
+	FileName  string `json:"file_name,omitempty"`
+	Size      int    `json:"size,omitempty"`
+}
+
+type ToolCall struct {
+	ID        string `json:"id,omitempty"`
+	Name      string `json:"name"`
+	Arguments string `json:"arguments"`
+}
+
+type Chunk struct {
+	Content   string          `json:"content,omitempty"`
+	ToolCalls []ToolCallDelta `json:"tool_calls,omitempty"`
+	Usage     *Usage          `json:"usage,omitempty"`
+}
+
+type ToolCallDelta struct {
+	Index     int    `json:"index"`
+	ID        string `json:"id,omitempty"`
+	Name      string `json:"name,omitempty"`
+	Arguments string `json:"arguments,omitempty"`
+}
+
+type Usage struct {
+	PromptTokens     int `json:"prompt_tokens"`
+	CompletionTokens int `json:"completion_tokens"`
+	TotalTokens      int `json:"total_tokens"`
+}
+
+type Error struct {
+	Provider   string `json:"provider,omitempty"`
+	StatusCode int    `json:"status_code,omitempty"`
+	Message    string `json:"message"`
+}
+
+// Load reads a cassette from a JSON file
+func Load(path string) (*Cassette, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read cassette: %w", err)
+	}
+
+	var c Cassette
+	if err := json.Unmarshal(data, &c); err != nil {
+		return nil, fmt.Errorf("failed to decode cassette %s: %w", path, err)
+	}
+
+	return &c, nil
+}
+
+// Save writes the cassette as indented JSON
+func (c *Cassette) Save(path string) error {
+	data, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode cassette: %w", err)
+	}
+
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		return fmt.Errorf("failed to write cassette: %w", err)
+	}
+
+	return nil
+}
+
+func newRequest(p llm.ChatParams) Request {
+	r := Request{Messages: make([]Message, 0, len(p.Messages))}
+	if p.Model != nil {
+		r.Model = *p.Model
+	}
+
+	if p.ResponseFormat != nil {
+		r.ResponseFormat = p.ResponseFormat.Name
+	}
+
+	for _, m := range p.Messages {
+		msg := Message{Role: m.Role, Content: m.Content, ToolCallID: m.ToolCallID}
+		for _, part := range m.Parts {
+			msg.Parts = append(msg.Parts, Part{Type: string(part.Type), Text: part.Text, MediaType: part.MediaType, FileName: part.FileName, Size: len(part.Data)})
+		}
+		for _, tc := range m.ToolCalls {
+			msg.ToolCalls = append(msg.ToolCalls, ToolCall{ID: tc.ID, Name: tc.Name, Arguments: tc.Arguments})
+		}
+		r.Messages = append(r.Messages, msg)
+	}
+
+	for _, t := range p.Tools {
+		r.Tools = append(r.Tools, t.Name)
+	}
+
+	return r
+}
+
+func newChunk(d llm.ChatDelta) Chunk {
+	c := Chunk{Content: d.Content, Usage: newUsage(d.Usage)}
+	for _, tc := range d.ToolCalls {
+		c.ToolCalls = append(c.ToolCalls, ToolCallDelta(tc))
+	}
+	return c
+}
+
+func (c Chunk) toLLM() llm.ChatDelta {
+	d := llm.ChatDelta{Content: c.Content, Usage: c.Usage.toLLM()}
+	for _, tc := range c.ToolCalls {
+		d.ToolCalls = append(d.ToolCalls, llm.ToolCallDelta(tc))
+	}
+	return d
+}
+
+func newUsage(u llm.Usage) *Usage {
+	if u == (llm.Usage{}) {
+		return nil
+	}
+	return &Usage{
+		PromptTokens:     u.PromptTokens,
+		CompletionTokens: u.CompletionTokens,
+		TotalTokens:      u.TotalTokens,
+	}
+}
+
+func (u *Usage) toLLM() llm.Usage {
+	if u == nil {
+		return llm.Usage{}
+	}
+	return llm.Usage{
+		PromptTokens:     u.PromptTokens,
+		CompletionTokens: u.CompletionTokens,
+		TotalTokens:      u.TotalTokens,
+	}
+}
+
+func newError(err error) *Error {
+	if err == nil {
+		return nil
+	}
+
+	e := &Error{Message: err.Error()}
+	var pe *llm.ProviderError
+	if errors.As(err, &pe) {
+		e.Provider = pe.Provider
+		e.StatusCode = pe.StatusCode
+	}
+	return e
+}
+
+// toLLM restores the error as llm.ProviderError so retry logic behaves as it did live
+func (e *Error) toLLM() error {
+	if e == nil {
+		return nil
+	}
+	return &llm.ProviderError{Provider: e.Provider, StatusCode: e.StatusCode, Err: errors.New(e.Message)}
+}
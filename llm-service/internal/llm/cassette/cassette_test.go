@@ -0,0 +1,137 @@
+package cassette
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"llm-service/internal/llm"
+	"llm-service/internal/llm/fake"
+	"llm-service/internal/logger"
+)
+
+func TestMain(m *testing.M) {
+	logger.Init()
+	os.Exit(m.Run())
+}
+
+func drain(t *testing.T, s llm.ChatStream) ([]llm.ChatDelta, error) {
+	t.Helper()
+	defer s.Close()
+
+	var chunks []llm.ChatDelta
+	for s.Next() {
+		chunks = append(chunks, s.Chunk())
+	}
+	return chunks, s.Err()
+}
+
+func TestRecordAndReplay(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "cassette.json")
+
+	rateLimited := &llm.ProviderError{Provider: "openai", StatusCode: 429, Err: errors.New("rate limit")}
+	provider := fake.New(
+		fake.Turn{
+			Content:   "Проверяю",
+			ToolCalls: []llm.ToolCall{fake.NewToolCall("web_search", map[string]any{"query": "ИНН 7707083893"})},
+			Usage:     llm.Usage{PromptTokens: 10, CompletionTokens: 5, TotalTokens: 15},
+			ChunkSize: 5,
+		},
+		fake.Turn{Err: rateLimited},
+		fake.Text("Название чата"),
+	)
+
+	params := llm.ChatParams{
+		Messages:     []llm.MessageParam{{Role: llm.RoleUser, Content: "Проверь контрагента"}},
+		Tools:        []llm.ToolDefinition{{Name: "web_search"}},
+		IncludeUsage: true,
+	}
+
+	recorder := NewRecorder(provider, path)
+
+	stream, err := recorder.CreateCompletionStream(ctx, params)
+	if err != nil {
+		t.Fatalf("CreateCompletionStream: %v", err)
+	}
+	recorded, err := drain(t, stream)
+	if err != nil {
+		t.Fatalf("stream error: %v", err)
+	}
+
+	if _, err := recorder.CreateCompletionStream(ctx, params); !errors.Is(err, rateLimited) {
+		t.Fatalf("error = %v, want rate limit", err)
+	}
+
+	title, _, err := recorder.CreateCompletion(ctx, params)
+	if err != nil {
+		t.Fatalf("CreateCompletion: %v", err)
+	}
+
+	player, err := Open(path)
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	if player.Remaining() != 3 {
+		t.Fatalf("interactions = %d, want 3", player.Remaining())
+	}
+
+	stream, err = player.CreateCompletionStream(ctx, params)
+	if err != nil {
+		t.Fatalf("replay CreateCompletionStream: %v", err)
+	}
+	replayed, err := drain(t, stream)
+	if err != nil {
+		t.Fatalf("replay stream error: %v", err)
+	}
+	if !reflect.DeepEqual(recorded, replayed) {
+		t.Errorf("replayed chunks differ:\nrecorded %+v\nreplayed %+v", recorded, replayed)
+	}
+
+	_, err = player.CreateCompletionStream(ctx, params)
+	var pe *llm.ProviderError
+	if !errors.As(err, &pe) || pe.StatusCode != 429 || !llm.IsRetryable(err) {
+		t.Errorf("replayed error = %v, want retryable 429", err)
+	}
+
+	replayedTitle, _, err := player.CreateCompletion(ctx, params)
+	if err != nil || replayedTitle != title {
+		t.Errorf("replayed completion = %q, %v; want %q", replayedTitle, err, title)
+	}
+
+	if _, _, err := player.CreateCompletion(ctx, params); err == nil {
+		t.Error("expected error when cassette is exhausted")
+	}
+}
+
+func TestReplayStreamErrorAfterChunks(t *testing.T) {
+	player := NewPlayer(&Cassette{Interactions: []Interaction{{
+		Stream: true,
+		Chunks: []Chunk{{Content: "Частичный "}, {Content: "ответ"}},
+		Error:  &Error{Provider: "anthropic", StatusCode: 529, Message: "overloaded"},
+	}}})
+
+	stream, err := player.CreateCompletionStream(context.Background(), llm.ChatParams{})
+	if err != nil {
+		t.Fatalf("CreateCompletionStream: %v", err)
+	}
+
+	chunks, err := drain(t, stream)
+	if len(chunks) != 2 {
+		t.Errorf("chunks = %d, want 2", len(chunks))
+	}
+	if !llm.IsRetryable(err) {
+		t.Errorf("error = %v, want retryable provider error", err)
+	}
+}
+
+func TestPlayerRejectsModeMismatch(t *testing.T) {
+	player := NewPlayer(&Cassette{Interactions: []Interaction{{Stream: true}}})
+
+	if _, _, err := player.CreateCompletion(context.Background(), llm.ChatParams{}); err == nil {
+		t.Error("expected error for non-stream call against a stream interaction")
+	}
+}
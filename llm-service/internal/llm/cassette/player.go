@@ -0,0 +1,128 @@
+package cassette
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"llm-service/internal/llm"
+)
+
+// Ensure implementation
+var _ llm.CompletionProvider = (*Player)(nil)
+
+// Player replays recorded interactions in order, regardless of the request.
+// Requests received during playback are kept for assertions.
+type Player struct {
+	mu           sync.Mutex
+	interactions []Interaction
+	next         int
+	requests     []llm.ChatParams
+}
+
+func NewPlayer(c *Cassette) *Player {
+	return &Player{interactions: c.Interactions}
+}
+
+// Open loads a cassette file and returns a player for it
+func Open(path string) (*Player, error) {
+	c, err := Load(path)
+	if err != nil {
+		return nil, err
+	}
+	return NewPlayer(c), nil
+}
+
+// Requests returns params of every call made to the player
+func (p *Player) Requests() []llm.ChatParams {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	requests := make([]llm.ChatParams, len(p.requests))
+	copy(requests, p.requests)
+	return requests
+}
+
+// Remaining returns the number of interactions not replayed yet
+func (p *Player) Remaining() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return len(p.interactions) - p.next
+}
+
+func (p *Player) CreateCompletion(_ context.Context, params llm.ChatParams) (string, llm.Usage, error) {
+	i, err := p.take(params, false)
+	if err != nil {
+		return "", llm.Usage{}, err
+	}
+
+	if i.Error != nil {
+		return "", llm.Usage{}, i.Error.toLLM()
+	}
+
+	return i.Content, i.Usage.toLLM(), nil
+}
+
+func (p *Player) CreateCompletionStream(_ context.Context, params llm.ChatParams) (llm.ChatStream, error) {
+	i, err := p.take(params, true)
+	if err != nil {
+		return nil, err
+	}
+
+	if i.Error != nil && len(i.Chunks) == 0 {
+		return nil, i.Error.toLLM()
+	}
+
+	return &replayStream{chunks: i.Chunks, err: i.Error.toLLM(), pos: -1}, nil
+}
+
+func (p *Player) take(params llm.ChatParams, stream bool) (Interaction, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.requests = append(p.requests, params)
+
+	if p.next >= len(p.interactions) {
+		return Interaction{}, fmt.Errorf("cassette exhausted after %d interactions", len(p.interactions))
+	}
+
+	i := p.interactions[p.next]
+	if i.Stream != stream {
+		return Interaction{}, fmt.Errorf("cassette interaction %d: stream=%t recorded, stream=%t requested", p.next, i.Stream, stream)
+	}
+
+	p.next++
+	return i, nil
+}
+
+type replayStream struct {
+	chunks []Chunk
+	err    error
+	pos    int
+}
+
+func (s *replayStream) Next() bool {
+	if s.pos+1 >= len(s.chunks) {
+		s.pos = len(s.chunks)
+		return false
+	}
+	s.pos++
+	return true
+}
+
+func (s *replayStream) Chunk() llm.ChatDelta {
+	if s.pos < 0 || s.pos >= len(s.chunks) {
+		return llm.ChatDelta{}
+	}
+	return s.chunks[s.pos].toLLM()
+}
+
+// Err returns the recorded error once all chunks are consumed
+func (s *replayStream) Err() error {
+	if s.pos < len(s.chunks) {
+		return nil
+	}
+	return s.err
+}
+
+func (s *replayStream) Close() error { return nil }
@@ -0,0 +1,126 @@
+package cassette
+
+import (
+	"context"
+	"sync"
+
+	"llm-service/internal/llm"
+	"llm-service/internal/logger"
+)
+
+// Ensure implementation
+var _ llm.CompletionProvider = (*Recorder)(nil)
+
+// Recorder proxies calls to a real provider and writes every interaction to
+// a cassette file. The file is rewritten after each interaction, so a cassette
+// survives the process being stopped mid-session.
+type Recorder struct {
+	inner llm.CompletionProvider
+	path  string
+
+	mu       sync.Mutex
+	cassette Cassette
+}
+
+func NewRecorder(inner llm.CompletionProvider, path string) *Recorder {
+	return &Recorder{
+		inner: inner,
+		path:  path,
+	}
+}
+
+// Cassette returns a copy of what has been recorded so far
+func (r *Recorder) Cassette() *Cassette {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	c := Cassette{Interactions: make([]Interaction, len(r.cassette.Interactions))}
+	copy(c.Interactions, r.cassette.Interactions)
+	return &c
+}
+
+func (r *Recorder) CreateCompletion(ctx context.Context, p llm.ChatParams) (string, llm.Usage, error) {
+	content, usage, err := r.inner.CreateCompletion(ctx, p)
+
+	r.record(ctx, Interaction{
+		Request: newRequest(p),
+		Content: content,
+		Usage:   newUsage(usage),
+		Error:   newError(err),
+	})
+
+	return content, usage, err
+}
+
+func (r *Recorder) CreateCompletionStream(ctx context.Context, p llm.ChatParams) (llm.ChatStream, error) {
+	stream, err := r.inner.CreateCompletionStream(ctx, p)
+	if err != nil {
+		r.record(ctx, Interaction{
+			Request: newRequest(p),
+			Stream:  true,
+			Error:   newError(err),
+		})
+		return nil, err
+	}
+
+	return &recordingStream{
+		inner:    stream,
+		recorder: r,
+		ctx:      ctx,
+		interaction: Interaction{
+			Request: newRequest(p),
+			Stream:  true,
+		},
+	}, nil
+}
+
+func (r *Recorder) record(ctx context.Context, i Interaction) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.cassette.Interactions = append(r.cassette.Interactions, i)
+	if r.path == "" {
+		return
+	}
+	if err := r.cassette.Save(r.path); err != nil {
+		logger.Error(ctx, "Failed to save LLM cassette", "path", r.path, "error", err)
+	}
+}
+
+// recordingStream passes chunks through and records the interaction once the
+// stream is drained or closed
+type recordingStream struct {
+	inner    llm.ChatStream
+	recorder *Recorder
+	ctx      context.Context
+
+	interaction Interaction
+	recorded    bool
+}
+
+func (s *recordingStream) Next() bool {
+	if s.inner.Next() {
+		s.interaction.Chunks = append(s.interaction.Chunks, newChunk(s.inner.Chunk()))
+		return true
+	}
+
+	s.finish()
+	return false
+}
+
+func (s *recordingStream) Chunk() llm.ChatDelta { return s.inner.Chunk() }
+func (s *recordingStream) Err() error           { return s.inner.Err() }
+
+func (s *recordingStream) Close() error {
+	s.finish()
+	return s.inner.Close()
+}
+
+func (s *recordingStream) finish() {
+	if s.recorded {
+		return
+	}
+	s.recorded = true
+	s.interaction.Error = newError(s.inner.Err())
+	s.recorder.record(s.ctx, s.interaction)
+}
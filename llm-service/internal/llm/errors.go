@@ -0,0 +1,54 @@
+package llm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+// ProviderError is an error returned by an LLM provider API.
+// StatusCode is 0 when the request did not reach the API (network failure).
+type ProviderError struct {
+	Provider   string
+	StatusCode int
+	Err        error
+}
+
+func (e *ProviderError) Error() string {
+	if e.StatusCode == 0 {
+		return fmt.Sprintf("%s: %v", e.Provider, e.Err)
+	}
+	return fmt.Sprintf("%s: status %d: %v", e.Provider, e.StatusCode, e.Err)
+}
+
+func (e *ProviderError) Unwrap() error { return e.Err }
+
+// IsRetryable reports whether a request may succeed if repeated later or sent
+// to another provider: rate limits, server errors and network failures.
+func IsRetryable(err error) bool {
+	if err == nil {
+		return false
+	}
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+
+	var pe *ProviderError
+	if !errors.As(err, &pe) {
+		return false
+	}
+
+	switch {
+	case pe.StatusCode == 0:
+		return true
+	case pe.StatusCode == http.StatusTooManyRequests:
+		return true
+	case pe.StatusCode == http.StatusRequestTimeout:
+		return true
+	case pe.StatusCode >= http.StatusInternalServerError:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,48 @@
+package llm_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"llm-service/internal/llm"
+)
+
+func TestIsRetryable(t *testing.T) {
+	providerErr := func(status int) error {
+		return &llm.ProviderError{Provider: "openai", StatusCode: status, Err: errors.New("failed")}
+	}
+
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{name: "nil", err: nil, want: false},
+		{name: "network", err: providerErr(0), want: true},
+		{name: "429", err: providerErr(http.StatusTooManyRequests), want: true},
+		{name: "408", err: providerErr(http.StatusRequestTimeout), want: true},
+		{name: "500", err: providerErr(http.StatusInternalServerError), want: true},
+		{name: "529 overloaded", err: providerErr(529), want: true},
+		{name: "400", err: providerErr(http.StatusBadRequest), want: false},
+		{name: "401", err: providerErr(http.StatusUnauthorized), want: false},
+		{name: "wrapped", err: fmt.Errorf("failed to create message: %w", providerErr(http.StatusBadGateway)), want: true},
+		{name: "not a provider error", err: errors.New("decode failed"), want: false},
+		{name: "canceled", err: context.Canceled, want: false},
+		{
+			name: "network failure caused by cancellation",
+			err:  &llm.ProviderError{Provider: "openai", Err: fmt.Errorf("post: %w", context.DeadlineExceeded)},
+			want: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := llm.IsRetryable(tt.err); got != tt.want {
+				t.Errorf("IsRetryable(%v) = %v, want %v", tt.err, got, tt.want)
+			}
+		})
+	}
+}
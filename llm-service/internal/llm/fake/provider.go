@@ -0,0 +1,191 @@
+package fake
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+
+	"llm-service/internal/llm"
+)
+
+// Ensure implementation
+var _ llm.CompletionProvider = (*Provider)(nil)
+
+// defaultChunkSize is how many runes of content or tool arguments go into one streamed delta
+const defaultChunkSize = 8
+
+// Turn is one scripted model response
+type Turn struct {
+	Content   string
+	ToolCalls []llm.ToolCall
+	Usage     llm.Usage
+	// Err is returned when the call is made, before any chunk is streamed
+	Err error
+	// StreamErr is returned from Err after all chunks are streamed
+	StreamErr error
+	// ChunkSize overrides how finely content and arguments are split
+	ChunkSize int
+}
+
+// Text is a turn answering with plain text
+func Text(content string) Turn {
+	return Turn{Content: content}
+}
+
+// Call is a turn calling a single tool; args are marshaled to JSON
+func Call(name string, args any) Turn {
+	return Turn{ToolCalls: []llm.ToolCall{NewToolCall(name, args)}}
+}
+
+// NewToolCall builds a tool call with arguments marshaled to JSON
+func NewToolCall(name string, args any) llm.ToolCall {
+	data, err := json.Marshal(args)
+	if err != nil {
+		panic(fmt.Sprintf("fake: failed to marshal %s arguments: %v", name, err))
+	}
+	return llm.ToolCall{Name: name, Arguments: string(data)}
+}
+
+// Provider answers with scripted turns in order and records every request
+type Provider struct {
+	mu       sync.Mutex
+	turns    []Turn
+	requests []llm.ChatParams
+}
+
+func New(turns ...Turn) *Provider {
+	return &Provider{turns: turns}
+}
+
+// Requests returns params of every call made to the provider
+func (p *Provider) Requests() []llm.ChatParams {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	requests := make([]llm.ChatParams, len(p.requests))
+	copy(requests, p.requests)
+	return requests
+}
+
+// Remaining returns the number of turns not consumed yet
+func (p *Provider) Remaining() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return len(p.turns)
+}
+
+func (p *Provider) CreateCompletion(_ context.Context, params llm.ChatParams) (string, llm.Usage, error) {
+	turn, err := p.take(params)
+	if err != nil {
+		return "", llm.Usage{}, err
+	}
+	if turn.Err != nil {
+		return "", llm.Usage{}, turn.Err
+	}
+	return turn.Content, turn.Usage, nil
+}
+
+func (p *Provider) CreateCompletionStream(_ context.Context, params llm.ChatParams) (llm.ChatStream, error) {
+	turn, err := p.take(params)
+	if err != nil {
+		return nil, err
+	}
+	if turn.Err != nil {
+		return nil, turn.Err
+	}
+
+	return &stream{chunks: turn.chunks(params.IncludeUsage), err: turn.StreamErr, pos: -1}, nil
+}
+
+func (p *Provider) take(params llm.ChatParams) (Turn, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.requests = append(p.requests, params)
+
+	if len(p.turns) == 0 {
+		return Turn{}, fmt.Errorf("fake provider: no scripted turns left (call %d)", len(p.requests))
+	}
+
+	turn := p.turns[0]
+	p.turns = p.turns[1:]
+	return turn, nil
+}
+
+// chunks splits the turn into deltas the way real providers stream them:
+// content pieces, then for each tool call a delta with id and name followed
+// by argument fragments, then usage
+func (t Turn) chunks(includeUsage bool) []llm.ChatDelta {
+	size := t.ChunkSize
+	if size <= 0 {
+		size = defaultChunkSize
+	}
+
+	var chunks []llm.ChatDelta
+
+	for _, part := range split(t.Content, size) {
+		chunks = append(chunks, llm.ChatDelta{Content: part})
+	}
+
+	for i, tc := range t.ToolCalls {
+		id := tc.ID
+		if id == "" {
+			id = fmt.Sprintf("call_%d", i)
+		}
+		chunks = append(chunks, llm.ChatDelta{ToolCalls: []llm.ToolCallDelta{{Index: i, ID: id, Name: tc.Name}}})
+
+		for _, part := range split(tc.Arguments, size) {
+			chunks = append(chunks, llm.ChatDelta{ToolCalls: []llm.ToolCallDelta{{Index: i, Arguments: part}}})
+		}
+	}
+
+	if includeUsage && t.Usage != (llm.Usage{}) {
+		chunks = append(chunks, llm.ChatDelta{Usage: t.Usage})
+	}
+
+	return chunks
+}
+
+// split cuts s into pieces of size runes, never splitting a multi-byte character
+func split(s string, size int) []string {
+	runes := []rune(s)
+
+	var parts []string
+	for start := 0; start < len(runes); start += size {
+		end := min(start+size, len(runes))
+		parts = append(parts, string(runes[start:end]))
+	}
+	return parts
+}
+
+type stream struct {
+	chunks []llm.ChatDelta
+	err    error
+	pos    int
+}
+
+func (s *stream) Next() bool {
+	if s.pos+1 >= len(s.chunks) {
+		s.pos = len(s.chunks)
+		return false
+	}
+	s.pos++
+	return true
+}
+
+func (s *stream) Chunk() llm.ChatDelta {
+	if s.pos < 0 || s.pos >= len(s.chunks) {
+		return llm.ChatDelta{}
+	}
+	return s.chunks[s.pos]
+}
+
+func (s *stream) Err() error {
+	if s.pos < len(s.chunks) {
+		return nil
+	}
+	return s.err
+}
+
+func (s *stream) Close() error { return nil }
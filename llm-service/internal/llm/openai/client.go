@@ -13,11 +13,45 @@ var _ llm.CompletionProvider = (*CompletionProvider)(nil)
 type CompletionProvider struct {
 	client openai.Client
 	cfg    *config.Config
+
+	// Optional overrides of config defaults, used when several
+	// OpenAI-compatible backends are configured
+	name            string
+	model           string
+	reasoningEffort *string
+}
+
+type Option func(*CompletionProvider)
+
+// WithName sets the provider name used in errors and logs
+func WithName(name string) Option {
+	return func(c *CompletionProvider) {
+		c.name = name
+	}
+}
+
+// WithModel overrides the default model from config
+func WithModel(model string) Option {
+	return func(c *CompletionProvider) {
+		c.model = model
+	}
+}
+
+// WithReasoningEffort overrides the default reasoning effort from config
+func WithReasoningEffort(effort string) Option {
+	return func(c *CompletionProvider) {
+		c.reasoningEffort = &effort
+	}
 }
 
-func New(client openai.Client, cfg *config.Config) *CompletionProvider {
-	return &CompletionProvider{
+func New(client openai.Client, cfg *config.Config, opts ...Option) *CompletionProvider {
+	c := &CompletionProvider{
 		client: client,
 		cfg:    cfg,
+		name:   "openai",
+	}
+	for _, opt := range opts {
+		opt(c)
 	}
+	return c
 }
@@ -16,7 +16,7 @@ func (c *CompletionProvider) CreateCompletion(ctx context.Context, p llm.ChatPar
 
 	completion, err := c.client.Chat.Completions.New(ctx, params)
 	if err != nil {
-		return "", llm.Usage{}, fmt.Errorf("failed to create chat completion for prompt: %w", err)
+		return "", llm.Usage{}, fmt.Errorf("failed to create chat completion for prompt: %w", wrapError(c.name, err))
 	}
 
 	if len(completion.Choices) == 0 {
@@ -18,8 +18,8 @@ func (c *CompletionProvider) CreateCompletionStream(ctx context.Context, p llm.C
 
 	stream := c.client.Chat.Completions.NewStreaming(ctx, req)
 	if err := stream.Err(); err != nil {
-		return nil, fmt.Errorf("failed to create chat completion stream: %w", err)
+		return nil, fmt.Errorf("failed to create chat completion stream: %w", wrapError(c.name, err))
 	}
 
-	return &chatStream{inner: stream}, nil
+	return &chatStream{inner: stream, provider: c.name}, nil
 }
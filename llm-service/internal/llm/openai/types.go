@@ -1,6 +1,9 @@
 package openai_llm
 
 import (
+	"encoding/base64"
+	"errors"
+
 	"llm-service/internal/llm"
 
 	"github.com/openai/openai-go/v3"
@@ -9,13 +12,28 @@ import (
 )
 
 type chatStream struct {
-	inner *ssestream.Stream[openai.ChatCompletionChunk]
+	inner    *ssestream.Stream[openai.ChatCompletionChunk]
+	provider string
 }
 
 func (s *chatStream) Next() bool   { return s.inner.Next() }
-func (s *chatStream) Err() error   { return s.inner.Err() }
+func (s *chatStream) Err() error   { return wrapError(s.provider, s.inner.Err()) }
 func (s *chatStream) Close() error { return s.inner.Close() }
 
+// wrapError converts SDK errors to llm.ProviderError so callers can decide on retries
+func wrapError(provider string, err error) error {
+	if err == nil {
+		return nil
+	}
+
+	var apiErr *openai.Error
+	if errors.As(err, &apiErr) {
+		return &llm.ProviderError{Provider: provider, StatusCode: apiErr.StatusCode, Err: err}
+	}
+
+	return &llm.ProviderError{Provider: provider, Err: err}
+}
+
 func (s *chatStream) Chunk() llm.ChatDelta {
 	chunk := s.inner.Current()
 
@@ -46,7 +64,11 @@ func (c *CompletionProvider) toOpenAIMessages(msgs []llm.MessageParam) []openai.
 		case llm.RoleSystem:
 			out = append(out, openai.SystemMessage(m.Content))
 		case llm.RoleUser:
-			out = append(out, openai.UserMessage(m.Content))
+			if len(m.Parts) > 0 {
+				out = append(out, openai.UserMessage(c.toOpenAIContentParts(m.Parts)))
+			} else {
+				out = append(out, openai.UserMessage(m.Content))
+			}
 		case llm.RoleAssistant:
 			assistant := openai.ChatCompletionAssistantMessageParam{}
 			if m.Content != "" {
@@ -75,6 +97,34 @@ func (c *CompletionProvider) toOpenAIMessages(msgs []llm.MessageParam) []openai.
 	return out
 }
 
+// toOpenAIContentParts maps multi-part user content; images and files are sent inline as base64 data URLs.
+func (c *CompletionProvider) toOpenAIContentParts(parts []llm.ContentPart) []openai.ChatCompletionContentPartUnionParam {
+	out := make([]openai.ChatCompletionContentPartUnionParam, 0, len(parts))
+	for _, part := range parts {
+		switch part.Type {
+		case llm.ContentPartText:
+			if part.Text != "" {
+				out = append(out, openai.TextContentPart(part.Text))
+			}
+		case llm.ContentPartImage:
+			out = append(out, openai.ImageContentPart(openai.ChatCompletionContentPartImageImageURLParam{
+				URL: dataURL(part),
+			}))
+		case llm.ContentPartFile:
+			file := openai.ChatCompletionContentPartFileFileParam{FileData: openai.String(dataURL(part))}
+			if part.FileName != "" {
+				file.Filename = openai.String(part.FileName)
+			}
+			out = append(out, openai.FileContentPart(file))
+		}
+	}
+	return out
+}
+
+func dataURL(part llm.ContentPart) string {
+	return "data:" + part.MediaType + ";base64," + base64.StdEncoding.EncodeToString(part.Data)
+}
+
 // toOpenAITools maps provider-agnostic tool definitions to OpenAI format.
 func (c *CompletionProvider) toOpenAITools(tools []llm.ToolDefinition) []openai.ChatCompletionToolUnionParam {
 	out := make([]openai.ChatCompletionToolUnionParam, 0, len(tools))
@@ -93,21 +143,42 @@ func (c *CompletionProvider) toOpenAITools(tools []llm.ToolDefinition) []openai.
 	return out
 }
 
+// toOpenAIResponseFormat maps a JSON schema response format to OpenAI structured outputs.
+func (c *CompletionProvider) toOpenAIResponseFormat(f *llm.ResponseFormat) openai.ChatCompletionNewParamsResponseFormatUnion {
+	schema := shared.ResponseFormatJSONSchemaJSONSchemaParam{
+		Name:   f.Name,
+		Schema: f.Schema,
+		Strict: openai.Bool(f.Strict),
+	}
+	if f.Description != "" {
+		schema.Description = openai.String(f.Description)
+	}
+	return openai.ChatCompletionNewParamsResponseFormatUnion{
+		OfJSONSchema: &shared.ResponseFormatJSONSchemaParam{JSONSchema: schema},
+	}
+}
+
 // newOpenAIParams constructs OpenAI request from generic params and mapped components.
 func (c *CompletionProvider) newOpenAIParams(p llm.ChatParams) openai.ChatCompletionNewParams {
-	// Use model from params if specified, otherwise from config
+	// Use model from params if specified, otherwise provider override or config
 	var model string
-	if p.Model != nil {
+	switch {
+	case p.Model != nil:
 		model = *p.Model
-	} else {
+	case c.model != "":
+		model = c.model
+	default:
 		model = c.cfg.GetLLMModel()
 	}
 
-	// Use reasoning effort from params if specified, otherwise from config
+	// Use reasoning effort from params if specified, otherwise provider override or config
 	var reasoningEffort string
-	if p.ReasoningEffort != nil {
+	switch {
+	case p.ReasoningEffort != nil:
 		reasoningEffort = *p.ReasoningEffort
-	} else {
+	case c.reasoningEffort != nil:
+		reasoningEffort = *c.reasoningEffort
+	default:
 		reasoningEffort = c.cfg.GetLLMReasoningEffort()
 	}
 
@@ -126,6 +197,9 @@ func (c *CompletionProvider) newOpenAIParams(p llm.ChatParams) openai.ChatComple
 		o.Tools = c.toOpenAITools(p.Tools)
 		o.ToolChoice = openai.ChatCompletionToolChoiceOptionUnionParam{OfAuto: openai.String("auto")}
 	}
+	if p.ResponseFormat != nil {
+		o.ResponseFormat = c.toOpenAIResponseFormat(p.ResponseFormat)
+	}
 	if p.IncludeUsage {
 		o.StreamOptions = openai.ChatCompletionStreamOptionsParam{IncludeUsage: openai.Bool(true)}
 	}
@@ -0,0 +1,333 @@
+package router
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	"llm-service/internal/llm"
+	"llm-service/internal/logger"
+)
+
+// Ensure implementation
+var _ llm.CompletionProvider = (*Router)(nil)
+
+const (
+	defaultMaxAttempts      = 3
+	defaultInitialBackoff   = 500 * time.Millisecond
+	defaultMaxBackoff       = 8 * time.Second
+	defaultFailureThreshold = 3
+	defaultCooldown         = 30 * time.Second
+)
+
+// Backend is a single configured completion provider
+type Backend struct {
+	Name     string
+	Provider llm.CompletionProvider
+	// Models the backend can serve when a model is requested explicitly.
+	// Empty means any model is passed through as is.
+	Models []string
+}
+
+// serves reports whether the backend can handle the requested model
+func (b *Backend) serves(model *string) bool {
+	if model == nil || len(b.Models) == 0 {
+		return true
+	}
+	return slices.Contains(b.Models, *model)
+}
+
+type backendState struct {
+	Backend
+
+	mu                  sync.Mutex
+	consecutiveFailures int
+	unhealthyUntil      time.Time
+}
+
+// Router implements llm.CompletionProvider over several backends.
+// Each request goes to backends that serve the requested model first, then to
+// the rest with their default model. Retryable errors (429, 5xx, network) are
+// retried with exponential backoff and then fail over to the next backend;
+// backends that keep failing are skipped until the cooldown expires.
+type Router struct {
+	backends []*backendState
+
+	maxAttempts      int
+	initialBackoff   time.Duration
+	maxBackoff       time.Duration
+	failureThreshold int
+	cooldown         time.Duration
+
+	now   func() time.Time
+	sleep func(ctx context.Context, d time.Duration) error
+}
+
+type Option func(*Router)
+
+// WithRetry configures per-backend attempts and backoff bounds
+func WithRetry(maxAttempts int, initialBackoff, maxBackoff time.Duration) Option {
+	return func(r *Router) {
+		if maxAttempts > 0 {
+			r.maxAttempts = maxAttempts
+		}
+		if initialBackoff > 0 {
+			r.initialBackoff = initialBackoff
+		}
+		if maxBackoff > 0 {
+			r.maxBackoff = maxBackoff
+		}
+	}
+}
+
+// WithHealth configures after how many consecutive failures a backend is
+// considered unhealthy and for how long it is skipped
+func WithHealth(failureThreshold int, cooldown time.Duration) Option {
+	return func(r *Router) {
+		if failureThreshold > 0 {
+			r.failureThreshold = failureThreshold
+		}
+		if cooldown > 0 {
+			r.cooldown = cooldown
+		}
+	}
+}
+
+func New(backends []Backend, opts ...Option) (*Router, error) {
+	if len(backends) == 0 {
+		return nil, fmt.Errorf("at least one LLM backend is required")
+	}
+
+	r := &Router{
+		maxAttempts:      defaultMaxAttempts,
+		initialBackoff:   defaultInitialBackoff,
+		maxBackoff:       defaultMaxBackoff,
+		failureThreshold: defaultFailureThreshold,
+		cooldown:         defaultCooldown,
+		now:              time.Now,
+		sleep:            sleepContext,
+	}
+	for _, b := range backends {
+		if b.Provider == nil {
+			return nil, fmt.Errorf("LLM backend %q has no provider", b.Name)
+		}
+		r.backends = append(r.backends, &backendState{Backend: b})
+	}
+	for _, opt := range opts {
+		opt(r)
+	}
+
+	return r, nil
+}
+
+func (r *Router) CreateCompletion(ctx context.Context, p llm.ChatParams) (string, llm.Usage, error) {
+	var (
+		text  string
+		usage llm.Usage
+	)
+
+	err := r.run(ctx, p, func(b *backendState, params llm.ChatParams) error {
+		var err error
+		text, usage, err = b.Provider.CreateCompletion(ctx, params)
+		return err
+	})
+	if err != nil {
+		return "", llm.Usage{}, err
+	}
+
+	return text, usage, nil
+}
+
+// CreateCompletionStream opens a stream and waits for its first chunk, so that
+// rate limits and provider failures surfacing before the first token are still
+// retried. Errors after the first chunk are returned to the caller as is.
+func (r *Router) CreateCompletionStream(ctx context.Context, p llm.ChatParams) (llm.ChatStream, error) {
+	var stream llm.ChatStream
+
+	err := r.run(ctx, p, func(b *backendState, params llm.ChatParams) error {
+		inner, err := b.Provider.CreateCompletionStream(ctx, params)
+		if err != nil {
+			return err
+		}
+
+		if !inner.Next() {
+			err := inner.Err()
+			if err != nil {
+				inner.Close()
+				return err
+			}
+			// Empty stream is a valid (if unusual) response
+			stream = &routedStream{inner: inner, backend: b, router: r, exhausted: true}
+			return nil
+		}
+
+		stream = &routedStream{inner: inner, backend: b, router: r, pending: true}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return stream, nil
+}
+
+// run calls fn against candidate backends until one succeeds, a non-retryable
+// error occurs or every backend is exhausted
+func (r *Router) run(ctx context.Context, p llm.ChatParams, fn func(b *backendState, params llm.ChatParams) error) error {
+	var lastErr error
+
+	for i, b := range r.candidates(p.Model) {
+		params := p
+		if !b.serves(p.Model) {
+			// Fall back to the backend's default model
+			params.Model = nil
+		}
+
+		if i > 0 {
+			logger.Warn(ctx, "Failing over to next LLM backend", "backend", b.Name, "error", lastErr)
+		}
+
+		for attempt := 0; attempt < r.maxAttempts; attempt++ {
+			if attempt > 0 {
+				if err := r.sleep(ctx, r.backoff(attempt)); err != nil {
+					return err
+				}
+			}
+
+			err := fn(b, params)
+			if err == nil {
+				r.markSuccess(b)
+				return nil
+			}
+
+			if !llm.IsRetryable(err) {
+				return err
+			}
+
+			lastErr = err
+			r.markFailure(b)
+			logger.Warn(ctx, "LLM backend request failed",
+				"backend", b.Name,
+				"attempt", attempt+1,
+				"error", err,
+			)
+
+			if !r.isHealthy(b) {
+				break
+			}
+		}
+	}
+
+	return fmt.Errorf("all LLM backends failed: %w", lastErr)
+}
+
+// candidates orders backends: healthy ones serving the requested model, healthy
+// ones that will use their default model, then unhealthy ones as a last resort
+func (r *Router) candidates(model *string) []*backendState {
+	var serving, other, unhealthy []*backendState
+	for _, b := range r.backends {
+		switch {
+		case !r.isHealthy(b):
+			unhealthy = append(unhealthy, b)
+		case b.serves(model):
+			serving = append(serving, b)
+		default:
+			other = append(other, b)
+		}
+	}
+
+	slices.SortStableFunc(unhealthy, func(a, b *backendState) int {
+		switch {
+		case a.serves(model) == b.serves(model):
+			return 0
+		case a.serves(model):
+			return -1
+		default:
+			return 1
+		}
+	})
+
+	result := make([]*backendState, 0, len(r.backends))
+	result = append(result, serving...)
+	result = append(result, other...)
+	return append(result, unhealthy...)
+}
+
+func (r *Router) backoff(attempt int) time.Duration {
+	d := r.initialBackoff << (attempt - 1)
+	if d <= 0 || d > r.maxBackoff {
+		return r.maxBackoff
+	}
+	return d
+}
+
+func (r *Router) isHealthy(b *backendState) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return !r.now().Before(b.unhealthyUntil)
+}
+
+func (r *Router) markSuccess(b *backendState) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.consecutiveFailures = 0
+	b.unhealthyUntil = time.Time{}
+}
+
+func (r *Router) markFailure(b *backendState) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.consecutiveFailures++
+	if b.consecutiveFailures >= r.failureThreshold {
+		b.unhealthyUntil = r.now().Add(r.cooldown)
+	}
+}
+
+func sleepContext(ctx context.Context, d time.Duration) error {
+	t := time.NewTimer(d)
+	defer t.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
+}
+
+// routedStream replays the chunk read by the router and reports mid-stream
+// failures to the backend health tracker
+type routedStream struct {
+	inner   llm.ChatStream
+	backend *backendState
+	router  *Router
+
+	pending   bool
+	exhausted bool
+}
+
+func (s *routedStream) Next() bool {
+	if s.pending {
+		s.pending = false
+		return true
+	}
+	if s.exhausted {
+		return false
+	}
+
+	if s.inner.Next() {
+		return true
+	}
+
+	s.exhausted = true
+	if llm.IsRetryable(s.inner.Err()) {
+		s.router.markFailure(s.backend)
+	}
+	return false
+}
+
+func (s *routedStream) Chunk() llm.ChatDelta { return s.inner.Chunk() }
+func (s *routedStream) Err() error           { return s.inner.Err() }
+func (s *routedStream) Close() error         { return s.inner.Close() }
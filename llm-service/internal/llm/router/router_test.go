@@ -0,0 +1,370 @@
+package router
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"os"
+	"reflect"
+	"testing"
+	"time"
+
+	"llm-service/internal/llm"
+	"llm-service/internal/llm/fake"
+	"llm-service/internal/logger"
+)
+
+func TestMain(m *testing.M) {
+	logger.Init()
+	os.Exit(m.Run())
+}
+
+func providerError(status int) error {
+	return &llm.ProviderError{Provider: "test", StatusCode: status, Err: errors.New(http.StatusText(status))}
+}
+
+func failing(status int, n int) []fake.Turn {
+	turns := make([]fake.Turn, n)
+	for i := range turns {
+		turns[i] = fake.Turn{Err: providerError(status)}
+	}
+	return turns
+}
+
+// testRouter creates a router with a manual clock and records backoff delays instead of sleeping
+func testRouter(t *testing.T, backends []Backend, opts ...Option) (*Router, *time.Time, *[]time.Duration) {
+	t.Helper()
+
+	r, err := New(backends, opts...)
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	now := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+	var slept []time.Duration
+	r.now = func() time.Time { return now }
+	r.sleep = func(_ context.Context, d time.Duration) error {
+		slept = append(slept, d)
+		return nil
+	}
+	return r, &now, &slept
+}
+
+func models(requests []llm.ChatParams) []string {
+	result := make([]string, len(requests))
+	for i, p := range requests {
+		if p.Model != nil {
+			result[i] = *p.Model
+		}
+	}
+	return result
+}
+
+func TestNew(t *testing.T) {
+	if _, err := New(nil); err == nil {
+		t.Error("New without backends = nil error, want error")
+	}
+	if _, err := New([]Backend{{Name: "primary"}}); err == nil {
+		t.Error("New with nil provider = nil error, want error")
+	}
+}
+
+func TestRouterFailover(t *testing.T) {
+	tests := []struct {
+		name          string
+		primary       []fake.Turn
+		secondary     []fake.Turn
+		wantText      string
+		wantErr       bool
+		wantRetryable bool
+		wantPrimary   int
+		wantSecondary int
+		wantSlept     []time.Duration
+	}{
+		{
+			name:        "первый backend отвечает",
+			primary:     []fake.Turn{fake.Text("primary")},
+			wantText:    "primary",
+			wantPrimary: 1,
+		},
+		{
+			name:        "повтор после 429",
+			primary:     append(failing(http.StatusTooManyRequests, 2), fake.Text("primary")),
+			wantText:    "primary",
+			wantPrimary: 3,
+			wantSlept:   []time.Duration{100 * time.Millisecond, 200 * time.Millisecond},
+		},
+		{
+			name:          "переключение после исчерпания попыток",
+			primary:       failing(http.StatusServiceUnavailable, 3),
+			secondary:     []fake.Turn{fake.Text("secondary")},
+			wantText:      "secondary",
+			wantPrimary:   3,
+			wantSecondary: 1,
+			wantSlept:     []time.Duration{100 * time.Millisecond, 200 * time.Millisecond},
+		},
+		{
+			name:        "неповторяемая ошибка не переключает backend",
+			primary:     failing(http.StatusBadRequest, 1),
+			secondary:   []fake.Turn{fake.Text("secondary")},
+			wantErr:     true,
+			wantPrimary: 1,
+		},
+		{
+			name:          "все backend недоступны",
+			primary:       failing(http.StatusInternalServerError, 3),
+			secondary:     failing(http.StatusBadGateway, 3),
+			wantErr:       true,
+			wantRetryable: true,
+			wantPrimary:   3,
+			wantSecondary: 3,
+			wantSlept: []time.Duration{
+				100 * time.Millisecond, 200 * time.Millisecond,
+				100 * time.Millisecond, 200 * time.Millisecond,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			primary := fake.New(tt.primary...)
+			secondary := fake.New(tt.secondary...)
+			r, _, slept := testRouter(t,
+				[]Backend{{Name: "primary", Provider: primary}, {Name: "secondary", Provider: secondary}},
+				WithRetry(3, 100*time.Millisecond, time.Second),
+				WithHealth(10, time.Minute),
+			)
+
+			text, _, err := r.CreateCompletion(context.Background(), llm.ChatParams{})
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("CreateCompletion = %q, want error", text)
+				}
+				if got := llm.IsRetryable(err); got != tt.wantRetryable {
+					t.Errorf("IsRetryable(%v) = %v, want %v", err, got, tt.wantRetryable)
+				}
+			} else {
+				if err != nil {
+					t.Fatalf("CreateCompletion: %v", err)
+				}
+				if text != tt.wantText {
+					t.Errorf("text = %q, want %q", text, tt.wantText)
+				}
+			}
+
+			if got := len(primary.Requests()); got != tt.wantPrimary {
+				t.Errorf("primary calls = %d, want %d", got, tt.wantPrimary)
+			}
+			if got := len(secondary.Requests()); got != tt.wantSecondary {
+				t.Errorf("secondary calls = %d, want %d", got, tt.wantSecondary)
+			}
+			if !reflect.DeepEqual(*slept, tt.wantSlept) {
+				t.Errorf("backoff = %v, want %v", *slept, tt.wantSlept)
+			}
+		})
+	}
+}
+
+func TestRouterModelRouting(t *testing.T) {
+	primary := fake.New(failing(http.StatusServiceUnavailable, 1)...)
+	specialized := fake.New(failing(http.StatusServiceUnavailable, 1)...)
+	fallback := fake.New(fake.Text("fallback"))
+	r, _, _ := testRouter(t,
+		[]Backend{
+			{Name: "primary", Provider: primary, Models: []string{"gpt-4o"}},
+			{Name: "specialized", Provider: specialized, Models: []string{"claude-sonnet"}},
+			{Name: "fallback", Provider: fallback},
+		},
+		WithRetry(1, time.Millisecond, time.Millisecond),
+	)
+
+	model := "claude-sonnet"
+	if _, _, err := r.CreateCompletion(context.Background(), llm.ChatParams{Model: &model}); err != nil {
+		t.Fatalf("CreateCompletion: %v", err)
+	}
+
+	// Backends serving the model go first; a backend without a model list gets the model as is,
+	// the one with another model list is tried last with its default model
+	if got := models(specialized.Requests()); !reflect.DeepEqual(got, []string{"claude-sonnet"}) {
+		t.Errorf("specialized models = %v, want [claude-sonnet]", got)
+	}
+	if got := models(fallback.Requests()); !reflect.DeepEqual(got, []string{"claude-sonnet"}) {
+		t.Errorf("fallback models = %v, want [claude-sonnet]", got)
+	}
+	if got := len(primary.Requests()); got != 0 {
+		t.Errorf("primary calls = %d, want 0 after fallback succeeded", got)
+	}
+}
+
+func TestRouterCandidatesOrder(t *testing.T) {
+	model := "claude-sonnet"
+
+	tests := []struct {
+		name      string
+		unhealthy []string
+		model     *string
+		want      []string
+	}{
+		{name: "все здоровы, модель не задана", want: []string{"openai", "anthropic", "local"}},
+		{name: "модель задана", model: &model, want: []string{"anthropic", "local", "openai"}},
+		{name: "нездоровый в конце", unhealthy: []string{"openai"}, want: []string{"anthropic", "local", "openai"}},
+		{
+			name:      "среди нездоровых первым обслуживающий модель",
+			unhealthy: []string{"openai", "anthropic"},
+			model:     &model,
+			want:      []string{"local", "anthropic", "openai"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, now, _ := testRouter(t, []Backend{
+				{Name: "openai", Provider: fake.New(), Models: []string{"gpt-4o"}},
+				{Name: "anthropic", Provider: fake.New(), Models: []string{"claude-sonnet"}},
+				{Name: "local", Provider: fake.New()},
+			})
+			for _, b := range r.backends {
+				for _, name := range tt.unhealthy {
+					if b.Name == name {
+						b.unhealthyUntil = now.Add(time.Minute)
+					}
+				}
+			}
+
+			var got []string
+			for _, b := range r.candidates(tt.model) {
+				got = append(got, b.Name)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("candidates = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRouterBackoff(t *testing.T) {
+	r, _, _ := testRouter(t,
+		[]Backend{{Name: "primary", Provider: fake.New()}},
+		WithRetry(10, 500*time.Millisecond, 3*time.Second),
+	)
+
+	tests := []struct {
+		attempt int
+		want    time.Duration
+	}{
+		{attempt: 1, want: 500 * time.Millisecond},
+		{attempt: 2, want: time.Second},
+		{attempt: 3, want: 2 * time.Second},
+		{attempt: 4, want: 3 * time.Second},
+		{attempt: 64, want: 3 * time.Second},
+	}
+
+	for _, tt := range tests {
+		if got := r.backoff(tt.attempt); got != tt.want {
+			t.Errorf("backoff(%d) = %s, want %s", tt.attempt, got, tt.want)
+		}
+	}
+}
+
+func TestRouterHealthCooldown(t *testing.T) {
+	primary := fake.New(append(failing(http.StatusServiceUnavailable, 2), fake.Text("primary again"))...)
+	secondary := fake.New(fake.Text("secondary 1"), fake.Text("secondary 2"))
+	r, now, _ := testRouter(t,
+		[]Backend{{Name: "primary", Provider: primary}, {Name: "secondary", Provider: secondary}},
+		WithRetry(5, time.Millisecond, time.Millisecond),
+		WithHealth(2, 30*time.Second),
+	)
+	ctx := context.Background()
+
+	// After two failures in a row the primary is marked unhealthy and the router
+	// fails over without spending the remaining attempts
+	text, _, err := r.CreateCompletion(ctx, llm.ChatParams{})
+	if err != nil || text != "secondary 1" {
+		t.Fatalf("first request = %q, %v, want secondary 1", text, err)
+	}
+	if got := len(primary.Requests()); got != 2 {
+		t.Fatalf("primary calls = %d, want 2", got)
+	}
+
+	// During the cooldown the unhealthy backend is not tried first
+	*now = now.Add(29 * time.Second)
+	text, _, err = r.CreateCompletion(ctx, llm.ChatParams{})
+	if err != nil || text != "secondary 2" {
+		t.Fatalf("request during cooldown = %q, %v, want secondary 2", text, err)
+	}
+	if got := len(primary.Requests()); got != 2 {
+		t.Errorf("primary calls during cooldown = %d, want 2", got)
+	}
+
+	// After the cooldown the primary is first again and a success resets its failures
+	*now = now.Add(2 * time.Second)
+	text, _, err = r.CreateCompletion(ctx, llm.ChatParams{})
+	if err != nil || text != "primary again" {
+		t.Fatalf("request after cooldown = %q, %v, want primary again", text, err)
+	}
+	if b := r.backends[0]; b.consecutiveFailures != 0 || !b.unhealthyUntil.IsZero() {
+		t.Errorf("primary state = %d failures until %s, want reset", b.consecutiveFailures, b.unhealthyUntil)
+	}
+}
+
+func TestRouterStreamRetriesBeforeFirstChunk(t *testing.T) {
+	primary := fake.New(
+		fake.Turn{Err: providerError(http.StatusTooManyRequests)},
+		fake.Turn{StreamErr: providerError(http.StatusServiceUnavailable)},
+		fake.Turn{Content: "hello", ChunkSize: 2},
+	)
+	r, _, _ := testRouter(t, []Backend{{Name: "primary", Provider: primary}},
+		WithRetry(3, time.Millisecond, time.Millisecond),
+	)
+
+	stream, err := r.CreateCompletionStream(context.Background(), llm.ChatParams{})
+	if err != nil {
+		t.Fatalf("CreateCompletionStream: %v", err)
+	}
+	defer stream.Close()
+
+	var text string
+	for stream.Next() {
+		text += stream.Chunk().Content
+	}
+	if err := stream.Err(); err != nil {
+		t.Fatalf("stream error: %v", err)
+	}
+	if text != "hello" {
+		t.Errorf("streamed text = %q, want hello", text)
+	}
+	if got := len(primary.Requests()); got != 3 {
+		t.Errorf("calls = %d, want 3", got)
+	}
+}
+
+func TestRouterStreamErrorAfterFirstChunk(t *testing.T) {
+	primary := fake.New(fake.Turn{Content: "hello", ChunkSize: 2, StreamErr: providerError(http.StatusBadGateway)})
+	secondary := fake.New()
+	r, _, _ := testRouter(t,
+		[]Backend{{Name: "primary", Provider: primary}, {Name: "secondary", Provider: secondary}},
+		WithHealth(1, time.Minute),
+	)
+
+	stream, err := r.CreateCompletionStream(context.Background(), llm.ChatParams{})
+	if err != nil {
+		t.Fatalf("CreateCompletionStream: %v", err)
+	}
+	defer stream.Close()
+
+	var text string
+	for stream.Next() {
+		text += stream.Chunk().Content
+	}
+
+	// Part of the answer is already delivered, so the error is returned instead of a failover
+	if text != "hello" || !llm.IsRetryable(stream.Err()) {
+		t.Errorf("stream = %q, %v, want hello and retryable error", text, stream.Err())
+	}
+	if got := len(secondary.Requests()); got != 0 {
+		t.Errorf("secondary calls = %d, want 0", got)
+	}
+	if r.isHealthy(r.backends[0]) {
+		t.Error("primary is healthy after a mid-stream failure, want unhealthy")
+	}
+}
@@ -0,0 +1,115 @@
+package llm
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// Validator is implemented by structured answers that check their own fields
+type Validator interface {
+	Validate() error
+}
+
+// InvalidOutputError is returned by CompleteStructured when the model answer
+// could not be decoded or validated even after a retry
+type InvalidOutputError struct {
+	Schema string
+	Output string
+	Err    error
+}
+
+func (e *InvalidOutputError) Error() string {
+	return fmt.Sprintf("invalid %s output: %v", e.Schema, e.Err)
+}
+
+func (e *InvalidOutputError) Unwrap() error { return e.Err }
+
+// CompleteStructured asks the model for JSON matching format and decodes it into T.
+// If T implements Validator, Validate is called on the decoded value, then checks
+// run in order; checks are for rules depending on data outside T.
+// When decoding or validation fails, the request is repeated once with the bad
+// answer and the error appended, so the model can fix it. Usage of both attempts
+// is summed. With InvalidOutputError the last decoded value is returned as is,
+// so callers may still salvage it (e.g. truncate a too long field).
+func CompleteStructured[T any](ctx context.Context, provider CompletionProvider, params ChatParams, format ResponseFormat, checks ...func(T) error) (T, Usage, error) {
+	var (
+		result T
+		total  Usage
+	)
+
+	params.ResponseFormat = &format
+	messages := params.Messages
+
+	for attempt := 0; attempt < 2; attempt++ {
+		params.Messages = messages
+
+		content, usage, err := provider.CreateCompletion(ctx, params)
+		total = total.Add(usage)
+		if err != nil {
+			return result, total, err
+		}
+
+		value, err := decodeStructured(content, checks)
+		if err == nil {
+			return value, total, nil
+		}
+
+		if attempt > 0 {
+			return value, total, &InvalidOutputError{Schema: format.Name, Output: content, Err: err}
+		}
+
+		messages = append(messages[:len(messages):len(messages)],
+			MessageParam{Role: RoleAssistant, Content: content},
+			MessageParam{Role: RoleUser, Content: fmt.Sprintf("The answer is invalid: %v. Reply again with only the corrected JSON object.", err)},
+		)
+	}
+
+	return result, total, nil
+}
+
+// decodeStructured parses the answer, tolerating markdown code fences some
+// providers put around JSON even when asked not to
+func decodeStructured[T any](content string, checks []func(T) error) (T, error) {
+	var value T
+
+	content = strings.TrimSpace(content)
+	if strings.HasPrefix(content, "```") {
+		content = strings.TrimPrefix(content, "```json")
+		content = strings.TrimPrefix(content, "```")
+		content = strings.TrimSuffix(content, "```")
+		content = strings.TrimSpace(content)
+	}
+
+	if err := json.Unmarshal([]byte(content), &value); err != nil {
+		return value, fmt.Errorf("not valid JSON: %w", err)
+	}
+
+	if v, ok := any(&value).(Validator); ok {
+		if err := v.Validate(); err != nil {
+			return value, err
+		}
+	} else if v, ok := any(value).(Validator); ok {
+		if err := v.Validate(); err != nil {
+			return value, err
+		}
+	}
+
+	for _, check := range checks {
+		if err := check(value); err != nil {
+			return value, err
+		}
+	}
+
+	return value, nil
+}
+
+// Add sums token usage of several calls
+func (u Usage) Add(other Usage) Usage {
+	return Usage{
+		PromptTokens:     u.PromptTokens + other.PromptTokens,
+		CompletionTokens: u.CompletionTokens + other.CompletionTokens,
+		TotalTokens:      u.TotalTokens + other.TotalTokens,
+	}
+}
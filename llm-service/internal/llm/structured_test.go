@@ -0,0 +1,92 @@
+package llm_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"llm-service/internal/llm"
+	"llm-service/internal/llm/fake"
+)
+
+type answer struct {
+	Title string `json:"title"`
+}
+
+func (a *answer) Validate() error {
+	if utf8.RuneCountInString(a.Title) > 10 {
+		return errors.New("title is too long")
+	}
+	return nil
+}
+
+var format = llm.ResponseFormat{Name: "answer", Schema: map[string]any{"type": "object"}}
+
+func TestCompleteStructuredRetriesOnce(t *testing.T) {
+	provider := fake.New(
+		fake.Turn{Content: `{"title": "Очень длинное название"}`, Usage: llm.Usage{TotalTokens: 10}},
+		fake.Turn{Content: "```json\n{\"title\": \"Договор\"}\n```", Usage: llm.Usage{TotalTokens: 7}},
+	)
+
+	params := llm.ChatParams{Messages: []llm.MessageParam{{Role: llm.RoleUser, Content: "Название?"}}}
+	result, usage, err := llm.CompleteStructured[answer](context.Background(), provider, params, format)
+	if err != nil {
+		t.Fatalf("CompleteStructured: %v", err)
+	}
+	if result.Title != "Договор" {
+		t.Errorf("title = %q, want Договор", result.Title)
+	}
+	if usage.TotalTokens != 17 {
+		t.Errorf("total tokens = %d, want 17", usage.TotalTokens)
+	}
+
+	requests := provider.Requests()
+	if len(requests) != 2 {
+		t.Fatalf("requests = %d, want 2", len(requests))
+	}
+	if requests[0].ResponseFormat == nil || requests[0].ResponseFormat.Name != "answer" {
+		t.Errorf("response format not passed to provider")
+	}
+	retry := requests[1].Messages
+	if len(retry) != 3 || retry[1].Role != llm.RoleAssistant || !strings.Contains(retry[2].Content, "title is too long") {
+		t.Errorf("retry messages = %+v, want bad answer and validation error appended", retry)
+	}
+	if len(params.Messages) != 1 {
+		t.Errorf("caller messages modified: %+v", params.Messages)
+	}
+}
+
+func TestCompleteStructuredGivesUpAfterRetry(t *testing.T) {
+	provider := fake.New(fake.Text("не JSON"), fake.Text(`{"title": "Слишком длинное название"}`))
+
+	result, _, err := llm.CompleteStructured[answer](context.Background(), provider, llm.ChatParams{}, format)
+
+	var invalid *llm.InvalidOutputError
+	if !errors.As(err, &invalid) {
+		t.Fatalf("error = %v, want InvalidOutputError", err)
+	}
+	if result.Title != "Слишком длинное название" {
+		t.Errorf("last decoded value not returned: %q", result.Title)
+	}
+	if provider.Remaining() != 0 {
+		t.Errorf("expected exactly two attempts")
+	}
+}
+
+func TestCompleteStructuredChecks(t *testing.T) {
+	provider := fake.New(fake.Text(`{"title": "Акт"}`), fake.Text(`{"title": "Счет"}`))
+
+	notAct := func(a answer) error {
+		if a.Title == "Акт" {
+			return errors.New("act is not allowed")
+		}
+		return nil
+	}
+
+	result, _, err := llm.CompleteStructured(context.Background(), provider, llm.ChatParams{}, format, notAct)
+	if err != nil || result.Title != "Счет" {
+		t.Errorf("result = %q, %v; want Счет", result.Title, err)
+	}
+}
@@ -27,10 +27,36 @@ type ToolDefinition struct {
 	RequiresConfirm bool
 }
 
+// ContentPartType is a kind of user content part
+type ContentPartType string
+
+const (
+	ContentPartText  ContentPartType = "text"
+	ContentPartImage ContentPartType = "image"
+	ContentPartFile  ContentPartType = "file" // documents such as PDF scans
+)
+
+// ContentPart is one piece of multi-part user content
+type ContentPart struct {
+	Type ContentPartType
+	Text string
+	// Image and file parts: raw bytes, MIME type and optional file name
+	Data      []byte
+	MediaType string
+	FileName  string
+}
+
+// TextPart is a text content part
+func TextPart(text string) ContentPart {
+	return ContentPart{Type: ContentPartText, Text: text}
+}
+
 // MessageParam is a message input to the model
 type MessageParam struct {
 	Role    string
 	Content string
+	// User-only: multi-part content for vision models; when set, Content is ignored
+	Parts []ContentPart
 	// Assistant-only: optional planned tool calls
 	ToolCalls []ToolCall
 	// Tool-only: references tool call id
@@ -42,8 +68,18 @@ type ChatParams struct {
 	Messages        []MessageParam
 	Tools           []ToolDefinition
 	IncludeUsage    bool
-	Model           *string // Optional: override default model from config
-	ReasoningEffort *string // Optional: override default reasoning effort from config
+	Model           *string         // Optional: override default model from config
+	ReasoningEffort *string         // Optional: override default reasoning effort from config
+	ResponseFormat  *ResponseFormat // Optional: constrain the answer to a JSON schema
+}
+
+// ResponseFormat asks the model to answer with JSON matching Schema
+type ResponseFormat struct {
+	// Name identifies the schema (a-z, A-Z, 0-9, underscores and dashes, up to 64 chars)
+	Name        string
+	Description string
+	Schema      map[string]any // JSON Schema of the answer as a map
+	Strict      bool
 }
 
 // Usage token accounting
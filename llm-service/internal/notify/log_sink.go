@@ -0,0 +1,29 @@
+package notify
+
+import (
+	"context"
+
+	"llm-service/internal/domain"
+	"llm-service/internal/logger"
+)
+
+// LogSink пишет уведомления в лог. Используется, если очередь уведомлений не настроена,
+// чтобы результаты фоновых задач не терялись молча.
+type LogSink struct{}
+
+func NewLogSink() *LogSink {
+	return &LogSink{}
+}
+
+// Notify логирует уведомление
+func (s *LogSink) Notify(ctx context.Context, notification domain.Notification) error {
+	chatID := ""
+	if notification.ChatID != nil {
+		chatID = notification.ChatID.String()
+	}
+
+	logger.Infof(ctx, "notification %s for user %s (org %s, chat %s): %s",
+		notification.Kind, notification.UserID, notification.OrganizationID, chatID, notification.Title)
+
+	return nil
+}
@@ -0,0 +1,62 @@
+package notify
+
+import (
+	"context"
+	"time"
+
+	"llm-service/internal/domain"
+)
+
+// notificationEventTypes - типы событий сервиса уведомлений core-service для видов уведомлений
+var notificationEventTypes = map[domain.NotificationKind]string{
+	domain.NotificationKindScheduledRun: "agent.scheduled_run",
+}
+
+type publisher interface {
+	PublishMessage(ctx context.Context, message interface{}) error
+}
+
+// notificationEvent - событие очереди уведомлений (формат сервиса уведомлений core-service)
+type notificationEvent struct {
+	EventID        string    `json:"event_id"`
+	Type           string    `json:"type"`
+	Source         string    `json:"source"`
+	OrganizationID string    `json:"organization_id"`
+	UserID         string    `json:"user_id"`
+	Title          string    `json:"title"`
+	Text           string    `json:"text"`
+	OccurredAt     time.Time `json:"occurred_at"`
+}
+
+// QueueSink публикует уведомления в очередь RabbitMQ, откуда их доставляет core-service
+type QueueSink struct {
+	publisher publisher
+}
+
+func NewQueueSink(publisher publisher) *QueueSink {
+	return &QueueSink{publisher: publisher}
+}
+
+// Notify публикует уведомление как доменное событие
+func (s *QueueSink) Notify(ctx context.Context, notification domain.Notification) error {
+	eventType, ok := notificationEventTypes[notification.Kind]
+	if !ok {
+		return domain.NewInvalidArgumentError("unsupported notification kind: " + string(notification.Kind))
+	}
+
+	eventID := notification.Key
+	if eventID == "" {
+		eventID = domain.NewID().String()
+	}
+
+	return s.publisher.PublishMessage(ctx, notificationEvent{
+		EventID:        eventType + ":" + eventID,
+		Type:           eventType,
+		Source:         "llm-service",
+		OrganizationID: notification.OrganizationID.String(),
+		UserID:         notification.UserID.String(),
+		Title:          notification.Title,
+		Text:           notification.Text,
+		OccurredAt:     time.Now().UTC(),
+	})
+}
@@ -0,0 +1,147 @@
+package queue
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"llm-service/internal/logger"
+
+	"github.com/opentracing/opentracing-go"
+	amqp "github.com/rabbitmq/amqp091-go"
+)
+
+// MessageHandler обрабатывает тело сообщения из очереди
+type MessageHandler func(ctx context.Context, body []byte) error
+
+// RabbitMQClient публикует JSON-сообщения в очередь RabbitMQ и читает их из нее
+type RabbitMQClient struct {
+	conn      *amqp.Connection
+	channel   *amqp.Channel
+	queueName string
+}
+
+func NewRabbitMQClient(url, queueName string) (*RabbitMQClient, error) {
+	conn, err := amqp.Dial(url)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to RabbitMQ: %w", err)
+	}
+
+	channel, err := conn.Channel()
+	if err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("failed to open channel: %w", err)
+	}
+
+	_, err = channel.QueueDeclare(
+		queueName,
+		true,
+		false,
+		false,
+		false,
+		nil,
+	)
+	if err != nil {
+		channel.Close()
+		conn.Close()
+		return nil, fmt.Errorf("failed to declare queue: %w", err)
+	}
+
+	return &RabbitMQClient{
+		conn:      conn,
+		channel:   channel,
+		queueName: queueName,
+	}, nil
+}
+
+func (c *RabbitMQClient) Close() error {
+	if c.channel != nil {
+		c.channel.Close()
+	}
+	if c.conn != nil {
+		return c.conn.Close()
+	}
+	return nil
+}
+
+// PublishMessage сериализует сообщение в JSON и публикует его в очередь
+func (c *RabbitMQClient) PublishMessage(ctx context.Context, message interface{}) error {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "queue.RabbitMQClient.PublishMessage")
+	defer span.Finish()
+
+	body, err := json.Marshal(message)
+	if err != nil {
+		return fmt.Errorf("failed to marshal message: %w", err)
+	}
+
+	err = c.channel.PublishWithContext(
+		ctx,
+		"",
+		c.queueName,
+		false,
+		false,
+		amqp.Publishing{
+			ContentType:  "application/json",
+			Body:         body,
+			DeliveryMode: amqp.Persistent,
+		},
+	)
+	if err != nil {
+		return fmt.Errorf("failed to publish message: %w", err)
+	}
+
+	return nil
+}
+
+// Consume передает сообщения обработчику, пока не отменен контекст.
+// Сообщение с ошибкой возвращается в очередь один раз, при повторной ошибке отбрасывается
+func (c *RabbitMQClient) Consume(ctx context.Context, consumerTag string, prefetchCount int, handler MessageHandler) error {
+	if err := c.channel.Qos(prefetchCount, 0, false); err != nil {
+		return fmt.Errorf("failed to set QoS: %w", err)
+	}
+
+	msgs, err := c.channel.Consume(
+		c.queueName,
+		consumerTag,
+		false,
+		false,
+		false,
+		false,
+		nil,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to register consumer: %w", err)
+	}
+
+	logger.Info(ctx, "consumer started", "queue", c.queueName, "consumer", consumerTag)
+
+	for {
+		select {
+		case <-ctx.Done():
+			logger.Info(ctx, "consumer stopped", "queue", c.queueName)
+			return nil
+		case msg, ok := <-msgs:
+			if !ok {
+				return fmt.Errorf("queue %s: delivery channel closed", c.queueName)
+			}
+			c.handleMessage(ctx, msg, handler)
+		}
+	}
+}
+
+func (c *RabbitMQClient) handleMessage(ctx context.Context, msg amqp.Delivery, handler MessageHandler) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "queue.RabbitMQClient.handleMessage")
+	defer span.Finish()
+
+	if err := handler(ctx, msg.Body); err != nil {
+		logger.Error(ctx, "failed to handle message",
+			"queue", c.queueName,
+			"error", err,
+			"redelivered", msg.Redelivered,
+		)
+		msg.Nack(false, !msg.Redelivered)
+		return
+	}
+
+	msg.Ack(false)
+}
@@ -0,0 +1,107 @@
+package rag
+
+import (
+	"context"
+	"fmt"
+
+	"llm-service/internal/domain"
+	"llm-service/internal/logger"
+	desc "llm-service/pkg/document"
+
+	"github.com/opentracing/opentracing-go"
+)
+
+// ChatAttachmentResult - результат обработки файла чата в docs-processor
+type ChatAttachmentResult struct {
+	Inline     bool   // текст возвращен целиком и не индексировался
+	Text       string // текст документа при Inline
+	TextLength int
+	ChunkCount int
+}
+
+// IndexChatAttachment разбирает файл чата; большие документы индексируются во временный индекс чата
+func (c *Client) IndexChatAttachment(
+	ctx context.Context,
+	organizationID, chatID domain.ID,
+	attachment domain.Attachment,
+) (*ChatAttachmentResult, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "client.rag.IndexChatAttachment")
+	defer span.Finish()
+
+	resp, err := c.client.IndexChatAttachment(ctx, &desc.IndexChatAttachmentRequest{
+		OrganizationId: organizationID.String(),
+		ChatId:         chatID.String(),
+		AttachmentId:   attachment.ID.String(),
+		S3Key:          attachment.S3Key,
+		FileName:       attachment.FileName,
+		DocumentType:   attachment.DocumentType(),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to index chat attachment: %w", err)
+	}
+
+	return &ChatAttachmentResult{
+		Inline:     resp.GetInline(),
+		Text:       resp.GetText(),
+		TextLength: int(resp.GetTextLength()),
+		ChunkCount: int(resp.GetChunkCount()),
+	}, nil
+}
+
+// DeleteChatAttachments удаляет фрагменты файлов чата из индекса.
+// Если attachmentID не задан, удаляются все файлы чата.
+func (c *Client) DeleteChatAttachments(ctx context.Context, organizationID, chatID domain.ID, attachmentID *domain.ID) error {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "client.rag.DeleteChatAttachments")
+	defer span.Finish()
+
+	req := &desc.DeleteChatAttachmentsRequest{
+		OrganizationId: organizationID.String(),
+		ChatId:         chatID.String(),
+	}
+	if attachmentID != nil {
+		id := attachmentID.String()
+		req.AttachmentId = &id
+	}
+
+	if _, err := c.client.DeleteChatAttachments(ctx, req); err != nil {
+		return fmt.Errorf("failed to delete chat attachments: %w", err)
+	}
+
+	return nil
+}
+
+// SearchChatChunks ищет фрагменты только среди файлов указанного чата
+func (c *Client) SearchChatChunks(
+	ctx context.Context,
+	organizationID, chatID domain.ID,
+	query string,
+	limit int,
+) ([]string, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "client.rag.SearchChatChunks")
+	defer span.Finish()
+
+	if limit <= 0 {
+		limit = 5
+	}
+
+	chatIDStr := chatID.String()
+	resp, err := c.client.SearchChunks(ctx, &desc.SearchChunksRequest{
+		Query:          query,
+		OrganizationId: organizationID.String(),
+		Limit:          int32(limit),
+		MinScore:       0.3,
+		ChatId:         &chatIDStr,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to search chat chunks: %w", err)
+	}
+
+	chunks := make([]string, 0, len(resp.GetChunks()))
+	for _, chunk := range resp.GetChunks() {
+		chunks = append(chunks, fmt.Sprintf("[Файл: %s]\n%s", chunk.GetDocumentName(), chunk.GetContent()))
+	}
+
+	logger.Debugf(ctx, "found %d chat file chunks", len(chunks))
+
+	return chunks, nil
+}
@@ -2,6 +2,8 @@ package repository
 
 import (
 	"context"
+	"time"
+
 	"llm-service/internal/domain"
 )
 
@@ -27,6 +29,78 @@ type ChatRepository interface {
 
 	// GetActiveChildChat получает активный дочерний чат для родительского чата
 	GetActiveChildChat(ctx context.Context, parentChatID domain.ID) (*domain.Chat, error)
+
+	// SearchChats выполняет полнотекстовый поиск по чатам пользователя
+	SearchChats(ctx context.Context, query domain.ChatSearchQuery, page, pageSize int) ([]domain.ChatSearchHit, int, error)
+}
+
+// ChatShareRepository - репозиторий совместного доступа к чатам
+type ChatShareRepository interface {
+	// UpsertChatShare выдает доступ к чату или меняет роль
+	UpsertChatShare(ctx context.Context, share domain.ChatShare) error
+
+	// GetChatShare получает доступ пользователя к чату
+	GetChatShare(ctx context.Context, chatID, userID domain.ID) (domain.ChatShare, error)
+
+	// ListChatShares получает всех пользователей, с которыми поделились чатом
+	ListChatShares(ctx context.Context, chatID domain.ID) ([]domain.ChatShare, error)
+
+	// DeleteChatShare отзывает доступ пользователя к чату
+	DeleteChatShare(ctx context.Context, chatID, userID domain.ID) error
+
+	// ListSharedChats получает чаты организации, которыми поделились с пользователем
+	ListSharedChats(ctx context.Context, organizationID, userID domain.ID, page, pageSize int) ([]domain.SharedChat, int, error)
+}
+
+// FeedbackRepository - репозиторий оценок ответов ассистента
+type FeedbackRepository interface {
+	// UpsertMessageFeedback сохраняет оценку ответа и заполняет инструменты, вызванные при его подготовке
+	UpsertMessageFeedback(ctx context.Context, feedback *domain.MessageFeedback) error
+
+	// ListUserMessageFeedback получает оценки пользователя для списка сообщений
+	ListUserMessageFeedback(ctx context.Context, userID domain.ID, messageIDs []domain.ID) ([]domain.MessageFeedback, error)
+
+	// GetFeedbackReport агрегирует оценки организации за период
+	GetFeedbackReport(ctx context.Context, query domain.FeedbackReportQuery) (domain.FeedbackReport, error)
+}
+
+// ScheduleRepository - репозиторий расписаний агентов
+type ScheduleRepository interface {
+	// CreateSchedule создает расписание
+	CreateSchedule(ctx context.Context, schedule *domain.AgentSchedule) error
+
+	// GetSchedule получает расписание по ID
+	GetSchedule(ctx context.Context, id domain.ID) (*domain.AgentSchedule, error)
+
+	// ListSchedules получает расписания пользователя в организации
+	ListSchedules(ctx context.Context, organizationID, userID domain.ID) ([]*domain.AgentSchedule, error)
+
+	// CountSchedules считает расписания пользователя в организации
+	CountSchedules(ctx context.Context, organizationID, userID domain.ID) (int, error)
+
+	// UpdateSchedule обновляет настройки расписания
+	UpdateSchedule(ctx context.Context, schedule *domain.AgentSchedule) error
+
+	// DeleteSchedule удаляет расписание
+	DeleteSchedule(ctx context.Context, id domain.ID) error
+
+	// ClaimDueSchedules забирает в работу расписания, которым пора выполниться
+	ClaimDueSchedules(ctx context.Context, now, lockedUntil time.Time, limit int) ([]*domain.AgentSchedule, error)
+
+	// CompleteScheduleRun сохраняет результат запуска и снимает блокировку воркера
+	CompleteScheduleRun(ctx context.Context, schedule *domain.AgentSchedule, claimedUpdatedAt time.Time) error
+}
+
+// TelegramBindingRepository - репозиторий связей чатов Telegram-бота с чатами агента
+type TelegramBindingRepository interface {
+	// GetActiveTelegramBinding получает связь Telegram-чата с последней выбранной организацией
+	GetActiveTelegramBinding(ctx context.Context, telegramChatID int64) (*domain.TelegramBinding, error)
+
+	// ActivateTelegramBinding делает организацию активной для Telegram-чата
+	ActivateTelegramBinding(ctx context.Context, binding *domain.TelegramBinding) error
+
+	// SetTelegramBindingChat меняет чат агента, который продолжает Telegram-чат в организации
+	SetTelegramBindingChat(ctx context.Context, telegramChatID int64, organizationID domain.ID, chatID *domain.ID) error
 }
 
 // ChatFilter - фильтр для поиска чатов
@@ -42,14 +116,29 @@ type MessageRepository interface {
 	// CreateMessage создает новое сообщение
 	CreateMessage(ctx context.Context, message *domain.Message) error
 
+	// GetMessageByID получает сообщение по ID
+	GetMessageByID(ctx context.Context, id domain.ID) (*domain.Message, error)
+
 	// ListMessagesByChatID получает список сообщений чата
 	ListMessagesByChatID(ctx context.Context, chatID domain.ID, limit, offset int) ([]*domain.Message, int, error)
 
 	// ListMessagesByChatIDWithToolCalls получает сообщения вместе с tool calls
 	ListMessagesByChatIDWithToolCalls(ctx context.Context, chatID domain.ID, limit, offset int) ([]*domain.Message, int, error)
 
-	// ListMessagesWithSubchatsWithToolCalls получает сообщения родительского чата и всех его субчатов с tool calls
-	ListMessagesWithSubchatsWithToolCalls(ctx context.Context, parentChatID domain.ID, limit, offset int) ([]*domain.Message, int, error)
+	// ListBranchMessagesWithToolCalls получает активную ветку чата с tool calls
+	ListBranchMessagesWithToolCalls(ctx context.Context, chatID domain.ID) ([]*domain.Message, error)
+
+	// ListBranchMessagesWithSubchatsWithToolCalls получает активную ветку родительского чата и вызванных из нее субчатов с tool calls
+	ListBranchMessagesWithSubchatsWithToolCalls(ctx context.Context, parentChatID domain.ID, limit, offset int) ([]*domain.Message, int, error)
+
+	// ListMessageLinks получает связи всех сообщений чата для построения дерева веток
+	ListMessageLinks(ctx context.Context, chatID domain.ID) ([]domain.MessageLink, error)
+
+	// SetActiveMessage переключает активную ветку чата
+	SetActiveMessage(ctx context.Context, chatID, messageID domain.ID) error
+
+	// UpdateMessageAttachments обновляет вложения сообщения
+	UpdateMessageAttachments(ctx context.Context, id domain.ID, attachments []domain.Attachment) error
 
 	// DeleteMessage удаляет сообщение
 	DeleteMessage(ctx context.Context, id domain.ID) error
@@ -76,6 +165,18 @@ type ToolCallRepository interface {
 	CompleteToolCall(ctx context.Context, id domain.ID, result []byte) error
 }
 
+// ErasureRepository - безвозвратное удаление данных по заявкам core-service.
+// Методы возвращают количество удаленных записей и могут вызываться повторно
+type ErasureRepository interface {
+	// PurgeOrganizationData удаляет чаты (с сообщениями, вызовами инструментов, отзывами и доступами),
+	// память, расписания и привязки Telegram организации
+	PurgeOrganizationData(ctx context.Context, organizationID domain.ID) (int, error)
+
+	// PurgeUserData удаляет чаты, отзывы, доступы к чужим чатам, расписания, привязки Telegram
+	// и расход токенов пользователя; в чужих чатах его сообщения обезличиваются
+	PurgeUserData(ctx context.Context, userID domain.ID) (int, error)
+}
+
 // SubagentSessionRepository - репозиторий для работы с сессиями субагентов
 type SubagentSessionRepository interface {
 	// Create создает новую сессию субагента
@@ -0,0 +1,173 @@
+package repository
+
+import (
+	"context"
+	"html"
+	"strings"
+
+	"llm-service/internal/domain"
+
+	"github.com/georgysavva/scany/v2/pgxscan"
+	"github.com/opentracing/opentracing-go"
+	"github.com/samber/lo"
+)
+
+// Маркеры совпадений ts_headline. Управляющие символы не встречаются в тексте сообщений,
+// поэтому после HTML-экранирования их можно безопасно заменить на <mark>.
+const (
+	highlightStart = "\x02"
+	highlightStop  = "\x03"
+)
+
+var (
+	snippetOptions = `StartSel="` + highlightStart + `", StopSel="` + highlightStop + `", MaxWords=35, MinWords=15, MaxFragments=2, FragmentDelimiter=" … "`
+	titleOptions   = `StartSel="` + highlightStart + `", StopSel="` + highlightStop + `", HighlightAll=true`
+
+	highlightReplacer = strings.NewReplacer(highlightStart, "<mark>", highlightStop, "</mark>")
+)
+
+// chatSearchRow - строка результата поиска по чатам
+type chatSearchRow struct {
+	chatRow
+	MessageID      *string `db:"message_id"`
+	MessageRole    *string `db:"message_role"`
+	Snippet        string  `db:"snippet"`
+	TitleHighlight string  `db:"title_highlight"`
+	Rank           float64 `db:"rank"`
+	Total          int     `db:"total"`
+}
+
+// SearchChats ищет основные чаты пользователя по названию и содержимому сообщений активной ветки
+// (включая субагентов). Для каждого чата возвращается лучшее совпадение; название чата весит
+// больше текста сообщения.
+func (r *PGXRepository) SearchChats(ctx context.Context, query domain.ChatSearchQuery, page, pageSize int) ([]domain.ChatSearchHit, int, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "repository.SearchChats")
+	defer span.Finish()
+
+	engine := r.engineFactory.Get(ctx)
+
+	// Совпадения ищутся только в активных ветках: от active_message_id основных чатов к корню
+	// и в субчатах, вызванных из активной ветки, - как при показе чата
+	sql := `
+		WITH RECURSIVE q AS (
+			SELECT websearch_to_tsquery('russian', $3) AS query
+		),
+		root_branch AS (
+			SELECT c.id AS chat_id, m.id, m.parent_message_id
+			FROM chats c
+			JOIN messages m ON m.id = c.active_message_id
+			WHERE c.organization_id = $1
+			  AND c.user_id = $2
+			  AND c.parent_chat_id IS NULL
+			UNION ALL
+			SELECT b.chat_id, m.id, m.parent_message_id
+			FROM messages m
+			JOIN root_branch b ON m.id = b.parent_message_id
+		),
+		sub_branch AS (
+			SELECT rb.chat_id, m.id, m.parent_message_id
+			FROM chats c
+			JOIN tool_calls tc ON tc.id = c.parent_tool_call_id
+			JOIN root_branch rb ON rb.id = tc.message_id
+			JOIN messages m ON m.id = c.active_message_id
+			WHERE c.parent_chat_id = rb.chat_id
+			UNION ALL
+			SELECT b.chat_id, m.id, m.parent_message_id
+			FROM messages m
+			JOIN sub_branch b ON m.id = b.parent_message_id
+		),
+		branch AS (
+			SELECT chat_id, id FROM root_branch
+			UNION ALL
+			SELECT chat_id, id FROM sub_branch
+		),
+		matches AS (
+			SELECT DISTINCT ON (branch.chat_id)
+			       branch.chat_id, m.id AS message_id, m.role, m.content,
+			       ts_rank(m.content_tsv, q.query) AS rank
+			FROM branch
+			JOIN messages m ON m.id = branch.id
+			CROSS JOIN q
+			WHERE m.role = ANY($4)
+			  AND m.content_tsv @@ q.query
+			ORDER BY branch.chat_id, rank DESC, m.created_at DESC
+		),
+		hits AS (
+			SELECT c.id, c.organization_id, c.user_id, c.agent_key, c.title, c.status,
+			       c.parent_chat_id, c.parent_tool_call_id, c.created_at, c.updated_at,
+			       matches.message_id, matches.role AS message_role, matches.content,
+			       GREATEST(COALESCE(matches.rank, 0), ts_rank(c.title_tsv, q.query) * 2) AS rank
+			FROM chats c
+			CROSS JOIN q
+			LEFT JOIN matches ON matches.chat_id = c.id
+			WHERE c.organization_id = $1
+			  AND c.user_id = $2
+			  AND c.parent_chat_id IS NULL
+			  AND (matches.chat_id IS NOT NULL OR c.title_tsv @@ q.query)
+		)
+		SELECT hits.id, hits.organization_id, hits.user_id, hits.agent_key, hits.title, hits.status,
+		       hits.parent_chat_id, hits.parent_tool_call_id, hits.created_at, hits.updated_at,
+		       hits.message_id, hits.message_role, hits.rank,
+		       COALESCE(ts_headline('russian', hits.content, q.query, $5), '') AS snippet,
+		       ts_headline('russian', hits.title, q.query, $6) AS title_highlight,
+		       COUNT(*) OVER() AS total
+		FROM hits
+		CROSS JOIN q
+		ORDER BY hits.rank DESC, hits.updated_at DESC
+		LIMIT $7 OFFSET $8
+	`
+
+	roles := lo.Map(query.Roles(), func(role domain.MessageRole, _ int) string { return string(role) })
+	offset := (page - 1) * pageSize
+
+	var rows []chatSearchRow
+	if err := pgxscan.Select(ctx, engine, &rows, sql,
+		query.OrganizationID.String(),
+		query.UserID.String(),
+		strings.TrimSpace(query.Query),
+		roles,
+		snippetOptions,
+		titleOptions,
+		pageSize,
+		offset,
+	); err != nil {
+		return nil, 0, domain.NewInternalError("failed to search chats", err)
+	}
+
+	total := 0
+	hits := make([]domain.ChatSearchHit, 0, len(rows))
+	for _, row := range rows {
+		total = row.Total
+
+		chat, err := row.toDomain()
+		if err != nil {
+			return nil, 0, domain.NewInternalError("failed to parse chat", err)
+		}
+
+		hit := domain.ChatSearchHit{
+			Chat:           chat,
+			Snippet:        highlightText(row.Snippet),
+			TitleHighlight: highlightText(row.TitleHighlight),
+			Rank:           row.Rank,
+		}
+		if row.MessageID != nil {
+			messageID, err := domain.ParseID(*row.MessageID)
+			if err != nil {
+				return nil, 0, domain.NewInternalError("failed to parse message id", err)
+			}
+			hit.MessageID = &messageID
+		}
+		if row.MessageRole != nil {
+			hit.MessageRole = domain.MessageRole(*row.MessageRole)
+		}
+
+		hits = append(hits, hit)
+	}
+
+	return hits, total, nil
+}
+
+// highlightText экранирует текст фрагмента и заменяет маркеры ts_headline на <mark>
+func highlightText(text string) string {
+	return highlightReplacer.Replace(html.EscapeString(text))
+}
@@ -0,0 +1,84 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"llm-service/internal/domain"
+)
+
+func TestSearchChatsMatchesActiveBranchOnly(t *testing.T) {
+	repo := newTestRepository(t, "chats")
+	ctx := context.Background()
+	organizationID, userID := domain.NewID(), domain.NewID()
+
+	createChat := func(title string, parentChatID, parentToolCallID *domain.ID) *domain.Chat {
+		chat := domain.NewChat(organizationID, userID, "main", title, parentChatID, parentToolCallID)
+		if err := repo.CreateChat(ctx, chat); err != nil {
+			t.Fatalf("CreateChat: %v", err)
+		}
+		return chat
+	}
+	createMessage := func(chat *domain.Chat, parent *domain.Message, role domain.MessageRole, content string) *domain.Message {
+		message := &domain.Message{Model: domain.NewModel(), ChatID: chat.ID, Role: role, Content: content}
+		if parent != nil {
+			message.ParentMessageID = &parent.ID
+		}
+		if err := repo.CreateMessage(ctx, message); err != nil {
+			t.Fatalf("CreateMessage: %v", err)
+		}
+		return message
+	}
+	search := func(query string) map[domain.ID]domain.ChatSearchHit {
+		hits, _, err := repo.SearchChats(ctx, domain.ChatSearchQuery{OrganizationID: organizationID, UserID: userID, Query: query}, 1, 20)
+		if err != nil {
+			t.Fatalf("SearchChats(%q): %v", query, err)
+		}
+		result := make(map[domain.ID]domain.ChatSearchHit, len(hits))
+		for _, hit := range hits {
+			result[hit.Chat.ID] = hit
+		}
+		return result
+	}
+
+	chat := createChat("Вопросы", nil, nil)
+	question := createMessage(chat, nil, domain.MessageRoleUser, "Подготовь договор поставки")
+
+	// Первый ответ заменен перегенерацией: его ветка больше не активна
+	abandoned := createMessage(chat, question, domain.MessageRoleAssistant, "Черновик с неустойкой")
+	toolCall := &domain.ToolCall{
+		Model:     domain.NewModel(),
+		Name:      "switch_to_subagent",
+		Arguments: json.RawMessage(`{}`),
+		Status:    domain.ToolCallStatusCompleted,
+	}
+	if err := repo.CreateToolCall(ctx, abandoned.ID, toolCall); err != nil {
+		t.Fatalf("CreateToolCall: %v", err)
+	}
+	abandonedSubagent := createChat("Субагент", &chat.ID, &toolCall.ID)
+	createMessage(abandonedSubagent, nil, domain.MessageRoleAssistant, "Проверка контрагента")
+
+	active := createMessage(chat, question, domain.MessageRoleAssistant, "Договор с рассрочкой платежа")
+
+	hits := search("неустойка")
+	if _, ok := hits[chat.ID]; ok {
+		t.Errorf("search found a message from an inactive branch")
+	}
+	if hits := search("контрагент"); len(hits) != 0 {
+		t.Errorf("search found a subagent message called from an inactive branch: %+v", hits)
+	}
+
+	hit, ok := search("рассрочка")[chat.ID]
+	if !ok {
+		t.Fatalf("search did not find a message from the active branch")
+	}
+	if hit.MessageID == nil || *hit.MessageID != active.ID {
+		t.Errorf("hit message = %v, want %s", hit.MessageID, active.ID)
+	}
+
+	// Общий корень веток тоже относится к активной ветке
+	if _, ok := search("поставка")[chat.ID]; !ok {
+		t.Errorf("search did not find the question shared by both branches")
+	}
+}
@@ -0,0 +1,214 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"llm-service/internal/domain"
+
+	"github.com/georgysavva/scany/v2/pgxscan"
+	"github.com/jackc/pgx/v5"
+	"github.com/opentracing/opentracing-go"
+)
+
+// chatShareRow - структура для маппинга из БД
+type chatShareRow struct {
+	ChatID    string    `db:"chat_id"`
+	UserID    string    `db:"user_id"`
+	Role      string    `db:"role"`
+	SharedBy  string    `db:"shared_by"`
+	CreatedAt time.Time `db:"created_at"`
+}
+
+func (r *chatShareRow) toDomain() (domain.ChatShare, error) {
+	chatID, err := domain.ParseID(r.ChatID)
+	if err != nil {
+		return domain.ChatShare{}, err
+	}
+
+	userID, err := domain.ParseID(r.UserID)
+	if err != nil {
+		return domain.ChatShare{}, err
+	}
+
+	sharedBy, err := domain.ParseID(r.SharedBy)
+	if err != nil {
+		return domain.ChatShare{}, err
+	}
+
+	return domain.ChatShare{
+		ChatID:    chatID,
+		UserID:    userID,
+		Role:      domain.ChatShareRole(r.Role),
+		SharedBy:  sharedBy,
+		CreatedAt: r.CreatedAt,
+	}, nil
+}
+
+// UpsertChatShare выдает доступ к чату или меняет роль
+func (r *PGXRepository) UpsertChatShare(ctx context.Context, share domain.ChatShare) error {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "repository.UpsertChatShare")
+	defer span.Finish()
+
+	engine := r.engineFactory.Get(ctx)
+
+	query := `
+		INSERT INTO chat_shares (chat_id, user_id, role, shared_by, created_at)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (chat_id, user_id) DO UPDATE
+		SET role = EXCLUDED.role, shared_by = EXCLUDED.shared_by
+	`
+
+	_, err := engine.Exec(ctx, query,
+		share.ChatID.String(),
+		share.UserID.String(),
+		string(share.Role),
+		share.SharedBy.String(),
+		share.CreatedAt,
+	)
+	if err != nil {
+		return domain.NewInternalError("failed to save chat share", err)
+	}
+
+	return nil
+}
+
+// GetChatShare получает доступ пользователя к чату
+func (r *PGXRepository) GetChatShare(ctx context.Context, chatID, userID domain.ID) (domain.ChatShare, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "repository.GetChatShare")
+	defer span.Finish()
+
+	engine := r.engineFactory.Get(ctx)
+
+	query := `
+		SELECT chat_id, user_id, role, shared_by, created_at
+		FROM chat_shares
+		WHERE chat_id = $1 AND user_id = $2
+	`
+
+	var row chatShareRow
+	if err := pgxscan.Get(ctx, engine, &row, query, chatID.String(), userID.String()); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return domain.ChatShare{}, domain.NewNotFoundError("chat share not found")
+		}
+		return domain.ChatShare{}, domain.NewInternalError("failed to get chat share", err)
+	}
+
+	return row.toDomain()
+}
+
+// ListChatShares получает всех пользователей, с которыми поделились чатом
+func (r *PGXRepository) ListChatShares(ctx context.Context, chatID domain.ID) ([]domain.ChatShare, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "repository.ListChatShares")
+	defer span.Finish()
+
+	engine := r.engineFactory.Get(ctx)
+
+	query := `
+		SELECT chat_id, user_id, role, shared_by, created_at
+		FROM chat_shares
+		WHERE chat_id = $1
+		ORDER BY created_at
+	`
+
+	var rows []chatShareRow
+	if err := pgxscan.Select(ctx, engine, &rows, query, chatID.String()); err != nil {
+		return nil, domain.NewInternalError("failed to list chat shares", err)
+	}
+
+	shares := make([]domain.ChatShare, 0, len(rows))
+	for _, row := range rows {
+		share, err := row.toDomain()
+		if err != nil {
+			return nil, domain.NewInternalError("failed to parse chat share", err)
+		}
+		shares = append(shares, share)
+	}
+
+	return shares, nil
+}
+
+// DeleteChatShare отзывает доступ пользователя к чату
+func (r *PGXRepository) DeleteChatShare(ctx context.Context, chatID, userID domain.ID) error {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "repository.DeleteChatShare")
+	defer span.Finish()
+
+	engine := r.engineFactory.Get(ctx)
+
+	query := `DELETE FROM chat_shares WHERE chat_id = $1 AND user_id = $2`
+
+	tag, err := engine.Exec(ctx, query, chatID.String(), userID.String())
+	if err != nil {
+		return domain.NewInternalError("failed to delete chat share", err)
+	}
+
+	if tag.RowsAffected() == 0 {
+		return domain.NewNotFoundError("chat share not found")
+	}
+
+	return nil
+}
+
+// sharedChatRow - чат вместе с доступом текущего пользователя
+type sharedChatRow struct {
+	chatRow
+	ShareRole      string    `db:"share_role"`
+	ShareSharedBy  string    `db:"share_shared_by"`
+	ShareCreatedAt time.Time `db:"share_created_at"`
+	Total          int       `db:"total"`
+}
+
+// ListSharedChats получает чаты организации, которыми поделились с пользователем
+func (r *PGXRepository) ListSharedChats(ctx context.Context, organizationID, userID domain.ID, page, pageSize int) ([]domain.SharedChat, int, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "repository.ListSharedChats")
+	defer span.Finish()
+
+	engine := r.engineFactory.Get(ctx)
+
+	query := `
+		SELECT c.id, c.organization_id, c.user_id, c.agent_key, c.title, c.status,
+		       c.parent_chat_id, c.parent_tool_call_id, c.created_at, c.updated_at,
+		       s.role AS share_role, s.shared_by AS share_shared_by, s.created_at AS share_created_at,
+		       COUNT(*) OVER() AS total
+		FROM chat_shares s
+		JOIN chats c ON c.id = s.chat_id
+		WHERE s.user_id = $1 AND c.organization_id = $2
+		ORDER BY c.updated_at DESC
+		LIMIT $3 OFFSET $4
+	`
+
+	var rows []sharedChatRow
+	if err := pgxscan.Select(ctx, engine, &rows, query, userID.String(), organizationID.String(), pageSize, (page-1)*pageSize); err != nil {
+		return nil, 0, domain.NewInternalError("failed to list shared chats", err)
+	}
+
+	total := 0
+	chats := make([]domain.SharedChat, 0, len(rows))
+	for _, row := range rows {
+		total = row.Total
+
+		chat, err := row.toDomain()
+		if err != nil {
+			return nil, 0, domain.NewInternalError("failed to parse chat", err)
+		}
+
+		sharedBy, err := domain.ParseID(row.ShareSharedBy)
+		if err != nil {
+			return nil, 0, domain.NewInternalError("failed to parse chat share", err)
+		}
+
+		chats = append(chats, domain.SharedChat{
+			Chat: chat,
+			Share: domain.ChatShare{
+				ChatID:    chat.ID,
+				UserID:    userID,
+				Role:      domain.ChatShareRole(row.ShareRole),
+				SharedBy:  sharedBy,
+				CreatedAt: row.ShareCreatedAt,
+			},
+		})
+	}
+
+	return chats, total, nil
+}
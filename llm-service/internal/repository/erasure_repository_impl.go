@@ -0,0 +1,63 @@
+package repository
+
+import (
+	"context"
+
+	"llm-service/internal/domain"
+	"llm-service/internal/logger"
+
+	"github.com/opentracing/opentracing-go"
+)
+
+// organizationPurgeQueries удаляют данные организации. Сообщения, вызовы инструментов, отзывы
+// и доступы удаляются каскадом вместе с чатами; строки, удаленные каскадом, в отчет не входят
+var organizationPurgeQueries = []string{
+	`DELETE FROM telegram_chat_bindings WHERE organization_id = $1`,
+	`DELETE FROM agent_schedules WHERE organization_id = $1`,
+	`DELETE FROM message_feedback WHERE organization_id = $1`,
+	`DELETE FROM chats WHERE organization_id = $1`,
+	`DELETE FROM llm_organization_memory_facts WHERE organization_id = $1`,
+}
+
+// userPurgeQueries удаляют данные пользователя во всех организациях
+var userPurgeQueries = []string{
+	`DELETE FROM telegram_chat_bindings WHERE user_id = $1`,
+	`DELETE FROM agent_schedules WHERE user_id = $1`,
+	`DELETE FROM message_feedback WHERE user_id = $1`,
+	`DELETE FROM chat_shares WHERE user_id = $1`,
+	`DELETE FROM chats WHERE user_id = $1`,
+	`UPDATE messages SET author_user_id = NULL WHERE author_user_id = $1`,
+	`DELETE FROM llm_token_usage WHERE user_id = $1`,
+}
+
+// PurgeOrganizationData удаляет все данные организации
+func (r *PGXRepository) PurgeOrganizationData(ctx context.Context, organizationID domain.ID) (int, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "repository.PurgeOrganizationData")
+	defer span.Finish()
+
+	return r.execPurge(ctx, organizationPurgeQueries, organizationID)
+}
+
+// PurgeUserData удаляет все данные пользователя
+func (r *PGXRepository) PurgeUserData(ctx context.Context, userID domain.ID) (int, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "repository.PurgeUserData")
+	defer span.Finish()
+
+	return r.execPurge(ctx, userPurgeQueries, userID)
+}
+
+func (r *PGXRepository) execPurge(ctx context.Context, queries []string, id domain.ID) (int, error) {
+	engine := r.engineFactory.Get(ctx)
+
+	affected := 0
+	for _, query := range queries {
+		tag, err := engine.Exec(ctx, query, uuidToPgtype(id))
+		if err != nil {
+			logger.Errorf(ctx, "failed to purge data: %v", err)
+			return 0, err
+		}
+		affected += int(tag.RowsAffected())
+	}
+
+	return affected, nil
+}
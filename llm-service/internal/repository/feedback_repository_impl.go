@@ -0,0 +1,297 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"llm-service/internal/domain"
+
+	"github.com/georgysavva/scany/v2/pgxscan"
+	"github.com/opentracing/opentracing-go"
+)
+
+// messageFeedbackRow - структура для маппинга из БД
+type messageFeedbackRow struct {
+	MessageID      string    `db:"message_id"`
+	UserID         string    `db:"user_id"`
+	OrganizationID string    `db:"organization_id"`
+	ChatID         string    `db:"chat_id"`
+	AgentKey       string    `db:"agent_key"`
+	Rating         string    `db:"rating"`
+	Reasons        []string  `db:"reasons"`
+	Comment        string    `db:"comment"`
+	ToolNames      []string  `db:"tool_names"`
+	CreatedAt      time.Time `db:"created_at"`
+	UpdatedAt      time.Time `db:"updated_at"`
+}
+
+func (r *messageFeedbackRow) toDomain() (domain.MessageFeedback, error) {
+	messageID, err := domain.ParseID(r.MessageID)
+	if err != nil {
+		return domain.MessageFeedback{}, err
+	}
+
+	userID, err := domain.ParseID(r.UserID)
+	if err != nil {
+		return domain.MessageFeedback{}, err
+	}
+
+	organizationID, err := domain.ParseID(r.OrganizationID)
+	if err != nil {
+		return domain.MessageFeedback{}, err
+	}
+
+	chatID, err := domain.ParseID(r.ChatID)
+	if err != nil {
+		return domain.MessageFeedback{}, err
+	}
+
+	reasons := make([]domain.FeedbackReason, 0, len(r.Reasons))
+	for _, reason := range r.Reasons {
+		reasons = append(reasons, domain.FeedbackReason(reason))
+	}
+
+	return domain.MessageFeedback{
+		MessageID:      messageID,
+		UserID:         userID,
+		OrganizationID: organizationID,
+		ChatID:         chatID,
+		AgentKey:       r.AgentKey,
+		Rating:         domain.FeedbackRating(r.Rating),
+		Reasons:        reasons,
+		Comment:        r.Comment,
+		ToolNames:      r.ToolNames,
+		CreatedAt:      r.CreatedAt,
+		UpdatedAt:      r.UpdatedAt,
+	}, nil
+}
+
+// UpsertMessageFeedback сохраняет оценку ответа. Инструменты ответа собираются по ветке
+// от оцененного сообщения до вопроса пользователя, включая инструменты вызванных субагентов.
+func (r *PGXRepository) UpsertMessageFeedback(ctx context.Context, feedback *domain.MessageFeedback) error {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "repository.UpsertMessageFeedback")
+	defer span.Finish()
+
+	engine := r.engineFactory.Get(ctx)
+
+	query := `
+		WITH RECURSIVE turn AS (
+			SELECT id, parent_message_id, role FROM messages WHERE id = $1
+			UNION ALL
+			SELECT m.id, m.parent_message_id, m.role
+			FROM messages m
+			JOIN turn t ON m.id = t.parent_message_id
+			WHERE t.role <> 'user'
+		),
+		turn_tools AS (
+			SELECT tc.id, tc.name
+			FROM tool_calls tc
+			JOIN turn ON tc.message_id = turn.id
+		),
+		tools AS (
+			SELECT name FROM turn_tools
+			UNION
+			SELECT tc.name
+			FROM tool_calls tc
+			JOIN messages m ON m.id = tc.message_id
+			JOIN chats c ON c.id = m.chat_id
+			WHERE c.parent_tool_call_id IN (SELECT id FROM turn_tools)
+		)
+		INSERT INTO message_feedback (
+			message_id, user_id, organization_id, chat_id, agent_key,
+			rating, reasons, comment, tool_names, created_at, updated_at
+		)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, ARRAY(SELECT name FROM tools ORDER BY name), $9, $9)
+		ON CONFLICT (message_id, user_id) DO UPDATE
+		SET rating = EXCLUDED.rating,
+		    reasons = EXCLUDED.reasons,
+		    comment = EXCLUDED.comment,
+		    tool_names = EXCLUDED.tool_names,
+		    updated_at = EXCLUDED.updated_at
+		RETURNING tool_names, created_at
+	`
+
+	reasons := make([]string, 0, len(feedback.Reasons))
+	for _, reason := range feedback.Reasons {
+		reasons = append(reasons, string(reason))
+	}
+
+	var saved struct {
+		ToolNames []string  `db:"tool_names"`
+		CreatedAt time.Time `db:"created_at"`
+	}
+	err := pgxscan.Get(ctx, engine, &saved, query,
+		feedback.MessageID.String(),
+		feedback.UserID.String(),
+		feedback.OrganizationID.String(),
+		feedback.ChatID.String(),
+		feedback.AgentKey,
+		string(feedback.Rating),
+		reasons,
+		feedback.Comment,
+		feedback.UpdatedAt,
+	)
+	if err != nil {
+		return domain.NewInternalError("failed to save message feedback", err)
+	}
+
+	feedback.ToolNames = saved.ToolNames
+	feedback.CreatedAt = saved.CreatedAt
+
+	return nil
+}
+
+// ListUserMessageFeedback получает оценки пользователя для списка сообщений
+func (r *PGXRepository) ListUserMessageFeedback(ctx context.Context, userID domain.ID, messageIDs []domain.ID) ([]domain.MessageFeedback, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "repository.ListUserMessageFeedback")
+	defer span.Finish()
+
+	if len(messageIDs) == 0 {
+		return nil, nil
+	}
+
+	engine := r.engineFactory.Get(ctx)
+
+	query := `
+		SELECT message_id, user_id, organization_id, chat_id, agent_key,
+		       rating, reasons, comment, tool_names, created_at, updated_at
+		FROM message_feedback
+		WHERE user_id = $1 AND message_id = ANY($2)
+	`
+
+	var rows []messageFeedbackRow
+	if err := pgxscan.Select(ctx, engine, &rows, query, userID.String(), uuidsToPgtype(messageIDs)); err != nil {
+		return nil, domain.NewInternalError("failed to list message feedback", err)
+	}
+
+	return feedbackRowsToDomain(rows)
+}
+
+// feedbackStatsRow - агрегированные оценки
+type feedbackStatsRow struct {
+	Key      string `db:"key"`
+	Positive int    `db:"positive"`
+	Negative int    `db:"negative"`
+}
+
+// feedbackReasonRow - количество упоминаний причины в отрицательных оценках агента
+type feedbackReasonRow struct {
+	AgentKey string `db:"agent_key"`
+	Reason   string `db:"reason"`
+	Count    int    `db:"count"`
+}
+
+// GetFeedbackReport агрегирует оценки организации за период по агентам и инструментам
+func (r *PGXRepository) GetFeedbackReport(ctx context.Context, query domain.FeedbackReportQuery) (domain.FeedbackReport, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "repository.GetFeedbackReport")
+	defer span.Finish()
+
+	engine := r.engineFactory.Get(ctx)
+
+	// Общий фильтр всех запросов отчета: $1 - организация, $2/$3 - период, $4 - агент (пустой - все)
+	const filter = `
+		f.organization_id = $1
+		AND f.created_at >= $2 AND f.created_at < $3
+		AND ($4 = '' OR f.agent_key = $4)
+	`
+	args := []interface{}{query.OrganizationID.String(), query.From, query.To, query.AgentKey}
+
+	var agentRows []feedbackStatsRow
+	agentsQuery := `
+		SELECT f.agent_key AS key,
+		       COUNT(*) FILTER (WHERE f.rating = 'positive') AS positive,
+		       COUNT(*) FILTER (WHERE f.rating = 'negative') AS negative
+		FROM message_feedback f
+		WHERE ` + filter + `
+		GROUP BY f.agent_key
+		ORDER BY negative DESC, f.agent_key
+	`
+	if err := pgxscan.Select(ctx, engine, &agentRows, agentsQuery, args...); err != nil {
+		return domain.FeedbackReport{}, domain.NewInternalError("failed to aggregate feedback by agent", err)
+	}
+
+	var reasonRows []feedbackReasonRow
+	reasonsQuery := `
+		SELECT f.agent_key, reason, COUNT(*) AS count
+		FROM message_feedback f
+		CROSS JOIN LATERAL unnest(f.reasons) AS reason
+		WHERE ` + filter + ` AND f.rating = 'negative'
+		GROUP BY f.agent_key, reason
+	`
+	if err := pgxscan.Select(ctx, engine, &reasonRows, reasonsQuery, args...); err != nil {
+		return domain.FeedbackReport{}, domain.NewInternalError("failed to aggregate feedback reasons", err)
+	}
+
+	var toolRows []feedbackStatsRow
+	toolsQuery := `
+		SELECT tool_name AS key,
+		       COUNT(*) FILTER (WHERE f.rating = 'positive') AS positive,
+		       COUNT(*) FILTER (WHERE f.rating = 'negative') AS negative
+		FROM message_feedback f
+		CROSS JOIN LATERAL unnest(f.tool_names) AS tool_name
+		WHERE ` + filter + `
+		GROUP BY tool_name
+		ORDER BY negative DESC, tool_name
+	`
+	if err := pgxscan.Select(ctx, engine, &toolRows, toolsQuery, args...); err != nil {
+		return domain.FeedbackReport{}, domain.NewInternalError("failed to aggregate feedback by tool", err)
+	}
+
+	var commentRows []messageFeedbackRow
+	commentsQuery := `
+		SELECT f.message_id, f.user_id, f.organization_id, f.chat_id, f.agent_key,
+		       f.rating, f.reasons, f.comment, f.tool_names, f.created_at, f.updated_at
+		FROM message_feedback f
+		WHERE ` + filter + ` AND f.rating = 'negative' AND f.comment <> ''
+		ORDER BY f.updated_at DESC
+		LIMIT $5
+	`
+	if err := pgxscan.Select(ctx, engine, &commentRows, commentsQuery, append(args, query.CommentsLimit)...); err != nil {
+		return domain.FeedbackReport{}, domain.NewInternalError("failed to list feedback comments", err)
+	}
+
+	reasons := make(map[string]map[domain.FeedbackReason]int)
+	for _, row := range reasonRows {
+		if reasons[row.AgentKey] == nil {
+			reasons[row.AgentKey] = make(map[domain.FeedbackReason]int)
+		}
+		reasons[row.AgentKey][domain.FeedbackReason(row.Reason)] = row.Count
+	}
+
+	var report domain.FeedbackReport
+	for _, row := range agentRows {
+		report.Totals.Positive += row.Positive
+		report.Totals.Negative += row.Negative
+		report.Agents = append(report.Agents, domain.AgentFeedbackStats{
+			AgentKey:      row.Key,
+			FeedbackStats: domain.FeedbackStats{Positive: row.Positive, Negative: row.Negative},
+			Reasons:       reasons[row.Key],
+		})
+	}
+	for _, row := range toolRows {
+		report.Tools = append(report.Tools, domain.ToolFeedbackStats{
+			ToolName:      row.Key,
+			FeedbackStats: domain.FeedbackStats{Positive: row.Positive, Negative: row.Negative},
+		})
+	}
+
+	comments, err := feedbackRowsToDomain(commentRows)
+	if err != nil {
+		return domain.FeedbackReport{}, err
+	}
+	report.Comments = comments
+
+	return report, nil
+}
+
+func feedbackRowsToDomain(rows []messageFeedbackRow) ([]domain.MessageFeedback, error) {
+	result := make([]domain.MessageFeedback, 0, len(rows))
+	for _, row := range rows {
+		feedback, err := row.toDomain()
+		if err != nil {
+			return nil, domain.NewInternalError("failed to parse message feedback", err)
+		}
+		result = append(result, feedback)
+	}
+	return result, nil
+}
@@ -0,0 +1,237 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"testing"
+	"time"
+
+	"llm-service/internal/domain"
+)
+
+// feedbackFixture строит ветки сообщений чата для проверки сбора инструментов ответа
+type feedbackFixture struct {
+	t    *testing.T
+	repo *PGXRepository
+	ctx  context.Context
+}
+
+func (f *feedbackFixture) chat(organizationID domain.ID, agentKey string, parentChatID, parentToolCallID *domain.ID) *domain.Chat {
+	f.t.Helper()
+
+	chat := domain.NewChat(organizationID, domain.NewID(), agentKey, "Чат", parentChatID, parentToolCallID)
+	if err := f.repo.CreateChat(f.ctx, chat); err != nil {
+		f.t.Fatalf("CreateChat: %v", err)
+	}
+	return chat
+}
+
+// message сохраняет сообщение после parent и вызовы инструментов с именами tools; возвращает сообщение и ID вызовов
+func (f *feedbackFixture) message(chat *domain.Chat, parent *domain.Message, role domain.MessageRole, tools ...string) (*domain.Message, []domain.ID) {
+	f.t.Helper()
+
+	message := &domain.Message{Model: domain.NewModel(), ChatID: chat.ID, Role: role, Content: string(role)}
+	if parent != nil {
+		message.ParentMessageID = &parent.ID
+	}
+	if err := f.repo.CreateMessage(f.ctx, message); err != nil {
+		f.t.Fatalf("CreateMessage: %v", err)
+	}
+
+	ids := make([]domain.ID, 0, len(tools))
+	for _, name := range tools {
+		toolCall := &domain.ToolCall{
+			Model:     domain.NewModel(),
+			Name:      name,
+			Arguments: json.RawMessage(`{}`),
+			Status:    domain.ToolCallStatusCompleted,
+		}
+		if err := f.repo.CreateToolCall(f.ctx, message.ID, toolCall); err != nil {
+			f.t.Fatalf("CreateToolCall: %v", err)
+		}
+		ids = append(ids, toolCall.ID)
+	}
+	return message, ids
+}
+
+func (f *feedbackFixture) rate(feedback *domain.MessageFeedback) {
+	f.t.Helper()
+
+	if err := f.repo.UpsertMessageFeedback(f.ctx, feedback); err != nil {
+		f.t.Fatalf("UpsertMessageFeedback: %v", err)
+	}
+}
+
+func TestUpsertMessageFeedbackCollectsTurnTools(t *testing.T) {
+	repo := newTestRepository(t, "chats")
+	f := &feedbackFixture{t: t, repo: repo, ctx: context.Background()}
+	organizationID := domain.NewID()
+	chat := f.chat(organizationID, "main", nil, nil)
+
+	// Предыдущий ход: его инструменты не относятся к оцениваемому ответу
+	previousQuestion, _ := f.message(chat, nil, domain.MessageRoleUser)
+	previousAnswer, _ := f.message(chat, previousQuestion, domain.MessageRoleAssistant, "list_facts")
+
+	// Оцениваемый ход: поиск в интернете и переход к субагенту, который сам вызвал инструменты
+	question, _ := f.message(chat, previousAnswer, domain.MessageRoleUser)
+	call, toolCallIDs := f.message(chat, question, domain.MessageRoleAssistant, "web_search", "switch_to_subagent")
+	subagentToolCallID := toolCallIDs[1]
+	toolResult, _ := f.message(chat, call, domain.MessageRoleTool)
+	answer, _ := f.message(chat, toolResult, domain.MessageRoleAssistant)
+
+	subagentChat := f.chat(organizationID, "legal_agent", &chat.ID, &subagentToolCallID)
+	subagentTask, _ := f.message(subagentChat, nil, domain.MessageRoleUser)
+	f.message(subagentChat, subagentTask, domain.MessageRoleAssistant, "search_contracts", "web_search")
+
+	// Альтернативная версия ответа на тот же вопрос в соседней ветке
+	f.message(chat, question, domain.MessageRoleAssistant, "calendar")
+
+	// Субагент другого хода не попадает в оценку
+	_, otherToolCallIDs := f.message(chat, previousQuestion, domain.MessageRoleAssistant, "switch_to_subagent")
+	otherSubagent := f.chat(organizationID, "crm_agent", &chat.ID, &otherToolCallIDs[0])
+	f.message(otherSubagent, nil, domain.MessageRoleAssistant, "crm_search")
+
+	feedback := &domain.MessageFeedback{
+		MessageID:      answer.ID,
+		UserID:         chat.UserID,
+		OrganizationID: organizationID,
+		ChatID:         chat.ID,
+		AgentKey:       "main",
+		Rating:         domain.FeedbackRatingNegative,
+		Reasons:        []domain.FeedbackReason{domain.FeedbackReasonIncorrect},
+		UpdatedAt:      time.Now().UTC().Truncate(time.Microsecond),
+	}
+	f.rate(feedback)
+
+	want := []string{"search_contracts", "switch_to_subagent", "web_search"}
+	if !reflect.DeepEqual(feedback.ToolNames, want) {
+		t.Errorf("tool names = %v, want %v", feedback.ToolNames, want)
+	}
+	createdAt := feedback.CreatedAt
+
+	// Повторная оценка заменяет предыдущую, время создания сохраняется
+	feedback.Rating = domain.FeedbackRatingPositive
+	feedback.Reasons = []domain.FeedbackReason{domain.FeedbackReasonHelpful}
+	feedback.UpdatedAt = feedback.UpdatedAt.Add(time.Minute)
+	f.rate(feedback)
+
+	stored, err := repo.ListUserMessageFeedback(f.ctx, chat.UserID, []domain.ID{answer.ID})
+	if err != nil {
+		t.Fatalf("ListUserMessageFeedback: %v", err)
+	}
+	if len(stored) != 1 {
+		t.Fatalf("stored feedback = %d, want 1", len(stored))
+	}
+	if stored[0].Rating != domain.FeedbackRatingPositive || !reflect.DeepEqual(stored[0].ToolNames, want) {
+		t.Errorf("stored feedback = %+v, want positive with tools %v", stored[0], want)
+	}
+	if !stored[0].CreatedAt.Equal(createdAt) {
+		t.Errorf("created_at = %s, want %s", stored[0].CreatedAt, createdAt)
+	}
+}
+
+func TestGetFeedbackReport(t *testing.T) {
+	repo := newTestRepository(t, "chats")
+	f := &feedbackFixture{t: t, repo: repo, ctx: context.Background()}
+	organizationID := domain.NewID()
+	now := time.Now().UTC().Truncate(time.Microsecond)
+
+	chat := f.chat(organizationID, "main", nil, nil)
+	question, _ := f.message(chat, nil, domain.MessageRoleUser)
+	call, toolCallIDs := f.message(chat, question, domain.MessageRoleAssistant, "switch_to_subagent")
+	subagentChat := f.chat(organizationID, "legal_agent", &chat.ID, &toolCallIDs[0])
+	f.message(subagentChat, nil, domain.MessageRoleAssistant, "search_contracts")
+	toolResult, _ := f.message(chat, call, domain.MessageRoleTool)
+	answer, _ := f.message(chat, toolResult, domain.MessageRoleAssistant)
+
+	secondQuestion, _ := f.message(chat, answer, domain.MessageRoleUser)
+	secondAnswer, _ := f.message(chat, secondQuestion, domain.MessageRoleAssistant, "web_search")
+
+	legalChat := f.chat(organizationID, "legal_agent", nil, nil)
+	legalQuestion, _ := f.message(legalChat, nil, domain.MessageRoleUser)
+	legalAnswer, _ := f.message(legalChat, legalQuestion, domain.MessageRoleAssistant, "search_contracts")
+
+	newFeedback := func(message *domain.Message, chat *domain.Chat, rating domain.FeedbackRating, comment string, reasons ...domain.FeedbackReason) *domain.MessageFeedback {
+		return &domain.MessageFeedback{
+			MessageID:      message.ID,
+			UserID:         domain.NewID(),
+			OrganizationID: organizationID,
+			ChatID:         chat.ID,
+			AgentKey:       chat.AgentKey,
+			Rating:         rating,
+			Reasons:        reasons,
+			Comment:        comment,
+			UpdatedAt:      now,
+		}
+	}
+
+	// Ответ с субагентом: отрицательная и положительная оценки разных пользователей
+	f.rate(newFeedback(answer, chat, domain.FeedbackRatingNegative, "Не нашел договор",
+		domain.FeedbackReasonIncorrect, domain.FeedbackReasonToolError))
+	f.rate(newFeedback(answer, chat, domain.FeedbackRatingPositive, ""))
+	f.rate(newFeedback(secondAnswer, chat, domain.FeedbackRatingNegative, "", domain.FeedbackReasonIncorrect))
+	f.rate(newFeedback(legalAnswer, legalChat, domain.FeedbackRatingPositive, "Спасибо"))
+
+	// Оценка другой организации в отчет не попадает
+	otherChat := f.chat(domain.NewID(), "main", nil, nil)
+	otherAnswer, _ := f.message(otherChat, nil, domain.MessageRoleAssistant, "web_search")
+	other := newFeedback(otherAnswer, otherChat, domain.FeedbackRatingNegative, "Чужая")
+	other.OrganizationID = otherChat.OrganizationID
+	f.rate(other)
+
+	report, err := repo.GetFeedbackReport(f.ctx, domain.FeedbackReportQuery{
+		OrganizationID: organizationID,
+		From:           now.Add(-time.Hour),
+		To:             now.Add(time.Hour),
+		CommentsLimit:  10,
+	})
+	if err != nil {
+		t.Fatalf("GetFeedbackReport: %v", err)
+	}
+
+	if want := (domain.FeedbackStats{Positive: 2, Negative: 2}); report.Totals != want {
+		t.Errorf("totals = %+v, want %+v", report.Totals, want)
+	}
+
+	wantAgents := []domain.AgentFeedbackStats{
+		{
+			AgentKey:      "main",
+			FeedbackStats: domain.FeedbackStats{Positive: 1, Negative: 2},
+			Reasons:       map[domain.FeedbackReason]int{domain.FeedbackReasonIncorrect: 2, domain.FeedbackReasonToolError: 1},
+		},
+		{AgentKey: "legal_agent", FeedbackStats: domain.FeedbackStats{Positive: 1}},
+	}
+	if !reflect.DeepEqual(report.Agents, wantAgents) {
+		t.Errorf("agents = %+v, want %+v", report.Agents, wantAgents)
+	}
+
+	// Инструмент субагента учитывается в оценках ответа основного агента
+	wantTools := []domain.ToolFeedbackStats{
+		{ToolName: "search_contracts", FeedbackStats: domain.FeedbackStats{Positive: 2, Negative: 1}},
+		{ToolName: "switch_to_subagent", FeedbackStats: domain.FeedbackStats{Positive: 1, Negative: 1}},
+		{ToolName: "web_search", FeedbackStats: domain.FeedbackStats{Negative: 1}},
+	}
+	if !reflect.DeepEqual(report.Tools, wantTools) {
+		t.Errorf("tools = %+v, want %+v", report.Tools, wantTools)
+	}
+
+	if len(report.Comments) != 1 || report.Comments[0].Comment != "Не нашел договор" {
+		t.Errorf("comments = %+v, want only the negative comment of the organization", report.Comments)
+	}
+
+	// Отчет по одному агенту
+	legalReport, err := repo.GetFeedbackReport(f.ctx, domain.FeedbackReportQuery{
+		OrganizationID: organizationID,
+		AgentKey:       "legal_agent",
+		From:           now.Add(-time.Hour),
+		To:             now.Add(time.Hour),
+		CommentsLimit:  10,
+	})
+	if err != nil {
+		t.Fatalf("GetFeedbackReport: %v", err)
+	}
+	if want := (domain.FeedbackStats{Positive: 1}); legalReport.Totals != want {
+		t.Errorf("legal_agent totals = %+v, want %+v", legalReport.Totals, want)
+	}
+}
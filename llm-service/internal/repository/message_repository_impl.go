@@ -2,25 +2,32 @@ package repository
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"llm-service/internal/domain"
 	"time"
 
-	sq "github.com/Masterminds/squirrel"
 	"github.com/georgysavva/scany/v2/pgxscan"
 	"github.com/jackc/pgx/v5"
 	"github.com/opentracing/opentracing-go"
+	"github.com/samber/lo"
 )
 
 // messageRow - структура для маппинга из БД
 type messageRow struct {
-	ID         string    `db:"id"`
-	ChatID     string    `db:"chat_id"`
-	Role       string    `db:"role"`
-	Content    string    `db:"content"`
-	Sender     *string   `db:"sender"`
-	ToolCallID *string   `db:"tool_call_id"`
-	CreatedAt  time.Time `db:"created_at"`
+	ID         string  `db:"id"`
+	ChatID     string  `db:"chat_id"`
+	Role       string  `db:"role"`
+	Content    string  `db:"content"`
+	Sender     *string `db:"sender"`
+	ToolCallID *string `db:"tool_call_id"`
+	// Автор пользовательского сообщения
+	AuthorUserID *string `db:"author_user_id"`
+	// Предыдущее сообщение ветки
+	ParentMessageID *string `db:"parent_message_id"`
+	// JSON массив domain.Attachment
+	Attachments []byte    `db:"attachments"`
+	CreatedAt   time.Time `db:"created_at"`
 }
 
 func (r *messageRow) toDomain() (*domain.Message, error) {
@@ -54,6 +61,28 @@ func (r *messageRow) toDomain() (*domain.Message, error) {
 		msg.ToolCallID = &toolCallID
 	}
 
+	if r.AuthorUserID != nil {
+		authorID, err := domain.ParseID(*r.AuthorUserID)
+		if err != nil {
+			return nil, err
+		}
+		msg.AuthorUserID = &authorID
+	}
+
+	if r.ParentMessageID != nil {
+		parentID, err := domain.ParseID(*r.ParentMessageID)
+		if err != nil {
+			return nil, err
+		}
+		msg.ParentMessageID = &parentID
+	}
+
+	if len(r.Attachments) > 0 {
+		if err := json.Unmarshal(r.Attachments, &msg.Attachments); err != nil {
+			return nil, err
+		}
+	}
+
 	return msg, nil
 }
 
@@ -64,19 +93,35 @@ func (r *PGXRepository) CreateMessage(ctx context.Context, message *domain.Messa
 
 	engine := r.engineFactory.Get(ctx)
 
+	attachments, err := json.Marshal(lo.Ternary(message.Attachments == nil, []domain.Attachment{}, message.Attachments))
+	if err != nil {
+		return domain.NewInternalError("failed to marshal message attachments", err)
+	}
+
+	// Сообщение продолжает активную ветку чата (или ветку от явно заданного родителя)
+	// и становится ее последним сообщением. Строка чата блокируется, чтобы параллельные
+	// сохранения выстраивались в цепочку, а не в соседние ветки.
 	query := `
-		INSERT INTO messages (id, chat_id, role, content, sender, tool_call_id, created_at)
-		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		WITH prev AS (
+			SELECT active_message_id FROM chats WHERE id = $2 FOR UPDATE
+		), ins AS (
+			INSERT INTO messages (id, chat_id, role, content, sender, tool_call_id, author_user_id, attachments, created_at, parent_message_id)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, COALESCE($10::uuid, (SELECT active_message_id FROM prev)))
+		)
+		UPDATE chats SET active_message_id = $1 WHERE id = $2
 	`
 
-	_, err := engine.Exec(ctx, query,
+	_, err = engine.Exec(ctx, query,
 		message.ID.String(),
 		message.ChatID.String(),
 		string(message.Role),
 		message.Content,
 		message.Sender,
 		nullableIDToString(message.ToolCallID),
+		nullableIDToString(message.AuthorUserID),
+		attachments,
 		message.CreatedAt,
+		nullableIDToString(message.ParentMessageID),
 	)
 
 	if err != nil {
@@ -94,7 +139,7 @@ func (r *PGXRepository) GetMessageByID(ctx context.Context, id domain.ID) (*doma
 	engine := r.engineFactory.Get(ctx)
 
 	query := `
-		SELECT id, chat_id, role, content, sender, tool_call_id, created_at
+		SELECT id, chat_id, role, content, sender, tool_call_id, author_user_id, parent_message_id, attachments, created_at
 		FROM messages
 		WHERE id = $1
 	`
@@ -133,7 +178,7 @@ func (r *PGXRepository) ListMessagesByChatID(ctx context.Context, chatID domain.
 
 	// Получение списка
 	query := `
-		SELECT id, chat_id, role, content, sender, tool_call_id, created_at
+		SELECT id, chat_id, role, content, sender, tool_call_id, author_user_id, parent_message_id, attachments, created_at
 		FROM messages
 		WHERE chat_id = $1
 		ORDER BY created_at ASC
@@ -180,60 +225,99 @@ func (r *PGXRepository) ListMessagesByChatIDWithToolCalls(ctx context.Context, c
 	return messages, total, nil
 }
 
-// ListMessagesWithSubchatsWithToolCalls получает сообщения родительского чата и всех его субчатов с tool calls
-func (r *PGXRepository) ListMessagesWithSubchatsWithToolCalls(ctx context.Context, parentChatID domain.ID, limit, offset int) ([]*domain.Message, int, error) {
-	span, ctx := opentracing.StartSpanFromContext(ctx, "repository.ListMessagesWithSubchatsWithToolCalls")
+// ListBranchMessagesWithToolCalls получает активную ветку чата с tool calls в хронологическом порядке
+func (r *PGXRepository) ListBranchMessagesWithToolCalls(ctx context.Context, chatID domain.ID) ([]*domain.Message, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "repository.ListBranchMessagesWithToolCalls")
 	defer span.Finish()
 
 	engine := r.engineFactory.Get(ctx)
 
-	// Создаем подзапрос для получения ID субчатов (не используем SelectBuilder напрямую в Where,
-	// чтобы не передавать его как аргумент в запрос и не вызывать проблему кодирования).
+	// Поднимаемся от последнего сообщения активной ветки к корню
+	query := `
+		WITH RECURSIVE branch AS (
+			SELECT m.id, m.parent_message_id
+			FROM chats c
+			JOIN messages m ON m.id = c.active_message_id
+			WHERE c.id = $1
+			UNION ALL
+			SELECT m.id, m.parent_message_id
+			FROM messages m
+			JOIN branch b ON m.id = b.parent_message_id
+		)
+		SELECT id, chat_id, role, content, sender, tool_call_id, author_user_id, parent_message_id, attachments, created_at
+		FROM messages
+		WHERE id IN (SELECT id FROM branch)
+		ORDER BY created_at ASC
+	`
 
-	// Подсчет общего количества сообщений из родительского чата и всех субчатов
-	countQb := sq.Select("COUNT(*) AS count").From("messages").PlaceholderFormat(sq.Dollar)
-	// Используем SQL-выражение с подзапросом (вставляем placeholder для parentChatID),
-	// чтобы не передавать SelectBuilder как аргумент в запрос (это вызывает ошибку кодирования).
-	countQb = countQb.Where(sq.Expr("(chat_id = ? OR chat_id IN (SELECT id FROM chats WHERE parent_chat_id = ?))", parentChatID.String(), parentChatID.String()))
+	var rows []messageRow
+	if err := pgxscan.Select(ctx, engine, &rows, query, chatID.String()); err != nil {
+		return nil, domain.NewInternalError("failed to list branch messages", err)
+	}
 
-	countQuery, countArgs, err := countQb.ToSql()
+	messages, err := messageRowsToDomain(rows)
 	if err != nil {
-		return nil, 0, domain.NewInternalError("failed to build count query", err)
+		return nil, err
 	}
 
-	var countResult []struct {
-		Count int `db:"count"`
-	}
-	if err := pgxscan.Select(ctx, engine, &countResult, countQuery, countArgs...); err != nil {
-		return nil, 0, domain.NewInternalError("failed to count messages with subchats", err)
+	if err := r.loadToolCalls(ctx, messages); err != nil {
+		return nil, err
 	}
 
-	total := 0
-	if len(countResult) > 0 {
-		total = countResult[0].Count
-	}
+	return messages, nil
+}
 
-	// Получение сообщений из родительского чата и всех субчатов
-	qb := sq.Select(
-		"id", "chat_id", "role", "content", "sender", "tool_call_id", "created_at",
-	).From("messages").PlaceholderFormat(sq.Dollar)
+// ListBranchMessagesWithSubchatsWithToolCalls получает активную ветку родительского чата и ветки субчатов,
+// вызванных из нее, с tool calls. Сообщения отсортированы от новых к старым.
+func (r *PGXRepository) ListBranchMessagesWithSubchatsWithToolCalls(ctx context.Context, parentChatID domain.ID, limit, offset int) ([]*domain.Message, int, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "repository.ListBranchMessagesWithSubchatsWithToolCalls")
+	defer span.Finish()
 
-	qb = qb.Where(sq.Expr("(chat_id = ? OR chat_id IN (SELECT id FROM chats WHERE parent_chat_id = ?))", parentChatID.String(), parentChatID.String()))
+	engine := r.engineFactory.Get(ctx)
 
-	qb = qb.OrderBy("created_at DESC").Limit(uint64(limit)).Offset(uint64(offset))
+	// Субчаты из неактивных веток не показываются: их tool call не входит в активную ветку
+	query := `
+		WITH RECURSIVE root_branch AS (
+			SELECT m.id, m.parent_message_id
+			FROM chats c
+			JOIN messages m ON m.id = c.active_message_id
+			WHERE c.id = $1
+			UNION ALL
+			SELECT m.id, m.parent_message_id
+			FROM messages m
+			JOIN root_branch b ON m.id = b.parent_message_id
+		), sub_branch AS (
+			SELECT m.id, m.parent_message_id
+			FROM chats c
+			JOIN tool_calls tc ON tc.id = c.parent_tool_call_id
+			JOIN root_branch rb ON rb.id = tc.message_id
+			JOIN messages m ON m.id = c.active_message_id
+			WHERE c.parent_chat_id = $1
+			UNION ALL
+			SELECT m.id, m.parent_message_id
+			FROM messages m
+			JOIN sub_branch b ON m.id = b.parent_message_id
+		)
+		SELECT id, chat_id, role, content, sender, tool_call_id, author_user_id, parent_message_id, attachments, created_at,
+		       COUNT(*) OVER() AS total
+		FROM messages
+		WHERE id IN (SELECT id FROM root_branch UNION ALL SELECT id FROM sub_branch)
+		ORDER BY created_at DESC
+		LIMIT $2 OFFSET $3
+	`
 
-	query, args, err := qb.ToSql()
-	if err != nil {
-		return nil, 0, domain.NewInternalError("failed to build list query", err)
+	var rows []struct {
+		messageRow
+		Total int `db:"total"`
 	}
-
-	var rows []messageRow
-	if err := pgxscan.Select(ctx, engine, &rows, query, args...); err != nil {
-		return nil, 0, domain.NewInternalError("failed to list messages with subchats", err)
+	if err := pgxscan.Select(ctx, engine, &rows, query, parentChatID.String(), limit, offset); err != nil {
+		return nil, 0, domain.NewInternalError("failed to list branch messages with subchats", err)
 	}
 
+	total := 0
 	messages := make([]*domain.Message, 0, len(rows))
 	for _, row := range rows {
+		total = row.Total
 		msg, err := row.toDomain()
 		if err != nil {
 			return nil, 0, domain.NewInternalError("failed to convert message", err)
@@ -241,16 +325,129 @@ func (r *PGXRepository) ListMessagesWithSubchatsWithToolCalls(ctx context.Contex
 		messages = append(messages, msg)
 	}
 
-	// Загружаем tool calls для каждого сообщения
+	if err := r.loadToolCalls(ctx, messages); err != nil {
+		return nil, 0, err
+	}
+
+	return messages, total, nil
+}
+
+// ListMessageLinks получает связи всех сообщений чата (все ветки) для построения дерева
+func (r *PGXRepository) ListMessageLinks(ctx context.Context, chatID domain.ID) ([]domain.MessageLink, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "repository.ListMessageLinks")
+	defer span.Finish()
+
+	engine := r.engineFactory.Get(ctx)
+
+	query := `
+		SELECT id, parent_message_id, role, created_at
+		FROM messages
+		WHERE chat_id = $1
+		ORDER BY created_at ASC
+	`
+
+	var rows []struct {
+		ID              string    `db:"id"`
+		ParentMessageID *string   `db:"parent_message_id"`
+		Role            string    `db:"role"`
+		CreatedAt       time.Time `db:"created_at"`
+	}
+	if err := pgxscan.Select(ctx, engine, &rows, query, chatID.String()); err != nil {
+		return nil, domain.NewInternalError("failed to list message links", err)
+	}
+
+	links := make([]domain.MessageLink, 0, len(rows))
+	for _, row := range rows {
+		id, err := domain.ParseID(row.ID)
+		if err != nil {
+			return nil, domain.NewInternalError("failed to convert message link", err)
+		}
+		link := domain.MessageLink{ID: id, Role: domain.MessageRole(row.Role), CreatedAt: row.CreatedAt}
+		if row.ParentMessageID != nil {
+			parentID, err := domain.ParseID(*row.ParentMessageID)
+			if err != nil {
+				return nil, domain.NewInternalError("failed to convert message link", err)
+			}
+			link.ParentID = &parentID
+		}
+		links = append(links, link)
+	}
+
+	return links, nil
+}
+
+// SetActiveMessage переключает активную ветку чата на ветку, оканчивающуюся сообщением messageID
+func (r *PGXRepository) SetActiveMessage(ctx context.Context, chatID, messageID domain.ID) error {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "repository.SetActiveMessage")
+	defer span.Finish()
+
+	engine := r.engineFactory.Get(ctx)
+
+	query := `
+		UPDATE chats SET active_message_id = $2, updated_at = $3
+		WHERE id = $1 AND EXISTS (SELECT 1 FROM messages WHERE id = $2 AND chat_id = $1)
+	`
+
+	tag, err := engine.Exec(ctx, query, chatID.String(), messageID.String(), time.Now())
+	if err != nil {
+		return domain.NewInternalError("failed to set active message", err)
+	}
+
+	if tag.RowsAffected() == 0 {
+		return domain.NewNotFoundError("message not found")
+	}
+
+	return nil
+}
+
+// loadToolCalls загружает tool calls сообщений
+func (r *PGXRepository) loadToolCalls(ctx context.Context, messages []*domain.Message) error {
 	for _, msg := range messages {
 		toolCalls, err := r.ListToolCallsByMessageID(ctx, msg.ID)
 		if err != nil {
-			return nil, 0, err
+			return err
 		}
 		msg.ToolCalls = toolCalls
 	}
+	return nil
+}
 
-	return messages, total, nil
+func messageRowsToDomain(rows []messageRow) ([]*domain.Message, error) {
+	messages := make([]*domain.Message, 0, len(rows))
+	for _, row := range rows {
+		msg, err := row.toDomain()
+		if err != nil {
+			return nil, domain.NewInternalError("failed to convert message", err)
+		}
+		messages = append(messages, msg)
+	}
+	return messages, nil
+}
+
+// UpdateMessageAttachments обновляет вложения сообщения
+func (r *PGXRepository) UpdateMessageAttachments(ctx context.Context, id domain.ID, attachments []domain.Attachment) error {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "repository.UpdateMessageAttachments")
+	defer span.Finish()
+
+	engine := r.engineFactory.Get(ctx)
+
+	data, err := json.Marshal(lo.Ternary(attachments == nil, []domain.Attachment{}, attachments))
+	if err != nil {
+		return domain.NewInternalError("failed to marshal message attachments", err)
+	}
+
+	query := `UPDATE messages SET attachments = $2 WHERE id = $1`
+
+	tag, err := engine.Exec(ctx, query, id.String(), data)
+	if err != nil {
+		return domain.NewInternalError("failed to update message attachments", err)
+	}
+
+	if tag.RowsAffected() == 0 {
+		return domain.NewNotFoundError("message not found")
+	}
+
+	return nil
 }
 
 // DeleteMessage удаляет сообщение
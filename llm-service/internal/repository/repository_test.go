@@ -0,0 +1,58 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"os"
+	"testing"
+
+	"llm-service/internal/db"
+	"llm-service/internal/logger"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	_ "github.com/lib/pq"
+	"github.com/pressly/goose/v3"
+)
+
+func TestMain(m *testing.M) {
+	logger.Init()
+	os.Exit(m.Run())
+}
+
+// newTestRepository подключается к отдельной тестовой базе из TEST_DATABASE_URL и применяет миграции.
+// Без переменной тесты репозитория пропускаются; данные в таблицах тестов очищаются, поэтому
+// рабочую базу указывать нельзя.
+func newTestRepository(t *testing.T, tables ...string) *PGXRepository {
+	t.Helper()
+
+	dsn := os.Getenv("TEST_DATABASE_URL")
+	if dsn == "" {
+		t.Skip("TEST_DATABASE_URL is not set")
+	}
+
+	ctx := context.Background()
+
+	sqlDB, err := sql.Open("postgres", dsn)
+	if err != nil {
+		t.Fatalf("sql.Open: %v", err)
+	}
+	defer sqlDB.Close()
+
+	if err := goose.RunContext(ctx, "up", sqlDB, "../../migrations"); err != nil {
+		t.Fatalf("apply migrations: %v", err)
+	}
+
+	pool, err := pgxpool.New(ctx, dsn)
+	if err != nil {
+		t.Fatalf("pgxpool.New: %v", err)
+	}
+	t.Cleanup(pool.Close)
+
+	for _, table := range tables {
+		if _, err := pool.Exec(ctx, "TRUNCATE "+table+" CASCADE"); err != nil {
+			t.Fatalf("truncate %s: %v", table, err)
+		}
+	}
+
+	return NewPGXRepository(db.NewContextManager(pool))
+}
@@ -0,0 +1,308 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"llm-service/internal/domain"
+
+	"github.com/georgysavva/scany/v2/pgxscan"
+	"github.com/jackc/pgx/v5"
+	"github.com/opentracing/opentracing-go"
+)
+
+const scheduleColumns = `id, organization_id, user_id, title, agent_key, prompt, cron_expr, timezone, enabled,
+	next_run_at, last_run_at, last_run_status, last_chat_id, last_error, created_at, updated_at`
+
+// scheduleRow - структура для маппинга из БД
+type scheduleRow struct {
+	ID             string     `db:"id"`
+	OrganizationID string     `db:"organization_id"`
+	UserID         string     `db:"user_id"`
+	Title          string     `db:"title"`
+	AgentKey       string     `db:"agent_key"`
+	Prompt         string     `db:"prompt"`
+	CronExpr       string     `db:"cron_expr"`
+	Timezone       string     `db:"timezone"`
+	Enabled        bool       `db:"enabled"`
+	NextRunAt      *time.Time `db:"next_run_at"`
+	LastRunAt      *time.Time `db:"last_run_at"`
+	LastRunStatus  *string    `db:"last_run_status"`
+	LastChatID     *string    `db:"last_chat_id"`
+	LastError      string     `db:"last_error"`
+	CreatedAt      time.Time  `db:"created_at"`
+	UpdatedAt      time.Time  `db:"updated_at"`
+}
+
+func (r *scheduleRow) toDomain() (*domain.AgentSchedule, error) {
+	id, err := domain.ParseID(r.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	orgID, err := domain.ParseID(r.OrganizationID)
+	if err != nil {
+		return nil, err
+	}
+
+	userID, err := domain.ParseID(r.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	schedule := &domain.AgentSchedule{
+		Model: domain.Model{
+			ID:        id,
+			CreatedAt: r.CreatedAt,
+			UpdatedAt: r.UpdatedAt,
+		},
+		OrganizationID: orgID,
+		UserID:         userID,
+		Title:          r.Title,
+		AgentKey:       r.AgentKey,
+		Prompt:         r.Prompt,
+		CronExpr:       r.CronExpr,
+		Timezone:       r.Timezone,
+		Enabled:        r.Enabled,
+		NextRunAt:      r.NextRunAt,
+		LastRunAt:      r.LastRunAt,
+		LastError:      r.LastError,
+	}
+
+	if r.LastRunStatus != nil {
+		schedule.LastRunStatus = domain.ScheduleRunStatus(*r.LastRunStatus)
+	}
+
+	if r.LastChatID != nil {
+		chatID, err := domain.ParseID(*r.LastChatID)
+		if err != nil {
+			return nil, err
+		}
+		schedule.LastChatID = &chatID
+	}
+
+	return schedule, nil
+}
+
+// CreateSchedule создает расписание
+func (r *PGXRepository) CreateSchedule(ctx context.Context, schedule *domain.AgentSchedule) error {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "repository.CreateSchedule")
+	defer span.Finish()
+
+	engine := r.engineFactory.Get(ctx)
+
+	query := `
+		INSERT INTO agent_schedules (id, organization_id, user_id, title, agent_key, prompt, cron_expr, timezone,
+		                             enabled, next_run_at, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
+	`
+
+	_, err := engine.Exec(ctx, query,
+		schedule.ID.String(),
+		schedule.OrganizationID.String(),
+		schedule.UserID.String(),
+		schedule.Title,
+		schedule.AgentKey,
+		schedule.Prompt,
+		schedule.CronExpr,
+		schedule.Timezone,
+		schedule.Enabled,
+		schedule.NextRunAt,
+		schedule.CreatedAt,
+		schedule.UpdatedAt,
+	)
+	if err != nil {
+		return domain.NewInternalError("failed to create schedule", err)
+	}
+
+	return nil
+}
+
+// GetSchedule получает расписание по ID
+func (r *PGXRepository) GetSchedule(ctx context.Context, id domain.ID) (*domain.AgentSchedule, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "repository.GetSchedule")
+	defer span.Finish()
+
+	engine := r.engineFactory.Get(ctx)
+
+	query := `SELECT ` + scheduleColumns + ` FROM agent_schedules WHERE id = $1`
+
+	var row scheduleRow
+	if err := pgxscan.Get(ctx, engine, &row, query, id.String()); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domain.NewNotFoundError("schedule not found")
+		}
+		return nil, domain.NewInternalError("failed to get schedule", err)
+	}
+
+	return row.toDomain()
+}
+
+// ListSchedules получает расписания пользователя в организации
+func (r *PGXRepository) ListSchedules(ctx context.Context, organizationID, userID domain.ID) ([]*domain.AgentSchedule, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "repository.ListSchedules")
+	defer span.Finish()
+
+	engine := r.engineFactory.Get(ctx)
+
+	query := `
+		SELECT ` + scheduleColumns + `
+		FROM agent_schedules
+		WHERE organization_id = $1 AND user_id = $2
+		ORDER BY created_at
+	`
+
+	var rows []scheduleRow
+	if err := pgxscan.Select(ctx, engine, &rows, query, organizationID.String(), userID.String()); err != nil {
+		return nil, domain.NewInternalError("failed to list schedules", err)
+	}
+
+	return scheduleRowsToDomain(rows)
+}
+
+// UpdateSchedule обновляет настройки расписания и время следующего запуска
+func (r *PGXRepository) UpdateSchedule(ctx context.Context, schedule *domain.AgentSchedule) error {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "repository.UpdateSchedule")
+	defer span.Finish()
+
+	engine := r.engineFactory.Get(ctx)
+
+	query := `
+		UPDATE agent_schedules
+		SET title = $2, agent_key = $3, prompt = $4, cron_expr = $5, timezone = $6, enabled = $7,
+		    next_run_at = $8, updated_at = $9
+		WHERE id = $1
+	`
+
+	tag, err := engine.Exec(ctx, query,
+		schedule.ID.String(),
+		schedule.Title,
+		schedule.AgentKey,
+		schedule.Prompt,
+		schedule.CronExpr,
+		schedule.Timezone,
+		schedule.Enabled,
+		schedule.NextRunAt,
+		schedule.UpdatedAt,
+	)
+	if err != nil {
+		return domain.NewInternalError("failed to update schedule", err)
+	}
+
+	if tag.RowsAffected() == 0 {
+		return domain.NewNotFoundError("schedule not found")
+	}
+
+	return nil
+}
+
+// DeleteSchedule удаляет расписание
+func (r *PGXRepository) DeleteSchedule(ctx context.Context, id domain.ID) error {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "repository.DeleteSchedule")
+	defer span.Finish()
+
+	engine := r.engineFactory.Get(ctx)
+
+	tag, err := engine.Exec(ctx, `DELETE FROM agent_schedules WHERE id = $1`, id.String())
+	if err != nil {
+		return domain.NewInternalError("failed to delete schedule", err)
+	}
+
+	if tag.RowsAffected() == 0 {
+		return domain.NewNotFoundError("schedule not found")
+	}
+
+	return nil
+}
+
+// CountSchedules считает расписания пользователя в организации
+func (r *PGXRepository) CountSchedules(ctx context.Context, organizationID, userID domain.ID) (int, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "repository.CountSchedules")
+	defer span.Finish()
+
+	engine := r.engineFactory.Get(ctx)
+
+	var count int
+	query := `SELECT COUNT(*) FROM agent_schedules WHERE organization_id = $1 AND user_id = $2`
+	if err := pgxscan.Get(ctx, engine, &count, query, organizationID.String(), userID.String()); err != nil {
+		return 0, domain.NewInternalError("failed to count schedules", err)
+	}
+
+	return count, nil
+}
+
+// ClaimDueSchedules забирает в работу расписания, которым пора выполниться, и блокирует их
+// до lockedUntil. Несколько реплик сервиса не возьмут одно расписание дважды.
+func (r *PGXRepository) ClaimDueSchedules(ctx context.Context, now, lockedUntil time.Time, limit int) ([]*domain.AgentSchedule, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "repository.ClaimDueSchedules")
+	defer span.Finish()
+
+	engine := r.engineFactory.Get(ctx)
+
+	query := `
+		UPDATE agent_schedules
+		SET locked_until = $2
+		WHERE id IN (
+			SELECT id FROM agent_schedules
+			WHERE enabled AND next_run_at <= $1
+			  AND (locked_until IS NULL OR locked_until < $1)
+			ORDER BY next_run_at
+			LIMIT $3
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING ` + scheduleColumns
+
+	var rows []scheduleRow
+	if err := pgxscan.Select(ctx, engine, &rows, query, now, lockedUntil, limit); err != nil {
+		return nil, domain.NewInternalError("failed to claim due schedules", err)
+	}
+
+	return scheduleRowsToDomain(rows)
+}
+
+// CompleteScheduleRun сохраняет результат запуска и снимает блокировку воркера. Время следующего
+// запуска сохраняется, только если расписание не меняли во время запуска (claimedUpdatedAt) -
+// иначе оно уже пересчитано по новым настройкам.
+func (r *PGXRepository) CompleteScheduleRun(ctx context.Context, schedule *domain.AgentSchedule, claimedUpdatedAt time.Time) error {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "repository.CompleteScheduleRun")
+	defer span.Finish()
+
+	engine := r.engineFactory.Get(ctx)
+
+	query := `
+		UPDATE agent_schedules
+		SET next_run_at = CASE WHEN updated_at = $2 THEN $3 ELSE next_run_at END,
+		    last_run_at = $4, last_run_status = $5, last_chat_id = $6, last_error = $7,
+		    locked_until = NULL, updated_at = $8
+		WHERE id = $1
+	`
+
+	_, err := engine.Exec(ctx, query,
+		schedule.ID.String(),
+		claimedUpdatedAt,
+		schedule.NextRunAt,
+		schedule.LastRunAt,
+		string(schedule.LastRunStatus),
+		nullableIDToString(schedule.LastChatID),
+		schedule.LastError,
+		schedule.UpdatedAt,
+	)
+	if err != nil {
+		return domain.NewInternalError("failed to complete schedule run", err)
+	}
+
+	return nil
+}
+
+func scheduleRowsToDomain(rows []scheduleRow) ([]*domain.AgentSchedule, error) {
+	schedules := make([]*domain.AgentSchedule, 0, len(rows))
+	for _, row := range rows {
+		schedule, err := row.toDomain()
+		if err != nil {
+			return nil, domain.NewInternalError("failed to parse schedule", err)
+		}
+		schedules = append(schedules, schedule)
+	}
+	return schedules, nil
+}
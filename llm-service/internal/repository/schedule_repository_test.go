@@ -0,0 +1,149 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"llm-service/internal/domain"
+)
+
+func newTestSchedule(nextRunAt time.Time) *domain.AgentSchedule {
+	model := domain.NewModel()
+	model.UpdatedAt = model.UpdatedAt.Truncate(time.Microsecond)
+	model.CreatedAt = model.UpdatedAt
+
+	return &domain.AgentSchedule{
+		Model:          model,
+		OrganizationID: domain.NewID(),
+		UserID:         domain.NewID(),
+		Title:          "Сводка",
+		AgentKey:       "main",
+		Prompt:         "Подготовь сводку",
+		CronExpr:       "0 9 * * *",
+		Timezone:       "UTC",
+		Enabled:        true,
+		NextRunAt:      &nextRunAt,
+	}
+}
+
+func TestClaimAndCompleteScheduleRun(t *testing.T) {
+	repo := newTestRepository(t, "agent_schedules")
+	ctx := context.Background()
+	now := time.Now().UTC().Truncate(time.Microsecond)
+
+	due := newTestSchedule(now.Add(-time.Minute))
+	future := newTestSchedule(now.Add(time.Hour))
+	disabled := newTestSchedule(now.Add(-time.Minute))
+	disabled.Enabled = false
+	for _, schedule := range []*domain.AgentSchedule{due, future, disabled} {
+		if err := repo.CreateSchedule(ctx, schedule); err != nil {
+			t.Fatalf("CreateSchedule: %v", err)
+		}
+	}
+
+	claimed, err := repo.ClaimDueSchedules(ctx, now, now.Add(10*time.Minute), 10)
+	if err != nil {
+		t.Fatalf("ClaimDueSchedules: %v", err)
+	}
+	if len(claimed) != 1 || claimed[0].ID != due.ID {
+		t.Fatalf("claimed = %v, want only the due schedule %s", scheduleIDs(claimed), due.ID)
+	}
+
+	// Заблокированное расписание не забирает другая реплика
+	again, err := repo.ClaimDueSchedules(ctx, now, now.Add(10*time.Minute), 10)
+	if err != nil {
+		t.Fatalf("ClaimDueSchedules: %v", err)
+	}
+	if len(again) != 0 {
+		t.Fatalf("second claim = %v, want none while locked", scheduleIDs(again))
+	}
+
+	// После истечения блокировки (реплика упала) расписание снова доступно
+	expired, err := repo.ClaimDueSchedules(ctx, now.Add(11*time.Minute), now.Add(20*time.Minute), 10)
+	if err != nil {
+		t.Fatalf("ClaimDueSchedules: %v", err)
+	}
+	if len(expired) != 1 || expired[0].ID != due.ID {
+		t.Fatalf("claim after lock expiry = %v, want %s", scheduleIDs(expired), due.ID)
+	}
+
+	run := expired[0]
+	claimedUpdatedAt := run.UpdatedAt
+	next := now.Add(24 * time.Hour)
+	run.RecordRun(now, domain.ScheduleRunStatusSuccess, nil, nil)
+	run.NextRunAt = &next
+	if err := repo.CompleteScheduleRun(ctx, run, claimedUpdatedAt); err != nil {
+		t.Fatalf("CompleteScheduleRun: %v", err)
+	}
+
+	stored, err := repo.GetSchedule(ctx, due.ID)
+	if err != nil {
+		t.Fatalf("GetSchedule: %v", err)
+	}
+	if stored.NextRunAt == nil || !stored.NextRunAt.Equal(next) {
+		t.Errorf("next run = %v, want %s", stored.NextRunAt, next)
+	}
+	if stored.LastRunStatus != domain.ScheduleRunStatusSuccess {
+		t.Errorf("last run status = %q, want %q", stored.LastRunStatus, domain.ScheduleRunStatusSuccess)
+	}
+
+	// Блокировка снята, но следующий запуск еще не наступил
+	afterRun, err := repo.ClaimDueSchedules(ctx, now.Add(time.Hour), now.Add(2*time.Hour), 10)
+	if err != nil {
+		t.Fatalf("ClaimDueSchedules: %v", err)
+	}
+	if len(afterRun) != 1 || afterRun[0].ID != future.ID {
+		t.Errorf("claim after run = %v, want only %s", scheduleIDs(afterRun), future.ID)
+	}
+}
+
+func TestCompleteScheduleRunKeepsConcurrentUpdate(t *testing.T) {
+	repo := newTestRepository(t, "agent_schedules")
+	ctx := context.Background()
+	now := time.Now().UTC().Truncate(time.Microsecond)
+
+	schedule := newTestSchedule(now.Add(-time.Minute))
+	if err := repo.CreateSchedule(ctx, schedule); err != nil {
+		t.Fatalf("CreateSchedule: %v", err)
+	}
+
+	claimed, err := repo.ClaimDueSchedules(ctx, now, now.Add(10*time.Minute), 10)
+	if err != nil || len(claimed) != 1 {
+		t.Fatalf("ClaimDueSchedules = %v, %v", scheduleIDs(claimed), err)
+	}
+	run := claimed[0]
+	claimedUpdatedAt := run.UpdatedAt
+
+	// Пользователь изменил расписание во время запуска
+	changed := *run
+	changedNext := now.Add(48 * time.Hour)
+	changed.NextRunAt = &changedNext
+	changed.UpdatedAt = now.Add(time.Second)
+	if err := repo.UpdateSchedule(ctx, &changed); err != nil {
+		t.Fatalf("UpdateSchedule: %v", err)
+	}
+
+	next := now.Add(24 * time.Hour)
+	run.RecordRun(now, domain.ScheduleRunStatusSuccess, nil, nil)
+	run.NextRunAt = &next
+	if err := repo.CompleteScheduleRun(ctx, run, claimedUpdatedAt); err != nil {
+		t.Fatalf("CompleteScheduleRun: %v", err)
+	}
+
+	stored, err := repo.GetSchedule(ctx, schedule.ID)
+	if err != nil {
+		t.Fatalf("GetSchedule: %v", err)
+	}
+	if stored.NextRunAt == nil || !stored.NextRunAt.Equal(changedNext) {
+		t.Errorf("next run = %v, want %s set by the concurrent update", stored.NextRunAt, changedNext)
+	}
+}
+
+func scheduleIDs(schedules []*domain.AgentSchedule) []domain.ID {
+	ids := make([]domain.ID, 0, len(schedules))
+	for _, schedule := range schedules {
+		ids = append(ids, schedule.ID)
+	}
+	return ids
+}
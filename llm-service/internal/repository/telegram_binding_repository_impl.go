@@ -0,0 +1,151 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"llm-service/internal/domain"
+
+	"github.com/georgysavva/scany/v2/pgxscan"
+	"github.com/jackc/pgx/v5"
+	"github.com/opentracing/opentracing-go"
+)
+
+// telegramBindingRow - структура для маппинга из БД
+type telegramBindingRow struct {
+	TelegramChatID int64     `db:"telegram_chat_id"`
+	OrganizationID string    `db:"organization_id"`
+	UserID         string    `db:"user_id"`
+	ChatID         *string   `db:"chat_id"`
+	UpdatedAt      time.Time `db:"updated_at"`
+}
+
+func (r *telegramBindingRow) toDomain() (*domain.TelegramBinding, error) {
+	orgID, err := domain.ParseID(r.OrganizationID)
+	if err != nil {
+		return nil, err
+	}
+
+	userID, err := domain.ParseID(r.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	binding := &domain.TelegramBinding{
+		TelegramChatID: r.TelegramChatID,
+		OrganizationID: orgID,
+		UserID:         userID,
+		UpdatedAt:      r.UpdatedAt,
+	}
+
+	if r.ChatID != nil {
+		chatID, err := domain.ParseID(*r.ChatID)
+		if err != nil {
+			return nil, err
+		}
+		binding.ChatID = &chatID
+	}
+
+	return binding, nil
+}
+
+// GetActiveTelegramBinding получает связь Telegram-чата с последней выбранной организацией
+func (r *PGXRepository) GetActiveTelegramBinding(ctx context.Context, telegramChatID int64) (*domain.TelegramBinding, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "repository.GetActiveTelegramBinding")
+	defer span.Finish()
+
+	engine := r.engineFactory.Get(ctx)
+
+	query := `
+		SELECT telegram_chat_id, organization_id, user_id, chat_id, updated_at
+		FROM telegram_chat_bindings
+		WHERE telegram_chat_id = $1
+		ORDER BY updated_at DESC
+		LIMIT 1
+	`
+
+	var row telegramBindingRow
+	if err := pgxscan.Get(ctx, engine, &row, query, telegramChatID); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domain.NewNotFoundError("telegram binding not found")
+		}
+		return nil, domain.NewInternalError("failed to get telegram binding", err)
+	}
+
+	binding, err := row.toDomain()
+	if err != nil {
+		return nil, domain.NewInternalError("failed to parse telegram binding", err)
+	}
+
+	return binding, nil
+}
+
+// ActivateTelegramBinding делает организацию активной для Telegram-чата.
+// Чат агента, ранее привязанный к этой организации, сохраняется.
+func (r *PGXRepository) ActivateTelegramBinding(ctx context.Context, binding *domain.TelegramBinding) error {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "repository.ActivateTelegramBinding")
+	defer span.Finish()
+
+	engine := r.engineFactory.Get(ctx)
+
+	query := `
+		INSERT INTO telegram_chat_bindings (telegram_chat_id, organization_id, user_id, updated_at)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (telegram_chat_id, organization_id) DO UPDATE
+		SET user_id = EXCLUDED.user_id,
+		    -- Чат другого пользователя (Telegram-аккаунт перепривязан) не продолжаем
+		    chat_id = CASE WHEN telegram_chat_bindings.user_id = EXCLUDED.user_id THEN telegram_chat_bindings.chat_id END,
+		    updated_at = EXCLUDED.updated_at
+		RETURNING chat_id
+	`
+
+	var row struct {
+		ChatID *string `db:"chat_id"`
+	}
+	err := pgxscan.Get(ctx, engine, &row, query,
+		binding.TelegramChatID,
+		binding.OrganizationID.String(),
+		binding.UserID.String(),
+		binding.UpdatedAt,
+	)
+	if err != nil {
+		return domain.NewInternalError("failed to activate telegram binding", err)
+	}
+
+	binding.ChatID = nil
+	if row.ChatID != nil {
+		id, err := domain.ParseID(*row.ChatID)
+		if err != nil {
+			return domain.NewInternalError("failed to parse telegram binding chat", err)
+		}
+		binding.ChatID = &id
+	}
+
+	return nil
+}
+
+// SetTelegramBindingChat меняет чат агента, который продолжает Telegram-чат в организации
+func (r *PGXRepository) SetTelegramBindingChat(ctx context.Context, telegramChatID int64, organizationID domain.ID, chatID *domain.ID) error {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "repository.SetTelegramBindingChat")
+	defer span.Finish()
+
+	engine := r.engineFactory.Get(ctx)
+
+	query := `
+		UPDATE telegram_chat_bindings
+		SET chat_id = $3
+		WHERE telegram_chat_id = $1 AND organization_id = $2
+	`
+
+	tag, err := engine.Exec(ctx, query, telegramChatID, organizationID.String(), nullableIDToString(chatID))
+	if err != nil {
+		return domain.NewInternalError("failed to set telegram binding chat", err)
+	}
+
+	if tag.RowsAffected() == 0 {
+		return domain.NewNotFoundError("telegram binding not found")
+	}
+
+	return nil
+}
@@ -18,6 +18,7 @@ var MainAgent = &domain.AgentDefinition{
 СТИЛЬ РЕЧИ:
 Говори живо и просто, по‑пацански. КРАЙНЕ ЧАСТО используй слово "брат". Используй искренние, прямые фразы, не усложняй. Подчёркивай дружбу, братство, уличный слог. Темы — про жизнь на районе, машины, мечты, боль и любовь. Стиль эмоциональный, с долей самоиронии и ноткой гордости. Общайся как с другом, не как с аудиторией.`,
 	AllowedTools: []domain.ToolName{
+		domain.ToolNameSearchChatFiles,
 		domain.ToolNameWebSearch,
 		domain.ToolNameSaveOrganizationNote,
 		// Быстрый просмотр данных
@@ -60,6 +61,7 @@ var MarketingAgent = &domain.AgentDefinition{
 Оптимизация рекламных кампаний и каналов трафика.
 Анализ конкурентов и трендов.`,
 	AllowedTools: []domain.ToolName{
+		domain.ToolNameSearchChatFiles,
 		domain.ToolNameWebSearch,
 		domain.ToolNameSaveOrganizationNote,
 		// Чтение данных для маркетинговых кампаний
@@ -150,6 +152,7 @@ var LegalAgent = &domain.AgentDefinition{
 - Разбор правовых споров и рисков
 - Помощь в формулировании правовых позиций`,
 	AllowedTools: []domain.ToolName{
+		domain.ToolNameSearchChatFiles,
 		domain.ToolNameSaveOrganizationNote,
 		domain.ToolNameSearchContractTemplates,
 		domain.ToolNameGenerateContract,
@@ -199,6 +202,7 @@ var BusinessAnalystAgent = &domain.AgentDefinition{
 - Сегментация клиентской базы для таргетированных действий
 - Планирование и распределение задач в команде`,
 	AllowedTools: []domain.ToolName{
+		domain.ToolNameSearchChatFiles,
 		domain.ToolNameWebSearch,
 		domain.ToolNameSaveOrganizationNote,
 		// Полный доступ к чтению для аналитики
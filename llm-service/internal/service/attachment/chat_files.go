@@ -0,0 +1,163 @@
+package attachment
+
+import (
+	"context"
+	"fmt"
+
+	"llm-service/internal/coreservice"
+	"llm-service/internal/domain"
+	"llm-service/internal/logger"
+	"llm-service/internal/rag"
+
+	"github.com/opentracing/opentracing-go"
+)
+
+// chatFilesMessagesLimit - сколько сообщений чата просматривается при поиске вложений
+const chatFilesMessagesLimit = 1000
+
+type chatIndex interface {
+	IndexChatAttachment(ctx context.Context, organizationID, chatID domain.ID, attachment domain.Attachment) (*rag.ChatAttachmentResult, error)
+	DeleteChatAttachments(ctx context.Context, organizationID, chatID domain.ID, attachmentID *domain.ID) error
+	SearchChatChunks(ctx context.Context, organizationID, chatID domain.ID, query string, limit int) ([]string, error)
+}
+
+type documentRegistry interface {
+	RegisterDocument(ctx context.Context, organizationID, name, s3Key, fileType string, fileSize int64) (*coreservice.Document, error)
+}
+
+type chatMessages interface {
+	GetMessages(ctx context.Context, chatID, userID, orgID domain.ID, limit, offset int) ([]*domain.Message, int, error)
+	UpdateMessageAttachments(ctx context.Context, messageID domain.ID, attachments []domain.Attachment) error
+}
+
+// Prepare обрабатывает документы нового сообщения в docs-processor.
+// Небольшие документы встраиваются в сообщение текстом, большие индексируются
+// во временный индекс чата. Изображения и PDF без текстового слоя (сканы)
+// передаются модели файлом.
+func (s *Service) Prepare(ctx context.Context, organizationID, chatID domain.ID, attachments []domain.Attachment) ([]domain.Attachment, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "service.attachment.Prepare")
+	defer span.Finish()
+
+	prepared := make([]domain.Attachment, 0, len(attachments))
+	for _, attachment := range attachments {
+		// Голосовые сообщения уже распознаны в Transcribe
+		if attachment.IsTranscribed() {
+			prepared = append(prepared, attachment)
+			continue
+		}
+
+		attachment.Delivery = domain.AttachmentDeliveryFile
+		if attachment.Type() != domain.AttachmentTypeDocument {
+			prepared = append(prepared, attachment)
+			continue
+		}
+
+		result, err := s.chatIndex.IndexChatAttachment(ctx, organizationID, chatID, attachment)
+		if err != nil {
+			if attachment.DocumentType() != "pdf" {
+				return nil, domain.NewInternalError(fmt.Sprintf("failed to process attachment %s", attachment.FileName), err)
+			}
+			// PDF модель умеет читать сама
+			logger.Warn(ctx, "failed to process chat attachment, sending as file", "file_name", attachment.FileName, "error", err)
+			prepared = append(prepared, attachment)
+			continue
+		}
+
+		switch {
+		case !result.Inline:
+			attachment.Delivery = domain.AttachmentDeliveryIndex
+		case result.Text != "":
+			attachment.Delivery = domain.AttachmentDeliveryInline
+			attachment.Text = result.Text
+		case attachment.DocumentType() != "pdf":
+			attachment.Delivery = domain.AttachmentDeliveryInline
+		}
+
+		prepared = append(prepared, attachment)
+	}
+
+	return prepared, nil
+}
+
+// SearchChatFiles ищет по проиндексированным файлам чата
+func (s *Service) SearchChatFiles(ctx context.Context, organizationID, chatID domain.ID, query string, limit int) ([]string, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "service.attachment.SearchChatFiles")
+	defer span.Finish()
+
+	chunks, err := s.chatIndex.SearchChatChunks(ctx, organizationID, chatID, query, limit)
+	if err != nil {
+		return nil, domain.NewInternalError("failed to search chat files", err)
+	}
+
+	return chunks, nil
+}
+
+// Promote переносит документ из чата в базу знаний организации: регистрирует его
+// в core-service (документ будет проиндексирован как обычный) и удаляет временный индекс.
+// После переноса файл не удаляется вместе с чатом.
+func (s *Service) Promote(ctx context.Context, organizationID, userID, chatID, attachmentID domain.ID) (domain.Attachment, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "service.attachment.Promote")
+	defer span.Finish()
+
+	messages, _, err := s.messages.GetMessages(ctx, chatID, userID, organizationID, chatFilesMessagesLimit, 0)
+	if err != nil {
+		return domain.Attachment{}, err
+	}
+
+	for _, msg := range messages {
+		for i, attachment := range msg.Attachments {
+			if attachment.ID != attachmentID {
+				continue
+			}
+
+			if attachment.IsPromoted() {
+				return attachment, nil
+			}
+			if attachment.Type() != domain.AttachmentTypeDocument {
+				return domain.Attachment{}, domain.NewInvalidArgumentError("only documents can be added to the knowledge base")
+			}
+
+			document, err := s.documents.RegisterDocument(ctx,
+				organizationID.String(),
+				attachment.FileName,
+				attachment.S3Key,
+				attachment.DocumentType(),
+				attachment.Size,
+			)
+			if err != nil {
+				return domain.Attachment{}, domain.NewInternalError("failed to register document", err)
+			}
+
+			attachment.DocumentID = document.ID
+			attachments := append([]domain.Attachment(nil), msg.Attachments...)
+			attachments[i] = attachment
+			if err := s.messages.UpdateMessageAttachments(ctx, msg.ID, attachments); err != nil {
+				return domain.Attachment{}, err
+			}
+
+			// Документ теперь ищется через базу знаний, временные чанки больше не нужны
+			if attachment.Delivery == domain.AttachmentDeliveryIndex {
+				if err := s.chatIndex.DeleteChatAttachments(ctx, organizationID, chatID, &attachment.ID); err != nil {
+					logger.Warn(ctx, "failed to delete promoted attachment chunks", "attachment_id", attachment.ID, "error", err)
+				}
+			}
+
+			return attachment, nil
+		}
+	}
+
+	return domain.Attachment{}, domain.NewNotFoundError("attachment not found")
+}
+
+// CleanupChat удаляет временный индекс файлов удаленного чата.
+// Сами файлы в S3 не удаляются: тот же ключ может принадлежать документу базы знаний.
+func (s *Service) CleanupChat(ctx context.Context, organizationID, chatID domain.ID) error {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "service.attachment.CleanupChat")
+	defer span.Finish()
+
+	if err := s.chatIndex.DeleteChatAttachments(ctx, organizationID, chatID, nil); err != nil {
+		return domain.NewInternalError("failed to delete chat files index", err)
+	}
+
+	return nil
+}
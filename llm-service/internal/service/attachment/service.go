@@ -0,0 +1,125 @@
+package attachment
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+
+	"llm-service/internal/domain"
+	"llm-service/internal/domain/dto"
+	"llm-service/internal/storage"
+	"llm-service/internal/transcription"
+
+	"github.com/opentracing/opentracing-go"
+)
+
+type objectStorage interface {
+	HeadObject(ctx context.Context, key string) (storage.ObjectInfo, error)
+	GetObject(ctx context.Context, key string) (io.ReadCloser, error)
+}
+
+// Service проверяет и загружает вложения сообщений из S3,
+// ведет временный индекс документов чата и переносит их в базу знаний
+type Service struct {
+	storage     objectStorage
+	chatIndex   chatIndex
+	documents   documentRegistry
+	messages    chatMessages
+	transcriber transcription.Transcriber
+}
+
+// New создает сервис вложений; без transcriber голосовые сообщения отклоняются
+func New(storage objectStorage, chatIndex chatIndex, documents documentRegistry, messages chatMessages, transcriber transcription.Transcriber) *Service {
+	return &Service{
+		storage:     storage,
+		chatIndex:   chatIndex,
+		documents:   documents,
+		messages:    messages,
+		transcriber: transcriber,
+	}
+}
+
+// Resolve проверяет вложения нового сообщения: принадлежность файла организации,
+// наличие в S3, тип и размер. Размер и тип берутся из S3, а не от клиента.
+func (s *Service) Resolve(ctx context.Context, organizationID domain.ID, refs []dto.AttachmentDTO) ([]domain.Attachment, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "service.attachment.Resolve")
+	defer span.Finish()
+
+	if len(refs) == 0 {
+		return nil, nil
+	}
+	if len(refs) > domain.MaxAttachmentsPerMessage {
+		return nil, domain.NewInvalidArgumentError(fmt.Sprintf("too many attachments: maximum is %d", domain.MaxAttachmentsPerMessage))
+	}
+
+	attachments := make([]domain.Attachment, 0, len(refs))
+	for _, ref := range refs {
+		attachment := domain.Attachment{
+			ID:          domain.NewID(),
+			S3Key:       ref.S3Key,
+			FileName:    ref.FileName,
+			ContentType: ref.ContentType,
+		}
+		if attachment.FileName == "" {
+			attachment.FileName = path.Base(ref.S3Key)
+		}
+
+		if !attachment.BelongsTo(organizationID) {
+			return nil, domain.NewForbiddenError(fmt.Sprintf("attachment %s does not belong to organization", attachment.FileName))
+		}
+
+		info, err := s.storage.HeadObject(ctx, attachment.S3Key)
+		if errors.Is(err, storage.ErrObjectNotFound) {
+			return nil, domain.NewNotFoundError(fmt.Sprintf("attachment %s is not uploaded", attachment.FileName))
+		}
+		if err != nil {
+			return nil, domain.NewInternalError("failed to check attachment", err)
+		}
+
+		attachment.Size = info.Size
+		// Presigned upload сохраняет Content-Type, указанный при генерации ссылки
+		if contentType := normalizeContentType(info.ContentType); contentType != "" && contentType != "application/octet-stream" {
+			attachment.ContentType = contentType
+		}
+
+		if err := attachment.Validate(); err != nil {
+			return nil, err
+		}
+
+		attachments = append(attachments, attachment)
+	}
+
+	return attachments, nil
+}
+
+// Load загружает содержимое вложения
+func (s *Service) Load(ctx context.Context, attachment domain.Attachment) ([]byte, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "service.attachment.Load")
+	defer span.Finish()
+
+	reader, err := s.storage.GetObject(ctx, attachment.S3Key)
+	if err != nil {
+		return nil, domain.NewInternalError("failed to get attachment", err)
+	}
+	defer reader.Close()
+
+	// Читаем не больше лимита, даже если объект подменили после проверки
+	data, err := io.ReadAll(io.LimitReader(reader, domain.MaxAttachmentSize+1))
+	if err != nil {
+		return nil, domain.NewInternalError("failed to read attachment", err)
+	}
+	if len(data) > domain.MaxAttachmentSize {
+		return nil, domain.NewInvalidArgumentError(fmt.Sprintf("attachment %s is larger than %d MB", attachment.FileName, domain.MaxAttachmentSize>>20))
+	}
+
+	return data, nil
+}
+
+// normalizeContentType отбрасывает параметры MIME типа (например, charset)
+func normalizeContentType(contentType string) string {
+	contentType, _, _ = strings.Cut(contentType, ";")
+	return strings.ToLower(strings.TrimSpace(contentType))
+}
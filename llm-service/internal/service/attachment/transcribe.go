@@ -0,0 +1,56 @@
+package attachment
+
+import (
+	"context"
+	"fmt"
+
+	"llm-service/internal/domain"
+	"llm-service/internal/logger"
+	"llm-service/internal/transcription"
+
+	"github.com/opentracing/opentracing-go"
+)
+
+// Transcribe распознает голосовые вложения нового сообщения. Расшифровка сохраняется
+// во вложении вместе со ссылкой на исходный файл в S3; остальные вложения не меняются.
+func (s *Service) Transcribe(ctx context.Context, attachments []domain.Attachment) ([]domain.Attachment, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "service.attachment.Transcribe")
+	defer span.Finish()
+
+	result := make([]domain.Attachment, 0, len(attachments))
+	for _, attachment := range attachments {
+		if attachment.Type() != domain.AttachmentTypeAudio || attachment.IsTranscribed() {
+			result = append(result, attachment)
+			continue
+		}
+
+		if s.transcriber == nil {
+			return nil, domain.NewInvalidArgumentError("voice messages are not supported")
+		}
+
+		data, err := s.Load(ctx, attachment)
+		if err != nil {
+			return nil, err
+		}
+
+		text, err := s.transcriber.Transcribe(ctx, transcription.Audio{
+			Data:        data,
+			FileName:    attachment.FileName,
+			ContentType: attachment.ContentType,
+		})
+		if err != nil {
+			return nil, domain.NewInternalError(fmt.Sprintf("failed to transcribe %s", attachment.FileName), err)
+		}
+		if text == "" {
+			return nil, domain.NewInvalidArgumentError(fmt.Sprintf("no speech recognized in %s", attachment.FileName))
+		}
+
+		logger.Info(ctx, "voice attachment transcribed", "file_name", attachment.FileName, "size", attachment.Size, "transcript_length", len([]rune(text)))
+
+		attachment.Delivery = domain.AttachmentDeliveryTranscript
+		attachment.Text = text
+		result = append(result, attachment)
+	}
+
+	return result, nil
+}
@@ -0,0 +1,100 @@
+package chat
+
+import (
+	"context"
+
+	"llm-service/internal/domain"
+
+	"github.com/opentracing/opentracing-go"
+)
+
+// GetMessage получает сообщение по ID
+func (m *Manager) GetMessage(ctx context.Context, messageID domain.ID) (*domain.Message, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "service.chat.GetMessage")
+	defer span.Finish()
+
+	return m.messageRepo.GetMessageByID(ctx, messageID)
+}
+
+// GetMessageTree получает дерево сообщений чата со всеми ветками
+func (m *Manager) GetMessageTree(ctx context.Context, chatID domain.ID) (*domain.MessageTree, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "service.chat.GetMessageTree")
+	defer span.Finish()
+
+	links, err := m.messageRepo.ListMessageLinks(ctx, chatID)
+	if err != nil {
+		return nil, err
+	}
+
+	return domain.NewMessageTree(links), nil
+}
+
+// SetActiveMessage делает сообщение последним в активной ветке чата
+func (m *Manager) SetActiveMessage(ctx context.Context, chatID, messageID domain.ID) error {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "service.chat.SetActiveMessage")
+	defer span.Finish()
+
+	return m.messageRepo.SetActiveMessage(ctx, chatID, messageID)
+}
+
+// PrepareBranch готовит основной чат к смене ветки: активная сессия субагента
+// относится к покидаемой ветке, поэтому архивируется
+func (m *Manager) PrepareBranch(ctx context.Context, chatID domain.ID) error {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "service.chat.PrepareBranch")
+	defer span.Finish()
+
+	for {
+		child, err := m.chatRepo.GetActiveChildChat(ctx, chatID)
+		if err != nil {
+			if domain.IsNotFoundError(err) {
+				return nil
+			}
+			return err
+		}
+
+		child.Archive()
+		if err := m.chatRepo.UpdateChat(ctx, child); err != nil {
+			return err
+		}
+	}
+}
+
+// SwitchBranch переключает чат на ветку, содержащую сообщение messageID:
+// ветка продолжается по самым новым ответам до конца
+func (m *Manager) SwitchBranch(ctx context.Context, chatID, messageID, userID, orgID domain.ID) error {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "service.chat.SwitchBranch")
+	defer span.Finish()
+
+	chat, err := m.chatRepo.GetChatByID(ctx, chatID)
+	if err != nil {
+		return err
+	}
+
+	access, err := m.GetChatAccess(ctx, chat, userID, orgID)
+	if err != nil {
+		return err
+	}
+	if !access.CanRead() {
+		return domain.NewNotFoundError("chat not found")
+	}
+	if !access.CanWrite() {
+		return domain.NewForbiddenError("read-only access to chat")
+	}
+	if chat.IsSubagentChat() {
+		return domain.NewInvalidArgumentError("branches can be switched only in the main chat")
+	}
+
+	tree, err := m.GetMessageTree(ctx, chat.ID)
+	if err != nil {
+		return err
+	}
+	if _, ok := tree.Get(messageID); !ok {
+		return domain.NewNotFoundError("message not found")
+	}
+
+	if err := m.PrepareBranch(ctx, chat.ID); err != nil {
+		return err
+	}
+
+	return m.messageRepo.SetActiveMessage(ctx, chat.ID, tree.LatestLeaf(messageID))
+}
@@ -15,6 +15,20 @@ type Manager struct {
 	chatRepo    repository.ChatRepository
 	messageRepo repository.MessageRepository
 	toolRepo    repository.ToolCallRepository
+	shareRepo   repository.ChatShareRepository
+	memberships MembershipProvider
+	policy      AccessPolicy
+}
+
+// MembershipProvider - членство пользователей в организациях; domain.ErrNotFound для не участников
+type MembershipProvider interface {
+	GetMembership(ctx context.Context, userID, organizationID domain.ID) (*domain.Membership, error)
+}
+
+// AccessPolicy - настраиваемая политика доступа к чатам
+type AccessPolicy interface {
+	// GetChatsAdminVisibility - администраторы организации могут читать все ее чаты
+	GetChatsAdminVisibility() bool
 }
 
 // NewManager создает новый менеджер чатов
@@ -22,11 +36,17 @@ func NewManager(
 	chatRepo repository.ChatRepository,
 	messageRepo repository.MessageRepository,
 	toolRepo repository.ToolCallRepository,
+	shareRepo repository.ChatShareRepository,
+	memberships MembershipProvider,
+	policy AccessPolicy,
 ) *Manager {
 	return &Manager{
 		chatRepo:    chatRepo,
 		messageRepo: messageRepo,
 		toolRepo:    toolRepo,
+		shareRepo:   shareRepo,
+		memberships: memberships,
+		policy:      policy,
 	}
 }
 
@@ -82,6 +102,23 @@ func (m *Manager) ListChats(ctx context.Context, organizationID, userID domain.I
 	return chats, total, nil
 }
 
+// SearchChats ищет по истории чатов пользователя
+func (m *Manager) SearchChats(ctx context.Context, query domain.ChatSearchQuery, page, pageSize int) ([]domain.ChatSearchHit, int, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "service.chat.SearchChats")
+	defer span.Finish()
+
+	if err := query.Validate(); err != nil {
+		return nil, 0, err
+	}
+
+	hits, total, err := m.chatRepo.SearchChats(ctx, query, page, pageSize)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return hits, total, nil
+}
+
 // DeleteChat удаляет чат
 func (m *Manager) DeleteChat(ctx context.Context, chatID, userID, orgID domain.ID) error {
 	span, ctx := opentracing.StartSpanFromContext(ctx, "service.chat.DeleteChat")
@@ -104,19 +141,33 @@ func (m *Manager) GetMessages(ctx context.Context, chatID, userID, orgID domain.
 		return nil, 0, err
 	}
 
-	if chat.UserID != userID {
-		return nil, 0, domain.ErrNotFound
+	access, err := m.GetChatAccess(ctx, chat, userID, orgID)
+	if err != nil {
+		return nil, 0, err
 	}
-
-	if chat.OrganizationID != orgID {
+	if !access.CanRead() {
 		return nil, 0, domain.ErrNotFound
 	}
 
-	messages, total, err := m.messageRepo.ListMessagesWithSubchatsWithToolCalls(ctx, chatID, limit, offset)
+	messages, total, err := m.messageRepo.ListBranchMessagesWithSubchatsWithToolCalls(ctx, chatID, limit, offset)
 	if err != nil {
 		return nil, 0, domain.NewInternalError("failed to get messages", err)
 	}
 
+	// Для сообщений с альтернативными версиями отдаем список вариантов для переключения веток
+	tree, err := m.GetMessageTree(ctx, chatID)
+	if err != nil {
+		return nil, 0, err
+	}
+	for _, msg := range messages {
+		if msg.ChatID != chatID {
+			continue
+		}
+		if siblings := tree.Siblings(msg.ID); len(siblings) > 1 {
+			msg.SiblingIDs = siblings
+		}
+	}
+
 	return messages, total, nil
 }
 
@@ -139,6 +190,14 @@ func (m *Manager) SaveMessage(ctx context.Context, message *domain.Message) erro
 	return nil
 }
 
+// UpdateMessageAttachments обновляет вложения сообщения
+func (m *Manager) UpdateMessageAttachments(ctx context.Context, messageID domain.ID, attachments []domain.Attachment) error {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "service.chat.UpdateMessageAttachments")
+	defer span.Finish()
+
+	return m.messageRepo.UpdateMessageAttachments(ctx, messageID, attachments)
+}
+
 // UpdateToolCall обновляет статус tool call
 func (m *Manager) UpdateToolCall(ctx context.Context, toolCall *domain.ToolCall) error {
 	span, ctx := opentracing.StartSpanFromContext(ctx, "service.chat.UpdateToolCall")
@@ -150,7 +209,7 @@ func (m *Manager) UpdateToolCall(ctx context.Context, toolCall *domain.ToolCall)
 	return nil
 }
 
-// GetChatWithMessages получает чат со всеми сообщениями
+// GetChatWithMessages получает чат с сообщениями активной ветки
 func (m *Manager) GetChatWithMessages(ctx context.Context, chatID domain.ID) (*domain.Chat, []*domain.Message, error) {
 	span, ctx := opentracing.StartSpanFromContext(ctx, "service.chat.GetChatWithMessages")
 	defer span.Finish()
@@ -160,7 +219,7 @@ func (m *Manager) GetChatWithMessages(ctx context.Context, chatID domain.ID) (*d
 		return nil, nil, err
 	}
 
-	messages, _, err := m.messageRepo.ListMessagesByChatIDWithToolCalls(ctx, chatID, 1000, 0)
+	messages, err := m.messageRepo.ListBranchMessagesWithToolCalls(ctx, chatID)
 	if err != nil {
 		return nil, nil, domain.NewInternalError("failed to get messages", err)
 	}
@@ -0,0 +1,205 @@
+package chat
+
+import (
+	"context"
+	"errors"
+
+	"llm-service/internal/domain"
+	"llm-service/internal/repository"
+
+	"github.com/opentracing/opentracing-go"
+)
+
+// GetChatAccess определяет уровень доступа пользователя к чату.
+// Доступ к чату субагента определяется по основному чату.
+func (m *Manager) GetChatAccess(ctx context.Context, chat *domain.Chat, userID, orgID domain.ID) (domain.ChatAccess, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "service.chat.GetChatAccess")
+	defer span.Finish()
+
+	if chat.OrganizationID != orgID {
+		return domain.ChatAccessNone, nil
+	}
+
+	if chat.ParentChatID != nil {
+		root, err := m.chatRepo.GetChatByID(ctx, *chat.ParentChatID)
+		if err != nil {
+			return domain.ChatAccessNone, err
+		}
+		chat = root
+	}
+
+	if chat.UserID == userID {
+		return domain.ChatAccessOwner, nil
+	}
+
+	share, err := m.shareRepo.GetChatShare(ctx, chat.ID, userID)
+	if err == nil {
+		if share.Role == domain.ChatShareRoleCollaborator {
+			return domain.ChatAccessCollaborator, nil
+		}
+		return domain.ChatAccessViewer, nil
+	}
+	if !errors.Is(err, domain.ErrNotFound) {
+		return domain.ChatAccessNone, err
+	}
+
+	if m.policy.GetChatsAdminVisibility() {
+		member, err := m.findMember(ctx, orgID, userID)
+		if err != nil {
+			return domain.ChatAccessNone, err
+		}
+		if member != nil && member.Active && member.HasPermission(domain.PermissionChatsViewAll) {
+			return domain.ChatAccessAdmin, nil
+		}
+	}
+
+	return domain.ChatAccessNone, nil
+}
+
+// ShareChat выдает сотруднику организации доступ к чату. Управлять доступом может только владелец.
+func (m *Manager) ShareChat(ctx context.Context, chatID, ownerID, orgID, userID domain.ID, role domain.ChatShareRole) (domain.ChatShare, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "service.chat.ShareChat")
+	defer span.Finish()
+
+	if !role.IsValid() {
+		return domain.ChatShare{}, domain.NewInvalidArgumentError("invalid share role")
+	}
+
+	chat, err := m.getManagedChat(ctx, chatID, ownerID, orgID)
+	if err != nil {
+		return domain.ChatShare{}, err
+	}
+
+	if userID == chat.UserID {
+		return domain.ChatShare{}, domain.NewInvalidArgumentError("cannot share chat with its owner")
+	}
+
+	member, err := m.findMember(ctx, orgID, userID)
+	if err != nil {
+		return domain.ChatShare{}, err
+	}
+	if member == nil || !member.Active {
+		return domain.ChatShare{}, domain.NewInvalidArgumentError("user is not an active member of the organization")
+	}
+
+	share := domain.NewChatShare(chat.ID, userID, ownerID, role)
+	if err := m.shareRepo.UpsertChatShare(ctx, share); err != nil {
+		return domain.ChatShare{}, err
+	}
+
+	return share, nil
+}
+
+// UnshareChat отзывает доступ к чату. Владелец может отозвать любой доступ,
+// пользователь - отказаться от своего.
+func (m *Manager) UnshareChat(ctx context.Context, chatID, requesterID, orgID, userID domain.ID) error {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "service.chat.UnshareChat")
+	defer span.Finish()
+
+	if requesterID != userID {
+		if _, err := m.getManagedChat(ctx, chatID, requesterID, orgID); err != nil {
+			return err
+		}
+	} else {
+		chat, err := m.chatRepo.GetChatByID(ctx, chatID)
+		if err != nil {
+			return err
+		}
+		if chat.OrganizationID != orgID {
+			return domain.ErrNotFound
+		}
+	}
+
+	return m.shareRepo.DeleteChatShare(ctx, chatID, userID)
+}
+
+// ListChatShares возвращает список доступов к чату для любого, кто может его читать
+func (m *Manager) ListChatShares(ctx context.Context, chatID, userID, orgID domain.ID) ([]domain.ChatShare, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "service.chat.ListChatShares")
+	defer span.Finish()
+
+	chat, err := m.chatRepo.GetChatByID(ctx, chatID)
+	if err != nil {
+		return nil, err
+	}
+
+	access, err := m.GetChatAccess(ctx, chat, userID, orgID)
+	if err != nil {
+		return nil, err
+	}
+	if !access.CanRead() {
+		return nil, domain.ErrNotFound
+	}
+
+	return m.shareRepo.ListChatShares(ctx, chat.ID)
+}
+
+// ListSharedChats возвращает чаты организации, которыми поделились с пользователем
+func (m *Manager) ListSharedChats(ctx context.Context, organizationID, userID domain.ID, page, pageSize int) ([]domain.SharedChat, int, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "service.chat.ListSharedChats")
+	defer span.Finish()
+
+	return m.shareRepo.ListSharedChats(ctx, organizationID, userID, page, pageSize)
+}
+
+// ListOrganizationChats возвращает все чаты организации. Доступно участникам с правом
+// chats.view_all, если политика chats.admin_visibility включена.
+func (m *Manager) ListOrganizationChats(ctx context.Context, organizationID, userID domain.ID, page, pageSize int) ([]*domain.Chat, int, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "service.chat.ListOrganizationChats")
+	defer span.Finish()
+
+	if !m.policy.GetChatsAdminVisibility() {
+		return nil, 0, domain.NewForbiddenError("organization chats visibility is disabled")
+	}
+
+	member, err := m.findMember(ctx, organizationID, userID)
+	if err != nil {
+		return nil, 0, err
+	}
+	if member == nil || !member.Active || !member.HasPermission(domain.PermissionChatsViewAll) {
+		return nil, 0, domain.NewForbiddenError("permission chats.view_all is required to view all chats")
+	}
+
+	chats, total, err := m.chatRepo.ListChats(ctx, repository.ChatFilter{OrganizationID: &organizationID}, page, pageSize)
+	if err != nil {
+		return nil, 0, domain.NewInternalError("failed to list organization chats", err)
+	}
+
+	return chats, total, nil
+}
+
+// getManagedChat получает основной чат, которым пользователь может управлять
+func (m *Manager) getManagedChat(ctx context.Context, chatID, userID, orgID domain.ID) (*domain.Chat, error) {
+	chat, err := m.chatRepo.GetChatByID(ctx, chatID)
+	if err != nil {
+		return nil, err
+	}
+	if chat.IsSubagentChat() {
+		return nil, domain.NewInvalidArgumentError("subagent chats cannot be shared separately")
+	}
+
+	access, err := m.GetChatAccess(ctx, chat, userID, orgID)
+	if err != nil {
+		return nil, err
+	}
+	if !access.CanRead() {
+		return nil, domain.ErrNotFound
+	}
+	if !access.CanManage() {
+		return nil, domain.NewForbiddenError("only the chat owner can manage access")
+	}
+
+	return chat, nil
+}
+
+// findMember ищет членство пользователя в организации; nil, если пользователь не состоит в ней
+func (m *Manager) findMember(ctx context.Context, organizationID, userID domain.ID) (*domain.Membership, error) {
+	member, err := m.memberships.GetMembership(ctx, userID, organizationID)
+	if errors.Is(err, domain.ErrNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return member, nil
+}
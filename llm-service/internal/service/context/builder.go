@@ -2,6 +2,7 @@ package context
 
 import (
 	"context"
+	"llm-service/internal/coreservice"
 	"llm-service/internal/domain"
 	"llm-service/internal/rag"
 	"strings"
@@ -11,6 +12,7 @@ import (
 type Builder struct {
 	ragClient        *rag.Client
 	orgMemoryService OrganizationMemoryService
+	profiles         OrganizationProfileSource
 }
 
 // OrganizationMemoryService - интерфейс для работы с фактами об организации
@@ -18,11 +20,17 @@ type OrganizationMemoryService interface {
 	ListFacts(ctx context.Context, organizationID domain.ID) ([]domain.OrganizationMemoryFact, error)
 }
 
+// OrganizationProfileSource - источник анкеты организации (core-service)
+type OrganizationProfileSource interface {
+	GetOrganizationProfile(ctx context.Context, organizationID string) (*coreservice.OrganizationProfile, error)
+}
+
 // NewBuilder создает новый builder контекста
-func NewBuilder(ragClient *rag.Client, orgMemoryService OrganizationMemoryService) *Builder {
+func NewBuilder(ragClient *rag.Client, orgMemoryService OrganizationMemoryService, profiles OrganizationProfileSource) *Builder {
 	return &Builder{
 		ragClient:        ragClient,
 		orgMemoryService: orgMemoryService,
+		profiles:         profiles,
 	}
 }
 
@@ -77,3 +85,44 @@ func (b *Builder) EnrichWithOrganizationFacts(
 
 	return result.String(), nil
 }
+
+// EnrichWithOrganizationProfile добавляет в контекст карточку организации и поля анкеты,
+// проверенные по схеме: на них агент может опираться как на достоверные реквизиты
+func (b *Builder) EnrichWithOrganizationProfile(
+	ctx context.Context,
+	organizationID domain.ID,
+) (string, error) {
+	if b.profiles == nil {
+		return "", nil
+	}
+
+	profile, err := b.profiles.GetOrganizationProfile(ctx, organizationID.String())
+	if err != nil {
+		return "", err
+	}
+
+	var lines strings.Builder
+	writeProfileLine(&lines, "Название", profile.Name)
+	writeProfileLine(&lines, "Отрасль", profile.Industry)
+	writeProfileLine(&lines, "Регион", profile.Region)
+	writeProfileLine(&lines, "Описание", profile.Description)
+	for _, field := range profile.Fields {
+		writeProfileLine(&lines, field.Title, field.Value)
+	}
+	if lines.Len() == 0 {
+		return "", nil
+	}
+
+	return "\n\nПрофиль организации:\n" + lines.String() + "\n", nil
+}
+
+func writeProfileLine(result *strings.Builder, title, value string) {
+	if value == "" {
+		return
+	}
+	result.WriteString("- ")
+	result.WriteString(title)
+	result.WriteString(": ")
+	result.WriteString(value)
+	result.WriteString("\n")
+}
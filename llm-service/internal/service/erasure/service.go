@@ -0,0 +1,104 @@
+package erasure
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"llm-service/internal/domain"
+	"llm-service/internal/logger"
+
+	"github.com/opentracing/opentracing-go"
+)
+
+type repository interface {
+	PurgeOrganizationData(ctx context.Context, organizationID domain.ID) (int, error)
+	PurgeUserData(ctx context.Context, userID domain.ID) (int, error)
+}
+
+type transactor interface {
+	Do(ctx context.Context, f func(ctx context.Context) error) error
+}
+
+type publisher interface {
+	PublishMessage(ctx context.Context, message interface{}) error
+}
+
+// Service выполняет заявки core-service на безвозвратное удаление данных
+// и сообщает ему результат
+type Service struct {
+	repo    repository
+	tx      transactor
+	results publisher
+}
+
+func New(repo repository, tx transactor, results publisher) *Service {
+	return &Service{
+		repo:    repo,
+		tx:      tx,
+		results: results,
+	}
+}
+
+// HandleMessage разбирает заявку из очереди. Некорректные заявки отбрасываются,
+// ошибка отправки результата возвращается, чтобы заявка была обработана повторно
+func (s *Service) HandleMessage(ctx context.Context, body []byte) error {
+	var req domain.ErasureRequest
+	if err := json.Unmarshal(body, &req); err != nil {
+		logger.Warn(ctx, "erasure: dropping malformed request", "error", err)
+		return nil
+	}
+	if req.ErasureID == (domain.ID{}) || req.SubjectID == (domain.ID{}) {
+		logger.Warn(ctx, "erasure: dropping request without ids", "erasure_id", req.ErasureID)
+		return nil
+	}
+
+	return s.results.PublishMessage(ctx, s.Erase(ctx, req))
+}
+
+// Erase удаляет данные организации или пользователя и возвращает итог шага.
+// Ошибка удаления попадает в результат: core-service повторит заявку позже
+func (s *Service) Erase(ctx context.Context, req domain.ErasureRequest) domain.ErasureStepResult {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "service.erasure.Erase")
+	defer span.Finish()
+
+	result := domain.ErasureStepResult{
+		ErasureID: req.ErasureID,
+		Step:      domain.ErasureStepLLMData,
+		Source:    "llm-service",
+		Status:    domain.ErasureStepCompleted,
+	}
+
+	err := s.tx.Do(ctx, func(txCtx context.Context) error {
+		var err error
+		switch req.SubjectType {
+		case domain.ErasureSubjectOrganization:
+			result.ItemsDeleted, err = s.repo.PurgeOrganizationData(txCtx, req.SubjectID)
+		case domain.ErasureSubjectUser:
+			result.ItemsDeleted, err = s.repo.PurgeUserData(txCtx, req.SubjectID)
+		default:
+			err = fmt.Errorf("unknown erasure subject %q", req.SubjectType)
+		}
+		return err
+	})
+	if err != nil {
+		logger.Error(ctx, "erasure: failed to purge data",
+			"erasure_id", req.ErasureID,
+			"subject_type", req.SubjectType,
+			"error", err,
+		)
+		result.Status = domain.ErasureStepFailed
+		result.ItemsDeleted = 0
+		result.Error = err.Error()
+	} else {
+		logger.Info(ctx, "erasure: data purged",
+			"erasure_id", req.ErasureID,
+			"subject_type", req.SubjectType,
+			"items_deleted", result.ItemsDeleted,
+		)
+	}
+
+	result.FinishedAt = time.Now()
+	return result
+}
@@ -0,0 +1,159 @@
+package executor
+
+import (
+	"context"
+	"strings"
+
+	"llm-service/internal/domain"
+	"llm-service/internal/domain/dto"
+	"llm-service/internal/logger"
+	"llm-service/internal/service"
+
+	"github.com/opentracing/opentracing-go"
+)
+
+// EditMessageStream создает новую ветку: измененное сообщение пользователя становится
+// соседом исходного, исходная ветка сохраняется, ответ агента стримится заново
+func (e *Executor) EditMessageStream(ctx context.Context, req dto.EditMessageDTO, stream service.MessageStream) error {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "executor.EditMessageStream")
+	defer span.Finish()
+
+	logger.Infof(ctx, "EditMessageStream: chatID=%s, messageID=%s, userID=%s, content_len=%d",
+		req.ChatID, req.MessageID, req.UserID, len(req.Content))
+
+	if strings.TrimSpace(req.Content) == "" {
+		return stream.SendError(domain.NewInvalidArgumentError("message content is required"))
+	}
+
+	chat, original, err := e.loadBranchMessage(ctx, req.ChatID, req.MessageID, req.UserID, req.OrgID)
+	if err != nil {
+		return stream.SendError(err)
+	}
+	if original.Role != domain.MessageRoleUser || original.ParentMessageID == nil {
+		return stream.SendError(domain.NewInvalidArgumentError("only user messages can be edited"))
+	}
+
+	if err := e.chatManager.PrepareBranch(ctx, chat.ID); err != nil {
+		return stream.SendError(err)
+	}
+
+	// Вложения исходного сообщения уже подготовлены и переносятся как есть
+	userMessage := &domain.Message{
+		Model:           domain.NewModel(),
+		ChatID:          chat.ID,
+		Role:            domain.MessageRoleUser,
+		Content:         req.Content,
+		Attachments:     original.Attachments,
+		AuthorUserID:    &req.UserID,
+		ParentMessageID: original.ParentMessageID,
+	}
+	if err := e.chatManager.SaveMessage(ctx, userMessage); err != nil {
+		logger.Errorf(ctx, "EditMessageStream: failed to save edited message: %v", err)
+		return stream.SendError(err)
+	}
+
+	logger.Infof(ctx, "EditMessageStream: saved edited message with ID=%s", userMessage.ID)
+
+	return e.runBranchTurn(ctx, chat, req.UserID, req.OrgID, req.Membership, stream)
+}
+
+// RegenerateStream создает новую ветку с другим ответом ассистента: активная ветка
+// обрезается до сообщения пользователя, на которое был дан ответ, и агент отвечает заново
+func (e *Executor) RegenerateStream(ctx context.Context, req dto.RegenerateMessageDTO, stream service.MessageStream) error {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "executor.RegenerateStream")
+	defer span.Finish()
+
+	logger.Infof(ctx, "RegenerateStream: chatID=%s, messageID=%s, userID=%s", req.ChatID, req.MessageID, req.UserID)
+
+	chat, reply, err := e.loadBranchMessage(ctx, req.ChatID, req.MessageID, req.UserID, req.OrgID)
+	if err != nil {
+		return stream.SendError(err)
+	}
+	if reply.Role != domain.MessageRoleAssistant {
+		return stream.SendError(domain.NewInvalidArgumentError("only assistant messages can be regenerated"))
+	}
+
+	tree, err := e.chatManager.GetMessageTree(ctx, chat.ID)
+	if err != nil {
+		return stream.SendError(err)
+	}
+
+	// Ответ мог состоять из нескольких сообщений с вызовами инструментов,
+	// поэтому новая ветка начинается от ближайшего сообщения пользователя
+	question, ok := tree.NearestAncestor(reply.ID, domain.MessageRoleUser)
+	if !ok {
+		return stream.SendError(domain.NewInvalidArgumentError("message has no user message to answer"))
+	}
+
+	if err := e.chatManager.PrepareBranch(ctx, chat.ID); err != nil {
+		return stream.SendError(err)
+	}
+	if err := e.chatManager.SetActiveMessage(ctx, chat.ID, question.ID); err != nil {
+		logger.Errorf(ctx, "RegenerateStream: failed to rewind active branch: %v", err)
+		return stream.SendError(err)
+	}
+
+	return e.runBranchTurn(ctx, chat, req.UserID, req.OrgID, req.Membership, stream)
+}
+
+// loadBranchMessage загружает основной чат и его сообщение, проверяя право на запись
+func (e *Executor) loadBranchMessage(ctx context.Context, chatID, messageID, userID, orgID domain.ID) (*domain.Chat, *domain.Message, error) {
+	chat, err := e.chatManager.GetChat(ctx, chatID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	access, err := e.chatManager.GetChatAccess(ctx, chat, userID, orgID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if !access.CanRead() {
+		return nil, nil, domain.NewNotFoundError("chat not found")
+	}
+	if !access.CanWrite() {
+		return nil, nil, domain.NewForbiddenError("read-only access to chat")
+	}
+	if chat.IsSubagentChat() {
+		return nil, nil, domain.NewInvalidArgumentError("messages can be edited only in the main chat")
+	}
+
+	message, err := e.chatManager.GetMessage(ctx, messageID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if message.ChatID != chat.ID {
+		return nil, nil, domain.NewNotFoundError("message not found")
+	}
+
+	return chat, message, nil
+}
+
+// runBranchTurn запускает основного агента по новой активной ветке и отправляет финальное состояние
+func (e *Executor) runBranchTurn(
+	ctx context.Context,
+	chat *domain.Chat,
+	userID, orgID domain.ID,
+	member *domain.Membership,
+	stream service.MessageStream,
+) error {
+	agentDef, err := e.agentManager.GetAgent(chat.AgentKey)
+	if err != nil {
+		return stream.SendError(err)
+	}
+
+	execCtx := &domain.ExecutionContext{
+		OrganizationID: chat.OrganizationID,
+		UserID:         userID,
+		ChatID:         chat.ID,
+		RootChatID:     chat.ID,
+		AgentKey:       chat.AgentKey,
+	}
+	execCtx.SetMembership(member)
+
+	if err := e.runAgentLoopStream(ctx, chat, agentDef, execCtx, stream); err != nil {
+		logger.Errorf(ctx, "agent loop failed on new branch: %v", err)
+		return err
+	}
+
+	return e.sendFinalState(ctx, chat.ID, userID, orgID, stream)
+}
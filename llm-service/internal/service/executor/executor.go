@@ -3,6 +3,7 @@ package executor
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"llm-service/internal/config"
 	"llm-service/internal/domain"
@@ -12,6 +13,7 @@ import (
 	"llm-service/internal/service"
 	"strings"
 	"time"
+	"unicode/utf8"
 
 	"github.com/opentracing/opentracing-go"
 	"github.com/samber/lo"
@@ -30,6 +32,8 @@ type Executor struct {
 	subagentManager service.SubagentManager
 	llmProvider     llm.CompletionProvider
 	quotaService    service.QuotaService
+	attachments     service.AttachmentService
+	factExtractor   service.FactExtractor
 	cfg             *config.Config
 }
 
@@ -42,6 +46,8 @@ func NewExecutor(
 	subagentManager service.SubagentManager,
 	llmProvider llm.CompletionProvider,
 	quotaService service.QuotaService,
+	attachments service.AttachmentService,
+	factExtractor service.FactExtractor,
 	cfg *config.Config,
 ) *Executor {
 	return &Executor{
@@ -52,6 +58,8 @@ func NewExecutor(
 		subagentManager: subagentManager,
 		llmProvider:     llmProvider,
 		quotaService:    quotaService,
+		attachments:     attachments,
+		factExtractor:   factExtractor,
 		cfg:             cfg,
 	}
 }
@@ -75,11 +83,16 @@ func (e *Executor) ExecuteStream(ctx context.Context, req dto.ExecuteAgentDTO, s
 			return stream.SendError(err)
 		}
 	} else {
+		title := req.Title
+		if title == "" {
+			title = req.Task
+		}
+
 		chat, err = e.chatManager.CreateChat(ctx, dto.CreateChatDTO{
 			OrganizationID: req.OrganizationID,
 			UserID:         req.UserID,
 			AgentKey:       req.AgentKey,
-			Title:          req.Task,
+			Title:          title,
 		})
 		if err != nil {
 			return stream.SendError(err)
@@ -111,6 +124,7 @@ func (e *Executor) ExecuteStream(ctx context.Context, req dto.ExecuteAgentDTO, s
 		TaskDescription:   req.Task,
 		AdditionalContext: req.Context,
 	}
+	execCtx.SetMembership(req.Membership)
 
 	// Добавляем сообщение пользователя
 	userMessage := &domain.Message{
@@ -137,8 +151,33 @@ func (e *Executor) SendMessageStream(ctx context.Context, req dto.SendMessageDTO
 	span, ctx := opentracing.StartSpanFromContext(ctx, "executor.SendMessageStream")
 	defer span.Finish()
 
-	logger.Infof(ctx, "SendMessageStream: started with chatID=%v, userID=%s, orgID=%s, content_len=%d",
-		req.ChatID, req.UserID, req.OrgID, len(req.Content))
+	logger.Infof(ctx, "SendMessageStream: started with chatID=%v, userID=%s, orgID=%s, content_len=%d, attachments=%d",
+		req.ChatID, req.UserID, req.OrgID, len(req.Content), len(req.Attachments))
+
+	if strings.TrimSpace(req.Content) == "" && len(req.Attachments) == 0 {
+		return stream.SendError(domain.NewInvalidArgumentError("message content or attachments are required"))
+	}
+
+	// Проверяем вложения до создания чата, чтобы не оставлять пустые чаты
+	var attachments []domain.Attachment
+	if len(req.Attachments) > 0 {
+		var err error
+		attachments, err = e.attachments.Resolve(ctx, req.OrgID, req.Attachments)
+		if err != nil {
+			logger.Errorf(ctx, "SendMessageStream: invalid attachments: %v", err)
+			return stream.SendError(err)
+		}
+
+		// Голосовые сообщения распознаем сразу: расшифровка нужна для названия чата и RAG
+		attachments, err = e.attachments.Transcribe(ctx, attachments)
+		if err != nil {
+			logger.Errorf(ctx, "SendMessageStream: failed to transcribe attachments: %v", err)
+			return stream.SendError(err)
+		}
+	}
+
+	// Текст запроса пользователя: напечатанный и надиктованный
+	requestText := userText(req.Content, attachments)
 
 	// Получаем чат (всегда родительский - пользователь пишет в него)
 	// Если нет, создаем новый
@@ -148,9 +187,13 @@ func (e *Executor) SendMessageStream(ctx context.Context, req dto.SendMessageDTO
 	)
 	if req.ChatID == nil {
 		logger.Info(ctx, "SendMessageStream: no chatID provided, creating new chat")
-		title, genErr := e.generateChatTitle(ctx, req.Content)
-		if genErr != nil {
-			logger.Errorf(ctx, "failed to generate chat title: %v", genErr)
+		var title string
+		if requestText != "" {
+			var genErr error
+			title, genErr = e.generateChatTitle(ctx, requestText)
+			if genErr != nil {
+				logger.Errorf(ctx, "failed to generate chat title: %v", genErr)
+			}
 		}
 		chat, err = e.chatManager.CreateChat(ctx, dto.CreateChatDTO{
 			OrganizationID: req.OrgID,
@@ -173,7 +216,7 @@ func (e *Executor) SendMessageStream(ctx context.Context, req dto.SendMessageDTO
 		}
 
 		logger.Info(ctx, "SendMessageStream: building system prompt with RAG")
-		systemPrompt, err := e.buildSystemPromptWithRAG(ctx, chat, agentDef, req.Content)
+		systemPrompt, err := e.buildSystemPromptWithRAG(ctx, chat, agentDef, requestText)
 		if err != nil {
 			logger.Errorf(ctx, "SendMessageStream: failed to build system prompt: %v", err)
 			return stream.SendError(err)
@@ -200,7 +243,20 @@ func (e *Executor) SendMessageStream(ctx context.Context, req dto.SendMessageDTO
 			logger.Errorf(ctx, "SendMessageStream: failed to get chat: %v", err)
 			return stream.SendError(err)
 		}
-		logger.Infof(ctx, "SendMessageStream: loaded chat, agentKey=%s, status=%v", chat.AgentKey, chat.Status)
+
+		// Писать в чат могут владелец и соавторы; у читателей доступ только на чтение
+		access, err := e.chatManager.GetChatAccess(ctx, chat, req.UserID, req.OrgID)
+		if err != nil {
+			logger.Errorf(ctx, "SendMessageStream: failed to check chat access: %v", err)
+			return stream.SendError(err)
+		}
+		if !access.CanRead() {
+			return stream.SendError(domain.NewNotFoundError("chat not found"))
+		}
+		if !access.CanWrite() {
+			return stream.SendError(domain.NewForbiddenError("read-only access to chat"))
+		}
+		logger.Infof(ctx, "SendMessageStream: loaded chat, agentKey=%s, status=%v, access=%s", chat.AgentKey, chat.Status, access)
 	}
 
 	// Определяем активный чат: если есть активная сессия субагента, используем её
@@ -231,12 +287,23 @@ func (e *Executor) SendMessageStream(ctx context.Context, req dto.SendMessageDTO
 
 	logger.Infof(ctx, "SendMessageStream: using agent '%s'(%s)", agentDef.Name, agentDef.Key)
 
+	// Документы обрабатываются в контексте основного чата: субагенты видят те же файлы
+	if len(attachments) > 0 {
+		attachments, err = e.attachments.Prepare(ctx, chat.OrganizationID, chat.ID, attachments)
+		if err != nil {
+			logger.Errorf(ctx, "SendMessageStream: failed to prepare attachments: %v", err)
+			return stream.SendError(err)
+		}
+	}
+
 	// Сохраняем сообщение пользователя в активный чат
 	userMessage := &domain.Message{
-		Model:   domain.NewModel(),
-		ChatID:  activeChatID,
-		Role:    domain.MessageRoleUser,
-		Content: req.Content,
+		Model:        domain.NewModel(),
+		ChatID:       activeChatID,
+		Role:         domain.MessageRoleUser,
+		Content:      req.Content,
+		Attachments:  attachments,
+		AuthorUserID: &req.UserID,
 	}
 	if err := e.chatManager.SaveMessage(ctx, userMessage); err != nil {
 		logger.Errorf(ctx, "SendMessageStream: failed to save user message: %v", err)
@@ -250,8 +317,10 @@ func (e *Executor) SendMessageStream(ctx context.Context, req dto.SendMessageDTO
 		OrganizationID: chat.OrganizationID,
 		UserID:         req.UserID,
 		ChatID:         activeChat.ID,
+		RootChatID:     chat.ID,
 		AgentKey:       activeChat.AgentKey,
 	}
+	execCtx.SetMembership(req.Membership)
 
 	logger.Infof(ctx, "SendMessageStream: starting agent loop for chatID=%s, agentKey=%s",
 		activeChat.ID, activeChat.AgentKey)
@@ -265,13 +334,24 @@ func (e *Executor) SendMessageStream(ctx context.Context, req dto.SendMessageDTO
 
 	logger.Info(ctx, "SendMessageStream: agent loop completed successfully")
 
-	// Отправляем финальное состояние чата
-	finalChat, err := e.chatManager.GetChat(ctx, chat.ID)
+	// Извлеченные факты сохраняются в память организации, поэтому нужно право на ее изменение
+	if e.factExtractor != nil && e.cfg.GetLLMFactExtraction() && execCtx.HasPermission(domain.PermissionMemoryEdit) {
+		if !e.factExtractor.Submit(ctx, chat.OrganizationID, req.UserID, requestText) {
+			logger.Warn(ctx, "fact extraction queue is full, skipping message", "chat_id", chat.ID)
+		}
+	}
+
+	return e.sendFinalState(ctx, chat.ID, req.UserID, req.OrgID, stream)
+}
+
+// sendFinalState отправляет финальное состояние чата
+func (e *Executor) sendFinalState(ctx context.Context, chatID, userID, orgID domain.ID, stream service.MessageStream) error {
+	finalChat, err := e.chatManager.GetChat(ctx, chatID)
 	if err != nil {
 		return stream.SendError(err)
 	}
 
-	finalMessages, _, err := e.chatManager.GetMessages(ctx, chat.ID, req.UserID, req.OrgID, 1000, 0)
+	finalMessages, _, err := e.chatManager.GetMessages(ctx, chatID, userID, orgID, 1000, 0)
 	if err != nil {
 		return stream.SendError(err)
 	}
@@ -304,6 +384,9 @@ func (e *Executor) runAgentLoopStream(
 	currentAgent := agentDef
 	currentExecCtx := execCtx
 
+	// Содержимое вложений загружается из S3 один раз за запуск
+	attachmentData := make(map[string][]byte)
+
 	for range maxIterations {
 		// Получаем актуальную историю текущего чата
 		_, messages, err := e.chatManager.GetChatWithMessages(ctx, currentChat.ID)
@@ -312,13 +395,13 @@ func (e *Executor) runAgentLoopStream(
 		}
 
 		// Строим контекст для LLM - просто конвертируем messages из БД
-		llmMessages, err := e.buildLLMMessages(messages)
+		llmMessages, err := e.buildLLMMessages(ctx, messages, attachmentData)
 		if err != nil {
 			return stream.SendError(err)
 		}
 
 		// Получаем инструменты текущего агента
-		tools, err := e.buildLLMTools(currentAgent)
+		tools, err := e.buildLLMTools(currentAgent, currentExecCtx)
 		if err != nil {
 			return stream.SendError(err)
 		}
@@ -329,6 +412,10 @@ func (e *Executor) runAgentLoopStream(
 			Tools:        tools,
 			IncludeUsage: true,
 		}
+		if currentAgent.Model != "" {
+			// Модель агента; роутер провайдеров выберет бэкенд, который ее обслуживает
+			params.Model = &currentAgent.Model
+		}
 
 		llmStream, err := e.llmProvider.CreateCompletionStream(ctx, params)
 		if err != nil {
@@ -517,9 +604,12 @@ func (e *Executor) runAgentLoopStream(
 					OrganizationID:    currentExecCtx.OrganizationID,
 					UserID:            currentExecCtx.UserID,
 					ChatID:            subagentChat.ID,
+					RootChatID:        currentExecCtx.RootChatID,
 					AgentKey:          subagentKey,
 					TaskDescription:   task,
 					AdditionalContext: currentExecCtx.AdditionalContext,
+					Role:              currentExecCtx.Role,
+					Permissions:       currentExecCtx.Permissions,
 				}
 
 				// ПЕРЕКЛЮЧАЕМ КОНТЕКСТ на субагента
@@ -580,9 +670,12 @@ func (e *Executor) runAgentLoopStream(
 					OrganizationID:    currentExecCtx.OrganizationID,
 					UserID:            currentExecCtx.UserID,
 					ChatID:            parentChat.ID,
+					RootChatID:        currentExecCtx.RootChatID,
 					AgentKey:          parentChat.AgentKey,
 					TaskDescription:   "",
 					AdditionalContext: currentExecCtx.AdditionalContext,
+					Role:              currentExecCtx.Role,
+					Permissions:       currentExecCtx.Permissions,
 				}
 
 				// Сохраняем результат субагента в родительский чат как tool result
@@ -667,6 +760,18 @@ func (e *Executor) buildSystemPromptWithRAG(
 	systemPrompt := fmt.Sprintf("Текущее время: %s\n\n", time.Now().Format("2006-01-02 15:04:05"))
 	systemPrompt += agentDef.GetSystemPrompt()
 
+	// Реквизиты и показатели из анкеты организации, проверенные core-service по схеме
+	// Без анкеты агент продолжает работу, но ошибка не должна теряться
+	profileContext, err := e.contextBuilder.EnrichWithOrganizationProfile(ctx, chat.OrganizationID)
+	if err != nil {
+		logger.Warn(ctx, "failed to enrich system prompt with organization profile",
+			"organization_id", chat.OrganizationID,
+			"error", err,
+		)
+	} else if profileContext != "" {
+		systemPrompt += profileContext
+	}
+
 	// Обогащаем контекст фактами об организации
 	orgContext, err := e.contextBuilder.EnrichWithOrganizationFacts(ctx, chat.OrganizationID)
 	if err == nil && orgContext != "" {
@@ -691,7 +796,9 @@ func (e *Executor) buildSystemPromptWithRAG(
 
 // buildLLMMessages строит сообщения для LLM из БД
 func (e *Executor) buildLLMMessages(
+	ctx context.Context,
 	messages []*domain.Message,
+	attachmentData map[string][]byte,
 ) ([]llm.MessageParam, error) {
 	llmMessages := make([]llm.MessageParam, 0, len(messages))
 
@@ -702,6 +809,15 @@ func (e *Executor) buildLLMMessages(
 			Content: msg.Content,
 		}
 
+		// Вложения передаем модели как части сообщения вместе с текстом
+		if msg.IsUserMessage() && msg.HasAttachments() {
+			parts, err := e.buildAttachmentParts(ctx, msg, attachmentData)
+			if err != nil {
+				return nil, err
+			}
+			llmMsg.Parts = parts
+		}
+
 		// Добавляем tool calls для assistant сообщений
 		if msg.HasToolCalls() {
 			llmMsg.ToolCalls = make([]llm.ToolCall, len(msg.ToolCalls))
@@ -725,8 +841,9 @@ func (e *Executor) buildLLMMessages(
 	return llmMessages, nil
 }
 
-// buildLLMTools строит список инструментов для LLM
-func (e *Executor) buildLLMTools(agentDef *domain.AgentDefinition) ([]llm.ToolDefinition, error) {
+// buildLLMTools строит список инструментов для LLM.
+// Инструменты, на которые у пользователя нет права, модели не показываются
+func (e *Executor) buildLLMTools(agentDef *domain.AgentDefinition, execCtx *domain.ExecutionContext) ([]llm.ToolDefinition, error) {
 	// Используем GetAgentTools для правильной обработки паттернов (например, "ammo-crm-*")
 	agentTools, err := e.agentManager.GetAgentTools(agentDef.Key)
 	if err != nil {
@@ -735,12 +852,82 @@ func (e *Executor) buildLLMTools(agentDef *domain.AgentDefinition) ([]llm.ToolDe
 
 	tools := make([]llm.ToolDefinition, 0, len(agentTools))
 	for _, toolDef := range agentTools {
+		if _, missing := execCtx.MissingToolPermission(string(toolDef.Name)); missing {
+			continue
+		}
 		tools = append(tools, toolDef.ToLLMObject())
 	}
 
 	return tools, nil
 }
 
+// buildAttachmentParts строит multi-part контент сообщения: текст и вложения
+func (e *Executor) buildAttachmentParts(
+	ctx context.Context,
+	msg *domain.Message,
+	attachmentData map[string][]byte,
+) ([]llm.ContentPart, error) {
+	parts := make([]llm.ContentPart, 0, len(msg.Attachments)+1)
+	if msg.Content != "" {
+		parts = append(parts, llm.TextPart(msg.Content))
+	}
+
+	for _, attachment := range msg.Attachments {
+		switch attachment.Delivery {
+		case domain.AttachmentDeliveryInline:
+			parts = append(parts, llm.TextPart(fmt.Sprintf("Файл %s:\n%s", attachment.FileName, attachment.Text)))
+			continue
+		case domain.AttachmentDeliveryTranscript:
+			parts = append(parts, llm.TextPart(fmt.Sprintf("Голосовое сообщение (%s), расшифровка:\n%s", attachment.FileName, attachment.Text)))
+			continue
+		case domain.AttachmentDeliveryIndex:
+			parts = append(parts, llm.TextPart(fmt.Sprintf(
+				"Файл %s приложен к чату, но слишком большой, чтобы привести его целиком. Ищи в нем нужные фрагменты инструментом %s.",
+				attachment.FileName, domain.ToolNameSearchChatFiles,
+			)))
+			continue
+		}
+
+		data, ok := attachmentData[attachment.S3Key]
+		if !ok {
+			var err error
+			data, err = e.attachments.Load(ctx, attachment)
+			if err != nil {
+				return nil, err
+			}
+			attachmentData[attachment.S3Key] = data
+		}
+
+		partType := llm.ContentPartImage
+		if attachment.Type() == domain.AttachmentTypeDocument {
+			partType = llm.ContentPartFile
+		}
+
+		parts = append(parts, llm.ContentPart{
+			Type:      partType,
+			Data:      data,
+			MediaType: attachment.ContentType,
+			FileName:  attachment.FileName,
+		})
+	}
+
+	return parts, nil
+}
+
+// userText - текст сообщения пользователя вместе с расшифровками голосовых вложений
+func userText(content string, attachments []domain.Attachment) string {
+	parts := make([]string, 0, len(attachments)+1)
+	if content = strings.TrimSpace(content); content != "" {
+		parts = append(parts, content)
+	}
+	for _, attachment := range attachments {
+		if attachment.IsTranscribed() {
+			parts = append(parts, attachment.Text)
+		}
+	}
+	return strings.Join(parts, "\n\n")
+}
+
 // mapMessageRole маппит доменную роль в LLM роль
 func (e *Executor) mapMessageRole(role domain.MessageRole) string {
 	switch role {
@@ -757,12 +944,48 @@ func (e *Executor) mapMessageRole(role domain.MessageRole) string {
 	}
 }
 
+// maxChatTitleLength - максимальная длина названия чата в символах
+const maxChatTitleLength = 50
+
+// chatTitle - структурированный ответ модели при генерации названия чата
+type chatTitle struct {
+	Title string `json:"title"`
+}
+
+func (t *chatTitle) Validate() error {
+	t.Title = strings.TrimSpace(t.Title)
+	if t.Title == "" {
+		return errors.New("title is empty")
+	}
+	if n := utf8.RuneCountInString(t.Title); n > maxChatTitleLength {
+		return fmt.Errorf("title is %d characters long, maximum is %d", n, maxChatTitleLength)
+	}
+	return nil
+}
+
+var chatTitleFormat = llm.ResponseFormat{
+	Name:        "chat_title",
+	Description: "Краткое название чата",
+	Schema: map[string]any{
+		"type": "object",
+		"properties": map[string]any{
+			"title": map[string]any{
+				"type":        "string",
+				"description": "Название чата, не более 50 символов",
+			},
+		},
+		"required":             []string{"title"},
+		"additionalProperties": false,
+	},
+	Strict: true,
+}
+
 // generateChatTitle генерирует название чата на основе первого сообщения пользователя
 func (e *Executor) generateChatTitle(ctx context.Context, userMessage string) (string, error) {
 	span, ctx := opentracing.StartSpanFromContext(ctx, "executor.generateChatTitle")
 	defer span.Finish()
 
-	prompt := "Сгенерируй краткое и ёмкое название для этого чата на основе первого сообщения пользователя. Название должно быть коротким, не более 50 символов. В ответе укажи только одно название без лишних символов"
+	prompt := "Сгенерируй краткое и ёмкое название для этого чата на основе первого сообщения пользователя. Название должно быть коротким, не более 50 символов. Ответь JSON объектом с полем title"
 
 	// Используем специальную модель для генерации названий
 	titleModel := e.cfg.GetLLMTitleGenerationModel()
@@ -778,18 +1001,27 @@ func (e *Executor) generateChatTitle(ctx context.Context, userMessage string) (s
 
 	logger.Infof(ctx, "Generating chat title with model: %s, prompt: %s", titleModel, prompt)
 
-	title, _, err := e.llmProvider.CreateCompletion(ctx, params)
+	result, _, err := llm.CompleteStructured[chatTitle](ctx, e.llmProvider, params, chatTitleFormat)
 	if err != nil {
-		return "", fmt.Errorf("failed to generate chat title: %w", err)
+		// Слишком длинное название после повтора обрезаем, а не теряем
+		var invalid *llm.InvalidOutputError
+		if !errors.As(err, &invalid) || strings.TrimSpace(result.Title) == "" {
+			return "", fmt.Errorf("failed to generate chat title: %w", err)
+		}
+		logger.Warn(ctx, "Chat title failed validation, truncating", "error", err)
 	}
 
-	// Trim and limit length
-	title = strings.TrimSpace(title)
-	if len(title) > 50 {
-		title = title[:50]
-	}
+	title := truncateRunes(strings.TrimSpace(result.Title), maxChatTitleLength)
 
 	logger.Infof(ctx, "Generated chat title: %s", title)
 
 	return title, nil
 }
+
+// truncateRunes обрезает строку до n символов, не разрывая многобайтовые руны
+func truncateRunes(s string, n int) string {
+	if utf8.RuneCountInString(s) <= n {
+		return s
+	}
+	return strings.TrimSpace(string([]rune(s)[:n]))
+}
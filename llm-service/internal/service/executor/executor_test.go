@@ -0,0 +1,645 @@
+package executor
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"strings"
+	"testing"
+
+	"llm-service/internal/config"
+	"llm-service/internal/domain"
+	"llm-service/internal/domain/dto"
+	"llm-service/internal/llm"
+	"llm-service/internal/llm/cassette"
+	"llm-service/internal/llm/fake"
+	"llm-service/internal/logger"
+	"llm-service/internal/service/agent"
+	"llm-service/internal/service/subagent"
+)
+
+func TestMain(m *testing.M) {
+	logger.Init()
+	os.Exit(m.Run())
+}
+
+type testEnv struct {
+	executor     *Executor
+	chatManager  *memChatManager
+	toolExecutor *fakeToolExecutor
+	agentManager *agent.Manager
+	stream       *recordingStream
+	chat         *domain.Chat
+	agentDef     *domain.AgentDefinition
+	execCtx      *domain.ExecutionContext
+}
+
+// newTestEnv создает executor с in-memory зависимостями и основной чат с system и user сообщениями
+func newTestEnv(t *testing.T, provider llm.CompletionProvider) *testEnv {
+	t.Helper()
+
+	ctx := context.Background()
+
+	agentManager, err := agent.NewManager()
+	if err != nil {
+		t.Fatalf("agent.NewManager: %v", err)
+	}
+
+	chatManager := newMemChatManager()
+	subagentManager := subagent.NewManager(chatManager, agentManager)
+	toolExecutor := newFakeToolExecutor(subagentManager)
+
+	agentDef, err := agentManager.GetAgent("main")
+	if err != nil {
+		t.Fatalf("GetAgent: %v", err)
+	}
+
+	chat, err := chatManager.CreateChat(ctx, dto.CreateChatDTO{
+		OrganizationID: domain.NewID(),
+		UserID:         domain.NewID(),
+		AgentKey:       agentDef.Key,
+		Title:          "Тестовый чат",
+	})
+	if err != nil {
+		t.Fatalf("CreateChat: %v", err)
+	}
+
+	for _, msg := range []*domain.Message{
+		{Model: domain.NewModel(), ChatID: chat.ID, Role: domain.MessageRoleSystem, Content: agentDef.GetSystemPrompt()},
+		{Model: domain.NewModel(), ChatID: chat.ID, Role: domain.MessageRoleUser, Content: "Найди новости о нашей отрасли"},
+	} {
+		if err := chatManager.SaveMessage(ctx, msg); err != nil {
+			t.Fatalf("SaveMessage: %v", err)
+		}
+	}
+
+	return &testEnv{
+		executor: NewExecutor(
+			chatManager,
+			agentManager,
+			fakeContextBuilder{},
+			toolExecutor,
+			subagentManager,
+			provider,
+			nil,
+			nil,
+			nil,
+			config.Get(),
+		),
+		chatManager:  chatManager,
+		toolExecutor: toolExecutor,
+		agentManager: agentManager,
+		stream:       &recordingStream{},
+		chat:         chat,
+		agentDef:     agentDef,
+		execCtx: &domain.ExecutionContext{
+			OrganizationID: chat.OrganizationID,
+			UserID:         chat.UserID,
+			ChatID:         chat.ID,
+			AgentKey:       agentDef.Key,
+		},
+	}
+}
+
+func (env *testEnv) run(t *testing.T) error {
+	t.Helper()
+	return env.executor.runAgentLoopStream(context.Background(), env.chat, env.agentDef, env.execCtx, env.stream)
+}
+
+func messagesByRole(messages []*domain.Message, role domain.MessageRole) []*domain.Message {
+	var result []*domain.Message
+	for _, msg := range messages {
+		if msg.Role == role {
+			result = append(result, msg)
+		}
+	}
+	return result
+}
+
+func TestRunAgentLoopStream_TextResponse(t *testing.T) {
+	provider := fake.New(fake.Turn{
+		Content:   "Вот свежие новости отрасли.",
+		Usage:     llm.Usage{PromptTokens: 100, CompletionTokens: 20, TotalTokens: 120},
+		ChunkSize: 4,
+	})
+	env := newTestEnv(t, provider)
+
+	if err := env.run(t); err != nil {
+		t.Fatalf("runAgentLoopStream: %v", err)
+	}
+
+	if got := strings.Join(env.stream.chunks, ""); got != "Вот свежие новости отрасли." {
+		t.Errorf("streamed content = %q", got)
+	}
+	if len(env.stream.chunks) < 2 {
+		t.Errorf("expected content to be streamed in several chunks, got %d", len(env.stream.chunks))
+	}
+
+	if len(env.stream.usage) != 1 || env.stream.usage[0].TotalTokens != 120 {
+		t.Errorf("usage = %+v, want one event with 120 total tokens", env.stream.usage)
+	}
+
+	assistant := messagesByRole(env.chatManager.chatMessages(env.chat.ID), domain.MessageRoleAssistant)
+	if len(assistant) != 1 {
+		t.Fatalf("assistant messages = %d, want 1", len(assistant))
+	}
+	if assistant[0].Content != "Вот свежие новости отрасли." {
+		t.Errorf("saved content = %q", assistant[0].Content)
+	}
+	if assistant[0].Sender == nil || *assistant[0].Sender != "main" {
+		t.Errorf("sender = %v, want main", assistant[0].Sender)
+	}
+
+	requests := provider.Requests()
+	if len(requests) != 1 {
+		t.Fatalf("LLM requests = %d, want 1", len(requests))
+	}
+	if len(requests[0].Messages) != 2 || requests[0].Messages[0].Role != llm.RoleSystem || requests[0].Messages[1].Role != llm.RoleUser {
+		t.Errorf("unexpected request history: %+v", requests[0].Messages)
+	}
+	if requests[0].Model != nil {
+		t.Errorf("model = %q, want provider default", *requests[0].Model)
+	}
+}
+
+func TestRunAgentLoopStream_AgentModel(t *testing.T) {
+	provider := fake.New(fake.Text("ok"))
+	env := newTestEnv(t, provider)
+
+	agentDef := *env.agentDef
+	agentDef.Model = "gpt-5"
+	env.agentDef = &agentDef
+
+	if err := env.run(t); err != nil {
+		t.Fatalf("runAgentLoopStream: %v", err)
+	}
+
+	requests := provider.Requests()
+	if requests[0].Model == nil || *requests[0].Model != "gpt-5" {
+		t.Errorf("model = %v, want gpt-5", requests[0].Model)
+	}
+}
+
+func TestRunAgentLoopStream_ToolCallAccumulation(t *testing.T) {
+	provider := fake.New(
+		fake.Turn{
+			Content: "Сейчас поищу.",
+			ToolCalls: []llm.ToolCall{
+				fake.NewToolCall("web_search", map[string]any{"query": "новости ритейла", "max_results": 3}),
+				fake.NewToolCall("save_organization_note", map[string]any{"content": "Компания работает в ритейле"}),
+			},
+			ChunkSize: 3,
+		},
+		fake.Text("Нашел три новости."),
+	)
+	env := newTestEnv(t, provider)
+	env.toolExecutor.results["web_search"] = map[string]any{"results": []string{"a", "b", "c"}}
+
+	if err := env.run(t); err != nil {
+		t.Fatalf("runAgentLoopStream: %v", err)
+	}
+
+	invocations := env.toolExecutor.Invocations()
+	if len(invocations) != 2 {
+		t.Fatalf("tool invocations = %d, want 2", len(invocations))
+	}
+	if invocations[0].Name != "web_search" || invocations[0].Arguments["query"] != "новости ритейла" || invocations[0].Arguments["max_results"] != float64(3) {
+		t.Errorf("web_search invocation = %+v", invocations[0])
+	}
+	if invocations[1].Name != "save_organization_note" || invocations[1].Arguments["content"] != "Компания работает в ритейле" {
+		t.Errorf("save_organization_note invocation = %+v", invocations[1])
+	}
+
+	messages := env.chatManager.chatMessages(env.chat.ID)
+	assistant := messagesByRole(messages, domain.MessageRoleAssistant)
+	if len(assistant) != 2 {
+		t.Fatalf("assistant messages = %d, want 2", len(assistant))
+	}
+
+	toolCalls := assistant[0].ToolCalls
+	if len(toolCalls) != 2 {
+		t.Fatalf("tool calls = %d, want 2", len(toolCalls))
+	}
+	for _, tc := range toolCalls {
+		if !json.Valid(tc.Arguments) {
+			t.Errorf("tool call %s has invalid accumulated arguments: %s", tc.Name, tc.Arguments)
+		}
+		if tc.Status != domain.ToolCallStatusCompleted {
+			t.Errorf("tool call %s status = %s, want completed", tc.Name, tc.Status)
+		}
+	}
+
+	results := messagesByRole(messages, domain.MessageRoleTool)
+	if len(results) != 2 {
+		t.Fatalf("tool results = %d, want 2", len(results))
+	}
+	for i, result := range results {
+		if result.ToolCallID == nil || *result.ToolCallID != toolCalls[i].ID {
+			t.Errorf("tool result %d references %v, want %s", i, result.ToolCallID, toolCalls[i].ID)
+		}
+	}
+	if !strings.Contains(results[0].Content, `"results"`) {
+		t.Errorf("web_search result = %s", results[0].Content)
+	}
+
+	// Второй запрос должен содержать tool calls ассистента и результаты инструментов
+	requests := provider.Requests()
+	if len(requests) != 2 {
+		t.Fatalf("LLM requests = %d, want 2", len(requests))
+	}
+	history := requests[1].Messages
+	if len(history) != 5 {
+		t.Fatalf("second request history = %d messages, want 5", len(history))
+	}
+	if history[2].Role != llm.RoleAssistant || len(history[2].ToolCalls) != 2 {
+		t.Errorf("assistant turn in history = %+v", history[2])
+	}
+	if history[3].Role != llm.RoleTool || history[3].ToolCallID != history[2].ToolCalls[0].ID {
+		t.Errorf("tool result in history = %+v", history[3])
+	}
+
+	// Каждый tool call проходит pending -> executing -> completed
+	var statuses []domain.ToolCallStatus
+	for _, tc := range env.stream.toolCalls {
+		if tc.Name == "web_search" {
+			statuses = append(statuses, tc.Status)
+		}
+	}
+	want := []domain.ToolCallStatus{domain.ToolCallStatusPending, domain.ToolCallStatusExecuting, domain.ToolCallStatusCompleted}
+	if strings.Join(toStrings(statuses), ",") != strings.Join(toStrings(want), ",") {
+		t.Errorf("web_search status events = %v, want %v", statuses, want)
+	}
+}
+
+func TestRunAgentLoopStream_ToolError(t *testing.T) {
+	provider := fake.New(
+		fake.Call("web_search", map[string]any{"query": "курс рубля"}),
+		fake.Text("Поиск сейчас недоступен."),
+	)
+	env := newTestEnv(t, provider)
+	env.toolExecutor.errors["web_search"] = errors.New("tavily is down")
+
+	if err := env.run(t); err != nil {
+		t.Fatalf("runAgentLoopStream: %v", err)
+	}
+
+	results := messagesByRole(env.chatManager.chatMessages(env.chat.ID), domain.MessageRoleTool)
+	if len(results) != 1 || !strings.Contains(results[0].Content, "tavily is down") {
+		t.Fatalf("tool results = %+v, want one with the error", results)
+	}
+
+	assistant := messagesByRole(env.chatManager.chatMessages(env.chat.ID), domain.MessageRoleAssistant)
+	if assistant[0].ToolCalls[0].Status != domain.ToolCallStatusFailed {
+		t.Errorf("tool call status = %s, want failed", assistant[0].ToolCalls[0].Status)
+	}
+	if provider.Remaining() != 0 {
+		t.Errorf("unused turns = %d", provider.Remaining())
+	}
+}
+
+func TestRunAgentLoopStream_SubagentRoundTrip(t *testing.T) {
+	provider := fake.New(
+		fake.Call("switch_to_subagent", map[string]any{"subagent_key": "legal_agent", "task": "Проверь договор поставки"}),
+		fake.Call("finish_subagent", map[string]any{"summary": "Договор в порядке"}),
+		fake.Text("Юрист проверил договор, замечаний нет."),
+	)
+	env := newTestEnv(t, provider)
+
+	if err := env.run(t); err != nil {
+		t.Fatalf("runAgentLoopStream: %v", err)
+	}
+
+	if provider.Remaining() != 0 {
+		t.Fatalf("unused turns = %d", provider.Remaining())
+	}
+
+	invocations := env.toolExecutor.Invocations()
+	if len(invocations) != 2 {
+		t.Fatalf("tool invocations = %d, want 2", len(invocations))
+	}
+	if invocations[0].AgentKey != "main" || invocations[1].AgentKey != "legal_agent" {
+		t.Errorf("agents = %s, %s; want main, legal_agent", invocations[0].AgentKey, invocations[1].AgentKey)
+	}
+
+	subagentChatID := invocations[1].ChatID
+	subagentChat, err := env.chatManager.GetChat(context.Background(), subagentChatID)
+	if err != nil {
+		t.Fatalf("GetChat(subagent): %v", err)
+	}
+	if subagentChat.ParentChatID == nil || *subagentChat.ParentChatID != env.chat.ID {
+		t.Errorf("subagent parent = %v, want %s", subagentChat.ParentChatID, env.chat.ID)
+	}
+	if subagentChat.Status != domain.ChatStatusCompleted {
+		t.Errorf("subagent status = %s, want completed", subagentChat.Status)
+	}
+
+	// Субагент получил собственный system prompt и описание задачи
+	subagentMessages := env.chatManager.chatMessages(subagentChatID)
+	systemMessages := messagesByRole(subagentMessages, domain.MessageRoleSystem)
+	if len(systemMessages) != 2 || !strings.Contains(systemMessages[1].Content, "Проверь договор поставки") {
+		t.Errorf("subagent system messages = %+v", systemMessages)
+	}
+
+	// Результат субагента сохранен в родительский чат как ответ на switch_to_subagent
+	parentMessages := env.chatManager.chatMessages(env.chat.ID)
+	switchCall := messagesByRole(parentMessages, domain.MessageRoleAssistant)[0].ToolCalls[0]
+	results := messagesByRole(parentMessages, domain.MessageRoleTool)
+	if len(results) != 1 {
+		t.Fatalf("parent tool results = %d, want 1", len(results))
+	}
+	if results[0].ToolCallID == nil || *results[0].ToolCallID != switchCall.ID {
+		t.Errorf("parent tool result references %v, want %s", results[0].ToolCallID, switchCall.ID)
+	}
+	if !strings.Contains(results[0].Content, "Договор в порядке") {
+		t.Errorf("parent tool result = %s", results[0].Content)
+	}
+
+	requests := provider.Requests()
+	if !hasTool(requests[1].Tools, "finish_subagent") || hasTool(requests[1].Tools, "switch_to_subagent") {
+		t.Errorf("subagent tools = %v", toolNames(requests[1].Tools))
+	}
+	if !hasTool(requests[2].Tools, "switch_to_subagent") {
+		t.Errorf("main agent tools after return = %v", toolNames(requests[2].Tools))
+	}
+	if last := requests[2].Messages[len(requests[2].Messages)-1]; last.Role != llm.RoleTool {
+		t.Errorf("last message for main agent = %+v, want tool result", last)
+	}
+}
+
+func TestRunAgentLoopStream_SubagentPromptIncludesOrganizationProfile(t *testing.T) {
+	provider := fake.New(
+		fake.Call("switch_to_subagent", map[string]any{"subagent_key": "legal_agent", "task": "Проверь реквизиты в договоре"}),
+		fake.Call("finish_subagent", map[string]any{"summary": "Реквизиты совпадают"}),
+		fake.Text("Реквизиты в договоре верные."),
+	)
+	env := newTestEnv(t, provider)
+	profile := "\n\nПрофиль организации:\n- ИНН: 7707083893\n- Система налогообложения: ОСНО\n"
+	env.executor.contextBuilder = fakeContextBuilder{profile: profile}
+
+	if err := env.run(t); err != nil {
+		t.Fatalf("runAgentLoopStream: %v", err)
+	}
+
+	subagentChatID := env.toolExecutor.Invocations()[1].ChatID
+	systemMessages := messagesByRole(env.chatManager.chatMessages(subagentChatID), domain.MessageRoleSystem)
+	if len(systemMessages) == 0 || !strings.Contains(systemMessages[0].Content, profile) {
+		t.Errorf("subagent system prompt does not include organization profile: %+v", systemMessages)
+	}
+}
+
+func TestBuildSystemPromptWithoutOrganizationProfile(t *testing.T) {
+	env := newTestEnv(t, fake.New())
+	// Ошибка загрузки анкеты не должна срывать создание чата
+	env.executor.contextBuilder = fakeContextBuilder{
+		profile:    "\n\nПрофиль организации:\n- ИНН: 7707083893\n",
+		profileErr: errors.New("core-service unavailable"),
+	}
+
+	prompt, err := env.executor.buildSystemPromptWithRAG(context.Background(), env.chat, env.agentDef, "")
+	if err != nil {
+		t.Fatalf("buildSystemPromptWithRAG: %v", err)
+	}
+	if !strings.Contains(prompt, env.agentDef.GetSystemPrompt()) {
+		t.Errorf("system prompt = %q, want agent prompt", prompt)
+	}
+	if strings.Contains(prompt, "Профиль организации") {
+		t.Errorf("system prompt = %q, want no profile after error", prompt)
+	}
+}
+
+func TestRunAgentLoopStream_ToolsFilteredByUserPermissions(t *testing.T) {
+	provider := fake.New(
+		fake.Call("switch_to_subagent", map[string]any{"subagent_key": "legal_agent", "task": "Найди договор поставки"}),
+		fake.Call("finish_subagent", map[string]any{"summary": "Договор найден"}),
+		fake.Text("Договор нашелся."),
+	)
+	env := newTestEnv(t, provider)
+	// Сотрудник с собственной ролью, которому доступен только просмотр договоров
+	env.execCtx.Role = domain.OrganizationRoleEmployee
+	env.execCtx.Permissions = []domain.Permission{domain.PermissionContractsRead}
+
+	if err := env.run(t); err != nil {
+		t.Fatalf("runAgentLoopStream: %v", err)
+	}
+
+	requests := provider.Requests()
+	if len(requests) != 3 {
+		t.Fatalf("requests = %d, want 3", len(requests))
+	}
+	for _, i := range []int{0, 2} {
+		if hasTool(requests[i].Tools, "save_organization_note") || !hasTool(requests[i].Tools, "web_search") {
+			t.Errorf("main agent tools = %v, want web_search without save_organization_note", toolNames(requests[i].Tools))
+		}
+	}
+
+	// Субагент работает с ролью и правами того же пользователя
+	invocations := env.toolExecutor.Invocations()
+	if len(invocations) != 2 {
+		t.Fatalf("tool invocations = %d, want 2", len(invocations))
+	}
+	subagentCtx := invocations[1].ExecCtx
+	if subagentCtx.AgentKey != "legal_agent" || subagentCtx.Role != domain.OrganizationRoleEmployee {
+		t.Errorf("subagent context = %s/%s, want legal_agent/employee", subagentCtx.AgentKey, subagentCtx.Role)
+	}
+	if !subagentCtx.HasPermission(domain.PermissionContractsRead) || subagentCtx.HasPermission(domain.PermissionContractsGenerate) {
+		t.Errorf("subagent permissions = %v, want only contracts.read", subagentCtx.Permissions)
+	}
+}
+
+func TestRunAgentLoopStream_AdminSeesAllAgentTools(t *testing.T) {
+	provider := fake.New(fake.Text("Готово."))
+	env := newTestEnv(t, provider)
+	env.execCtx.Role = domain.OrganizationRoleAdmin
+
+	if err := env.run(t); err != nil {
+		t.Fatalf("runAgentLoopStream: %v", err)
+	}
+
+	tools := provider.Requests()[0].Tools
+	if !hasTool(tools, "save_organization_note") {
+		t.Errorf("admin tools = %v, want save_organization_note", toolNames(tools))
+	}
+}
+
+func TestRunAgentLoopStream_ProviderError(t *testing.T) {
+	providerErr := &llm.ProviderError{Provider: "fake", StatusCode: 503, Err: errors.New("unavailable")}
+	env := newTestEnv(t, fake.New(fake.Turn{Err: providerErr}))
+
+	err := env.run(t)
+	if !errors.Is(err, providerErr) {
+		t.Fatalf("error = %v, want provider error", err)
+	}
+	if len(env.stream.errors) != 1 {
+		t.Errorf("stream errors = %d, want 1", len(env.stream.errors))
+	}
+	if assistant := messagesByRole(env.chatManager.chatMessages(env.chat.ID), domain.MessageRoleAssistant); len(assistant) != 0 {
+		t.Errorf("assistant messages = %d, want 0", len(assistant))
+	}
+}
+
+func TestRunAgentLoopStream_StreamErrorAfterChunks(t *testing.T) {
+	env := newTestEnv(t, fake.New(fake.Turn{
+		Content:   "Начинаю отвечать",
+		StreamErr: errors.New("connection reset"),
+	}))
+
+	if err := env.run(t); err == nil || !strings.Contains(err.Error(), "connection reset") {
+		t.Fatalf("error = %v, want connection reset", err)
+	}
+	if len(env.stream.chunks) == 0 {
+		t.Error("expected chunks before the error")
+	}
+	if assistant := messagesByRole(env.chatManager.chatMessages(env.chat.ID), domain.MessageRoleAssistant); len(assistant) != 0 {
+		t.Errorf("assistant messages = %d, want 0", len(assistant))
+	}
+}
+
+// Кассета записана с помощью cassette.Recorder: поиск в вебе и финальный ответ
+func TestRunAgentLoopStream_ReplayCassette(t *testing.T) {
+	player, err := cassette.Open("testdata/web_search.json")
+	if err != nil {
+		t.Fatalf("cassette.Open: %v", err)
+	}
+	env := newTestEnv(t, player)
+
+	if err := env.run(t); err != nil {
+		t.Fatalf("runAgentLoopStream: %v", err)
+	}
+
+	if player.Remaining() != 0 {
+		t.Errorf("unplayed interactions = %d", player.Remaining())
+	}
+
+	invocations := env.toolExecutor.Invocations()
+	if len(invocations) != 1 || invocations[0].Name != "web_search" {
+		t.Fatalf("tool invocations = %+v, want one web_search", invocations)
+	}
+
+	assistant := messagesByRole(env.chatManager.chatMessages(env.chat.ID), domain.MessageRoleAssistant)
+	if len(assistant) != 2 || assistant[1].Content == "" {
+		t.Fatalf("assistant messages = %+v", assistant)
+	}
+	if len(env.stream.usage) != 2 {
+		t.Errorf("usage events = %d, want 2", len(env.stream.usage))
+	}
+}
+
+func TestRegenerateStream_NewBranch(t *testing.T) {
+	provider := fake.New(fake.Text("Первый ответ"), fake.Text("Второй ответ"))
+	env := newTestEnv(t, provider)
+	ctx := context.Background()
+
+	if err := env.run(t); err != nil {
+		t.Fatalf("runAgentLoopStream: %v", err)
+	}
+	first := messagesByRole(env.chatManager.chatMessages(env.chat.ID), domain.MessageRoleAssistant)[0]
+
+	err := env.executor.RegenerateStream(ctx, dto.RegenerateMessageDTO{
+		ChatID:    env.chat.ID,
+		MessageID: first.ID,
+		UserID:    env.chat.UserID,
+		OrgID:     env.chat.OrganizationID,
+	}, env.stream)
+	if err != nil {
+		t.Fatalf("RegenerateStream: %v", err)
+	}
+
+	branch := env.chatManager.chatMessages(env.chat.ID)
+	if len(branch) != 3 || branch[2].Content != "Второй ответ" {
+		t.Fatalf("active branch = %+v, want system, user and the new reply", branch)
+	}
+
+	requests := provider.Requests()
+	if len(requests) != 2 || len(requests[1].Messages) != 2 {
+		t.Fatalf("regenerate request must not include the old reply: %+v", requests)
+	}
+
+	tree, _ := env.chatManager.GetMessageTree(ctx, env.chat.ID)
+	if siblings := tree.Siblings(branch[2].ID); len(siblings) != 2 || siblings[0] != first.ID {
+		t.Errorf("siblings = %v, want old and new reply", siblings)
+	}
+
+	// Возврат к первой ветке
+	if err := env.chatManager.SwitchBranch(ctx, env.chat.ID, first.ID, env.chat.UserID, env.chat.OrganizationID); err != nil {
+		t.Fatalf("SwitchBranch: %v", err)
+	}
+	if branch := env.chatManager.chatMessages(env.chat.ID); branch[len(branch)-1].ID != first.ID {
+		t.Errorf("active message = %s, want %s", branch[len(branch)-1].ID, first.ID)
+	}
+}
+
+func TestEditMessageStream_NewBranch(t *testing.T) {
+	provider := fake.New(fake.Text("Ответ на исходный вопрос"), fake.Text("Ответ на новый вопрос"))
+	env := newTestEnv(t, provider)
+	ctx := context.Background()
+
+	if err := env.run(t); err != nil {
+		t.Fatalf("runAgentLoopStream: %v", err)
+	}
+	original := messagesByRole(env.chatManager.chatMessages(env.chat.ID), domain.MessageRoleUser)[0]
+
+	err := env.executor.EditMessageStream(ctx, dto.EditMessageDTO{
+		ChatID:    env.chat.ID,
+		MessageID: original.ID,
+		UserID:    env.chat.UserID,
+		OrgID:     env.chat.OrganizationID,
+		Content:   "Найди новости о конкурентах",
+	}, env.stream)
+	if err != nil {
+		t.Fatalf("EditMessageStream: %v", err)
+	}
+
+	branch := env.chatManager.chatMessages(env.chat.ID)
+	if len(branch) != 3 {
+		t.Fatalf("active branch length = %d, want 3", len(branch))
+	}
+	if branch[1].ID == original.ID || branch[1].Content != "Найди новости о конкурентах" {
+		t.Errorf("edited message = %+v", branch[1])
+	}
+	if branch[2].Content != "Ответ на новый вопрос" {
+		t.Errorf("reply = %q", branch[2].Content)
+	}
+
+	last := provider.Requests()[1].Messages
+	if len(last) != 2 || last[1].Content != "Найди новости о конкурентах" {
+		t.Errorf("edit request history = %+v", last)
+	}
+
+	// Ответ ассистента редактировать нельзя
+	err = env.executor.EditMessageStream(ctx, dto.EditMessageDTO{
+		ChatID:    env.chat.ID,
+		MessageID: branch[2].ID,
+		UserID:    env.chat.UserID,
+		OrgID:     env.chat.OrganizationID,
+		Content:   "текст",
+	}, env.stream)
+	if !errors.Is(err, domain.ErrInvalidArgument) {
+		t.Errorf("editing assistant message: err = %v, want invalid argument", err)
+	}
+}
+
+func hasTool(tools []llm.ToolDefinition, name string) bool {
+	for _, tool := range tools {
+		if tool.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+func toolNames(tools []llm.ToolDefinition) []string {
+	names := make([]string, 0, len(tools))
+	for _, tool := range tools {
+		names = append(names, tool.Name)
+	}
+	return names
+}
+
+func toStrings(statuses []domain.ToolCallStatus) []string {
+	result := make([]string, 0, len(statuses))
+	for _, s := range statuses {
+		result = append(result, string(s))
+	}
+	return result
+}
@@ -0,0 +1,398 @@
+package executor
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sync"
+
+	"llm-service/internal/domain"
+	"llm-service/internal/domain/dto"
+	"llm-service/internal/service"
+)
+
+// memChatManager - in-memory реализация service.ChatManager
+type memChatManager struct {
+	mu        sync.Mutex
+	chats     map[domain.ID]*domain.Chat
+	messages  []*domain.Message
+	toolCalls map[domain.ID]*domain.ToolCall
+	// active - последнее сообщение активной ветки каждого чата
+	active map[domain.ID]domain.ID
+}
+
+var _ service.ChatManager = (*memChatManager)(nil)
+
+func newMemChatManager() *memChatManager {
+	return &memChatManager{
+		chats:     make(map[domain.ID]*domain.Chat),
+		toolCalls: make(map[domain.ID]*domain.ToolCall),
+		active:    make(map[domain.ID]domain.ID),
+	}
+}
+
+func (m *memChatManager) CreateChat(_ context.Context, req dto.CreateChatDTO) (*domain.Chat, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	chat := domain.NewChat(req.OrganizationID, req.UserID, req.AgentKey, req.Title, req.ParentChatID, req.ParentToolCallID)
+	m.chats[chat.ID] = chat
+	return chat, nil
+}
+
+func (m *memChatManager) GetChat(_ context.Context, chatID domain.ID) (*domain.Chat, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	chat, ok := m.chats[chatID]
+	if !ok {
+		return nil, domain.NewNotFoundError(fmt.Sprintf("chat %s not found", chatID))
+	}
+	return chat, nil
+}
+
+func (m *memChatManager) ListChats(_ context.Context, organizationID, userID domain.ID, _, _ int) ([]*domain.Chat, int, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var chats []*domain.Chat
+	for _, chat := range m.chats {
+		if chat.OrganizationID == organizationID && chat.UserID == userID && chat.ParentChatID == nil {
+			chats = append(chats, chat)
+		}
+	}
+	return chats, len(chats), nil
+}
+
+func (m *memChatManager) SearchChats(context.Context, domain.ChatSearchQuery, int, int) ([]domain.ChatSearchHit, int, error) {
+	return nil, 0, nil
+}
+
+func (m *memChatManager) DeleteChat(_ context.Context, chatID, _, _ domain.ID) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	delete(m.chats, chatID)
+	return nil
+}
+
+func (m *memChatManager) UpdateChat(_ context.Context, chat *domain.Chat) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.chats[chat.ID] = chat
+	return nil
+}
+
+func (m *memChatManager) GetMessages(ctx context.Context, chatID, _, _ domain.ID, limit, offset int) ([]*domain.Message, int, error) {
+	messages := m.chatMessages(chatID)
+	total := len(messages)
+
+	if offset >= total {
+		return nil, total, nil
+	}
+	messages = messages[offset:]
+	if limit > 0 && limit < len(messages) {
+		messages = messages[:limit]
+	}
+	return messages, total, nil
+}
+
+func (m *memChatManager) SaveMessage(_ context.Context, message *domain.Message) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if message.ParentMessageID == nil {
+		if activeID, ok := m.active[message.ChatID]; ok {
+			message.ParentMessageID = &activeID
+		}
+	}
+	m.active[message.ChatID] = message.ID
+	m.messages = append(m.messages, message)
+	for _, tc := range message.ToolCalls {
+		m.toolCalls[tc.ID] = tc
+	}
+	return nil
+}
+
+func (m *memChatManager) UpdateMessageAttachments(_ context.Context, messageID domain.ID, attachments []domain.Attachment) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, msg := range m.messages {
+		if msg.ID == messageID {
+			msg.Attachments = attachments
+			return nil
+		}
+	}
+	return domain.NewNotFoundError(fmt.Sprintf("message %s not found", messageID))
+}
+
+func (m *memChatManager) UpdateToolCall(_ context.Context, toolCall *domain.ToolCall) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, ok := m.toolCalls[toolCall.ID]; !ok {
+		return domain.NewNotFoundError(fmt.Sprintf("tool call %s not found", toolCall.ID))
+	}
+	m.toolCalls[toolCall.ID] = toolCall
+	return nil
+}
+
+func (m *memChatManager) GetChatWithMessages(ctx context.Context, chatID domain.ID) (*domain.Chat, []*domain.Message, error) {
+	chat, err := m.GetChat(ctx, chatID)
+	if err != nil {
+		return nil, nil, err
+	}
+	return chat, m.chatMessages(chatID), nil
+}
+
+func (m *memChatManager) GetMessage(_ context.Context, messageID domain.ID) (*domain.Message, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, msg := range m.messages {
+		if msg.ID == messageID {
+			return msg, nil
+		}
+	}
+	return nil, domain.NewNotFoundError(fmt.Sprintf("message %s not found", messageID))
+}
+
+func (m *memChatManager) GetMessageTree(_ context.Context, chatID domain.ID) (*domain.MessageTree, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var links []domain.MessageLink
+	for _, msg := range m.messages {
+		if msg.ChatID == chatID {
+			links = append(links, domain.MessageLink{ID: msg.ID, ParentID: msg.ParentMessageID, Role: msg.Role, CreatedAt: msg.CreatedAt})
+		}
+	}
+	return domain.NewMessageTree(links), nil
+}
+
+func (m *memChatManager) SetActiveMessage(_ context.Context, chatID, messageID domain.ID) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.active[chatID] = messageID
+	return nil
+}
+
+func (m *memChatManager) PrepareBranch(ctx context.Context, chatID domain.ID) error {
+	for {
+		child, err := m.GetActiveChildChat(ctx, chatID)
+		if err != nil {
+			return nil
+		}
+		child.Archive()
+	}
+}
+
+func (m *memChatManager) SwitchBranch(ctx context.Context, chatID, messageID, _, _ domain.ID) error {
+	tree, err := m.GetMessageTree(ctx, chatID)
+	if err != nil {
+		return err
+	}
+	return m.SetActiveMessage(ctx, chatID, tree.LatestLeaf(messageID))
+}
+
+func (m *memChatManager) GetActiveChildChat(_ context.Context, parentChatID domain.ID) (*domain.Chat, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, chat := range m.chats {
+		if chat.ParentChatID != nil && *chat.ParentChatID == parentChatID && chat.Status == domain.ChatStatusActive {
+			return chat, nil
+		}
+	}
+	return nil, domain.NewNotFoundError("active child chat not found")
+}
+
+func (m *memChatManager) GetChatAccess(_ context.Context, chat *domain.Chat, userID, orgID domain.ID) (domain.ChatAccess, error) {
+	if chat.OrganizationID == orgID && chat.UserID == userID {
+		return domain.ChatAccessOwner, nil
+	}
+	return domain.ChatAccessNone, nil
+}
+
+func (m *memChatManager) ShareChat(context.Context, domain.ID, domain.ID, domain.ID, domain.ID, domain.ChatShareRole) (domain.ChatShare, error) {
+	return domain.ChatShare{}, domain.NewForbiddenError("sharing is not supported")
+}
+
+func (m *memChatManager) UnshareChat(context.Context, domain.ID, domain.ID, domain.ID, domain.ID) error {
+	return nil
+}
+
+func (m *memChatManager) ListChatShares(context.Context, domain.ID, domain.ID, domain.ID) ([]domain.ChatShare, error) {
+	return nil, nil
+}
+
+func (m *memChatManager) ListSharedChats(context.Context, domain.ID, domain.ID, int, int) ([]domain.SharedChat, int, error) {
+	return nil, 0, nil
+}
+
+func (m *memChatManager) ListOrganizationChats(context.Context, domain.ID, domain.ID, int, int) ([]*domain.Chat, int, error) {
+	return nil, 0, nil
+}
+
+func (m *memChatManager) chatMessages(chatID domain.ID) []*domain.Message {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	byID := make(map[domain.ID]*domain.Message, len(m.messages))
+	for _, msg := range m.messages {
+		byID[msg.ID] = msg
+	}
+
+	// Активная ветка: от последнего сообщения вверх по родителям
+	var messages []*domain.Message
+	id, ok := m.active[chatID]
+	for ok {
+		msg := byID[id]
+		messages = append(messages, msg)
+		if msg.ParentMessageID == nil {
+			break
+		}
+		id = *msg.ParentMessageID
+	}
+	slices.Reverse(messages)
+	return messages
+}
+
+// toolInvocation - вызов инструмента, полученный fakeToolExecutor
+type toolInvocation struct {
+	Name      string
+	Arguments map[string]interface{}
+	ChatID    domain.ID
+	AgentKey  string
+	ExecCtx   domain.ExecutionContext
+}
+
+// fakeToolExecutor выполняет системные инструменты субагентов через настоящий
+// SubagentManager, а для остальных возвращает заранее заданные результаты
+type fakeToolExecutor struct {
+	subagentManager service.SubagentManager
+
+	mu          sync.Mutex
+	results     map[string]interface{}
+	errors      map[string]error
+	invocations []toolInvocation
+}
+
+var _ service.ToolExecutor = (*fakeToolExecutor)(nil)
+
+func newFakeToolExecutor(subagentManager service.SubagentManager) *fakeToolExecutor {
+	return &fakeToolExecutor{
+		subagentManager: subagentManager,
+		results:         make(map[string]interface{}),
+		errors:          make(map[string]error),
+	}
+}
+
+func (f *fakeToolExecutor) Execute(ctx context.Context, toolName string, arguments map[string]interface{}, execCtx *domain.ExecutionContext, toolCallID *domain.ID) (interface{}, error) {
+	f.mu.Lock()
+	f.invocations = append(f.invocations, toolInvocation{
+		Name:      toolName,
+		Arguments: arguments,
+		ChatID:    execCtx.ChatID,
+		AgentKey:  execCtx.AgentKey,
+		ExecCtx:   *execCtx,
+	})
+	result, hasResult := f.results[toolName]
+	err := f.errors[toolName]
+	f.mu.Unlock()
+
+	switch domain.ToolName(toolName) {
+	case domain.ToolNameSwitchToSubagent:
+		subagentKey, _ := arguments["subagent_key"].(string)
+		task, _ := arguments["task"].(string)
+		childChat, err := f.subagentManager.SwitchToSubagent(ctx, execCtx.ChatID, subagentKey, task, toolCallID)
+		if err != nil {
+			return nil, err
+		}
+		return map[string]interface{}{"status": "subagent_started", "chat_id": childChat.ID}, nil
+	case domain.ToolNameFinishSubagent:
+		summary, _ := arguments["summary"].(string)
+		if err := f.subagentManager.FinishSubagent(ctx, execCtx.ChatID, summary); err != nil {
+			return nil, err
+		}
+		return map[string]interface{}{"status": "subagent_finished", "summary": summary}, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+	if !hasResult {
+		return map[string]interface{}{"ok": true}, nil
+	}
+	return result, nil
+}
+
+func (f *fakeToolExecutor) CanExecute(string, string) bool { return true }
+
+func (f *fakeToolExecutor) Invocations() []toolInvocation {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return slices.Clone(f.invocations)
+}
+
+// fakeContextBuilder возвращает пустой контекст, кроме заданного профиля организации
+type fakeContextBuilder struct {
+	profile    string
+	profileErr error
+}
+
+func (fakeContextBuilder) EnrichWithRAG(context.Context, domain.ID, string, int) (string, error) {
+	return "", nil
+}
+
+func (fakeContextBuilder) EnrichWithOrganizationFacts(context.Context, domain.ID) (string, error) {
+	return "", nil
+}
+
+func (f fakeContextBuilder) EnrichWithOrganizationProfile(context.Context, domain.ID) (string, error) {
+	return f.profile, f.profileErr
+}
+
+// recordingStream собирает все события стрима для проверок
+type recordingStream struct {
+	chunks    []string
+	messages  []*domain.Message
+	toolCalls []domain.ToolCall
+	usage     []*dto.ChatUsageDTO
+	errors    []error
+}
+
+var _ service.MessageStream = (*recordingStream)(nil)
+
+func (s *recordingStream) SendChunk(content string) error {
+	s.chunks = append(s.chunks, content)
+	return nil
+}
+
+func (s *recordingStream) SendMessage(message *domain.Message) error {
+	s.messages = append(s.messages, message)
+	return nil
+}
+
+func (s *recordingStream) SendToolCall(toolCall *domain.ToolCall) error {
+	// Копия, т.к. executor меняет статус того же объекта
+	s.toolCalls = append(s.toolCalls, *toolCall)
+	return nil
+}
+
+func (s *recordingStream) SendUsage(usage *dto.ChatUsageDTO) error {
+	s.usage = append(s.usage, usage)
+	return nil
+}
+
+func (s *recordingStream) SendError(err error) error {
+	s.errors = append(s.errors, err)
+	return err
+}
+
+func (s *recordingStream) SendChat(*domain.Chat) error { return nil }
+
+func (s *recordingStream) SendFinal(*domain.Chat, []*domain.Message) error { return nil }
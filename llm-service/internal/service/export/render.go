@@ -0,0 +1,158 @@
+package export
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"strings"
+
+	"llm-service/internal/docx"
+
+	"github.com/go-pdf/fpdf"
+)
+
+// renderMarkdown - сообщения агентов уже в Markdown, поэтому выводятся как есть
+func renderMarkdown(t transcript) string {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "# %s\n\n", t.Title)
+	fmt.Fprintf(&b, "_Чат от %s, экспорт %s_\n", formatTime(t.CreatedAt), formatTime(t.ExportedAt))
+
+	for _, e := range t.Entries {
+		var section strings.Builder
+
+		fmt.Fprintf(&section, "**%s** · %s\n", e.Speaker, formatTime(e.Time))
+		if e.Text != "" {
+			fmt.Fprintf(&section, "\n%s\n", e.Text)
+		}
+		if len(e.Attachments) > 0 {
+			fmt.Fprintf(&section, "\n_Вложения: %s_\n", strings.Join(e.Attachments, ", "))
+		}
+		if len(e.Tools) > 0 {
+			section.WriteString("\n")
+			for _, tool := range e.Tools {
+				fmt.Fprintf(&section, "- _%s_\n", tool)
+			}
+		}
+
+		b.WriteString("\n---\n\n")
+		if e.Subagent {
+			// Переписка субагента выводится цитатой
+			for _, line := range strings.Split(strings.TrimRight(section.String(), "\n"), "\n") {
+				b.WriteString(strings.TrimRight("> "+line, " ") + "\n")
+			}
+		} else {
+			b.WriteString(section.String())
+		}
+	}
+
+	return b.String()
+}
+
+func renderDOCX(t transcript) *docx.Document {
+	doc := &docx.Document{}
+
+	doc.Add(docx.StyleTitle, 0, docx.Run{Text: t.Title})
+	doc.Add(docx.StyleNormal, 0, docx.Run{
+		Text:   fmt.Sprintf("Чат от %s, экспорт %s", formatTime(t.CreatedAt), formatTime(t.ExportedAt)),
+		Italic: true,
+	})
+
+	for _, e := range t.Entries {
+		indent := 0
+		if e.Subagent {
+			indent = 1
+		}
+
+		doc.Add(docx.StyleHeading3, indent, docx.Run{Text: fmt.Sprintf("%s · %s", e.Speaker, formatTime(e.Time))})
+		for _, block := range plainBlocks(e.Text) {
+			doc.Add(docx.StyleNormal, indent, docx.Run{Text: block.Text, Bold: block.Heading})
+		}
+		if len(e.Attachments) > 0 {
+			doc.Add(docx.StyleNormal, indent, docx.Run{Text: "Вложения: " + strings.Join(e.Attachments, ", "), Italic: true})
+		}
+		for _, tool := range e.Tools {
+			doc.Add(docx.StyleNormal, indent, docx.Run{Text: "→ " + tool, Italic: true})
+		}
+	}
+
+	return doc
+}
+
+const (
+	pdfFontFamily  = "DejaVu"
+	pdfMargin      = 20.0
+	pdfIndent      = 8.0
+	pdfLineHeight  = 5.0
+	pdfTitleSize   = 16
+	pdfHeaderSize  = 10
+	pdfBodySize    = 10
+	pdfCaptionSize = 9
+)
+
+// renderPDF рендерит PDF; встроенные шрифты fpdf не поддерживают кириллицу, поэтому нужны TTF из конфига
+func renderPDF(t transcript, fontPath, boldFontPath string) ([]byte, error) {
+	pdf := fpdf.New("P", "mm", "A4", "")
+	pdf.SetMargins(pdfMargin, pdfMargin, pdfMargin)
+	pdf.SetAutoPageBreak(true, pdfMargin)
+	for style, path := range map[string]string{"": fontPath, "B": boldFontPath} {
+		font, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read PDF font: %w", err)
+		}
+		pdf.AddUTF8FontFromBytes(pdfFontFamily, style, font)
+	}
+	if err := pdf.Error(); err != nil {
+		return nil, fmt.Errorf("failed to load PDF fonts: %w", err)
+	}
+
+	pdf.SetTitle(t.Title, true)
+	pdf.AddPage()
+
+	pdf.SetFont(pdfFontFamily, "B", pdfTitleSize)
+	pdf.MultiCell(0, 8, t.Title, "", "L", false)
+	pdf.SetFont(pdfFontFamily, "", pdfCaptionSize)
+	pdf.SetTextColor(110, 110, 110)
+	pdf.MultiCell(0, pdfLineHeight, fmt.Sprintf("Чат от %s, экспорт %s", formatTime(t.CreatedAt), formatTime(t.ExportedAt)), "", "L", false)
+	pdf.SetTextColor(0, 0, 0)
+
+	for _, e := range t.Entries {
+		left := pdfMargin
+		if e.Subagent {
+			left += pdfIndent
+		}
+		pdf.SetLeftMargin(left)
+		pdf.SetX(left)
+		pdf.Ln(3)
+
+		pdf.SetFont(pdfFontFamily, "B", pdfHeaderSize)
+		pdf.MultiCell(0, pdfLineHeight, fmt.Sprintf("%s · %s", e.Speaker, formatTime(e.Time)), "", "L", false)
+
+		for _, block := range plainBlocks(e.Text) {
+			style := ""
+			if block.Heading {
+				style = "B"
+			}
+			pdf.SetFont(pdfFontFamily, style, pdfBodySize)
+			pdf.MultiCell(0, pdfLineHeight, block.Text, "", "L", false)
+			pdf.Ln(1)
+		}
+
+		pdf.SetFont(pdfFontFamily, "", pdfCaptionSize)
+		pdf.SetTextColor(110, 110, 110)
+		if len(e.Attachments) > 0 {
+			pdf.MultiCell(0, pdfLineHeight, "Вложения: "+strings.Join(e.Attachments, ", "), "", "L", false)
+		}
+		for _, tool := range e.Tools {
+			pdf.MultiCell(0, pdfLineHeight, "→ "+tool, "", "L", false)
+		}
+		pdf.SetTextColor(0, 0, 0)
+	}
+
+	var buf bytes.Buffer
+	if err := pdf.Output(&buf); err != nil {
+		return nil, fmt.Errorf("failed to write PDF: %w", err)
+	}
+
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,196 @@
+package export
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"llm-service/internal/coreservice"
+	"llm-service/internal/docx"
+	"llm-service/internal/domain"
+	"llm-service/internal/logger"
+
+	"github.com/opentracing/opentracing-go"
+)
+
+// exportPageSize - размер страницы при чтении истории чата
+const exportPageSize = 500
+
+type chatReader interface {
+	GetChat(ctx context.Context, chatID domain.ID) (*domain.Chat, error)
+	GetChatAccess(ctx context.Context, chat *domain.Chat, userID, orgID domain.ID) (domain.ChatAccess, error)
+	GetMessages(ctx context.Context, chatID, userID, orgID domain.ID, limit, offset int) ([]*domain.Message, int, error)
+}
+
+type agentDirectory interface {
+	GetAgent(agentKey string) (*domain.AgentDefinition, error)
+}
+
+type objectStorage interface {
+	PutObject(ctx context.Context, key string, data []byte, contentType string) error
+}
+
+type coreClient interface {
+	RegisterDocument(ctx context.Context, organizationID, name, s3Key, fileType string, fileSize int64) (*coreservice.Document, error)
+	GenerateDownloadURL(ctx context.Context, s3Key string) (string, error)
+	ListOrganizationMembers(ctx context.Context, organizationID string) ([]*coreservice.Member, error)
+}
+
+type fontConfig interface {
+	GetExportPDFFontPath() string
+	GetExportPDFBoldFontPath() string
+}
+
+// Service экспортирует чаты в Markdown, DOCX и PDF
+type Service struct {
+	chats   chatReader
+	agents  agentDirectory
+	storage objectStorage
+	core    coreClient
+	docx    *docx.Processor
+	fonts   fontConfig
+}
+
+func New(chats chatReader, agents agentDirectory, storage objectStorage, core coreClient, docxProcessor *docx.Processor, fonts fontConfig) *Service {
+	return &Service{
+		chats:   chats,
+		agents:  agents,
+		storage: storage,
+		core:    core,
+		docx:    docxProcessor,
+		fonts:   fonts,
+	}
+}
+
+// Export рендерит основной чат в файл, сохраняет его в S3 и возвращает ссылку на скачивание.
+// Для чата субагента экспортируется основной чат.
+func (s *Service) Export(ctx context.Context, organizationID, userID, chatID domain.ID, opts domain.ChatExportOptions) (*domain.ChatExport, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "service.export.Export")
+	defer span.Finish()
+
+	if !opts.Format.IsValid() {
+		return nil, domain.NewInvalidArgumentError("unsupported export format")
+	}
+
+	chat, err := s.chats.GetChat(ctx, chatID)
+	if err != nil {
+		return nil, err
+	}
+	if chat.ParentChatID != nil {
+		chat, err = s.chats.GetChat(ctx, *chat.ParentChatID)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	access, err := s.chats.GetChatAccess(ctx, chat, userID, organizationID)
+	if err != nil {
+		return nil, err
+	}
+	if !access.CanRead() {
+		return nil, domain.NewNotFoundError("chat not found")
+	}
+
+	messages, err := s.loadMessages(ctx, chat.ID, userID, organizationID)
+	if err != nil {
+		return nil, err
+	}
+
+	t := s.buildTranscript(ctx, chat, messages, opts.IncludeSubagents)
+
+	var data []byte
+	switch opts.Format {
+	case domain.ChatExportFormatMarkdown:
+		data = []byte(renderMarkdown(t))
+	case domain.ChatExportFormatDOCX:
+		data, err = s.docx.Render(ctx, renderDOCX(t))
+	case domain.ChatExportFormatPDF:
+		data, err = renderPDF(t, s.fonts.GetExportPDFFontPath(), s.fonts.GetExportPDFBoldFontPath())
+	}
+	if err != nil {
+		return nil, domain.NewInternalError("failed to render chat export", err)
+	}
+
+	result := &domain.ChatExport{
+		S3Key:       fmt.Sprintf("exports/%s/%s%s", organizationID, domain.NewID(), opts.Format.Extension()),
+		FileName:    exportFileName(chat, opts.Format),
+		ContentType: opts.Format.ContentType(),
+		Size:        int64(len(data)),
+	}
+
+	if err := s.storage.PutObject(ctx, result.S3Key, data, result.ContentType); err != nil {
+		return nil, domain.NewInternalError("failed to save chat export", err)
+	}
+
+	if opts.RegisterDocument {
+		document, err := s.core.RegisterDocument(ctx,
+			organizationID.String(),
+			result.FileName,
+			result.S3Key,
+			opts.Format.DocumentType(),
+			result.Size,
+		)
+		if err != nil {
+			return nil, domain.NewInternalError("failed to register document", err)
+		}
+		result.DocumentID = document.ID
+	}
+
+	result.DownloadURL, err = s.core.GenerateDownloadURL(ctx, result.S3Key)
+	if err != nil {
+		return nil, domain.NewInternalError("failed to generate download url", err)
+	}
+
+	logger.Info(ctx, "chat exported",
+		"chat_id", chat.ID,
+		"format", opts.Format,
+		"messages", len(messages),
+		"size", result.Size,
+		"document_id", result.DocumentID,
+	)
+
+	return result, nil
+}
+
+// loadMessages читает всю историю чата вместе с чатами субагентов в хронологическом порядке
+func (s *Service) loadMessages(ctx context.Context, chatID, userID, organizationID domain.ID) ([]*domain.Message, error) {
+	var messages []*domain.Message
+	for {
+		page, total, err := s.chats.GetMessages(ctx, chatID, userID, organizationID, exportPageSize, len(messages))
+		if err != nil {
+			return nil, err
+		}
+		messages = append(messages, page...)
+		if len(page) == 0 || len(messages) >= total {
+			break
+		}
+	}
+
+	slices.SortStableFunc(messages, func(a, b *domain.Message) int {
+		return a.CreatedAt.Compare(b.CreatedAt)
+	})
+
+	return messages, nil
+}
+
+// exportFileName формирует имя файла из названия чата
+func exportFileName(chat *domain.Chat, format domain.ChatExportFormat) string {
+	name := strings.Map(func(r rune) rune {
+		switch r {
+		case '/', '\\', ':', '*', '?', '"', '<', '>', '|':
+			return '_'
+		}
+		return r
+	}, strings.TrimSpace(chat.Title))
+
+	if runes := []rune(name); len(runes) > 100 {
+		name = string(runes[:100])
+	}
+	if name == "" {
+		name = "chat"
+	}
+
+	return fmt.Sprintf("%s (%s)%s", name, time.Now().UTC().Format("2006-01-02"), format.Extension())
+}
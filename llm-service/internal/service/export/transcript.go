@@ -0,0 +1,225 @@
+package export
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"llm-service/internal/domain"
+	"llm-service/internal/logger"
+)
+
+// maxToolArgumentLength - длина аргумента инструмента в сводке
+const maxToolArgumentLength = 200
+
+// transcript - чат, подготовленный к рендерингу в любой формат
+type transcript struct {
+	Title      string
+	CreatedAt  time.Time
+	ExportedAt time.Time
+	Entries    []entry
+}
+
+// entry - одно сообщение переписки
+type entry struct {
+	Speaker string
+	Time    time.Time
+	Text    string
+	// Subagent - сообщение из чата субагента, выводится с отступом
+	Subagent    bool
+	Attachments []string
+	// Tools - читаемые сводки вызовов инструментов
+	Tools []string
+}
+
+// buildTranscript собирает переписку: системные промпты и сырые результаты инструментов
+// не выводятся, вызовы инструментов сворачиваются в короткие сводки
+func (s *Service) buildTranscript(ctx context.Context, chat *domain.Chat, messages []*domain.Message, includeSubagents bool) transcript {
+	t := transcript{
+		Title:      chat.Title,
+		CreatedAt:  chat.CreatedAt,
+		ExportedAt: time.Now().UTC(),
+	}
+
+	authors := s.memberNames(ctx, chat.OrganizationID)
+
+	for _, msg := range messages {
+		subagent := msg.ChatID != chat.ID
+		if subagent && !includeSubagents {
+			continue
+		}
+
+		e := entry{
+			Time:     msg.CreatedAt,
+			Text:     strings.TrimSpace(msg.Content),
+			Subagent: subagent,
+		}
+
+		switch msg.Role {
+		case domain.MessageRoleUser:
+			e.Speaker = "Пользователь"
+			if msg.AuthorUserID != nil {
+				if name, ok := authors[msg.AuthorUserID.String()]; ok {
+					e.Speaker = name
+				}
+			}
+			for _, a := range msg.Attachments {
+				e.Attachments = append(e.Attachments, a.FileName)
+				if a.IsTranscribed() {
+					e.Text = strings.TrimSpace(e.Text + "\n\nГолосовое сообщение: «" + a.Text + "»")
+				}
+			}
+		case domain.MessageRoleAssistant:
+			e.Speaker = "Ассистент"
+			if msg.Sender != nil {
+				e.Speaker = s.agentName(*msg.Sender)
+			}
+			for _, tc := range msg.ToolCalls {
+				e.Tools = append(e.Tools, s.summarizeToolCall(tc))
+			}
+		default:
+			continue
+		}
+
+		if e.Text == "" && len(e.Tools) == 0 && len(e.Attachments) == 0 {
+			continue
+		}
+
+		t.Entries = append(t.Entries, e)
+	}
+
+	return t
+}
+
+// memberNames возвращает имена сотрудников организации по user_id; ошибка не прерывает экспорт
+func (s *Service) memberNames(ctx context.Context, organizationID domain.ID) map[string]string {
+	members, err := s.core.ListOrganizationMembers(ctx, organizationID.String())
+	if err != nil {
+		logger.Warn(ctx, "failed to load organization members for chat export", "error", err)
+		return nil
+	}
+
+	names := make(map[string]string, len(members))
+	for _, m := range members {
+		name := strings.TrimSpace(m.FirstName + " " + m.LastName)
+		if name == "" {
+			name = m.Email
+		}
+		if name != "" {
+			names[m.UserID] = name
+		}
+	}
+	return names
+}
+
+func (s *Service) agentName(key string) string {
+	agent, err := s.agents.GetAgent(key)
+	if err != nil || agent.Name == "" {
+		return key
+	}
+	return agent.Name
+}
+
+// summarizeToolCall описывает вызов инструмента одной строкой
+func (s *Service) summarizeToolCall(tc *domain.ToolCall) string {
+	var args map[string]interface{}
+	_ = json.Unmarshal(tc.Arguments, &args)
+
+	arg := func(name string) string {
+		value, _ := args[name].(string)
+		value = strings.Join(strings.Fields(value), " ")
+		if runes := []rune(value); len(runes) > maxToolArgumentLength {
+			value = string(runes[:maxToolArgumentLength]) + "…"
+		}
+		return value
+	}
+
+	var summary string
+	switch domain.ToolName(tc.Name) {
+	case domain.ToolNameWebSearch:
+		summary = fmt.Sprintf("Веб-поиск: «%s»", arg("query"))
+	case domain.ToolNameSearchChatFiles:
+		summary = fmt.Sprintf("Поиск по файлам чата: «%s»", arg("query"))
+	case domain.ToolNameSaveOrganizationNote:
+		summary = fmt.Sprintf("Сохранен факт об организации: «%s»", arg("content"))
+	case domain.ToolNameSearchContractTemplates:
+		summary = fmt.Sprintf("Поиск шаблонов договоров: «%s»", arg("query"))
+	case domain.ToolNameGenerateContract:
+		summary = fmt.Sprintf("Сформирован договор «%s»", arg("contract_name"))
+	case domain.ToolNameListGeneratedContracts:
+		summary = "Просмотр списка договоров организации"
+	case domain.ToolNameSwitchToSubagent:
+		summary = fmt.Sprintf("Передача задачи агенту «%s»: %s", s.agentName(arg("subagent_key")), arg("task"))
+	case domain.ToolNameFinishSubagent:
+		summary = fmt.Sprintf("Агент завершил работу: %s", arg("summary"))
+	default:
+		if domain.IsMCPTool(tc.Name) {
+			summary = fmt.Sprintf("Запрос к AmoCRM (%s)", strings.TrimPrefix(tc.Name, domain.AmoCRMMCPToolPrefix))
+		} else {
+			summary = fmt.Sprintf("Вызов инструмента %s", tc.Name)
+		}
+	}
+
+	switch tc.Status {
+	case domain.ToolCallStatusFailed:
+		summary += " — ошибка"
+	case domain.ToolCallStatusPending, domain.ToolCallStatusExecuting:
+		summary += " — не завершен"
+	}
+
+	return summary
+}
+
+// textBlock - абзац текста сообщения без Markdown-разметки
+type textBlock struct {
+	Text    string
+	Heading bool
+}
+
+// plainBlocks разбивает Markdown ответа агента на абзацы для DOCX и PDF:
+// заголовки выделяются, маркеры списков заменяются на «•», выделение и ограждения кода убираются
+func plainBlocks(content string) []textBlock {
+	var (
+		blocks []textBlock
+		lines  []string
+	)
+	flush := func() {
+		if len(lines) > 0 {
+			blocks = append(blocks, textBlock{Text: strings.Join(lines, "\n")})
+			lines = nil
+		}
+	}
+
+	for _, line := range strings.Split(content, "\n") {
+		trimmed := strings.TrimSpace(line)
+		switch {
+		case trimmed == "":
+			flush()
+		case strings.HasPrefix(trimmed, "```"):
+			continue
+		case strings.HasPrefix(trimmed, "#"):
+			flush()
+			blocks = append(blocks, textBlock{Text: stripInline(strings.TrimLeft(trimmed, "# ")), Heading: true})
+		case strings.HasPrefix(trimmed, "- "), strings.HasPrefix(trimmed, "* "):
+			lines = append(lines, "• "+stripInline(trimmed[2:]))
+		default:
+			lines = append(lines, stripInline(strings.TrimRight(line, " ")))
+		}
+	}
+	flush()
+
+	return blocks
+}
+
+var inlineMarkup = strings.NewReplacer("**", "", "__", "", "`", "")
+
+func stripInline(s string) string {
+	return inlineMarkup.Replace(s)
+}
+
+// formatTime форматирует время для экспорта
+func formatTime(t time.Time) string {
+	return t.UTC().Format("02.01.2006 15:04 UTC")
+}
@@ -0,0 +1,187 @@
+package feedback
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"llm-service/internal/domain"
+	"llm-service/internal/logger"
+
+	"github.com/opentracing/opentracing-go"
+)
+
+const (
+	// defaultReportPeriod - период отчета, если границы не заданы
+	defaultReportPeriod = 30 * 24 * time.Hour
+	// defaultCommentsLimit, maxCommentsLimit - количество отрицательных отзывов с комментариями в отчете
+	defaultCommentsLimit = 20
+	maxCommentsLimit     = 100
+)
+
+type feedbackRepository interface {
+	UpsertMessageFeedback(ctx context.Context, feedback *domain.MessageFeedback) error
+	ListUserMessageFeedback(ctx context.Context, userID domain.ID, messageIDs []domain.ID) ([]domain.MessageFeedback, error)
+	GetFeedbackReport(ctx context.Context, query domain.FeedbackReportQuery) (domain.FeedbackReport, error)
+}
+
+type chatReader interface {
+	GetChat(ctx context.Context, chatID domain.ID) (*domain.Chat, error)
+	GetChatAccess(ctx context.Context, chat *domain.Chat, userID, orgID domain.ID) (domain.ChatAccess, error)
+	GetMessage(ctx context.Context, messageID domain.ID) (*domain.Message, error)
+}
+
+type agentDirectory interface {
+	GetAgent(agentKey string) (*domain.AgentDefinition, error)
+}
+
+// Service собирает оценки ответов ассистента и строит по ним отчеты
+type Service struct {
+	repo   feedbackRepository
+	chats  chatReader
+	agents agentDirectory
+}
+
+func New(repo feedbackRepository, chats chatReader, agents agentDirectory) *Service {
+	return &Service{
+		repo:   repo,
+		chats:  chats,
+		agents: agents,
+	}
+}
+
+// RateMessage сохраняет оценку ответа ассистента. Оценить ответ может любой, кто видит чат,
+// включая ответы субагентов; повторная оценка заменяет предыдущую.
+func (s *Service) RateMessage(ctx context.Context, feedback domain.MessageFeedback, chatID domain.ID) (domain.MessageFeedback, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "service.feedback.RateMessage")
+	defer span.Finish()
+
+	if !feedback.Rating.IsValid() {
+		return domain.MessageFeedback{}, domain.NewInvalidArgumentError("rating is required")
+	}
+
+	var reasons []domain.FeedbackReason
+	for _, reason := range feedback.Reasons {
+		if !reason.IsValid() {
+			return domain.MessageFeedback{}, domain.NewInvalidArgumentError("unknown feedback reason: " + string(reason))
+		}
+		if !slices.Contains(reasons, reason) {
+			reasons = append(reasons, reason)
+		}
+	}
+	feedback.Reasons = reasons
+
+	feedback.Comment = strings.TrimSpace(feedback.Comment)
+	if utf8.RuneCountInString(feedback.Comment) > domain.MaxFeedbackCommentLength {
+		return domain.MessageFeedback{}, domain.NewInvalidArgumentError("feedback comment is too long")
+	}
+
+	message, err := s.chats.GetMessage(ctx, feedback.MessageID)
+	if err != nil {
+		return domain.MessageFeedback{}, err
+	}
+	if message.Role != domain.MessageRoleAssistant {
+		return domain.MessageFeedback{}, domain.NewInvalidArgumentError("only assistant messages can be rated")
+	}
+
+	chat, err := s.chats.GetChat(ctx, message.ChatID)
+	if err != nil {
+		return domain.MessageFeedback{}, err
+	}
+
+	// Ответ субагента оценивается из основного чата, в котором он показан
+	rootChatID := chat.ID
+	if chat.ParentChatID != nil {
+		rootChatID = *chat.ParentChatID
+	}
+	if chatID != chat.ID && chatID != rootChatID {
+		return domain.MessageFeedback{}, domain.NewNotFoundError("message not found")
+	}
+
+	access, err := s.chats.GetChatAccess(ctx, chat, feedback.UserID, feedback.OrganizationID)
+	if err != nil {
+		return domain.MessageFeedback{}, err
+	}
+	if !access.CanRead() {
+		return domain.MessageFeedback{}, domain.NewNotFoundError("chat not found")
+	}
+
+	feedback.ChatID = rootChatID
+	feedback.AgentKey = chat.AgentKey
+	if message.Sender != nil {
+		feedback.AgentKey = *message.Sender
+	}
+	feedback.UpdatedAt = time.Now().UTC()
+
+	if err := s.repo.UpsertMessageFeedback(ctx, &feedback); err != nil {
+		return domain.MessageFeedback{}, err
+	}
+
+	logger.Info(ctx, "message rated",
+		"message_id", feedback.MessageID,
+		"agent_key", feedback.AgentKey,
+		"rating", feedback.Rating,
+		"reasons", feedback.Reasons,
+		"tools", feedback.ToolNames,
+	)
+
+	return feedback, nil
+}
+
+// GetUserFeedback возвращает оценки пользователя для сообщений по их ID
+func (s *Service) GetUserFeedback(ctx context.Context, userID domain.ID, messageIDs []domain.ID) (map[domain.ID]domain.MessageFeedback, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "service.feedback.GetUserFeedback")
+	defer span.Finish()
+
+	list, err := s.repo.ListUserMessageFeedback(ctx, userID, messageIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make(map[domain.ID]domain.MessageFeedback, len(list))
+	for _, feedback := range list {
+		result[feedback.MessageID] = feedback
+	}
+	return result, nil
+}
+
+// GetReport строит отчет по оценкам организации. Право analytics.view на отчет
+// проверяет интерсептор авторизации по политике доступа RPC.
+func (s *Service) GetReport(ctx context.Context, query domain.FeedbackReportQuery) (domain.FeedbackReport, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "service.feedback.GetReport")
+	defer span.Finish()
+
+	if query.To.IsZero() {
+		query.To = time.Now().UTC()
+	}
+	if query.From.IsZero() {
+		query.From = query.To.Add(-defaultReportPeriod)
+	}
+	if !query.From.Before(query.To) {
+		return domain.FeedbackReport{}, domain.NewInvalidArgumentError("report period start must be before its end")
+	}
+
+	switch {
+	case query.CommentsLimit <= 0:
+		query.CommentsLimit = defaultCommentsLimit
+	case query.CommentsLimit > maxCommentsLimit:
+		query.CommentsLimit = maxCommentsLimit
+	}
+
+	report, err := s.repo.GetFeedbackReport(ctx, query)
+	if err != nil {
+		return domain.FeedbackReport{}, err
+	}
+
+	report.From, report.To = query.From, query.To
+	for i := range report.Agents {
+		report.Agents[i].AgentName = report.Agents[i].AgentKey
+		if agent, err := s.agents.GetAgent(report.Agents[i].AgentKey); err == nil && agent.Name != "" {
+			report.Agents[i].AgentName = agent.Name
+		}
+	}
+
+	return report, nil
+}
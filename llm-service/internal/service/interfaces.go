@@ -18,6 +18,9 @@ type ChatManager interface {
 	// ListChats получает список чатов пользователя
 	ListChats(ctx context.Context, organizationID, userID domain.ID, page, pageSize int) ([]*domain.Chat, int, error)
 
+	// SearchChats выполняет полнотекстовый поиск по чатам пользователя
+	SearchChats(ctx context.Context, query domain.ChatSearchQuery, page, pageSize int) ([]domain.ChatSearchHit, int, error)
+
 	// DeleteChat удаляет чат
 	DeleteChat(ctx context.Context, chatID, userID, orgID domain.ID) error
 
@@ -30,14 +33,50 @@ type ChatManager interface {
 	// SaveMessage сохраняет сообщение
 	SaveMessage(ctx context.Context, message *domain.Message) error
 
+	// UpdateMessageAttachments обновляет вложения сообщения
+	UpdateMessageAttachments(ctx context.Context, messageID domain.ID, attachments []domain.Attachment) error
+
 	// UpdateToolCall обновляет статус tool call
 	UpdateToolCall(ctx context.Context, toolCall *domain.ToolCall) error
 
-	// GetChatWithMessages получает чат со всеми сообщениями
+	// GetChatWithMessages получает чат с сообщениями активной ветки
 	GetChatWithMessages(ctx context.Context, chatID domain.ID) (*domain.Chat, []*domain.Message, error)
 
+	// GetMessage получает сообщение по ID
+	GetMessage(ctx context.Context, messageID domain.ID) (*domain.Message, error)
+
+	// GetMessageTree получает дерево сообщений чата со всеми ветками
+	GetMessageTree(ctx context.Context, chatID domain.ID) (*domain.MessageTree, error)
+
+	// SetActiveMessage делает сообщение последним в активной ветке чата
+	SetActiveMessage(ctx context.Context, chatID, messageID domain.ID) error
+
+	// PrepareBranch архивирует сессию субагента перед сменой ветки
+	PrepareBranch(ctx context.Context, chatID domain.ID) error
+
+	// SwitchBranch переключает чат на ветку, содержащую сообщение
+	SwitchBranch(ctx context.Context, chatID, messageID, userID, orgID domain.ID) error
+
 	// GetActiveChildChat получает активный дочерний чат
 	GetActiveChildChat(ctx context.Context, parentChatID domain.ID) (*domain.Chat, error)
+
+	// GetChatAccess определяет уровень доступа пользователя к чату
+	GetChatAccess(ctx context.Context, chat *domain.Chat, userID, orgID domain.ID) (domain.ChatAccess, error)
+
+	// ShareChat выдает сотруднику доступ к чату
+	ShareChat(ctx context.Context, chatID, ownerID, orgID, userID domain.ID, role domain.ChatShareRole) (domain.ChatShare, error)
+
+	// UnshareChat отзывает доступ к чату
+	UnshareChat(ctx context.Context, chatID, requesterID, orgID, userID domain.ID) error
+
+	// ListChatShares получает список доступов к чату
+	ListChatShares(ctx context.Context, chatID, userID, orgID domain.ID) ([]domain.ChatShare, error)
+
+	// ListSharedChats получает чаты, которыми поделились с пользователем
+	ListSharedChats(ctx context.Context, organizationID, userID domain.ID, page, pageSize int) ([]domain.SharedChat, int, error)
+
+	// ListOrganizationChats получает все чаты организации (для администраторов)
+	ListOrganizationChats(ctx context.Context, organizationID, userID domain.ID, page, pageSize int) ([]*domain.Chat, int, error)
 }
 
 // AgentManager - сервис для управления агентами
@@ -65,6 +104,9 @@ type ContextBuilder interface {
 
 	// EnrichWithOrganizationFacts добавляет факты об организации в контекст
 	EnrichWithOrganizationFacts(ctx context.Context, organizationID domain.ID) (string, error)
+
+	// EnrichWithOrganizationProfile добавляет в контекст проверенные поля анкеты организации
+	EnrichWithOrganizationProfile(ctx context.Context, organizationID domain.ID) (string, error)
 }
 
 // AgentExecutor - основной сервис для выполнения агентов
@@ -74,6 +116,12 @@ type AgentExecutor interface {
 
 	// SendMessageStream отправляет сообщение с потоковым ответом
 	SendMessageStream(ctx context.Context, req dto.SendMessageDTO, stream MessageStream) error
+
+	// EditMessageStream создает новую ветку с измененным сообщением пользователя и потоковым ответом
+	EditMessageStream(ctx context.Context, req dto.EditMessageDTO, stream MessageStream) error
+
+	// RegenerateStream создает новую ветку с заново сгенерированным ответом ассистента
+	RegenerateStream(ctx context.Context, req dto.RegenerateMessageDTO, stream MessageStream) error
 }
 
 // ExecutionStream - интерфейс для потоковой передачи результатов выполнения
@@ -161,6 +209,32 @@ type OrganizationMemoryService interface {
 	DeleteFact(ctx context.Context, organizationID domain.ID, factID domain.ID) error
 }
 
+// AttachmentService - сервис вложений сообщений
+type AttachmentService interface {
+	// Resolve проверяет вложения нового сообщения и дополняет их размером и типом из S3
+	Resolve(ctx context.Context, organizationID domain.ID, refs []dto.AttachmentDTO) ([]domain.Attachment, error)
+
+	// Transcribe распознает голосовые вложения; расшифровка сохраняется во вложении
+	Transcribe(ctx context.Context, attachments []domain.Attachment) ([]domain.Attachment, error)
+
+	// Load загружает содержимое вложения
+	Load(ctx context.Context, attachment domain.Attachment) ([]byte, error)
+
+	// Prepare обрабатывает документы нового сообщения: небольшие встраиваются текстом,
+	// большие индексируются во временный индекс чата
+	Prepare(ctx context.Context, organizationID, chatID domain.ID, attachments []domain.Attachment) ([]domain.Attachment, error)
+
+	// SearchChatFiles ищет по проиндексированным файлам чата
+	SearchChatFiles(ctx context.Context, organizationID, chatID domain.ID, query string, limit int) ([]string, error)
+}
+
+// FactExtractor - автоматическое пополнение памяти организации из сообщений
+type FactExtractor interface {
+	// Submit ставит извлечение фактов из сообщения пользователя в фоновую очередь.
+	// Возвращает false, если очередь заполнена и сообщение пропущено
+	Submit(ctx context.Context, organizationID, userID domain.ID, text string) bool
+}
+
 // ContractSearchService - сервис для поиска шаблонов контрактов
 type ContractSearchService interface {
 	// SearchTemplates ищет подходящие шаблоны договоров
@@ -172,6 +246,9 @@ type ContractGeneratorService interface {
 	// GenerateContract генерирует договор из шаблона с заполненными данными
 	GenerateContract(ctx context.Context, organizationID, templateID, contractName string, filledData map[string]interface{}) (*contracts.GeneratedContract, error)
 
+	// ExtractFields извлекает значения полей шаблона из свободного текста
+	ExtractFields(ctx context.Context, templateID, source string) (map[string]interface{}, error)
+
 	// ListContracts получает список сгенерированных контрактов
 	ListContracts(ctx context.Context, organizationID string, limit, offset int) ([]*contracts.ContractListItem, int, error)
 }
@@ -184,3 +261,15 @@ type QuotaService interface {
 	// GetLimits получает лимиты пользователя
 	GetLimits(ctx context.Context, userID domain.ID) (domain.LLMLimits, error)
 }
+
+// NotificationSink - канал доставки уведомлений пользователю (Telegram-бот и т.п.)
+type NotificationSink interface {
+	// Notify отправляет уведомление
+	Notify(ctx context.Context, notification domain.Notification) error
+}
+
+// AuditRecorder - журнал аудита core-service
+type AuditRecorder interface {
+	// Record публикует событие аудита; ошибка публикации не должна срывать действие
+	Record(ctx context.Context, event domain.AuditEvent)
+}
@@ -0,0 +1,113 @@
+package membership
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"llm-service/internal/coreservice"
+	"llm-service/internal/domain"
+
+	"github.com/opentracing/opentracing-go"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type memberDirectory interface {
+	ListOrganizationMembers(ctx context.Context, organizationID string) ([]*coreservice.Member, error)
+}
+
+type memberKey struct {
+	userID         domain.ID
+	organizationID domain.ID
+}
+
+type cachedMembership struct {
+	membership domain.Membership
+	expiresAt  time.Time
+}
+
+// Service ищет членство пользователя в организации через core-service для проверки доступа.
+// Найденные членства кэшируются на ttl; отсутствие членства не кэшируется,
+// чтобы только что вступивший пользователь сразу получил доступ.
+type Service struct {
+	members memberDirectory
+	ttl     time.Duration
+
+	mu          sync.Mutex
+	memberships map[memberKey]cachedMembership
+	lastEvicted time.Time
+}
+
+// New создает сервис; ttl <= 0 отключает кэш
+func New(members memberDirectory, ttl time.Duration) *Service {
+	return &Service{
+		members:     members,
+		ttl:         ttl,
+		memberships: make(map[memberKey]cachedMembership),
+	}
+}
+
+// GetMembership возвращает членство пользователя в организации или domain.ErrNotFound.
+// Запрос в core-service выполняется с токеном пользователя, поэтому core-service отказывает
+// не участникам - это тоже означает отсутствие членства.
+func (s *Service) GetMembership(ctx context.Context, userID, organizationID domain.ID) (*domain.Membership, error) {
+	key := memberKey{userID: userID, organizationID: organizationID}
+	now := time.Now()
+
+	s.mu.Lock()
+	cached, ok := s.memberships[key]
+	s.mu.Unlock()
+	if ok && now.Before(cached.expiresAt) {
+		membership := cached.membership
+		return &membership, nil
+	}
+
+	span, ctx := opentracing.StartSpanFromContext(ctx, "service.membership.GetMembership")
+	defer span.Finish()
+
+	members, err := s.members.ListOrganizationMembers(ctx, organizationID.String())
+	if err != nil {
+		switch status.Code(err) {
+		case codes.PermissionDenied, codes.NotFound:
+			return nil, domain.ErrNotFound
+		}
+		return nil, domain.NewInternalError("failed to get organization members", err)
+	}
+
+	for _, member := range members {
+		if member.UserID != userID.String() {
+			continue
+		}
+
+		membership := domain.Membership{
+			OrganizationID: organizationID,
+			Role:           domain.OrganizationRole(member.Role),
+			Active:         member.Active,
+			Permissions:    domain.ParsePermissions(member.Permissions),
+		}
+		if s.ttl > 0 {
+			s.mu.Lock()
+			s.evictExpired(now)
+			s.memberships[key] = cachedMembership{membership: membership, expiresAt: now.Add(s.ttl)}
+			s.mu.Unlock()
+		}
+		return &membership, nil
+	}
+
+	return nil, domain.ErrNotFound
+}
+
+// evictExpired удаляет устаревшие записи не чаще раза в ttl; вызывается под mu
+func (s *Service) evictExpired(now time.Time) {
+	if now.Sub(s.lastEvicted) < s.ttl {
+		return
+	}
+	s.lastEvicted = now
+
+	for key, cached := range s.memberships {
+		if !now.Before(cached.expiresAt) {
+			delete(s.memberships, key)
+		}
+	}
+}
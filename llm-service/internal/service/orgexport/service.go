@@ -0,0 +1,322 @@
+package orgexport
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"llm-service/internal/domain"
+	"llm-service/internal/logger"
+	"llm-service/internal/repository"
+
+	"github.com/opentracing/opentracing-go"
+)
+
+// pageSize - размер страницы при чтении чатов и сообщений
+const pageSize = 500
+
+type chatRepository interface {
+	ListChats(ctx context.Context, filter repository.ChatFilter, page, pageSize int) ([]*domain.Chat, int, error)
+	ListMessagesByChatIDWithToolCalls(ctx context.Context, chatID domain.ID, limit, offset int) ([]*domain.Message, int, error)
+}
+
+type memoryRepository interface {
+	CountOrganizationMemoryFacts(ctx context.Context, organizationID domain.ID) (int, error)
+	ListOrganizationMemoryFacts(ctx context.Context, organizationID domain.ID, limit int) ([]domain.OrganizationMemoryFact, error)
+}
+
+type objectStorage interface {
+	PutObject(ctx context.Context, key string, data []byte, contentType string) error
+}
+
+type publisher interface {
+	PublishMessage(ctx context.Context, message interface{}) error
+}
+
+// Service выгружает чаты и память организации в S3 по запросам core-service,
+// который собирает из них и своих данных архив
+type Service struct {
+	chats   chatRepository
+	memory  memoryRepository
+	storage objectStorage
+	results publisher
+}
+
+func New(chats chatRepository, memory memoryRepository, storage objectStorage, results publisher) *Service {
+	return &Service{
+		chats:   chats,
+		memory:  memory,
+		storage: storage,
+		results: results,
+	}
+}
+
+// chatRecord - чат в архиве; чаты субагентов выгружаются отдельными записями со ссылкой на родительский
+type chatRecord struct {
+	ID               domain.ID         `json:"id"`
+	UserID           domain.ID         `json:"user_id"`
+	AgentKey         string            `json:"agent_key"`
+	Title            string            `json:"title"`
+	Status           domain.ChatStatus `json:"status"`
+	ParentChatID     *domain.ID        `json:"parent_chat_id,omitempty"`
+	ParentToolCallID *domain.ID        `json:"parent_tool_call_id,omitempty"`
+	CreatedAt        time.Time         `json:"created_at"`
+	UpdatedAt        time.Time         `json:"updated_at"`
+	Messages         []messageRecord   `json:"messages"`
+}
+
+// messageRecord - сообщение со всеми ветками переписки: ParentMessageID связывает их в дерево
+type messageRecord struct {
+	ID              domain.ID           `json:"id"`
+	Role            domain.MessageRole  `json:"role"`
+	Content         string              `json:"content"`
+	Sender          *string             `json:"sender,omitempty"`
+	AuthorUserID    *domain.ID          `json:"author_user_id,omitempty"`
+	ParentMessageID *domain.ID          `json:"parent_message_id,omitempty"`
+	ToolCallID      *domain.ID          `json:"tool_call_id,omitempty"`
+	Attachments     []domain.Attachment `json:"attachments,omitempty"`
+	ToolCalls       []toolCallRecord    `json:"tool_calls,omitempty"`
+	CreatedAt       time.Time           `json:"created_at"`
+}
+
+type toolCallRecord struct {
+	ID          domain.ID             `json:"id"`
+	Name        string                `json:"name"`
+	Arguments   json.RawMessage       `json:"arguments"`
+	Result      json.RawMessage       `json:"result"`
+	Error       *string               `json:"error,omitempty"`
+	Status      domain.ToolCallStatus `json:"status"`
+	CreatedAt   time.Time             `json:"created_at"`
+	CompletedAt *time.Time            `json:"completed_at,omitempty"`
+}
+
+type memoryFactRecord struct {
+	ID        domain.ID `json:"id"`
+	Content   string    `json:"content"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// HandleMessage разбирает запрос из очереди. Некорректные запросы отбрасываются,
+// ошибка отправки результата возвращается, чтобы запрос был обработан повторно
+func (s *Service) HandleMessage(ctx context.Context, body []byte) error {
+	var req domain.OrganizationExportRequest
+	if err := json.Unmarshal(body, &req); err != nil {
+		logger.Warn(ctx, "organization export: dropping malformed request", "error", err)
+		return nil
+	}
+	if req.ExportID == (domain.ID{}) || req.OrganizationID == (domain.ID{}) {
+		logger.Warn(ctx, "organization export: dropping request without ids", "export_id", req.ExportID)
+		return nil
+	}
+
+	return s.results.PublishMessage(ctx, s.Export(ctx, req))
+}
+
+// Export выгружает чаты с сообщениями и вызовами инструментов и память организации в S3.
+// Ошибка попадает в результат: core-service завершит выгрузку с ошибкой
+func (s *Service) Export(ctx context.Context, req domain.OrganizationExportRequest) domain.OrganizationExportResult {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "service.orgexport.Export")
+	defer span.Finish()
+
+	result := domain.OrganizationExportResult{
+		ExportID: req.ExportID,
+		Source:   "llm-service",
+		Status:   domain.OrganizationExportCompleted,
+	}
+
+	files, err := s.export(ctx, req)
+	if err != nil {
+		logger.Error(ctx, "organization export: failed to export data",
+			"export_id", req.ExportID,
+			"organization_id", req.OrganizationID,
+			"error", err,
+		)
+		result.Status = domain.OrganizationExportFailed
+		result.Error = err.Error()
+		return result
+	}
+
+	logger.Info(ctx, "organization export: data exported",
+		"export_id", req.ExportID,
+		"organization_id", req.OrganizationID,
+		"files", len(files),
+	)
+	result.Files = files
+	return result
+}
+
+func (s *Service) export(ctx context.Context, req domain.OrganizationExportRequest) ([]domain.OrganizationExportFile, error) {
+	chats, err := s.collectChats(ctx, req.OrganizationID)
+	if err != nil {
+		return nil, err
+	}
+	facts, err := s.collectMemoryFacts(ctx, req.OrganizationID)
+	if err != nil {
+		return nil, err
+	}
+
+	chatsFile, err := s.put(ctx, req, "assistant/chats.json", chats, len(chats))
+	if err != nil {
+		return nil, err
+	}
+	factsFile, err := s.put(ctx, req, "assistant/memory_facts.json", facts, len(facts))
+	if err != nil {
+		return nil, err
+	}
+
+	return []domain.OrganizationExportFile{chatsFile, factsFile}, nil
+}
+
+// collectChats собирает все чаты организации, включая чаты субагентов любой вложенности
+func (s *Service) collectChats(ctx context.Context, organizationID domain.ID) ([]chatRecord, error) {
+	chats, err := s.listChats(ctx, repository.ChatFilter{OrganizationID: &organizationID})
+	if err != nil {
+		return nil, err
+	}
+
+	var records []chatRecord
+	for len(chats) > 0 {
+		chat := chats[0]
+		chats = chats[1:]
+
+		children, err := s.listChats(ctx, repository.ChatFilter{OrganizationID: &organizationID, ParentChatID: &chat.ID})
+		if err != nil {
+			return nil, err
+		}
+		chats = append(chats, children...)
+
+		messages, err := s.listMessages(ctx, chat.ID)
+		if err != nil {
+			return nil, err
+		}
+
+		records = append(records, chatRecord{
+			ID:               chat.ID,
+			UserID:           chat.UserID,
+			AgentKey:         chat.AgentKey,
+			Title:            chat.Title,
+			Status:           chat.Status,
+			ParentChatID:     chat.ParentChatID,
+			ParentToolCallID: chat.ParentToolCallID,
+			CreatedAt:        chat.CreatedAt,
+			UpdatedAt:        chat.UpdatedAt,
+			Messages:         messages,
+		})
+	}
+
+	return records, nil
+}
+
+func (s *Service) listChats(ctx context.Context, filter repository.ChatFilter) ([]*domain.Chat, error) {
+	var chats []*domain.Chat
+	for page := 1; ; page++ {
+		batch, total, err := s.chats.ListChats(ctx, filter, page, pageSize)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list chats: %w", err)
+		}
+		chats = append(chats, batch...)
+		if len(batch) == 0 || len(chats) >= total {
+			return chats, nil
+		}
+	}
+}
+
+func (s *Service) listMessages(ctx context.Context, chatID domain.ID) ([]messageRecord, error) {
+	var records []messageRecord
+	for offset := 0; ; offset += pageSize {
+		messages, total, err := s.chats.ListMessagesByChatIDWithToolCalls(ctx, chatID, pageSize, offset)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list messages of chat %s: %w", chatID, err)
+		}
+		for _, msg := range messages {
+			records = append(records, toMessageRecord(msg))
+		}
+		if len(messages) == 0 || offset+len(messages) >= total {
+			return records, nil
+		}
+	}
+}
+
+func (s *Service) collectMemoryFacts(ctx context.Context, organizationID domain.ID) ([]memoryFactRecord, error) {
+	count, err := s.memory.CountOrganizationMemoryFacts(ctx, organizationID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count memory facts: %w", err)
+	}
+
+	records := make([]memoryFactRecord, 0, count)
+	if count == 0 {
+		return records, nil
+	}
+
+	facts, err := s.memory.ListOrganizationMemoryFacts(ctx, organizationID, count)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list memory facts: %w", err)
+	}
+	for _, fact := range facts {
+		records = append(records, memoryFactRecord{
+			ID:        fact.ID,
+			Content:   fact.Content,
+			CreatedAt: fact.CreatedAt,
+			UpdatedAt: fact.UpdatedAt,
+		})
+	}
+
+	return records, nil
+}
+
+// put сохраняет файл рядом с будущим архивом: core-service удалит его после сборки
+func (s *Service) put(ctx context.Context, req domain.OrganizationExportRequest, name string, v any, items int) (domain.OrganizationExportFile, error) {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return domain.OrganizationExportFile{}, fmt.Errorf("failed to encode %s: %w", name, err)
+	}
+
+	key := fmt.Sprintf("exports/%s/%s/%s", req.OrganizationID, req.ExportID, name)
+	if err := s.storage.PutObject(ctx, key, data, "application/json"); err != nil {
+		return domain.OrganizationExportFile{}, fmt.Errorf("failed to upload %s: %w", name, err)
+	}
+
+	return domain.OrganizationExportFile{Name: name, S3Key: key, Items: items}, nil
+}
+
+func toMessageRecord(msg *domain.Message) messageRecord {
+	record := messageRecord{
+		ID:              msg.ID,
+		Role:            msg.Role,
+		Content:         msg.Content,
+		Sender:          msg.Sender,
+		AuthorUserID:    msg.AuthorUserID,
+		ParentMessageID: msg.ParentMessageID,
+		ToolCallID:      msg.ToolCallID,
+		Attachments:     msg.Attachments,
+		CreatedAt:       msg.CreatedAt,
+	}
+	for _, tc := range msg.ToolCalls {
+		record.ToolCalls = append(record.ToolCalls, toolCallRecord{
+			ID:          tc.ID,
+			Name:        tc.Name,
+			Arguments:   rawJSON(tc.Arguments),
+			Result:      rawJSON(tc.Result),
+			Error:       tc.Error,
+			Status:      tc.Status,
+			CreatedAt:   tc.CreatedAt,
+			CompletedAt: tc.CompletedAt,
+		})
+	}
+	return record
+}
+
+// rawJSON оставляет аргументы и результаты инструментов как есть; пустые и некорректные
+// значения не должны ломать кодирование всего файла
+func rawJSON(data json.RawMessage) json.RawMessage {
+	if len(data) == 0 {
+		return json.RawMessage("null")
+	}
+	if json.Valid(data) {
+		return data
+	}
+	encoded, _ := json.Marshal(string(data))
+	return encoded
+}
@@ -0,0 +1,112 @@
+package orgmemory
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"llm-service/internal/domain"
+	"llm-service/internal/llm"
+	"llm-service/internal/logger"
+)
+
+// extractionTimeout ограничение времени одного извлечения фактов
+const extractionTimeout = 2 * time.Minute
+
+type factExtractor interface {
+	ExtractFacts(ctx context.Context, organizationID domain.ID, text string) ([]domain.OrganizationMemoryFact, llm.Usage, error)
+}
+
+type usageRecorder interface {
+	Confirm(ctx context.Context, userID domain.ID, reserved int, actual int) error
+}
+
+type extractionTask struct {
+	ctx            context.Context
+	organizationID domain.ID
+	userID         domain.ID
+	text           string
+}
+
+// ExtractionWorker извлекает факты из сообщений в фоне ограниченным числом горутин.
+// Токены запроса к модели списываются с квоты автора сообщения. Если очередь заполнена,
+// сообщение пропускается: извлечение фактов не должно тормозить чат.
+type ExtractionWorker struct {
+	extractor factExtractor
+	quota     usageRecorder
+	workers   int
+	tasks     chan extractionTask
+}
+
+func NewExtractionWorker(extractor factExtractor, quota usageRecorder, workers, queueSize int) *ExtractionWorker {
+	return &ExtractionWorker{
+		extractor: extractor,
+		quota:     quota,
+		workers:   max(workers, 1),
+		tasks:     make(chan extractionTask, max(queueSize, 0)),
+	}
+}
+
+// Submit ставит извлечение фактов в очередь. Возвращает false, если очередь заполнена
+func (w *ExtractionWorker) Submit(ctx context.Context, organizationID, userID domain.ID, text string) bool {
+	task := extractionTask{
+		// Значения контекста (trace, request id) сохраняются, отмена запроса - нет
+		ctx:            context.WithoutCancel(ctx),
+		organizationID: organizationID,
+		userID:         userID,
+		text:           text,
+	}
+
+	select {
+	case w.tasks <- task:
+		return true
+	default:
+		return false
+	}
+}
+
+// Run обрабатывает очередь до отмены контекста и дожидается начатых извлечений
+func (w *ExtractionWorker) Run(ctx context.Context) {
+	logger.Infof(ctx, "fact extraction worker started, %d workers", w.workers)
+
+	var wg sync.WaitGroup
+	for range w.workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case task := <-w.tasks:
+					w.extract(ctx, task)
+				}
+			}
+		}()
+	}
+
+	wg.Wait()
+	logger.Info(ctx, "fact extraction worker stopped")
+}
+
+// extract выполняет одно извлечение; остановка воркера прерывает запрос к модели
+func (w *ExtractionWorker) extract(runCtx context.Context, task extractionTask) {
+	ctx, cancel := context.WithTimeout(task.ctx, extractionTimeout)
+	defer cancel()
+	stop := context.AfterFunc(runCtx, cancel)
+	defer stop()
+
+	facts, usage, err := w.extractor.ExtractFacts(ctx, task.organizationID, task.text)
+	if usage.TotalTokens > 0 {
+		if err := w.quota.Confirm(ctx, task.userID, 0, usage.TotalTokens); err != nil {
+			logger.Warn(ctx, "failed to confirm fact extraction token usage", "error", err)
+		}
+	}
+	if err != nil {
+		logger.Warn(ctx, "failed to extract organization facts", "error", err)
+		return
+	}
+	if len(facts) > 0 {
+		logger.Infof(ctx, "Saved %d organization facts from user message", len(facts))
+	}
+}
@@ -0,0 +1,184 @@
+package orgmemory
+
+import (
+	"context"
+	"errors"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	"llm-service/internal/domain"
+	"llm-service/internal/llm"
+	"llm-service/internal/logger"
+)
+
+func TestMain(m *testing.M) {
+	logger.Init()
+	os.Exit(m.Run())
+}
+
+// fakeExtractor возвращает заданный результат и сообщает о каждом вызове в calls
+type fakeExtractor struct {
+	usage llm.Usage
+	err   error
+	block chan struct{}
+	calls chan string
+}
+
+func (f *fakeExtractor) ExtractFacts(ctx context.Context, _ domain.ID, text string) ([]domain.OrganizationMemoryFact, llm.Usage, error) {
+	if f.block != nil {
+		select {
+		case <-f.block:
+		case <-ctx.Done():
+			return nil, llm.Usage{}, ctx.Err()
+		}
+	}
+	defer func() { f.calls <- text }()
+	if f.err != nil {
+		return nil, f.usage, f.err
+	}
+	return []domain.OrganizationMemoryFact{{Content: text}}, f.usage, nil
+}
+
+type confirmedUsage struct {
+	userID domain.ID
+	tokens int
+}
+
+type fakeQuota struct {
+	mu        sync.Mutex
+	confirmed []confirmedUsage
+}
+
+func (f *fakeQuota) Confirm(_ context.Context, userID domain.ID, _ int, actual int) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.confirmed = append(f.confirmed, confirmedUsage{userID: userID, tokens: actual})
+	return nil
+}
+
+func (f *fakeQuota) all() []confirmedUsage {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]confirmedUsage(nil), f.confirmed...)
+}
+
+func waitCall(t *testing.T, calls chan string) string {
+	t.Helper()
+	select {
+	case text := <-calls:
+		return text
+	case <-time.After(5 * time.Second):
+		t.Fatal("extraction was not executed")
+		return ""
+	}
+}
+
+func TestExtractionWorkerChargesUsage(t *testing.T) {
+	tests := []struct {
+		name       string
+		usage      llm.Usage
+		err        error
+		wantTokens []int
+	}{
+		{name: "успешное извлечение", usage: llm.Usage{TotalTokens: 120}, wantTokens: []int{120}},
+		{name: "ошибка после запроса к модели", usage: llm.Usage{TotalTokens: 80}, err: errors.New("invalid output"), wantTokens: []int{80}},
+		{name: "без запроса к модели", wantTokens: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			extractor := &fakeExtractor{usage: tt.usage, err: tt.err, calls: make(chan string, 1)}
+			quota := &fakeQuota{}
+			worker := NewExtractionWorker(extractor, quota, 1, 10)
+
+			done := make(chan struct{})
+			go func() {
+				worker.Run(ctx)
+				close(done)
+			}()
+
+			userID := domain.NewID()
+			if !worker.Submit(ctx, domain.NewID(), userID, "Мы продаем кофе") {
+				t.Fatal("Submit = false, want true")
+			}
+			waitCall(t, extractor.calls)
+			cancel()
+			<-done
+
+			confirmed := quota.all()
+			if len(confirmed) != len(tt.wantTokens) {
+				t.Fatalf("confirmed = %+v, want tokens %v", confirmed, tt.wantTokens)
+			}
+			for i, tokens := range tt.wantTokens {
+				if confirmed[i].tokens != tokens || confirmed[i].userID != userID {
+					t.Errorf("confirmed[%d] = %+v, want %d tokens for %s", i, confirmed[i], tokens, userID)
+				}
+			}
+		})
+	}
+}
+
+func TestExtractionWorkerDropsWhenQueueIsFull(t *testing.T) {
+	extractor := &fakeExtractor{calls: make(chan string, 3)}
+	worker := NewExtractionWorker(extractor, &fakeQuota{}, 1, 2)
+
+	// Воркер не запущен: в очередь помещаются только queueSize сообщений
+	ctx := context.Background()
+	for i, want := range []bool{true, true, false} {
+		if got := worker.Submit(ctx, domain.NewID(), domain.NewID(), "text"); got != want {
+			t.Errorf("Submit #%d = %v, want %v", i+1, got, want)
+		}
+	}
+}
+
+func TestExtractionWorkerSurvivesRequestCancel(t *testing.T) {
+	runCtx, stopWorker := context.WithCancel(context.Background())
+	defer stopWorker()
+
+	extractor := &fakeExtractor{block: make(chan struct{}), calls: make(chan string, 1)}
+	worker := NewExtractionWorker(extractor, &fakeQuota{}, 1, 1)
+	go worker.Run(runCtx)
+
+	// Запрос пользователя завершается раньше, чем извлечение фактов
+	requestCtx, cancelRequest := context.WithCancel(context.Background())
+	if !worker.Submit(requestCtx, domain.NewID(), domain.NewID(), "text") {
+		t.Fatal("Submit = false, want true")
+	}
+	cancelRequest()
+	close(extractor.block)
+
+	if got := waitCall(t, extractor.calls); got != "text" {
+		t.Errorf("extracted text = %q, want %q", got, "text")
+	}
+}
+
+func TestExtractionWorkerStopCancelsExtraction(t *testing.T) {
+	runCtx, stopWorker := context.WithCancel(context.Background())
+
+	extractor := &fakeExtractor{block: make(chan struct{}), calls: make(chan string, 1)}
+	worker := NewExtractionWorker(extractor, &fakeQuota{}, 1, 1)
+
+	done := make(chan struct{})
+	go func() {
+		worker.Run(runCtx)
+		close(done)
+	}()
+
+	if !worker.Submit(context.Background(), domain.NewID(), domain.NewID(), "text") {
+		t.Fatal("Submit = false, want true")
+	}
+	// Даем воркеру забрать задачу, затем останавливаем его
+	time.Sleep(50 * time.Millisecond)
+	stopWorker()
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Run did not return after stop")
+	}
+}
@@ -0,0 +1,135 @@
+package orgmemory
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"llm-service/internal/domain"
+	"llm-service/internal/llm"
+	"llm-service/internal/logger"
+
+	"github.com/opentracing/opentracing-go"
+)
+
+// maxExtractedFacts ограничение количества фактов, извлекаемых из одного сообщения
+const maxExtractedFacts = 5
+
+const factExtractionPrompt = `Ты ведешь память об организации пользователя. Из сообщения пользователя выпиши новые устойчивые факты о его организации: реквизиты, сфера деятельности, продукты, клиенты, сотрудники, используемые системы, договоренности.
+Не записывай вопросы, разовые задачи и то, что уже есть в списке известных фактов. Каждый факт - одно короткое утверждение на русском языке, не длиннее 100 символов.
+Если новых фактов нет, верни пустой список.`
+
+// extractedFacts - структурированный ответ модели при извлечении фактов
+type extractedFacts struct {
+	Facts []string `json:"facts"`
+}
+
+func (f *extractedFacts) Validate() error {
+	if len(f.Facts) > maxExtractedFacts {
+		return fmt.Errorf("got %d facts, maximum is %d", len(f.Facts), maxExtractedFacts)
+	}
+	for i, fact := range f.Facts {
+		fact = strings.TrimSpace(fact)
+		if fact == "" {
+			return fmt.Errorf("fact %d is empty", i+1)
+		}
+		if n := utf8.RuneCountInString(fact); n > MaxFactLength {
+			return fmt.Errorf("fact %d is %d characters long, maximum is %d", i+1, n, MaxFactLength)
+		}
+		f.Facts[i] = fact
+	}
+	return nil
+}
+
+var extractedFactsFormat = llm.ResponseFormat{
+	Name:        "organization_facts",
+	Description: "Новые факты об организации пользователя",
+	Schema: map[string]any{
+		"type": "object",
+		"properties": map[string]any{
+			"facts": map[string]any{
+				"type":  "array",
+				"items": map[string]any{"type": "string"},
+			},
+		},
+		"required":             []string{"facts"},
+		"additionalProperties": false,
+	},
+	Strict: true,
+}
+
+// FactExtractor извлекает факты об организации из сообщений и сохраняет их в память
+type FactExtractor struct {
+	llmProvider llm.CompletionProvider
+	service     *Service
+}
+
+func NewFactExtractor(llmProvider llm.CompletionProvider, service *Service) *FactExtractor {
+	return &FactExtractor{llmProvider: llmProvider, service: service}
+}
+
+// ExtractFacts извлекает новые факты из текста и сохраняет их.
+// Возвращает сохраненные факты и токены, потраченные на запрос к модели.
+func (e *FactExtractor) ExtractFacts(ctx context.Context, organizationID domain.ID, text string) ([]domain.OrganizationMemoryFact, llm.Usage, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "service.orgmemory.ExtractFacts")
+	defer span.Finish()
+
+	if strings.TrimSpace(text) == "" {
+		return nil, llm.Usage{}, nil
+	}
+
+	existing, err := e.service.ListFacts(ctx, organizationID)
+	if err != nil {
+		return nil, llm.Usage{}, err
+	}
+	if len(existing) >= MaxFactsPerOrganization {
+		return nil, llm.Usage{}, nil
+	}
+
+	known := make(map[string]struct{}, len(existing))
+	var knownList strings.Builder
+	for _, fact := range existing {
+		known[strings.ToLower(fact.Content)] = struct{}{}
+		knownList.WriteString("- " + fact.Content + "\n")
+	}
+
+	prompt := factExtractionPrompt
+	if knownList.Len() > 0 {
+		prompt += "\n\nИзвестные факты:\n" + knownList.String()
+	}
+
+	params := llm.ChatParams{
+		Messages: []llm.MessageParam{
+			{Role: llm.RoleSystem, Content: prompt},
+			{Role: llm.RoleUser, Content: text},
+		},
+	}
+
+	result, usage, err := llm.CompleteStructured[extractedFacts](ctx, e.llmProvider, params, extractedFactsFormat)
+	if err != nil {
+		return nil, usage, fmt.Errorf("failed to extract facts: %w", err)
+	}
+
+	saved := make([]domain.OrganizationMemoryFact, 0, len(result.Facts))
+	for _, content := range result.Facts {
+		if _, ok := known[strings.ToLower(content)]; ok {
+			continue
+		}
+
+		fact, err := e.service.AddFact(ctx, organizationID, content)
+		if errors.Is(err, domain.ErrTooManyRequests) {
+			break
+		}
+		if err != nil {
+			logger.Warn(ctx, "failed to save extracted fact", "error", err)
+			continue
+		}
+
+		known[strings.ToLower(content)] = struct{}{}
+		saved = append(saved, fact)
+	}
+
+	return saved, usage, nil
+}
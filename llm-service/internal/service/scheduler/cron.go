@@ -0,0 +1,60 @@
+package scheduler
+
+import (
+	"time"
+
+	"llm-service/internal/domain"
+
+	"github.com/robfig/cron/v3"
+)
+
+// cronParser разбирает стандартные cron-выражения из 5 полей и дескрипторы вида @daily
+var cronParser = cron.NewParser(cron.Minute | cron.Hour | cron.Dom | cron.Month | cron.Dow | cron.Descriptor)
+
+// parseSchedule разбирает cron-выражение в часовом поясе расписания
+func parseSchedule(expr, timezone string) (cron.Schedule, error) {
+	location, err := time.LoadLocation(timezone)
+	if err != nil {
+		return nil, domain.NewInvalidArgumentError("unknown timezone: " + timezone)
+	}
+
+	schedule, err := cronParser.Parse(expr)
+	if err != nil {
+		return nil, domain.NewInvalidArgumentError("invalid cron expression: " + err.Error())
+	}
+
+	if spec, ok := schedule.(*cron.SpecSchedule); ok {
+		spec.Location = location
+	}
+
+	return schedule, nil
+}
+
+// nextRun возвращает время следующего запуска в UTC
+func nextRun(schedule cron.Schedule, after time.Time) *time.Time {
+	next := schedule.Next(after)
+	if next.IsZero() {
+		return nil
+	}
+	next = next.UTC()
+	return &next
+}
+
+// shortestInterval оценивает минимальный интервал между запусками по нескольким ближайшим запускам
+func shortestInterval(schedule cron.Schedule, from time.Time) time.Duration {
+	const probes = 8
+
+	var shortest time.Duration
+	prev := schedule.Next(from)
+	for i := 0; i < probes && !prev.IsZero(); i++ {
+		next := schedule.Next(prev)
+		if next.IsZero() {
+			break
+		}
+		if interval := next.Sub(prev); shortest == 0 || interval < shortest {
+			shortest = interval
+		}
+		prev = next
+	}
+	return shortest
+}
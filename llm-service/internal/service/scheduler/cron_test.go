@@ -0,0 +1,140 @@
+package scheduler
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"llm-service/internal/domain"
+)
+
+func TestParseSchedule(t *testing.T) {
+	tests := []struct {
+		name     string
+		expr     string
+		timezone string
+		wantErr  bool
+	}{
+		{name: "ежедневно", expr: "0 9 * * *", timezone: "Europe/Moscow"},
+		{name: "по будням", expr: "30 8 * * 1-5", timezone: "UTC"},
+		{name: "дескриптор", expr: "@daily", timezone: "Asia/Novosibirsk"},
+		{name: "шесть полей", expr: "0 0 9 * * *", timezone: "UTC", wantErr: true},
+		{name: "мусор", expr: "every day", timezone: "UTC", wantErr: true},
+		{name: "пустое выражение", expr: "", timezone: "UTC", wantErr: true},
+		{name: "час вне диапазона", expr: "0 25 * * *", timezone: "UTC", wantErr: true},
+		{name: "неизвестный часовой пояс", expr: "0 9 * * *", timezone: "Mars/Olympus", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := parseSchedule(tt.expr, tt.timezone)
+			if !tt.wantErr {
+				if err != nil {
+					t.Fatalf("parseSchedule(%q, %q): %v", tt.expr, tt.timezone, err)
+				}
+				return
+			}
+			if !errors.Is(err, domain.ErrInvalidArgument) {
+				t.Fatalf("parseSchedule(%q, %q) = %v, want ErrInvalidArgument", tt.expr, tt.timezone, err)
+			}
+		})
+	}
+}
+
+func TestNextRun(t *testing.T) {
+	after := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		expr     string
+		timezone string
+		want     time.Time
+	}{
+		{
+			// 09:00 по Москве (UTC+3) уже прошло в 15:00 по Москве, следующий запуск завтра в 06:00 UTC
+			name:     "часовой пояс расписания",
+			expr:     "0 9 * * *",
+			timezone: "Europe/Moscow",
+			want:     time.Date(2026, 3, 11, 6, 0, 0, 0, time.UTC),
+		},
+		{
+			name:     "в тот же день",
+			expr:     "0 18 * * *",
+			timezone: "UTC",
+			want:     time.Date(2026, 3, 10, 18, 0, 0, 0, time.UTC),
+		},
+		{
+			// 10 марта 2026 - вторник, следующий понедельник - 16 марта
+			name:     "по дню недели",
+			expr:     "0 10 * * 1",
+			timezone: "UTC",
+			want:     time.Date(2026, 3, 16, 10, 0, 0, 0, time.UTC),
+		},
+		{
+			name:     "дескриптор",
+			expr:     "@daily",
+			timezone: "Asia/Vladivostok",
+			want:     time.Date(2026, 3, 10, 14, 0, 0, 0, time.UTC),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			schedule, err := parseSchedule(tt.expr, tt.timezone)
+			if err != nil {
+				t.Fatalf("parseSchedule: %v", err)
+			}
+			got := nextRun(schedule, after)
+			if got == nil {
+				t.Fatal("nextRun returned nil")
+			}
+			if got.Location() != time.UTC {
+				t.Errorf("nextRun location = %s, want UTC", got.Location())
+			}
+			if !got.Equal(tt.want) {
+				t.Errorf("nextRun = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNextRunNever(t *testing.T) {
+	// 30 февраля не наступает никогда
+	schedule, err := parseSchedule("0 0 30 2 *", "UTC")
+	if err != nil {
+		t.Fatalf("parseSchedule: %v", err)
+	}
+	if got := nextRun(schedule, time.Now()); got != nil {
+		t.Errorf("nextRun = %s, want nil", got)
+	}
+}
+
+func TestShortestInterval(t *testing.T) {
+	from := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name string
+		expr string
+		want time.Duration
+	}{
+		{name: "каждую минуту", expr: "* * * * *", want: time.Minute},
+		{name: "каждые 5 минут", expr: "*/5 * * * *", want: 5 * time.Minute},
+		{name: "каждый час", expr: "0 * * * *", want: time.Hour},
+		{name: "дважды в день", expr: "0 9,21 * * *", want: 12 * time.Hour},
+		{name: "неравномерно", expr: "0 9,10 * * *", want: time.Hour},
+		{name: "ежедневно", expr: "@daily", want: 24 * time.Hour},
+		{name: "никогда", expr: "0 0 30 2 *", want: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			schedule, err := parseSchedule(tt.expr, "UTC")
+			if err != nil {
+				t.Fatalf("parseSchedule: %v", err)
+			}
+			if got := shortestInterval(schedule, from); got != tt.want {
+				t.Errorf("shortestInterval(%q) = %s, want %s", tt.expr, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,209 @@
+package scheduler
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"llm-service/internal/domain"
+	"llm-service/internal/domain/dto"
+
+	"github.com/opentracing/opentracing-go"
+)
+
+const (
+	// defaultTimezone - часовой пояс расписания, если пользователь его не указал
+	defaultTimezone = "Europe/Moscow"
+	// maxTitleLength, maxPromptLength - ограничения на текстовые поля расписания
+	maxTitleLength  = 200
+	maxPromptLength = 8000
+)
+
+type scheduleRepository interface {
+	CreateSchedule(ctx context.Context, schedule *domain.AgentSchedule) error
+	GetSchedule(ctx context.Context, id domain.ID) (*domain.AgentSchedule, error)
+	ListSchedules(ctx context.Context, organizationID, userID domain.ID) ([]*domain.AgentSchedule, error)
+	CountSchedules(ctx context.Context, organizationID, userID domain.ID) (int, error)
+	UpdateSchedule(ctx context.Context, schedule *domain.AgentSchedule) error
+	DeleteSchedule(ctx context.Context, id domain.ID) error
+}
+
+type agentDirectory interface {
+	GetAgent(agentKey string) (*domain.AgentDefinition, error)
+}
+
+type limitsConfig interface {
+	GetSchedulerMinInterval() time.Duration
+	GetSchedulerMaxSchedulesPerUser() int
+}
+
+// Service управляет расписаниями агентов пользователя
+type Service struct {
+	repo   scheduleRepository
+	agents agentDirectory
+	limits limitsConfig
+}
+
+func New(repo scheduleRepository, agents agentDirectory, limits limitsConfig) *Service {
+	return &Service{
+		repo:   repo,
+		agents: agents,
+		limits: limits,
+	}
+}
+
+// CreateSchedule создает расписание и вычисляет время первого запуска
+func (s *Service) CreateSchedule(ctx context.Context, req dto.CreateScheduleDTO) (*domain.AgentSchedule, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "service.scheduler.CreateSchedule")
+	defer span.Finish()
+
+	count, err := s.repo.CountSchedules(ctx, req.OrganizationID, req.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if limit := s.limits.GetSchedulerMaxSchedulesPerUser(); limit > 0 && count >= limit {
+		return nil, domain.NewInvalidArgumentError(fmt.Sprintf("schedule limit reached: at most %d schedules per user", limit))
+	}
+
+	schedule := &domain.AgentSchedule{
+		Model:          domain.NewModel(),
+		OrganizationID: req.OrganizationID,
+		UserID:         req.UserID,
+		Title:          strings.TrimSpace(req.Title),
+		AgentKey:       req.AgentKey,
+		Prompt:         strings.TrimSpace(req.Prompt),
+		CronExpr:       strings.TrimSpace(req.CronExpr),
+		Timezone:       strings.TrimSpace(req.Timezone),
+		Enabled:        req.Enabled,
+	}
+
+	if err := s.prepare(schedule, time.Now()); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.CreateSchedule(ctx, schedule); err != nil {
+		return nil, err
+	}
+
+	return schedule, nil
+}
+
+// ListSchedules возвращает расписания пользователя в организации
+func (s *Service) ListSchedules(ctx context.Context, organizationID, userID domain.ID) ([]*domain.AgentSchedule, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "service.scheduler.ListSchedules")
+	defer span.Finish()
+
+	return s.repo.ListSchedules(ctx, organizationID, userID)
+}
+
+// UpdateSchedule изменяет расписание; при изменении cron-выражения, часового пояса
+// или включении расписания время следующего запуска пересчитывается
+func (s *Service) UpdateSchedule(ctx context.Context, req dto.UpdateScheduleDTO) (*domain.AgentSchedule, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "service.scheduler.UpdateSchedule")
+	defer span.Finish()
+
+	schedule, err := s.getOwned(ctx, req.ScheduleID, req.OrganizationID, req.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Title != nil {
+		schedule.Title = strings.TrimSpace(*req.Title)
+	}
+	if req.AgentKey != nil {
+		schedule.AgentKey = *req.AgentKey
+	}
+	if req.Prompt != nil {
+		schedule.Prompt = strings.TrimSpace(*req.Prompt)
+	}
+	if req.CronExpr != nil {
+		schedule.CronExpr = strings.TrimSpace(*req.CronExpr)
+	}
+	if req.Timezone != nil {
+		schedule.Timezone = strings.TrimSpace(*req.Timezone)
+	}
+	if req.Enabled != nil {
+		schedule.Enabled = *req.Enabled
+	}
+
+	if err := s.prepare(schedule, time.Now()); err != nil {
+		return nil, err
+	}
+	schedule.UpdatedAt = time.Now().UTC()
+
+	if err := s.repo.UpdateSchedule(ctx, schedule); err != nil {
+		return nil, err
+	}
+
+	return schedule, nil
+}
+
+// DeleteSchedule удаляет расписание; чаты прошлых запусков сохраняются
+func (s *Service) DeleteSchedule(ctx context.Context, scheduleID, organizationID, userID domain.ID) error {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "service.scheduler.DeleteSchedule")
+	defer span.Finish()
+
+	if _, err := s.getOwned(ctx, scheduleID, organizationID, userID); err != nil {
+		return err
+	}
+
+	return s.repo.DeleteSchedule(ctx, scheduleID)
+}
+
+// getOwned загружает расписание пользователя; чужие расписания не видны
+func (s *Service) getOwned(ctx context.Context, scheduleID, organizationID, userID domain.ID) (*domain.AgentSchedule, error) {
+	schedule, err := s.repo.GetSchedule(ctx, scheduleID)
+	if err != nil {
+		return nil, err
+	}
+	if schedule.OrganizationID != organizationID || schedule.UserID != userID {
+		return nil, domain.NewNotFoundError("schedule not found")
+	}
+	return schedule, nil
+}
+
+// prepare проверяет расписание и вычисляет время следующего запуска
+func (s *Service) prepare(schedule *domain.AgentSchedule, now time.Time) error {
+	if schedule.Prompt == "" {
+		return domain.NewInvalidArgumentError("prompt is required")
+	}
+	if utf8.RuneCountInString(schedule.Prompt) > maxPromptLength {
+		return domain.NewInvalidArgumentError(fmt.Sprintf("prompt is too long: at most %d characters", maxPromptLength))
+	}
+	if utf8.RuneCountInString(schedule.Title) > maxTitleLength {
+		return domain.NewInvalidArgumentError(fmt.Sprintf("title is too long: at most %d characters", maxTitleLength))
+	}
+	if schedule.CronExpr == "" {
+		return domain.NewInvalidArgumentError("cron expression is required")
+	}
+	if schedule.Timezone == "" {
+		schedule.Timezone = defaultTimezone
+	}
+
+	if _, err := s.agents.GetAgent(schedule.AgentKey); err != nil {
+		return domain.NewInvalidArgumentError("unknown agent: " + schedule.AgentKey)
+	}
+
+	cronSchedule, err := parseSchedule(schedule.CronExpr, schedule.Timezone)
+	if err != nil {
+		return err
+	}
+
+	if minInterval := s.limits.GetSchedulerMinInterval(); minInterval > 0 {
+		if interval := shortestInterval(cronSchedule, now); interval > 0 && interval < minInterval {
+			return domain.NewInvalidArgumentError(fmt.Sprintf("schedule runs too often: minimum interval is %s", minInterval))
+		}
+	}
+
+	schedule.NextRunAt = nil
+	if schedule.Enabled {
+		schedule.NextRunAt = nextRun(cronSchedule, now)
+		if schedule.NextRunAt == nil {
+			return domain.NewInvalidArgumentError("cron expression never fires")
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,305 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"llm-service/internal/coreservice"
+	"llm-service/internal/domain"
+	"llm-service/internal/domain/dto"
+	"llm-service/internal/logger"
+	"llm-service/internal/service"
+
+	"github.com/opentracing/opentracing-go"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// notificationPreviewLength - сколько символов ответа агента попадает в уведомление
+const notificationPreviewLength = 1000
+
+type runRepository interface {
+	ClaimDueSchedules(ctx context.Context, now, lockedUntil time.Time, limit int) ([]*domain.AgentSchedule, error)
+	CompleteScheduleRun(ctx context.Context, schedule *domain.AgentSchedule, claimedUpdatedAt time.Time) error
+}
+
+type agentExecutor interface {
+	ExecuteStream(ctx context.Context, req dto.ExecuteAgentDTO, stream service.ExecutionStream) error
+}
+
+type quotaService interface {
+	GetLimits(ctx context.Context, userID domain.ID) (domain.LLMLimits, error)
+}
+
+type memberDirectory interface {
+	ListOrganizationMembers(ctx context.Context, organizationID string) ([]*coreservice.Member, error)
+	AuthenticateServiceUser(ctx context.Context, serviceToken, userID string) (*coreservice.ServiceSession, error)
+}
+
+type workerConfig interface {
+	GetSchedulerPollInterval() time.Duration
+	GetSchedulerRunTimeout() time.Duration
+	GetSchedulerBatchSize() int
+	GetSchedulerServiceToken() string
+}
+
+// Worker выполняет расписания агентов: раз в PollInterval забирает наступившие запуски,
+// выполняет агента без участия пользователя и уведомляет пользователя о результате
+type Worker struct {
+	repo     runRepository
+	executor agentExecutor
+	quota    quotaService
+	members  memberDirectory
+	notifier service.NotificationSink
+	cfg      workerConfig
+}
+
+func NewWorker(repo runRepository, executor agentExecutor, quota quotaService, members memberDirectory, notifier service.NotificationSink, cfg workerConfig) *Worker {
+	return &Worker{
+		repo:     repo,
+		executor: executor,
+		quota:    quota,
+		members:  members,
+		notifier: notifier,
+		cfg:      cfg,
+	}
+}
+
+// Run обрабатывает расписания до отмены контекста
+func (w *Worker) Run(ctx context.Context) {
+	logger.Infof(ctx, "scheduler worker started, poll interval %s", w.cfg.GetSchedulerPollInterval())
+
+	ticker := time.NewTicker(w.cfg.GetSchedulerPollInterval())
+	defer ticker.Stop()
+
+	for {
+		w.poll(ctx)
+
+		select {
+		case <-ctx.Done():
+			logger.Info(ctx, "scheduler worker stopped")
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// poll забирает пачку наступивших запусков и выполняет их по очереди
+func (w *Worker) poll(ctx context.Context) {
+	now := time.Now().UTC()
+
+	// Блокировка с запасом на выполнение всей пачки: если реплика упадет,
+	// расписания снова станут доступны после ее истечения
+	batchSize := w.cfg.GetSchedulerBatchSize()
+	lease := w.cfg.GetSchedulerRunTimeout() * time.Duration(batchSize+1)
+
+	schedules, err := w.repo.ClaimDueSchedules(ctx, now, now.Add(lease), batchSize)
+	if err != nil {
+		logger.Errorf(ctx, "scheduler: failed to claim due schedules: %v", err)
+		return
+	}
+
+	for _, schedule := range schedules {
+		if ctx.Err() != nil {
+			return
+		}
+		w.runSchedule(ctx, schedule)
+	}
+}
+
+// runSchedule выполняет одно расписание, сохраняет результат и отправляет уведомление
+func (w *Worker) runSchedule(ctx context.Context, schedule *domain.AgentSchedule) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "scheduler.Worker.runSchedule")
+	defer span.Finish()
+
+	logger.Infof(ctx, "scheduler: running schedule %s (agent=%s, user=%s)", schedule.ID, schedule.AgentKey, schedule.UserID)
+
+	claimedUpdatedAt := schedule.UpdatedAt
+	startedAt := time.Now().UTC()
+
+	status, chatID, reply, runErr := w.execute(ctx, schedule)
+
+	schedule.RecordRun(startedAt, status, chatID, runErr)
+	if cronSchedule, err := parseSchedule(schedule.CronExpr, schedule.Timezone); err == nil {
+		schedule.NextRunAt = nextRun(cronSchedule, startedAt)
+	} else {
+		// Некорректное расписание не должно запускаться каждый опрос
+		logger.Errorf(ctx, "scheduler: schedule %s has invalid cron expression: %v", schedule.ID, err)
+		schedule.NextRunAt = nil
+	}
+
+	if err := w.repo.CompleteScheduleRun(ctx, schedule, claimedUpdatedAt); err != nil {
+		logger.Errorf(ctx, "scheduler: failed to save run of schedule %s: %v", schedule.ID, err)
+	}
+
+	if runErr != nil {
+		logger.Warnf(ctx, "scheduler: schedule %s finished with status %s: %v", schedule.ID, status, runErr)
+	}
+
+	if err := w.notifier.Notify(ctx, runNotification(schedule, reply, runErr)); err != nil {
+		logger.Errorf(ctx, "scheduler: failed to notify user %s about schedule %s: %v", schedule.UserID, schedule.ID, err)
+	}
+}
+
+// execute проверяет членство и квоту пользователя и выполняет агента
+func (w *Worker) execute(ctx context.Context, schedule *domain.AgentSchedule) (domain.ScheduleRunStatus, *domain.ID, string, error) {
+	// У фонового запуска нет запроса пользователя: токен владельца расписания выдает core-service,
+	// и все запросы к core-service во время запуска выполняются от его имени
+	session, err := w.members.AuthenticateServiceUser(ctx, w.cfg.GetSchedulerServiceToken(), schedule.UserID.String())
+	if status.Code(err) == codes.NotFound {
+		return domain.ScheduleRunStatusSkipped, nil, "", errors.New("user no longer exists")
+	}
+	if err != nil {
+		return domain.ScheduleRunStatusFailed, nil, "", err
+	}
+	ctx = metadata.NewIncomingContext(ctx, metadata.Pairs("authorization", "Bearer "+session.AccessToken))
+
+	member, err := w.activeMember(ctx, schedule.OrganizationID, schedule.UserID)
+	if err != nil {
+		return domain.ScheduleRunStatusFailed, nil, "", err
+	}
+	if member == nil {
+		return domain.ScheduleRunStatusSkipped, nil, "", errors.New("user is no longer an active member of the organization")
+	}
+
+	limits, err := w.quota.GetLimits(ctx, schedule.UserID)
+	if err != nil {
+		return domain.ScheduleRunStatusFailed, nil, "", err
+	}
+	if limits.Remaining() <= 0 {
+		return domain.ScheduleRunStatusSkipped, nil, "", domain.NewQuotaExceededError("daily token limit exceeded")
+	}
+
+	runCtx, cancel := context.WithTimeout(ctx, w.cfg.GetSchedulerRunTimeout())
+	defer cancel()
+
+	stream := &headlessStream{}
+	err = w.executor.ExecuteStream(runCtx, dto.ExecuteAgentDTO{
+		AgentKey:       schedule.AgentKey,
+		OrganizationID: schedule.OrganizationID,
+		UserID:         schedule.UserID,
+		Task:           schedule.Prompt,
+		Title:          runTitle(schedule),
+		Membership:     member,
+	}, stream)
+	if err == nil {
+		err = stream.err
+	}
+	if err != nil {
+		return domain.ScheduleRunStatusFailed, stream.chatID, stream.reply, err
+	}
+
+	return domain.ScheduleRunStatusSuccess, stream.chatID, stream.reply, nil
+}
+
+// activeMember возвращает членство пользователя в организации или nil, если он в ней больше не состоит
+func (w *Worker) activeMember(ctx context.Context, organizationID, userID domain.ID) (*domain.Membership, error) {
+	members, err := w.members.ListOrganizationMembers(ctx, organizationID.String())
+	if err != nil {
+		return nil, err
+	}
+	for _, member := range members {
+		if member.UserID != userID.String() || !member.Active {
+			continue
+		}
+		return &domain.Membership{
+			OrganizationID: organizationID,
+			Role:           domain.OrganizationRole(member.Role),
+			Active:         member.Active,
+			Permissions:    domain.ParsePermissions(member.Permissions),
+		}, nil
+	}
+	return nil, nil
+}
+
+// runTitle - заголовок чата запуска: название расписания и дата
+func runTitle(schedule *domain.AgentSchedule) string {
+	title := schedule.Title
+	if title == "" {
+		title = truncate(schedule.Prompt, 80)
+	}
+
+	date := time.Now()
+	if location, err := time.LoadLocation(schedule.Timezone); err == nil {
+		date = date.In(location)
+	}
+
+	return fmt.Sprintf("%s · %s", title, date.Format("02.01.2006 15:04"))
+}
+
+func runNotification(schedule *domain.AgentSchedule, reply string, runErr error) domain.Notification {
+	title := schedule.Title
+	if title == "" {
+		title = truncate(schedule.Prompt, 80)
+	}
+
+	var text string
+	switch schedule.LastRunStatus {
+	case domain.ScheduleRunStatusSuccess:
+		text = truncate(reply, notificationPreviewLength)
+	case domain.ScheduleRunStatusSkipped:
+		text = "Запуск по расписанию пропущен: " + runErr.Error()
+	default:
+		text = "Не удалось выполнить задачу по расписанию: " + runErr.Error()
+	}
+
+	key := schedule.ID.String()
+	if schedule.LastRunAt != nil {
+		key = fmt.Sprintf("%s:%d", schedule.ID, schedule.LastRunAt.Unix())
+	}
+
+	return domain.Notification{
+		Key:            key,
+		Kind:           domain.NotificationKindScheduledRun,
+		OrganizationID: schedule.OrganizationID,
+		UserID:         schedule.UserID,
+		Title:          title,
+		Text:           text,
+		ChatID:         schedule.LastChatID,
+	}
+}
+
+func truncate(s string, limit int) string {
+	s = strings.TrimSpace(s)
+	if utf8.RuneCountInString(s) <= limit {
+		return s
+	}
+	return string([]rune(s)[:limit]) + "…"
+}
+
+// headlessStream принимает события выполнения без клиента: запоминает созданный чат
+// и последний ответ ассистента
+type headlessStream struct {
+	chatID *domain.ID
+	reply  string
+	err    error
+}
+
+func (s *headlessStream) SendChunk(string) error {
+	return nil
+}
+
+func (s *headlessStream) SendMessage(message *domain.Message) error {
+	if s.chatID == nil {
+		chatID := message.ChatID
+		s.chatID = &chatID
+	}
+	if message.Role == domain.MessageRoleAssistant && strings.TrimSpace(message.Content) != "" {
+		s.reply = message.Content
+	}
+	return nil
+}
+
+func (s *headlessStream) SendToolCall(*domain.ToolCall) error {
+	return nil
+}
+
+func (s *headlessStream) SendError(err error) error {
+	s.err = err
+	return err
+}
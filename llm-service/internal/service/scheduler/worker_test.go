@@ -0,0 +1,395 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	"llm-service/internal/coreservice"
+	"llm-service/internal/domain"
+	"llm-service/internal/domain/dto"
+	"llm-service/internal/logger"
+	"llm-service/internal/service"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+func TestMain(m *testing.M) {
+	logger.Init()
+	os.Exit(m.Run())
+}
+
+// memRunRepository - in-memory реализация runRepository с той же семантикой блокировок,
+// что и запросы ClaimDueSchedules/CompleteScheduleRun
+type memRunRepository struct {
+	mu          sync.Mutex
+	schedules   map[domain.ID]*domain.AgentSchedule
+	lockedUntil map[domain.ID]time.Time
+}
+
+func newMemRunRepository(schedules ...*domain.AgentSchedule) *memRunRepository {
+	r := &memRunRepository{
+		schedules:   make(map[domain.ID]*domain.AgentSchedule),
+		lockedUntil: make(map[domain.ID]time.Time),
+	}
+	for _, schedule := range schedules {
+		r.schedules[schedule.ID] = schedule
+	}
+	return r
+}
+
+func (r *memRunRepository) ClaimDueSchedules(_ context.Context, now, lockedUntil time.Time, limit int) ([]*domain.AgentSchedule, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var claimed []*domain.AgentSchedule
+	for id, schedule := range r.schedules {
+		if len(claimed) == limit {
+			break
+		}
+		if !schedule.Enabled || schedule.NextRunAt == nil || schedule.NextRunAt.After(now) {
+			continue
+		}
+		if until, ok := r.lockedUntil[id]; ok && until.After(now) {
+			continue
+		}
+		r.lockedUntil[id] = lockedUntil
+		copied := *schedule
+		claimed = append(claimed, &copied)
+	}
+	return claimed, nil
+}
+
+func (r *memRunRepository) CompleteScheduleRun(_ context.Context, schedule *domain.AgentSchedule, claimedUpdatedAt time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored, ok := r.schedules[schedule.ID]
+	if !ok {
+		return domain.NewNotFoundError("schedule not found")
+	}
+
+	// Расписание изменили во время запуска: время следующего запуска уже пересчитано при изменении
+	nextRunAt := stored.NextRunAt
+	if stored.UpdatedAt.Equal(claimedUpdatedAt) {
+		nextRunAt = schedule.NextRunAt
+	}
+
+	updated := *schedule
+	updated.NextRunAt = nextRunAt
+	r.schedules[schedule.ID] = &updated
+	delete(r.lockedUntil, schedule.ID)
+	return nil
+}
+
+func (r *memRunRepository) get(id domain.ID) (*domain.AgentSchedule, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	_, locked := r.lockedUntil[id]
+	return r.schedules[id], locked
+}
+
+// authorization возвращает заголовок авторизации, с которым core-service получил бы запрос
+func authorization(ctx context.Context) string {
+	md, _ := metadata.FromIncomingContext(ctx)
+	if values := md.Get("authorization"); len(values) > 0 {
+		return values[0]
+	}
+	return ""
+}
+
+type fakeMembers struct {
+	members []*coreservice.Member
+	authErr error
+
+	serviceToken  string
+	listAuthorize string
+}
+
+func (f *fakeMembers) ListOrganizationMembers(ctx context.Context, _ string) ([]*coreservice.Member, error) {
+	f.listAuthorize = authorization(ctx)
+	if f.listAuthorize == "" {
+		return nil, status.Error(codes.Unauthenticated, "missing authorization")
+	}
+	return f.members, nil
+}
+
+func (f *fakeMembers) AuthenticateServiceUser(_ context.Context, serviceToken, userID string) (*coreservice.ServiceSession, error) {
+	f.serviceToken = serviceToken
+	if f.authErr != nil {
+		return nil, f.authErr
+	}
+	return &coreservice.ServiceSession{AccessToken: "token-" + userID, ExpiresAt: time.Now().Add(time.Hour)}, nil
+}
+
+type fakeQuota struct {
+	limits domain.LLMLimits
+}
+
+func (f *fakeQuota) GetLimits(context.Context, domain.ID) (domain.LLMLimits, error) {
+	return f.limits, nil
+}
+
+type fakeExecutor struct {
+	reply     string
+	err       error
+	calls     []dto.ExecuteAgentDTO
+	authorize string
+}
+
+func (f *fakeExecutor) ExecuteStream(ctx context.Context, req dto.ExecuteAgentDTO, stream service.ExecutionStream) error {
+	f.calls = append(f.calls, req)
+	f.authorize = authorization(ctx)
+	if f.err != nil {
+		return f.err
+	}
+
+	chatID := domain.NewID()
+	if err := stream.SendMessage(&domain.Message{ChatID: chatID, Role: domain.MessageRoleUser, Content: req.Task}); err != nil {
+		return err
+	}
+	return stream.SendMessage(&domain.Message{ChatID: chatID, Role: domain.MessageRoleAssistant, Content: f.reply})
+}
+
+type fakeNotifier struct {
+	notifications []domain.Notification
+}
+
+func (f *fakeNotifier) Notify(_ context.Context, notification domain.Notification) error {
+	f.notifications = append(f.notifications, notification)
+	return nil
+}
+
+type fakeWorkerConfig struct{}
+
+func (fakeWorkerConfig) GetSchedulerPollInterval() time.Duration { return time.Minute }
+func (fakeWorkerConfig) GetSchedulerRunTimeout() time.Duration   { return time.Minute }
+func (fakeWorkerConfig) GetSchedulerBatchSize() int              { return 10 }
+func (fakeWorkerConfig) GetSchedulerServiceToken() string        { return "service-secret" }
+
+type workerEnv struct {
+	worker   *Worker
+	repo     *memRunRepository
+	members  *fakeMembers
+	quota    *fakeQuota
+	executor *fakeExecutor
+	notifier *fakeNotifier
+	schedule *domain.AgentSchedule
+}
+
+// newWorkerEnv создает воркер с одним наступившим расписанием, владелец которого - активный участник организации
+func newWorkerEnv(t *testing.T) *workerEnv {
+	t.Helper()
+
+	past := time.Now().UTC().Add(-time.Minute)
+	schedule := &domain.AgentSchedule{
+		Model:          domain.NewModel(),
+		OrganizationID: domain.NewID(),
+		UserID:         domain.NewID(),
+		Title:          "Сводка",
+		AgentKey:       "main",
+		Prompt:         "Подготовь сводку",
+		CronExpr:       "0 9 * * *",
+		Timezone:       "Europe/Moscow",
+		Enabled:        true,
+		NextRunAt:      &past,
+	}
+
+	env := &workerEnv{
+		repo: newMemRunRepository(schedule),
+		members: &fakeMembers{members: []*coreservice.Member{{
+			UserID:      schedule.UserID.String(),
+			Role:        coreservice.MemberRoleEmployee,
+			Active:      true,
+			Permissions: []string{"crm.read"},
+		}}},
+		quota:    &fakeQuota{limits: domain.NewLLMLimits(1000, 0, 0)},
+		executor: &fakeExecutor{reply: "Готово"},
+		notifier: &fakeNotifier{},
+		schedule: schedule,
+	}
+	env.worker = NewWorker(env.repo, env.executor, env.quota, env.members, env.notifier, fakeWorkerConfig{})
+	return env
+}
+
+func TestWorkerRunsDueScheduleWithServiceToken(t *testing.T) {
+	env := newWorkerEnv(t)
+	before := time.Now().UTC()
+
+	env.worker.poll(context.Background())
+
+	if env.members.serviceToken != "service-secret" {
+		t.Errorf("service token = %q, want %q", env.members.serviceToken, "service-secret")
+	}
+	wantAuth := "Bearer token-" + env.schedule.UserID.String()
+	if env.members.listAuthorize != wantAuth {
+		t.Errorf("ListOrganizationMembers authorization = %q, want %q", env.members.listAuthorize, wantAuth)
+	}
+	if env.executor.authorize != wantAuth {
+		t.Errorf("ExecuteStream authorization = %q, want %q", env.executor.authorize, wantAuth)
+	}
+
+	if len(env.executor.calls) != 1 {
+		t.Fatalf("executor calls = %d, want 1", len(env.executor.calls))
+	}
+	req := env.executor.calls[0]
+	if req.Membership == nil || !req.Membership.HasPermission(domain.PermissionCRMRead) {
+		t.Errorf("execution membership = %+v, want active member with crm.read", req.Membership)
+	}
+
+	stored, locked := env.repo.get(env.schedule.ID)
+	if locked {
+		t.Error("schedule is still locked after the run")
+	}
+	if stored.LastRunStatus != domain.ScheduleRunStatusSuccess {
+		t.Errorf("last run status = %q, want %q (error: %s)", stored.LastRunStatus, domain.ScheduleRunStatusSuccess, stored.LastError)
+	}
+	if stored.LastChatID == nil {
+		t.Error("last chat id is not recorded")
+	}
+	if stored.NextRunAt == nil || !stored.NextRunAt.After(before) {
+		t.Errorf("next run = %v, want a time after %s", stored.NextRunAt, before)
+	} else if stored.NextRunAt.Minute() != 0 || stored.NextRunAt.Hour() != 6 {
+		t.Errorf("next run = %s, want 06:00 UTC (09:00 Europe/Moscow)", stored.NextRunAt)
+	}
+
+	if len(env.notifier.notifications) != 1 || env.notifier.notifications[0].Text != "Готово" {
+		t.Errorf("notifications = %+v, want one with the agent reply", env.notifier.notifications)
+	}
+
+	// Следующий запуск еще не наступил: повторный опрос ничего не выполняет
+	env.worker.poll(context.Background())
+	if len(env.executor.calls) != 1 {
+		t.Errorf("executor calls after second poll = %d, want 1", len(env.executor.calls))
+	}
+}
+
+func TestWorkerSkipsLockedSchedule(t *testing.T) {
+	env := newWorkerEnv(t)
+
+	// Расписание уже забрала другая реплика
+	now := time.Now().UTC()
+	if _, err := env.repo.ClaimDueSchedules(context.Background(), now, now.Add(time.Hour), 10); err != nil {
+		t.Fatalf("ClaimDueSchedules: %v", err)
+	}
+
+	env.worker.poll(context.Background())
+
+	if len(env.executor.calls) != 0 {
+		t.Errorf("executor calls = %d, want 0 for a locked schedule", len(env.executor.calls))
+	}
+}
+
+func TestWorkerKeepsNextRunOfScheduleChangedDuringRun(t *testing.T) {
+	env := newWorkerEnv(t)
+
+	changedNextRun := time.Now().UTC().Add(48 * time.Hour).Truncate(time.Minute)
+	env.worker.executor = executorFunc(func(ctx context.Context, req dto.ExecuteAgentDTO, stream service.ExecutionStream) error {
+		// Пользователь изменил расписание, пока агент выполнялся
+		env.repo.mu.Lock()
+		changed := *env.repo.schedules[env.schedule.ID]
+		changed.NextRunAt = &changedNextRun
+		changed.UpdatedAt = time.Now().UTC().Add(time.Second)
+		env.repo.schedules[env.schedule.ID] = &changed
+		env.repo.mu.Unlock()
+		return env.executor.ExecuteStream(ctx, req, stream)
+	})
+
+	env.worker.poll(context.Background())
+
+	stored, locked := env.repo.get(env.schedule.ID)
+	if locked {
+		t.Error("schedule is still locked after the run")
+	}
+	if stored.NextRunAt == nil || !stored.NextRunAt.Equal(changedNextRun) {
+		t.Errorf("next run = %v, want %s set by the concurrent update", stored.NextRunAt, changedNextRun)
+	}
+}
+
+func TestWorkerRunOutcomes(t *testing.T) {
+	tests := []struct {
+		name       string
+		prepare    func(env *workerEnv)
+		wantStatus domain.ScheduleRunStatus
+		wantRun    bool
+	}{
+		{
+			name: "пользователь удален",
+			prepare: func(env *workerEnv) {
+				env.members.authErr = status.Error(codes.NotFound, "user not found")
+			},
+			wantStatus: domain.ScheduleRunStatusSkipped,
+		},
+		{
+			name: "неверный токен сервиса",
+			prepare: func(env *workerEnv) {
+				env.members.authErr = status.Error(codes.Unauthenticated, "invalid service token")
+			},
+			wantStatus: domain.ScheduleRunStatusFailed,
+		},
+		{
+			name: "пользователь деактивирован",
+			prepare: func(env *workerEnv) {
+				env.members.members[0].Active = false
+			},
+			wantStatus: domain.ScheduleRunStatusSkipped,
+		},
+		{
+			name: "квота исчерпана",
+			prepare: func(env *workerEnv) {
+				env.quota.limits = domain.NewLLMLimits(1000, 1000, 0)
+			},
+			wantStatus: domain.ScheduleRunStatusSkipped,
+		},
+		{
+			name: "ошибка агента",
+			prepare: func(env *workerEnv) {
+				env.executor.err = errors.New("provider unavailable")
+			},
+			wantStatus: domain.ScheduleRunStatusFailed,
+			wantRun:    true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			env := newWorkerEnv(t)
+			tt.prepare(env)
+
+			env.worker.poll(context.Background())
+
+			if ran := len(env.executor.calls) > 0; ran != tt.wantRun {
+				t.Errorf("agent executed = %v, want %v", ran, tt.wantRun)
+			}
+
+			stored, locked := env.repo.get(env.schedule.ID)
+			if locked {
+				t.Error("schedule is still locked after the run")
+			}
+			if stored.LastRunStatus != tt.wantStatus {
+				t.Errorf("last run status = %q, want %q", stored.LastRunStatus, tt.wantStatus)
+			}
+			if stored.LastError == "" {
+				t.Error("last error is empty")
+			}
+			if stored.NextRunAt == nil || !stored.NextRunAt.After(time.Now()) {
+				t.Errorf("next run = %v, want a future time", stored.NextRunAt)
+			}
+			if len(env.notifier.notifications) != 1 {
+				t.Errorf("notifications = %d, want 1", len(env.notifier.notifications))
+			}
+		})
+	}
+}
+
+type executorFunc func(ctx context.Context, req dto.ExecuteAgentDTO, stream service.ExecutionStream) error
+
+func (f executorFunc) ExecuteStream(ctx context.Context, req dto.ExecuteAgentDTO, stream service.ExecutionStream) error {
+	return f(ctx, req, stream)
+}
@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"llm-service/internal/domain"
+	"llm-service/internal/logger"
 	"llm-service/internal/service"
 
 	"github.com/opentracing/opentracing-go"
@@ -19,6 +20,8 @@ type Executor struct {
 	mcpClient                service.MCPClient
 	contractSearchService    service.ContractSearchService
 	contractGeneratorService service.ContractGeneratorService
+	attachmentService        service.AttachmentService
+	auditRecorder            service.AuditRecorder
 }
 
 // NewExecutor создает новый executor для инструментов
@@ -30,6 +33,8 @@ func NewExecutor(
 	mcpClient service.MCPClient,
 	contractSearchService service.ContractSearchService,
 	contractGeneratorService service.ContractGeneratorService,
+	attachmentService service.AttachmentService,
+	auditRecorder service.AuditRecorder,
 ) *Executor {
 	return &Executor{
 		agentManager:             agentManager,
@@ -39,6 +44,8 @@ func NewExecutor(
 		mcpClient:                mcpClient,
 		contractSearchService:    contractSearchService,
 		contractGeneratorService: contractGeneratorService,
+		attachmentService:        attachmentService,
+		auditRecorder:            auditRecorder,
 	}
 }
 
@@ -58,6 +65,41 @@ func (e *Executor) Execute(
 		return nil, domain.NewForbiddenError(fmt.Sprintf("agent %s is not allowed to use tool %s", execCtx.AgentKey, toolName))
 	}
 
+	// Агент действует от имени пользователя и не может выйти за пределы его прав в организации
+	if permission, missing := execCtx.MissingToolPermission(toolName); missing {
+		logger.Warnf(ctx, "tool call denied: user=%s agent=%s tool=%s chat=%s missing permission %s",
+			execCtx.UserID, execCtx.AgentKey, toolName, execCtx.ChatID, permission)
+		e.auditRecorder.Record(ctx, domain.NewToolAuditEvent(domain.AuditActionAgentToolDenied, toolName, execCtx,
+			map[string]any{"missing_permission": string(permission)}))
+		return nil, domain.NewForbiddenError(fmt.Sprintf(
+			"the user is not allowed to use tool %s: permission %s is required in this organization, ask an organization admin to grant it",
+			toolName, permission,
+		))
+	}
+
+	result, err := e.dispatch(ctx, toolName, arguments, execCtx, toolCallID)
+
+	// Изменяющие вызовы фиксируются в журнале аудита вместе с результатом
+	if domain.IsWriteTool(toolName) {
+		metadata := map[string]any{"status": "success"}
+		if err != nil {
+			metadata["status"] = "error"
+			metadata["error"] = err.Error()
+		}
+		e.auditRecorder.Record(ctx, domain.NewToolAuditEvent(domain.AuditActionAgentToolExecuted, toolName, execCtx, metadata))
+	}
+
+	return result, err
+}
+
+// dispatch направляет вызов исполнителю конкретного инструмента
+func (e *Executor) dispatch(
+	ctx context.Context,
+	toolName string,
+	arguments map[string]interface{},
+	execCtx *domain.ExecutionContext,
+	toolCallID *domain.ID,
+) (interface{}, error) {
 	// Проверяем, является ли инструмент MCP инструментом
 	if domain.IsMCPTool(toolName) {
 		// Извлекаем имя инструмента без префикса
@@ -81,6 +123,8 @@ func (e *Executor) Execute(
 		return e.executeGenerateContract(ctx, arguments, execCtx)
 	case domain.ToolNameListGeneratedContracts:
 		return e.executeListGeneratedContracts(ctx, arguments, execCtx)
+	case domain.ToolNameSearchChatFiles:
+		return e.executeSearchChatFiles(ctx, arguments, execCtx)
 	default:
 		return nil, domain.NewInvalidArgumentError(fmt.Sprintf("unknown tool: %s", toolName))
 	}
@@ -239,6 +283,43 @@ func (e *Executor) executeSaveOrganizationNote(
 	return result, nil
 }
 
+// executeSearchChatFiles ищет по файлам, приложенным к текущему чату
+func (e *Executor) executeSearchChatFiles(
+	ctx context.Context,
+	arguments map[string]interface{},
+	execCtx *domain.ExecutionContext,
+) (interface{}, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "tool.Executor.executeSearchChatFiles")
+	defer span.Finish()
+
+	// Парсим аргументы
+	query, ok := arguments["query"].(string)
+	if !ok || query == "" {
+		return nil, domain.NewInvalidArgumentError("query is required and must be a non-empty string")
+	}
+
+	limit := 5 // default
+	if l, ok := arguments["limit"].(float64); ok && l > 0 {
+		limit = int(l)
+	}
+
+	// Файлы привязаны к основному чату, поэтому субагент ищет по тем же файлам
+	chunks, err := e.attachmentService.SearchChatFiles(ctx, execCtx.OrganizationID, execCtx.FilesChatID(), query, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	result := map[string]interface{}{
+		"chunks": chunks,
+		"count":  len(chunks),
+	}
+	if len(chunks) == 0 {
+		result["message"] = "В файлах чата ничего не найдено"
+	}
+
+	return result, nil
+}
+
 // executeMCPTool выполняет вызов MCP инструмента
 func (e *Executor) executeMCPTool(
 	ctx context.Context,
@@ -316,6 +397,19 @@ func (e *Executor) executeGenerateContract(
 		return nil, domain.NewInvalidArgumentError("filled_data is required and must be an object")
 	}
 
+	// Дозаполняем поля из свободного текста; явно переданные значения приоритетнее
+	if sourceText, _ := arguments["source_text"].(string); sourceText != "" {
+		extracted, err := e.contractGeneratorService.ExtractFields(ctx, templateID, sourceText)
+		if err != nil {
+			return nil, err
+		}
+		for name, value := range extracted {
+			if _, exists := filledData[name]; !exists {
+				filledData[name] = value
+			}
+		}
+	}
+
 	// Генерируем контракт
 	result, err := e.contractGeneratorService.GenerateContract(
 		ctx,
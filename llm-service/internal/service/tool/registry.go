@@ -33,6 +33,22 @@ func GetToolsRegistry() map[domain.ToolName]*domain.ToolDefinition {
 			},
 			Required: []string{"content"},
 		},
+		// Инструменты работы с файлами чата
+		domain.ToolNameSearchChatFiles: {
+			Name:        string(domain.ToolNameSearchChatFiles),
+			Description: "Найти фрагменты в больших файлах, которые пользователь приложил к этому чату. Используй, когда в сообщении указано, что файл нужно искать этим инструментом.",
+			Parameters: map[string]interface{}{
+				"query": map[string]interface{}{
+					"type":        "string",
+					"description": "Что нужно найти в файлах чата",
+				},
+				"limit": map[string]interface{}{
+					"type":        "integer",
+					"description": "Максимальное количество фрагментов (по умолчанию 5)",
+				},
+			},
+			Required: []string{"query"},
+		},
 		// Системные инструменты
 		domain.ToolNameSwitchToSubagent: {
 			Name:        string(domain.ToolNameSwitchToSubagent),
@@ -93,6 +109,10 @@ func GetToolsRegistry() map[domain.ToolName]*domain.ToolDefinition {
 					"type":        "object",
 					"description": "Объект с заполненными данными для всех полей шаблона (ключи - имена полей из fields_schema БЕЗ фигурных скобок, значения - введенные пользователем данные)",
 				},
+				"source_text": map[string]interface{}{
+					"type":        "string",
+					"description": "Необязательно: текст пользователя с реквизитами сторон и условиями (например, скопированная карточка компании). Недостающие в filled_data поля будут извлечены из него автоматически",
+				},
 			},
 			Required: []string{"template_id", "contract_name", "filled_data"},
 		},
@@ -3,10 +3,13 @@ package storage
 import (
 	"bytes"
 	"context"
+	"errors"
 	"fmt"
 	"io"
+	"net/http"
 
 	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
 	"github.com/aws/aws-sdk-go/aws/credentials"
 	"github.com/aws/aws-sdk-go/aws/session"
 	"github.com/aws/aws-sdk-go/service/s3"
@@ -82,3 +85,34 @@ func (c *S3Client) DeleteObject(ctx context.Context, key string) error {
 
 	return nil
 }
+
+// ObjectInfo - метаданные объекта в S3
+type ObjectInfo struct {
+	Size        int64
+	ContentType string
+}
+
+// ErrObjectNotFound - объекта с таким ключом нет в бакете
+var ErrObjectNotFound = errors.New("object not found")
+
+func (c *S3Client) HeadObject(ctx context.Context, key string) (ObjectInfo, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "storage.S3Client.HeadObject")
+	defer span.Finish()
+
+	result, err := c.client.HeadObjectWithContext(ctx, &s3.HeadObjectInput{
+		Bucket: aws.String(c.bucket),
+		Key:    aws.String(key),
+	})
+	if err != nil {
+		var aerr awserr.RequestFailure
+		if errors.As(err, &aerr) && aerr.StatusCode() == http.StatusNotFound {
+			return ObjectInfo{}, ErrObjectNotFound
+		}
+		return ObjectInfo{}, fmt.Errorf("failed to head object in S3: %w", err)
+	}
+
+	return ObjectInfo{
+		Size:        aws.Int64Value(result.ContentLength),
+		ContentType: aws.StringValue(result.ContentType),
+	}, nil
+}
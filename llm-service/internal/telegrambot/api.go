@@ -0,0 +1,305 @@
+package telegrambot
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// maxMessageLength - ограничение Telegram на длину текста сообщения (в символах)
+const maxMessageLength = 4096
+
+// Update - входящее событие Bot API
+type Update struct {
+	UpdateID      int64          `json:"update_id"`
+	Message       *Message       `json:"message"`
+	CallbackQuery *CallbackQuery `json:"callback_query"`
+}
+
+// Message - сообщение Telegram
+type Message struct {
+	MessageID int64  `json:"message_id"`
+	From      *User  `json:"from"`
+	Chat      Chat   `json:"chat"`
+	Text      string `json:"text"`
+	// Voice - голосовое сообщение, Audio - аудиофайл
+	Voice *File `json:"voice"`
+	Audio *File `json:"audio"`
+}
+
+// File - файл сообщения; содержимое скачивается через getFile
+type File struct {
+	FileID   string `json:"file_id"`
+	FileName string `json:"file_name"`
+	MimeType string `json:"mime_type"`
+	FileSize int64  `json:"file_size"`
+	// FilePath - путь для скачивания, заполняется в ответе getFile
+	FilePath string `json:"file_path"`
+}
+
+// User - пользователь Telegram
+type User struct {
+	ID        int64  `json:"id"`
+	FirstName string `json:"first_name"`
+}
+
+// Chat - чат Telegram
+type Chat struct {
+	ID   int64  `json:"id"`
+	Type string `json:"type"`
+}
+
+// CallbackQuery - нажатие на inline-кнопку
+type CallbackQuery struct {
+	ID      string   `json:"id"`
+	From    User     `json:"from"`
+	Message *Message `json:"message"`
+	Data    string   `json:"data"`
+}
+
+// InlineKeyboardMarkup - inline-клавиатура под сообщением
+type InlineKeyboardMarkup struct {
+	InlineKeyboard [][]InlineKeyboardButton `json:"inline_keyboard"`
+}
+
+// InlineKeyboardButton - кнопка inline-клавиатуры: callback, ссылка или mini app
+type InlineKeyboardButton struct {
+	Text         string      `json:"text"`
+	CallbackData string      `json:"callback_data,omitempty"`
+	URL          string      `json:"url,omitempty"`
+	WebApp       *WebAppInfo `json:"web_app,omitempty"`
+}
+
+// WebAppInfo - ссылка на mini app
+type WebAppInfo struct {
+	URL string `json:"url"`
+}
+
+// APIError - ошибка, которую вернул Bot API
+type APIError struct {
+	Code        int
+	Description string
+	// RetryAfter - сколько ждать перед повтором при превышении лимитов
+	RetryAfter time.Duration
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("telegram api error %d: %s", e.Code, e.Description)
+}
+
+// isNotModified - Telegram отклоняет редактирование, если текст не изменился
+func isNotModified(err error) bool {
+	apiErr, ok := err.(*APIError)
+	return ok && strings.Contains(apiErr.Description, "message is not modified")
+}
+
+type apiResponse struct {
+	OK          bool            `json:"ok"`
+	Result      json.RawMessage `json:"result"`
+	ErrorCode   int             `json:"error_code"`
+	Description string          `json:"description"`
+	Parameters  *struct {
+		RetryAfter int `json:"retry_after"`
+	} `json:"parameters"`
+}
+
+// API - клиент Telegram Bot API
+type API struct {
+	baseURL     string
+	fileBaseURL string
+	httpClient  *http.Client
+}
+
+// NewAPI создает клиент бота; apiURL обычно https://api.telegram.org
+func NewAPI(apiURL, token string) *API {
+	apiURL = strings.TrimRight(apiURL, "/")
+	return &API{
+		baseURL:     fmt.Sprintf("%s/bot%s", apiURL, token),
+		fileBaseURL: fmt.Sprintf("%s/file/bot%s", apiURL, token),
+		httpClient:  &http.Client{},
+	}
+}
+
+// GetUpdates получает новые события long polling'ом
+func (a *API) GetUpdates(ctx context.Context, offset int64, timeout time.Duration) ([]Update, error) {
+	var updates []Update
+	err := a.call(ctx, "getUpdates", map[string]any{
+		"offset":          offset,
+		"timeout":         int(timeout.Seconds()),
+		"allowed_updates": []string{"message", "callback_query"},
+	}, &updates)
+	return updates, err
+}
+
+// SendMessage отправляет текстовое сообщение без разметки
+func (a *API) SendMessage(ctx context.Context, chatID int64, text string, markup *InlineKeyboardMarkup) (*Message, error) {
+	params := map[string]any{
+		"chat_id":                  chatID,
+		"text":                     text,
+		"disable_web_page_preview": true,
+	}
+	if markup != nil {
+		params["reply_markup"] = markup
+	}
+
+	var message Message
+	if err := a.call(ctx, "sendMessage", params, &message); err != nil {
+		return nil, err
+	}
+	return &message, nil
+}
+
+// EditMessageText заменяет текст отправленного сообщения; неизмененный текст не считается ошибкой
+func (a *API) EditMessageText(ctx context.Context, chatID, messageID int64, text string, markup *InlineKeyboardMarkup) error {
+	params := map[string]any{
+		"chat_id":                  chatID,
+		"message_id":               messageID,
+		"text":                     text,
+		"disable_web_page_preview": true,
+	}
+	if markup != nil {
+		params["reply_markup"] = markup
+	}
+
+	err := a.call(ctx, "editMessageText", params, nil)
+	if isNotModified(err) {
+		return nil
+	}
+	return err
+}
+
+// SendChatAction показывает статус «печатает…» / «отправляет файл…»
+func (a *API) SendChatAction(ctx context.Context, chatID int64, action string) error {
+	return a.call(ctx, "sendChatAction", map[string]any{
+		"chat_id": chatID,
+		"action":  action,
+	}, nil)
+}
+
+// AnswerCallbackQuery подтверждает нажатие inline-кнопки
+func (a *API) AnswerCallbackQuery(ctx context.Context, callbackQueryID, text string) error {
+	return a.call(ctx, "answerCallbackQuery", map[string]any{
+		"callback_query_id": callbackQueryID,
+		"text":              text,
+	}, nil)
+}
+
+// DownloadFile скачивает файл сообщения не больше maxSize байт
+func (a *API) DownloadFile(ctx context.Context, fileID string, maxSize int64) ([]byte, error) {
+	var file File
+	if err := a.call(ctx, "getFile", map[string]any{"file_id": fileID}, &file); err != nil {
+		return nil, err
+	}
+	if file.FilePath == "" {
+		return nil, fmt.Errorf("telegram returned no path for file %s", fileID)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, a.fileBaseURL+"/"+file.FilePath, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create telegram request: %w", err)
+	}
+
+	resp, err := a.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to download telegram file: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to download telegram file: status %d", resp.StatusCode)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read telegram file: %w", err)
+	}
+	if int64(len(data)) > maxSize {
+		return nil, fmt.Errorf("telegram file %s is larger than %d bytes", fileID, maxSize)
+	}
+
+	return data, nil
+}
+
+// SendDocument отправляет файл документом
+func (a *API) SendDocument(ctx context.Context, chatID int64, fileName string, file io.Reader, caption string) error {
+	body := &bytes.Buffer{}
+	writer := multipart.NewWriter(body)
+
+	if err := writer.WriteField("chat_id", strconv.FormatInt(chatID, 10)); err != nil {
+		return err
+	}
+	if caption != "" {
+		if err := writer.WriteField("caption", caption); err != nil {
+			return err
+		}
+	}
+	part, err := writer.CreateFormFile("document", fileName)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(part, file); err != nil {
+		return fmt.Errorf("failed to read document: %w", err)
+	}
+	if err := writer.Close(); err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, a.baseURL+"/sendDocument", body)
+	if err != nil {
+		return fmt.Errorf("failed to create telegram request: %w", err)
+	}
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+
+	return a.do(req, nil)
+}
+
+func (a *API) call(ctx context.Context, method string, params any, result any) error {
+	body, err := json.Marshal(params)
+	if err != nil {
+		return fmt.Errorf("failed to marshal telegram request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, a.baseURL+"/"+method, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create telegram request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	return a.do(req, result)
+}
+
+func (a *API) do(req *http.Request, result any) error {
+	resp, err := a.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to call telegram: %w", err)
+	}
+	defer resp.Body.Close()
+
+	var apiResp apiResponse
+	if err := json.NewDecoder(resp.Body).Decode(&apiResp); err != nil {
+		return fmt.Errorf("failed to decode telegram response (status %d): %w", resp.StatusCode, err)
+	}
+
+	if !apiResp.OK {
+		apiErr := &APIError{Code: apiResp.ErrorCode, Description: apiResp.Description}
+		if apiResp.Parameters != nil {
+			apiErr.RetryAfter = time.Duration(apiResp.Parameters.RetryAfter) * time.Second
+		}
+		return apiErr
+	}
+
+	if result != nil {
+		if err := json.Unmarshal(apiResp.Result, result); err != nil {
+			return fmt.Errorf("failed to decode telegram result: %w", err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,605 @@
+package telegrambot
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"llm-service/internal/coreservice"
+	"llm-service/internal/domain"
+	"llm-service/internal/domain/dto"
+	"llm-service/internal/logger"
+	"llm-service/internal/service"
+
+	"github.com/opentracing/opentracing-go"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// callbackOrgPrefix - префикс callback_data кнопок выбора организации
+const callbackOrgPrefix = "org:"
+
+// sessionRefreshMargin - за сколько до истечения токена бот получает новый
+const sessionRefreshMargin = time.Minute
+
+// errNotRegistered - пользователь Telegram еще не прошел регистрацию в mini app
+var errNotRegistered = errors.New("telegram user is not registered")
+
+type coreClient interface {
+	AuthenticateBotUser(ctx context.Context, botToken, telegramID string) (*coreservice.BotSession, error)
+}
+
+type bindingRepository interface {
+	GetActiveTelegramBinding(ctx context.Context, telegramChatID int64) (*domain.TelegramBinding, error)
+	ActivateTelegramBinding(ctx context.Context, binding *domain.TelegramBinding) error
+	SetTelegramBindingChat(ctx context.Context, telegramChatID int64, organizationID domain.ID, chatID *domain.ID) error
+}
+
+type membershipProvider interface {
+	GetMembership(ctx context.Context, userID, organizationID domain.ID) (*domain.Membership, error)
+}
+
+type agentExecutor interface {
+	SendMessageStream(ctx context.Context, req dto.SendMessageDTO, stream service.MessageStream) error
+}
+
+type objectStorage interface {
+	GetObject(ctx context.Context, key string) (io.ReadCloser, error)
+	PutObject(ctx context.Context, key string, data []byte, contentType string) error
+}
+
+type botConfig interface {
+	GetTelegramBotToken() string
+	GetTelegramBotWebAppURL() string
+	GetTelegramBotEditInterval() time.Duration
+	GetTelegramBotPollTimeout() time.Duration
+}
+
+// session - токен пользователя, выданный core-service для работы бота от его имени
+type session struct {
+	*coreservice.BotSession
+	userID domain.ID
+}
+
+func (s *session) organization(id domain.ID) *coreservice.Organization {
+	for _, org := range s.Organizations {
+		if org.ID == id.String() {
+			return org
+		}
+	}
+	return nil
+}
+
+// Bot - чат с агентом в личных сообщениях Telegram: каждая организация пользователя
+// продолжает свой чат агента, ответ стримится редактированием сообщения
+type Bot struct {
+	api         *API
+	core        coreClient
+	bindings    bindingRepository
+	memberships membershipProvider
+	executor    agentExecutor
+	storage     objectStorage
+	cfg         botConfig
+
+	mu       sync.Mutex
+	sessions map[int64]*session
+	// busy - Telegram-чаты, в которых агент еще отвечает на предыдущее сообщение
+	busy map[int64]bool
+}
+
+func New(
+	api *API,
+	core coreClient,
+	bindings bindingRepository,
+	memberships membershipProvider,
+	executor agentExecutor,
+	storage objectStorage,
+	cfg botConfig,
+) *Bot {
+	return &Bot{
+		api:         api,
+		core:        core,
+		bindings:    bindings,
+		memberships: memberships,
+		executor:    executor,
+		storage:     storage,
+		cfg:         cfg,
+		sessions:    make(map[int64]*session),
+		busy:        make(map[int64]bool),
+	}
+}
+
+// Run получает обновления long polling'ом до отмены контекста
+func (b *Bot) Run(ctx context.Context) {
+	logger.Info(ctx, "telegram bot started")
+
+	var offset int64
+	for {
+		updates, err := b.api.GetUpdates(ctx, offset, b.cfg.GetTelegramBotPollTimeout())
+		if ctx.Err() != nil {
+			logger.Info(ctx, "telegram bot stopped")
+			return
+		}
+		if err != nil {
+			logger.Errorf(ctx, "telegram bot: failed to get updates: %v", err)
+
+			delay := 3 * time.Second
+			var apiErr *APIError
+			if errors.As(err, &apiErr) && apiErr.RetryAfter > 0 {
+				delay = apiErr.RetryAfter
+			}
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(delay):
+			}
+			continue
+		}
+
+		for _, update := range updates {
+			offset = update.UpdateID + 1
+			go b.handleUpdate(ctx, update)
+		}
+	}
+}
+
+func (b *Bot) handleUpdate(ctx context.Context, update Update) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "telegrambot.Bot.handleUpdate")
+	defer span.Finish()
+
+	switch {
+	case update.CallbackQuery != nil:
+		b.handleCallback(ctx, update.CallbackQuery)
+	case update.Message != nil && update.Message.From != nil && update.Message.Chat.Type == "private":
+		b.handleMessage(ctx, update.Message)
+	}
+}
+
+func (b *Bot) handleMessage(ctx context.Context, message *Message) {
+	chatID := message.Chat.ID
+	text := strings.TrimSpace(message.Text)
+
+	// Голосовые сообщения и аудиофайлы агент получает расшифровкой
+	if voice := message.Voice; voice != nil || message.Audio != nil {
+		if voice == nil {
+			voice = message.Audio
+		}
+		b.handleQuestion(ctx, chatID, message.From, "", voice)
+		return
+	}
+
+	if text == "" {
+		b.reply(ctx, chatID, "Я понимаю текстовые и голосовые сообщения.", nil)
+		return
+	}
+
+	if strings.HasPrefix(text, "/") {
+		command, _, _ := strings.Cut(strings.Fields(text)[0], "@")
+		switch command {
+		case "/start":
+			b.handleStart(ctx, chatID, message.From)
+		case "/help":
+			b.reply(ctx, chatID, helpText, nil)
+		case "/new":
+			b.handleNewChat(ctx, chatID, message.From)
+		case "/org":
+			b.handleOrgList(ctx, chatID, message.From)
+		default:
+			b.reply(ctx, chatID, "Неизвестная команда. "+helpText, nil)
+		}
+		return
+	}
+
+	b.handleQuestion(ctx, chatID, message.From, text, nil)
+}
+
+const helpText = `Напишите вопрос — агент ответит с учетом данных вашей организации и продолжит текущий чат.
+
+/new — начать новый чат
+/org — выбрать организацию
+/help — эта справка`
+
+func (b *Bot) handleStart(ctx context.Context, chatID int64, from *User) {
+	sess, ok := b.session(ctx, chatID, from, false)
+	if !ok {
+		return
+	}
+
+	_, org, ok := b.activeBinding(ctx, chatID, sess)
+	if !ok {
+		return
+	}
+
+	text := fmt.Sprintf("Здравствуйте, %s!\n\nТекущая организация: %s.\n\n%s", sess.FirstName, org.Name, helpText)
+	b.reply(ctx, chatID, text, b.webAppKeyboard("Открыть приложение"))
+}
+
+func (b *Bot) handleNewChat(ctx context.Context, chatID int64, from *User) {
+	sess, ok := b.session(ctx, chatID, from, false)
+	if !ok {
+		return
+	}
+
+	binding, org, ok := b.activeBinding(ctx, chatID, sess)
+	if !ok {
+		return
+	}
+
+	if err := b.bindings.SetTelegramBindingChat(ctx, chatID, binding.OrganizationID, nil); err != nil {
+		logger.Errorf(ctx, "telegram bot: failed to reset chat for %d: %v", chatID, err)
+		b.reply(ctx, chatID, "Не удалось начать новый чат, попробуйте позже.", nil)
+		return
+	}
+
+	b.reply(ctx, chatID, fmt.Sprintf("Начат новый чат в организации «%s». Напишите вопрос.", org.Name), nil)
+}
+
+func (b *Bot) handleOrgList(ctx context.Context, chatID int64, from *User) {
+	// Список организаций берем свежий: пользователя могли пригласить или исключить
+	sess, ok := b.session(ctx, chatID, from, true)
+	if !ok {
+		return
+	}
+
+	binding, _, ok := b.activeBinding(ctx, chatID, sess)
+	if !ok {
+		return
+	}
+
+	keyboard := &InlineKeyboardMarkup{}
+	for _, org := range sess.Organizations {
+		label := org.Name
+		if org.ID == binding.OrganizationID.String() {
+			label = "✓ " + label
+		}
+		keyboard.InlineKeyboard = append(keyboard.InlineKeyboard, []InlineKeyboardButton{{
+			Text:         label,
+			CallbackData: callbackOrgPrefix + org.ID,
+		}})
+	}
+
+	b.reply(ctx, chatID, "Выберите организацию. У каждой организации свой чат с агентом.", keyboard)
+}
+
+func (b *Bot) handleCallback(ctx context.Context, query *CallbackQuery) {
+	if query.Message == nil || !strings.HasPrefix(query.Data, callbackOrgPrefix) {
+		b.answerCallback(ctx, query.ID, "")
+		return
+	}
+	chatID := query.Message.Chat.ID
+
+	orgID, err := domain.ParseID(strings.TrimPrefix(query.Data, callbackOrgPrefix))
+	if err != nil {
+		b.answerCallback(ctx, query.ID, "Неизвестная организация")
+		return
+	}
+
+	sess, ok := b.session(ctx, chatID, &query.From, false)
+	if !ok {
+		b.answerCallback(ctx, query.ID, "")
+		return
+	}
+
+	org := sess.organization(orgID)
+	if org == nil {
+		b.answerCallback(ctx, query.ID, "Вы больше не состоите в этой организации")
+		return
+	}
+
+	binding := &domain.TelegramBinding{
+		TelegramChatID: chatID,
+		OrganizationID: orgID,
+		UserID:         sess.userID,
+		UpdatedAt:      time.Now().UTC(),
+	}
+	if err := b.bindings.ActivateTelegramBinding(ctx, binding); err != nil {
+		logger.Errorf(ctx, "telegram bot: failed to switch organization for %d: %v", chatID, err)
+		b.answerCallback(ctx, query.ID, "Не удалось сменить организацию")
+		return
+	}
+
+	b.answerCallback(ctx, query.ID, "Организация выбрана")
+
+	text := fmt.Sprintf("Текущая организация: %s. Продолжаем ее чат с агентом, /new — начать новый.", org.Name)
+	if err := b.api.EditMessageText(ctx, chatID, query.Message.MessageID, text, nil); err != nil {
+		logger.Warnf(ctx, "telegram bot: failed to update organization list: %v", err)
+	}
+}
+
+// handleQuestion отправляет сообщение агенту в чат активной организации и стримит ответ
+func (b *Bot) handleQuestion(ctx context.Context, chatID int64, from *User, text string, voice *File) {
+	if !b.acquire(chatID) {
+		b.reply(ctx, chatID, "Подождите, я еще отвечаю на предыдущее сообщение.", nil)
+		return
+	}
+	defer b.release(chatID)
+
+	sess, ok := b.session(ctx, chatID, from, false)
+	if !ok {
+		return
+	}
+
+	binding, _, ok := b.activeBinding(ctx, chatID, sess)
+	if !ok {
+		return
+	}
+
+	// Запросы к core-service выполняются от имени пользователя, как из mini app
+	agentCtx := metadata.NewIncomingContext(ctx, metadata.Pairs("authorization", "Bearer "+sess.AccessToken))
+
+	// Агент вызывает инструменты в пределах прав пользователя в выбранной организации
+	member, err := b.memberships.GetMembership(agentCtx, sess.userID, binding.OrganizationID)
+	if errors.Is(err, domain.ErrNotFound) || (err == nil && !member.Active) {
+		b.reply(ctx, chatID, "У вас больше нет доступа к этой организации. Выберите другую командой /org.", nil)
+		return
+	}
+	if err != nil {
+		logger.Errorf(ctx, "telegram bot: failed to get membership of %d: %v", chatID, err)
+		b.reply(ctx, chatID, "Сервис временно недоступен, попробуйте позже.", nil)
+		return
+	}
+
+	var attachments []dto.AttachmentDTO
+	if voice != nil {
+		attachment, err := b.uploadVoice(ctx, binding.OrganizationID, voice)
+		if err != nil {
+			logger.Errorf(ctx, "telegram bot: failed to upload voice message of %d: %v", chatID, err)
+			b.reply(ctx, chatID, "Не удалось обработать голосовое сообщение. Оно должно быть не длиннее нескольких минут.", nil)
+			return
+		}
+		attachments = append(attachments, *attachment)
+	}
+
+	placeholder, err := b.api.SendMessage(ctx, chatID, "⏳ Думаю…", nil)
+	if err != nil {
+		logger.Errorf(ctx, "telegram bot: failed to send placeholder to %d: %v", chatID, err)
+		return
+	}
+
+	stream := newReplyStream(ctx, b.api, chatID, placeholder.MessageID, b.cfg.GetTelegramBotEditInterval())
+	err = b.executor.SendMessageStream(agentCtx, dto.SendMessageDTO{
+		ChatID:      binding.ChatID,
+		UserID:      sess.userID,
+		OrgID:       binding.OrganizationID,
+		Content:     text,
+		Attachments: attachments,
+		Membership:  member,
+	}, stream)
+	if err == nil {
+		err = stream.err
+	}
+
+	if stream.chat != nil && (binding.ChatID == nil || *binding.ChatID != stream.chat.ID) {
+		if bindErr := b.bindings.SetTelegramBindingChat(ctx, chatID, binding.OrganizationID, &stream.chat.ID); bindErr != nil {
+			logger.Errorf(ctx, "telegram bot: failed to save chat of %d: %v", chatID, bindErr)
+		}
+	}
+
+	if err != nil {
+		logger.Errorf(ctx, "telegram bot: agent failed for %d: %v", chatID, err)
+		// Чат удалили или к нему закрыли доступ - следующее сообщение начнет новый
+		if binding.ChatID != nil && (errors.Is(err, domain.ErrNotFound) || errors.Is(err, domain.ErrForbidden)) {
+			if bindErr := b.bindings.SetTelegramBindingChat(ctx, chatID, binding.OrganizationID, nil); bindErr != nil {
+				logger.Errorf(ctx, "telegram bot: failed to reset chat of %d: %v", chatID, bindErr)
+			}
+		}
+		stream.Finish(errorText(err))
+		return
+	}
+
+	stream.Finish("")
+	b.sendContracts(ctx, chatID, stream)
+}
+
+// uploadVoice копирует голосовое сообщение из Telegram в S3 организации, откуда его
+// распознает агент; файл остается исходником расшифровки в сообщении чата
+func (b *Bot) uploadVoice(ctx context.Context, organizationID domain.ID, voice *File) (*dto.AttachmentDTO, error) {
+	if voice.FileSize > domain.MaxAttachmentSize {
+		return nil, fmt.Errorf("voice message is larger than %d bytes", domain.MaxAttachmentSize)
+	}
+
+	data, err := b.api.DownloadFile(ctx, voice.FileID, domain.MaxAttachmentSize)
+	if err != nil {
+		return nil, err
+	}
+
+	contentType := voice.MimeType
+	if contentType == "" {
+		contentType = "audio/ogg"
+	}
+	fileName := voice.FileName
+	if fileName == "" {
+		fileName = "voice" + audioExtensions[contentType]
+	}
+
+	key := domain.AttachmentKeyPrefix(organizationID) + "telegram/" + domain.NewID().String() + path.Ext(fileName)
+	if err := b.storage.PutObject(ctx, key, data, contentType); err != nil {
+		return nil, err
+	}
+
+	return &dto.AttachmentDTO{
+		S3Key:       key,
+		FileName:    fileName,
+		ContentType: contentType,
+	}, nil
+}
+
+// audioExtensions - расширения файлов голосовых сообщений без имени
+var audioExtensions = map[string]string{
+	"audio/ogg":  ".ogg",
+	"audio/mpeg": ".mp3",
+	"audio/mp4":  ".m4a",
+}
+
+// sendContracts отправляет договоры, сформированные агентом, файлами
+func (b *Bot) sendContracts(ctx context.Context, chatID int64, stream *replyStream) {
+	for _, contract := range stream.contracts {
+		if err := b.api.SendChatAction(ctx, chatID, "upload_document"); err != nil {
+			logger.Warnf(ctx, "telegram bot: failed to send chat action: %v", err)
+		}
+
+		file, err := b.storage.GetObject(ctx, contract.S3Key)
+		if err != nil {
+			logger.Errorf(ctx, "telegram bot: failed to load contract %s: %v", contract.ContractID, err)
+			b.reply(ctx, chatID, fmt.Sprintf("Не удалось загрузить договор «%s», он доступен в приложении.", contract.Name), nil)
+			continue
+		}
+
+		fileName := contract.Name + path.Ext(contract.S3Key)
+		err = b.api.SendDocument(ctx, chatID, fileName, file, contract.TemplateName)
+		file.Close()
+		if err != nil {
+			logger.Errorf(ctx, "telegram bot: failed to send contract %s: %v", contract.ContractID, err)
+			b.reply(ctx, chatID, fmt.Sprintf("Не удалось отправить договор «%s», он доступен в приложении.", contract.Name), nil)
+		}
+	}
+}
+
+// session возвращает токен пользователя; незарегистрированному пользователю предлагает открыть mini app
+func (b *Bot) session(ctx context.Context, chatID int64, from *User, refresh bool) (*session, bool) {
+	b.mu.Lock()
+	cached := b.sessions[from.ID]
+	b.mu.Unlock()
+
+	if cached != nil && !refresh && time.Until(cached.ExpiresAt) > sessionRefreshMargin {
+		return cached, true
+	}
+
+	botSession, err := b.core.AuthenticateBotUser(ctx, b.cfg.GetTelegramBotToken(), strconv.FormatInt(from.ID, 10))
+	if err == nil {
+		var userID domain.ID
+		userID, err = domain.ParseID(botSession.UserID)
+		if err == nil {
+			sess := &session{BotSession: botSession, userID: userID}
+			b.mu.Lock()
+			b.sessions[from.ID] = sess
+			b.mu.Unlock()
+			return sess, true
+		}
+	}
+
+	if status.Code(err) == codes.NotFound {
+		err = errNotRegistered
+	}
+
+	b.mu.Lock()
+	delete(b.sessions, from.ID)
+	b.mu.Unlock()
+
+	if errors.Is(err, errNotRegistered) {
+		b.reply(ctx, chatID, "Чтобы пользоваться ботом, зарегистрируйтесь в приложении и вступите в организацию.", b.webAppKeyboard("Зарегистрироваться"))
+		return nil, false
+	}
+
+	logger.Errorf(ctx, "telegram bot: failed to authenticate telegram user %d: %v", from.ID, err)
+	b.reply(ctx, chatID, "Сервис временно недоступен, попробуйте позже.", nil)
+	return nil, false
+}
+
+// activeBinding возвращает организацию, в которой пользователь сейчас общается с агентом.
+// Если выбранной организации нет или пользователь из нее вышел, выбирается первая доступная.
+func (b *Bot) activeBinding(ctx context.Context, chatID int64, sess *session) (*domain.TelegramBinding, *coreservice.Organization, bool) {
+	if len(sess.Organizations) == 0 {
+		b.reply(ctx, chatID, "Вы пока не состоите ни в одной организации. Создайте организацию или примите приглашение в приложении.", b.webAppKeyboard("Открыть приложение"))
+		return nil, nil, false
+	}
+
+	binding, err := b.bindings.GetActiveTelegramBinding(ctx, chatID)
+	if err != nil && !errors.Is(err, domain.ErrNotFound) {
+		logger.Errorf(ctx, "telegram bot: failed to get binding of %d: %v", chatID, err)
+		b.reply(ctx, chatID, "Сервис временно недоступен, попробуйте позже.", nil)
+		return nil, nil, false
+	}
+
+	if binding != nil && binding.UserID == sess.userID {
+		if org := sess.organization(binding.OrganizationID); org != nil {
+			return binding, org, true
+		}
+	}
+
+	org := sess.Organizations[0]
+	orgID, err := domain.ParseID(org.ID)
+	if err != nil {
+		logger.Errorf(ctx, "telegram bot: invalid organization id %q: %v", org.ID, err)
+		b.reply(ctx, chatID, "Сервис временно недоступен, попробуйте позже.", nil)
+		return nil, nil, false
+	}
+
+	binding = &domain.TelegramBinding{
+		TelegramChatID: chatID,
+		OrganizationID: orgID,
+		UserID:         sess.userID,
+		UpdatedAt:      time.Now().UTC(),
+	}
+	if err := b.bindings.ActivateTelegramBinding(ctx, binding); err != nil {
+		logger.Errorf(ctx, "telegram bot: failed to activate binding of %d: %v", chatID, err)
+		b.reply(ctx, chatID, "Сервис временно недоступен, попробуйте позже.", nil)
+		return nil, nil, false
+	}
+
+	return binding, org, true
+}
+
+func (b *Bot) acquire(chatID int64) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.busy[chatID] {
+		return false
+	}
+	b.busy[chatID] = true
+	return true
+}
+
+func (b *Bot) release(chatID int64) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	delete(b.busy, chatID)
+}
+
+func (b *Bot) reply(ctx context.Context, chatID int64, text string, markup *InlineKeyboardMarkup) {
+	if _, err := b.api.SendMessage(ctx, chatID, text, markup); err != nil {
+		logger.Errorf(ctx, "telegram bot: failed to send message to %d: %v", chatID, err)
+	}
+}
+
+func (b *Bot) answerCallback(ctx context.Context, callbackQueryID, text string) {
+	if err := b.api.AnswerCallbackQuery(ctx, callbackQueryID, text); err != nil {
+		logger.Warnf(ctx, "telegram bot: failed to answer callback: %v", err)
+	}
+}
+
+// webAppKeyboard - кнопка, открывающая mini app; без настроенной ссылки кнопки нет
+func (b *Bot) webAppKeyboard(text string) *InlineKeyboardMarkup {
+	url := b.cfg.GetTelegramBotWebAppURL()
+	if url == "" {
+		return nil
+	}
+	return &InlineKeyboardMarkup{
+		InlineKeyboard: [][]InlineKeyboardButton{{{Text: text, WebApp: &WebAppInfo{URL: url}}}},
+	}
+}
+
+// errorText - понятное пользователю описание ошибки агента
+func errorText(err error) string {
+	switch {
+	case errors.Is(err, domain.ErrInvalidArgument):
+		return "⚠️ Не удалось распознать сообщение. Попробуйте написать текстом."
+	case errors.Is(err, domain.ErrTooManyRequests):
+		return "⚠️ Дневной лимит запросов к агенту исчерпан. Попробуйте завтра."
+	case errors.Is(err, domain.ErrNotFound):
+		return "⚠️ Чат больше недоступен. Следующее сообщение начнет новый чат."
+	case errors.Is(err, domain.ErrForbidden):
+		return "⚠️ Нет доступа к чату. Следующее сообщение начнет новый чат."
+	default:
+		return "⚠️ Не удалось получить ответ агента, попробуйте еще раз."
+	}
+}
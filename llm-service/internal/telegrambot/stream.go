@@ -0,0 +1,233 @@
+package telegrambot
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"llm-service/internal/contracts"
+	"llm-service/internal/domain"
+	"llm-service/internal/domain/dto"
+	"llm-service/internal/logger"
+)
+
+// toolStatuses - что показывать пользователю, пока выполняется инструмент
+var toolStatuses = map[domain.ToolName]string{
+	domain.ToolNameWebSearch:               "Ищу в интернете…",
+	domain.ToolNameSearchChatFiles:         "Ищу в файлах чата…",
+	domain.ToolNameSaveOrganizationNote:    "Запоминаю…",
+	domain.ToolNameSearchContractTemplates: "Ищу шаблоны договоров…",
+	domain.ToolNameGenerateContract:        "Формирую договор…",
+	domain.ToolNameListGeneratedContracts:  "Смотрю договоры организации…",
+	domain.ToolNameSwitchToSubagent:        "Передаю задачу профильному агенту…",
+}
+
+// replyStream показывает ответ агента в одном сообщении Telegram и обновляет его
+// по мере генерации не чаще раза в interval (ограничения Bot API на редактирование)
+type replyStream struct {
+	ctx       context.Context
+	api       *API
+	chatID    int64
+	messageID int64
+	interval  time.Duration
+
+	mu sync.Mutex
+	// parts - сохраненные ответы ассистента, draft - ответ, который еще генерируется
+	parts    []string
+	draft    strings.Builder
+	status   string
+	shown    string
+	lastEdit time.Time
+
+	chat      *domain.Chat
+	contracts []contracts.GeneratedContract
+	err       error
+}
+
+func newReplyStream(ctx context.Context, api *API, chatID, messageID int64, interval time.Duration) *replyStream {
+	return &replyStream{
+		ctx:       ctx,
+		api:       api,
+		chatID:    chatID,
+		messageID: messageID,
+		interval:  interval,
+	}
+}
+
+func (s *replyStream) SendChunk(content string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.draft.WriteString(content)
+	s.flush()
+	return nil
+}
+
+func (s *replyStream) SendMessage(message *domain.Message) error {
+	if message.Role != domain.MessageRoleAssistant {
+		return nil
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if content := strings.TrimSpace(message.Content); content != "" {
+		s.parts = append(s.parts, content)
+	}
+	s.draft.Reset()
+	s.flush()
+	return nil
+}
+
+func (s *replyStream) SendToolCall(toolCall *domain.ToolCall) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	switch toolCall.Status {
+	case domain.ToolCallStatusPending, domain.ToolCallStatusExecuting:
+		s.status = toolStatus(toolCall.Name)
+	case domain.ToolCallStatusCompleted:
+		s.status = ""
+		if domain.ToolName(toolCall.Name) == domain.ToolNameGenerateContract {
+			var contract contracts.GeneratedContract
+			if err := json.Unmarshal(toolCall.Result, &contract); err == nil && contract.S3Key != "" {
+				s.contracts = append(s.contracts, contract)
+			}
+		}
+	default:
+		s.status = ""
+	}
+
+	s.flush()
+	return nil
+}
+
+func (s *replyStream) SendUsage(*dto.ChatUsageDTO) error {
+	return nil
+}
+
+func (s *replyStream) SendError(err error) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.err = err
+	return nil
+}
+
+func (s *replyStream) SendChat(chat *domain.Chat) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.chat = chat
+	return nil
+}
+
+func (s *replyStream) SendFinal(chat *domain.Chat, _ []*domain.Message) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.chat == nil {
+		s.chat = chat
+	}
+	return nil
+}
+
+// Finish выводит итоговый ответ: первая часть заменяет сообщение-заглушку,
+// продолжение длинного ответа отправляется отдельными сообщениями
+func (s *replyStream) Finish(errorText string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.status = ""
+	text := s.text()
+	if errorText != "" {
+		text = strings.TrimSpace(text + "\n\n" + errorText)
+	}
+	if text == "" {
+		text = "Агент не дал ответа. Попробуйте переформулировать вопрос."
+	}
+
+	pages := splitMessage(text, maxMessageLength)
+	if err := s.api.EditMessageText(s.ctx, s.chatID, s.messageID, pages[0], nil); err != nil {
+		logger.Errorf(s.ctx, "telegram bot: failed to edit reply: %v", err)
+	}
+	for _, page := range pages[1:] {
+		if _, err := s.api.SendMessage(s.ctx, s.chatID, page, nil); err != nil {
+			logger.Errorf(s.ctx, "telegram bot: failed to send reply continuation: %v", err)
+			return
+		}
+	}
+}
+
+// text - весь ответ ассистента за текущий запуск
+func (s *replyStream) text() string {
+	parts := s.parts
+	if draft := strings.TrimSpace(s.draft.String()); draft != "" {
+		parts = append(parts[:len(parts):len(parts)], draft)
+	}
+	return strings.Join(parts, "\n\n")
+}
+
+// flush обновляет сообщение с ответом, если с прошлого обновления прошло достаточно времени
+func (s *replyStream) flush() {
+	if time.Since(s.lastEdit) < s.interval {
+		return
+	}
+
+	text := s.text()
+	// Пока ответ генерируется, показываем его конец - начало будет разбито на части в Finish
+	if utf8.RuneCountInString(text) > maxMessageLength-100 {
+		runes := []rune(text)
+		text = "…" + string(runes[len(runes)-(maxMessageLength-100):])
+	}
+	if s.status != "" {
+		text = strings.TrimSpace(text + "\n\n⏳ " + s.status)
+	}
+	if text == "" || text == s.shown {
+		return
+	}
+
+	s.lastEdit = time.Now()
+	if err := s.api.EditMessageText(s.ctx, s.chatID, s.messageID, text, nil); err != nil {
+		var apiErr *APIError
+		if errors.As(err, &apiErr) && apiErr.RetryAfter > 0 {
+			s.lastEdit = time.Now().Add(apiErr.RetryAfter)
+			return
+		}
+		logger.Warnf(s.ctx, "telegram bot: failed to update reply: %v", err)
+		return
+	}
+	s.shown = text
+}
+
+func toolStatus(name string) string {
+	if status, ok := toolStatuses[domain.ToolName(name)]; ok {
+		return status
+	}
+	if domain.IsMCPTool(name) {
+		return "Обращаюсь к AmoCRM…"
+	}
+	return "Работаю…"
+}
+
+// splitMessage делит текст на части не длиннее limit символов, по возможности по границе абзаца или строки
+func splitMessage(text string, limit int) []string {
+	var pages []string
+	runes := []rune(text)
+	for len(runes) > limit {
+		cut := limit
+		chunk := string(runes[:limit])
+		if i := strings.LastIndex(chunk, "\n\n"); i > 0 {
+			cut = utf8.RuneCountInString(chunk[:i])
+		} else if i := strings.LastIndex(chunk, "\n"); i > 0 {
+			cut = utf8.RuneCountInString(chunk[:i])
+		}
+		pages = append(pages, strings.TrimSpace(string(runes[:cut])))
+		runes = []rune(strings.TrimSpace(string(runes[cut:])))
+	}
+	return append(pages, string(runes))
+}
@@ -0,0 +1,42 @@
+package transcription
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/opentracing/opentracing-go"
+)
+
+// OpenAIClient - распознавание через OpenAI-совместимый эндпоинт /audio/transcriptions
+type OpenAIClient struct {
+	baseURL    string
+	apiKey     string
+	model      string
+	language   string
+	httpClient *http.Client
+}
+
+// NewOpenAIClient создает клиент; baseURL указывается вместе с версией API, например https://api.openai.com/v1
+func NewOpenAIClient(baseURL, apiKey, model, language string, timeout time.Duration) *OpenAIClient {
+	return &OpenAIClient{
+		baseURL:    strings.TrimRight(baseURL, "/"),
+		apiKey:     apiKey,
+		model:      model,
+		language:   language,
+		httpClient: &http.Client{Timeout: timeout},
+	}
+}
+
+// Transcribe распознает речь в аудиофайле
+func (c *OpenAIClient) Transcribe(ctx context.Context, audio Audio) (string, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "transcription.OpenAIClient.Transcribe")
+	defer span.Finish()
+
+	return postMultipart(ctx, c.httpClient, c.baseURL+"/audio/transcriptions", c.apiKey, audio, map[string]string{
+		"model":           c.model,
+		"language":        c.language,
+		"response_format": "json",
+	})
+}
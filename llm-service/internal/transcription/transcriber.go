@@ -0,0 +1,82 @@
+package transcription
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"strings"
+)
+
+// Transcriber - распознавание речи в аудиофайле
+type Transcriber interface {
+	Transcribe(ctx context.Context, audio Audio) (string, error)
+}
+
+// Audio - аудиофайл для распознавания
+type Audio struct {
+	Data        []byte
+	FileName    string
+	ContentType string
+}
+
+// transcriptionResponse - ответ с response_format=json (OpenAI и whisper.cpp server)
+type transcriptionResponse struct {
+	Text string `json:"text"`
+}
+
+// postMultipart отправляет файл и поля формы и возвращает распознанный текст
+func postMultipart(ctx context.Context, httpClient *http.Client, url, apiKey string, audio Audio, fields map[string]string) (string, error) {
+	body := &bytes.Buffer{}
+	writer := multipart.NewWriter(body)
+
+	for name, value := range fields {
+		if value == "" {
+			continue
+		}
+		if err := writer.WriteField(name, value); err != nil {
+			return "", err
+		}
+	}
+
+	part, err := writer.CreateFormFile("file", audio.FileName)
+	if err != nil {
+		return "", err
+	}
+	if _, err := part.Write(audio.Data); err != nil {
+		return "", err
+	}
+	if err := writer.Close(); err != nil {
+		return "", err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, body)
+	if err != nil {
+		return "", fmt.Errorf("failed to create transcription request: %w", err)
+	}
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+	if apiKey != "" {
+		req.Header.Set("Authorization", "Bearer "+apiKey)
+	}
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to call transcription service: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		message, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return "", fmt.Errorf("transcription service returned status %d: %s", resp.StatusCode, strings.TrimSpace(string(message)))
+	}
+
+	var result transcriptionResponse
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return "", fmt.Errorf("failed to decode transcription response: %w", err)
+	}
+
+	return strings.TrimSpace(result.Text), nil
+}
@@ -0,0 +1,39 @@
+package transcription
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/opentracing/opentracing-go"
+)
+
+// WhisperServerClient - распознавание через локальный whisper.cpp server (эндпоинт /inference).
+// Заменяет облачный API при локальной разработке и в закрытом контуре.
+type WhisperServerClient struct {
+	baseURL    string
+	language   string
+	httpClient *http.Client
+}
+
+// NewWhisperServerClient создает клиент; baseURL - адрес сервера, например http://localhost:8178
+func NewWhisperServerClient(baseURL, language string, timeout time.Duration) *WhisperServerClient {
+	return &WhisperServerClient{
+		baseURL:    strings.TrimRight(baseURL, "/"),
+		language:   language,
+		httpClient: &http.Client{Timeout: timeout},
+	}
+}
+
+// Transcribe распознает речь в аудиофайле
+func (c *WhisperServerClient) Transcribe(ctx context.Context, audio Audio) (string, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "transcription.WhisperServerClient.Transcribe")
+	defer span.Finish()
+
+	return postMultipart(ctx, c.httpClient, c.baseURL+"/inference", "", audio, map[string]string{
+		"language":        c.language,
+		"response_format": "json",
+		"temperature":     "0.0",
+	})
+}
@@ -0,0 +1,6 @@
+-- +goose Up
+-- Вложения пользовательских сообщений (изображения, сканы документов) хранятся как JSON массив ссылок на S3
+ALTER TABLE messages
+ADD COLUMN attachments JSONB NOT NULL DEFAULT '[]'::jsonb;
+-- +goose Down
+ALTER TABLE messages DROP COLUMN attachments;
@@ -0,0 +1,16 @@
+-- +goose Up
+-- Полнотекстовый поиск по истории чатов (русская морфология)
+ALTER TABLE messages
+ADD COLUMN content_tsv tsvector GENERATED ALWAYS AS (to_tsvector('russian', content)) STORED;
+
+CREATE INDEX idx_messages_content_tsv ON messages USING GIN (content_tsv);
+
+ALTER TABLE chats
+ADD COLUMN title_tsv tsvector GENERATED ALWAYS AS (to_tsvector('russian', title)) STORED;
+
+CREATE INDEX idx_chats_title_tsv ON chats USING GIN (title_tsv);
+-- +goose Down
+DROP INDEX IF EXISTS idx_chats_title_tsv;
+ALTER TABLE chats DROP COLUMN title_tsv;
+DROP INDEX IF EXISTS idx_messages_content_tsv;
+ALTER TABLE messages DROP COLUMN content_tsv;
@@ -0,0 +1,23 @@
+-- +goose Up
+-- Совместный доступ к чатам внутри организации
+CREATE TABLE IF NOT EXISTS chat_shares (
+    chat_id UUID NOT NULL REFERENCES chats(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL,
+    role VARCHAR(20) NOT NULL CHECK (role IN ('viewer', 'collaborator')),
+    shared_by UUID NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (chat_id, user_id)
+);
+
+CREATE INDEX idx_chat_shares_user_id ON chat_shares(user_id);
+
+-- Автор пользовательского сообщения: в общий чат могут писать несколько человек
+ALTER TABLE messages ADD COLUMN author_user_id UUID;
+
+UPDATE messages m
+SET author_user_id = c.user_id
+FROM chats c
+WHERE c.id = m.chat_id AND m.role = 'user';
+-- +goose Down
+ALTER TABLE messages DROP COLUMN author_user_id;
+DROP TABLE IF EXISTS chat_shares;
@@ -0,0 +1,30 @@
+-- +goose Up
+-- Дерево сообщений: редактирование и перегенерация создают новую ветку от родительского сообщения
+ALTER TABLE messages ADD COLUMN parent_message_id UUID REFERENCES messages(id) ON DELETE CASCADE;
+
+-- Последнее сообщение активной ветки чата
+ALTER TABLE chats ADD COLUMN active_message_id UUID;
+
+-- Существующие чаты линейные: родитель - предыдущее сообщение чата
+UPDATE messages m
+SET parent_message_id = p.prev_id
+FROM (
+    SELECT id, LAG(id) OVER (PARTITION BY chat_id ORDER BY created_at, id) AS prev_id
+    FROM messages
+) p
+WHERE m.id = p.id AND p.prev_id IS NOT NULL;
+
+UPDATE chats c
+SET active_message_id = (
+    SELECT m.id FROM messages m
+    WHERE m.chat_id = c.id
+    ORDER BY m.created_at DESC, m.id DESC
+    LIMIT 1
+);
+
+CREATE INDEX idx_messages_parent_message_id ON messages(parent_message_id);
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_messages_parent_message_id;
+ALTER TABLE chats DROP COLUMN active_message_id;
+ALTER TABLE messages DROP COLUMN parent_message_id;
@@ -0,0 +1,23 @@
+-- +goose Up
+-- Оценки ответов ассистента пользователями
+CREATE TABLE IF NOT EXISTS message_feedback (
+    message_id UUID NOT NULL REFERENCES messages(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL,
+    organization_id UUID NOT NULL,
+    chat_id UUID NOT NULL REFERENCES chats(id) ON DELETE CASCADE,
+    agent_key VARCHAR(255) NOT NULL,
+    rating VARCHAR(20) NOT NULL CHECK (rating IN ('positive', 'negative')),
+    reasons TEXT[] NOT NULL DEFAULT '{}',
+    comment TEXT NOT NULL DEFAULT '',
+    -- Инструменты, вызванные при подготовке ответа (включая инструменты субагентов)
+    tool_names TEXT[] NOT NULL DEFAULT '{}',
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (message_id, user_id)
+);
+
+CREATE INDEX idx_message_feedback_org_created ON message_feedback(organization_id, created_at);
+CREATE INDEX idx_message_feedback_agent_key ON message_feedback(agent_key);
+
+-- +goose Down
+DROP TABLE IF EXISTS message_feedback;
@@ -0,0 +1,28 @@
+-- +goose Up
+-- Регулярные задачи агентов: запуск по cron-выражению без сообщения пользователя
+CREATE TABLE IF NOT EXISTS agent_schedules (
+    id UUID PRIMARY KEY,
+    organization_id UUID NOT NULL,
+    user_id UUID NOT NULL,
+    title VARCHAR(500) NOT NULL,
+    agent_key VARCHAR(255) NOT NULL,
+    prompt TEXT NOT NULL,
+    cron_expr VARCHAR(100) NOT NULL,
+    timezone VARCHAR(64) NOT NULL,
+    enabled BOOLEAN NOT NULL DEFAULT TRUE,
+    next_run_at TIMESTAMP WITH TIME ZONE,
+    -- Воркер, взявший расписание в работу, держит его до locked_until
+    locked_until TIMESTAMP WITH TIME ZONE,
+    last_run_at TIMESTAMP WITH TIME ZONE,
+    last_run_status VARCHAR(20),
+    last_chat_id UUID REFERENCES chats(id) ON DELETE SET NULL,
+    last_error TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_agent_schedules_org_user ON agent_schedules(organization_id, user_id);
+CREATE INDEX idx_agent_schedules_due ON agent_schedules(next_run_at) WHERE enabled;
+
+-- +goose Down
+DROP TABLE IF EXISTS agent_schedules;
@@ -0,0 +1,16 @@
+-- +goose Up
+-- Чаты Telegram-бота: какой чат агента продолжается в какой организации
+CREATE TABLE IF NOT EXISTS telegram_chat_bindings (
+    telegram_chat_id BIGINT NOT NULL,
+    organization_id UUID NOT NULL,
+    user_id UUID NOT NULL,
+    chat_id UUID REFERENCES chats(id) ON DELETE SET NULL,
+    -- Активная организация чата - связь с последним updated_at
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (telegram_chat_id, organization_id)
+);
+
+CREATE INDEX idx_telegram_chat_bindings_active ON telegram_chat_bindings(telegram_chat_id, updated_at DESC);
+
+-- +goose Down
+DROP TABLE IF EXISTS telegram_chat_bindings;
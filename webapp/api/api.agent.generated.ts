@@ -9,6 +9,67 @@
  * ---------------------------------------------------------------
  */
 
+export interface AgentAgentFeedbackStats {
+  agentKey?: string;
+  agentName?: string;
+  stats?: AgentFeedbackStats;
+  /** Причины отрицательных оценок */
+  reasons?: AgentFeedbackReasonCount[];
+}
+
+export interface AgentAgentSchedule {
+  id?: string;
+  organizationId?: string;
+  userId?: string;
+  title?: string;
+  agentKey?: string;
+  prompt?: string;
+  /** Cron-выражение из 5 полей (минута, час, день месяца, месяц, день недели) или @daily, @weekly */
+  cronExpr?: string;
+  /** Часовой пояс IANA, например Europe/Moscow */
+  timezone?: string;
+  enabled?: boolean;
+  /**
+   * Пусто, если расписание выключено
+   * @format date-time
+   */
+  nextRunAt?: string;
+  /** @format date-time */
+  lastRunAt?: string;
+  lastRunStatus?: AgentScheduleRunStatus;
+  /** Чат с результатом последнего запуска */
+  lastChatId?: string;
+  lastError?: string;
+  /** @format date-time */
+  createdAt?: string;
+  /** @format date-time */
+  updatedAt?: string;
+}
+
+export interface AgentAttachment {
+  s3Key?: string;
+  fileName?: string;
+  contentType?: string;
+  /** @format int64 */
+  size?: string;
+  id?: string;
+  /**
+   * Как документ передан агенту: file, inline, index (поиск через search_chat_files)
+   * или transcript (голосовое сообщение передано расшифровкой)
+   */
+  delivery?: string;
+  /** ID документа базы знаний, если вложение перенесено */
+  documentId?: string;
+  /** Расшифровка голосового сообщения */
+  transcript?: string;
+}
+
+export interface AgentAttachmentRef {
+  s3Key?: string;
+  fileName?: string;
+  contentType?: string;
+}
+
 export interface AgentChat {
   id?: string;
   organizationId?: string;
@@ -41,6 +102,46 @@ export enum AgentChatStatus {
   CHAT_STATUS_ARCHIVED = "CHAT_STATUS_ARCHIVED",
 }
 
+/** @default "CHAT_EXPORT_FORMAT_UNSPECIFIED" */
+export enum AgentChatExportFormat {
+  CHAT_EXPORT_FORMAT_UNSPECIFIED = "CHAT_EXPORT_FORMAT_UNSPECIFIED",
+  CHAT_EXPORT_FORMAT_MARKDOWN = "CHAT_EXPORT_FORMAT_MARKDOWN",
+  CHAT_EXPORT_FORMAT_DOCX = "CHAT_EXPORT_FORMAT_DOCX",
+  CHAT_EXPORT_FORMAT_PDF = "CHAT_EXPORT_FORMAT_PDF",
+}
+
+export interface AgentChatSearchResult {
+  chat?: AgentChat;
+  /** Сообщение с лучшим совпадением; пусто, если совпало только название */
+  messageId?: string;
+  messageRole?: AgentMessageRole;
+  /** HTML-экранированный фрагмент, совпадения обернуты в <mark> */
+  snippet?: string;
+  titleHighlight?: string;
+  /** @format float */
+  rank?: number;
+}
+
+export interface AgentChatShare {
+  chatId?: string;
+  userId?: string;
+  role?: AgentChatShareRole;
+  sharedBy?: string;
+  /** @format date-time */
+  createdAt?: string;
+}
+
+/**
+ * - CHAT_SHARE_ROLE_VIEWER: Только чтение
+ *  - CHAT_SHARE_ROLE_COLLABORATOR: Может отправлять сообщения в чат
+ * @default "CHAT_SHARE_ROLE_UNSPECIFIED"
+ */
+export enum AgentChatShareRole {
+  CHAT_SHARE_ROLE_UNSPECIFIED = "CHAT_SHARE_ROLE_UNSPECIFIED",
+  CHAT_SHARE_ROLE_VIEWER = "CHAT_SHARE_ROLE_VIEWER",
+  CHAT_SHARE_ROLE_COLLABORATOR = "CHAT_SHARE_ROLE_COLLABORATOR",
+}
+
 export interface AgentChatUsage {
   /** @format int32 */
   promptTokens?: number;
@@ -50,6 +151,10 @@ export interface AgentChatUsage {
   totalTokens?: number;
 }
 
+export interface AgentCreateAgentScheduleResponse {
+  schedule?: AgentAgentSchedule;
+}
+
 export interface AgentCreateMemoryFactRequest {
   orgId?: string;
   content?: string;
@@ -59,11 +164,72 @@ export interface AgentCreateMemoryFactResponse {
   fact?: AgentMemoryFact;
 }
 
+export interface AgentEditMessagePayload {
+  chatId?: string;
+  orgId?: string;
+  messageId?: string;
+  content?: string;
+}
+
 export interface AgentErrorEvent {
   code?: string;
   message?: string;
 }
 
+export interface AgentExportChatResponse {
+  downloadUrl?: string;
+  s3Key?: string;
+  fileName?: string;
+  contentType?: string;
+  /** @format int64 */
+  size?: string;
+  /** ID документа организации, если экспорт зарегистрирован */
+  documentId?: string;
+}
+
+/** @default "FEEDBACK_RATING_UNSPECIFIED" */
+export enum AgentFeedbackRating {
+  FEEDBACK_RATING_UNSPECIFIED = "FEEDBACK_RATING_UNSPECIFIED",
+  FEEDBACK_RATING_POSITIVE = "FEEDBACK_RATING_POSITIVE",
+  FEEDBACK_RATING_NEGATIVE = "FEEDBACK_RATING_NEGATIVE",
+}
+
+/**
+ * - FEEDBACK_REASON_INCORRECT: Ответ содержит ошибку
+ *  - FEEDBACK_REASON_OUTDATED: Устаревшие данные (законодательство, цены)
+ *  - FEEDBACK_REASON_TOOL_ERROR: Инструмент отработал неверно (поиск, генерация договора)
+ * @default "FEEDBACK_REASON_UNSPECIFIED"
+ */
+export enum AgentFeedbackReason {
+  FEEDBACK_REASON_UNSPECIFIED = "FEEDBACK_REASON_UNSPECIFIED",
+  FEEDBACK_REASON_INCORRECT = "FEEDBACK_REASON_INCORRECT",
+  FEEDBACK_REASON_INCOMPLETE = "FEEDBACK_REASON_INCOMPLETE",
+  FEEDBACK_REASON_IRRELEVANT = "FEEDBACK_REASON_IRRELEVANT",
+  FEEDBACK_REASON_OUTDATED = "FEEDBACK_REASON_OUTDATED",
+  FEEDBACK_REASON_TOOL_ERROR = "FEEDBACK_REASON_TOOL_ERROR",
+  FEEDBACK_REASON_TOO_LONG = "FEEDBACK_REASON_TOO_LONG",
+  FEEDBACK_REASON_HELPFUL = "FEEDBACK_REASON_HELPFUL",
+  FEEDBACK_REASON_OTHER = "FEEDBACK_REASON_OTHER",
+}
+
+export interface AgentFeedbackReasonCount {
+  reason?: AgentFeedbackReason;
+  /** @format int32 */
+  count?: number;
+}
+
+export interface AgentFeedbackStats {
+  /** @format int32 */
+  positive?: number;
+  /** @format int32 */
+  negative?: number;
+  /**
+   * Доля положительных оценок
+   * @format double
+   */
+  satisfactionRate?: number;
+}
+
 /** Отправляется в конце стрима с полным состоянием чата */
 export interface AgentFinalEvent {
   chat?: AgentChat;
@@ -76,6 +242,17 @@ export interface AgentGetChatResponse {
   chat?: AgentChat;
 }
 
+export interface AgentGetFeedbackReportResponse {
+  totals?: AgentFeedbackStats;
+  agents?: AgentAgentFeedbackStats[];
+  tools?: AgentToolFeedbackStats[];
+  comments?: AgentMessageFeedback[];
+  /** @format date-time */
+  from?: string;
+  /** @format date-time */
+  to?: string;
+}
+
 export interface AgentGetLLMLimitsResponse {
   /** @format int32 */
   dailyLimit?: number;
@@ -91,6 +268,10 @@ export interface AgentGetMessagesResponse {
   total?: number;
 }
 
+export interface AgentListAgentSchedulesResponse {
+  schedules?: AgentAgentSchedule[];
+}
+
 export interface AgentListChatsResponse {
   chats?: AgentChat[];
   /** @format int32 */
@@ -101,10 +282,24 @@ export interface AgentListChatsResponse {
   pageSize?: number;
 }
 
+export interface AgentListChatSharesResponse {
+  shares?: AgentChatShare[];
+}
+
 export interface AgentListMemoryFactsResponse {
   facts?: AgentMemoryFact[];
 }
 
+export interface AgentListSharedChatsResponse {
+  chats?: AgentSharedChat[];
+  /** @format int32 */
+  total?: number;
+  /** @format int32 */
+  page?: number;
+  /** @format int32 */
+  pageSize?: number;
+}
+
 export interface AgentMemoryFact {
   id?: string;
   content?: string;
@@ -124,12 +319,37 @@ export interface AgentMessage {
   toolCallId?: string;
   /** @format date-time */
   createdAt?: string;
+  attachments?: AgentAttachment[];
+  /** Автор пользовательского сообщения (в общих чатах пишут несколько пользователей) */
+  authorUserId?: string;
+  /** Предыдущее сообщение в ветке */
+  parentMessageId?: string;
+  /** Альтернативные версии сообщения (включая его само), если их несколько */
+  siblingIds?: string[];
+  /** Оценка ответа текущим пользователем */
+  myFeedback?: AgentMessageFeedback;
 }
 
 export interface AgentMessageChunk {
   content?: string;
 }
 
+export interface AgentMessageFeedback {
+  messageId?: string;
+  chatId?: string;
+  userId?: string;
+  agentKey?: string;
+  rating?: AgentFeedbackRating;
+  reasons?: AgentFeedbackReason[];
+  comment?: string;
+  /** Инструменты, вызванные при подготовке ответа */
+  toolNames?: string[];
+  /** @format date-time */
+  createdAt?: string;
+  /** @format date-time */
+  updatedAt?: string;
+}
+
 /** @default "MESSAGE_ROLE_UNSPECIFIED" */
 export enum AgentMessageRole {
   MESSAGE_ROLE_UNSPECIFIED = "MESSAGE_ROLE_UNSPECIFIED",
@@ -142,7 +362,107 @@ export enum AgentMessageRole {
 export interface AgentNewMessagePayload {
   chatId?: string;
   orgId?: string;
+  /** Может быть пустым, если есть вложения */
   content?: string;
+  /**
+   * Файлы, загруженные через GenerateUploadURL core-service.
+   * Аудио (голосовое сообщение) распознается до отправки агенту
+   */
+  attachments?: AgentAttachmentRef[];
+}
+
+export interface AgentPromoteChatAttachmentResponse {
+  attachment?: AgentAttachment;
+}
+
+export interface AgentRateMessageResponse {
+  feedback?: AgentMessageFeedback;
+}
+
+export interface AgentRegenerateMessagePayload {
+  chatId?: string;
+  orgId?: string;
+  messageId?: string;
+}
+
+/**
+ * - SCHEDULE_RUN_STATUS_SKIPPED: Запуск пропущен: исчерпана квота токенов или пользователь покинул организацию
+ * @default "SCHEDULE_RUN_STATUS_UNSPECIFIED"
+ */
+export enum AgentScheduleRunStatus {
+  SCHEDULE_RUN_STATUS_UNSPECIFIED = "SCHEDULE_RUN_STATUS_UNSPECIFIED",
+  SCHEDULE_RUN_STATUS_SUCCESS = "SCHEDULE_RUN_STATUS_SUCCESS",
+  SCHEDULE_RUN_STATUS_FAILED = "SCHEDULE_RUN_STATUS_FAILED",
+  SCHEDULE_RUN_STATUS_SKIPPED = "SCHEDULE_RUN_STATUS_SKIPPED",
+}
+
+export interface AgentSearchChatsResponse {
+  results?: AgentChatSearchResult[];
+  /** @format int32 */
+  total?: number;
+  /** @format int32 */
+  page?: number;
+  /** @format int32 */
+  pageSize?: number;
+}
+
+export interface AgentServiceCreateAgentScheduleBody {
+  title?: string;
+  agentKey?: string;
+  prompt?: string;
+  cronExpr?: string;
+  /** По умолчанию Europe/Moscow */
+  timezone?: string;
+  /** Выключенное расписание не запускается */
+  enabled?: boolean;
+}
+
+export interface AgentServiceExportChatBody {
+  orgId?: string;
+  format?: AgentChatExportFormat;
+  /** Включить переписку с субагентами в место их вызова */
+  includeSubagents?: boolean;
+  /** Сохранить экспорт в документы организации */
+  registerDocument?: boolean;
+}
+
+export interface AgentServicePromoteChatAttachmentBody {
+  orgId?: string;
+}
+
+export interface AgentServiceRateMessageBody {
+  orgId?: string;
+  rating?: AgentFeedbackRating;
+  reasons?: AgentFeedbackReason[];
+  comment?: string;
+}
+
+export interface AgentServiceShareChatBody {
+  orgId?: string;
+  userId?: string;
+  role?: AgentChatShareRole;
+}
+
+export interface AgentServiceSwitchMessageBranchBody {
+  orgId?: string;
+}
+
+export interface AgentServiceUpdateAgentScheduleBody {
+  title?: string;
+  agentKey?: string;
+  prompt?: string;
+  cronExpr?: string;
+  timezone?: string;
+  enabled?: boolean;
+}
+
+export interface AgentShareChatResponse {
+  share?: AgentChatShare;
+}
+
+export interface AgentSharedChat {
+  chat?: AgentChat;
+  share?: AgentChatShare;
 }
 
 export interface AgentStreamMessageResponse {
@@ -187,6 +507,11 @@ export interface AgentToolCall {
   completedAt?: string;
 }
 
+export interface AgentToolFeedbackStats {
+  toolName?: string;
+  stats?: AgentFeedbackStats;
+}
+
 export interface AgentToolCallEvent {
   toolCallId?: string;
   toolName?: string;
@@ -203,6 +528,10 @@ export enum AgentToolCallStatus {
   TOOL_CALL_STATUS_FAILED = "TOOL_CALL_STATUS_FAILED",
 }
 
+export interface AgentUpdateAgentScheduleResponse {
+  schedule?: AgentAgentSchedule;
+}
+
 export interface AgentUsageEvent {
   usage?: AgentChatUsage;
 }
@@ -436,6 +765,51 @@ export class Api<SecurityDataType extends unknown> extends HttpClient<SecurityDa
         ...params,
       }),
 
+    /**
+     * No description
+     *
+     * @tags AgentService
+     * @name AgentServicePromoteChatAttachment
+     * @summary Перенести документ, приложенный к чату, в базу знаний организации
+     * @request POST:/v1/chats/{chatId}/attachments/{attachmentId}/promote
+     * @secure
+     */
+    agentServicePromoteChatAttachment: (
+      chatId: string,
+      attachmentId: string,
+      body: AgentServicePromoteChatAttachmentBody,
+      params: RequestParams = {},
+    ) =>
+      this.request<AgentPromoteChatAttachmentResponse, RpcStatus>({
+        path: `/v1/chats/${chatId}/attachments/${attachmentId}/promote`,
+        method: "POST",
+        body: body,
+        secure: true,
+        type: ContentType.Json,
+        format: "json",
+        ...params,
+      }),
+
+    /**
+     * No description
+     *
+     * @tags AgentService
+     * @name AgentServiceExportChat
+     * @summary Экспортировать чат в Markdown, DOCX или PDF
+     * @request POST:/v1/chats/{chatId}/export
+     * @secure
+     */
+    agentServiceExportChat: (chatId: string, body: AgentServiceExportChatBody, params: RequestParams = {}) =>
+      this.request<AgentExportChatResponse, RpcStatus>({
+        path: `/v1/chats/${chatId}/export`,
+        method: "POST",
+        body: body,
+        secure: true,
+        type: ContentType.Json,
+        format: "json",
+        ...params,
+      }),
+
     /**
      * No description
      *
@@ -465,6 +839,187 @@ export class Api<SecurityDataType extends unknown> extends HttpClient<SecurityDa
         ...params,
       }),
 
+    /**
+     * No description
+     *
+     * @tags AgentService
+     * @name AgentServiceSwitchMessageBranch
+     * @summary Переключить чат на ветку, содержащую сообщение
+     * @request POST:/v1/chats/{chatId}/messages/{messageId}/activate
+     * @secure
+     */
+    agentServiceSwitchMessageBranch: (
+      chatId: string,
+      messageId: string,
+      body: AgentServiceSwitchMessageBranchBody,
+      params: RequestParams = {},
+    ) =>
+      this.request<object, RpcStatus>({
+        path: `/v1/chats/${chatId}/messages/${messageId}/activate`,
+        method: "POST",
+        body: body,
+        secure: true,
+        type: ContentType.Json,
+        format: "json",
+        ...params,
+      }),
+
+    /**
+     * No description
+     *
+     * @tags AgentService
+     * @name AgentServiceRateMessage
+     * @summary Оценить ответ ассистента (повторная оценка заменяет предыдущую)
+     * @request POST:/v1/chats/{chatId}/messages/{messageId}/feedback
+     * @secure
+     */
+    agentServiceRateMessage: (
+      chatId: string,
+      messageId: string,
+      body: AgentServiceRateMessageBody,
+      params: RequestParams = {},
+    ) =>
+      this.request<AgentRateMessageResponse, RpcStatus>({
+        path: `/v1/chats/${chatId}/messages/${messageId}/feedback`,
+        method: "POST",
+        body: body,
+        secure: true,
+        type: ContentType.Json,
+        format: "json",
+        ...params,
+      }),
+
+    /**
+     * No description
+     *
+     * @tags AgentService
+     * @name AgentServiceListChatShares
+     * @summary Получить список пользователей, у которых есть доступ к чату
+     * @request GET:/v1/chats/{chatId}/shares
+     * @secure
+     */
+    agentServiceListChatShares: (
+      chatId: string,
+      query?: {
+        orgId?: string;
+      },
+      params: RequestParams = {},
+    ) =>
+      this.request<AgentListChatSharesResponse, RpcStatus>({
+        path: `/v1/chats/${chatId}/shares`,
+        method: "GET",
+        query: query,
+        secure: true,
+        format: "json",
+        ...params,
+      }),
+
+    /**
+     * No description
+     *
+     * @tags AgentService
+     * @name AgentServiceShareChat
+     * @summary Поделиться чатом с сотрудником организации
+     * @request POST:/v1/chats/{chatId}/shares
+     * @secure
+     */
+    agentServiceShareChat: (chatId: string, body: AgentServiceShareChatBody, params: RequestParams = {}) =>
+      this.request<AgentShareChatResponse, RpcStatus>({
+        path: `/v1/chats/${chatId}/shares`,
+        method: "POST",
+        body: body,
+        secure: true,
+        type: ContentType.Json,
+        format: "json",
+        ...params,
+      }),
+
+    /**
+     * No description
+     *
+     * @tags AgentService
+     * @name AgentServiceUnshareChat
+     * @summary Отозвать доступ к чату (владелец - любой доступ, пользователь - свой)
+     * @request DELETE:/v1/chats/{chatId}/shares/{userId}
+     * @secure
+     */
+    agentServiceUnshareChat: (
+      chatId: string,
+      userId: string,
+      query?: {
+        orgId?: string;
+      },
+      params: RequestParams = {},
+    ) =>
+      this.request<object, RpcStatus>({
+        path: `/v1/chats/${chatId}/shares/${userId}`,
+        method: "DELETE",
+        query: query,
+        secure: true,
+        format: "json",
+        ...params,
+      }),
+
+    /**
+     * No description
+     *
+     * @tags AgentService
+     * @name AgentServiceSearchChats
+     * @summary Полнотекстовый поиск по истории чатов пользователя
+     * @request GET:/v1/chats:search
+     * @secure
+     */
+    agentServiceSearchChats: (
+      query?: {
+        orgId?: string;
+        query?: string;
+        /** @format int32 */
+        page?: number;
+        /** @format int32 */
+        pageSize?: number;
+        /** Искать также в результатах инструментов и системных сообщениях (по умолчанию исключены) */
+        includeToolMessages?: boolean;
+        includeSystemMessages?: boolean;
+      },
+      params: RequestParams = {},
+    ) =>
+      this.request<AgentSearchChatsResponse, RpcStatus>({
+        path: `/v1/chats:search`,
+        method: "GET",
+        query: query,
+        secure: true,
+        format: "json",
+        ...params,
+      }),
+
+    /**
+     * No description
+     *
+     * @tags AgentService
+     * @name AgentServiceListSharedChats
+     * @summary Получить чаты, которыми поделились с пользователем
+     * @request GET:/v1/chats:shared
+     * @secure
+     */
+    agentServiceListSharedChats: (
+      query?: {
+        orgId?: string;
+        /** @format int32 */
+        page?: number;
+        /** @format int32 */
+        pageSize?: number;
+      },
+      params: RequestParams = {},
+    ) =>
+      this.request<AgentListSharedChatsResponse, RpcStatus>({
+        path: `/v1/chats:shared`,
+        method: "GET",
+        query: query,
+        secure: true,
+        format: "json",
+        ...params,
+      }),
+
     /**
      * No description
      *
@@ -571,5 +1126,160 @@ export class Api<SecurityDataType extends unknown> extends HttpClient<SecurityDa
         format: "json",
         ...params,
       }),
+
+    /**
+     * No description
+     *
+     * @tags AgentService
+     * @name AgentServiceListOrganizationChats
+     * @summary Получить все чаты организации (для администраторов, если разрешено политикой)
+     * @request GET:/v1/organizations/{orgId}/chats
+     * @secure
+     */
+    agentServiceListOrganizationChats: (
+      orgId: string,
+      query?: {
+        /** @format int32 */
+        page?: number;
+        /** @format int32 */
+        pageSize?: number;
+      },
+      params: RequestParams = {},
+    ) =>
+      this.request<AgentListChatsResponse, RpcStatus>({
+        path: `/v1/organizations/${orgId}/chats`,
+        method: "GET",
+        query: query,
+        secure: true,
+        format: "json",
+        ...params,
+      }),
+
+    /**
+     * No description
+     *
+     * @tags AgentService
+     * @name AgentServiceGetFeedbackReport
+     * @summary Отчет по оценкам ответов по агентам и инструментам (для администраторов организации)
+     * @request GET:/v1/organizations/{orgId}/feedback/report
+     * @secure
+     */
+    agentServiceGetFeedbackReport: (
+      orgId: string,
+      query?: {
+        /** Если задан, отчет только по одному агенту */
+        agentKey?: string;
+        /**
+         * По умолчанию - последние 30 дней
+         * @format date-time
+         */
+        from?: string;
+        /** @format date-time */
+        to?: string;
+        /**
+         * Количество последних отрицательных отзывов с комментариями (по умолчанию 20, максимум 100)
+         * @format int32
+         */
+        commentsLimit?: number;
+      },
+      params: RequestParams = {},
+    ) =>
+      this.request<AgentGetFeedbackReportResponse, RpcStatus>({
+        path: `/v1/organizations/${orgId}/feedback/report`,
+        method: "GET",
+        query: query,
+        secure: true,
+        format: "json",
+        ...params,
+      }),
+
+    /**
+     * No description
+     *
+     * @tags AgentService
+     * @name AgentServiceListAgentSchedules
+     * @summary Получить задачи по расписанию текущего пользователя
+     * @request GET:/v1/organizations/{orgId}/schedules
+     * @secure
+     */
+    agentServiceListAgentSchedules: (orgId: string, params: RequestParams = {}) =>
+      this.request<AgentListAgentSchedulesResponse, RpcStatus>({
+        path: `/v1/organizations/${orgId}/schedules`,
+        method: "GET",
+        secure: true,
+        format: "json",
+        ...params,
+      }),
+
+    /**
+     * No description
+     *
+     * @tags AgentService
+     * @name AgentServiceCreateAgentSchedule
+     * @summary Создать задачу агента по расписанию
+     * @request POST:/v1/organizations/{orgId}/schedules
+     * @secure
+     */
+    agentServiceCreateAgentSchedule: (
+      orgId: string,
+      body: AgentServiceCreateAgentScheduleBody,
+      params: RequestParams = {},
+    ) =>
+      this.request<AgentCreateAgentScheduleResponse, RpcStatus>({
+        path: `/v1/organizations/${orgId}/schedules`,
+        method: "POST",
+        body: body,
+        secure: true,
+        type: ContentType.Json,
+        format: "json",
+        ...params,
+      }),
+
+    /**
+     * No description
+     *
+     * @tags AgentService
+     * @name AgentServiceDeleteAgentSchedule
+     * @summary Удалить задачу по расписанию (чаты прошлых запусков сохраняются)
+     * @request DELETE:/v1/organizations/{orgId}/schedules/{scheduleId}
+     * @secure
+     */
+    agentServiceDeleteAgentSchedule: (
+      orgId: string,
+      scheduleId: string,
+      params: RequestParams = {},
+    ) =>
+      this.request<object, RpcStatus>({
+        path: `/v1/organizations/${orgId}/schedules/${scheduleId}`,
+        method: "DELETE",
+        secure: true,
+        format: "json",
+        ...params,
+      }),
+
+    /**
+     * No description
+     *
+     * @tags AgentService
+     * @name AgentServiceUpdateAgentSchedule
+     * @summary Изменить задачу по расписанию (заданные поля заменяются)
+     * @request PATCH:/v1/organizations/{orgId}/schedules/{scheduleId}
+     * @secure
+     */
+    agentServiceUpdateAgentSchedule: (
+      orgId: string,
+      scheduleId: string,
+      body: AgentServiceUpdateAgentScheduleBody,
+      params: RequestParams = {},
+    ) =>
+      this.request<AgentUpdateAgentScheduleResponse, RpcStatus>({
+        path: `/v1/organizations/${orgId}/schedules/${scheduleId}`,
+        method: "PATCH",
+        body: body,
+        secure: true,
+        type: ContentType.Json,
+        format: "json",
+        ...params,
+      }),
   };
 }
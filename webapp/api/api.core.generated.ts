@@ -47,25 +47,84 @@ export interface OrganizationServiceUpdateOrganizationBody {
   industry?: string;
   region?: string;
   description?: string;
+  /** Анкета в JSON целиком, проверяется по схеме из GetOrganizationProfileSchema */
   profileData?: string;
 }
 
+export interface RoleServiceCreateRoleBody {
+  name?: string;
+  description?: string;
+  permissions?: string[];
+}
+
+export interface RoleServiceUpdateRoleBody {
+  name?: string;
+  description?: string;
+  permissions?: string[];
+}
+
 export type UserServiceAcceptInvitationBody = object;
 
 export type UserServiceDeactivateUserBody = object;
 
 export interface UserServiceInviteUserBody {
   role?: CoreUserRole;
+  /** Собственная роль организации для вступивших; role при этом должна быть сотрудником */
+  customRoleId?: string;
+  /**
+   * Сколько пользователей может вступить по ссылке; 0 - одноразовая ссылка
+   * @format int32
+   */
+  maxUses?: number;
+  /**
+   * Срок действия в часах; 0 - 7 дней
+   * @format int32
+   */
+  expiresInHours?: number;
+}
+
+export type UserServiceLeaveOrganizationBody = object;
+
+export interface UserServiceRegenerateInvitationBody {
+  /**
+   * Срок действия новой ссылки в часах; 0 - 7 дней
+   * @format int32
+   */
+  expiresInHours?: number;
+}
+
+export type UserServiceRevokeInvitationBody = object;
+
+export interface UserServiceTransferOwnershipBody {
+  /** Пользователь, который станет администратором */
+  newOwnerId?: string;
 }
 
 export interface UserServiceUpdateUserRoleBody {
   role?: CoreUserRole;
+  /** Собственная роль организации; если задана, пользователь становится сотрудником с правами этой роли */
+  customRoleId?: string;
 }
 
 export interface CoreAcceptInvitationResponse {
   user?: CoreUser;
 }
 
+export interface CoreAuditEvent {
+  id?: string;
+  action?: string;
+  /** Сервис, в котором произошло действие */
+  source?: string;
+  organizationId?: string;
+  actorUserId?: string;
+  resourceType?: string;
+  resourceId?: string;
+  /** Подробности действия в формате JSON */
+  metadata?: string;
+  /** @format date-time */
+  occurredAt?: string;
+}
+
 export interface CoreAuthenticateWithTelegramRequest {
   initData?: string;
 }
@@ -109,7 +168,7 @@ export interface CoreCreateOrganizationRequest {
   industry?: string;
   region?: string;
   description?: string;
-  /** Расширяемая анкета в JSON */
+  /** Анкета в JSON, проверяется по схеме из GetOrganizationProfileSchema */
   profileData?: string;
 }
 
@@ -117,6 +176,10 @@ export interface CoreCreateOrganizationResponse {
   organization?: CoreOrganization;
 }
 
+export interface CoreCreateRoleResponse {
+  role?: CoreRole;
+}
+
 export interface CoreCreateTemplateRequest {
   name?: string;
   description?: string;
@@ -130,6 +193,38 @@ export interface CoreCreateTemplateResponse {
   template?: CoreContractTemplate;
 }
 
+/** Заявка на безвозвратное удаление данных и отчет о ее выполнении */
+export interface CoreDataErasure {
+  id?: string;
+  subjectType?: CoreErasureSubject;
+  subjectId?: string;
+  /** Название организации или имя пользователя на момент заявки */
+  subjectName?: string;
+  status?: CoreErasureStatus;
+  /**
+   * Окончание льготного периода
+   * @format date-time
+   */
+  purgeAfter?: string;
+  steps?: CoreErasureStepReport[];
+  /**
+   * Всего удалено объектов по всем шагам
+   * @format int32
+   */
+  itemsDeleted?: number;
+  /** @format date-time */
+  createdAt?: string;
+  /** @format date-time */
+  startedAt?: string;
+  /** @format date-time */
+  completedAt?: string;
+}
+
+export interface CoreDeleteMyAccountResponse {
+  /** Заявка на безвозвратное удаление данных и отчет о ее выполнении */
+  erasure?: CoreDataErasure;
+}
+
 export interface CoreDocument {
   id?: string;
   organizationId?: string;
@@ -155,6 +250,82 @@ export enum CoreDocumentStatus {
   DOCUMENT_STATUS_FAILED = "DOCUMENT_STATUS_FAILED",
 }
 
+/**
+ * - ERASURE_STATUS_SCHEDULED: Ждет окончания льготного периода
+ *  - ERASURE_STATUS_FAILED: Будет повторено автоматически
+ * @default "ERASURE_STATUS_UNSPECIFIED"
+ */
+export enum CoreErasureStatus {
+  ERASURE_STATUS_UNSPECIFIED = "ERASURE_STATUS_UNSPECIFIED",
+  ERASURE_STATUS_SCHEDULED = "ERASURE_STATUS_SCHEDULED",
+  ERASURE_STATUS_IN_PROGRESS = "ERASURE_STATUS_IN_PROGRESS",
+  ERASURE_STATUS_COMPLETED = "ERASURE_STATUS_COMPLETED",
+  ERASURE_STATUS_FAILED = "ERASURE_STATUS_FAILED",
+  ERASURE_STATUS_CANCELLED = "ERASURE_STATUS_CANCELLED",
+}
+
+export interface CoreErasureStepReport {
+  /** storage, search_index, llm_data, database */
+  step?: string;
+  status?: CoreErasureStepStatus;
+  /** @format int32 */
+  itemsDeleted?: number;
+  error?: string;
+  /** @format date-time */
+  finishedAt?: string;
+}
+
+/** @default "ERASURE_STEP_STATUS_UNSPECIFIED" */
+export enum CoreErasureStepStatus {
+  ERASURE_STEP_STATUS_UNSPECIFIED = "ERASURE_STEP_STATUS_UNSPECIFIED",
+  ERASURE_STEP_STATUS_PENDING = "ERASURE_STEP_STATUS_PENDING",
+  ERASURE_STEP_STATUS_COMPLETED = "ERASURE_STEP_STATUS_COMPLETED",
+  ERASURE_STEP_STATUS_FAILED = "ERASURE_STEP_STATUS_FAILED",
+}
+
+/** @default "ERASURE_SUBJECT_UNSPECIFIED" */
+export enum CoreErasureSubject {
+  ERASURE_SUBJECT_UNSPECIFIED = "ERASURE_SUBJECT_UNSPECIFIED",
+  ERASURE_SUBJECT_ORGANIZATION = "ERASURE_SUBJECT_ORGANIZATION",
+  ERASURE_SUBJECT_USER = "ERASURE_SUBJECT_USER",
+}
+
+export interface CoreExportOrganizationResponse {
+  /** Выгрузка данных организации: профиль, участники, документы, заметки, договоры, чаты и память ассистента */
+  export?: CoreOrganizationExport;
+}
+
+/**
+ * - EXPORT_STAGE_ASSISTANT_DATA: Чаты и память ассистента
+ *  - EXPORT_STAGE_DATABASE: Профиль, участники, заметки и описи файлов
+ *  - EXPORT_STAGE_FILES: Оригиналы документов и договоров
+ *  - EXPORT_STAGE_UPLOAD: Загрузка архива
+ * @default "EXPORT_STAGE_UNSPECIFIED"
+ */
+export enum CoreExportStage {
+  EXPORT_STAGE_UNSPECIFIED = "EXPORT_STAGE_UNSPECIFIED",
+  EXPORT_STAGE_ASSISTANT_DATA = "EXPORT_STAGE_ASSISTANT_DATA",
+  EXPORT_STAGE_DATABASE = "EXPORT_STAGE_DATABASE",
+  EXPORT_STAGE_FILES = "EXPORT_STAGE_FILES",
+  EXPORT_STAGE_UPLOAD = "EXPORT_STAGE_UPLOAD",
+  EXPORT_STAGE_DONE = "EXPORT_STAGE_DONE",
+}
+
+/**
+ * - EXPORT_STATUS_PENDING: Ждет выгрузки чатов и памяти ассистента
+ *  - EXPORT_STATUS_FAILED: Выгрузку можно запросить снова
+ *  - EXPORT_STATUS_EXPIRED: Архив удален по истечении срока хранения
+ * @default "EXPORT_STATUS_UNSPECIFIED"
+ */
+export enum CoreExportStatus {
+  EXPORT_STATUS_UNSPECIFIED = "EXPORT_STATUS_UNSPECIFIED",
+  EXPORT_STATUS_PENDING = "EXPORT_STATUS_PENDING",
+  EXPORT_STATUS_IN_PROGRESS = "EXPORT_STATUS_IN_PROGRESS",
+  EXPORT_STATUS_COMPLETED = "EXPORT_STATUS_COMPLETED",
+  EXPORT_STATUS_FAILED = "EXPORT_STATUS_FAILED",
+  EXPORT_STATUS_EXPIRED = "EXPORT_STATUS_EXPIRED",
+}
+
 export interface CoreGenerateDownloadURLRequest {
   s3Key?: string;
 }
@@ -198,10 +369,37 @@ export interface CoreGetDocumentResponse {
   document?: CoreDocument;
 }
 
+export interface CoreGetMyAccountDeletionResponse {
+  /** Заявка на безвозвратное удаление данных и отчет о ее выполнении */
+  erasure?: CoreDataErasure;
+}
+
 export interface CoreGetNoteResponse {
   note?: CoreNote;
 }
 
+export interface CoreGetNotificationPreferencesResponse {
+  preferences?: CoreNotificationPreferences;
+}
+
+export interface CoreGetOrganizationDeletionResponse {
+  /** Заявка на безвозвратное удаление данных и отчет о ее выполнении */
+  erasure?: CoreDataErasure;
+}
+
+export interface CoreGetOrganizationExportResponse {
+  /** Выгрузка данных организации: профиль, участники, документы, заметки, договоры, чаты и память ассистента */
+  export?: CoreOrganizationExport;
+}
+
+export interface CoreGetOrganizationProfileSchemaResponse {
+  /**
+   * Версионированная схема анкеты. Сохраненная анкета хранит версию в поле schema_version,
+   * поля вне схемы сохраняются без проверки
+   */
+  schema?: CoreOrganizationProfileSchema;
+}
+
 export interface CoreGetOrganizationResponse {
   organization?: CoreOrganization;
 }
@@ -221,10 +419,41 @@ export interface CoreInvitation {
   role?: CoreUserRole;
   /** @format date-time */
   expiresAt?: string;
-  /** @format date-time */
+  /**
+   * Время последнего использования
+   * @format date-time
+   */
   usedAt?: string;
   /** @format date-time */
   createdAt?: string;
+  customRoleId?: string;
+  customRoleName?: string;
+  /** @format int32 */
+  maxUses?: number;
+  /** @format int32 */
+  usesCount?: number;
+  /** @format date-time */
+  revokedAt?: string;
+  status?: CoreInvitationStatus;
+  createdBy?: string;
+  invitationUrl?: string;
+}
+
+/** @default "INVITATION_STATUS_UNSPECIFIED" */
+export enum CoreInvitationStatus {
+  INVITATION_STATUS_UNSPECIFIED = "INVITATION_STATUS_UNSPECIFIED",
+  INVITATION_STATUS_ACTIVE = "INVITATION_STATUS_ACTIVE",
+  INVITATION_STATUS_EXPIRED = "INVITATION_STATUS_EXPIRED",
+  INVITATION_STATUS_EXHAUSTED = "INVITATION_STATUS_EXHAUSTED",
+  INVITATION_STATUS_REVOKED = "INVITATION_STATUS_REVOKED",
+}
+
+export interface CoreInvitationUse {
+  userId?: string;
+  firstName?: string;
+  lastName?: string;
+  /** @format date-time */
+  usedAt?: string;
 }
 
 export interface CoreInviteUserResponse {
@@ -232,6 +461,17 @@ export interface CoreInviteUserResponse {
   invitationUrl?: string;
   /** @format date-time */
   expiresAt?: string;
+  invitation?: CoreInvitation;
+}
+
+export interface CoreListAuditEventsResponse {
+  events?: CoreAuditEvent[];
+  /** @format int32 */
+  total?: number;
+  /** @format int32 */
+  page?: number;
+  /** @format int32 */
+  pageSize?: number;
 }
 
 export interface CoreListContractsResponse {
@@ -262,6 +502,24 @@ export interface CoreListInvitationsResponse {
   page?: number;
 }
 
+export interface CoreListInvitationUsesResponse {
+  uses?: CoreInvitationUse[];
+  /** @format int32 */
+  total?: number;
+  /** @format int32 */
+  page?: number;
+}
+
+export interface CoreListMyDeletionReportsResponse {
+  erasures?: CoreDataErasure[];
+  /** @format int32 */
+  total?: number;
+  /** @format int32 */
+  page?: number;
+  /** @format int32 */
+  pageSize?: number;
+}
+
 export interface CoreListMyOrganizationsResponse {
   organizations?: CoreOrganization[];
 }
@@ -270,6 +528,24 @@ export interface CoreListNotesResponse {
   notes?: CoreNote[];
 }
 
+export interface CoreListOrganizationExportsResponse {
+  exports?: CoreOrganizationExport[];
+  /** @format int32 */
+  total?: number;
+  /** @format int32 */
+  page?: number;
+  /** @format int32 */
+  pageSize?: number;
+}
+
+export interface CoreListPermissionsResponse {
+  permissions?: CorePermissionInfo[];
+}
+
+export interface CoreListRolesResponse {
+  roles?: CoreRole[];
+}
+
 export interface CoreListTemplatesResponse {
   templates?: CoreContractTemplate[];
   /** @format int32 */
@@ -298,6 +574,32 @@ export interface CoreNote {
   createdAt?: string;
 }
 
+/**
+ * - NOTIFICATION_EVENT_TYPE_DOCUMENT_INDEXED: Документ проиндексирован
+ *  - NOTIFICATION_EVENT_TYPE_DOCUMENT_FAILED: Ошибка обработки документа
+ *  - NOTIFICATION_EVENT_TYPE_SCHEDULED_RUN: Результат задачи агента по расписанию
+ *  - NOTIFICATION_EVENT_TYPE_INVITATION_ACCEPTED: Приглашение принято
+ *  - NOTIFICATION_EVENT_TYPE_DATA_ERASED: Данные организации безвозвратно удалены
+ *  - NOTIFICATION_EVENT_TYPE_EXPORT_FINISHED: Выгрузка данных организации готова или не удалась
+ * @default "NOTIFICATION_EVENT_TYPE_UNSPECIFIED"
+ */
+export enum CoreNotificationEventType {
+  NOTIFICATION_EVENT_TYPE_UNSPECIFIED = "NOTIFICATION_EVENT_TYPE_UNSPECIFIED",
+  NOTIFICATION_EVENT_TYPE_DOCUMENT_INDEXED = "NOTIFICATION_EVENT_TYPE_DOCUMENT_INDEXED",
+  NOTIFICATION_EVENT_TYPE_DOCUMENT_FAILED = "NOTIFICATION_EVENT_TYPE_DOCUMENT_FAILED",
+  NOTIFICATION_EVENT_TYPE_SCHEDULED_RUN = "NOTIFICATION_EVENT_TYPE_SCHEDULED_RUN",
+  NOTIFICATION_EVENT_TYPE_INVITATION_ACCEPTED = "NOTIFICATION_EVENT_TYPE_INVITATION_ACCEPTED",
+  NOTIFICATION_EVENT_TYPE_DATA_ERASED = "NOTIFICATION_EVENT_TYPE_DATA_ERASED",
+  NOTIFICATION_EVENT_TYPE_EXPORT_FINISHED = "NOTIFICATION_EVENT_TYPE_EXPORT_FINISHED",
+}
+
+export interface CoreNotificationPreferences {
+  telegramEnabled?: boolean;
+  mutedEvents?: CoreNotificationEventType[];
+  /** @format date-time */
+  updatedAt?: string;
+}
+
 export interface CoreOrganization {
   id?: string;
   name?: string;
@@ -313,10 +615,103 @@ export interface CoreOrganization {
   deletedAt?: string;
 }
 
+/** Выгрузка данных организации: профиль, участники, документы, заметки, договоры, чаты и память ассистента */
+export interface CoreOrganizationExport {
+  id?: string;
+  organizationId?: string;
+  requestedBy?: string;
+  status?: CoreExportStatus;
+  stage?: CoreExportStage;
+  /**
+   * Прогресс текущего этапа
+   * @format int32
+   */
+  processedItems?: number;
+  /** @format int32 */
+  totalItems?: number;
+  /**
+   * Оценка общего прогресса, 0-100
+   * @format int32
+   */
+  progressPercent?: number;
+  /**
+   * Размер архива в байтах
+   * @format int64
+   */
+  fileSize?: string;
+  /** Ссылка на архив, пока он не удален */
+  downloadUrl?: string;
+  error?: string;
+  /** @format date-time */
+  createdAt?: string;
+  /** @format date-time */
+  completedAt?: string;
+  /**
+   * После этого момента архив удаляется
+   * @format date-time
+   */
+  expiresAt?: string;
+}
+
+/**
+ * Версионированная схема анкеты. Сохраненная анкета хранит версию в поле schema_version,
+ * поля вне схемы сохраняются без проверки
+ */
+export interface CoreOrganizationProfileSchema {
+  /** @format int32 */
+  version?: number;
+  fields?: CoreProfileField[];
+}
+
+export interface CorePermissionInfo {
+  /** Например, documents.write */
+  name?: string;
+  description?: string;
+}
+
+export interface CoreProfileField {
+  /** Ключ в profile_data */
+  key?: string;
+  title?: string;
+  description?: string;
+  type?: CoreProfileFieldType;
+  /** Допустимые значения PROFILE_FIELD_TYPE_ENUM */
+  options?: CoreProfileFieldOption[];
+  /** Регулярное выражение для строковых полей */
+  pattern?: string;
+  /** @format double */
+  min?: number;
+  /** @format double */
+  max?: number;
+  /** Дополнительная проверка значения: inn_checksum, ogrn_checksum */
+  check?: string;
+}
+
+export interface CoreProfileFieldOption {
+  value?: string;
+  title?: string;
+}
+
+/**
+ * - PROFILE_FIELD_TYPE_ENUM: Одно из значений options
+ * @default "PROFILE_FIELD_TYPE_UNSPECIFIED"
+ */
+export enum CoreProfileFieldType {
+  PROFILE_FIELD_TYPE_UNSPECIFIED = "PROFILE_FIELD_TYPE_UNSPECIFIED",
+  PROFILE_FIELD_TYPE_STRING = "PROFILE_FIELD_TYPE_STRING",
+  PROFILE_FIELD_TYPE_INTEGER = "PROFILE_FIELD_TYPE_INTEGER",
+  PROFILE_FIELD_TYPE_NUMBER = "PROFILE_FIELD_TYPE_NUMBER",
+  PROFILE_FIELD_TYPE_ENUM = "PROFILE_FIELD_TYPE_ENUM",
+}
+
 export interface CoreRefreshTokenResponse {
   accessToken?: string;
 }
 
+export interface CoreRegenerateInvitationResponse {
+  invitation?: CoreInvitation;
+}
+
 export interface CoreRegisterContractResponse {
   contract?: CoreGeneratedContract;
 }
@@ -325,6 +720,38 @@ export interface CoreRegisterDocumentResponse {
   document?: CoreDocument;
 }
 
+export interface CoreRevokeInvitationResponse {
+  invitation?: CoreInvitation;
+}
+
+export interface CoreRole {
+  /** Пусто для встроенных ролей */
+  id?: string;
+  organizationId?: string;
+  name?: string;
+  description?: string;
+  permissions?: string[];
+  /** Для встроенных ролей; USER_ROLE_UNSPECIFIED у собственных */
+  builtinRole?: CoreUserRole;
+  /** @format date-time */
+  createdAt?: string;
+  /** @format date-time */
+  updatedAt?: string;
+}
+
+export interface CoreTransferOwnershipResponse {
+  newOwner?: CoreUser;
+}
+
+export interface CoreUpdateNotificationPreferencesRequest {
+  telegramEnabled?: boolean;
+  mutedEvents?: CoreNotificationEventType[];
+}
+
+export interface CoreUpdateNotificationPreferencesResponse {
+  preferences?: CoreNotificationPreferences;
+}
+
 export interface CoreUpdateOrganizationResponse {
   organization?: CoreOrganization;
 }
@@ -333,6 +760,10 @@ export interface CoreUpdateTemplateResponse {
   template?: CoreContractTemplate;
 }
 
+export interface CoreUpdateRoleResponse {
+  role?: CoreRole;
+}
+
 export interface CoreUpdateUserRoleResponse {
   user?: CoreUser;
 }
@@ -350,6 +781,11 @@ export interface CoreUser {
   createdAt?: string;
   /** @format date-time */
   updatedAt?: string;
+  /** Собственная роль организации, если назначена */
+  customRoleId?: string;
+  customRoleName?: string;
+  /** Действующие права в организации */
+  permissions?: string[];
 }
 
 /** @default "USER_ROLE_UNSPECIFIED" */
@@ -365,6 +801,7 @@ export enum CoreUserStatus {
   USER_STATUS_PENDING = "USER_STATUS_PENDING",
   USER_STATUS_ACTIVE = "USER_STATUS_ACTIVE",
   USER_STATUS_INACTIVE = "USER_STATUS_INACTIVE",
+  USER_STATUS_LEFT = "USER_STATUS_LEFT",
 }
 
 export interface ProtobufAny {
@@ -678,7 +1115,7 @@ export class Api<SecurityDataType extends unknown> extends HttpClient<SecurityDa
      *
      * @tags UserService
      * @name UserServiceDeleteInvitation
-     * @summary Удалить/отозвать приглашение
+     * @summary Удалить приглашение вместе с историей использований
      * @request DELETE:/v1/invitations/{id}
      * @secure
      */
@@ -692,6 +1129,74 @@ export class Api<SecurityDataType extends unknown> extends HttpClient<SecurityDa
       }),
 
     /**
+     * No description
+     *
+     * @tags UserService
+     * @name UserServiceRegenerateInvitation
+     * @summary Перевыпустить приглашение: новая ссылка и срок, счетчик использований обнуляется
+     * @request POST:/v1/invitations/{id}/regenerate
+     * @secure
+     */
+    userServiceRegenerateInvitation: (id: string, body: UserServiceRegenerateInvitationBody, params: RequestParams = {}) =>
+      this.request<CoreRegenerateInvitationResponse, RpcStatus>({
+        path: `/v1/invitations/${id}/regenerate`,
+        method: "POST",
+        body: body,
+        secure: true,
+        type: ContentType.Json,
+        format: "json",
+        ...params,
+      }),
+
+    /**
+     * No description
+     *
+     * @tags UserService
+     * @name UserServiceRevokeInvitation
+     * @summary Отозвать приглашение: ссылка перестает работать, история сохраняется
+     * @request POST:/v1/invitations/{id}/revoke
+     * @secure
+     */
+    userServiceRevokeInvitation: (id: string, body: UserServiceRevokeInvitationBody, params: RequestParams = {}) =>
+      this.request<CoreRevokeInvitationResponse, RpcStatus>({
+        path: `/v1/invitations/${id}/revoke`,
+        method: "POST",
+        body: body,
+        secure: true,
+        type: ContentType.Json,
+        format: "json",
+        ...params,
+      }),
+
+    /**
+     * No description
+     *
+     * @tags UserService
+     * @name UserServiceListInvitationUses
+     * @summary История: кто и когда вступил по приглашению
+     * @request GET:/v1/invitations/{id}/uses
+     * @secure
+     */
+    userServiceListInvitationUses: (
+      id: string,
+      query?: {
+        /** @format int32 */
+        page?: number;
+        /** @format int32 */
+        pageSize?: number;
+      },
+      params: RequestParams = {},
+    ) =>
+      this.request<CoreListInvitationUsesResponse, RpcStatus>({
+        path: `/v1/invitations/${id}/uses`,
+        method: "GET",
+        query: query,
+        secure: true,
+        format: "json",
+        ...params,
+      }),
+
+    /**
  * No description
  *
  * @tags UserService
@@ -787,6 +1292,25 @@ export class Api<SecurityDataType extends unknown> extends HttpClient<SecurityDa
       }),
 
     /**
+ * No description
+ *
+ * @tags OrganizationService
+ * @name OrganizationServiceGetOrganizationProfileSchema
+ * @summary Схема анкеты организации (profile_data): типы полей, допустимые значения и проверки.
+По ней webapp строит форму, а при сохранении анкета проверяется по этой же схеме
+ * @request GET:/v1/organizations/profile-schema
+ * @secure
+ */
+    organizationServiceGetOrganizationProfileSchema: (params: RequestParams = {}) =>
+      this.request<CoreGetOrganizationProfileSchemaResponse, RpcStatus>({
+        path: `/v1/organizations/profile-schema`,
+        method: "GET",
+        secure: true,
+        format: "json",
+        ...params,
+      }),
+
+    /**
      * No description
      *
      * @tags OrganizationService
@@ -805,14 +1329,15 @@ export class Api<SecurityDataType extends unknown> extends HttpClient<SecurityDa
       }),
 
     /**
-     * No description
-     *
-     * @tags OrganizationService
-     * @name OrganizationServiceDeleteOrganization
-     * @summary Удалить организацию (soft delete)
-     * @request DELETE:/v1/organizations/{id}
-     * @secure
-     */
+ * No description
+ *
+ * @tags OrganizationService
+ * @name OrganizationServiceDeleteOrganization
+ * @summary Удалить организацию: она сразу скрывается, а все данные (файлы, поисковый индекс, чаты ассистента,
+записи в базе) стираются после льготного периода. До этого удаление можно отменить в ErasureService
+ * @request DELETE:/v1/organizations/{id}
+ * @secure
+ */
     organizationServiceDeleteOrganization: (id: string, params: RequestParams = {}) =>
       this.request<UserServiceAcceptInvitationBody, RpcStatus>({
         path: `/v1/organizations/${id}`,
@@ -849,15 +1374,22 @@ export class Api<SecurityDataType extends unknown> extends HttpClient<SecurityDa
     /**
      * No description
      *
-     * @tags GeneratedContractService
-     * @name GeneratedContractServiceListContracts
-     * @summary Список договоров организации
-     * @request GET:/v1/organizations/{organizationId}/contracts
+     * @tags AuditService
+     * @name AuditServiceListAuditEvents
+     * @summary Журнал аудита организации (только для участников с правом audit.view)
+     * @request GET:/v1/organizations/{organizationId}/audit-events
      * @secure
      */
-    generatedContractServiceListContracts: (
+    auditServiceListAuditEvents: (
       organizationId: string,
       query?: {
+        actorUserId?: string;
+        /** Точное действие (document.deleted) или категория с точкой на конце (document.) */
+        action?: string;
+        /** @format date-time */
+        from?: string;
+        /** @format date-time */
+        to?: string;
         /** @format int32 */
         page?: number;
         /** @format int32 */
@@ -865,8 +1397,8 @@ export class Api<SecurityDataType extends unknown> extends HttpClient<SecurityDa
       },
       params: RequestParams = {},
     ) =>
-      this.request<CoreListContractsResponse, RpcStatus>({
-        path: `/v1/organizations/${organizationId}/contracts`,
+      this.request<CoreListAuditEventsResponse, RpcStatus>({
+        path: `/v1/organizations/${organizationId}/audit-events`,
         method: "GET",
         query: query,
         secure: true,
@@ -878,15 +1410,43 @@ export class Api<SecurityDataType extends unknown> extends HttpClient<SecurityDa
      * No description
      *
      * @tags GeneratedContractService
-     * @name GeneratedContractServiceRegisterContract
-     * @summary Зарегистрировать сгенерированный договор (вызов от LLM Service)
-     * @request POST:/v1/organizations/{organizationId}/contracts
+     * @name GeneratedContractServiceListContracts
+     * @summary Список договоров организации
+     * @request GET:/v1/organizations/{organizationId}/contracts
      * @secure
      */
-    generatedContractServiceRegisterContract: (
+    generatedContractServiceListContracts: (
       organizationId: string,
-      body: GeneratedContractServiceRegisterContractBody,
-      params: RequestParams = {},
+      query?: {
+        /** @format int32 */
+        page?: number;
+        /** @format int32 */
+        pageSize?: number;
+      },
+      params: RequestParams = {},
+    ) =>
+      this.request<CoreListContractsResponse, RpcStatus>({
+        path: `/v1/organizations/${organizationId}/contracts`,
+        method: "GET",
+        query: query,
+        secure: true,
+        format: "json",
+        ...params,
+      }),
+
+    /**
+     * No description
+     *
+     * @tags GeneratedContractService
+     * @name GeneratedContractServiceRegisterContract
+     * @summary Зарегистрировать сгенерированный договор (вызов от LLM Service)
+     * @request POST:/v1/organizations/{organizationId}/contracts
+     * @secure
+     */
+    generatedContractServiceRegisterContract: (
+      organizationId: string,
+      body: GeneratedContractServiceRegisterContractBody,
+      params: RequestParams = {},
     ) =>
       this.request<CoreRegisterContractResponse, RpcStatus>({
         path: `/v1/organizations/${organizationId}/contracts`,
@@ -957,6 +1517,89 @@ export class Api<SecurityDataType extends unknown> extends HttpClient<SecurityDa
         ...params,
       }),
 
+    /**
+     * No description
+     *
+     * @tags ErasureService
+     * @name ErasureServiceGetOrganizationDeletion
+     * @summary Запланированное или выполняющееся удаление данных организации (только для участников с правом organization.manage)
+     * @request GET:/v1/organizations/{organizationId}/erasure
+     * @secure
+     */
+    erasureServiceGetOrganizationDeletion: (organizationId: string, params: RequestParams = {}) =>
+      this.request<CoreGetOrganizationDeletionResponse, RpcStatus>({
+        path: `/v1/organizations/${organizationId}/erasure`,
+        method: "GET",
+        secure: true,
+        format: "json",
+        ...params,
+      }),
+
+    /**
+     * No description
+     *
+     * @tags ErasureService
+     * @name ErasureServiceCancelOrganizationDeletion
+     * @summary Отменить удаление организации в льготный период и восстановить ее
+     * @request POST:/v1/organizations/{organizationId}/erasure/cancel
+     * @secure
+     */
+    erasureServiceCancelOrganizationDeletion: (organizationId: string, params: RequestParams = {}) =>
+      this.request<UserServiceAcceptInvitationBody, RpcStatus>({
+        path: `/v1/organizations/${organizationId}/erasure/cancel`,
+        method: "POST",
+        secure: true,
+        format: "json",
+        ...params,
+      }),
+
+    /**
+     * No description
+     *
+     * @tags ExportService
+     * @name ExportServiceListOrganizationExports
+     * @summary Выгрузки организации, от новых к старым
+     * @request GET:/v1/organizations/{organizationId}/exports
+     * @secure
+     */
+    exportServiceListOrganizationExports: (
+      organizationId: string,
+      query?: {
+        /** @format int32 */
+        page?: number;
+        /** @format int32 */
+        pageSize?: number;
+      },
+      params: RequestParams = {},
+    ) =>
+      this.request<CoreListOrganizationExportsResponse, RpcStatus>({
+        path: `/v1/organizations/${organizationId}/exports`,
+        method: "GET",
+        query: query,
+        secure: true,
+        format: "json",
+        ...params,
+      }),
+
+    /**
+ * No description
+ *
+ * @tags ExportService
+ * @name ExportServiceExportOrganization
+ * @summary Выгрузить все данные организации в ZIP-архив. Выгрузка выполняется асинхронно: прогресс можно получить
+в GetOrganizationExport, ссылка на архив придет уведомлением
+ * @request POST:/v1/organizations/{organizationId}/exports
+ * @secure
+ */
+    exportServiceExportOrganization: (organizationId: string, params: RequestParams = {}) =>
+      this.request<CoreExportOrganizationResponse, RpcStatus>({
+        path: `/v1/organizations/${organizationId}/exports`,
+        method: "POST",
+        secure: true,
+        format: "json",
+        ...params,
+      }),
+
     /**
      * No description
      *
@@ -985,6 +1628,30 @@ export class Api<SecurityDataType extends unknown> extends HttpClient<SecurityDa
         ...params,
       }),
 
+    /**
+     * No description
+     *
+     * @tags UserService
+     * @name UserServiceLeaveOrganization
+     * @summary Покинуть организацию. Последний администратор должен сначала передать управление
+     * @request POST:/v1/organizations/{organizationId}/leave
+     * @secure
+     */
+    userServiceLeaveOrganization: (
+      organizationId: string,
+      body: UserServiceLeaveOrganizationBody,
+      params: RequestParams = {},
+    ) =>
+      this.request<UserServiceAcceptInvitationBody, RpcStatus>({
+        path: `/v1/organizations/${organizationId}/leave`,
+        method: "POST",
+        body: body,
+        secure: true,
+        type: ContentType.Json,
+        format: "json",
+        ...params,
+      }),
+
     /**
      * No description
      *
@@ -1031,6 +1698,68 @@ export class Api<SecurityDataType extends unknown> extends HttpClient<SecurityDa
         ...params,
       }),
 
+    /**
+     * No description
+     *
+     * @tags RoleService
+     * @name RoleServiceListRoles
+     * @summary Список ролей организации: встроенные и собственные
+     * @request GET:/v1/organizations/{organizationId}/roles
+     * @secure
+     */
+    roleServiceListRoles: (organizationId: string, params: RequestParams = {}) =>
+      this.request<CoreListRolesResponse, RpcStatus>({
+        path: `/v1/organizations/${organizationId}/roles`,
+        method: "GET",
+        secure: true,
+        format: "json",
+        ...params,
+      }),
+
+    /**
+     * No description
+     *
+     * @tags RoleService
+     * @name RoleServiceCreateRole
+     * @summary Создать собственную роль организации
+     * @request POST:/v1/organizations/{organizationId}/roles
+     * @secure
+     */
+    roleServiceCreateRole: (organizationId: string, body: RoleServiceCreateRoleBody, params: RequestParams = {}) =>
+      this.request<CoreCreateRoleResponse, RpcStatus>({
+        path: `/v1/organizations/${organizationId}/roles`,
+        method: "POST",
+        body: body,
+        secure: true,
+        type: ContentType.Json,
+        format: "json",
+        ...params,
+      }),
+
+    /**
+     * No description
+     *
+     * @tags UserService
+     * @name UserServiceTransferOwnership
+     * @summary Передать управление организацией: участник становится администратором, текущий администратор - сотрудником
+     * @request POST:/v1/organizations/{organizationId}/transfer-ownership
+     * @secure
+     */
+    userServiceTransferOwnership: (
+      organizationId: string,
+      body: UserServiceTransferOwnershipBody,
+      params: RequestParams = {},
+    ) =>
+      this.request<CoreTransferOwnershipResponse, RpcStatus>({
+        path: `/v1/organizations/${organizationId}/transfer-ownership`,
+        method: "POST",
+        body: body,
+        secure: true,
+        type: ContentType.Json,
+        format: "json",
+        ...params,
+      }),
+
     /**
      * No description
      *
@@ -1064,7 +1793,7 @@ export class Api<SecurityDataType extends unknown> extends HttpClient<SecurityDa
      *
      * @tags UserService
      * @name UserServiceInviteUser
-     * @summary Пригласить пользователя (создать одноразовую ссылку)
+     * @summary Пригласить пользователей: одноразовая или многоразовая ссылка с заранее назначенной ролью
      * @request POST:/v1/organizations/{organizationId}/users/invite
      * @secure
      */
@@ -1079,6 +1808,24 @@ export class Api<SecurityDataType extends unknown> extends HttpClient<SecurityDa
         ...params,
       }),
 
+    /**
+     * No description
+     *
+     * @tags RoleService
+     * @name RoleServiceListPermissions
+     * @summary Список всех прав, которые можно выдать роли
+     * @request GET:/v1/permissions
+     * @secure
+     */
+    roleServiceListPermissions: (params: RequestParams = {}) =>
+      this.request<CoreListPermissionsResponse, RpcStatus>({
+        path: `/v1/permissions`,
+        method: "GET",
+        secure: true,
+        format: "json",
+        ...params,
+      }),
+
     /**
      * No description
      *
@@ -1226,6 +1973,161 @@ export class Api<SecurityDataType extends unknown> extends HttpClient<SecurityDa
         ...params,
       }),
 
+    /**
+     * No description
+     *
+     * @tags AuditService
+     * @name AuditServiceListMyAuditEvents
+     * @summary Действия текущего пользователя во всех организациях
+     * @request GET:/v1/users/me/audit-events
+     * @secure
+     */
+    auditServiceListMyAuditEvents: (
+      query?: {
+        /** Точное действие (document.deleted) или категория с точкой на конце (document.) */
+        action?: string;
+        /** @format date-time */
+        from?: string;
+        /** @format date-time */
+        to?: string;
+        /** @format int32 */
+        page?: number;
+        /** @format int32 */
+        pageSize?: number;
+      },
+      params: RequestParams = {},
+    ) =>
+      this.request<CoreListAuditEventsResponse, RpcStatus>({
+        path: `/v1/users/me/audit-events`,
+        method: "GET",
+        query: query,
+        secure: true,
+        format: "json",
+        ...params,
+      }),
+
+    /**
+     * No description
+     *
+     * @tags ErasureService
+     * @name ErasureServiceGetMyAccountDeletion
+     * @summary Запланированное или выполняющееся удаление своей учетной записи
+     * @request GET:/v1/users/me/erasure
+     * @secure
+     */
+    erasureServiceGetMyAccountDeletion: (params: RequestParams = {}) =>
+      this.request<CoreGetMyAccountDeletionResponse, RpcStatus>({
+        path: `/v1/users/me/erasure`,
+        method: "GET",
+        secure: true,
+        format: "json",
+        ...params,
+      }),
+
+    /**
+     * No description
+     *
+     * @tags ErasureService
+     * @name ErasureServiceDeleteMyAccount
+     * @summary Удалить свою учетную запись: пользователь покидает все организации, данные стираются после льготного периода
+     * @request POST:/v1/users/me/erasure
+     * @secure
+     */
+    erasureServiceDeleteMyAccount: (params: RequestParams = {}) =>
+      this.request<CoreDeleteMyAccountResponse, RpcStatus>({
+        path: `/v1/users/me/erasure`,
+        method: "POST",
+        secure: true,
+        format: "json",
+        ...params,
+      }),
+
+    /**
+     * No description
+     *
+     * @tags ErasureService
+     * @name ErasureServiceCancelMyAccountDeletion
+     * @summary Отменить удаление своей учетной записи в льготный период
+     * @request POST:/v1/users/me/erasure/cancel
+     * @secure
+     */
+    erasureServiceCancelMyAccountDeletion: (params: RequestParams = {}) =>
+      this.request<UserServiceAcceptInvitationBody, RpcStatus>({
+        path: `/v1/users/me/erasure/cancel`,
+        method: "POST",
+        secure: true,
+        format: "json",
+        ...params,
+      }),
+
+    /**
+     * No description
+     *
+     * @tags ErasureService
+     * @name ErasureServiceListMyDeletionReports
+     * @summary Удаления, запрошенные текущим пользователем, с отчетами о выполнении
+     * @request GET:/v1/users/me/erasures
+     * @secure
+     */
+    erasureServiceListMyDeletionReports: (
+      query?: {
+        /** @format int32 */
+        page?: number;
+        /** @format int32 */
+        pageSize?: number;
+      },
+      params: RequestParams = {},
+    ) =>
+      this.request<CoreListMyDeletionReportsResponse, RpcStatus>({
+        path: `/v1/users/me/erasures`,
+        method: "GET",
+        query: query,
+        secure: true,
+        format: "json",
+        ...params,
+      }),
+
+    /**
+     * No description
+     *
+     * @tags NotificationService
+     * @name NotificationServiceGetNotificationPreferences
+     * @summary Получить настройки уведомлений текущего пользователя
+     * @request GET:/v1/users/me/notification-preferences
+     * @secure
+     */
+    notificationServiceGetNotificationPreferences: (params: RequestParams = {}) =>
+      this.request<CoreGetNotificationPreferencesResponse, RpcStatus>({
+        path: `/v1/users/me/notification-preferences`,
+        method: "GET",
+        secure: true,
+        format: "json",
+        ...params,
+      }),
+
+    /**
+     * No description
+     *
+     * @tags NotificationService
+     * @name NotificationServiceUpdateNotificationPreferences
+     * @summary Обновить настройки уведомлений текущего пользователя
+     * @request PUT:/v1/users/me/notification-preferences
+     * @secure
+     */
+    notificationServiceUpdateNotificationPreferences: (
+      body: CoreUpdateNotificationPreferencesRequest,
+      params: RequestParams = {},
+    ) =>
+      this.request<CoreUpdateNotificationPreferencesResponse, RpcStatus>({
+        path: `/v1/users/me/notification-preferences`,
+        method: "PUT",
+        body: body,
+        secure: true,
+        type: ContentType.Json,
+        format: "json",
+        ...params,
+      }),
+
     /**
      * No description
      *
@@ -1244,18 +2146,84 @@ export class Api<SecurityDataType extends unknown> extends HttpClient<SecurityDa
         ...params,
       }),
 
+    /**
+     * No description
+     *
+     * @tags ExportService
+     * @name ExportServiceGetOrganizationExport
+     * @summary Состояние выгрузки и ссылка на готовый архив
+     * @request GET:/v1/organizations/{organizationId}/exports/{exportId}
+     * @secure
+     */
+    exportServiceGetOrganizationExport: (organizationId: string, exportId: string, params: RequestParams = {}) =>
+      this.request<CoreGetOrganizationExportResponse, RpcStatus>({
+        path: `/v1/organizations/${organizationId}/exports/${exportId}`,
+        method: "GET",
+        secure: true,
+        format: "json",
+        ...params,
+      }),
+
+    /**
+     * No description
+     *
+     * @tags RoleService
+     * @name RoleServiceDeleteRole
+     * @summary Удалить собственную роль, если она никому не назначена
+     * @request DELETE:/v1/organizations/{organizationId}/roles/{id}
+     * @secure
+     */
+    roleServiceDeleteRole: (organizationId: string, id: string, params: RequestParams = {}) =>
+      this.request<UserServiceAcceptInvitationBody, RpcStatus>({
+        path: `/v1/organizations/${organizationId}/roles/${id}`,
+        method: "DELETE",
+        secure: true,
+        format: "json",
+        ...params,
+      }),
+
+    /**
+     * No description
+     *
+     * @tags RoleService
+     * @name RoleServiceUpdateRole
+     * @summary Обновить собственную роль; участники с этой ролью получают новые права
+     * @request PUT:/v1/organizations/{organizationId}/roles/{id}
+     * @secure
+     */
+    roleServiceUpdateRole: (
+      organizationId: string,
+      id: string,
+      body: RoleServiceUpdateRoleBody,
+      params: RequestParams = {},
+    ) =>
+      this.request<CoreUpdateRoleResponse, RpcStatus>({
+        path: `/v1/organizations/${organizationId}/roles/${id}`,
+        method: "PUT",
+        body: body,
+        secure: true,
+        type: ContentType.Json,
+        format: "json",
+        ...params,
+      }),
+
     /**
      * No description
      *
      * @tags UserService
      * @name UserServiceDeactivateUser
-     * @summary Деактивировать пользователя
-     * @request POST:/v1/users/{id}/deactivate
+     * @summary Деактивировать членство пользователя в организации; учетная запись и другие членства остаются активными
+     * @request POST:/v1/organizations/{organizationId}/users/{id}/deactivate
      * @secure
      */
-    userServiceDeactivateUser: (id: string, body: UserServiceDeactivateUserBody, params: RequestParams = {}) =>
+    userServiceDeactivateUser: (
+      organizationId: string,
+      id: string,
+      body: UserServiceDeactivateUserBody,
+      params: RequestParams = {},
+    ) =>
       this.request<UserServiceAcceptInvitationBody, RpcStatus>({
-        path: `/v1/users/${id}/deactivate`,
+        path: `/v1/organizations/${organizationId}/users/${id}/deactivate`,
         method: "POST",
         body: body,
         secure: true,
@@ -1269,13 +2237,18 @@ export class Api<SecurityDataType extends unknown> extends HttpClient<SecurityDa
      *
      * @tags UserService
      * @name UserServiceUpdateUserRole
-     * @summary Обновить роль пользователя
-     * @request PATCH:/v1/users/{id}/role
+     * @summary Обновить роль пользователя: встроенную или собственную роль организации
+     * @request PATCH:/v1/organizations/{organizationId}/users/{id}/role
      * @secure
      */
-    userServiceUpdateUserRole: (id: string, body: UserServiceUpdateUserRoleBody, params: RequestParams = {}) =>
+    userServiceUpdateUserRole: (
+      organizationId: string,
+      id: string,
+      body: UserServiceUpdateUserRoleBody,
+      params: RequestParams = {},
+    ) =>
       this.request<CoreUpdateUserRoleResponse, RpcStatus>({
-        path: `/v1/users/${id}/role`,
+        path: `/v1/organizations/${organizationId}/users/${id}/role`,
         method: "PATCH",
         body: body,
         secure: true,
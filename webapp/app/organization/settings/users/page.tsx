@@ -34,6 +34,7 @@ import {
   CoreUserRole,
   CoreUserStatus,
   CoreInvitation,
+  CoreInvitationStatus,
 } from "@/api/api.core.generated";
 import { useApiClients } from "@/api/client";
 import { useAuth } from "@/hooks/useAuth";
@@ -286,6 +287,12 @@ export default function UsersPage() {
           color: "danger" as const,
           icon: XCircleIcon,
         };
+      case CoreUserStatus.USER_STATUS_LEFT:
+        return {
+          label: "Покинул организацию",
+          color: "default" as const,
+          icon: XCircleIcon,
+        };
       default:
         return {
           label: "Неизвестно",
@@ -450,10 +457,16 @@ export default function UsersPage() {
                   {invitations.map((invitation) => {
                     const role = getRoleLabel(invitation.role);
                     const RoleIcon = role.icon;
-                    const isExpired = invitation.expiresAt
-                      ? new Date(invitation.expiresAt) < new Date()
-                      : false;
-                    const isUsed = !!invitation.usedAt;
+                    const isExpired =
+                      invitation.status ===
+                      CoreInvitationStatus.INVITATION_STATUS_EXPIRED;
+                    const isUsed =
+                      invitation.status ===
+                      CoreInvitationStatus.INVITATION_STATUS_EXHAUSTED;
+                    const isRevoked =
+                      invitation.status ===
+                      CoreInvitationStatus.INVITATION_STATUS_REVOKED;
+                    const isReusable = (invitation.maxUses ?? 1) > 1;
 
                     return (
                       <div
@@ -504,9 +517,24 @@ export default function UsersPage() {
                             startContent={<RoleIcon className="h-4 w-4" />}
                             variant="flat"
                           >
-                            {role.label}
+                            {invitation.customRoleName || role.label}
                           </Chip>
-                          {isUsed ? (
+                          {isReusable && (
+                            <Chip size="sm" variant="flat">
+                              Вступили: {invitation.usesCount ?? 0} из{" "}
+                              {invitation.maxUses}
+                            </Chip>
+                          )}
+                          {isRevoked ? (
+                            <Chip
+                              color="default"
+                              size="sm"
+                              startContent={<XCircleIcon className="h-4 w-4" />}
+                              variant="flat"
+                            >
+                              Отозвано
+                            </Chip>
+                          ) : isUsed ? (
                             <Chip
                               color="success"
                               size="sm"